@@ -24,8 +24,10 @@ import (
 
 	"sigs.k8s.io/kubebuilder/internal/cmdutil"
 	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
 	scaffolds "sigs.k8s.io/kubebuilder/pkg/plugin/v2/scaffolds"
+	scaffoldsv3 "sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds"
 )
 
 type editError struct {
@@ -47,12 +49,22 @@ func newEditCmd() *cobra.Command {
 	kubebuilder edit --multigroup
 
 	# Disable the multigroup layout
-	kubebuilder edit --multigroup=false`,
-		Run: func(_ *cobra.Command, _ []string) {
+	kubebuilder edit --multigroup=false
+
+	# Move the API groups to a new domain
+	kubebuilder edit --domain new.example.com
+
+	# Use the v0.1.0 tag of the quay.io/acme/operator repository as manager image
+	kubebuilder edit --image quay.io/acme/operator:v0.1.0`,
+		Run: func(cmd *cobra.Command, _ []string) {
 			var err error
 			if options.config, err = config.LoadInitialized(); err != nil {
 				log.Fatal(err)
 			}
+			// Changing the domain or the image alone keeps the layout of the project
+			if !cmd.Flags().Changed("multigroup") && (cmd.Flags().Changed("domain") || cmd.Flags().Changed("image")) {
+				options.multigroup = options.config.MultiGroup
+			}
 			if err := cmdutil.Run(options); err != nil {
 				log.Fatal(editError{err})
 			}
@@ -70,18 +82,34 @@ type editOptions struct {
 	config *config.Config
 
 	multigroup bool
+	domain     string
+	image      string
 }
 
 func (o *editOptions) bindFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&o.multigroup, "multigroup", false, "enable or disable multigroup layout")
+	cmd.Flags().StringVar(&o.domain, "domain", "",
+		"move the API groups to this domain, updating the files that reference the current one")
+	cmd.Flags().StringVar(&o.image, "image", "", "reference of the manager image, e.g. "+
+		"quay.io/acme/operator:v0.1.0, set in the PROJECT file, the Makefile, the manager manifest and the Tiltfile")
 }
 
 func (o *editOptions) Validate() error {
-	return nil
+	if o.image == "" {
+		return nil
+	}
+	if !o.config.IsV3() {
+		return fmt.Errorf("the image can only be edited in projects with version %q", modelconfig.Version3Alpha)
+	}
+	return modelconfig.ValidateImage(o.image)
 }
 
 func (o *editOptions) GetScaffolder() (scaffold.Scaffolder, error) {
-	return scaffolds.NewEditScaffolder(&o.config.Config, o.multigroup), nil
+	// v3 projects may be laid out by a profile, or in a subdirectory of their module or workspace
+	if o.config.IsV3() {
+		return scaffoldsv3.NewEditScaffolder(&o.config.Config, o.multigroup, o.domain, o.image), nil
+	}
+	return scaffolds.NewEditScaffolder(&o.config.Config, o.multigroup, o.domain), nil
 }
 
 func (o *editOptions) PostScaffold() error {
@@ -21,15 +21,35 @@ import (
 
 	"sigs.k8s.io/kubebuilder/cmd/version"
 	"sigs.k8s.io/kubebuilder/pkg/cli"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	apiserverv1 "sigs.k8s.io/kubebuilder/pkg/plugin/apiserver/v1"
+	kustomizev1 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v1"
+	kustomizev2 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2"
+	tiltv1 "sigs.k8s.io/kubebuilder/pkg/plugin/tilt/v1"
 	pluginv2 "sigs.k8s.io/kubebuilder/pkg/plugin/v2"
 	pluginv3 "sigs.k8s.io/kubebuilder/pkg/plugin/v3"
 )
 
 func main() {
+	// The go/v3-alpha plugin chains the Go base plugin with the kustomize manifests plugin
+	gov3Bundle, err := plugin.NewBundle("go"+plugin.DefaultNameQualifier,
+		plugin.Version{Number: 3, Stage: plugin.AlphaStage},
+		pluginv3.Plugin{},
+		kustomizev1.Plugin{},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	c, err := cli.New(
 		cli.WithPlugins(
 			&pluginv2.Plugin{},
+			gov3Bundle,
 			&pluginv3.Plugin{},
+			kustomizev1.Plugin{},
+			kustomizev2.Plugin{},
+			tiltv1.Plugin{},
+			apiserverv1.Plugin{},
 		),
 		cli.WithDefaultPlugins(
 			&pluginv2.Plugin{},
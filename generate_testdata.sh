@@ -53,10 +53,16 @@ scaffold_test_project() {
 
         export GO111MODULE=on
         export PATH=$PATH:$(go env GOPATH)/bin
-        go mod init sigs.k8s.io/kubebuilder/testdata/$project  # our repo autodetection will traverse up to the kb module if we don't do this
+        local repo_flag=""
+        if [ $version == "2" ]; then
+          go mod init sigs.k8s.io/kubebuilder/testdata/$project  # our repo autodetection will traverse up to the kb module if we don't do this
+        else
+          # an existing go.mod would be kept as is, so let kubebuilder scaffold it from the provided repo
+          repo_flag="--repo sigs.k8s.io/kubebuilder/testdata/$project"
+        fi
 
         header_text "initializing $project ..."
-        $kb init $plugin_flag --project-version $version --domain testproject.org --license apache2 --owner "The Kubernetes authors"
+        $kb init $plugin_flag $repo_flag --project-version $version --domain testproject.org --license apache2 --owner "The Kubernetes authors"
 
         if [ $project == "project-v2" ] || [ $project == "project-v3" ]; then
             header_text 'Creating APIs ...'
@@ -38,17 +38,10 @@ func Run(options RunOptions) error {
 		return err
 	}
 
-	// Step 2: get scaffolder
-	scaffolder, err := options.GetScaffolder()
-	if err != nil {
+	// Step 2 and 3: get scaffolder and scaffold
+	if err := Scaffold(options); err != nil {
 		return err
 	}
-	// Step 3: scaffold
-	if scaffolder != nil {
-		if err := scaffolder.Scaffold(); err != nil {
-			return err
-		}
-	}
 	// Step 4: finish
 	if err := options.PostScaffold(); err != nil {
 		return err
@@ -56,3 +49,16 @@ func Run(options RunOptions) error {
 
 	return nil
 }
+
+// Scaffold creates the Scaffolder of options and scaffolds it, if any. It implements the scaffolding
+// phase of plugin subcommands, which are validated and post-scaffolded separately by the cli.
+func Scaffold(options RunOptions) error {
+	scaffolder, err := options.GetScaffolder()
+	if err != nil {
+		return err
+	}
+	if scaffolder == nil {
+		return nil
+	}
+	return scaffolder.Scaffold()
+}
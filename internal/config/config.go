@@ -129,8 +129,8 @@ func LoadFrom(path string) (*Config, error) {
 	return &Config{Config: c, path: path, fs: fs}, err
 }
 
-// Save saves the configuration information
-func (c Config) Save() error {
+// Save saves the configuration information. Once saved, a new configuration can be saved again.
+func (c *Config) Save() error {
 	if c.fs == nil {
 		c.fs = afero.NewOsFs()
 	}
@@ -163,6 +163,7 @@ func (c Config) Save() error {
 	if err != nil {
 		return saveError{fmt.Errorf("failed to save configuration to %s: %v", c.path, err)}
 	}
+	c.mustNotExist = false
 
 	return nil
 }
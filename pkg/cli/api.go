@@ -37,14 +37,16 @@ func (c *cli) newCreateAPICmd() *cobra.Command {
 		),
 	}
 
-	// Lookup the plugin for projectVersion and bind it to the command.
-	c.bindCreateAPI(ctx, cmd)
+	// Lookup the plugin for projectVersion and bind it to the command once it runs.
+	c.bindLazily(cmd, func() { c.bindCreateAPI(ctx, cmd) })
 	return cmd
 }
 
 func (c cli) newAPIContext() plugin.Context {
 	ctx := plugin.Context{
 		CommandName: c.commandName,
+		Logger:      c.logger,
+		GlobalFlags: c.globalFlags,
 		Description: `Scaffold a Kubernetes API.
 `,
 	}
@@ -55,17 +57,13 @@ func (c cli) newAPIContext() plugin.Context {
 }
 
 func (c cli) bindCreateAPI(ctx plugin.Context, cmd *cobra.Command) {
-	var getter plugin.CreateAPIPluginGetter
+	// Every resolved plugin supporting API creation is chained.
+	var subcommands []plugin.GenericSubcommand
+	var keys []string
 	for _, p := range c.resolvedPlugins {
-		tmpGetter, isGetter := p.(plugin.CreateAPIPluginGetter)
-		if isGetter {
-			if getter != nil {
-				err := fmt.Errorf("duplicate API creation plugins for project version %q (%s, %s), "+
-					"use a more specific plugin key", c.projectVersion, plugin.KeyFor(getter), plugin.KeyFor(p))
-				cmdErr(cmd, err)
-				return
-			}
-			getter = tmpGetter
+		if getter, isGetter := p.(plugin.CreateAPIPluginGetter); isGetter {
+			subcommands = append(subcommands, getter.GetCreateAPIPlugin())
+			keys = append(keys, plugin.KeyFor(p))
 		}
 	}
 
@@ -75,18 +73,18 @@ func (c cli) bindCreateAPI(ctx plugin.Context, cmd *cobra.Command) {
 		return
 	}
 
-	if getter == nil {
+	if len(subcommands) == 0 {
 		err := fmt.Errorf("layout plugin %q does not support an API creation plugin", cfg.Layout)
 		cmdErr(cmd, err)
 		return
 	}
 
-	createAPI := getter.GetCreateAPIPlugin()
-	createAPI.InjectConfig(&cfg.Config)
-	createAPI.BindFlags(cmd.Flags())
-	createAPI.UpdateContext(&ctx)
+	if err := bindSubcommands(&ctx, cmd, &cfg.Config, subcommands, keys); err != nil {
+		cmdErr(cmd, err)
+		return
+	}
 	cmd.Long = ctx.Description
 	cmd.Example = ctx.Examples
-	cmd.RunE = runECmdFunc(cfg, createAPI,
+	cmd.RunE = runECmdFunc(cfg, subcommands,
 		fmt.Sprintf("failed to create API with version %q", c.projectVersion))
 }
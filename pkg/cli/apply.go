@@ -0,0 +1,325 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	applyCommandName = "apply"
+
+	fileFlag = "file"
+)
+
+// shellSafeRegexp matches the arguments that are written unquoted when printing a command.
+var shellSafeRegexp = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// projectSpec is the declarative spec of a project read by 'apply'.
+type projectSpec struct {
+	// ProjectVersion is passed to init as --project-version.
+	ProjectVersion string `json:"projectVersion,omitempty"`
+	// Plugins are passed to init as --plugins.
+	Plugins []string `json:"plugins,omitempty"`
+	Domain  string   `json:"domain,omitempty"`
+	Repo    string   `json:"repo,omitempty"`
+	// ProjectName is passed to init as --project-name.
+	ProjectName string `json:"projectName,omitempty"`
+	// Flags are passed to init as --<key>=<value>, e.g. {"license": "none", "fetch-deps": "false"}.
+	Flags map[string]string `json:"flags,omitempty"`
+
+	// APIs are created in order, after the project is initialized.
+	APIs []apiSpec `json:"apis,omitempty"`
+	// Webhooks are created in order, after the APIs.
+	Webhooks []webhookSpec `json:"webhooks,omitempty"`
+}
+
+// apiSpec is the spec of an API, created with 'create api'.
+type apiSpec struct {
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	Plural  string `json:"plural,omitempty"`
+	// Namespaced is only passed if set, so the default of the plugin applies otherwise.
+	Namespaced *bool `json:"namespaced,omitempty"`
+	// Resource and Controller default to true, as apply does not prompt for them.
+	Resource   *bool `json:"resource,omitempty"`
+	Controller *bool `json:"controller,omitempty"`
+	// Flags are passed as --<key>=<value>, e.g. {"make": "false"}.
+	Flags map[string]string `json:"flags,omitempty"`
+}
+
+// webhookSpec is the spec of the webhooks of a resource, created with 'create webhook'.
+type webhookSpec struct {
+	Group                  string `json:"group,omitempty"`
+	Version                string `json:"version"`
+	Kind                   string `json:"kind"`
+	Defaulting             bool   `json:"defaulting,omitempty"`
+	ProgrammaticValidation bool   `json:"programmaticValidation,omitempty"`
+	Conversion             bool   `json:"conversion,omitempty"`
+	// Flags are passed as --<key>=<value>, e.g. {"webhook-version": "v1"}.
+	Flags map[string]string `json:"flags,omitempty"`
+}
+
+func (c cli) newApplyCmd() *cobra.Command {
+	var file string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   applyCommandName,
+		Short: "Scaffold a project from a declarative spec",
+		Long: `Scaffold a project from a YAML spec of its init options, APIs and webhooks in one pass, e.g. to
+generate projects from template repositories or from a spec kept in git.
+
+The spec is turned into an init command, a create api command per API and a create webhook command per
+webhook, which run in that order. Options without a field of their own are passed with 'flags', whose keys are
+the flag names. The global flags set on apply are passed to every command. With --dry-run, the commands are
+printed instead of run.
+
+The spec is scaffolded in a new project, so apply fails if the current directory already has a PROJECT file.
+
+Example spec:
+
+  domain: my.domain
+  repo: example.com/project
+  plugins: [go/v3-alpha]
+  flags:
+    license: none
+  apis:
+  - group: crew
+    version: v1
+    kind: Captain
+    flags:
+      make: "false"
+  webhooks:
+  - group: crew
+    version: v1
+    kind: Captain
+    defaulting: true
+    programmaticValidation: true
+`,
+		Example: fmt.Sprintf(`  # Scaffold the project described by project.yaml
+  %[1]s %[2]s -f project.yaml
+
+  # Print the commands scaffolding the project described in the standard input
+  cat project.yaml | %[1]s %[2]s -f - --%[3]s
+`, c.commandName, applyCommandName, dryRunFlag),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if c.configured {
+				return errors.New("the current directory already has a PROJECT file, " +
+					"a spec can only be applied in a new project")
+			}
+			spec, err := readProjectSpec(cmd.InOrStdin(), file)
+			if err != nil {
+				return err
+			}
+			commands := appendInheritedFlags(spec.commands(), cmd)
+			if dryRun {
+				for _, args := range commands {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), formatCommand(c.commandName, args))
+				}
+				return nil
+			}
+			executable, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("unable to find the %s executable: %v", c.commandName, err)
+			}
+			return runCommands(cmd.OutOrStdout(), c.commandName, commands, func(args []string) error {
+				command := exec.Command(executable, args...)
+				// Without a standard input, a command prompting for a missing option fails instead of waiting.
+				command.Stdout, command.Stderr = cmd.OutOrStdout(), cmd.ErrOrStderr()
+				return command.Run()
+			})
+		},
+	}
+	cmd.Flags().StringVarP(&file, fileFlag, "f", "", `YAML spec of the project, "-" to read it from the standard input`)
+	cmd.Flags().BoolVar(&dryRun, dryRunFlag, false, "print the commands instead of running them")
+	return cmd
+}
+
+// readProjectSpec reads the spec in file, or in stdin if file is "-". Unknown fields are rejected.
+func readProjectSpec(stdin io.Reader, file string) (projectSpec, error) {
+	var (
+		b   []byte
+		err error
+	)
+	switch file {
+	case "":
+		return projectSpec{}, fmt.Errorf("--%s is required", fileFlag)
+	case "-":
+		b, err = ioutil.ReadAll(stdin)
+	default:
+		b, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return projectSpec{}, fmt.Errorf("unable to read the spec: %v", err)
+	}
+
+	if file == "-" {
+		file = "stdin"
+	}
+	var spec projectSpec
+	if err := yaml.UnmarshalStrict(b, &spec); err != nil {
+		return projectSpec{}, fmt.Errorf("invalid spec %s: %v", file, err)
+	}
+	for i, api := range spec.APIs {
+		if api.Version == "" || api.Kind == "" {
+			return projectSpec{}, fmt.Errorf("invalid spec %s: apis[%d] must have a version and a kind", file, i)
+		}
+	}
+	for i, webhook := range spec.Webhooks {
+		if webhook.Version == "" || webhook.Kind == "" {
+			return projectSpec{}, fmt.Errorf("invalid spec %s: webhooks[%d] must have a version and a kind", file, i)
+		}
+	}
+	return spec, nil
+}
+
+// commands returns the arguments of the commands scaffolding the spec, in the order they must run.
+func (s projectSpec) commands() [][]string {
+	initArgs := []string{"init"}
+	initArgs = appendStringFlag(initArgs, projectVersionFlag, s.ProjectVersion)
+	if len(s.Plugins) != 0 {
+		initArgs = appendStringFlag(initArgs, pluginsFlag, strings.Join(s.Plugins, ","))
+	}
+	initArgs = appendStringFlag(initArgs, "domain", s.Domain)
+	initArgs = appendStringFlag(initArgs, "repo", s.Repo)
+	initArgs = appendStringFlag(initArgs, "project-name", s.ProjectName)
+	commands := [][]string{appendFlags(initArgs, s.Flags)}
+
+	for _, api := range s.APIs {
+		args := appendGVKFlags([]string{"create", "api"}, api.Group, api.Version, api.Kind)
+		args = appendStringFlag(args, "plural", api.Plural)
+		args = appendBoolFlag(args, "namespaced", api.Namespaced)
+		args = appendBoolFlag(args, "resource", defaultTrue(api.Resource))
+		args = appendBoolFlag(args, "controller", defaultTrue(api.Controller))
+		commands = append(commands, appendFlags(args, api.Flags))
+	}
+
+	for _, webhook := range s.Webhooks {
+		args := appendGVKFlags([]string{"create", "webhook"}, webhook.Group, webhook.Version, webhook.Kind)
+		if webhook.Defaulting {
+			args = append(args, "--defaulting")
+		}
+		if webhook.ProgrammaticValidation {
+			args = append(args, "--programmatic-validation")
+		}
+		if webhook.Conversion {
+			args = append(args, "--conversion")
+		}
+		commands = append(commands, appendFlags(args, webhook.Flags))
+	}
+	return commands
+}
+
+func appendGVKFlags(args []string, group, version, kind string) []string {
+	args = appendStringFlag(args, "group", group)
+	args = appendStringFlag(args, "version", version)
+	return appendStringFlag(args, "kind", kind)
+}
+
+// appendStringFlag appends --name=value to args, unless value is empty.
+func appendStringFlag(args []string, name, value string) []string {
+	if value == "" {
+		return args
+	}
+	return append(args, fmt.Sprintf("--%s=%s", name, value))
+}
+
+// appendBoolFlag appends --name=value to args, unless value is nil.
+func appendBoolFlag(args []string, name string, value *bool) []string {
+	if value == nil {
+		return args
+	}
+	return append(args, fmt.Sprintf("--%s=%s", name, strconv.FormatBool(*value)))
+}
+
+// defaultTrue returns value, or true if value is nil.
+func defaultTrue(value *bool) *bool {
+	if value == nil {
+		value = new(bool)
+		*value = true
+	}
+	return value
+}
+
+// appendFlags appends --key=value to args for each of flags, sorted by key.
+func appendFlags(args []string, flags map[string]string) []string {
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, fmt.Sprintf("--%s=%s", key, flags[key]))
+	}
+	return args
+}
+
+// inheritedFlagArgs returns the arguments setting the inherited flags of cmd that were set, e.g. --verbose.
+func inheritedFlagArgs(cmd *cobra.Command) (args []string) {
+	cmd.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		value := f.Value.String()
+		// Slice and map values are written in brackets, but set without them
+		if _, isSlice := f.Value.(pflag.SliceValue); isSlice || f.Value.Type() == "stringToString" {
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", f.Name, value))
+	})
+	return args
+}
+
+// runCommands runs each command with run, writing it to out first. It stops at the first command that fails.
+func runCommands(out io.Writer, commandName string, commands [][]string, run func(args []string) error) error {
+	for _, args := range commands {
+		command := formatCommand(commandName, args)
+		_, _ = fmt.Fprintf(out, "$ %s\n", command)
+		if err := run(args); err != nil {
+			return fmt.Errorf("%s failed: %v", command, err)
+		}
+	}
+	return nil
+}
+
+// formatCommand returns the command line running commandName with args, quoting the arguments a shell would split.
+func formatCommand(commandName string, args []string) string {
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, commandName)
+	for _, arg := range args {
+		if !shellSafeRegexp.MatchString(arg) {
+			arg = "'" + strings.Replace(arg, "'", `'\''`, -1) + "'"
+		}
+		quoted = append(quoted, arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("apply", func() {
+	const spec = `domain: my.domain
+repo: example.com/project
+plugins: [go/v3-alpha]
+flags:
+  owner: The Authors
+  fetch-deps: "false"
+apis:
+- group: crew
+  version: v1
+  kind: Captain
+- group: crew
+  version: v1
+  kind: Admiral
+  namespaced: false
+  controller: false
+  flags:
+    make: "false"
+webhooks:
+- group: crew
+  version: v1
+  kind: Captain
+  defaulting: true
+  programmaticValidation: true
+`
+
+	It("should turn the spec into commands", func() {
+		s, err := readProjectSpec(strings.NewReader(spec), "-")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.commands()).To(Equal([][]string{
+			{"init", "--plugins=go/v3-alpha", "--domain=my.domain", "--repo=example.com/project",
+				"--fetch-deps=false", "--owner=The Authors"},
+			{"create", "api", "--group=crew", "--version=v1", "--kind=Captain", "--resource=true", "--controller=true"},
+			{"create", "api", "--group=crew", "--version=v1", "--kind=Admiral", "--namespaced=false",
+				"--resource=true", "--controller=false", "--make=false"},
+			{"create", "webhook", "--group=crew", "--version=v1", "--kind=Captain",
+				"--defaulting", "--programmatic-validation"},
+		}))
+	})
+
+	It("should reject an invalid spec", func() {
+		_, err := readProjectSpec(strings.NewReader("domain: my.domain\ndomian: typo\n"), "-")
+		Expect(err).To(MatchError(ContainSubstring(`unknown field "domian"`)))
+
+		_, err = readProjectSpec(strings.NewReader("webhooks:\n- group: crew\n  kind: Captain\n"), "-")
+		Expect(err).To(MatchError("invalid spec stdin: webhooks[0] must have a version and a kind"))
+
+		_, err = readProjectSpec(strings.NewReader(spec), "")
+		Expect(err).To(MatchError("--file is required"))
+	})
+
+	It("should write each command before running it and stop at the first failure", func() {
+		var ran [][]string
+		out := &bytes.Buffer{}
+		err := runCommands(out, "kubebuilder", [][]string{
+			{"init", "--owner=The Authors"},
+			{"create", "api", "--kind=Captain"},
+			{"create", "webhook", "--kind=Captain"},
+		}, func(args []string) error {
+			ran = append(ran, args)
+			if args[1] == "api" {
+				return errors.New("exit status 1")
+			}
+			return nil
+		})
+		Expect(err).To(MatchError("kubebuilder create api --kind=Captain failed: exit status 1"))
+		Expect(ran).To(HaveLen(2))
+		Expect(out.String()).To(Equal("$ kubebuilder init '--owner=The Authors'\n" +
+			"$ kubebuilder create api --kind=Captain\n"))
+	})
+})
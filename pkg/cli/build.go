@@ -0,0 +1,293 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/tools"
+)
+
+const (
+	buildCommandName = "build"
+
+	imageFlag    = "image"
+	pushFlag     = "push"
+	platformFlag = "platform"
+
+	// defaultManifestsOutput is the file the manifests are rendered into by default
+	defaultManifestsOutput = "dist/install.yaml"
+)
+
+var (
+	makefileImageRegexp = regexp.MustCompile(`(?m)^IMG \?= (\S+)`)
+	// buildContextRegexp matches the build context of the docker-build target, set if it is not the project
+	buildContextRegexp = regexp.MustCompile(`docker build -f Dockerfile -t \$\{IMG\} (\S+)`)
+)
+
+// toolStep is a tool run by the build, deploy and undeploy commands.
+type toolStep struct {
+	// dir is the directory the tool is run in, relative to the project, the project itself if empty
+	dir  string
+	name string
+	args []string
+	// output is the file the standard output of the tool is written to, if not empty
+	output string
+	// pipe is true if the standard output of the tool is the standard input of the next step
+	pipe bool
+}
+
+// String returns the shell command line of s.
+func (s toolStep) String() string {
+	command := formatCommand(s.name, s.args)
+	if s.dir != "" {
+		command = fmt.Sprintf("cd %s && %s", s.dir, command)
+	}
+	if s.output != "" {
+		command = fmt.Sprintf("%s > %s", command, s.output)
+	}
+	return command
+}
+
+// formatSteps returns the shell command lines of steps, a line holding the steps piped into each other.
+func formatSteps(steps []toolStep) []string {
+	lines := make([]string, 0, len(steps))
+	pipeline := make([]string, 0, 2)
+	for _, step := range steps {
+		if pipeline = append(pipeline, step.String()); !step.pipe {
+			lines = append(lines, strings.Join(pipeline, " | "))
+			pipeline = pipeline[:0]
+		}
+	}
+	return lines
+}
+
+// buildOptions are the options of the build command.
+type buildOptions struct {
+	// image is the image to build, defaulting to the IMG of the Makefile
+	image     string
+	push      bool
+	platforms []string
+	// output is the file the manifests are rendered into
+	output string
+	// kustomize is the kustomize binary
+	kustomize string
+}
+
+func (c cli) newBuildCmd() *cobra.Command {
+	opts := buildOptions{}
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   buildCommandName,
+		Short: "Build the manager, its image and the manifests deploying it",
+		Long: `Build the manager, its image and the manifests deploying it in one step, the way the manager, docker-build,
+docker-push and deploy targets of the Makefile do:
+
+- the manager is compiled into bin/manager
+- the image is built with docker, from the Dockerfile and the build context of the Makefile, and pushed with
+  --push. With --platform, it is built by docker buildx for each platform, which needs the Dockerfile to build
+  the manager for the TARGETOS and TARGETARCH build arguments, as the scaffolded one does. Images of several
+  platforms can only be pushed, as docker does not load them
+- the image of the manager manifest is set to the built one in config/manager/kustomization.yaml and the
+  manifests of config/default are rendered into --output
+
+The image defaults to the IMG of the Makefile. The kustomize binary built by the Makefile into bin/ is used
+if there is one. With --dry-run, the commands are printed instead of run.
+`,
+		Example: fmt.Sprintf(`  # Build the manager, the image of the Makefile and the manifests in dist/install.yaml
+  %[1]s %[2]s
+
+  # Build and push an image for amd64 and arm64 nodes
+  %[1]s %[2]s --%[3]s quay.io/acme/operator:v0.1.0 --%[4]s --%[5]s linux/amd64,linux/arm64
+`, c.commandName, buildCommandName, imageFlag, pushFlag, platformFlag),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !c.configured {
+				return errors.New("unable to find configuration file, project must be initialized")
+			}
+			project, err := internalconfig.Load()
+			if err != nil {
+				return fmt.Errorf("failed to read config: %v", err)
+			}
+			makefile, err := ioutil.ReadFile("Makefile")
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			dockerfile, err := ioutil.ReadFile("Dockerfile")
+			if err != nil {
+				return fmt.Errorf("unable to read the Dockerfile of the image: %v", err)
+			}
+			opts.kustomize = kustomizeBinary()
+
+			steps, err := buildSteps(project.Config, string(makefile), string(dockerfile), opts)
+			if err != nil {
+				return err
+			}
+			return runSteps(cmd.OutOrStdout(), cmd.ErrOrStderr(), steps, dryRun)
+		},
+	}
+	cmd.Flags().StringVar(&opts.image, imageFlag, "", "image to build, defaults to the IMG of the Makefile")
+	cmd.Flags().BoolVar(&opts.push, pushFlag, false, "push the image")
+	cmd.Flags().StringSliceVar(&opts.platforms, platformFlag, nil,
+		"platforms to build the image for with docker buildx, e.g. linux/amd64,linux/arm64")
+	cmd.Flags().StringVar(&opts.output, outputFlag, defaultManifestsOutput, "file to render the manifests into")
+	cmd.Flags().BoolVar(&dryRun, dryRunFlag, false, "print the commands instead of running them")
+	return cmd
+}
+
+// buildSteps returns the steps building the project of cfg, whose Makefile and Dockerfile are passed.
+func buildSteps(cfg config.Config, makefile, dockerfile string, opts buildOptions) ([]toolStep, error) {
+	image := opts.image
+	if image == "" {
+		image = makefileImage(cfg, makefile)
+	}
+	if err := config.ValidateImage(image); err != nil {
+		return nil, err
+	}
+	if len(opts.platforms) > 1 && !opts.push {
+		return nil, fmt.Errorf("an image of several platforms can only be pushed, set --%s", pushFlag)
+	}
+	if !strings.Contains(dockerfile, "TARGETARCH") {
+		for _, platform := range opts.platforms {
+			if platform != "linux/amd64" {
+				return nil, fmt.Errorf("the Dockerfile builds the manager for linux/amd64 only, its GOOS and "+
+					"GOARCH must be set to the TARGETOS and TARGETARCH build arguments to build it for %s", platform)
+			}
+		}
+	}
+	buildContext := "."
+	if m := buildContextRegexp.FindStringSubmatch(makefile); m != nil {
+		buildContext = m[1]
+	}
+
+	steps := []toolStep{{name: "go", args: []string{"build", "-o", filepath.Join(localBin, "manager"), "main.go"}}}
+	if len(opts.platforms) == 0 {
+		steps = append(steps, toolStep{name: "docker", args: []string{"build", "-f", "Dockerfile", "-t", image,
+			buildContext}})
+		if opts.push {
+			steps = append(steps, toolStep{name: "docker", args: []string{"push", image}})
+		}
+	} else {
+		args := []string{"buildx", "build", "--platform", strings.Join(opts.platforms, ",")}
+		// A single platform image is loaded into docker, as docker build does
+		if opts.push {
+			args = append(args, "--push")
+		} else {
+			args = append(args, "--load")
+		}
+		steps = append(steps, toolStep{name: "docker", args: append(args, "-f", "Dockerfile", "-t", image,
+			buildContext)})
+	}
+	output := opts.output
+	if output == "" {
+		output = defaultManifestsOutput
+	}
+	steps = append(steps, setImageStep(cfg, opts.kustomize, image),
+		toolStep{name: opts.kustomize, args: []string{"build", filepath.Join("config", "default")}, output: output},
+	)
+	return steps, nil
+}
+
+// makefileImage returns the IMG of makefile, or the image of the manager manifest of cfg if it has none.
+func makefileImage(cfg config.Config, makefile string) string {
+	if m := makefileImageRegexp.FindStringSubmatch(makefile); m != nil {
+		return m[1]
+	}
+	return cfg.GetManifestImage()
+}
+
+// setImageStep returns the step setting the image of the manager manifest of cfg to image.
+func setImageStep(cfg config.Config, kustomize, image string) toolStep {
+	return toolStep{dir: filepath.Join("config", "manager"), name: kustomize,
+		args: []string{"edit", "set", "image", config.ImageName(cfg.GetManifestImage()) + "=" + image}}
+}
+
+// kustomizeBinary returns the kustomize binary built by the Makefile into localBin if there is one, else the one
+// of the PATH. Its path is absolute, as kustomize is also run in the directories of the manifests it edits.
+func kustomizeBinary() string {
+	path, err := filepath.Abs(filepath.Join(localBin, "kustomize"))
+	if err != nil {
+		return "kustomize"
+	}
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return "kustomize"
+	}
+	return path
+}
+
+// runSteps runs steps in the tools environment, writing their command lines and the standard output of the tools
+// to out and their standard error to errOut. With dryRun, the command lines are only written.
+func runSteps(out, errOut io.Writer, steps []toolStep, dryRun bool) error {
+	lines := formatSteps(steps)
+	if dryRun {
+		for _, line := range lines {
+			_, _ = fmt.Fprintln(out, line)
+		}
+		return nil
+	}
+	var stdin io.Reader
+	for _, step := range steps {
+		// A line is written before the first step of its pipeline
+		if stdin == nil {
+			_, _ = fmt.Fprintf(out, "$ %s\n", lines[0])
+			lines = lines[1:]
+		}
+		command := tools.Default().Command(step.name, step.args...)
+		command.Dir, command.Stdin, command.Stdout, command.Stderr = step.dir, stdin, out, errOut
+		stdin = nil
+		if step.pipe {
+			piped := &bytes.Buffer{}
+			command.Stdout, stdin = piped, piped
+		}
+		if err := runStep(command, step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runStep runs command, the tool of step, writing its standard output to the output of step if it has one.
+func runStep(command *exec.Cmd, step toolStep) error {
+	if step.output != "" {
+		if err := os.MkdirAll(filepath.Dir(step.output), 0755); err != nil {
+			return err
+		}
+		f, err := os.Create(step.output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		command.Stdout = f
+	}
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("%s failed: %v", step, err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+var _ = Describe("build", func() {
+	const (
+		makefile   = "# Image URL to use all building/pushing image targets\nIMG ?= project:latest\n"
+		dockerfile = "ARG TARGETOS\nARG TARGETARCH\n" +
+			"RUN CGO_ENABLED=0 GOOS=${TARGETOS:-linux} GOARCH=${TARGETARCH:-amd64} go build -a -o manager main.go\n"
+	)
+
+	var (
+		cfg  config.Config
+		opts buildOptions
+	)
+
+	BeforeEach(func() {
+		cfg = config.Config{Version: config.Version3Alpha, Domain: "my.domain", Repo: "example.com/project"}
+		opts = buildOptions{output: defaultManifestsOutput, kustomize: "kustomize"}
+	})
+
+	It("should build the image of the Makefile", func() {
+		steps, err := buildSteps(cfg, makefile, dockerfile, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(formatSteps(steps)).To(Equal([]string{
+			"go build -o bin/manager main.go",
+			"docker build -f Dockerfile -t project:latest .",
+			"cd config/manager && kustomize edit set image controller=project:latest",
+			"kustomize build config/default > dist/install.yaml",
+		}))
+	})
+
+	It("should push the image of --image, set in the manifest recorded in the PROJECT file", func() {
+		cfg.Image = "quay.io/acme/operator:v0.1.0"
+		opts.image = "quay.io/acme/operator:v0.2.0"
+		opts.push = true
+		steps, err := buildSteps(cfg, makefile+"\tdocker build -f Dockerfile -t ${IMG} ..\n", dockerfile, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(formatSteps(steps)).To(Equal([]string{
+			"go build -o bin/manager main.go",
+			"docker build -f Dockerfile -t quay.io/acme/operator:v0.2.0 ..",
+			"docker push quay.io/acme/operator:v0.2.0",
+			"cd config/manager && kustomize edit set image quay.io/acme/operator=quay.io/acme/operator:v0.2.0",
+			"kustomize build config/default > dist/install.yaml",
+		}))
+	})
+
+	It("should build the image for each platform with docker buildx", func() {
+		opts.platforms = []string{"linux/arm64"}
+		steps, err := buildSteps(cfg, "", dockerfile, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(steps[1].String()).To(Equal(
+			"docker buildx build --platform linux/arm64 --load -f Dockerfile -t controller:latest ."))
+
+		opts.platforms = []string{"linux/amd64", "linux/arm64"}
+		_, err = buildSteps(cfg, "", dockerfile, opts)
+		Expect(err).To(MatchError("an image of several platforms can only be pushed, set --push"))
+
+		opts.push = true
+		steps, err = buildSteps(cfg, "", dockerfile, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(steps[1].String()).To(Equal(
+			"docker buildx build --platform linux/amd64,linux/arm64 --push -f Dockerfile -t controller:latest ."))
+	})
+
+	It("should fail for a platform that the Dockerfile does not build the manager for", func() {
+		opts.platforms = []string{"linux/arm64"}
+		_, err := buildSteps(cfg, makefile, "RUN CGO_ENABLED=0 GOOS=linux GOARCH=amd64 go build -a -o manager\n", opts)
+		Expect(err).To(MatchError(ContainSubstring("to build it for linux/arm64")))
+	})
+
+	It("should fail for an invalid image", func() {
+		opts.image = "Operator"
+		_, err := buildSteps(cfg, makefile, dockerfile, opts)
+		Expect(err).To(MatchError(ContainSubstring(`invalid image "Operator"`)))
+	})
+})
@@ -27,8 +27,10 @@ import (
 
 	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
 	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/tools"
 )
 
 const (
@@ -40,6 +42,18 @@ project.
 	projectVersionFlag = "project-version"
 	helpFlag           = "help"
 	pluginsFlag        = "plugins"
+	verboseFlag        = "verbose"
+	quietFlag          = "quiet"
+	toolsConfigFlag    = "tools-config"
+	toolEnvFlag        = "tool-env"
+	toolPathFlag       = "tool-path"
+	noHistoryFlag      = "no-history"
+
+	// layoutSeparator separates the keys of chained plugins, both in --plugins and in a config's layout.
+	layoutSeparator = ","
+
+	// defaultEnvPrefix is the prefix of the environment variables the base flags fall back to.
+	defaultEnvPrefix = "KUBEBUILDER"
 )
 
 // CLI interacts with a command line interface.
@@ -65,30 +79,68 @@ type cli struct {
 	configured bool
 	// Whether the command is requesting help.
 	doGenericHelp bool
+	// IDs of the warnings that should not be written.
+	suppressedWarnings []string
+	// Format warnings are written in.
+	warningsFormat string
+	// Logger passed to plugins, its level is set by --verbose and --quiet.
+	logger logger.Logger
+	// Environment external tools are executed in, set by --tools-config, --tool-env and --tool-path.
+	tools tools.Environment
 
 	// Plugins injected by options.
 	pluginsFromOptions map[string][]plugin.Base
 	// Default plugins injected by options. Only one plugin per project version
 	// is allowed.
 	defaultPluginsFromOptions map[string]plugin.Base
-	// A plugin key passed to --plugins on invoking 'init'.
-	cliPluginKey string
+	// Deprecated project versions injected by options.
+	projectVersionDeprecations map[string]projectVersionDeprecation
+	// Plugin keys passed to --plugins on invoking 'init'.
+	cliPluginKeys []string
+	// True if cliPluginKeys are the plugins of the defaults file, as --plugins was not set.
+	pluginKeysFromDefaults bool
+	// Path of the defaults file set by --defaults-file, the one of the user if empty.
+	defaultsFile string
+	// Defaults of the init flags of the user, only read for projects to initialize.
+	userDefaults userDefaults
+	// The plugins the resolved keys refer to, before bundles are expanded. Their keys
+	// are stored as the project layout.
+	layoutPlugins []plugin.Base
 	// A filtered set of plugins that should be used by command constructors.
+	// Bundles are expanded, so their plugins are chained in order.
 	resolvedPlugins []plugin.Base
+	// How resolvedPlugins were resolved, described by 'plugins resolve'.
+	resolution pluginResolution
 
 	// Base command.
 	cmd *cobra.Command
+	// Functions binding the plugins to the commands, only called for the command that runs, as constructing
+	// the plugin subcommands may read the project config or run external tools.
+	binders map[*cobra.Command]func()
 	// Commands injected by options.
 	extraCommands []*cobra.Command
+	// Root command description injected by options, replacing the default one.
+	description string
+	// Root command examples injected by options, replacing the default ones.
+	examples string
+	// Paths of the commands to hide, injected by options.
+	hiddenCommands []string
+	// Persistent flags injected by options, parsed along with the base flags.
+	globalFlags *pflag.FlagSet
+	// Prefix of the environment variables the base flags that are not set fall back to.
+	envPrefix string
 }
 
 // New creates a new cli instance.
 func New(opts ...Option) (CLI, error) {
 	c := &cli{
-		commandName:               "kubebuilder",
-		defaultProjectVersion:     internalconfig.DefaultVersion,
-		pluginsFromOptions:        make(map[string][]plugin.Base),
-		defaultPluginsFromOptions: make(map[string]plugin.Base),
+		commandName:                "kubebuilder",
+		defaultProjectVersion:      internalconfig.DefaultVersion,
+		pluginsFromOptions:         make(map[string][]plugin.Base),
+		defaultPluginsFromOptions:  make(map[string]plugin.Base),
+		projectVersionDeprecations: make(map[string]projectVersionDeprecation),
+		binders:                    make(map[*cobra.Command]func()),
+		envPrefix:                  defaultEnvPrefix,
 	}
 	for _, opt := range opts {
 		if err := opt(c); err != nil {
@@ -104,6 +156,7 @@ func New(opts ...Option) (CLI, error) {
 
 // Run runs the cli.
 func (c cli) Run() error {
+	c.bindRunCommand(os.Args[1:])
 	return c.cmd.Execute()
 }
 
@@ -164,6 +217,22 @@ func WithDefaultPlugins(plugins ...plugin.Base) Option {
 	}
 }
 
+// WithDeprecatedProjectVersion is an Option that deprecates a project version: warning, e.g. telling which
+// version to upgrade to, is written for the projects of that version, along with removalVersion, the version
+// of the CLI the project version is removed in, if set.
+func WithDeprecatedProjectVersion(version, warning, removalVersion string) Option {
+	return func(c *cli) error {
+		if err := validation.ValidateProjectVersion(version); err != nil {
+			return fmt.Errorf("broken deprecated project version %q: %v", version, err)
+		}
+		c.projectVersionDeprecations[version] = projectVersionDeprecation{
+			Warning:        warning,
+			RemovalVersion: removalVersion,
+		}
+		return nil
+	}
+}
+
 // WithExtraCommands is an Option that adds extra subcommands to the cli.
 // Adding extra commands that duplicate existing commands results in an error.
 func WithExtraCommands(cmds ...*cobra.Command) Option {
@@ -173,6 +242,69 @@ func WithExtraCommands(cmds ...*cobra.Command) Option {
 	}
 }
 
+// WithDescription is an Option that replaces the description of the root
+// command. Its first line is used as the short description.
+func WithDescription(description string) Option {
+	return func(c *cli) error {
+		c.description = description
+		return nil
+	}
+}
+
+// WithExamples is an Option that replaces the examples of the root command.
+func WithExamples(examples string) Option {
+	return func(c *cli) error {
+		c.examples = examples
+		return nil
+	}
+}
+
+// WithHiddenCommands is an Option that hides commands from the help of the cli,
+// although they can still be run. Each command is identified by its path from
+// the root command, ex. "create webhook". Hiding a command that does not exist
+// results in an error.
+func WithHiddenCommands(commandPaths ...string) Option {
+	return func(c *cli) error {
+		c.hiddenCommands = append(c.hiddenCommands, commandPaths...)
+		return nil
+	}
+}
+
+// WithEnvPrefix is an Option that sets the prefix of the environment variables the base and global flags fall
+// back to when they are not set, e.g. "MYCLI" for MYCLI_PLUGINS to set --plugins. It defaults to "KUBEBUILDER".
+func WithEnvPrefix(prefix string) Option {
+	return func(c *cli) error {
+		if strings.ContainsAny(prefix, "=\x00") {
+			return fmt.Errorf("invalid environment variable prefix %q", prefix)
+		}
+		c.envPrefix = prefix
+		return nil
+	}
+}
+
+// WithGlobalFlags is an Option that registers flags as persistent flags of the
+// root command. They are parsed along with the base flags, before any plugin is
+// run, and passed to plugins through their context. Flags that conflict with a
+// base flag result in an error.
+func WithGlobalFlags(flags *pflag.FlagSet) Option {
+	return func(c *cli) error {
+		if c.globalFlags == nil {
+			c.globalFlags = pflag.NewFlagSet("global", pflag.ContinueOnError)
+		}
+		var err error
+		flags.VisitAll(func(f *pflag.Flag) {
+			if err == nil && c.globalFlags.Lookup(f.Name) != nil {
+				err = fmt.Errorf("global flag %q is registered more than once", f.Name)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		c.globalFlags.AddFlagSet(flags)
+		return nil
+	}
+}
+
 // initialize initializes the cli.
 func (c *cli) initialize() error {
 	// Initialize cli with globally-relevant flags or flags that determine
@@ -180,6 +312,8 @@ func (c *cli) initialize() error {
 	if err := c.parseBaseFlags(); err != nil {
 		return err
 	}
+	logger.SetDefault(c.logger)
+	tools.SetDefault(c.tools)
 
 	// Configure the project version first for plugin retrieval in command
 	// constructors.
@@ -195,12 +329,22 @@ func (c *cli) initialize() error {
 
 		if projectConfig.IsV1() {
 			return fmt.Errorf(noticeColor, "project version 1 is no longer supported.\n"+
-				"See how to upgrade your project: https://book.kubebuilder.io/migration/guide.html\n")
+				"See how to upgrade your project: "+migrationGuideURL+"\n")
 		}
 	} else {
 		return fmt.Errorf("failed to read config: %v", err)
 	}
 
+	// The defaults of the user only apply to the projects to initialize.
+	if !c.configured {
+		if c.userDefaults, err = loadUserDefaults(c.defaultsFile); err != nil {
+			return err
+		}
+		if len(c.cliPluginKeys) == 0 && len(c.userDefaults.Plugins) != 0 {
+			c.cliPluginKeys, c.pluginKeysFromDefaults = c.userDefaults.Plugins, true
+		}
+	}
+
 	// Validate after setting projectVersion but before buildRootCmd so we error
 	// out before an error resulting from an incorrect cli is returned downstream.
 	if err = c.validate(); err != nil {
@@ -210,9 +354,12 @@ func (c *cli) initialize() error {
 	// When invoking 'init', a user can:
 	// 1. Not set --plugins
 	// 2. Set --plugins to a plugin, ex. --plugins=go-x
+	// 3. Set --plugins to a comma-separated list of plugins, ex. --plugins=go-x,kustomize-y
 	// In case 1, default plugins will be used to determine which plugin to use.
-	// In case 2, the value passed to --plugins is used.
-	// For all other commands, a config's 'layout' key is used. Since both
+	// In cases 2 and 3, the values passed to --plugins are used, and the
+	// subcommands of each plugin are chained in the provided order.
+	// For all other commands, a config's 'layout' key is used, which contains
+	// the same comma-separated list of plugin keys. Since both
 	// layout and --plugins values can be short (ex. "go/v2") or unversioned
 	// (ex. "go.kubebuilder.io") keys or both, their values may need to be
 	// resolved to known plugins by key.
@@ -221,13 +368,22 @@ func (c *cli) initialize() error {
 	// in situations like 'init --plugins "go"' when multiple go-type plugins
 	// are available but only one default is for a particular project version.
 	allPlugins := c.pluginsFromOptions[c.projectVersion]
-	defaultPlugin := []plugin.Base{c.defaultPluginsFromOptions[c.projectVersion]}
+	var defaultPlugin []plugin.Base
+	c.resolution = pluginResolution{ProjectVersion: c.projectVersion}
+	if p, hasDefault := c.defaultPluginsFromOptions[c.projectVersion]; hasDefault {
+		defaultPlugin = []plugin.Base{p}
+		c.resolution.DefaultPlugin = plugin.KeyFor(p)
+	}
 	switch {
-	case c.cliPluginKey != "":
-		// Filter plugin by keys passed in CLI.
-		if c.resolvedPlugins, err = resolvePluginsByKey(defaultPlugin, c.cliPluginKey); err != nil {
-			c.resolvedPlugins, err = resolvePluginsByKey(allPlugins, c.cliPluginKey)
-		}
+	case c.pluginKeysFromDefaults:
+		c.logger.Debug("resolving plugins of the defaults file", "keys", strings.Join(c.cliPluginKeys, layoutSeparator))
+		c.resolution.Source = resolutionSourceDefaultsFile
+		c.layoutPlugins, c.resolution.Keys, err = explainPluginsByKeys(defaultPlugin, allPlugins, c.cliPluginKeys)
+	case len(c.cliPluginKeys) != 0:
+		// Filter plugins by keys passed in CLI.
+		c.logger.Debug("resolving plugins passed with --"+pluginsFlag, "keys", strings.Join(c.cliPluginKeys, layoutSeparator))
+		c.resolution.Source = resolutionSourceFlag
+		c.layoutPlugins, c.resolution.Keys, err = explainPluginsByKeys(defaultPlugin, allPlugins, c.cliPluginKeys)
 	case c.configured && projectConfig.IsV3():
 		// All non-v1 configs must have a layout key. This check will help with
 		// migration.
@@ -235,20 +391,33 @@ func (c *cli) initialize() error {
 		if layout == "" {
 			return fmt.Errorf("config must have a layout value")
 		}
-		// Filter plugin by config's layout value.
-		if c.resolvedPlugins, err = resolvePluginsByKey(defaultPlugin, layout); err != nil {
-			c.resolvedPlugins, err = resolvePluginsByKey(allPlugins, layout)
-		}
+		// Filter plugins by config's layout value.
+		c.logger.Debug("resolving plugins of the project layout", "layout", layout)
+		c.resolution.Source = resolutionSourceLayout
+		c.layoutPlugins, c.resolution.Keys, err = explainPluginsByKeys(defaultPlugin, allPlugins,
+			strings.Split(layout, layoutSeparator))
 	default:
 		// Use the default plugins for this project version.
-		c.resolvedPlugins = defaultPlugin
+		c.logger.Debug("using the default plugins", "projectVersion", c.projectVersion)
+		c.resolution.Source = resolutionSourceDefault
+		c.layoutPlugins = defaultPlugin
 	}
 	if err != nil {
 		return err
 	}
+	c.resolvedPlugins = expandBundles(c.layoutPlugins...)
+	c.resolution.Layout = makeLayout(c.layoutPlugins...)
+	c.resolution.Chain = makePluginKeySlice(c.resolvedPlugins...)
+	c.logger.Debug("resolved plugins", "layout", makeLayout(c.layoutPlugins...),
+		"chain", strings.Join(makePluginKeySlice(c.resolvedPlugins...), layoutSeparator))
 
 	c.cmd = c.buildRootCmd()
 
+	// Add commands contributed by plugins, before extra commands so that both are checked for conflicts.
+	if err := c.addPluginCommands(); err != nil {
+		return err
+	}
+
 	// Add extra commands injected by options.
 	for _, cmd := range c.extraCommands {
 		for _, subCmd := range c.cmd.Commands() {
@@ -259,15 +428,22 @@ func (c *cli) initialize() error {
 		c.cmd.AddCommand(cmd)
 	}
 
-	// Write deprecation notices after all commands have been constructed.
-	for _, p := range c.resolvedPlugins {
-		if d, isDeprecated := p.(plugin.Deprecated); isDeprecated {
-			fmt.Printf(noticeColor, fmt.Sprintf("[Deprecation Notice] %s\n\n",
-				d.DeprecationWarning()))
+	// Hide commands requested by options, which may be extra commands.
+	for _, commandPath := range c.hiddenCommands {
+		cmd, err := findCommand(c.cmd, commandPath)
+		if err != nil {
+			return err
 		}
+		cmd.Hidden = true
 	}
 
-	return nil
+	// Write deprecation notices after all commands have been constructed.
+	warnings, err := newWarningWriter(os.Stderr, c.warningsFormat, c.suppressedWarnings)
+	if err != nil {
+		return err
+	}
+	return warnings.write(append(deprecationWarnings(c.resolvedPlugins...),
+		c.projectVersionDeprecationWarnings()...)...)
 }
 
 // parseBaseFlags parses the command line arguments, looking for flags that
@@ -278,23 +454,114 @@ func (c *cli) parseBaseFlags() error {
 	fs := pflag.NewFlagSet("base", pflag.ExitOnError)
 	fs.ParseErrorsWhitelist = pflag.ParseErrorsWhitelist{UnknownFlags: true}
 
-	var help bool
+	var (
+		help        bool
+		pluginKeys  string
+		verbose     bool
+		quiet       bool
+		toolsConfig string
+		toolEnv     map[string]string
+		toolPaths   map[string]string
+	)
 	// Set base flags that require pre-parsing to initialize c.
 	fs.BoolVarP(&help, helpFlag, "h", false, "print help")
 	fs.StringVar(&c.projectVersion, projectVersionFlag, c.defaultProjectVersion, "project version")
-	fs.StringVar(&c.cliPluginKey, pluginsFlag, "", "plugins to run")
+	fs.StringVar(&pluginKeys, pluginsFlag, "", "plugins to run")
+	fs.StringSliceVar(&c.suppressedWarnings, suppressWarningsFlag, nil, "warnings to suppress")
+	fs.StringVar(&c.warningsFormat, warningsFormatFlag, warningsFormatText, "warnings format")
+	fs.BoolVar(&verbose, verboseFlag, false, "verbose output")
+	fs.BoolVarP(&quiet, quietFlag, "q", false, "quiet output")
+	fs.StringVar(&toolsConfig, toolsConfigFlag, "", "tools config file")
+	fs.StringVar(&c.defaultsFile, defaultsFileFlag, "", "defaults file")
+	fs.StringToStringVar(&toolEnv, toolEnvFlag, nil, "tool environment variables")
+	fs.StringToStringVar(&toolPaths, toolPathFlag, nil, "tool binaries")
+
+	// Global flags injected by options are parsed along with the base flags.
+	if c.globalFlags != nil {
+		var conflictErr error
+		c.globalFlags.VisitAll(func(f *pflag.Flag) {
+			if conflictErr != nil {
+				return
+			}
+			if fs.Lookup(f.Name) != nil || f.Shorthand != "" && fs.ShorthandLookup(f.Shorthand) != nil {
+				conflictErr = fmt.Errorf("global flag %q conflicts with a base flag", f.Name)
+			}
+		})
+		if conflictErr != nil {
+			return conflictErr
+		}
+		fs.AddFlagSet(c.globalFlags)
+	}
 
 	// Parse current CLI args outside of cobra.
 	err := fs.Parse(os.Args[1:])
+	if envErr := setFlagsFromEnv(fs, c.envPrefix); envErr != nil {
+		return envErr
+	}
 	// User needs *generic* help if args are incorrect or --help is set and
 	// --project-version is not set. Plugin-specific help is given if a
 	// plugin.Context is updated, which does not require this field.
 	c.doGenericHelp = err != nil || help && !fs.Lookup(projectVersionFlag).Changed
-	c.cliPluginKey = strings.TrimSpace(c.cliPluginKey)
+	if verbose && quiet {
+		return fmt.Errorf("--%s and --%s can not be used together", verboseFlag, quietFlag)
+	}
+	level := logger.NormalLevel
+	switch {
+	case verbose:
+		level = logger.VerboseLevel
+	case quiet:
+		level = logger.QuietLevel
+	}
+	c.logger = logger.New(os.Stdout, level)
+
+	// Flags take precedence over the tools config file.
+	c.tools = tools.Environment{}
+	if toolsConfig != "" {
+		if c.tools, err = tools.LoadFile(toolsConfig); err != nil {
+			return err
+		}
+	}
+	c.tools = c.tools.Merge(tools.Environment{Env: toolEnv, Paths: toolPaths})
+
+	c.cliPluginKeys = nil
+	for _, key := range strings.Split(pluginKeys, layoutSeparator) {
+		if key = strings.TrimSpace(key); key != "" {
+			c.cliPluginKeys = append(c.cliPluginKeys, key)
+		}
+	}
 
 	return nil
 }
 
+// setFlagsFromEnv sets each flag of fs that was not set, but --help, from its environment variable if set, named
+// after the flag with prefix, e.g. KUBEBUILDER_PROJECT_VERSION for --project-version.
+func setFlagsFromEnv(fs *pflag.FlagSet, prefix string) error {
+	var err error
+	fs.VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed || f.Name == helpFlag {
+			return
+		}
+		name := flagEnvName(prefix, f.Name)
+		value, isSet := os.LookupEnv(name)
+		if !isSet {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q of %s for --%s: %v", value, name, f.Name, setErr)
+		}
+	})
+	return err
+}
+
+// flagEnvName returns the name of the environment variable of the flag name, with prefix.
+func flagEnvName(prefix, name string) string {
+	name = strings.ToUpper(strings.Replace(name, "-", "_", -1))
+	if prefix == "" {
+		return name
+	}
+	return strings.ToUpper(prefix) + "_" + name
+}
+
 // validate validates fields in a cli.
 func (c cli) validate() error {
 	// Validate project version.
@@ -308,7 +575,7 @@ func (c cli) validate() error {
 	// If --plugins is not set, no layout exists (no config or project is v1 or v2),
 	// and no defaults exist, we cannot know which plugins to use.
 	isLayoutSupported := c.projectVersion == config.Version3Alpha
-	if (!c.configured || !isLayoutSupported) && c.cliPluginKey == "" {
+	if (!c.configured || !isLayoutSupported) && len(c.cliPluginKeys) == 0 {
 		_, versionExists := c.defaultPluginsFromOptions[c.projectVersion]
 		if !versionExists {
 			return fmt.Errorf("no default plugins for project version %q", c.projectVersion)
@@ -316,14 +583,14 @@ func (c cli) validate() error {
 	}
 
 	// Validate plugin keys set in CLI.
-	if c.cliPluginKey != "" {
-		pluginName, pluginVersion := plugin.SplitKey(c.cliPluginKey)
+	for _, pluginKey := range c.cliPluginKeys {
+		pluginName, pluginVersion := plugin.SplitKey(pluginKey)
 		if err := plugin.ValidateName(pluginName); err != nil {
 			return fmt.Errorf("invalid plugin name %q: %v", pluginName, err)
 		}
-		// CLI-set plugins do not have to contain a version.
+		// CLI-set plugins do not have to contain a version, which can also be a version range.
 		if pluginVersion != "" {
-			if _, err := plugin.ParseVersion(pluginVersion); err != nil {
+			if _, err := plugin.ParseVersionConstraint(pluginVersion); err != nil {
 				return fmt.Errorf("invalid plugin version %q: %v", pluginVersion, err)
 			}
 		}
@@ -337,8 +604,49 @@ func (c cli) validate() error {
 func (c cli) buildRootCmd() *cobra.Command {
 	rootCmd := c.defaultCommand()
 
+	// Register the output flags, parsed on initialization, so that they show up in help and do not cause
+	// a parse error.
+	rootCmd.PersistentFlags().StringSlice(suppressWarningsFlag, nil,
+		fmt.Sprintf("IDs of the warnings not to write, or %q to suppress all of them (e.g. %s)",
+			allWarnings, deprecatedPluginWarningID))
+	rootCmd.PersistentFlags().Bool(verboseFlag, false,
+		"write debug messages, e.g. each scaffolded file, each executed command and how plugins were resolved")
+	rootCmd.PersistentFlags().BoolP(quietFlag, "q", false, "only write what is requested from the user and errors")
+	rootCmd.PersistentFlags().String(warningsFormatFlag, warningsFormatText,
+		fmt.Sprintf("format warnings are written to stderr in, possible values: (%s, %s)",
+			warningsFormatText, warningsFormatJSON))
+	rootCmd.PersistentFlags().String(toolsConfigFlag, "",
+		"YAML file with the environment variables ('env') and binaries ('paths', by tool name) used to execute "+
+			"external tools, e.g. go, make and controller-gen")
+	rootCmd.PersistentFlags().StringToString(toolEnvFlag, nil,
+		"environment variables set when executing external tools, e.g. GOFLAGS=-mod=vendor,GOPROXY=off, "+
+			"overriding the ones of the tools config file")
+	rootCmd.PersistentFlags().StringToString(toolPathFlag, nil,
+		"binaries executed for external tools, e.g. go=/usr/local/go/bin/go, overriding the ones of the "+
+			"tools config file")
+	rootCmd.PersistentFlags().String(defaultsFileFlag, "",
+		"YAML file with the defaults of the init flags, e.g. 'domain', 'owner', 'license', 'baseImage' and "+
+			"'plugins', defaults to $XDG_CONFIG_HOME/kubebuilder/defaults.yaml or ~/.config/kubebuilder/defaults.yaml")
+	rootCmd.PersistentFlags().Bool(noHistoryFlag, false,
+		"do not record the scaffolding command, with the flags that were set, in the history of the PROJECT file")
+	if c.globalFlags != nil {
+		rootCmd.PersistentFlags().AddFlagSet(c.globalFlags)
+	}
+
 	// kubebuilder alpha
 	alphaCmd := c.newAlphaCmd()
+	// kubebuilder alpha setup-envtest
+	alphaCmd.AddCommand(c.newSetupEnvtestCmd())
+	// kubebuilder alpha rename
+	alphaCmd.AddCommand(c.newRenameCmd())
+	// kubebuilder alpha export
+	alphaCmd.AddCommand(c.newExportCmd())
+	// kubebuilder alpha verify
+	alphaCmd.AddCommand(c.newVerifyCmd())
+	// kubebuilder alpha replay
+	alphaCmd.AddCommand(c.newReplayCmd())
+	// kubebuilder alpha upgrade
+	alphaCmd.AddCommand(c.newUpgradeCmd())
 
 	// Only add alpha group if it has subcommands
 	if alphaCmd.HasSubCommands() {
@@ -354,14 +662,75 @@ func (c cli) buildRootCmd() *cobra.Command {
 		rootCmd.AddCommand(createCmd)
 	}
 
+	// kubebuilder delete
+	deleteCmd := c.newDeleteCmd()
+	// kubebuilder delete api
+	deleteCmd.AddCommand(c.newDeleteAPICmd())
+	// kubebuilder delete webhook
+	deleteCmd.AddCommand(c.newDeleteWebhookCmd())
+	rootCmd.AddCommand(deleteCmd)
+
 	// kubebuilder init
 	rootCmd.AddCommand(c.newInitCmd())
 
+	// kubebuilder apply
+	rootCmd.AddCommand(c.newApplyCmd())
+
+	// kubebuilder build
+	rootCmd.AddCommand(c.newBuildCmd())
+
+	// kubebuilder deploy
+	rootCmd.AddCommand(c.newDeployCmd())
+
+	// kubebuilder undeploy
+	rootCmd.AddCommand(c.newUndeployCmd())
+
+	// kubebuilder run
+	rootCmd.AddCommand(c.newRunCmd())
+
+	// kubebuilder doctor
+	rootCmd.AddCommand(c.newDoctorCmd())
+
+	// kubebuilder explain
+	rootCmd.AddCommand(c.newExplainCmd())
+
+	// kubebuilder plugins
+	rootCmd.AddCommand(c.newPluginsCmd())
+
 	return rootCmd
 }
 
-// defaultCommand returns the root command without its subcommands.
+// findCommand returns the subcommand of root at commandPath, a space separated
+// list of command names.
+func findCommand(root *cobra.Command, commandPath string) (*cobra.Command, error) {
+	cmd := root
+	for _, name := range strings.Fields(commandPath) {
+		if cmd = findSubcommand(cmd, name); cmd == nil {
+			return nil, fmt.Errorf("command %q does not exist", commandPath)
+		}
+	}
+	if cmd == root {
+		return nil, fmt.Errorf("the root command can not be hidden")
+	}
+	return cmd, nil
+}
+
+// defaultCommand returns the root command without its subcommands. Its
+// description and examples can be replaced by options.
 func (c cli) defaultCommand() *cobra.Command {
+	cmd := c.newDefaultCommand()
+	if c.description != "" {
+		cmd.Short = strings.SplitN(strings.TrimSpace(c.description), "\n", 2)[0]
+		cmd.Long = c.description
+	}
+	if c.examples != "" {
+		cmd.Example = c.examples
+	}
+	return cmd
+}
+
+// newDefaultCommand returns the kubebuilder root command without its subcommands.
+func (c cli) newDefaultCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   c.commandName,
 		Short: "Development kit for building Kubernetes extensions and tools.",
@@ -385,8 +754,13 @@ scaffold a Controller for an existing Resource, select "n" for Resource. To only
 the schema for a Resource without writing a Controller, select "n" for Controller.
 
 After the scaffold is written, api will run make on the project.
+
+The flags configuring the CLI itself, e.g. --plugins, --project-version and --verbose, fall back to the
+environment variables named after them when they are not set, e.g. %s for --plugins and %s for
+--project-version.
 `,
-			c.commandName, c.commandName),
+			c.commandName, c.commandName, flagEnvName(c.envPrefix, pluginsFlag),
+			flagEnvName(c.envPrefix, projectVersionFlag)),
 		Example: fmt.Sprintf(`
   # Initialize your project
   %s init --domain example.com --license apache2 --owner "The Kubernetes authors"
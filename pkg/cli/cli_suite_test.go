@@ -48,7 +48,9 @@ func (p mockPlugin) SupportedProjectVersions() []string { return p.projectVersio
 func (mockPlugin) UpdateContext(*plugin.Context) {}
 func (mockPlugin) BindFlags(*pflag.FlagSet)      {}
 func (mockPlugin) InjectConfig(*config.Config)   {}
-func (mockPlugin) Run() error                    { return nil }
+func (mockPlugin) Validate() error               { return nil }
+func (mockPlugin) Scaffold() error               { return nil }
+func (mockPlugin) PostScaffold() error           { return nil }
 
 func makeBasePlugin(name, version string, projVers ...string) plugin.Base {
 	v, err := plugin.ParseVersion(version)
@@ -17,13 +17,19 @@ limitations under the License.
 package cli
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/tools"
 )
 
 var _ = Describe("CLI", func() {
@@ -139,9 +145,32 @@ var _ = Describe("CLI", func() {
 				Expect(c).NotTo(BeNil())
 				Expect(c.(*cli).pluginsFromOptions).To(Equal(makeSetByProjVer(allPlugins...)))
 				Expect(c.(*cli).resolvedPlugins).To(Equal([]plugin.Base{pluginBV2}))
+
+				By(`setting cliPluginKey to "go.example.com/v1,go.test.com/v2"`)
+				setPluginsFlag("go.example.com/v1,go.test.com/v2")
+				c, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(allPlugins...))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c).NotTo(BeNil())
+				Expect(c.(*cli).layoutPlugins).To(Equal([]plugin.Base{pluginAV1, pluginBV2}))
+				Expect(c.(*cli).resolvedPlugins).To(Equal([]plugin.Base{pluginAV1, pluginBV2}))
+
+				By(`setting cliPluginKey to a bundle "bundle.example.com/v1"`)
+				bundle, err := plugin.NewBundle("bundle.example.com", plugin.Version{Number: 1}, pluginAV1, pluginBV2)
+				Expect(err).NotTo(HaveOccurred())
+				setPluginsFlag("bundle.example.com/v1")
+				c, err = New(WithDefaultPlugins(bundle), WithPlugins(bundle, pluginAV1, pluginBV2))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c).NotTo(BeNil())
+				Expect(c.(*cli).layoutPlugins).To(Equal([]plugin.Base{bundle}))
+				Expect(c.(*cli).resolvedPlugins).To(Equal([]plugin.Base{pluginAV1, pluginBV2}))
 			})
 
 			It("should return an error", func() {
+				By(`setting cliPluginKey to the same plugin twice "go/v1,go.example.com/v1"`)
+				setPluginsFlag("go/v1,go.example.com/v1")
+				_, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1, pluginAV2))
+				Expect(err).To(MatchError(`plugin "go.example.com/v1" can not be chained more than once`))
+
 				By(`setting cliPluginKey to an non-existent key "foo"`)
 				setPluginsFlag("foo")
 				_, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1, pluginAV2))
@@ -152,6 +181,219 @@ var _ = Describe("CLI", func() {
 			})
 		})
 
+		Context("with embedding options", func() {
+			It("should rebrand the root command", func() {
+				c, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1),
+					WithDescription("Operator kit.\n\nBuilds operators."),
+					WithExamples("  operator-kit init"))
+				Expect(err).NotTo(HaveOccurred())
+				cmd := c.(*cli).cmd
+				Expect(cmd.Short).To(Equal("Operator kit."))
+				Expect(cmd.Long).To(Equal("Operator kit.\n\nBuilds operators."))
+				Expect(cmd.Example).To(Equal("  operator-kit init"))
+			})
+
+			It("should hide commands", func() {
+				c, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1),
+					WithExtraCommands(&cobra.Command{Use: "extra"}),
+					WithHiddenCommands("create webhook", "extra"))
+				Expect(err).NotTo(HaveOccurred())
+				cmd := c.(*cli).cmd
+				for path, hidden := range map[string]bool{
+					"create webhook": true,
+					"extra":          true,
+					"create api":     false,
+					"init":           false,
+				} {
+					subCmd, err := findCommand(cmd, path)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(subCmd.Hidden).To(Equal(hidden), path)
+				}
+			})
+
+			It("should return an error when hiding a command that does not exist", func() {
+				_, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1),
+					WithHiddenCommands("create foo"))
+				Expect(err).To(MatchError(`command "create foo" does not exist`))
+			})
+		})
+
+		Context("with global flags", func() {
+
+			var (
+				args []string
+			)
+
+			BeforeEach(func() {
+				args = os.Args
+			})
+
+			AfterEach(func() {
+				os.Args = args
+			})
+
+			It("should parse them along with the base flags", func() {
+				var kubeconfig string
+				fs := pflag.NewFlagSet("embedder", pflag.ContinueOnError)
+				fs.StringVar(&kubeconfig, "kubeconfig", "", "path to the kubeconfig file")
+
+				os.Args = append(args, "init", "--kubeconfig", "/tmp/kubeconfig")
+				c, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1), WithGlobalFlags(fs))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(kubeconfig).To(Equal("/tmp/kubeconfig"))
+				Expect(c.(*cli).cmd.PersistentFlags().Lookup("kubeconfig")).NotTo(BeNil())
+			})
+
+			It("should return an error when they conflict", func() {
+				By("registering a base flag")
+				fs := pflag.NewFlagSet("embedder", pflag.ContinueOnError)
+				fs.String(pluginsFlag, "", "")
+				_, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1), WithGlobalFlags(fs))
+				Expect(err).To(MatchError(`global flag "plugins" conflicts with a base flag`))
+
+				By("registering a base flag shorthand")
+				fs = pflag.NewFlagSet("embedder", pflag.ContinueOnError)
+				fs.BoolP("quick", "q", false, "")
+				_, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1), WithGlobalFlags(fs))
+				Expect(err).To(MatchError(`global flag "quick" conflicts with a base flag`))
+
+				By("registering a flag twice")
+				fs = pflag.NewFlagSet("embedder", pflag.ContinueOnError)
+				fs.Bool("telemetry", false, "")
+				_, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1),
+					WithGlobalFlags(fs), WithGlobalFlags(fs))
+				Expect(err).To(MatchError(`global flag "telemetry" is registered more than once`))
+			})
+		})
+
+		Context("with base flags set in the environment", func() {
+
+			var (
+				args []string
+			)
+
+			BeforeEach(func() {
+				args = os.Args
+			})
+
+			AfterEach(func() {
+				os.Args = args
+				for _, name := range []string{"KUBEBUILDER_PLUGINS", "KUBEBUILDER_VERBOSE", "MYCLI_PLUGINS"} {
+					Expect(os.Unsetenv(name)).To(Succeed())
+				}
+			})
+
+			It("should fall back to them when the flags are not set", func() {
+				By("setting KUBEBUILDER_PLUGINS and KUBEBUILDER_VERBOSE")
+				Expect(os.Setenv("KUBEBUILDER_PLUGINS", "go/v2")).To(Succeed())
+				Expect(os.Setenv("KUBEBUILDER_VERBOSE", "true")).To(Succeed())
+				os.Args = append(args, "init")
+				c, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1, pluginAV2))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c.(*cli).cliPluginKeys).To(Equal([]string{"go/v2"}))
+				Expect(c.(*cli).logger.Level()).To(Equal(logger.VerboseLevel))
+
+				By("setting --plugins too")
+				setPluginsFlag("go/v1")
+				c, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1, pluginAV2))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c.(*cli).cliPluginKeys).To(Equal([]string{"go/v1"}))
+			})
+
+			It("should read the variables with the prefix set by an option", func() {
+				Expect(os.Setenv("KUBEBUILDER_PLUGINS", "go/v1")).To(Succeed())
+				Expect(os.Setenv("MYCLI_PLUGINS", "go/v2")).To(Succeed())
+				os.Args = append(args, "init")
+				c, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1, pluginAV2), WithEnvPrefix("MYCLI"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c.(*cli).cliPluginKeys).To(Equal([]string{"go/v2"}))
+			})
+
+			It("should return an error for an invalid value", func() {
+				Expect(os.Setenv("KUBEBUILDER_VERBOSE", "maybe")).To(Succeed())
+				os.Args = append(args, "init")
+				_, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1))
+				Expect(err).To(MatchError(ContainSubstring(`invalid value "maybe" of KUBEBUILDER_VERBOSE for --verbose`)))
+			})
+		})
+
+		Context("with --verbose or --quiet set", func() {
+
+			var (
+				args []string
+			)
+
+			BeforeEach(func() {
+				args = os.Args
+			})
+
+			AfterEach(func() {
+				os.Args = args
+			})
+
+			It("should return a CLI logging at the requested level", func() {
+				By("setting --verbose")
+				os.Args = append(args, "init", "--"+verboseFlag)
+				c, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c.(*cli).logger.Level()).To(Equal(logger.VerboseLevel))
+
+				By("setting -q")
+				os.Args = append(args, "init", "-q")
+				c, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c.(*cli).logger.Level()).To(Equal(logger.QuietLevel))
+			})
+
+			It("should return an error when both are set", func() {
+				os.Args = append(args, "init", "--"+verboseFlag, "--"+quietFlag)
+				_, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1))
+				Expect(err).To(MatchError("--verbose and --quiet can not be used together"))
+			})
+		})
+
+		Context("with the tools environment set", func() {
+
+			var (
+				args []string
+				dir  string
+			)
+
+			BeforeEach(func() {
+				args = os.Args
+				dir, err = ioutil.TempDir("", "cli")
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				os.Args = args
+				tools.SetDefault(tools.Environment{})
+				Expect(os.RemoveAll(dir)).To(Succeed())
+			})
+
+			It("should give precedence to the flags over the tools config file", func() {
+				toolsConfig := filepath.Join(dir, "tools.yaml")
+				Expect(ioutil.WriteFile(toolsConfig,
+					[]byte("env:\n  GOPROXY: direct\n  GOPRIVATE: example.com\npaths:\n  make: /usr/bin/make\n"),
+					0600)).To(Succeed())
+
+				os.Args = append(args, "init", "--"+toolsConfigFlag, toolsConfig,
+					"--"+toolEnvFlag, "GOPROXY=off,GOFLAGS=-mod=vendor", "--"+toolPathFlag, "go=/opt/go/bin/go")
+				_, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tools.Default()).To(Equal(tools.Environment{
+					Env:   map[string]string{"GOPROXY": "off", "GOPRIVATE": "example.com", "GOFLAGS": "-mod=vendor"},
+					Paths: map[string]string{"go": "/opt/go/bin/go", "make": "/usr/bin/make"},
+				}))
+			})
+
+			It("should return an error if the tools config file can not be read", func() {
+				os.Args = append(args, "init", "--"+toolsConfigFlag, filepath.Join(dir, "missing.yaml"))
+				_, err = New(WithDefaultPlugins(pluginAV1), WithPlugins(pluginAV1))
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
 	})
 
 })
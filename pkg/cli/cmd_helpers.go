@@ -18,10 +18,13 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/internal/config"
+	modelconfig "sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/plugin"
 )
 
@@ -45,16 +48,166 @@ func errCmdFunc(err error) func(*cobra.Command, []string) error {
 	}
 }
 
-// runECmdFunc returns a cobra RunE function that runs gsub and saves the
-// config, which may have been modified by gsub.
+// bindLazily defers bind, which binds the plugins to cmd, until cmd runs or its help is written.
+func (c cli) bindLazily(cmd *cobra.Command, bind func()) {
+	c.binders[cmd] = bind
+}
+
+// bindRunCommand binds the plugins to the command run by args, the same way cobra finds it, so that the
+// plugin subcommands of the other commands are not constructed. 'help <command>' binds the command whose
+// help is written.
+func (c cli) bindRunCommand(args []string) {
+	c.cmd.InitDefaultHelpCmd()
+	cmd, rest, err := c.cmd.Find(args)
+	if err != nil {
+		return
+	}
+	if cmd.Parent() == c.cmd && cmd.Name() == "help" {
+		if cmd, _, err = c.cmd.Find(rest); err != nil {
+			return
+		}
+	}
+	if bind, isLazy := c.binders[cmd]; isLazy {
+		delete(c.binders, cmd)
+		bind()
+	}
+}
+
+// bindSubcommands injects c into each of gsubs, binds their flags to cmd and
+// updates ctx with their help text, in the order they are chained. Flags are
+// bound to a separate flag set per subcommand first, so that a flag defined by
+// more than one subcommand results in an error instead of a panic. keys are the
+// keys of the plugins of gsubs: if several are chained, the help text of each
+// one is written in a section labeled with its key, after the one of the cli.
+func bindSubcommands(
+	ctx *plugin.Context,
+	cmd *cobra.Command,
+	c *modelconfig.Config,
+	gsubs []plugin.GenericSubcommand,
+	keys []string) error {
+	var descriptions, examples strings.Builder
+	for i, gsub := range gsubs {
+		gsub.InjectConfig(c)
+
+		fs := pflag.NewFlagSet(cmd.Name(), pflag.ContinueOnError)
+		gsub.BindFlags(fs)
+		var err error
+		fs.VisitAll(func(f *pflag.Flag) {
+			if err != nil {
+				return
+			}
+			if cmd.Flags().Lookup(f.Name) != nil ||
+				(f.Shorthand != "" && cmd.Flags().ShorthandLookup(f.Shorthand) != nil) {
+				err = fmt.Errorf("flag %q is defined by more than one plugin", f.Name)
+				return
+			}
+			cmd.Flags().AddFlag(f)
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(gsubs) == 1 {
+			gsub.UpdateContext(ctx)
+			continue
+		}
+		// Each subcommand gets the context without the help text of the others, so that none replaces it.
+		section := *ctx
+		section.Description, section.Examples = "", ""
+		gsub.UpdateContext(&section)
+		if description := strings.TrimSpace(section.Description); description != "" {
+			fmt.Fprintf(&descriptions, "\nPlugin %s:\n%s\n", keys[i], description)
+		}
+		if example := strings.TrimRight(strings.TrimLeft(section.Examples, "\n"), " \t\n"); example != "" {
+			fmt.Fprintf(&examples, "\n\n  # Plugin %s:\n\n%s", keys[i], example)
+		}
+	}
+	if len(gsubs) > 1 {
+		ctx.Description += descriptions.String()
+		ctx.Examples = strings.TrimPrefix(ctx.Examples+examples.String(), "\n\n")
+	}
+	return nil
+}
+
+// runECmdFunc returns a cobra RunE function that runs gsubs and saves the
+// config, which may have been modified by gsubs, using runRecordedSubcommands.
 func runECmdFunc(
 	c *config.Config,
-	gsub plugin.GenericSubcommand, // nolint:interfacer
+	gsubs []plugin.GenericSubcommand,
 	msg string) func(*cobra.Command, []string) error {
-	return func(*cobra.Command, []string) error {
-		if err := gsub.Run(); err != nil {
+	return func(cmd *cobra.Command, _ []string) error {
+		if err := runRecordedSubcommands(c, cmd, gsubs); err != nil {
 			return fmt.Errorf("%s: %v", msg, err)
 		}
-		return c.Save()
+		return nil
+	}
+}
+
+// recordCommand records cmd in the history of c, unless --no-history is set, and reports whether it did.
+func recordCommand(c *modelconfig.Config, cmd *cobra.Command) bool {
+	if noHistory, err := cmd.Flags().GetBool(noHistoryFlag); err == nil && noHistory {
+		return false
+	}
+	c.RecordCommand(recordedCommand(cmd))
+	return true
+}
+
+// recordedCommand returns cmd as recorded in the history of a project: its path below the root command and
+// the flags that were set, sorted by name, as --name=value, list flags being repeated for each of their values.
+func recordedCommand(cmd *cobra.Command) modelconfig.RecordedCommand {
+	recorded := modelconfig.RecordedCommand{
+		Command: strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+	}
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		if !f.Changed || f.Name == helpFlag {
+			return
+		}
+		values := []string{f.Value.String()}
+		if slice, isSlice := f.Value.(pflag.SliceValue); isSlice {
+			values = slice.GetSlice()
+		}
+		for _, value := range values {
+			recorded.Flags = append(recorded.Flags, fmt.Sprintf("--%s=%s", f.Name, value))
+		}
+	})
+	return recorded
+}
+
+// runSubcommands runs gsubs phase by phase, each phase being run by every
+// subcommand in order before the next one starts: all of them are validated
+// before any file is scaffolded, and c is saved once all of them scaffolded,
+// before they are post-scaffolded.
+func runSubcommands(c *config.Config, gsubs []plugin.GenericSubcommand) error {
+	for _, gsub := range gsubs {
+		if err := gsub.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, gsub := range gsubs {
+		if err := gsub.Scaffold(); err != nil {
+			return err
+		}
+	}
+	if err := c.Save(); err != nil {
+		return err
+	}
+	for _, gsub := range gsubs {
+		if err := gsub.PostScaffold(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runRecordedSubcommands runs gsubs using runSubcommands and, once every phase succeeded, records cmd in the
+// history of c and saves it again, so that a command failing to post-scaffold, e.g. to run make, is not
+// recorded.
+func runRecordedSubcommands(c *config.Config, cmd *cobra.Command, gsubs []plugin.GenericSubcommand) error {
+	if err := runSubcommands(c, gsubs); err != nil {
+		return err
+	}
+	if !recordCommand(&c.Config, cmd) {
+		return nil
 	}
+	return c.Save()
 }
@@ -0,0 +1,315 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+// phaseRecorder is a subcommand recording the phases it runs, failing on failPhase.
+type phaseRecorder struct {
+	mockPlugin
+	name      string
+	failPhase string
+	phases    *[]string
+}
+
+func (p phaseRecorder) run(phase string) error {
+	*p.phases = append(*p.phases, p.name+" "+phase)
+	if phase == p.failPhase {
+		return errors.New(p.name + " failed")
+	}
+	return nil
+}
+
+func (p phaseRecorder) Validate() error     { return p.run("validate") }
+func (p phaseRecorder) Scaffold() error     { return p.run("scaffold") }
+func (p phaseRecorder) PostScaffold() error { return p.run("post-scaffold") }
+
+// bindRecorder is a subcommand recording the commands it is bound to, binding a flag named after them.
+type bindRecorder struct {
+	mockPlugin
+	name  string
+	bound *[]string
+}
+
+func (p bindRecorder) BindFlags(fs *pflag.FlagSet) {
+	*p.bound = append(*p.bound, p.name)
+	fs.String(p.name+"-flag", "", "")
+}
+
+// mockBindPlugin binds a bindRecorder to init and to its commands.
+type mockBindPlugin struct {
+	mockCommandsPlugin
+	bound *[]string
+}
+
+func (p mockBindPlugin) GetInitPlugin() plugin.Init {
+	return bindRecorder{mockPlugin: p.mockPlugin, name: "init", bound: p.bound}
+}
+
+var _ = Describe("bindRunCommand", func() {
+
+	var (
+		wd, dir string
+		bound   []string
+		c       *cli
+	)
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "kubebuilder-cli")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(dir)).To(Succeed())
+
+		p := mockBindPlugin{bound: &bound}
+		p.mockPlugin = makeBasePlugin("cmds.example.com", "v1", internalconfig.DefaultVersion).(mockPlugin)
+		for _, name := range []string{"one", "two"} {
+			p.commands = append(p.commands, plugin.Command{
+				Path:       "alpha " + name,
+				Subcommand: bindRecorder{mockPlugin: p.mockPlugin, name: name, bound: &bound},
+			})
+		}
+		cfg := internalconfig.New(internalconfig.DefaultPath)
+		cfg.Layout = plugin.KeyFor(p)
+		Expect(cfg.Save()).To(Succeed())
+
+		bound = nil
+		kb, err := New(WithDefaultPlugins(p), WithPlugins(p))
+		Expect(err).NotTo(HaveOccurred())
+		c = kb.(*cli)
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should only bind the plugins to the command that runs", func() {
+		Expect(bound).To(BeEmpty())
+
+		c.bindRunCommand([]string{"alpha", "one", "--one-flag", "value"})
+		Expect(bound).To(Equal([]string{"one"}))
+		one, err := findCommand(c.cmd, "alpha one")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(one.Flags().Lookup("one-flag")).NotTo(BeNil())
+
+		By("running the same command again")
+		c.bindRunCommand([]string{"alpha", "one"})
+		Expect(bound).To(Equal([]string{"one"}))
+	})
+
+	It("should bind the command whose help is written", func() {
+		c.bindRunCommand([]string{"help", "alpha", "two"})
+		Expect(bound).To(Equal([]string{"two"}))
+	})
+
+	It("should not bind any plugin to the root command", func() {
+		c.bindRunCommand([]string{"--help"})
+		c.bindRunCommand([]string{"help"})
+		c.bindRunCommand([]string{"alpha"})
+		Expect(bound).To(BeEmpty())
+	})
+})
+
+// helpWriter is a subcommand appending its help text to the context.
+type helpWriter struct {
+	mockPlugin
+	description, examples string
+}
+
+func (p helpWriter) UpdateContext(ctx *plugin.Context) {
+	ctx.Description += p.description
+	ctx.Examples += p.examples
+}
+
+var _ = Describe("bindSubcommands", func() {
+
+	var (
+		ctx plugin.Context
+		cmd *cobra.Command
+	)
+
+	BeforeEach(func() {
+		ctx = plugin.Context{Description: "Scaffold a Kubernetes API.\n", Examples: "  # Create an API\n  kb create api"}
+		cmd = &cobra.Command{Use: "api"}
+	})
+
+	It("should let a single plugin update the help text", func() {
+		gsubs := []plugin.GenericSubcommand{helpWriter{description: "Scaffolds Go types.\n"}}
+		Expect(bindSubcommands(&ctx, cmd, &config.Config{}, gsubs, []string{"go.example.com/v1"})).To(Succeed())
+		Expect(ctx.Description).To(Equal("Scaffold a Kubernetes API.\nScaffolds Go types.\n"))
+		Expect(ctx.Examples).To(Equal("  # Create an API\n  kb create api"))
+	})
+
+	It("should write the help text of each plugin in a section labeled with its key", func() {
+		gsubs := []plugin.GenericSubcommand{
+			helpWriter{description: "Scaffolds Go types.\n", examples: "  # Create a Go API\n  kb create api\n"},
+			helpWriter{},
+			helpWriter{description: "\nWrites manifests.\n"},
+		}
+		keys := []string{"go.example.com/v1", "noop.example.com/v1", "kustomize.example.com/v1"}
+		Expect(bindSubcommands(&ctx, cmd, &config.Config{}, gsubs, keys)).To(Succeed())
+		Expect(ctx.Description).To(Equal("Scaffold a Kubernetes API.\n" +
+			"\nPlugin go.example.com/v1:\nScaffolds Go types.\n" +
+			"\nPlugin kustomize.example.com/v1:\nWrites manifests.\n"))
+		Expect(ctx.Examples).To(Equal("  # Create an API\n  kb create api\n\n" +
+			"  # Plugin go.example.com/v1:\n\n  # Create a Go API\n  kb create api"))
+	})
+})
+
+var _ = Describe("runSubcommands", func() {
+
+	var (
+		dir    string
+		cfg    *internalconfig.Config
+		phases []string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "kubebuilder-cli")
+		Expect(err).NotTo(HaveOccurred())
+		cfg = internalconfig.New(filepath.Join(dir, internalconfig.DefaultPath))
+		phases = nil
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	subcommands := func(failName, failPhase string) []plugin.GenericSubcommand {
+		var gsubs []plugin.GenericSubcommand
+		for _, name := range []string{"a", "b"} {
+			p := phaseRecorder{name: name, phases: &phases}
+			if name == failName {
+				p.failPhase = failPhase
+			}
+			gsubs = append(gsubs, p)
+		}
+		return gsubs
+	}
+
+	It("should run each phase for every subcommand before the next phase", func() {
+		Expect(runSubcommands(cfg, subcommands("", ""))).To(Succeed())
+		Expect(phases).To(Equal([]string{
+			"a validate", "b validate",
+			"a scaffold", "b scaffold",
+			"a post-scaffold", "b post-scaffold",
+		}))
+		Expect(cfg.Path()).To(BeAnExistingFile())
+	})
+
+	It("should not scaffold if a subcommand fails validation", func() {
+		Expect(runSubcommands(cfg, subcommands("b", "validate"))).To(MatchError("b failed"))
+		Expect(phases).To(Equal([]string{"a validate", "b validate"}))
+		Expect(cfg.Path()).NotTo(BeAnExistingFile())
+	})
+
+	It("should save the config before post-scaffolding", func() {
+		Expect(runSubcommands(cfg, subcommands("a", "post-scaffold"))).To(MatchError("a failed"))
+		Expect(phases).To(Equal([]string{
+			"a validate", "b validate",
+			"a scaffold", "b scaffold",
+			"a post-scaffold",
+		}))
+		Expect(cfg.Path()).To(BeAnExistingFile())
+	})
+
+	Context("recording the command", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cfg.Version = config.Version3Alpha
+			root := &cobra.Command{Use: "kubebuilder"}
+			root.PersistentFlags().Bool(noHistoryFlag, false, "")
+			cmd = &cobra.Command{Use: "edit"}
+			cmd.Flags().Bool("multigroup", false, "")
+			root.AddCommand(cmd)
+			Expect(cmd.ParseFlags([]string{"--multigroup"})).To(Succeed())
+		})
+
+		It("should record and save the command once every phase succeeded", func() {
+			Expect(runRecordedSubcommands(cfg, cmd, subcommands("", ""))).To(Succeed())
+			saved, err := internalconfig.ReadFrom(cfg.Path())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved.History).To(Equal([]config.RecordedCommand{{Command: "edit", Flags: []string{"--multigroup=true"}}}))
+		})
+
+		It("should not record the command if a subcommand fails to post-scaffold", func() {
+			Expect(runRecordedSubcommands(cfg, cmd, subcommands("b", "post-scaffold"))).To(MatchError("b failed"))
+			saved, err := internalconfig.ReadFrom(cfg.Path())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(saved.History).To(BeEmpty())
+			Expect(cfg.History).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("recordCommand", func() {
+	var (
+		root *cobra.Command
+		cmd  *cobra.Command
+		cfg  config.Config
+	)
+
+	BeforeEach(func() {
+		root = &cobra.Command{Use: "kubebuilder"}
+		root.PersistentFlags().Bool(noHistoryFlag, false, "")
+		create := &cobra.Command{Use: "create"}
+		cmd = &cobra.Command{Use: "api"}
+		cmd.Flags().String("group", "", "")
+		cmd.Flags().String("kind", "", "")
+		cmd.Flags().Bool("resource", true, "")
+		cmd.Flags().StringArray("set", nil, "")
+		cmd.Flags().StringSlice("plugins", nil, "")
+		root.AddCommand(create)
+		create.AddCommand(cmd)
+		cfg = config.Config{Version: config.Version3Alpha}
+	})
+
+	It("should record the command path and the flags that were set, sorted by name", func() {
+		Expect(cmd.ParseFlags([]string{
+			"--kind", "Captain", "--group=crew", "--set", "a=1", "--set", "b=2", "--plugins", "x,y",
+		})).To(Succeed())
+		Expect(recordCommand(&cfg, cmd)).To(BeTrue())
+		Expect(cfg.History).To(Equal([]config.RecordedCommand{{
+			Command: "create api",
+			Flags:   []string{"--group=crew", "--kind=Captain", "--plugins=x", "--plugins=y", "--set=a=1", "--set=b=2"},
+		}}))
+	})
+
+	It("should not record the command with --no-history", func() {
+		Expect(cmd.ParseFlags([]string{"--kind", "Captain", "--no-history"})).To(Succeed())
+		Expect(recordCommand(&cfg, cmd)).To(BeFalse())
+		Expect(cfg.History).To(BeEmpty())
+	})
+})
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+// Parent commands plugin commands can be mounted under, other than the root command.
+const (
+	alphaCommandName  = "alpha"
+	createCommandName = "create"
+)
+
+// addPluginCommands mounts the commands contributed by resolved plugins under
+// c.cmd. A command whose path is already used, by the cli or another plugin,
+// results in an error.
+func (c *cli) addPluginCommands() error {
+	for _, p := range c.resolvedPlugins {
+		getter, isGetter := p.(plugin.CommandsPluginGetter)
+		if !isGetter {
+			continue
+		}
+		for _, pluginCmd := range getter.GetCommands() {
+			if err := c.addPluginCommand(plugin.KeyFor(p), pluginCmd); err != nil {
+				return fmt.Errorf("plugin %q: %v", plugin.KeyFor(p), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *cli) addPluginCommand(key string, pluginCmd plugin.Command) error {
+	names := strings.Fields(pluginCmd.Path)
+	var parentName, name string
+	switch len(names) {
+	case 1:
+		name = names[0]
+	case 2:
+		parentName, name = names[0], names[1]
+	default:
+		return fmt.Errorf("invalid command path %q", pluginCmd.Path)
+	}
+
+	parent := c.cmd
+	if parentName != "" {
+		if parentName != alphaCommandName && parentName != createCommandName {
+			return fmt.Errorf("command %q can only be mounted under the root, %q or %q commands",
+				pluginCmd.Path, alphaCommandName, createCommandName)
+		}
+		// The alpha command is only added to the root command if it has subcommands.
+		if parent = findSubcommand(c.cmd, parentName); parent == nil && parentName == alphaCommandName {
+			parent = c.newAlphaCmd()
+			c.cmd.AddCommand(parent)
+		}
+		if parent == nil {
+			return fmt.Errorf("parent command %q does not exist", parentName)
+		}
+	}
+	if findSubcommand(parent, name) != nil {
+		return fmt.Errorf("command %q already exists", pluginCmd.Path)
+	}
+
+	cmd := &cobra.Command{
+		Use:   name,
+		Short: pluginCmd.Short,
+		Long:  pluginCmd.Short,
+	}
+	c.bindLazily(cmd, func() { c.bindPluginCommand(cmd, key, pluginCmd) })
+	parent.AddCommand(cmd)
+	return nil
+}
+
+func (c cli) bindPluginCommand(cmd *cobra.Command, key string, pluginCmd plugin.Command) {
+	ctx := plugin.Context{
+		CommandName: c.commandName,
+		Logger:      c.logger,
+		GlobalFlags: c.globalFlags,
+		Description: pluginCmd.Short,
+	}
+
+	cfg, err := config.LoadInitialized()
+	if err != nil {
+		cmdErr(cmd, err)
+		return
+	}
+
+	subcommands := []plugin.GenericSubcommand{pluginCmd.Subcommand}
+	if err := bindSubcommands(&ctx, cmd, &cfg.Config, subcommands, []string{key}); err != nil {
+		cmdErr(cmd, err)
+		return
+	}
+	cmd.Long = ctx.Description
+	cmd.Example = ctx.Examples
+	cmd.RunE = runECmdFunc(cfg, subcommands, fmt.Sprintf("failed to run %q", pluginCmd.Path))
+}
+
+// findSubcommand returns the direct subcommand of cmd named name, or nil if
+// there is none.
+func findSubcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, subCmd := range cmd.Commands() {
+		if subCmd.Name() == name {
+			return subCmd
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+type mockCommandsPlugin struct {
+	mockPlugin
+	commands []plugin.Command
+}
+
+func (p mockCommandsPlugin) GetCommands() []plugin.Command { return p.commands }
+
+func makeCommandsPlugin(name string, paths ...string) plugin.Base {
+	p := mockCommandsPlugin{mockPlugin: makeBasePlugin(name, "v1", internalconfig.DefaultVersion).(mockPlugin)}
+	for _, path := range paths {
+		p.commands = append(p.commands, plugin.Command{Path: path, Short: "Run " + path, Subcommand: p.mockPlugin})
+	}
+	return p
+}
+
+var _ = Describe("Plugin commands", func() {
+
+	It("should mount plugin commands", func() {
+		p := makeCommandsPlugin("cmds.example.com", "bundle", "create channel", "alpha generate")
+		c, err := New(WithDefaultPlugins(p), WithPlugins(p))
+		Expect(err).NotTo(HaveOccurred())
+
+		root := c.(*cli).cmd
+		for _, path := range []string{"bundle", "create channel", "create api", "alpha generate"} {
+			cmd, err := findCommand(root, path)
+			Expect(err).NotTo(HaveOccurred(), path)
+			if path != "create api" {
+				Expect(cmd.Short).To(Equal("Run " + path))
+			}
+		}
+	})
+
+	It("should return an error when a command conflicts", func() {
+		By("using the path of a cli command")
+		p := makeCommandsPlugin("cmds.example.com", "init")
+		_, err := New(WithDefaultPlugins(p), WithPlugins(p))
+		Expect(err).To(MatchError(`plugin "cmds.example.com/v1": command "init" already exists`))
+
+		By("using the path of a command of another plugin")
+		p1 := makeCommandsPlugin("cmds.example.com", "create channel")
+		p2 := makeCommandsPlugin("other.example.com", "create channel")
+		bundle, err := plugin.NewBundle("bundle.example.com", plugin.Version{Number: 1}, p1, p2)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = New(WithDefaultPlugins(bundle), WithPlugins(bundle))
+		Expect(err).To(MatchError(`plugin "other.example.com/v1": command "create channel" already exists`))
+
+		By("using the path of an extra command")
+		p = makeCommandsPlugin("cmds.example.com", "bundle")
+		_, err = New(WithDefaultPlugins(p), WithPlugins(p), WithExtraCommands(&cobra.Command{Use: "bundle"}))
+		Expect(err).To(MatchError(`command "bundle" already exists`))
+	})
+
+	It("should return an error when a command can not be mounted", func() {
+		for path, msg := range map[string]string{
+			"":               `invalid command path ""`,
+			"create foo bar": `invalid command path "create foo bar"`,
+			"edit foo":       `command "edit foo" can only be mounted under the root, "alpha" or "create" commands`,
+		} {
+			p := makeCommandsPlugin("cmds.example.com", path)
+			_, err := New(WithDefaultPlugins(p), WithPlugins(p))
+			Expect(err).To(MatchError(`plugin "cmds.example.com/v1": `+msg), path)
+		}
+	})
+})
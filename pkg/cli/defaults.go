@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultsFileFlag = "defaults-file"
+
+	// defaultsFileName is the name of the defaults file in the kubebuilder directory of the user config directory.
+	defaultsFileName = "defaults.yaml"
+)
+
+// userDefaults are the defaults of the init flags of a user, read from the defaults file, e.g.:
+//
+//	domain: example.org
+//	owner: The Example Authors
+//	license: apache2
+//	baseImage: gcr.io/distroless/static:latest
+//	plugins: [go/v3-alpha]
+//	flags:
+//	  go-version: "1.15"
+type userDefaults struct {
+	Domain  string `json:"domain,omitempty"`
+	Owner   string `json:"owner,omitempty"`
+	License string `json:"license,omitempty"`
+	// BaseImage is the image the Dockerfile packages the manager binary in.
+	BaseImage string `json:"baseImage,omitempty"`
+	// Plugins are the keys of the plugins projects are initialized with if --plugins is not set.
+	Plugins []string `json:"plugins,omitempty"`
+	// Flags are the defaults of the other init flags, by name.
+	Flags map[string]string `json:"flags,omitempty"`
+}
+
+// defaultDefaultsFile returns the path of the defaults file of the user,
+// $XDG_CONFIG_HOME/kubebuilder/defaults.yaml or else ~/.config/kubebuilder/defaults.yaml.
+func defaultDefaultsFile() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "kubebuilder", defaultsFileName), nil
+}
+
+// loadUserDefaults reads the defaults in path, or in the defaults file of the user if path is empty, which may
+// not exist. Unknown fields are rejected.
+func loadUserDefaults(path string) (userDefaults, error) {
+	mustExist := path != ""
+	if !mustExist {
+		var err error
+		if path, err = defaultDefaultsFile(); err != nil {
+			// Without a home directory, there are no defaults
+			return userDefaults{}, nil
+		}
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !mustExist {
+		return userDefaults{}, nil
+	} else if err != nil {
+		return userDefaults{}, fmt.Errorf("unable to read the defaults file: %v", err)
+	}
+
+	var defaults userDefaults
+	if err := yaml.UnmarshalStrict(b, &defaults); err != nil {
+		return userDefaults{}, fmt.Errorf("invalid defaults file %s: %v", path, err)
+	}
+	return defaults, nil
+}
+
+// initFlags returns the defaults of the init flags, by name.
+func (d userDefaults) initFlags() map[string]string {
+	flags := make(map[string]string, len(d.Flags)+4)
+	for name, value := range d.Flags {
+		flags[name] = value
+	}
+	setFlag := func(name, value string) {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+	setFlag("domain", d.Domain)
+	setFlag("owner", d.Owner)
+	setFlag("license", d.License)
+	setFlag("base-image", d.BaseImage)
+	return flags
+}
+
+// applyInitDefaults sets the defaults of the flags of the init command cmd, the plugins bound to it, from the
+// defaults of the user. The flags the plugins do not have are skipped. It returns the names of the flags it set.
+func (c cli) applyInitDefaults(cmd *cobra.Command) ([]string, error) {
+	flags := c.userDefaults.initFlags()
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	applied := make([]string, 0, len(names))
+	for _, name := range names {
+		f := cmd.Flags().Lookup(name)
+		if f == nil {
+			c.logger.Debug("skipping the default of a flag the plugins do not have", "flag", name)
+			continue
+		}
+		if err := f.Value.Set(flags[name]); err != nil {
+			return nil, fmt.Errorf("invalid default %q of --%s: %v", flags[name], name, err)
+		}
+		// The help shows the default of the user
+		f.DefValue = f.Value.String()
+		applied = append(applied, name)
+	}
+	return applied, nil
+}
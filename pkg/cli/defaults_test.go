@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+)
+
+var _ = Describe("user defaults", func() {
+	var tmp string
+
+	BeforeEach(func() {
+		var err error
+		tmp, err = ioutil.TempDir("", "defaults")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmp)).To(Succeed())
+	})
+
+	It("should read the defaults file of the user, which may not exist", func() {
+		xdgConfigHome, isSet := os.LookupEnv("XDG_CONFIG_HOME")
+		defer func() {
+			if isSet {
+				_ = os.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
+			} else {
+				_ = os.Unsetenv("XDG_CONFIG_HOME")
+			}
+		}()
+		Expect(os.Setenv("XDG_CONFIG_HOME", tmp)).To(Succeed())
+		Expect(loadUserDefaults("")).To(Equal(userDefaults{}))
+
+		Expect(os.MkdirAll(filepath.Join(tmp, "kubebuilder"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(tmp, "kubebuilder", "defaults.yaml"),
+			[]byte("domain: example.org\nplugins: [go/v3-alpha]\n"), 0644)).To(Succeed())
+		Expect(loadUserDefaults("")).To(Equal(userDefaults{Domain: "example.org", Plugins: []string{"go/v3-alpha"}}))
+	})
+
+	It("should require the file set with --defaults-file to exist and be valid", func() {
+		path := filepath.Join(tmp, "defaults.yaml")
+		_, err := loadUserDefaults(path)
+		Expect(err).To(MatchError(ContainSubstring("unable to read the defaults file")))
+
+		Expect(ioutil.WriteFile(path, []byte("domian: example.org\n"), 0644)).To(Succeed())
+		_, err = loadUserDefaults(path)
+		Expect(err).To(MatchError(ContainSubstring("invalid defaults file " + path)))
+	})
+
+	It("should set the defaults of the init flags the plugins have", func() {
+		c := cli{
+			logger: logger.New(ioutil.Discard, logger.NormalLevel),
+			userDefaults: userDefaults{
+				Domain:    "example.org",
+				Owner:     "The Example Authors",
+				BaseImage: "alpine:3.12",
+				Flags:     map[string]string{"fetch-deps": "false", "domain": "example.com"},
+			},
+		}
+		var domain, owner string
+		var fetchDeps bool
+		cmd := &cobra.Command{Use: "init"}
+		cmd.Flags().StringVar(&domain, "domain", "my.domain", "")
+		cmd.Flags().StringVar(&owner, "owner", "", "")
+		cmd.Flags().BoolVar(&fetchDeps, "fetch-deps", true, "")
+
+		Expect(c.applyInitDefaults(cmd)).To(Equal([]string{"domain", "fetch-deps", "owner"}))
+		Expect(domain).To(Equal("example.org"))
+		Expect(owner).To(Equal("The Example Authors"))
+		Expect(fetchDeps).To(BeFalse())
+		Expect(cmd.Flags().Lookup("domain").DefValue).To(Equal("example.org"))
+
+		By("setting a flag over its default")
+		Expect(cmd.ParseFlags([]string{"--domain", "example.net"})).To(Succeed())
+		Expect(domain).To(Equal("example.net"))
+
+		By("setting an invalid default")
+		c.userDefaults = userDefaults{Flags: map[string]string{"fetch-deps": "maybe"}}
+		_, err := c.applyInitDefaults(cmd)
+		Expect(err).To(MatchError(ContainSubstring(`invalid default "maybe" of --fetch-deps`)))
+	})
+})
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli // nolint:dupl
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+func (c *cli) newDeleteAPICmd() *cobra.Command {
+	ctx := c.newDeleteAPIContext()
+	cmd := &cobra.Command{
+		Use:     "api",
+		Short:   "Delete a Kubernetes API",
+		Long:    ctx.Description,
+		Example: ctx.Examples,
+		RunE: errCmdFunc(
+			fmt.Errorf("api subcommand requires an existing project"),
+		),
+	}
+
+	// Lookup the plugin for projectVersion and bind it to the command once it runs.
+	c.bindLazily(cmd, func() { c.bindDeleteAPI(ctx, cmd) })
+	return cmd
+}
+
+func (c cli) newDeleteAPIContext() plugin.Context {
+	ctx := plugin.Context{
+		CommandName: c.commandName,
+		Logger:      c.logger,
+		GlobalFlags: c.globalFlags,
+		Description: `Delete a Kubernetes API, removing the files and code scaffolded for it.
+`,
+	}
+	if !c.configured {
+		ctx.Description = fmt.Sprintf("%s\n%s", ctx.Description, runInProjectRootMsg)
+	}
+	return ctx
+}
+
+func (c cli) bindDeleteAPI(ctx plugin.Context, cmd *cobra.Command) {
+	// Every resolved plugin supporting API deletion is chained.
+	var subcommands []plugin.GenericSubcommand
+	var keys []string
+	for _, p := range c.resolvedPlugins {
+		if getter, isGetter := p.(plugin.DeleteAPIPluginGetter); isGetter {
+			subcommands = append(subcommands, getter.GetDeleteAPIPlugin())
+			keys = append(keys, plugin.KeyFor(p))
+		}
+	}
+
+	cfg, err := config.LoadInitialized()
+	if err != nil {
+		cmdErr(cmd, err)
+		return
+	}
+
+	if len(subcommands) == 0 {
+		err := fmt.Errorf("layout plugin %q does not support an API deletion plugin", cfg.Layout)
+		cmdErr(cmd, err)
+		return
+	}
+
+	if err := bindSubcommands(&ctx, cmd, &cfg.Config, subcommands, keys); err != nil {
+		cmdErr(cmd, err)
+		return
+	}
+	cmd.Long = ctx.Description
+	cmd.Example = ctx.Examples
+	cmd.RunE = runECmdFunc(cfg, subcommands,
+		fmt.Sprintf("failed to delete API with version %q", c.projectVersion))
+}
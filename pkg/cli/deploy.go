@@ -0,0 +1,248 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+const (
+	deployCommandName   = "deploy"
+	undeployCommandName = "undeploy"
+
+	kubeconfigFlag   = "kubeconfig"
+	contextFlag      = "context"
+	namespaceFlag    = "namespace"
+	waitForReadyFlag = "wait-for-ready"
+	timeoutFlag      = "timeout"
+)
+
+// kustomizationNamespaceRegexp matches the namespace the kustomization of config/default sets
+var kustomizationNamespaceRegexp = regexp.MustCompile(`(?m)^namespace: (\S+)`)
+
+// defaultKustomization is the kustomization whose manifests are deployed
+var defaultKustomization = filepath.Join("config", "default")
+
+// deployOptions are the options of the deploy and undeploy commands.
+type deployOptions struct {
+	kubeconfig string
+	context    string
+	// namespace is the namespace to deploy the manager into, the one of the kustomization if empty
+	namespace string
+	// image is the image of the manager, the one of its manifest if empty
+	image        string
+	waitForReady bool
+	timeout      time.Duration
+	// kustomize is the kustomize binary
+	kustomize string
+}
+
+// kubectl returns the step running kubectl with args against the cluster of o.
+func (o deployOptions) kubectl(args ...string) toolStep {
+	global := make([]string, 0, 4)
+	if o.kubeconfig != "" {
+		global = append(global, "--"+kubeconfigFlag, o.kubeconfig)
+	}
+	if o.context != "" {
+		global = append(global, "--"+contextFlag, o.context)
+	}
+	return toolStep{name: "kubectl", args: append(global, args...)}
+}
+
+// bindClusterFlags binds the flags selecting the cluster, and the namespace if withNamespace is set, to cmd.
+func (o *deployOptions) bindClusterFlags(cmd *cobra.Command, withNamespace bool) {
+	cmd.Flags().StringVar(&o.kubeconfig, kubeconfigFlag, "",
+		"kubeconfig file of the cluster, defaults to the one of kubectl")
+	cmd.Flags().StringVar(&o.context, contextFlag, "", "context of the kubeconfig, defaults to the current one")
+	if withNamespace {
+		cmd.Flags().StringVar(&o.namespace, namespaceFlag, "", "namespace of the manager, "+
+			"set in config/default/kustomization.yaml, defaults to the one already set in it")
+	}
+}
+
+func (c cli) newDeployCmd() *cobra.Command {
+	opts := deployOptions{}
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   deployCommandName,
+		Short: "Deploy the manager and its manifests to a cluster",
+		Long: `Deploy the manager and its manifests, CRDs, RBAC and webhooks included, to the cluster of the current
+kubectl context, the way the deploy target of the Makefile does: the manifests of config/default are rendered by
+kustomize and applied with kubectl.
+
+The image of the manager is set to --image and its namespace to --namespace, in the kustomizations of
+config/manager and config/default. Without them, the image and the namespace already set are deployed, e.g. the
+image set by the build command. With --wait-for-ready, deploy waits for the manager to be available.
+
+The kustomize binary built by the Makefile into bin/ is used if there is one. With --dry-run, the commands are
+printed instead of run.
+`,
+		Example: fmt.Sprintf(`  # Deploy the project to the cluster of the current kubectl context
+  %[1]s %[2]s
+
+  # Deploy an image into the operators namespace of the kind-dev context, waiting for the manager to be available
+  %[1]s %[2]s --%[3]s quay.io/acme/operator:v0.1.0 --%[4]s kind-dev --%[5]s operators --%[6]s
+`, c.commandName, deployCommandName, imageFlag, contextFlag, namespaceFlag, waitForReadyFlag),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			project, kustomization, err := c.loadDeployedProject()
+			if err != nil {
+				return err
+			}
+			opts.kustomize = kustomizeBinary()
+
+			steps, err := deploySteps(project.Config, kustomization, opts)
+			if err != nil {
+				return err
+			}
+			return runSteps(cmd.OutOrStdout(), cmd.ErrOrStderr(), steps, dryRun)
+		},
+	}
+	opts.bindClusterFlags(cmd, true)
+	cmd.Flags().StringVar(&opts.image, imageFlag, "", "image of the manager, "+
+		"set in config/manager/kustomization.yaml, defaults to the one already set in it")
+	cmd.Flags().BoolVar(&opts.waitForReady, waitForReadyFlag, false, "wait for the manager to be available")
+	cmd.Flags().DurationVar(&opts.timeout, timeoutFlag, 2*time.Minute,
+		fmt.Sprintf("how long to wait for the manager to be available with --%s", waitForReadyFlag))
+	cmd.Flags().BoolVar(&dryRun, dryRunFlag, false, "print the commands instead of running them")
+	return cmd
+}
+
+func (c cli) newUndeployCmd() *cobra.Command {
+	opts := deployOptions{}
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   undeployCommandName,
+		Short: "Delete the manager and its manifests from a cluster",
+		Long: `Delete the manager and its manifests, CRDs included, and so the objects of its APIs, from the cluster of
+the current kubectl context, the way the undeploy target of the Makefile does: the manifests of config/default
+are rendered by kustomize and deleted with kubectl. The manifests that are not found are skipped.
+
+The kustomize binary built by the Makefile into bin/ is used if there is one. With --dry-run, the commands are
+printed instead of run.
+`,
+		Example: fmt.Sprintf(`  # Delete the project from the cluster of the current kubectl context
+  %[1]s %[2]s
+
+  # Delete the project deployed into the operators namespace of the kind-dev context
+  %[1]s %[2]s --%[3]s kind-dev --%[4]s operators
+`, c.commandName, undeployCommandName, contextFlag, namespaceFlag),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if _, _, err := c.loadDeployedProject(); err != nil {
+				return err
+			}
+			opts.kustomize = kustomizeBinary()
+
+			steps, err := undeploySteps(opts)
+			if err != nil {
+				return err
+			}
+			return runSteps(cmd.OutOrStdout(), cmd.ErrOrStderr(), steps, dryRun)
+		},
+	}
+	opts.bindClusterFlags(cmd, true)
+	cmd.Flags().BoolVar(&dryRun, dryRunFlag, false, "print the commands instead of running them")
+	return cmd
+}
+
+// loadDeployedProject returns the config of the project and its config/default kustomization.
+func (c cli) loadDeployedProject() (*internalconfig.Config, string, error) {
+	if !c.configured {
+		return nil, "", errors.New("unable to find configuration file, project must be initialized")
+	}
+	project, err := internalconfig.Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read config: %v", err)
+	}
+	kustomization, err := ioutil.ReadFile(filepath.Join(defaultKustomization, "kustomization.yaml"))
+	if os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("the project has no kustomization in %s to deploy", defaultKustomization)
+	} else if err != nil {
+		return nil, "", err
+	}
+	return project, string(kustomization), nil
+}
+
+// deploySteps returns the steps deploying the project of cfg, whose config/default kustomization is passed.
+func deploySteps(cfg config.Config, kustomization string, opts deployOptions) ([]toolStep, error) {
+	steps := make([]toolStep, 0, 5)
+	if opts.image != "" {
+		if err := config.ValidateImage(opts.image); err != nil {
+			return nil, err
+		}
+		steps = append(steps, setImageStep(cfg, opts.kustomize, opts.image))
+	}
+	namespaceSteps, err := setNamespaceSteps(opts)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, namespaceSteps...)
+	steps = append(steps, toolStep{name: opts.kustomize, args: []string{"build", defaultKustomization}, pipe: true},
+		opts.kubectl("apply", "-f", "-"))
+
+	if opts.waitForReady {
+		namespace := opts.namespace
+		if namespace == "" {
+			m := kustomizationNamespaceRegexp.FindStringSubmatch(kustomization)
+			if m == nil {
+				return nil, fmt.Errorf("unable to find the namespace of the manager in %s, set --%s",
+					defaultKustomization, namespaceFlag)
+			}
+			namespace = m[1]
+		}
+		steps = append(steps, opts.kubectl("wait", "--for=condition=Available", "deployment",
+			"--selector=control-plane=controller-manager", "--namespace", namespace,
+			"--timeout", opts.timeout.String()))
+	}
+	return steps, nil
+}
+
+// undeploySteps returns the steps deleting the project from the cluster.
+func undeploySteps(opts deployOptions) ([]toolStep, error) {
+	steps, err := setNamespaceSteps(opts)
+	if err != nil {
+		return nil, err
+	}
+	return append(steps,
+		toolStep{name: opts.kustomize, args: []string{"build", defaultKustomization}, pipe: true},
+		opts.kubectl("delete", "--ignore-not-found", "-f", "-")), nil
+}
+
+// setNamespaceSteps returns the step setting the namespace of the manifests to the one of opts, if it is set.
+func setNamespaceSteps(opts deployOptions) ([]toolStep, error) {
+	if opts.namespace == "" {
+		return nil, nil
+	}
+	if err := validation.IsDNS1123Label(opts.namespace); err != nil {
+		return nil, fmt.Errorf("namespace %q is invalid: %v", opts.namespace, err)
+	}
+	return []toolStep{{dir: defaultKustomization, name: opts.kustomize,
+		args: []string{"edit", "set", "namespace", opts.namespace}}}, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+var _ = Describe("deploy", func() {
+	const kustomization = "# Adds namespace to all resources.\nnamespace: project-system\n\nnamePrefix: project-\n"
+
+	var (
+		cfg  config.Config
+		opts deployOptions
+	)
+
+	BeforeEach(func() {
+		cfg = config.Config{Version: config.Version3Alpha, Domain: "my.domain", Repo: "example.com/project"}
+		opts = deployOptions{timeout: 2 * time.Minute, kustomize: "kustomize"}
+	})
+
+	It("should apply the manifests of config/default", func() {
+		steps, err := deploySteps(cfg, kustomization, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(formatSteps(steps)).To(Equal([]string{"kustomize build config/default | kubectl apply -f -"}))
+	})
+
+	It("should set the image and the namespace, and wait for the manager to be available", func() {
+		opts.kubeconfig = "/tmp/kubeconfig"
+		opts.context = "kind-dev"
+		opts.namespace = "operators"
+		opts.image = "quay.io/acme/operator:v0.1.0"
+		opts.waitForReady = true
+		steps, err := deploySteps(cfg, kustomization, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(formatSteps(steps)).To(Equal([]string{
+			"cd config/manager && kustomize edit set image controller=quay.io/acme/operator:v0.1.0",
+			"cd config/default && kustomize edit set namespace operators",
+			"kustomize build config/default | kubectl --kubeconfig /tmp/kubeconfig --context kind-dev apply -f -",
+			"kubectl --kubeconfig /tmp/kubeconfig --context kind-dev wait --for=condition=Available deployment " +
+				"--selector=control-plane=controller-manager --namespace operators --timeout 2m0s",
+		}))
+	})
+
+	It("should wait for the manager in the namespace of the kustomization", func() {
+		opts.waitForReady = true
+		steps, err := deploySteps(cfg, kustomization, opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(steps[len(steps)-1].args).To(ContainElement("project-system"))
+
+		_, err = deploySteps(cfg, "namePrefix: project-\n", opts)
+		Expect(err).To(MatchError(
+			"unable to find the namespace of the manager in config/default, set --namespace"))
+	})
+
+	It("should fail for an invalid image or namespace", func() {
+		opts.image = "Operator"
+		_, err := deploySteps(cfg, kustomization, opts)
+		Expect(err).To(MatchError(ContainSubstring(`invalid image "Operator"`)))
+
+		opts.image = ""
+		opts.namespace = "Operators"
+		_, err = deploySteps(cfg, kustomization, opts)
+		Expect(err).To(MatchError(ContainSubstring(`namespace "Operators" is invalid`)))
+	})
+
+	It("should delete the manifests of config/default", func() {
+		opts.context = "kind-dev"
+		opts.namespace = "operators"
+		steps, err := undeploySteps(opts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(formatSteps(steps)).To(Equal([]string{
+			"cd config/default && kustomize edit set namespace operators",
+			"kustomize build config/default | kubectl --context kind-dev delete --ignore-not-found -f -",
+		}))
+	})
+})
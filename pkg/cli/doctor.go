@@ -0,0 +1,382 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/tools"
+)
+
+const (
+	fixFlag = "fix"
+
+	// minGoVersion is the Go version required when the project does not declare one in its go.mod.
+	minGoVersion = "1.13"
+)
+
+var (
+	goVersionRegexp     = regexp.MustCompile(`go([0-9]+)\.([0-9]+)`)
+	goModGoRegexp       = regexp.MustCompile(`(?m)^go ([0-9]+\.[0-9]+)`)
+	goModModuleRegexp   = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+	semverRegexp        = regexp.MustCompile(`v[0-9]+\.[0-9]+\.[0-9]+[0-9A-Za-z.-]*`)
+	controllerGenRegexp = regexp.MustCompile(`controller-gen@(v[0-9][^\s;]*)`)
+	kustomizeRegexp     = regexp.MustCompile(`kustomize/v[0-9]+@(v[0-9][^\s;]*)|KUSTOMIZE_VERSION \?= (v\S+)`)
+	// The following regexps match the versions required by the go.mod of hack/tools
+	controllerToolsRequireRegexp = regexp.MustCompile(`sigs\.k8s\.io/controller-tools (v\S+)`)
+	kustomizeRequireRegexp       = regexp.MustCompile(`sigs\.k8s\.io/kustomize/kustomize/v[0-9]+ (v\S+)`)
+)
+
+// toolsGoMod is the go.mod pinning the versions of the tools the Makefile builds into localBin
+var toolsGoMod = filepath.Join("hack", "tools", "go.mod")
+
+// localBin is the directory the Makefile installs tools into
+const localBin = "bin"
+
+// checkStatus is the outcome of a doctor check.
+type checkStatus string
+
+const (
+	checkPassed  checkStatus = "PASS"
+	checkFailed  checkStatus = "FAIL"
+	checkSkipped checkStatus = "SKIP"
+)
+
+// checkResult is the result of a doctor check.
+type checkResult struct {
+	name    string
+	status  checkStatus
+	message string
+	// hint explains how to fix a failed check.
+	hint string
+	// fix applies a safe fix for a failed check, nil if there is none.
+	fix func() error
+}
+
+// doctor checks the environment and the project in the current directory.
+type doctor struct {
+	// output runs a tool and returns its standard output.
+	output func(name string, args ...string) (string, error)
+	// lookPath finds the binary of a tool.
+	lookPath func(name string) (string, error)
+	// readFile reads a file of the project.
+	readFile func(path string) ([]byte, error)
+	// project is the project configuration, nil outside of a project.
+	project *internalconfig.Config
+}
+
+func (c cli) newDoctorCmd() *cobra.Command {
+	var fix bool
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that the environment can build and run the project",
+		Long: `Check that the environment can build and run the project: the Go version, a container tool to build
+images, the controller-gen and kustomize versions the Makefile expects and the consistency of the PROJECT file.
+The tools built by the Makefile into bin/ are checked before the ones found in the PATH.
+
+Each check is written with its result and, if it failed, a hint on how to fix it. With --fix, safe fixes
+are applied: missing tools are installed at the versions pinned in the Makefile or hack/tools/go.mod and the
+PROJECT file is fixed.
+`,
+		Example: fmt.Sprintf(`  # Check the environment and the project
+  %[1]s doctor
+
+  # Check the environment and the project, applying safe fixes
+  %[1]s doctor --fix
+`, c.commandName),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			d := newDoctor()
+			if c.configured {
+				project, err := internalconfig.Load()
+				if err != nil {
+					return fmt.Errorf("failed to read config: %v", err)
+				}
+				d.project = project
+			}
+			return runChecks(cmd.OutOrStdout(), d.checks(), fix)
+		},
+	}
+	cmd.Flags().BoolVar(&fix, fixFlag, false, "apply safe fixes for the failed checks")
+	return cmd
+}
+
+// newDoctor returns a doctor that runs tools in the tools environment set by the cli.
+func newDoctor() doctor {
+	return doctor{
+		output: func(name string, args ...string) (string, error) {
+			out, err := tools.Default().Command(name, args...).Output()
+			return string(out), err
+		},
+		lookPath: func(name string) (string, error) {
+			if path, found := tools.Default().Paths[name]; found {
+				return exec.LookPath(path)
+			}
+			return exec.LookPath(name)
+		},
+		readFile: ioutil.ReadFile,
+	}
+}
+
+// runChecks writes the result of each check to out, applying the fixes of failed checks if fix is set.
+// An error is returned if any check failed and was not fixed.
+func runChecks(out io.Writer, results []checkResult, fix bool) error {
+	failed := 0
+	for _, r := range results {
+		_, _ = fmt.Fprintf(out, "[%s] %s: %s\n", r.status, r.name, r.message)
+		if r.status != checkFailed {
+			continue
+		}
+		if fix && r.fix != nil {
+			if err := r.fix(); err != nil {
+				_, _ = fmt.Fprintf(out, "       fix failed: %v\n", err)
+			} else {
+				_, _ = fmt.Fprintln(out, "       fixed")
+				continue
+			}
+		}
+		if r.hint != "" {
+			_, _ = fmt.Fprintf(out, "       hint: %s\n", r.hint)
+		}
+		failed++
+	}
+	if failed != 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// checks runs every check.
+func (d doctor) checks() []checkResult {
+	makefile, _ := d.readFile("Makefile")
+	tools, _ := d.readFile(toolsGoMod)
+	// The Makefile pins the version of a tool it installs itself, which prevails over hack/tools
+	pinned := func(makefileRe, toolsRe *regexp.Regexp) string {
+		if version := versionPinnedIn(makefile, makefileRe); version != "" {
+			return version
+		}
+		return versionPinnedIn(tools, toolsRe)
+	}
+	return []checkResult{
+		d.checkGoVersion(),
+		d.checkContainerTool(),
+		d.checkTool("controller-gen", pinned(controllerGenRegexp, controllerToolsRequireRegexp), "--version"),
+		d.checkTool("kustomize", pinned(kustomizeRegexp, kustomizeRequireRegexp), "version"),
+		d.checkProject(),
+	}
+}
+
+// goModPath returns the path of the go.mod of the project, the one of its module if it is in a subdirectory.
+func (d doctor) goModPath() string {
+	if d.project == nil || d.project.BasePath == "" {
+		return "go.mod"
+	}
+	parents := strings.Repeat(".."+string(filepath.Separator), strings.Count(d.project.BasePath, "/")+1)
+	return filepath.Join(parents, "go.mod")
+}
+
+// checkGoVersion checks that the Go version is at least the one declared in the go.mod.
+func (d doctor) checkGoVersion() checkResult {
+	r := checkResult{name: "go"}
+	required := minGoVersion
+	if goMod, err := d.readFile(d.goModPath()); err == nil {
+		if m := goModGoRegexp.FindSubmatch(goMod); m != nil {
+			required = string(m[1])
+		}
+	}
+
+	out, err := d.output("go", "version")
+	if err != nil {
+		r.status, r.message = checkFailed, fmt.Sprintf("unable to run 'go version': %v", err)
+		r.hint = "install Go " + required + " or newer, see https://golang.org/doc/install"
+		return r
+	}
+	out = strings.TrimSpace(out)
+	m := goVersionRegexp.FindStringSubmatch(out)
+	if m == nil {
+		r.status, r.message = checkPassed, fmt.Sprintf("%q is not a release, assuming it is recent enough", out)
+		return r
+	}
+	found := m[1] + "." + m[2]
+	if compareGoVersions(found, required) < 0 {
+		r.status, r.message = checkFailed, fmt.Sprintf("go %s found, go %s or newer is required", found, required)
+		r.hint = "install Go " + required + " or newer, see https://golang.org/doc/install"
+		return r
+	}
+	r.status, r.message = checkPassed, fmt.Sprintf("go %s found, go %s or newer is required", found, required)
+	return r
+}
+
+// checkContainerTool checks that docker or podman is available to build the manager image.
+func (d doctor) checkContainerTool() checkResult {
+	r := checkResult{name: "container tool"}
+	for _, name := range []string{"docker", "podman"} {
+		if path, err := d.lookPath(name); err == nil {
+			r.status, r.message = checkPassed, fmt.Sprintf("%s found at %s", name, path)
+			return r
+		}
+	}
+	r.status, r.message = checkFailed, "neither docker nor podman found"
+	r.hint = "install docker or podman to build the manager image with 'make docker-build'"
+	return r
+}
+
+// checkTool checks that the tool name is installed at the expected version, which is printed when running
+// it with versionArgs. The binary installed by the Makefile in bin/ is preferred over the one found in the PATH.
+func (d doctor) checkTool(name, expected string, versionArgs ...string) checkResult {
+	r := checkResult{name: name}
+	if expected == "" {
+		r.status, r.message = checkSkipped, "no version pinned in the Makefile or "+toolsGoMod
+		return r
+	}
+
+	path, err := d.lookPath(filepath.Join(localBin, name))
+	if err != nil {
+		path, err = d.lookPath(name)
+	}
+	if err != nil {
+		r.status, r.message = checkFailed, fmt.Sprintf("not found, the Makefile expects %s", expected)
+		r.hint = fmt.Sprintf("run 'make %s' to install it", name)
+		r.fix = func() error {
+			_, err := d.output("make", name)
+			return err
+		}
+		return r
+	}
+
+	out, err := d.output(path, versionArgs...)
+	found := semverRegexp.FindString(out)
+	if err != nil || found == "" {
+		r.status, r.message = checkFailed, fmt.Sprintf("unable to get the version of %s", path)
+		r.hint = fmt.Sprintf("install %s %s", name, expected)
+		return r
+	}
+	if found != expected {
+		r.status, r.message = checkFailed, fmt.Sprintf("%s found at %s, the Makefile expects %s", found, path, expected)
+		r.hint = fmt.Sprintf("remove %s and run 'make %s' to install %s", path, name, expected)
+		return r
+	}
+	r.status, r.message = checkPassed, fmt.Sprintf("%s found at %s", found, path)
+	return r
+}
+
+// checkProject checks that the PROJECT file is consistent with the go.mod and does not track any
+// resource twice.
+func (d doctor) checkProject() checkResult {
+	r := checkResult{name: "PROJECT"}
+	if d.project == nil {
+		r.status, r.message = checkSkipped, "not in the root directory of a project"
+		return r
+	}
+
+	var problems, fixes []string
+	fixed := d.project.Config
+	if goMod, err := d.readFile(d.goModPath()); err == nil {
+		// The repo of a project in a subdirectory of its module is the package of that subdirectory
+		if m := goModModuleRegexp.FindSubmatch(goMod); m != nil {
+			modulePath := string(m[1])
+			repo := path.Join(modulePath, fixed.BasePath)
+			switch {
+			case repo == fixed.Repo:
+			case fixed.BasePath == "":
+				problems = append(problems, fmt.Sprintf("repo %q does not match module path %q of the go.mod",
+					fixed.Repo, modulePath))
+				fixes = append(fixes, "set repo to the module path")
+			default:
+				problems = append(problems, fmt.Sprintf("repo %q does not match package %q of the project in "+
+					"module %q", fixed.Repo, repo, modulePath))
+				fixes = append(fixes, "set repo to the package of the project")
+			}
+			fixed.Repo = repo
+		}
+	}
+	if resources := uniqueResources(fixed.Resources); len(resources) != len(fixed.Resources) {
+		problems = append(problems, "resources are tracked more than once")
+		fixes = append(fixes, "remove the duplicated resources")
+		fixed.Resources = resources
+	}
+
+	if len(problems) == 0 {
+		r.status, r.message = checkPassed, "consistent with the project"
+		return r
+	}
+	r.status, r.message = checkFailed, strings.Join(problems, ", ")
+	r.hint = fmt.Sprintf("%s, or run with --%s to do it", strings.Join(fixes, " and "), fixFlag)
+	r.fix = func() error {
+		d.project.Config = fixed
+		return d.project.Save()
+	}
+	return r
+}
+
+// versionPinnedIn returns the version captured by the first matching group of re in content, or an empty
+// string if re does not match.
+func versionPinnedIn(content []byte, re *regexp.Regexp) string {
+	if m := re.FindSubmatch(content); m != nil {
+		for _, version := range m[1:] {
+			if len(version) != 0 {
+				return string(version)
+			}
+		}
+	}
+	return ""
+}
+
+// uniqueResources returns resources without the ones with the same group, version and kind as a previous one.
+func uniqueResources(resources []config.GVK) []config.GVK {
+	unique := make([]config.GVK, 0, len(resources))
+	seen := make(map[string]bool, len(resources))
+	for _, r := range resources {
+		key := r.Group + "/" + r.Version + "/" + r.Kind
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, r)
+		}
+	}
+	return unique
+}
+
+// compareGoVersions compares two "major.minor" Go versions, returning a negative number if a is lower than b,
+// zero if they are equal and a positive number otherwise.
+func compareGoVersions(a, b string) int {
+	as, bs := strings.SplitN(a, ".", 2), strings.SplitN(b, ".", 2)
+	for i := 0; i < 2; i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+var _ = Describe("doctor", func() {
+	var (
+		d        doctor
+		files    map[string]string
+		binaries map[string]string
+		outputs  map[string]string
+		ran      []string
+	)
+
+	BeforeEach(func() {
+		files = map[string]string{
+			"go.mod": "module example.com/project\n\ngo 1.13\n",
+			"Makefile": "\tgo get sigs.k8s.io/controller-tools/cmd/controller-gen@v0.3.0 ;\\\n" +
+				"\tgo get sigs.k8s.io/kustomize/kustomize/v3@v3.5.4 ;\\\n",
+		}
+		binaries = map[string]string{
+			"go":             "/usr/bin/go",
+			"docker":         "/usr/bin/docker",
+			"controller-gen": "/go/bin/controller-gen",
+			"kustomize":      "/go/bin/kustomize",
+		}
+		outputs = map[string]string{
+			"go version":                       "go version go1.15.2 linux/amd64\n",
+			"/go/bin/controller-gen --version": "Version: v0.3.0\n",
+			"/go/bin/kustomize version":        "{Version:kustomize/v3.5.4 GitCommit:3af514fa9 BuildDate:2020-01-11}\n",
+		}
+		ran = nil
+
+		d = doctor{
+			output: func(name string, args ...string) (string, error) {
+				command := strings.Join(append([]string{name}, args...), " ")
+				ran = append(ran, command)
+				if out, found := outputs[command]; found {
+					return out, nil
+				}
+				return "", errors.New("exit status 1")
+			},
+			lookPath: func(name string) (string, error) {
+				if path, found := binaries[name]; found {
+					return path, nil
+				}
+				return "", errors.New("not found")
+			},
+			readFile: func(path string) ([]byte, error) {
+				if content, found := files[path]; found {
+					return []byte(content), nil
+				}
+				return nil, os.ErrNotExist
+			},
+		}
+	})
+
+	statuses := func(results []checkResult) map[string]checkStatus {
+		s := make(map[string]checkStatus, len(results))
+		for _, r := range results {
+			s[r.name] = r.status
+		}
+		return s
+	}
+
+	It("should pass in a healthy environment", func() {
+		Expect(statuses(d.checks())).To(Equal(map[string]checkStatus{
+			"go":             checkPassed,
+			"container tool": checkPassed,
+			"controller-gen": checkPassed,
+			"kustomize":      checkPassed,
+			"PROJECT":        checkSkipped,
+		}))
+	})
+
+	It("should fail if go is older than the version of the go.mod", func() {
+		files["go.mod"] = "module example.com/project\n\ngo 1.16\n"
+		r := d.checkGoVersion()
+		Expect(r.status).To(Equal(checkFailed))
+		Expect(r.message).To(Equal("go 1.15 found, go 1.16 or newer is required"))
+	})
+
+	It("should accept podman as container tool", func() {
+		delete(binaries, "docker")
+		binaries["podman"] = "/usr/bin/podman"
+		Expect(d.checkContainerTool().status).To(Equal(checkPassed))
+
+		delete(binaries, "podman")
+		Expect(d.checkContainerTool().status).To(Equal(checkFailed))
+	})
+
+	It("should fail if a tool does not have the version pinned in the Makefile", func() {
+		outputs["/go/bin/controller-gen --version"] = "Version: v0.2.5\n"
+		r := d.checks()[2]
+		Expect(r.status).To(Equal(checkFailed))
+		Expect(r.message).To(Equal("v0.2.5 found at /go/bin/controller-gen, the Makefile expects v0.3.0"))
+		Expect(r.fix).To(BeNil())
+	})
+
+	It("should check the tools built into bin/ at the versions pinned in hack/tools", func() {
+		files["Makefile"] = "CONTROLLER_GEN = $(LOCALBIN)/controller-gen\nKUSTOMIZE = $(LOCALBIN)/kustomize\n"
+		files[filepath.Join("hack", "tools", "go.mod")] = "module example.com/project/hack/tools\n\n" +
+			"require (\n\tsigs.k8s.io/controller-tools v0.4.1\n\tsigs.k8s.io/kustomize/kustomize/v3 v3.5.4\n)\n"
+		binaries[filepath.Join("bin", "controller-gen")] = "bin/controller-gen"
+		outputs["bin/controller-gen --version"] = "Version: v0.4.1\n"
+
+		results := d.checks()
+		Expect(results[2].status).To(Equal(checkPassed))
+		Expect(results[2].message).To(Equal("v0.4.1 found at bin/controller-gen"))
+		Expect(results[3].status).To(Equal(checkPassed))
+		Expect(results[3].message).To(Equal("v3.5.4 found at /go/bin/kustomize"))
+	})
+
+	It("should install missing tools with make when fixing", func() {
+		delete(binaries, "kustomize")
+		outputs["make kustomize"] = ""
+		out := &bytes.Buffer{}
+		Expect(runChecks(out, d.checks(), true)).To(Succeed())
+		Expect(ran).To(ContainElement("make kustomize"))
+		Expect(out.String()).To(ContainSubstring("[FAIL] kustomize: not found, the Makefile expects v3.5.4\n" +
+			"       fixed\n"))
+	})
+
+	It("should report the failed checks with hints", func() {
+		delete(binaries, "docker")
+		out := &bytes.Buffer{}
+		Expect(runChecks(out, d.checks(), false)).To(MatchError("1 check(s) failed"))
+		Expect(out.String()).To(ContainSubstring("[FAIL] container tool: neither docker nor podman found\n" +
+			"       hint: install docker or podman"))
+	})
+
+	Context("in a project", func() {
+		var dir, wd string
+
+		BeforeEach(func() {
+			var err error
+			wd, err = os.Getwd()
+			Expect(err).NotTo(HaveOccurred())
+			dir, err = ioutil.TempDir("", "doctor")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Chdir(dir)).To(Succeed())
+
+			project := internalconfig.New(internalconfig.DefaultPath)
+			project.Version = config.Version3Alpha
+			project.Repo = "example.com/other"
+			project.Resources = []config.GVK{
+				{Group: "crew", Version: "v1", Kind: "Captain"},
+				{Group: "crew", Version: "v1", Kind: "Captain"},
+			}
+			Expect(project.Save()).To(Succeed())
+			d.project, err = internalconfig.Load()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.Chdir(wd)).To(Succeed())
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should fix the repo and the duplicated resources", func() {
+			r := d.checkProject()
+			Expect(r.status).To(Equal(checkFailed))
+			Expect(r.message).To(Equal(`repo "example.com/other" does not match module path ` +
+				`"example.com/project" of the go.mod, resources are tracked more than once`))
+			Expect(r.fix()).To(Succeed())
+
+			project, err := internalconfig.Load()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(project.Repo).To(Equal("example.com/project"))
+			Expect(project.Resources).To(Equal([]config.GVK{{Group: "crew", Version: "v1", Kind: "Captain"}}))
+		})
+
+		It("should check the repo against the go.mod of the module of a project in a subdirectory", func() {
+			d.project.BasePath = "operators/captain"
+			d.project.Resources = d.project.Resources[:1]
+			files[filepath.Join("..", "..", "go.mod")] = "module example.com/monorepo\n\ngo 1.13\n"
+
+			r := d.checkProject()
+			Expect(r.status).To(Equal(checkFailed))
+			Expect(r.message).To(Equal(`repo "example.com/other" does not match package ` +
+				`"example.com/monorepo/operators/captain" of the project in module "example.com/monorepo"`))
+
+			d.project.Repo = "example.com/monorepo/operators/captain"
+			Expect(d.checkProject().status).To(Equal(checkPassed))
+		})
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+// projectFileDescription describes the config file, which is written by the cli rather than by plugins.
+const projectFileDescription = "project configuration: domain, repository, layout plugins and scaffolded resources"
+
+func (c cli) newExplainCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain [path]",
+		Short: "Describe what the scaffolded files and directories are for",
+		Long: `Describe what the files and directories scaffolded by the plugins of the project are for.
+
+Without arguments every described path is listed. If a path is passed, the paths under it are listed,
+or the closest described directory containing it if there is none.
+`,
+		Example: fmt.Sprintf(`  # Describe the whole project layout
+  %[1]s explain
+
+  # Describe the kustomize manifests
+  %[1]s explain config
+
+  # Describe the directory a file belongs to
+  %[1]s explain config/rbac/role.yaml
+`, c.commandName),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := internalconfig.LoadInitialized()
+			if err != nil {
+				return err
+			}
+			layout := describeLayout(&cfg.Config, c.resolvedPlugins...)
+			if len(args) == 0 {
+				return writeLayout(cmd.OutOrStdout(), layout)
+			}
+			explained, err := explainPath(layout, args[0])
+			if err != nil {
+				return err
+			}
+			return writeLayout(cmd.OutOrStdout(), explained)
+		},
+	}
+}
+
+// describeLayout merges the layout descriptions of plugins, in order, so chained plugins can override the
+// descriptions of the previous ones.
+func describeLayout(c *config.Config, plugins ...plugin.Base) map[string]string {
+	layout := map[string]string{internalconfig.DefaultPath: projectFileDescription}
+	for _, p := range plugins {
+		if describer, isDescriber := p.(plugin.LayoutDescriber); isDescriber {
+			for scaffoldedPath, description := range describer.DescribeLayout(c) {
+				layout[scaffoldedPath] = description
+			}
+		}
+	}
+	return layout
+}
+
+// explainPath returns the descriptions of layout for target and the paths under it or, if there are none,
+// for the closest directory containing target.
+func explainPath(layout map[string]string, target string) (map[string]string, error) {
+	target = path.Clean(filepath.ToSlash(target))
+
+	explained := make(map[string]string)
+	for p, description := range layout {
+		if target == "." || p == target || strings.HasPrefix(p, target+"/") {
+			explained[p] = description
+		}
+	}
+	if len(explained) != 0 {
+		return explained, nil
+	}
+
+	for dir := path.Dir(target); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if description, found := layout[dir]; found {
+			return map[string]string{dir: description}, nil
+		}
+	}
+	return nil, fmt.Errorf("%q is not described by the plugins of this project", target)
+}
+
+// writeLayout writes the paths and descriptions of layout to out, sorted by path.
+func writeLayout(out io.Writer, layout map[string]string) error {
+	paths := make([]string, 0, len(layout))
+	for p := range layout {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, p := range paths {
+		_, _ = fmt.Fprintf(w, "%s:\t%s\n", p, layout[p])
+	}
+	return w.Flush()
+}
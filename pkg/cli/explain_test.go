@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+var _ plugin.LayoutDescriber = mockLayoutPlugin{}
+
+type mockLayoutPlugin struct {
+	mockPlugin
+	layout map[string]string
+}
+
+func (p mockLayoutPlugin) DescribeLayout(*config.Config) map[string]string { return p.layout }
+
+var _ = Describe("explain", func() {
+	var layout map[string]string
+
+	BeforeEach(func() {
+		base := mockLayoutPlugin{
+			mockPlugin: makeBasePlugin("go.example.com", "v1", config.Version3Alpha).(mockPlugin),
+			layout:     map[string]string{"main.go": "entrypoint", "config": "manifests"},
+		}
+		kustomize := mockLayoutPlugin{
+			mockPlugin: makeBasePlugin("kustomize.example.com", "v1", config.Version3Alpha).(mockPlugin),
+			layout:     map[string]string{"config": "kustomize manifests", "config/rbac": "roles"},
+		}
+		layout = describeLayout(&config.Config{}, base, makeBasePlugin("other.example.com", "v1"), kustomize)
+	})
+
+	It("should merge the layouts of the plugins in order", func() {
+		Expect(layout).To(Equal(map[string]string{
+			"PROJECT":     projectFileDescription,
+			"main.go":     "entrypoint",
+			"config":      "kustomize manifests",
+			"config/rbac": "roles",
+		}))
+	})
+
+	It("should explain a path and the paths under it", func() {
+		Expect(explainPath(layout, "config/")).To(Equal(map[string]string{
+			"config":      "kustomize manifests",
+			"config/rbac": "roles",
+		}))
+		Expect(explainPath(layout, ".")).To(Equal(layout))
+	})
+
+	It("should explain the closest described directory", func() {
+		Expect(explainPath(layout, "config/rbac/role.yaml")).To(Equal(map[string]string{"config/rbac": "roles"}))
+		Expect(explainPath(layout, "config/crd/bases")).To(Equal(map[string]string{"config": "kustomize manifests"}))
+	})
+
+	It("should fail for paths that are not described", func() {
+		_, err := explainPath(layout, "bin/manager")
+		Expect(err).To(MatchError(`"bin/manager" is not described by the plugins of this project`))
+	})
+
+	It("should write the layout sorted by path", func() {
+		out := &bytes.Buffer{}
+		Expect(writeLayout(out, map[string]string{"main.go": "entrypoint", "config": "manifests"})).To(Succeed())
+		Expect(out.String()).To(Equal("config:   manifests\nmain.go:  entrypoint\n"))
+	})
+})
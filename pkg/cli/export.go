@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+)
+
+const (
+	exportCommandName = "export"
+
+	outputFormatYAML = "yaml"
+)
+
+// projectModel is the resolved model of a project written by 'alpha export'.
+type projectModel struct {
+	// Config is the content of the PROJECT file.
+	Config config.Config `json:"config"`
+	// Layout is the project layout the plugins were resolved from.
+	Layout string `json:"layout"`
+	// Plugins lists the keys of the plugins the commands run on the project, bundles expanded, in order.
+	Plugins []string `json:"plugins"`
+	// Paths are the directories of the project, omitted if they do not exist.
+	Paths projectPaths `json:"paths"`
+	// Resources are the resources tracked in the PROJECT file.
+	Resources []resourceModel `json:"resources,omitempty"`
+}
+
+// projectPaths are the directories of a project.
+type projectPaths struct {
+	Main        string `json:"main,omitempty"`
+	APIs        string `json:"apis,omitempty"`
+	Controllers string `json:"controllers,omitempty"`
+	Manifests   string `json:"manifests,omitempty"`
+	CRDs        string `json:"crds,omitempty"`
+	Samples     string `json:"samples,omitempty"`
+}
+
+// resourceModel is a resource tracked in the PROJECT file, with the values derived from it.
+type resourceModel struct {
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	// Plural is the resource name of the Kind, either recorded in the PROJECT file or computed from the Kind.
+	Plural string `json:"plural"`
+	// Domain is the fully-qualified API group of the resource.
+	Domain string `json:"domain"`
+	// Package is the Go package of the types of the resource.
+	Package        string `json:"package"`
+	CRDVersion     string `json:"crdVersion,omitempty"`
+	WebhookVersion string `json:"webhookVersion,omitempty"`
+	Defaults       string `json:"defaults,omitempty"`
+	Hub            bool   `json:"hub,omitempty"`
+	// Files are the files of the resource, omitted if they do not exist.
+	Files resourceFiles `json:"files"`
+}
+
+// resourceFiles are the files scaffolded or generated for a resource.
+type resourceFiles struct {
+	Types      string `json:"types,omitempty"`
+	Controller string `json:"controller,omitempty"`
+	Webhook    string `json:"webhook,omitempty"`
+	CRD        string `json:"crd,omitempty"`
+	Sample     string `json:"sample,omitempty"`
+}
+
+func (c cli) newExportCmd() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   exportCommandName,
+		Short: "Write the resolved model of the project",
+		Long: `Write the resolved model of the project for external tools, e.g. catalog generators or developer
+portals: the content of the PROJECT file, the plugins its commands run, the directories of the project and,
+for each resource, its plural, fully-qualified group, Go package and the files scaffolded or generated for it.
+
+Directories and files that do not exist are omitted, so the model describes the project as it is.
+`,
+		Example: fmt.Sprintf(`  # Write the model of the project in YAML
+  %[1]s alpha %[2]s
+
+  # Write the model of the project in JSON
+  %[1]s alpha %[2]s --%[3]s %[4]s
+`, c.commandName, exportCommandName, outputFlag, outputFormatJSON),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !c.configured {
+				return errors.New("unable to find configuration file, project must be initialized")
+			}
+			project, err := internalconfig.Load()
+			if err != nil {
+				return fmt.Errorf("failed to read config: %v", err)
+			}
+			model := newProjectModel(project.Config, c.resolution, pathExists)
+			return writeProjectModel(cmd.OutOrStdout(), output, model)
+		},
+	}
+	cmd.Flags().StringVarP(&output, outputFlag, "o", outputFormatYAML,
+		fmt.Sprintf("output format, possible values: (%s, %s)", outputFormatYAML, outputFormatJSON))
+	return cmd
+}
+
+// newProjectModel returns the model of the project configured by cfg, whose plugins were resolved as described by
+// resolution. exists reports whether a path of the project exists.
+func newProjectModel(cfg config.Config, resolution pluginResolution, exists func(string) bool) projectModel {
+	existing := func(path string) string {
+		if exists(path) {
+			return path
+		}
+		return ""
+	}
+
+	dirs := cfg.GetDirectories()
+	model := projectModel{
+		Config:  cfg,
+		Layout:  resolution.Layout,
+		Plugins: resolution.Chain,
+		Paths: projectPaths{
+			Main:        existing("main.go"),
+			APIs:        existing(dirs.APIs),
+			Controllers: existing(dirs.Controllers),
+			Manifests:   existing("config"),
+			CRDs:        existing(filepath.Join("config", "crd", "bases")),
+			Samples:     existing(filepath.Join("config", "samples")),
+		},
+	}
+
+	for _, gvk := range cfg.Resources {
+		opts := resource.Options{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Plural: gvk.Plural}
+		res := opts.NewResource(&cfg, true)
+		replacer := res.Replacer()
+
+		model.Resources = append(model.Resources, resourceModel{
+			Group:          gvk.Group,
+			Version:        gvk.Version,
+			Kind:           gvk.Kind,
+			Plural:         res.Plural,
+			Domain:         res.Domain,
+			Package:        res.Package,
+			CRDVersion:     gvk.CRDVersion,
+			WebhookVersion: gvk.WebhookVersion,
+			Defaults:       gvk.Defaults,
+			Hub:            gvk.Hub,
+			Files: resourceFiles{
+				Types:      existing(replacer.Replace(filepath.Join(dirs.Types, "%[kind]_types.go"))),
+				Controller: existing(replacer.Replace(filepath.Join(dirs.Controller, "%[kind]_controller.go"))),
+				Webhook:    existing(replacer.Replace(filepath.Join(dirs.Types, "%[kind]_webhook.go"))),
+				CRD: existing(filepath.Join("config", "crd", "bases",
+					fmt.Sprintf("%s_%s.yaml", res.Domain, strings.ToLower(res.Plural)))),
+				Sample: existing(replacer.Replace(
+					filepath.Join("config", "samples", "%[group]_%[version]_%[kind].yaml"))),
+			},
+		})
+	}
+	return model
+}
+
+// pathExists reports whether path exists.
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// writeProjectModel writes model to out in format.
+func writeProjectModel(out io.Writer, format string, model projectModel) error {
+	switch format {
+	case outputFormatYAML:
+		b, err := yaml.Marshal(model)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(b)
+		return err
+	case outputFormatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(model)
+	default:
+		return fmt.Errorf("unknown output format %q, possible values: (%s, %s)",
+			format, outputFormatYAML, outputFormatJSON)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+var _ = Describe("alpha export", func() {
+	var (
+		cfg        config.Config
+		resolution pluginResolution
+		files      map[string]bool
+	)
+
+	BeforeEach(func() {
+		cfg = config.Config{
+			Version: config.Version3Alpha,
+			Domain:  "my.domain",
+			Repo:    "example.com/project",
+			Layout:  "go.kubebuilder.io/v3-alpha",
+			Resources: []config.GVK{
+				{Group: "crew", Version: "v1", Kind: "Captain", WebhookVersion: "v1"},
+				{Group: "crew", Version: "v2", Kind: "Captain", Plural: "captainz"},
+			},
+		}
+		resolution = pluginResolution{
+			Layout: "go.kubebuilder.io/v3-alpha",
+			Chain:  []string{"base.go.kubebuilder.io/v3-alpha", "kustomize.common.kubebuilder.io/v1"},
+		}
+		files = map[string]bool{
+			"main.go":                           true,
+			"api":                               true,
+			"controllers":                       true,
+			"config":                            true,
+			"api/v1/captain_types.go":           true,
+			"api/v1/captain_webhook.go":         true,
+			"controllers/captain_controller.go": true,
+			"config/crd/bases/crew.my.domain_captainz.yaml": true,
+			"config/samples/crew_v2_captain.yaml":           true,
+		}
+	})
+
+	exists := func(path string) bool { return files[path] }
+
+	It("should derive the paths and the resources, sharing the plural of a Kind across its versions", func() {
+		model := newProjectModel(cfg, resolution, exists)
+		Expect(model.Layout).To(Equal("go.kubebuilder.io/v3-alpha"))
+		Expect(model.Plugins).To(Equal(resolution.Chain))
+		Expect(model.Paths).To(Equal(projectPaths{
+			Main:        "main.go",
+			APIs:        "api",
+			Controllers: "controllers",
+			Manifests:   "config",
+		}))
+		Expect(model.Resources).To(Equal([]resourceModel{
+			{
+				Group:          "crew",
+				Version:        "v1",
+				Kind:           "Captain",
+				Plural:         "captainz",
+				Domain:         "crew.my.domain",
+				Package:        "example.com/project/api/v1",
+				WebhookVersion: "v1",
+				Files: resourceFiles{
+					Types:      "api/v1/captain_types.go",
+					Controller: "controllers/captain_controller.go",
+					Webhook:    "api/v1/captain_webhook.go",
+					CRD:        "config/crd/bases/crew.my.domain_captainz.yaml",
+				},
+			},
+			{
+				Group:   "crew",
+				Version: "v2",
+				Kind:    "Captain",
+				Plural:  "captainz",
+				Domain:  "crew.my.domain",
+				Package: "example.com/project/api/v2",
+				Files: resourceFiles{
+					Controller: "controllers/captain_controller.go",
+					CRD:        "config/crd/bases/crew.my.domain_captainz.yaml",
+					Sample:     "config/samples/crew_v2_captain.yaml",
+				},
+			},
+		}))
+	})
+
+	It("should use the multigroup layout", func() {
+		cfg.MultiGroup = true
+		files["apis/crew/v1/captain_types.go"] = true
+		files["controllers/crew/captain_controller.go"] = true
+
+		model := newProjectModel(cfg, resolution, exists)
+		Expect(model.Paths.APIs).To(BeEmpty())
+		Expect(model.Resources[0].Package).To(Equal("example.com/project/apis/crew/v1"))
+		Expect(model.Resources[0].Files).To(Equal(resourceFiles{
+			Types:      "apis/crew/v1/captain_types.go",
+			Controller: "controllers/crew/captain_controller.go",
+			CRD:        "config/crd/bases/crew.my.domain_captainz.yaml",
+		}))
+	})
+
+	It("should use the layout profile", func() {
+		cfg.Profile = config.ProfileLegacy
+		files["pkg/apis"] = true
+		files["pkg/controller"] = true
+		files["pkg/apis/crew/v1/captain_types.go"] = true
+		files["pkg/controller/captain_controller.go"] = true
+
+		model := newProjectModel(cfg, resolution, exists)
+		Expect(model.Paths.APIs).To(Equal("pkg/apis"))
+		Expect(model.Paths.Controllers).To(Equal("pkg/controller"))
+		Expect(model.Resources[0].Package).To(Equal("example.com/project/pkg/apis/crew/v1"))
+		Expect(model.Resources[0].Files.Types).To(Equal("pkg/apis/crew/v1/captain_types.go"))
+		Expect(model.Resources[0].Files.Controller).To(Equal("pkg/controller/captain_controller.go"))
+	})
+
+	It("should write YAML and JSON", func() {
+		model := newProjectModel(config.Config{Version: config.Version3Alpha}, resolution, exists)
+
+		out := &bytes.Buffer{}
+		Expect(writeProjectModel(out, outputFormatYAML, model)).To(Succeed())
+		Expect(out.String()).To(Equal(`config:
+  version: 3-alpha
+layout: go.kubebuilder.io/v3-alpha
+paths:
+  apis: api
+  controllers: controllers
+  main: main.go
+  manifests: config
+plugins:
+- base.go.kubebuilder.io/v3-alpha
+- kustomize.common.kubebuilder.io/v1
+`))
+
+		out.Reset()
+		Expect(writeProjectModel(out, outputFormatJSON, model)).To(Succeed())
+		Expect(out.String()).To(MatchJSON(`{
+			"config": {"version": "3-alpha"},
+			"layout": "go.kubebuilder.io/v3-alpha",
+			"plugins": ["base.go.kubebuilder.io/v3-alpha", "kustomize.common.kubebuilder.io/v1"],
+			"paths": {"main": "main.go", "apis": "api", "controllers": "controllers", "manifests": "config"}
+		}`))
+
+		Expect(writeProjectModel(out, "toml", model)).To(MatchError(
+			`unknown output format "toml", possible values: (yaml, json)`))
+	})
+})
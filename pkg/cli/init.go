@@ -17,9 +17,13 @@ limitations under the License.
 package cli
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -27,10 +31,14 @@ import (
 	"github.com/spf13/cobra"
 
 	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/plugin"
 )
 
+const forceFlag = "force"
+
 func (c *cli) newInitCmd() *cobra.Command {
 	ctx := c.newInitContext()
 	cmd := &cobra.Command{
@@ -52,19 +60,25 @@ func (c *cli) newInitCmd() *cobra.Command {
 				fmt.Sprintf("Available plugins: (%s)", strings.Join(c.getAvailablePlugins(), ", ")))
 	}
 
-	// If only the help flag was set, return the command as is.
-	if c.doGenericHelp {
-		return cmd
-	}
+	cmd.Flags().Bool(forceFlag, false, "initialize the project even if the current directory has files in its "+
+		"layout, deciding for each existing file whether to overwrite it if the input is a terminal, "+
+		"or overwriting them all otherwise")
 
-	// Lookup the plugin for projectVersion and bind it to the command.
-	c.bindInit(ctx, cmd)
+	// Lookup the plugin for projectVersion and bind it to the command once it runs.
+	c.bindLazily(cmd, func() {
+		// If only the help flag was set, leave the command as is.
+		if !c.doGenericHelp {
+			c.bindInit(ctx, cmd)
+		}
+	})
 	return cmd
 }
 
 func (c cli) newInitContext() plugin.Context {
 	return plugin.Context{
 		CommandName: c.commandName,
+		Logger:      c.logger,
+		GlobalFlags: c.globalFlags,
 		Description: `Initialize a new project.
 
 For further help about a specific project version, set --project-version.
@@ -123,25 +137,23 @@ func (c cli) getAvailablePlugins() (pluginKeys []string) {
 }
 
 func (c cli) bindInit(ctx plugin.Context, cmd *cobra.Command) {
-	var getter plugin.InitPluginGetter
+	// Every resolved plugin supporting initialization is chained.
+	var subcommands []plugin.GenericSubcommand
+	var keys []string
 	for _, p := range c.resolvedPlugins {
-		tmpGetter, isGetter := p.(plugin.InitPluginGetter)
-		if isGetter {
-			if getter != nil {
-				err := fmt.Errorf("duplicate initialization plugins for project version %q (%s, %s), "+
-					"use a more specific plugin key", c.projectVersion, plugin.KeyFor(getter), plugin.KeyFor(p))
-				cmdErrNoHelp(cmd, err)
-				return
-			}
-			getter = tmpGetter
+		if getter, isGetter := p.(plugin.InitPluginGetter); isGetter {
+			subcommands = append(subcommands, getter.GetInitPlugin())
+			keys = append(keys, plugin.KeyFor(p))
 		}
 	}
-	if getter == nil {
+
+	if len(subcommands) == 0 {
 		var err error
-		if c.cliPluginKey == "" {
+		if len(c.cliPluginKeys) == 0 {
 			err = fmt.Errorf("project version %q does not support an initialization plugin", c.projectVersion)
 		} else {
-			err = fmt.Errorf("plugin %q does not support an initialization plugin", c.cliPluginKey)
+			err = fmt.Errorf("plugin %q does not support an initialization plugin",
+				strings.Join(c.cliPluginKeys, layoutSeparator))
 		}
 		cmdErrNoHelp(cmd, err)
 		return
@@ -150,22 +162,189 @@ func (c cli) bindInit(ctx plugin.Context, cmd *cobra.Command) {
 	cfg := internalconfig.New(internalconfig.DefaultPath)
 	cfg.Version = c.projectVersion
 
-	init := getter.GetInitPlugin()
-	init.InjectConfig(&cfg.Config)
-	init.BindFlags(cmd.Flags())
-	init.UpdateContext(&ctx)
+	if err := bindSubcommands(&ctx, cmd, &cfg.Config, subcommands, keys); err != nil {
+		cmdErrNoHelp(cmd, err)
+		return
+	}
+	defaulted, err := c.applyInitDefaults(cmd)
+	if err != nil {
+		cmdErrNoHelp(cmd, err)
+		return
+	}
+	// The layout records the whole plugin chain, so that later commands chain the same plugins.
+	if cfg.IsV3() {
+		cfg.Layout = makeLayout(c.layoutPlugins...)
+	}
+
 	cmd.Long = ctx.Description
 	cmd.Example = ctx.Examples
-	cmd.RunE = func(*cobra.Command, []string) error {
+	cmd.RunE = func(cmd *cobra.Command, _ []string) error {
 		// Check if a config is initialized in the command runner so the check
 		// doesn't erroneously fail other commands used in initialized projects.
 		_, err := internalconfig.Read()
 		if err == nil || os.IsExist(err) {
 			log.Fatal("config already initialized")
 		}
-		if err := init.Run(); err != nil {
+
+		// Conflicting files are reported before any plugin runs, so that a project is not left half-initialized.
+		conflicts, err := initConflicts(".", c.initLayout(&cfg.Config))
+		if err != nil {
+			return fmt.Errorf("failed to check the current directory: %v", err)
+		}
+		if len(conflicts) != 0 {
+			force, err := cmd.Flags().GetBool(forceFlag)
+			if err != nil {
+				return err
+			}
+			if !force {
+				return fmt.Errorf("the current directory already has files in the layout of the project: %s; "+
+					"set --%s to initialize the project anyway", strings.Join(conflicts, ", "), forceFlag)
+			}
+			machinery.SetExistingFileResolver(newExistingFileResolver(conflicts, cmd.InOrStdin(), cmd.OutOrStdout()))
+			defer machinery.SetExistingFileResolver(nil)
+		}
+
+		// The project version and plugins set in the environment or the defaults file, and the flags defaulted by
+		// the latter, are recorded as flags, for the history to initialize the project the same way.
+		for _, name := range defaulted {
+			cmd.Flags().Lookup(name).Changed = true
+		}
+		if !cmd.Flags().Changed(projectVersionFlag) && c.projectVersion != c.defaultProjectVersion {
+			_ = cmd.Flags().Set(projectVersionFlag, c.projectVersion)
+		}
+		if cmd.Flags().Lookup(pluginsFlag) != nil && !cmd.Flags().Changed(pluginsFlag) && len(c.cliPluginKeys) != 0 {
+			_ = cmd.Flags().Set(pluginsFlag, strings.Join(c.cliPluginKeys, layoutSeparator))
+		}
+		if err := runRecordedSubcommands(cfg, cmd, subcommands); err != nil {
 			return fmt.Errorf("failed to initialize project with version %q: %v", c.projectVersion, err)
 		}
-		return cfg.Save()
+		return nil
+	}
+}
+
+// initLayout returns the layout described by the resolved plugins that initialize projects, nil if none of them
+// describes it.
+func (c cli) initLayout(cfg *config.Config) map[string]string {
+	var layout map[string]string
+	for _, p := range c.resolvedPlugins {
+		if _, isGetter := p.(plugin.InitPluginGetter); !isGetter {
+			continue
+		}
+		if describer, isDescriber := p.(plugin.LayoutDescriber); isDescriber {
+			if layout == nil {
+				layout = make(map[string]string)
+			}
+			for path, description := range describer.DescribeLayout(cfg) {
+				layout[path] = description
+			}
+		}
+	}
+	return layout
+}
+
+// initConflicts returns the paths of layout that exist in dir, sorted, directories with a trailing slash.
+// If no layout is described, every entry of dir conflicts. A go.mod, that initialization reuses, and the
+// version control files never conflict.
+func initConflicts(dir string, layout map[string]string) ([]string, error) {
+	ignored := map[string]bool{"go.mod": true, "go.sum": true, ".git": true}
+
+	var paths []string
+	if layout == nil {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			paths = append(paths, entry.Name())
+		}
+	} else {
+		for path := range layout {
+			paths = append(paths, path)
+		}
+	}
+
+	var conflicts []string
+	for _, path := range paths {
+		if ignored[path] {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(dir, path))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			path += "/"
+		}
+		conflicts = append(conflicts, filepath.ToSlash(path))
+	}
+	sort.Strings(conflicts)
+	return conflicts, nil
+}
+
+// newExistingFileResolver returns the resolver deciding which existing files are overwritten on a forced
+// initialization: if in is a terminal, the user is asked for each of them on out, otherwise they are all
+// overwritten. Only the files reported as conflicts, or in a conflicting directory, are considered: the other
+// ones, like a go.mod, are kept as their templates decide.
+func newExistingFileResolver(conflicts []string, in io.Reader, out io.Writer) machinery.ExistingFileResolver {
+	resolve := newOverwritePrompt(in, out)
+	if !isTerminal(in) {
+		resolve = func(path string) bool {
+			logger.Default().Info("overwriting existing file", "path", path)
+			return true
+		}
+	}
+	return func(path string) bool {
+		return isConflict(conflicts, path) && resolve(path)
+	}
+}
+
+// isConflict reports whether path is one of conflicts, or in one of its directories, listed with a trailing slash.
+func isConflict(conflicts []string, path string) bool {
+	path = filepath.ToSlash(filepath.Clean(path))
+	for _, conflict := range conflicts {
+		if path == conflict || strings.HasSuffix(conflict, "/") && strings.HasPrefix(path, conflict) {
+			return true
+		}
+	}
+	return false
+}
+
+// newOverwritePrompt returns a resolver asking on out whether to overwrite each existing file, reading the
+// answers from in. Files are kept once in is exhausted.
+func newOverwritePrompt(in io.Reader, out io.Writer) machinery.ExistingFileResolver {
+	reader := bufio.NewReader(in)
+	return func(path string) bool {
+		for {
+			fmt.Fprintf(out, "%s already exists, overwrite it? [y/n] ", path)
+			answer, err := reader.ReadString('\n')
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+				return true
+			case "n", "no":
+				return false
+			}
+			if err != nil {
+				fmt.Fprintln(out)
+				return false
+			}
+			fmt.Fprintf(out, "invalid input %q, should be [y/n]\n", strings.TrimSpace(answer))
+		}
+	}
+}
+
+// isTerminal reports whether r is a terminal, i.e. a character device other than the null device.
+func isTerminal(r io.Reader) bool {
+	f, isFile := r.(*os.File)
+	if !isFile {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
 	}
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, null)
 }
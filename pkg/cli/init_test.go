@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+// initTestFile is a template skipped if the file exists, as the go.mod and Makefile templates of the plugins.
+type initTestFile struct {
+	file.TemplateMixin
+
+	body string
+}
+
+func (f *initTestFile) SetTemplateDefaults() error {
+	f.TemplateBody = f.body
+	return nil
+}
+
+var _ = Describe("init", func() {
+	Context("checking the current directory", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "kubebuilder-init")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.MkdirAll(filepath.Join(dir, "config", "default"), 0755)).To(Succeed())
+			Expect(os.Mkdir(filepath.Join(dir, ".git"), 0755)).To(Succeed())
+			for _, name := range []string{"Makefile", "README.md", "go.mod"} {
+				Expect(ioutil.WriteFile(filepath.Join(dir, name), []byte{}, 0644)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should list the existing paths of the layout", func() {
+			conflicts, err := initConflicts(dir, map[string]string{
+				"Makefile":       "",
+				"main.go":        "",
+				"go.mod":         "",
+				"config":         "",
+				"config/default": "",
+				"config/rbac":    "",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conflicts).To(Equal([]string{"Makefile", "config/", "config/default/"}))
+		})
+
+		It("should list every entry if no layout is described", func() {
+			conflicts, err := initConflicts(dir, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conflicts).To(Equal([]string{"Makefile", "README.md", "config/"}))
+		})
+
+		It("should not list anything in an empty directory", func() {
+			empty := filepath.Join(dir, "empty")
+			Expect(os.Mkdir(empty, 0755)).To(Succeed())
+			Expect(initConflicts(empty, nil)).To(BeEmpty())
+			Expect(initConflicts(empty, map[string]string{"Makefile": ""})).To(BeEmpty())
+		})
+	})
+
+	Context("deciding which existing files to overwrite", func() {
+		It("should overwrite every conflicting file if the input is not a terminal", func() {
+			var out bytes.Buffer
+			resolve := newExistingFileResolver([]string{"Makefile", "config/"}, strings.NewReader("n\n"), &out)
+			Expect(resolve("Makefile")).To(BeTrue())
+			Expect(resolve("config/default/kustomization.yaml")).To(BeTrue())
+			Expect(resolve("go.mod")).To(BeFalse())
+			Expect(resolve("configs.yaml")).To(BeFalse())
+			Expect(out.String()).To(BeEmpty())
+		})
+
+		It("should keep an existing go.mod on a forced initialization", func() {
+			dir, err := ioutil.TempDir("", "kubebuilder-init")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+			wd, err := os.Getwd()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Chdir(dir)).To(Succeed())
+			defer func() { Expect(os.Chdir(wd)).To(Succeed()) }()
+
+			goMod := "module example.org/project\n\nrequire github.com/foo/bar v1.2.3\n"
+			Expect(ioutil.WriteFile("go.mod", []byte(goMod), 0644)).To(Succeed())
+			Expect(ioutil.WriteFile("Makefile", []byte("all:\n"), 0644)).To(Succeed())
+
+			conflicts, err := initConflicts(".", map[string]string{"go.mod": "", "Makefile": ""})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conflicts).To(Equal([]string{"Makefile"}))
+
+			var out bytes.Buffer
+			machinery.SetExistingFileResolver(newExistingFileResolver(conflicts, strings.NewReader(""), &out))
+			defer machinery.SetExistingFileResolver(nil)
+			goModFile := &initTestFile{body: "module example.org/project\n"}
+			goModFile.Path = "go.mod"
+			makefile := &initTestFile{body: "build:\n"}
+			makefile.Path = "Makefile"
+			Expect(machinery.NewScaffold().Execute(model.NewUniverse(), goModFile, makefile)).To(Succeed())
+
+			Expect(ioutil.ReadFile("go.mod")).To(Equal([]byte(goMod)))
+			Expect(ioutil.ReadFile("Makefile")).To(Equal([]byte("build:\n")))
+		})
+
+		It("should ask whether to overwrite each file", func() {
+			var out bytes.Buffer
+			resolve := newOverwritePrompt(strings.NewReader("yes\nmaybe\nn\n"), &out)
+			Expect(resolve("Makefile")).To(BeTrue())
+			Expect(resolve("Dockerfile")).To(BeFalse())
+			Expect(out.String()).To(Equal("Makefile already exists, overwrite it? [y/n] " +
+				"Dockerfile already exists, overwrite it? [y/n] invalid input \"maybe\", should be [y/n]\n" +
+				"Dockerfile already exists, overwrite it? [y/n] "))
+		})
+
+		It("should keep the files once the input is exhausted", func() {
+			var out bytes.Buffer
+			resolve := newOverwritePrompt(strings.NewReader(""), &out)
+			Expect(resolve("Makefile")).To(BeFalse())
+		})
+	})
+})
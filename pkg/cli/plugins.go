@@ -19,6 +19,7 @@ package cli
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
 	"sigs.k8s.io/kubebuilder/pkg/plugin"
@@ -45,9 +46,21 @@ func (e errAmbiguousPlugin) Error() string {
 // unversioned names "go.kubernetes.io" and "go". If pluginKey is ambiguous
 // or does not match any known plugin's key, an error is returned.
 //
+// The version of a key can also be a range, ex. "go/>=v2" or "go/~v3", which
+// resolves to the highest stable matching version, or the highest unstable
+// one if no stable version matches.
+//
 // This function does not guarantee that the resolved set contains a plugin
 // for each plugin type, i.e. an Init plugin might not be returned.
-func resolvePluginsByKey(versionedPlugins []plugin.Base, pluginKey string) (resolved []plugin.Base, err error) {
+func resolvePluginsByKey(versionedPlugins []plugin.Base, pluginKey string) ([]plugin.Base, error) {
+	resolved, _, err := explainPluginsByKey(versionedPlugins, pluginKey)
+	return resolved, err
+}
+
+// explainPluginsByKey is resolvePluginsByKey, also returning the steps that
+// narrowed versionedPlugins down to the resolved plugin.
+func explainPluginsByKey(versionedPlugins []plugin.Base, pluginKey string) (
+	resolved []plugin.Base, steps []string, err error) {
 
 	name, version := plugin.SplitKey(pluginKey)
 
@@ -56,48 +69,302 @@ func resolvePluginsByKey(versionedPlugins []plugin.Base, pluginKey string) (reso
 	if name == shortName {
 		// Case: if plugin name is short, find matching short names.
 		resolved = findPluginsMatchingShortName(versionedPlugins, shortName)
+		steps = append(steps, fmt.Sprintf("short name %q matches %+q", shortName, makePluginKeySlice(resolved...)))
 	} else {
 		// Case: if plugin name is fully-qualified, match only fully-qualified names.
 		resolved = findPluginsMatchingName(versionedPlugins, name)
+		steps = append(steps, fmt.Sprintf("name %q matches %+q", name, makePluginKeySlice(resolved...)))
 	}
 
 	if len(resolved) == 0 {
-		return nil, errAmbiguousPlugin{
+		return nil, steps, errAmbiguousPlugin{
 			key: pluginKey,
-			msg: fmt.Sprintf("no names match, possible plugins: %+q", makePluginKeySlice(versionedPlugins...)),
+			msg: noMatchMessage("no names match",
+				findPluginsWithSimilarName(versionedPlugins, name, version), versionedPlugins),
 		}
 	}
 
 	if version != "" {
 		// Case: if plugin key has version, filter by version.
-		v, err := plugin.ParseVersion(version)
+		c, err := plugin.ParseVersionConstraint(version)
 		if err != nil {
-			return nil, err
+			return nil, steps, err
 		}
-		keys := makePluginKeySlice(resolved...)
+		sameName := append([]plugin.Base(nil), resolved...)
 		for i := 0; i < len(resolved); i++ {
-			if v.Compare(resolved[i].Version()) != 0 {
+			if !c.Check(resolved[i].Version()) {
 				resolved = append(resolved[:i], resolved[i+1:]...)
 				i--
 			}
 		}
+		steps = append(steps, fmt.Sprintf("version %q matches %+q", version, makePluginKeySlice(resolved...)))
 		if len(resolved) == 0 {
-			return nil, errAmbiguousPlugin{
+			return nil, steps, errAmbiguousPlugin{
 				key: pluginKey,
-				msg: fmt.Sprintf("no versions match, possible plugins: %+q", keys),
+				msg: noMatchMessage("no versions match", sameName, versionedPlugins),
 			}
 		}
+		// Case: if plugin key has a version range, pick the best matching version of each plugin.
+		if c.IsRange() {
+			resolved = findHighestVersions(resolved)
+			steps = append(steps, fmt.Sprintf("the highest version of each name, stable ones first, is %+q",
+				makePluginKeySlice(resolved...)))
+		}
 	}
 
 	// Since plugins has already been resolved by matching names and versions,
 	// it should only contain one matching value if it isn't ambiguous.
 	if len(resolved) != 1 {
-		return nil, errAmbiguousPlugin{
+		return nil, steps, errAmbiguousPlugin{
 			key: pluginKey,
 			msg: fmt.Sprintf("matching plugins: %+q", makePluginKeySlice(resolved...)),
 		}
 	}
-	return resolved, nil
+	return resolved, steps, nil
+}
+
+// noMatchMessage returns reason followed by suggestions, if any, and by the
+// keys of all plugins.
+func noMatchMessage(reason string, suggestions, plugins []plugin.Base) string {
+	if len(suggestions) != 0 {
+		reason += fmt.Sprintf(", did you mean %s?", strings.Join(quoteAll(makePluginKeySlice(suggestions...)), " or "))
+	} else {
+		reason += ","
+	}
+	return fmt.Sprintf("%s possible plugins: %+q", reason, makePluginKeySlice(plugins...))
+}
+
+// findPluginsWithSimilarName returns the plugins with the names closest to
+// name, which are likely what the user meant when name is a typo. A short name
+// is compared against short names. Only names within a third of the length of
+// name, and at least 1, edits are considered similar. If version is set,
+// similar plugins with a version matching it are preferred.
+func findPluginsWithSimilarName(plugins []plugin.Base, name, version string) []plugin.Base {
+	isShort := name == plugin.GetShortName(name)
+
+	maxDistance := len(name) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	var similar []plugin.Base
+	for _, p := range plugins {
+		candidate := p.Name()
+		if isShort {
+			candidate = plugin.GetShortName(candidate)
+		}
+		switch distance := levenshtein(name, candidate); {
+		case distance < maxDistance:
+			maxDistance = distance
+			similar = []plugin.Base{p}
+		case distance == maxDistance:
+			similar = append(similar, p)
+		}
+	}
+
+	if c, err := plugin.ParseVersionConstraint(version); err == nil {
+		var sameVersion []plugin.Base
+		for _, p := range similar {
+			if c.Check(p.Version()) {
+				sameVersion = append(sameVersion, p)
+			}
+		}
+		if len(sameVersion) != 0 {
+			return sameVersion
+		}
+	}
+	return similar
+}
+
+// levenshtein returns the minimum number of single character insertions,
+// deletions and substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", v))
+	}
+	return quoted
+}
+
+// resolvePluginsByKeys resolves each key in pluginKeys to a plugin, keeping
+// their order. Keys are first resolved against defaultPlugins, then against
+// versionedPlugins, using resolvePluginsByKey. Keys with a version range are
+// only resolved against versionedPlugins, as a default plugin matching the
+// range is not necessarily its best match.
+func resolvePluginsByKeys(defaultPlugins, versionedPlugins []plugin.Base, pluginKeys []string) ([]plugin.Base, error) {
+	resolved, _, err := explainPluginsByKeys(defaultPlugins, versionedPlugins, pluginKeys)
+	return resolved, err
+}
+
+const (
+	resolutionSourceFlag    = "flag"
+	resolutionSourceLayout  = "layout"
+	resolutionSourceDefault = "default"
+	// resolutionSourceDefaultsFile is the source of the plugins of the defaults file of the user.
+	resolutionSourceDefaultsFile = "defaultsFile"
+)
+
+// pluginResolution explains how the plugins of a cli were resolved.
+type pluginResolution struct {
+	ProjectVersion string `json:"projectVersion"`
+	// Source is where the resolved keys come from: the --plugins flag, the project layout or,
+	// if neither holds keys, the default plugin of the project version.
+	Source string `json:"source"`
+	// DefaultPlugin is the key of the default plugin of the project version, if any.
+	DefaultPlugin string `json:"defaultPlugin,omitempty"`
+	// Keys explains how each key was resolved, in order. Empty if the default plugin is used.
+	Keys []keyResolution `json:"keys,omitempty"`
+	// Layout is the project layout of the resolved plugins.
+	Layout string `json:"layout"`
+	// Chain lists the keys of the resolved plugins, bundles expanded, in the order they run.
+	Chain []string `json:"chain"`
+}
+
+// keyResolution explains how a plugin key was resolved.
+type keyResolution struct {
+	Key string `json:"key"`
+	// Candidates are the keys of the plugins the key was resolved against.
+	Candidates []string `json:"candidates"`
+	// Steps narrowed the candidates down to the resolved plugin.
+	Steps []string `json:"steps"`
+	// Resolved is the key of the plugin the key resolved to, empty if it could not be resolved.
+	Resolved string `json:"resolved,omitempty"`
+}
+
+// explainPluginsByKeys is resolvePluginsByKeys, also returning how each key
+// was resolved. A key resolved against versionedPlugins is explained by both
+// resolutions, the one against defaultPlugins first.
+func explainPluginsByKeys(defaultPlugins, versionedPlugins []plugin.Base, pluginKeys []string) (
+	[]plugin.Base, []keyResolution, error) {
+
+	resolved := make([]plugin.Base, 0, len(pluginKeys))
+	resolutions := make([]keyResolution, 0, len(pluginKeys))
+	explain := func(candidates []plugin.Base, pluginKey string) ([]plugin.Base, error) {
+		plugins, steps, err := explainPluginsByKey(candidates, pluginKey)
+		resolution := keyResolution{Key: pluginKey, Candidates: makePluginKeySlice(candidates...), Steps: steps}
+		if err != nil {
+			resolution.Steps = append(resolution.Steps, err.Error())
+		} else {
+			resolution.Resolved = plugin.KeyFor(plugins[0])
+		}
+		resolutions = append(resolutions, resolution)
+		return plugins, err
+	}
+	for _, pluginKey := range pluginKeys {
+		plugins, err := explain(defaultPlugins, pluginKey)
+		if err != nil || isVersionRange(pluginKey) {
+			last := &resolutions[len(resolutions)-1]
+			if err == nil {
+				last.Steps = append(last.Steps, "a version range is resolved against all the plugins")
+			} else {
+				last.Steps = append(last.Steps, "falling back to all the plugins")
+			}
+			if plugins, err = explain(versionedPlugins, pluginKey); err != nil {
+				return nil, resolutions, err
+			}
+		}
+		resolved = append(resolved, plugins...)
+	}
+
+	// The same plugin can not be chained twice, even through different bundles.
+	pluginKeySet := make(map[string]struct{}, len(resolved))
+	for _, p := range expandBundles(resolved...) {
+		pluginKey := plugin.KeyFor(p)
+		if _, seen := pluginKeySet[pluginKey]; seen {
+			return nil, resolutions, fmt.Errorf("plugin %q can not be chained more than once", pluginKey)
+		}
+		pluginKeySet[pluginKey] = struct{}{}
+	}
+
+	return resolved, resolutions, nil
+}
+
+// isVersionRange returns true if the version of pluginKey is a version range.
+func isVersionRange(pluginKey string) bool {
+	_, version := plugin.SplitKey(pluginKey)
+	c, err := plugin.ParseVersionConstraint(version)
+	return err == nil && c.IsRange()
+}
+
+// expandBundles returns plugins with every bundle replaced by the plugins it
+// groups, recursively.
+func expandBundles(plugins ...plugin.Base) (expanded []plugin.Base) {
+	for _, p := range plugins {
+		if b, isBundle := p.(plugin.Bundle); isBundle {
+			expanded = append(expanded, expandBundles(b.Plugins()...)...)
+		} else {
+			expanded = append(expanded, p)
+		}
+	}
+	return expanded
+}
+
+// makeLayout returns the layout value identifying a chain of plugins, which is
+// the ordered list of their keys.
+func makeLayout(plugins ...plugin.Base) string {
+	keys := make([]string, 0, len(plugins))
+	for _, p := range plugins {
+		keys = append(keys, plugin.KeyFor(p))
+	}
+	return strings.Join(keys, layoutSeparator)
+}
+
+// findHighestVersions returns, for each plugin name in plugins, the plugin with
+// the highest stable version. Unstable versions are only returned for names
+// that have no stable version.
+func findHighestVersions(plugins []plugin.Base) (highest []plugin.Base) {
+	highestByName := make(map[string]plugin.Base, len(plugins))
+	for _, p := range plugins {
+		current, found := highestByName[p.Name()]
+		if !found || isBetterVersion(p.Version(), current.Version()) {
+			highestByName[p.Name()] = p
+		}
+	}
+	for _, p := range highestByName {
+		highest = append(highest, p)
+	}
+	sort.Slice(highest, func(i, j int) bool { return highest[i].Name() < highest[j].Name() })
+	return highest
+}
+
+// isBetterVersion returns true if v should be preferred over current: stable
+// versions are preferred over unstable ones, then higher versions over lower.
+func isBetterVersion(v, current plugin.Version) bool {
+	if v.IsStable() != current.IsStable() {
+		return v.IsStable()
+	}
+	return v.Compare(current) > 0
 }
 
 // findPluginsMatchingName returns a set of plugins with Name() exactly
@@ -162,5 +429,12 @@ func validatePlugin(p plugin.Base) error {
 			return fmt.Errorf("invalid project version %q: %v", projectVersion, err)
 		}
 	}
+	if b, isBundle := p.(plugin.Bundle); isBundle {
+		for _, bp := range b.Plugins() {
+			if err := validatePlugin(bp); err != nil {
+				return fmt.Errorf("invalid plugin %q in bundle %q: %v", plugin.KeyFor(bp), plugin.KeyFor(p), err)
+			}
+		}
+	}
 	return nil
 }
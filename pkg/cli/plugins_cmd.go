@@ -0,0 +1,349 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+const (
+	pluginsCommandName = "plugins"
+
+	explainFlag       = "explain"
+	outputFlag        = "output"
+	outputFormatTable = "table"
+	outputFormatJSON  = "json"
+)
+
+// pluginInfo describes a registered plugin.
+type pluginInfo struct {
+	Key             string   `json:"key"`
+	Name            string   `json:"name"`
+	Version         string   `json:"version"`
+	ProjectVersions []string `json:"projectVersions"`
+	// DefaultFor lists the project versions the plugin is the default plugin of.
+	DefaultFor []string `json:"defaultFor,omitempty"`
+	// Deprecation is the deprecation warning of the plugin, if deprecated.
+	Deprecation string `json:"deprecation,omitempty"`
+	// Plugins lists the keys of the plugins grouped by the plugin, if it is a bundle.
+	Plugins []string `json:"plugins,omitempty"`
+}
+
+// deprecationKind is what a deprecation applies to.
+type deprecationKind string
+
+const (
+	deprecationKindPlugin         deprecationKind = "plugin"
+	deprecationKindProjectVersion deprecationKind = "projectVersion"
+)
+
+// deprecationInfo describes a deprecated plugin or project version in use by the project.
+type deprecationInfo struct {
+	Kind deprecationKind `json:"kind"`
+	// Name is the key of the plugin or the project version.
+	Name string `json:"name"`
+	// Warning tells what to do instead, e.g. which plugin to upgrade to.
+	Warning string `json:"warning"`
+	// RemovalVersion is the version of the CLI the plugin or project version is removed in, if announced.
+	RemovalVersion string `json:"removalVersion,omitempty"`
+	// Link points to the migration instructions.
+	Link string `json:"link"`
+}
+
+func (c cli) newPluginsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   pluginsCommandName,
+		Short: "Inspect the plugins available to this CLI",
+		Long:  `Command group for commands that describe the plugins available to this CLI`,
+	}
+	cmd.AddCommand(
+		c.newPluginsListCmd(),
+		c.newPluginsResolveCmd(),
+		c.newPluginsDeprecationsCmd(),
+	)
+	return cmd
+}
+
+func (c cli) newPluginsListCmd() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the available plugins",
+		Long: `List every plugin available to this CLI with its key, the project versions it supports, whether it is
+the default plugin of any project version and whether it is deprecated.
+
+Plugins are passed to 'init' by key with --plugins.
+`,
+		Example: fmt.Sprintf(`  # List the available plugins
+  %[1]s plugins list
+
+  # List the available plugins in JSON
+  %[1]s plugins list --output json
+`, c.commandName),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return writePluginInfos(cmd.OutOrStdout(), output, c.pluginInfos())
+		},
+	}
+	cmd.Flags().StringVarP(&output, outputFlag, "o", outputFormatTable,
+		fmt.Sprintf("output format, possible values: (%s, %s)", outputFormatTable, outputFormatJSON))
+	return cmd
+}
+
+func (c cli) newPluginsResolveCmd() *cobra.Command {
+	var (
+		explain bool
+		output  string
+	)
+	cmd := &cobra.Command{
+		Use:   "resolve",
+		Short: "Show the plugins the commands of this CLI run",
+		Long: `Show the project layout and the chain of plugins the commands of this CLI run, in the current directory.
+
+The plugin keys are taken from --plugins if set, from the layout of the PROJECT file otherwise. A project without
+either uses the default plugin of its project version. With --explain, the candidates each key was resolved against
+and the steps narrowing them down to a single plugin are shown too; the default plugin of the project version is
+tried first, all the plugins after it if the key does not match it or is a version range. The JSON output always
+holds the explanation.
+`,
+		Example: fmt.Sprintf(`  # Show the plugins of the project in the current directory
+  %[1]s plugins resolve
+
+  # Explain how the keys passed to init would be resolved
+  %[1]s plugins resolve --explain --plugins go/v3-alpha
+`, c.commandName),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return writePluginResolution(cmd.OutOrStdout(), output, explain, c.resolution)
+		},
+	}
+	cmd.Flags().BoolVar(&explain, explainFlag, false, "explain how each plugin key was resolved")
+	cmd.Flags().StringVarP(&output, outputFlag, "o", outputFormatTable,
+		fmt.Sprintf("output format, possible values: (%s, %s)", outputFormatTable, outputFormatJSON))
+	// --plugins is parsed before the commands are built, it is registered so that it does not cause a parse error.
+	cmd.Flags().StringSlice(pluginsFlag, nil, "keys of the plugins to resolve instead of the project layout")
+	return cmd
+}
+
+func (c cli) newPluginsDeprecationsCmd() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "deprecations",
+		Short: "List the deprecated plugins and project version in use by the project",
+		Long: `List the deprecated plugins the commands run on the project in the current directory, bundles and
+the plugins they group included, and its project version if it is deprecated, with what to do instead and the
+version of the CLI they are removed in, if announced.
+
+The JSON output is a list, empty if nothing is deprecated, so that the projects of many repositories can be
+scanned by scripts.
+`,
+		Example: fmt.Sprintf(`  # List the deprecations of the project
+  %[1]s plugins deprecations
+
+  # List the deprecations of the project in JSON
+  %[1]s plugins deprecations --output json
+`, c.commandName),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !c.configured {
+				return errors.New("unable to find configuration file, project must be initialized")
+			}
+			return writeDeprecationInfos(cmd.OutOrStdout(), output, c.deprecationInfos())
+		},
+	}
+	cmd.Flags().StringVarP(&output, outputFlag, "o", outputFormatTable,
+		fmt.Sprintf("output format, possible values: (%s, %s)", outputFormatTable, outputFormatJSON))
+	return cmd
+}
+
+// deprecationInfos describes the deprecated project version and plugins in use, the plugins in chain order.
+func (c cli) deprecationInfos() []deprecationInfo {
+	infos := make([]deprecationInfo, 0)
+	if d, isDeprecated := c.projectVersionDeprecations[c.projectVersion]; isDeprecated {
+		infos = append(infos, deprecationInfo{
+			Kind:           deprecationKindProjectVersion,
+			Name:           c.projectVersion,
+			Warning:        d.Warning,
+			RemovalVersion: d.RemovalVersion,
+			Link:           migrationGuideURL,
+		})
+	}
+	seen := make(map[string]bool)
+	for _, p := range append(append([]plugin.Base{}, c.layoutPlugins...), c.resolvedPlugins...) {
+		key := plugin.KeyFor(p)
+		d, isDeprecated := p.(plugin.Deprecated)
+		if !isDeprecated || seen[key] {
+			continue
+		}
+		seen[key] = true
+		infos = append(infos, deprecationInfo{
+			Kind:           deprecationKindPlugin,
+			Name:           key,
+			Warning:        d.DeprecationWarning(),
+			RemovalVersion: pluginRemovalVersion(p),
+			Link:           migrationGuideURL,
+		})
+	}
+	return infos
+}
+
+// writeDeprecationInfos writes infos to out in format.
+func writeDeprecationInfos(out io.Writer, format string, infos []deprecationInfo) error {
+	switch format {
+	case outputFormatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(infos)
+	case outputFormatTable:
+		if len(infos) == 0 {
+			_, _ = fmt.Fprintln(out, "No deprecated plugin or project version is in use.")
+			return nil
+		}
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "KIND\tNAME\tREMOVED IN\tWARNING")
+		for _, info := range infos {
+			removal := info.RemovalVersion
+			if removal == "" {
+				removal = "-"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", info.Kind, info.Name, removal, info.Warning)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q, possible values: (%s, %s)",
+			format, outputFormatTable, outputFormatJSON)
+	}
+}
+
+// writePluginResolution writes r to out in format, explaining how each key was resolved if explain is set.
+func writePluginResolution(out io.Writer, format string, explain bool, r pluginResolution) error {
+	switch format {
+	case outputFormatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case outputFormatTable:
+		if explain {
+			_, _ = fmt.Fprintf(out, "Project version: %s\n", r.ProjectVersion)
+			if r.DefaultPlugin != "" {
+				_, _ = fmt.Fprintf(out, "Default plugin: %s\n", r.DefaultPlugin)
+			}
+			switch r.Source {
+			case resolutionSourceFlag:
+				_, _ = fmt.Fprintf(out, "Keys from: --%s\n", pluginsFlag)
+			case resolutionSourceLayout:
+				_, _ = fmt.Fprintln(out, "Keys from: the project layout")
+			case resolutionSourceDefaultsFile:
+				_, _ = fmt.Fprintln(out, "Keys from: the defaults file")
+			default:
+				_, _ = fmt.Fprintln(out, "Keys from: none, the default plugin is used")
+			}
+			for _, key := range r.Keys {
+				_, _ = fmt.Fprintf(out, "Key %q against %+q:\n", key.Key, key.Candidates)
+				for _, step := range key.Steps {
+					_, _ = fmt.Fprintf(out, "  %s\n", step)
+				}
+				if key.Resolved != "" {
+					_, _ = fmt.Fprintf(out, "  resolved to %s\n", key.Resolved)
+				}
+			}
+		}
+		_, _ = fmt.Fprintf(out, "Layout: %s\n", r.Layout)
+		_, _ = fmt.Fprintf(out, "Chain: %s\n", strings.Join(r.Chain, ", "))
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, possible values: (%s, %s)",
+			format, outputFormatTable, outputFormatJSON)
+	}
+}
+
+// pluginInfos describes every registered and default plugin, sorted by key.
+func (c cli) pluginInfos() []pluginInfo {
+	infos := make(map[string]*pluginInfo)
+	addPlugin := func(p plugin.Base) *pluginInfo {
+		key := plugin.KeyFor(p)
+		if info, found := infos[key]; found {
+			return info
+		}
+		info := &pluginInfo{
+			Key:             key,
+			Name:            p.Name(),
+			Version:         p.Version().String(),
+			ProjectVersions: p.SupportedProjectVersions(),
+		}
+		if deprecated, isDeprecated := p.(plugin.Deprecated); isDeprecated {
+			info.Deprecation = deprecated.DeprecationWarning()
+		}
+		if bundle, isBundle := p.(plugin.Bundle); isBundle {
+			info.Plugins = makePluginKeySlice(bundle.Plugins()...)
+		}
+		infos[key] = info
+		return info
+	}
+	for _, plugins := range c.pluginsFromOptions {
+		for _, p := range plugins {
+			addPlugin(p)
+		}
+	}
+	for projectVersion, p := range c.defaultPluginsFromOptions {
+		info := addPlugin(p)
+		info.DefaultFor = append(info.DefaultFor, projectVersion)
+	}
+
+	sorted := make([]pluginInfo, 0, len(infos))
+	for _, info := range infos {
+		sort.Strings(info.DefaultFor)
+		sorted = append(sorted, *info)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+	return sorted
+}
+
+// writePluginInfos writes infos to out in format.
+func writePluginInfos(out io.Writer, format string, infos []pluginInfo) error {
+	switch format {
+	case outputFormatJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(infos)
+	case outputFormatTable:
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "KEY\tPROJECT VERSIONS\tDEFAULT FOR\tDEPRECATED")
+		for _, info := range infos {
+			deprecated := "no"
+			if info.Deprecation != "" {
+				deprecated = "yes"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", info.Key, strings.Join(info.ProjectVersions, ", "),
+				strings.Join(info.DefaultFor, ", "), deprecated)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q, possible values: (%s, %s)",
+			format, outputFormatTable, outputFormatJSON)
+	}
+}
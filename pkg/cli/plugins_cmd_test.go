@@ -0,0 +1,236 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+var _ = Describe("plugins list", func() {
+	var c *cli
+
+	BeforeEach(func() {
+		stable := makeBasePlugin("go.example.com", "v2", config.Version2, config.Version3Alpha)
+		deprecated := mockDeprecatedPlugin{makeBasePlugin("go.example.com", "v1", config.Version3Alpha).(mockPlugin)}
+		bundle, err := plugin.NewBundle("all.example.com", plugin.Version{Number: 1}, stable)
+		Expect(err).NotTo(HaveOccurred())
+
+		c = &cli{
+			pluginsFromOptions: makeSetByProjVer(stable, deprecated, bundle),
+			defaultPluginsFromOptions: map[string]plugin.Base{
+				config.Version2:      stable,
+				config.Version3Alpha: stable,
+			},
+		}
+	})
+
+	It("should describe every plugin sorted by key", func() {
+		Expect(c.pluginInfos()).To(Equal([]pluginInfo{
+			{
+				Key:             "all.example.com/v1",
+				Name:            "all.example.com",
+				Version:         "v1",
+				ProjectVersions: []string{config.Version2, config.Version3Alpha},
+				Plugins:         []string{"go.example.com/v2"},
+			},
+			{
+				Key:             "go.example.com/v1",
+				Name:            "go.example.com",
+				Version:         "v1",
+				ProjectVersions: []string{config.Version3Alpha},
+				Deprecation:     "use go.example.com/v2 instead",
+			},
+			{
+				Key:             "go.example.com/v2",
+				Name:            "go.example.com",
+				Version:         "v2",
+				ProjectVersions: []string{config.Version2, config.Version3Alpha},
+				DefaultFor:      []string{config.Version2, config.Version3Alpha},
+			},
+		}))
+	})
+
+	It("should write a table", func() {
+		out := &bytes.Buffer{}
+		Expect(writePluginInfos(out, outputFormatTable, c.pluginInfos())).To(Succeed())
+		Expect(out.String()).To(Equal(
+			"KEY                 PROJECT VERSIONS  DEFAULT FOR  DEPRECATED\n" +
+				"all.example.com/v1  2, 3-alpha                     no\n" +
+				"go.example.com/v1   3-alpha                        yes\n" +
+				"go.example.com/v2   2, 3-alpha        2, 3-alpha   no\n"))
+	})
+
+	It("should write JSON", func() {
+		out := &bytes.Buffer{}
+		Expect(writePluginInfos(out, outputFormatJSON, c.pluginInfos()[1:2])).To(Succeed())
+		Expect(out.String()).To(MatchJSON(`[{
+			"key": "go.example.com/v1",
+			"name": "go.example.com",
+			"version": "v1",
+			"projectVersions": ["3-alpha"],
+			"deprecation": "use go.example.com/v2 instead"
+		}]`))
+	})
+
+	It("should fail with an unknown format", func() {
+		err := writePluginInfos(&bytes.Buffer{}, "yaml", nil)
+		Expect(err).To(MatchError(`unknown output format "yaml", possible values: (table, json)`))
+	})
+})
+
+var _ = Describe("plugins resolve", func() {
+	resolution := pluginResolution{
+		ProjectVersion: config.Version3Alpha,
+		Source:         resolutionSourceFlag,
+		DefaultPlugin:  "go.example.com/v2",
+		Keys: []keyResolution{{
+			Key:        "go",
+			Candidates: []string{"go.example.com/v2"},
+			Steps:      []string{`short name "go" matches ["go.example.com/v2"]`},
+			Resolved:   "go.example.com/v2",
+		}},
+		Layout: "go.example.com/v2",
+		Chain:  []string{"go.example.com/v2"},
+	}
+
+	It("should write the layout and the chain", func() {
+		out := &bytes.Buffer{}
+		Expect(writePluginResolution(out, outputFormatTable, false, resolution)).To(Succeed())
+		Expect(out.String()).To(Equal("Layout: go.example.com/v2\nChain: go.example.com/v2\n"))
+	})
+
+	It("should explain the resolution", func() {
+		out := &bytes.Buffer{}
+		Expect(writePluginResolution(out, outputFormatTable, true, resolution)).To(Succeed())
+		Expect(out.String()).To(Equal(`Project version: 3-alpha
+Default plugin: go.example.com/v2
+Keys from: --plugins
+Key "go" against ["go.example.com/v2"]:
+  short name "go" matches ["go.example.com/v2"]
+  resolved to go.example.com/v2
+Layout: go.example.com/v2
+Chain: go.example.com/v2
+`))
+	})
+
+	It("should write JSON", func() {
+		out := &bytes.Buffer{}
+		Expect(writePluginResolution(out, outputFormatJSON, false, resolution)).To(Succeed())
+		Expect(out.String()).To(MatchJSON(`{
+			"projectVersion": "3-alpha",
+			"source": "flag",
+			"defaultPlugin": "go.example.com/v2",
+			"keys": [{
+				"key": "go",
+				"candidates": ["go.example.com/v2"],
+				"steps": ["short name \"go\" matches [\"go.example.com/v2\"]"],
+				"resolved": "go.example.com/v2"
+			}],
+			"layout": "go.example.com/v2",
+			"chain": ["go.example.com/v2"]
+		}`))
+	})
+})
+
+var _ plugin.Removable = mockRemovablePlugin{}
+
+type mockRemovablePlugin struct{ mockDeprecatedPlugin }
+
+func (mockRemovablePlugin) RemovalVersion() string { return "v4.0.0" }
+
+var _ = Describe("plugins deprecations", func() {
+	var c *cli
+
+	BeforeEach(func() {
+		deprecated := mockDeprecatedPlugin{makeBasePlugin("go.example.com", "v1", config.Version2).(mockPlugin)}
+		removable := mockRemovablePlugin{
+			mockDeprecatedPlugin{makeBasePlugin("kustomize.example.com", "v1", config.Version2).(mockPlugin)},
+		}
+		bundle, err := plugin.NewBundle("all.example.com", plugin.Version{Number: 1}, deprecated, removable)
+		Expect(err).NotTo(HaveOccurred())
+
+		c = &cli{
+			projectVersion: config.Version2,
+			projectVersionDeprecations: map[string]projectVersionDeprecation{
+				config.Version2: {Warning: "upgrade to 3-alpha", RemovalVersion: "v3.0.0"},
+			},
+			layoutPlugins:   []plugin.Base{bundle, deprecated},
+			resolvedPlugins: []plugin.Base{deprecated, removable, deprecated},
+		}
+	})
+
+	It("should describe the deprecated project version and plugins in use, once each", func() {
+		Expect(c.deprecationInfos()).To(Equal([]deprecationInfo{
+			{
+				Kind:           deprecationKindProjectVersion,
+				Name:           config.Version2,
+				Warning:        "upgrade to 3-alpha",
+				RemovalVersion: "v3.0.0",
+				Link:           migrationGuideURL,
+			},
+			{
+				Kind:    deprecationKindPlugin,
+				Name:    "go.example.com/v1",
+				Warning: "use go.example.com/v2 instead",
+				Link:    migrationGuideURL,
+			},
+			{
+				Kind:           deprecationKindPlugin,
+				Name:           "kustomize.example.com/v1",
+				Warning:        "use go.example.com/v2 instead",
+				RemovalVersion: "v4.0.0",
+				Link:           migrationGuideURL,
+			},
+		}))
+	})
+
+	It("should write a table", func() {
+		out := &bytes.Buffer{}
+		Expect(writeDeprecationInfos(out, outputFormatTable, c.deprecationInfos()[1:])).To(Succeed())
+		Expect(out.String()).To(Equal(
+			"KIND    NAME                      REMOVED IN  WARNING\n" +
+				"plugin  go.example.com/v1         -           use go.example.com/v2 instead\n" +
+				"plugin  kustomize.example.com/v1  v4.0.0      use go.example.com/v2 instead\n"))
+
+		out.Reset()
+		Expect(writeDeprecationInfos(out, outputFormatTable, nil)).To(Succeed())
+		Expect(out.String()).To(Equal("No deprecated plugin or project version is in use.\n"))
+	})
+
+	It("should write JSON, a list even if nothing is deprecated", func() {
+		out := &bytes.Buffer{}
+		Expect(writeDeprecationInfos(out, outputFormatJSON, c.deprecationInfos()[:1])).To(Succeed())
+		Expect(out.String()).To(MatchJSON(`[{
+			"kind": "projectVersion",
+			"name": "2",
+			"warning": "upgrade to 3-alpha",
+			"removalVersion": "v3.0.0",
+			"link": "` + migrationGuideURL + `"
+		}]`))
+
+		c = &cli{projectVersion: config.Version3Alpha}
+		out.Reset()
+		Expect(writeDeprecationInfos(out, outputFormatJSON, c.deprecationInfos())).To(Succeed())
+		Expect(out.String()).To(MatchJSON(`[]`))
+	})
+})
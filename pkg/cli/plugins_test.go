@@ -96,22 +96,156 @@ var _ = Describe("resolvePluginsByKey", func() {
 		_, err = resolvePluginsByKey(plugins, "foo.example.com/v2")
 		Expect(err).To(MatchError(errAmbiguousPlugin{
 			key: "foo.example.com/v2",
-			msg: `no versions match, possible plugins: ["foo.example.com/v1"]`,
+			msg: fmt.Sprintf(`no versions match, did you mean "foo.example.com/v1"? possible plugins: %+q`,
+				makePluginKeySlice(plugins...)),
+		}))
+
+		By("resolving fo.example.com/v1")
+		_, err = resolvePluginsByKey(plugins, "fo.example.com/v1")
+		Expect(err).To(MatchError(errAmbiguousPlugin{
+			key: "fo.example.com/v1",
+			msg: fmt.Sprintf(`no names match, did you mean "foo.example.com/v1"? possible plugins: %+q`,
+				makePluginKeySlice(plugins...)),
+		}))
+
+		By("resolving fooo/v2")
+		_, err = resolvePluginsByKey(plugins, "fooo/v2")
+		Expect(err).To(MatchError(errAmbiguousPlugin{
+			key: "fooo/v2",
+			msg: fmt.Sprintf(`no names match, did you mean "foo.kubebuilder.io/v2"? possible plugins: %+q`,
+				makePluginKeySlice(plugins...)),
+		}))
+
+		By("resolving fooo")
+		_, err = resolvePluginsByKey(plugins, "fooo")
+		Expect(err).To(MatchError(errAmbiguousPlugin{
+			key: "fooo",
+			msg: fmt.Sprintf(`no names match, did you mean "foo.example.com/v1" or "foo.kubebuilder.io/v1" or `+
+				`"foo.kubebuilder.io/v2"? possible plugins: %+q`, makePluginKeySlice(plugins...)),
 		}))
 
 		By("resolving foo/v3")
 		_, err = resolvePluginsByKey(plugins, "foo/v3")
 		Expect(err).To(MatchError(errAmbiguousPlugin{
 			key: "foo/v3",
-			msg: "no versions match, possible plugins: " +
-				`["foo.example.com/v1" "foo.kubebuilder.io/v1" "foo.kubebuilder.io/v2"]`,
+			msg: fmt.Sprintf(`no versions match, did you mean "foo.example.com/v1" or "foo.kubebuilder.io/v1" or `+
+				`"foo.kubebuilder.io/v2"? possible plugins: %+q`, makePluginKeySlice(plugins...)),
 		}))
 
 		By("resolving foo.example.com/v3")
 		_, err = resolvePluginsByKey(plugins, "foo.example.com/v3")
 		Expect(err).To(MatchError(errAmbiguousPlugin{
 			key: "foo.example.com/v3",
-			msg: `no versions match, possible plugins: ["foo.example.com/v1"]`,
+			msg: fmt.Sprintf(`no versions match, did you mean "foo.example.com/v1"? possible plugins: %+q`,
+				makePluginKeySlice(plugins...)),
+		}))
+	})
+})
+
+var _ = Describe("resolvePluginsByKey with version ranges", func() {
+
+	var (
+		plugins = makePluginsForKeys(
+			"go.kubebuilder.io/v2",
+			"go.kubebuilder.io/v3-alpha",
+			"go.kubebuilder.io/v3",
+			"go.kubebuilder.io/v4-alpha",
+			"go.example.com/v1",
+			"bar.example.com/v1",
+		)
+		resolvedPlugins []plugin.Base
+		err             error
+	)
+
+	It("should resolve the highest stable matching version", func() {
+		By("resolving go.kubebuilder.io/>=v2")
+		resolvedPlugins, err = resolvePluginsByKey(plugins, "go.kubebuilder.io/>=v2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(makePluginKeySlice(resolvedPlugins...)).To(Equal([]string{"go.kubebuilder.io/v3"}))
+
+		By("resolving go.kubebuilder.io/~v3")
+		resolvedPlugins, err = resolvePluginsByKey(plugins, "go.kubebuilder.io/~v3")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(makePluginKeySlice(resolvedPlugins...)).To(Equal([]string{"go.kubebuilder.io/v3"}))
+
+		By("resolving go.kubebuilder.io/<v3")
+		resolvedPlugins, err = resolvePluginsByKey(plugins, "go.kubebuilder.io/<v3")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(makePluginKeySlice(resolvedPlugins...)).To(Equal([]string{"go.kubebuilder.io/v2"}))
+
+		By("resolving go/>=v2")
+		resolvedPlugins, err = resolvePluginsByKey(plugins, "go/>=v2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(makePluginKeySlice(resolvedPlugins...)).To(Equal([]string{"go.kubebuilder.io/v3"}))
+
+		By("resolving bar/>=v1")
+		resolvedPlugins, err = resolvePluginsByKey(plugins, "bar/>=v1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(makePluginKeySlice(resolvedPlugins...)).To(Equal([]string{"bar.example.com/v1"}))
+	})
+
+	It("should resolve the highest unstable version if no stable version matches", func() {
+		resolvedPlugins, err = resolvePluginsByKey(plugins, "go/>v3")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(makePluginKeySlice(resolvedPlugins...)).To(Equal([]string{"go.kubebuilder.io/v4-alpha"}))
+	})
+
+	It("should return an error", func() {
+		By("resolving go/>=v1")
+		_, err = resolvePluginsByKey(plugins, "go/>=v1")
+		Expect(err).To(MatchError(errAmbiguousPlugin{
+			key: "go/>=v1",
+			msg: `matching plugins: ["go.example.com/v1" "go.kubebuilder.io/v3"]`,
+		}))
+
+		By("resolving go/>=v5")
+		_, err = resolvePluginsByKey(plugins, "go/>=v5")
+		Expect(err).To(MatchError(errAmbiguousPlugin{
+			key: "go/>=v5",
+			msg: fmt.Sprintf(`no versions match, did you mean %s? possible plugins: %+q`,
+				`"go.example.com/v1" or "go.kubebuilder.io/v2" or "go.kubebuilder.io/v3" or `+
+					`"go.kubebuilder.io/v3-alpha" or "go.kubebuilder.io/v4-alpha"`,
+				makePluginKeySlice(plugins...)),
+		}))
+	})
+
+	It("should not prefer the default plugins", func() {
+		defaultPlugins := makePluginsForKeys("go.kubebuilder.io/v3-alpha")
+		resolvedPlugins, err = resolvePluginsByKeys(defaultPlugins, plugins, []string{"go/>=v2"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(makePluginKeySlice(resolvedPlugins...)).To(Equal([]string{"go.kubebuilder.io/v3"}))
+	})
+
+	It("should explain each resolution", func() {
+		defaultPlugins := makePluginsForKeys("go.kubebuilder.io/v3-alpha")
+		var resolutions []keyResolution
+		resolvedPlugins, resolutions, err = explainPluginsByKeys(defaultPlugins, plugins, []string{"go/>=v2"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(makePluginKeySlice(resolvedPlugins...)).To(Equal([]string{"go.kubebuilder.io/v3"}))
+		Expect(resolutions).To(Equal([]keyResolution{
+			{
+				Key:        "go/>=v2",
+				Candidates: []string{"go.kubebuilder.io/v3-alpha"},
+				Steps: []string{
+					`short name "go" matches ["go.kubebuilder.io/v3-alpha"]`,
+					`version ">=v2" matches ["go.kubebuilder.io/v3-alpha"]`,
+					`the highest version of each name, stable ones first, is ["go.kubebuilder.io/v3-alpha"]`,
+					"a version range is resolved against all the plugins",
+				},
+				Resolved: "go.kubebuilder.io/v3-alpha",
+			},
+			{
+				Key:        "go/>=v2",
+				Candidates: makePluginKeySlice(plugins...),
+				Steps: []string{
+					`short name "go" matches ["go.example.com/v1" "go.kubebuilder.io/v2" "go.kubebuilder.io/v3" ` +
+						`"go.kubebuilder.io/v3-alpha" "go.kubebuilder.io/v4-alpha"]`,
+					`version ">=v2" matches ["go.kubebuilder.io/v2" "go.kubebuilder.io/v3" ` +
+						`"go.kubebuilder.io/v3-alpha" "go.kubebuilder.io/v4-alpha"]`,
+					`the highest version of each name, stable ones first, is ["go.kubebuilder.io/v3"]`,
+				},
+				Resolved: "go.kubebuilder.io/v3",
+			},
 		}))
 	})
 })
@@ -0,0 +1,415 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	"github.com/spf13/cobra"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+const (
+	renameCommandName = "rename"
+
+	kindFlag   = "kind"
+	toFlag     = "to"
+	dryRunFlag = "dry-run"
+)
+
+// renameSkipDirs are not walked when renaming a kind, as they hold binaries, dependencies or git objects.
+var renameSkipDirs = map[string]bool{
+	".git":    true,
+	"bin":     true,
+	"testbin": true,
+	"vendor":  true,
+}
+
+func (c cli) newRenameCmd() *cobra.Command {
+	var from, to string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   renameCommandName,
+		Short: "Rename the Kind of a resource of the project",
+		Long: `Rename the Kind of a resource of the project in every version of its group: the Go types, the
+reconciler, the webhooks, the files named after the Kind, the kubebuilder markers, the RBAC roles, the CRD and
+sample manifests and the PROJECT file.
+
+The Kind, its lowercase form and its plural are only replaced where they are not part of a longer word, or of
+an identifier other than the ones scaffolded after the Kind, e.g. captainOptions or newCaptainFuzzer, and
+files in .git, bin, testbin and vendor are left untouched. With --dry-run, the changes are printed as a diff
+instead of applied, which is worth doing first as any matching word gets renamed, e.g. in the README. A plural
+set with create api --plural is kept.
+
+A Kind tracked in several groups can't be renamed, as its files could not be told apart.
+`,
+		Example: fmt.Sprintf(`  # Print the changes renaming the Captain Kind to Admiral
+  %[1]s alpha %[2]s --%[3]s Captain --%[4]s Admiral --%[5]s
+
+  # Rename the Captain Kind to Admiral
+  %[1]s alpha %[2]s --%[3]s Captain --%[4]s Admiral
+`, c.commandName, renameCommandName, kindFlag, toFlag, dryRunFlag),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !c.configured {
+				return errors.New("unable to find configuration file, project must be initialized")
+			}
+			project, err := internalconfig.Load()
+			if err != nil {
+				return fmt.Errorf("failed to read config: %v", err)
+			}
+			r, err := newKindRename(&project.Config, from, to)
+			if err != nil {
+				return err
+			}
+			changes, err := r.plan(".")
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				return writeDiff(cmd.OutOrStdout(), changes)
+			}
+			if err := applyChanges(changes); err != nil {
+				return err
+			}
+			for _, change := range changes {
+				if change.path != change.newPath {
+					fmt.Fprintf(cmd.OutOrStdout(), "renamed %s to %s\n", change.path, change.newPath)
+				} else {
+					fmt.Fprintf(cmd.OutOrStdout(), "updated %s\n", change.path)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&from, kindFlag, "", "Kind of the resource to rename")
+	cmd.Flags().StringVar(&to, toFlag, "", "new Kind of the resource")
+	cmd.Flags().BoolVar(&dryRun, dryRunFlag, false, "print the changes as a diff instead of applying them")
+	return cmd
+}
+
+// kindRename renames the Kind of a resource, tracked in the project, in the files of the project.
+type kindRename struct {
+	project *config.Config
+	group   string
+	from    string
+	to      string
+	// words are replaced in order in the contents and names of the files.
+	words []wordRename
+}
+
+// newKindRename returns a kindRename of the resources with Kind from, which must be tracked in a single group,
+// to Kind to, which must be valid and not tracked in that group yet.
+func newKindRename(project *config.Config, from, to string) (kindRename, error) {
+	if from == "" || to == "" {
+		return kindRename{}, fmt.Errorf("--%s and --%s are required", kindFlag, toFlag)
+	}
+	if from == to {
+		return kindRename{}, fmt.Errorf("%s is already the Kind of the resource", to)
+	}
+	if errs := validation.IsDNS1035Label(strings.ToLower(to)); len(errs) != 0 ||
+		strings.ToLower(to[:1]) == to[:1] {
+		return kindRename{}, fmt.Errorf("invalid Kind %q, it must start with an uppercase character and "+
+			"its lowercase form must be a DNS-1035 label", to)
+	}
+
+	var groups []string
+	var plural string
+	for _, res := range project.Resources {
+		if res.Kind == from && !hasString(groups, res.Group) {
+			groups = append(groups, res.Group)
+		}
+		if res.Kind == from && res.Plural != "" {
+			plural = res.Plural
+		}
+	}
+	switch len(groups) {
+	case 0:
+		return kindRename{}, fmt.Errorf("no resource with Kind %s is tracked in the PROJECT file", from)
+	case 1:
+	default:
+		sort.Strings(groups)
+		return kindRename{}, fmt.Errorf("Kind %s is tracked in several groups (%s), it can't be renamed",
+			from, strings.Join(groups, ", "))
+	}
+	for _, res := range project.Resources {
+		if res.Kind == to && res.Group == groups[0] {
+			return kindRename{}, fmt.Errorf("Kind %s is already tracked in group %s", to, res.Group)
+		}
+	}
+
+	lowerFrom, lowerTo := strings.ToLower(from), strings.ToLower(to)
+	var words []wordRename
+	// A plural set with create api --plural is not computed from the Kind, so it is kept
+	if plural == "" {
+		words = append(words, wordRename{from: flect.Pluralize(lowerFrom), to: flect.Pluralize(lowerTo)})
+	}
+	// The identifiers scaffolded after the Kind are camel cased, e.g. captainOptions in main.go or
+	// newCaptainFuzzer and TestCaptainConversionRoundTrip in the conversion tests, and the options of the
+	// controllers of multi-group projects are prefixed with the package name of their group
+	groupPackage := strings.NewReplacer("-", "", ".", "").Replace(groups[0])
+	words = append(words,
+		wordRename{from: lowerFrom, to: lowerTo, prefixes: []string{"m", "v"},
+			suffixes: []string{"log", "Options", "FieldOwner", "Image", "DefaultImage"}},
+		wordRename{from: from, to: to, prefixes: []string{"new", "Test", groupPackage},
+			suffixes: []string{"Spec", "Status", "List", "Reconciler", "Options", "Fuzzer",
+				"ConversionRoundTrip", "HubConversionRoundTrip"}},
+	)
+	return kindRename{
+		project: project,
+		group:   groups[0],
+		from:    from,
+		to:      to,
+		words:   words,
+	}, nil
+}
+
+// rename returns s with the words renamed.
+func (r kindRename) rename(s string) string {
+	for _, w := range r.words {
+		s = w.replace(s)
+	}
+	return s
+}
+
+// renamedResources returns a copy of the resources of the project with the Kind renamed.
+func (r kindRename) renamedResources() []config.GVK {
+	resources := make([]config.GVK, 0, len(r.project.Resources))
+	for _, res := range r.project.Resources {
+		if res.Kind == r.from && res.Group == r.group {
+			res.Kind = r.to
+		}
+		resources = append(resources, res)
+	}
+	return resources
+}
+
+// fileChange is the change of the content, and maybe the path, of a file.
+type fileChange struct {
+	path       string
+	newPath    string
+	content    []byte
+	newContent []byte
+	mode       os.FileMode
+}
+
+// plan returns the changes renaming the Kind in the files under root, with the one of the PROJECT file,
+// sorted by path. Binary files and the directories in renameSkipDirs are left untouched.
+func (r kindRename) plan(root string) ([]fileChange, error) {
+	var changes []fileChange
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root && renameSkipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || path == filepath.Join(root, internalconfig.DefaultPath) {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(content, 0) != -1 {
+			return nil
+		}
+		change := fileChange{
+			path:       path,
+			newPath:    filepath.Join(filepath.Dir(path), r.rename(info.Name())),
+			content:    content,
+			newContent: []byte(r.rename(string(content))),
+			mode:       info.Mode(),
+		}
+		if change.newPath == path && bytes.Equal(change.newContent, content) {
+			return nil
+		}
+		if change.newPath != path {
+			if _, err := os.Stat(change.newPath); err == nil {
+				return fmt.Errorf("unable to rename %s, %s already exists", path, change.newPath)
+			}
+		}
+		changes = append(changes, change)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(root, internalconfig.DefaultPath)
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	renamed := *r.project
+	renamed.Resources = r.renamedResources()
+	newContent, err := renamed.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, fileChange{
+		path: path, newPath: path, content: content, newContent: newContent, mode: 0600,
+	})
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].path < changes[j].path })
+	return changes, nil
+}
+
+// applyChanges writes the changes, removing the files that were renamed.
+func applyChanges(changes []fileChange) error {
+	for _, change := range changes {
+		if err := ioutil.WriteFile(change.newPath, change.newContent, change.mode); err != nil {
+			return err
+		}
+		if change.newPath != change.path {
+			if err := os.Remove(change.path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeDiff writes the changes as a unified diff. Renaming words never adds or removes lines, so the
+// lines are compared one to one and only the changed ones are written.
+func writeDiff(out io.Writer, changes []fileChange) error {
+	var b strings.Builder
+	for _, change := range changes {
+		fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", filepath.ToSlash(change.path), filepath.ToSlash(change.newPath))
+		lines := strings.SplitAfter(string(change.content), "\n")
+		newLines := strings.SplitAfter(string(change.newContent), "\n")
+		if len(lines) != len(newLines) {
+			// The PROJECT file is marshalled again, so it may differ in more than the renamed words
+			writeHunk(&b, 1, lines, newLines)
+			continue
+		}
+		for i := 0; i < len(lines); i++ {
+			if lines[i] == newLines[i] {
+				continue
+			}
+			end := i + 1
+			for end < len(lines) && lines[end] != newLines[end] {
+				end++
+			}
+			writeHunk(&b, i+1, lines[i:end], newLines[i:end])
+			i = end
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// writeHunk writes a hunk replacing lines, starting at line start, with newLines.
+func writeHunk(b *strings.Builder, start int, lines, newLines []string) {
+	lines, newLines = withoutEmptyLast(lines), withoutEmptyLast(newLines)
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", start, len(lines), start, len(newLines))
+	for _, line := range lines {
+		b.WriteString("-" + strings.TrimSuffix(line, "\n") + "\n")
+	}
+	for _, line := range newLines {
+		b.WriteString("+" + strings.TrimSuffix(line, "\n") + "\n")
+	}
+}
+
+// withoutEmptyLast drops the empty element strings.SplitAfter returns after a trailing newline.
+func withoutEmptyLast(lines []string) []string {
+	if len(lines) != 0 && lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// wordRename renames a word where it is not part of a longer one. The word may still be preceded by one of
+// the prefixes, or followed by one of the suffixes, e.g. to rename the Go types named after a Kind.
+type wordRename struct {
+	from     string
+	to       string
+	prefixes []string
+	suffixes []string
+}
+
+// replace returns s with from replaced by to.
+func (w wordRename) replace(s string) string {
+	var b strings.Builder
+	last := 0
+	for i := strings.Index(s, w.from); i != -1; {
+		end := i + len(w.from)
+		if w.isWord(s[:i], s[end:]) {
+			b.WriteString(s[last:i])
+			b.WriteString(w.to)
+			last = end
+		}
+		next := strings.Index(s[end:], w.from)
+		if next == -1 {
+			break
+		}
+		i = end + next
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// isWord returns true if the text before and after an occurrence of the word do not continue it.
+func (w wordRename) isWord(before, after string) bool {
+	startsWord := func(s string) bool { return s == "" || !isAlphanumeric(s[len(s)-1]) }
+	endsWord := func(s string) bool { return s == "" || !isAlphanumeric(s[0]) }
+
+	start := startsWord(before)
+	for _, prefix := range w.prefixes {
+		if !start && strings.HasSuffix(before, prefix) {
+			start = startsWord(strings.TrimSuffix(before, prefix))
+		}
+	}
+	end := endsWord(after)
+	for _, suffix := range w.suffixes {
+		if !end && strings.HasPrefix(after, suffix) {
+			end = endsWord(strings.TrimPrefix(after, suffix))
+		}
+	}
+	return start && end
+}
+
+// hasString returns true if s is one of the strings.
+func hasString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isAlphanumeric returns true if c is an ASCII letter or digit.
+func isAlphanumeric(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+var _ = Describe("wordRename", func() {
+	It("should only rename whole words, with the prefixes and suffixes", func() {
+		w := wordRename{from: "Captain", to: "Admiral", prefixes: []string{"m"}, suffixes: []string{"List"}}
+		Expect(w.replace("Captain CaptainList mCaptain CaptainShip SeaCaptain Captains xmCaptain")).
+			To(Equal("Admiral AdmiralList mAdmiral CaptainShip SeaCaptain Captains xmCaptain"))
+	})
+})
+
+var _ = Describe("kindRename", func() {
+	var (
+		project *config.Config
+		root    string
+	)
+
+	BeforeEach(func() {
+		project = &config.Config{
+			Version: config.Version3Alpha,
+			Domain:  "testproject.org",
+			Repo:    "example.com/project",
+			Resources: []config.GVK{
+				{Group: "crew", Version: "v1", Kind: "Captain"},
+				{Group: "crew", Version: "v2", Kind: "Captain"},
+				{Group: "crew", Version: "v1", Kind: "FirstMate"},
+			},
+		}
+
+		var err error
+		root, err = ioutil.TempDir("", "rename")
+		Expect(err).NotTo(HaveOccurred())
+		content, err := project.Marshal()
+		Expect(err).NotTo(HaveOccurred())
+		files := map[string]string{
+			internalconfig.DefaultPath: string(content),
+			"api/v1/captain_types.go":  "// CaptainSpec defines the desired state of Captain\ntype CaptainSpec struct{}\n",
+			"controllers/captain_controller.go": "// +kubebuilder:rbac:groups=crew.testproject.org,resources=captains," +
+				"verbs=get\nfunc (r *CaptainReconciler) Reconcile() {}\n",
+			"config/samples/crew_v1_captain.yaml":   "kind: Captain\n",
+			"config/samples/crew_v1_firstmate.yaml": "kind: FirstMate\n",
+			"bin/manager":                           "Captain\x00",
+		}
+		for path, content := range files {
+			path = filepath.Join(root, path)
+			Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(root)).To(Succeed())
+	})
+
+	It("should rename the Kind in the files and the PROJECT file", func() {
+		r, err := newKindRename(project, "Captain", "Admiral")
+		Expect(err).NotTo(HaveOccurred())
+		changes, err := r.plan(root)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(HaveLen(4))
+		Expect(applyChanges(changes)).To(Succeed())
+
+		read := func(path string) string {
+			b, err := ioutil.ReadFile(filepath.Join(root, path))
+			Expect(err).NotTo(HaveOccurred())
+			return string(b)
+		}
+		Expect(read("api/v1/admiral_types.go")).To(Equal(
+			"// AdmiralSpec defines the desired state of Admiral\ntype AdmiralSpec struct{}\n"))
+		Expect(read("controllers/admiral_controller.go")).To(Equal(
+			"// +kubebuilder:rbac:groups=crew.testproject.org,resources=admirals," +
+				"verbs=get\nfunc (r *AdmiralReconciler) Reconcile() {}\n"))
+		Expect(read("config/samples/crew_v1_admiral.yaml")).To(Equal("kind: Admiral\n"))
+		Expect(read("config/samples/crew_v1_firstmate.yaml")).To(Equal("kind: FirstMate\n"))
+		Expect(read("bin/manager")).To(Equal("Captain\x00"))
+		_, err = os.Stat(filepath.Join(root, "api/v1/captain_types.go"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		renamed, err := internalconfig.ReadFrom(filepath.Join(root, internalconfig.DefaultPath))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(renamed.Resources).To(Equal([]config.GVK{
+			{Group: "crew", Version: "v1", Kind: "Admiral"},
+			{Group: "crew", Version: "v2", Kind: "Admiral"},
+			{Group: "crew", Version: "v1", Kind: "FirstMate"},
+		}))
+		// The configuration is only updated once the changes are applied
+		Expect(project.Resources[0].Kind).To(Equal("Captain"))
+	})
+
+	It("should rename the camel cased identifiers scaffolded after the Kind", func() {
+		files := map[string]string{
+			"api/v1/captain_conversion_test.go": "func newCaptainFuzzer(t *testing.T) *fuzz.Fuzzer {}\n" +
+				"func TestCaptainConversionRoundTrip(t *testing.T) { f := newCaptainFuzzer(t) }\n" +
+				"func TestCaptainHubConversionRoundTrip(t *testing.T) { f := newCaptainFuzzer(t) }\n",
+			"api/v1/captain_webhook_test.go": "func newCaptain(spec CaptainSpec) *Captain {}\n" +
+				"func TestCaptainShip(t *testing.T) {}\n",
+			"main.go": "captainOptions := controllers.ReconcilerOptions{}\n" +
+				"crewCaptainOptions := crewcontrollers.ReconcilerOptions{}\nrenewCaptain()\n",
+		}
+		for path, content := range files {
+			Expect(ioutil.WriteFile(filepath.Join(root, path), []byte(content), 0644)).To(Succeed())
+		}
+
+		r, err := newKindRename(project, "Captain", "Admiral")
+		Expect(err).NotTo(HaveOccurred())
+		changes, err := r.plan(root)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applyChanges(changes)).To(Succeed())
+
+		read := func(path string) string {
+			b, err := ioutil.ReadFile(filepath.Join(root, path))
+			Expect(err).NotTo(HaveOccurred())
+			return string(b)
+		}
+		Expect(read("api/v1/admiral_conversion_test.go")).To(Equal(
+			"func newAdmiralFuzzer(t *testing.T) *fuzz.Fuzzer {}\n" +
+				"func TestAdmiralConversionRoundTrip(t *testing.T) { f := newAdmiralFuzzer(t) }\n" +
+				"func TestAdmiralHubConversionRoundTrip(t *testing.T) { f := newAdmiralFuzzer(t) }\n"))
+		Expect(read("api/v1/admiral_webhook_test.go")).To(Equal(
+			"func newAdmiral(spec AdmiralSpec) *Admiral {}\nfunc TestCaptainShip(t *testing.T) {}\n"))
+		Expect(read("main.go")).To(Equal("admiralOptions := controllers.ReconcilerOptions{}\n" +
+			"crewAdmiralOptions := crewcontrollers.ReconcilerOptions{}\nrenewCaptain()\n"))
+	})
+
+	It("should print the changes as a diff", func() {
+		r, err := newKindRename(project, "Captain", "Admiral")
+		Expect(err).NotTo(HaveOccurred())
+		changes, err := r.plan(root)
+		Expect(err).NotTo(HaveOccurred())
+		out := &bytes.Buffer{}
+		Expect(writeDiff(out, changes)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(
+			"--- a/" + filepath.ToSlash(filepath.Join(root, "config/samples/crew_v1_captain.yaml")) + "\n" +
+				"+++ b/" + filepath.ToSlash(filepath.Join(root, "config/samples/crew_v1_admiral.yaml")) + "\n" +
+				"@@ -1,1 +1,1 @@\n-kind: Captain\n+kind: Admiral\n"))
+	})
+
+	It("should fail if the Kind can't be renamed", func() {
+		_, err := newKindRename(project, "Kraken", "Leviathan")
+		Expect(err).To(MatchError("no resource with Kind Kraken is tracked in the PROJECT file"))
+		_, err = newKindRename(project, "Captain", "FirstMate")
+		Expect(err).To(MatchError("Kind FirstMate is already tracked in group crew"))
+		_, err = newKindRename(project, "Captain", "admiral")
+		Expect(err).To(HaveOccurred())
+
+		project.Resources = append(project.Resources, config.GVK{Group: "ship", Version: "v1", Kind: "Captain"})
+		_, err = newKindRename(project, "Captain", "Admiral")
+		Expect(err).To(MatchError("Kind Captain is tracked in several groups (crew, ship), it can't be renamed"))
+	})
+})
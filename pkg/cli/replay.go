@@ -0,0 +1,207 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+const (
+	replayCommandName = "replay"
+
+	historyFileFlag = "history-file"
+	outputDirFlag   = "output-dir"
+	initPluginsFlag = "init-plugins"
+)
+
+func (c cli) newReplayCmd() *cobra.Command {
+	var (
+		historyFile string
+		outputDir   string
+		initPlugins []string
+		initFlags   map[string]string
+		dryRun      bool
+	)
+	cmd := &cobra.Command{
+		Use:   replayCommandName,
+		Short: "Run the command history of a project again in a new directory",
+		Long: `Run the scaffolding commands recorded in the history of the PROJECT file again, in order, in a new
+directory, e.g. to regenerate the project with newer plugins and then move the code of the user over to it.
+
+The history is read from the PROJECT file of the current directory, or from the file set with --history-file,
+whose 'history' section lists the commands as the PROJECT file records them. The first command must be init,
+its plugins being replaced with --init-plugins and its flags set with --init-flag, e.g. for an option the newer
+plugins add or rename. The project name recorded in the file is passed to init unless it was set, so that the
+names derived from it do not depend on the output directory. The global flags set on replay are passed to every
+command. With --dry-run, the commands are printed instead of run.
+
+Only the commands that scaffolded and were not run with --no-history are recorded, so the history of a project
+initialized before it was recorded is empty and the project can not be replayed.
+`,
+		Example: fmt.Sprintf(`  # Scaffold the project again in ../project-v2
+  %[1]s alpha %[2]s --%[3]s ../project-v2
+
+  # Print the commands scaffolding the project again with newer plugins
+  %[1]s alpha %[2]s --%[3]s ../project-v2 --%[4]s go/v3-alpha --%[5]s
+`, c.commandName, replayCommandName, outputDirFlag, initPluginsFlag, dryRunFlag),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if outputDir == "" {
+				return fmt.Errorf("--%s is required", outputDirFlag)
+			}
+			if historyFile == "" {
+				if !c.configured {
+					return errors.New("unable to find configuration file, " +
+						"project must be initialized or a history file must be provided")
+				}
+				historyFile = internalconfig.DefaultPath
+			}
+			project, err := internalconfig.ReadFrom(historyFile)
+			if err != nil {
+				return fmt.Errorf("failed to read the history: %v", err)
+			}
+			commands, err := replayCommands(*project, initPlugins, initFlags)
+			if err != nil {
+				return err
+			}
+			commands = appendInheritedFlags(commands, cmd)
+			if dryRun {
+				for _, args := range commands {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), formatCommand(c.commandName, args))
+				}
+				return nil
+			}
+
+			if err := createEmptyDir(outputDir); err != nil {
+				return err
+			}
+			executable, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("unable to find the %s executable: %v", c.commandName, err)
+			}
+			return runCommands(cmd.OutOrStdout(), c.commandName, commands, func(args []string) error {
+				command := exec.Command(executable, args...)
+				command.Dir, command.Stdout, command.Stderr = outputDir, cmd.OutOrStdout(), cmd.ErrOrStderr()
+				return command.Run()
+			})
+		},
+	}
+	cmd.Flags().StringVar(&historyFile, historyFileFlag, "",
+		"file with the history to replay, e.g. the PROJECT file of another project, defaults to the PROJECT file")
+	cmd.Flags().StringVar(&outputDir, outputDirFlag, "",
+		"directory the project is scaffolded in, which must not exist or be empty")
+	cmd.Flags().StringSliceVar(&initPlugins, initPluginsFlag, nil,
+		"plugin keys replacing the plugins of the recorded init command, e.g. go/v3-alpha")
+	cmd.Flags().StringToStringVar(&initFlags, initFlagFlag, nil,
+		"flag set on the recorded init command, as <name>=<value>, overriding the recorded one, "+
+			"e.g. project-version=3-alpha")
+	cmd.Flags().BoolVar(&dryRun, dryRunFlag, false, "print the commands instead of running them")
+	return cmd
+}
+
+// replayCommands returns the arguments of the commands recorded in the history of cfg, in order, the init one
+// being run with plugins, if any, and with initFlags set.
+func replayCommands(cfg config.Config, plugins []string, initFlags map[string]string) ([][]string, error) {
+	if len(cfg.History) == 0 {
+		return nil, errors.New("the history is empty, only the commands of version 3-alpha projects are recorded")
+	}
+	if cfg.History[0].Command != "init" {
+		return nil, fmt.Errorf("the history must start with init, found %q", cfg.History[0].Command)
+	}
+
+	flags := make(map[string]string, len(initFlags)+2)
+	for name, value := range initFlags {
+		flags[name] = value
+	}
+	if len(plugins) != 0 {
+		flags[pluginsFlag] = strings.Join(plugins, ",")
+	}
+	if cfg.ProjectName != "" && !hasFlag(cfg.History[0].Flags, "project-name") {
+		if _, isSet := flags["project-name"]; !isSet {
+			flags["project-name"] = cfg.ProjectName
+		}
+	}
+
+	commands := make([][]string, 0, len(cfg.History))
+	for i, recorded := range cfg.History {
+		args := append(strings.Fields(recorded.Command), recorded.Flags...)
+		if i == 0 {
+			args = setFlags(args, flags)
+		}
+		commands = append(commands, args)
+	}
+	return commands, nil
+}
+
+// hasFlag returns if args set the flag name as --name=value.
+func hasFlag(args []string, name string) bool {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--"+name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// setFlags returns args with each of flags set as --key=value, sorted by key, instead of the values args set.
+func setFlags(args []string, flags map[string]string) []string {
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kept := make([]string, 0, len(args)+len(keys))
+	for _, arg := range args {
+		overridden := false
+		for _, key := range keys {
+			if strings.HasPrefix(arg, "--"+key+"=") {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			kept = append(kept, arg)
+		}
+	}
+	return appendFlags(kept, flags)
+}
+
+// createEmptyDir creates dir, which may already exist if it is empty.
+func createEmptyDir(dir string) error {
+	infos, err := ioutil.ReadDir(dir)
+	switch {
+	case os.IsNotExist(err):
+		return os.MkdirAll(dir, 0755)
+	case err != nil:
+		return fmt.Errorf("unable to read the output directory: %v", err)
+	case len(infos) != 0:
+		return fmt.Errorf("the output directory %s is not empty", dir)
+	}
+	return nil
+}
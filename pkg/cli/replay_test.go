@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+var _ = Describe("alpha replay", func() {
+	var cfg config.Config
+
+	BeforeEach(func() {
+		cfg = config.Config{
+			Version:     config.Version3Alpha,
+			ProjectName: "project",
+			History: []config.RecordedCommand{
+				{Command: "init", Flags: []string{"--domain=my.domain", "--plugins=go/v2", "--repo=example.com/project"}},
+				{Command: "create api", Flags: []string{"--group=crew", "--kind=Captain", "--version=v1"}},
+			},
+		}
+	})
+
+	It("should run the recorded commands in order, with the recorded project name", func() {
+		Expect(replayCommands(cfg, nil, nil)).To(Equal([][]string{
+			{"init", "--domain=my.domain", "--plugins=go/v2", "--repo=example.com/project", "--project-name=project"},
+			{"create", "api", "--group=crew", "--kind=Captain", "--version=v1"},
+		}))
+	})
+
+	It("should replace the plugins and set the flags of init", func() {
+		cfg.History[0].Flags = append(cfg.History[0].Flags, "--project-name=crew")
+		commands, err := replayCommands(cfg, []string{"go/v3-alpha"}, map[string]string{
+			"domain":          "example.org",
+			"project-version": "3-alpha",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(commands[0]).To(Equal([]string{
+			"init", "--repo=example.com/project", "--project-name=crew",
+			"--domain=example.org", "--plugins=go/v3-alpha", "--project-version=3-alpha",
+		}))
+		Expect(commands[1]).To(Equal([]string{"create", "api", "--group=crew", "--kind=Captain", "--version=v1"}))
+	})
+
+	It("should only replay histories starting with init", func() {
+		_, err := replayCommands(config.Config{Version: config.Version2}, nil, nil)
+		Expect(err).To(MatchError(ContainSubstring("the history is empty")))
+
+		cfg.History = cfg.History[1:]
+		_, err = replayCommands(cfg, nil, nil)
+		Expect(err).To(MatchError(`the history must start with init, found "create api"`))
+	})
+
+	It("should only scaffold in an empty directory", func() {
+		tmp, err := ioutil.TempDir("", "replay")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmp)
+
+		dir := filepath.Join(tmp, "project")
+		Expect(createEmptyDir(dir)).To(Succeed())
+		Expect(dir).To(BeADirectory())
+		Expect(createEmptyDir(dir)).To(Succeed())
+
+		Expect(ioutil.WriteFile(filepath.Join(dir, "PROJECT"), nil, 0644)).To(Succeed())
+		Expect(createEmptyDir(dir)).To(MatchError(ContainSubstring("is not empty")))
+	})
+})
@@ -0,0 +1,356 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/tools"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	runCommandName = "run"
+
+	webhookHostFlag     = "webhook-host"
+	disableWebhooksFlag = "disable-webhooks"
+
+	// defaultWebhookPort is the port of the webhook server of controller-runtime
+	defaultWebhookPort = 9443
+	// servingCertValidity is how long the serving certificate of the local webhook server is valid for
+	servingCertValidity = 7 * 24 * time.Hour
+)
+
+var (
+	// webhookManifests are the webhook configurations generated by controller-gen
+	webhookManifests = filepath.Join("config", "webhook", "manifests.yaml")
+
+	kustomizationNamePrefixRegexp = regexp.MustCompile(`(?m)^namePrefix: (\S+)`)
+	// The following regexps match the options of the webhook server set in main.go
+	mainWebhookPortRegexp    = regexp.MustCompile(`(?m)^\s+Port:\s+([0-9]+),`)
+	mainWebhookCertDirRegexp = regexp.MustCompile(`(?m)^\s+CertDir:\s+"([^"]+)",`)
+)
+
+// runOptions are the options of the run command.
+type runOptions struct {
+	deployOptions
+	// webhookHost is the host the API server reaches the webhook server of the local manager at
+	webhookHost     string
+	disableWebhooks bool
+}
+
+func (c cli) newRunCmd() *cobra.Command {
+	opts := runOptions{}
+	cmd := &cobra.Command{
+		Use:   runCommandName + " [-- manager flags]",
+		Short: "Run the manager locally against a cluster",
+		Long: `Run the manager locally against the cluster of the current kubectl context, the way the run target of
+the Makefile does, the arguments after -- being passed to the manager. The CRDs must have been installed, e.g.
+by 'make install'.
+
+The API server cannot reach the webhooks of a manager that runs locally through the webhook configurations of
+config/webhook, which refer to its service. If the project has webhooks, either:
+
+- --webhook-host is set to the host or IP address the API server reaches this machine at, e.g.
+  host.docker.internal for a kind cluster of Docker Desktop. A temporary self-signed serving certificate is
+  generated for it and the webhook configurations are applied, with the prefix of the names of the manifests of
+  config/default, pointing at the webhook server of the manager on that host with this certificate, instead of
+  the service. They are deleted when the manager exits, so the deployed ones must be applied again, e.g. with
+  the deploy command.
+- --disable-webhooks is set, the manager skipping the setup of its webhooks when its ENABLE_WEBHOOKS
+  environment variable is false, as the one scaffolded does.
+
+The webhook configurations are the ones generated into config/webhook/manifests.yaml by 'make manifests'.
+`,
+		Example: fmt.Sprintf(`  # Run the manager with its webhooks served to a kind cluster of Docker Desktop
+  %[1]s %[2]s --%[3]s host.docker.internal
+
+  # Run the manager without its webhooks against the kind-dev context, with a flag of the manager
+  %[1]s %[2]s --%[4]s --%[5]s kind-dev -- --metrics-addr=:8081
+`, c.commandName, runCommandName, webhookHostFlag, disableWebhooksFlag, contextFlag),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !c.configured {
+				return errors.New("unable to find configuration file, project must be initialized")
+			}
+			if _, err := internalconfig.Load(); err != nil {
+				return fmt.Errorf("failed to read config: %v", err)
+			}
+			return runLocally(cmd, opts, args)
+		},
+	}
+	opts.bindClusterFlags(cmd, false)
+	cmd.Flags().StringVar(&opts.webhookHost, webhookHostFlag, "",
+		"host or IP address the API server reaches this machine at, to serve the webhooks of the manager to")
+	cmd.Flags().BoolVar(&opts.disableWebhooks, disableWebhooksFlag, false,
+		"run the manager without its webhooks, setting its ENABLE_WEBHOOKS environment variable to false")
+	return cmd
+}
+
+// runLocally builds the manager and runs it with args, serving its webhooks locally or disabling them.
+func runLocally(cmd *cobra.Command, opts runOptions, args []string) error {
+	out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+	mainGo, err := ioutil.ReadFile("main.go")
+	if err != nil {
+		return fmt.Errorf("unable to read the main.go of the manager: %v", err)
+	}
+	manifests, err := ioutil.ReadFile(webhookManifests)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	hasWebhooks := bytes.Contains(manifests, []byte("WebhookConfiguration"))
+	if hasWebhooks && !opts.disableWebhooks && opts.webhookHost == "" {
+		return fmt.Errorf("the project has webhooks, set --%s to serve them from this machine or --%s",
+			webhookHostFlag, disableWebhooksFlag)
+	}
+	if hasWebhooks && opts.disableWebhooks && bytes.Contains(mainGo, []byte("SetupWebhookWithManager")) &&
+		!bytes.Contains(mainGo, []byte("ENABLE_WEBHOOKS")) {
+		return errors.New("the webhooks of the manager cannot be disabled, as main.go sets them up regardless " +
+			"of its ENABLE_WEBHOOKS environment variable")
+	}
+
+	tmp, err := ioutil.TempDir("", "kubebuilder-run-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	steps := []toolStep{{name: "go", args: []string{"build", "-o", filepath.Join(localBin, "manager"), "main.go"}}}
+	env := make([]string, 0, 3)
+	switch {
+	case opts.context != "":
+		// The manager is run with a kubeconfig of the context only, as it has no flag to select one
+		kubeconfig := filepath.Join(tmp, "kubeconfig")
+		steps = append(steps, toolStep{name: "kubectl", args: opts.kubectl("config", "view", "--minify",
+			"--flatten").args, output: kubeconfig})
+		env = append(env, "KUBECONFIG="+kubeconfig)
+	case opts.kubeconfig != "":
+		env = append(env, "KUBECONFIG="+opts.kubeconfig)
+	}
+	if err := runSteps(out, errOut, steps, false); err != nil {
+		return err
+	}
+
+	switch {
+	case !hasWebhooks:
+	case opts.disableWebhooks:
+		env = append(env, "ENABLE_WEBHOOKS=false")
+	default:
+		certDir, port := webhookServerSettings(string(mainGo))
+		// Without a certificate directory set in main.go, controller-runtime reads the certificate from the
+		// temporary directory of the manager
+		if certDir == "" {
+			certDir = filepath.Join(tmp, "k8s-webhook-server", "serving-certs")
+			env = append(env, "TMPDIR="+tmp)
+		}
+		cert, key, err := generateServingCert(opts.webhookHost, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := writeServingCert(certDir, cert, key); err != nil {
+			return err
+		}
+
+		kustomization, _ := ioutil.ReadFile(filepath.Join(defaultKustomization, "kustomization.yaml"))
+		namePrefix := ""
+		if m := kustomizationNamePrefixRegexp.FindSubmatch(kustomization); m != nil {
+			namePrefix = string(m[1])
+		}
+		url := "https://" + net.JoinHostPort(opts.webhookHost, strconv.Itoa(port))
+		configurations, err := localWebhookConfigurations(manifests, namePrefix, url, cert)
+		if err != nil {
+			return err
+		}
+		if err := kubectlWithInput(out, errOut, opts.kubectl("apply", "-f", "-"), configurations); err != nil {
+			return err
+		}
+		defer func() {
+			err := kubectlWithInput(out, errOut, opts.kubectl("delete", "--ignore-not-found", "-f", "-"),
+				configurations)
+			if err != nil {
+				_, _ = fmt.Fprintf(errOut, "unable to delete the local webhook configurations: %v\n", err)
+			}
+		}()
+	}
+
+	return runManager(out, errOut, filepath.Join(localBin, "manager"), args, env)
+}
+
+// runManager runs the manager binary with args and the environment variables env until it exits, relaying the
+// interrupt and termination signals to it.
+func runManager(out, errOut io.Writer, binary string, args, env []string) error {
+	manager := tools.Default().Command(binary, args...)
+	manager.Env = append(manager.Env, env...)
+	manager.Stdout, manager.Stderr = out, errOut
+	_, _ = fmt.Fprintf(out, "$ %s\n", formatCommand(binary, args))
+	if err := manager.Start(); err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	done := make(chan error, 1)
+	go func() { done <- manager.Wait() }()
+	for {
+		select {
+		case sig := <-signals:
+			_ = manager.Process.Signal(sig)
+		case err := <-done:
+			if err != nil {
+				return fmt.Errorf("the manager exited: %v", err)
+			}
+			return nil
+		}
+	}
+}
+
+// kubectlWithInput runs step, a kubectl command, with input as standard input.
+func kubectlWithInput(out, errOut io.Writer, step toolStep, input []byte) error {
+	_, _ = fmt.Fprintf(out, "$ %s\n", step)
+	command := tools.Default().Command(step.name, step.args...)
+	command.Stdin, command.Stdout, command.Stderr = bytes.NewReader(input), out, errOut
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("%s failed: %v", step, err)
+	}
+	return nil
+}
+
+// webhookServerSettings returns the certificate directory and the port of the webhook server set in mainGo, the
+// directory being empty and the port the one of controller-runtime if they are not set.
+func webhookServerSettings(mainGo string) (string, int) {
+	certDir, port := "", defaultWebhookPort
+	if m := mainWebhookCertDirRegexp.FindStringSubmatch(mainGo); m != nil {
+		certDir = m[1]
+	}
+	if m := mainWebhookPortRegexp.FindStringSubmatch(mainGo); m != nil {
+		if p, err := strconv.Atoi(m[1]); err == nil {
+			port = p
+		}
+	}
+	return certDir, port
+}
+
+// generateServingCert returns a self-signed serving certificate for host, valid from now, and its key, PEM encoded.
+// The certificate is its own CA, so that it is also the CA bundle of the webhook configurations.
+func generateServingCert(host string, now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: host},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(servingCertValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to generate the serving certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+// writeServingCert writes cert and key into dir, with the names controller-runtime reads them from.
+func writeServingCert(dir string, cert, key []byte) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "tls.crt"), cert, 0600); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "tls.key"), key, 0600)
+}
+
+// localWebhookConfigurations returns the webhook configurations of manifests, their names prefixed with
+// namePrefix, pointing at the webhook server at url, whose certificate is caBundle, instead of a service.
+func localWebhookConfigurations(manifests []byte, namePrefix, url string, caBundle []byte) ([]byte, error) {
+	var configurations bytes.Buffer
+	for _, document := range strings.Split(string(manifests), "\n---\n") {
+		if strings.TrimSpace(strings.TrimPrefix(document, "---\n")) == "" {
+			continue
+		}
+		var object map[string]interface{}
+		if err := yaml.Unmarshal([]byte(document), &object); err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %v", webhookManifests, err)
+		}
+		metadata, _ := object["metadata"].(map[string]interface{})
+		webhooks, _ := object["webhooks"].([]interface{})
+		if metadata == nil {
+			return nil, fmt.Errorf("a manifest of %s has no metadata", webhookManifests)
+		}
+		metadata["name"] = fmt.Sprintf("%s%v", namePrefix, metadata["name"])
+		delete(metadata, "creationTimestamp")
+		for _, w := range webhooks {
+			webhook, _ := w.(map[string]interface{})
+			clientConfig, _ := webhook["clientConfig"].(map[string]interface{})
+			service, _ := clientConfig["service"].(map[string]interface{})
+			if service == nil {
+				return nil, fmt.Errorf("the webhook %v of %s has no service", webhook["name"], webhookManifests)
+			}
+			path, _ := service["path"].(string)
+			webhook["clientConfig"] = map[string]interface{}{"url": url + path, "caBundle": caBundle}
+		}
+		b, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		configurations.WriteString("---\n")
+		configurations.Write(b)
+	}
+	return configurations.Bytes(), nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("run", func() {
+	It("should generate a serving certificate for a host name or an IP address", func() {
+		now := time.Now()
+		for _, host := range []string{"host.docker.internal", "172.17.0.1"} {
+			cert, key, err := generateServingCert(host, now)
+			Expect(err).NotTo(HaveOccurred())
+			pair, err := tls.X509KeyPair(cert, key)
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed, err := x509.ParseCertificate(pair.Certificate[0])
+			Expect(err).NotTo(HaveOccurred())
+			roots := x509.NewCertPool()
+			Expect(roots.AppendCertsFromPEM(cert)).To(BeTrue())
+			_, err = parsed.Verify(x509.VerifyOptions{DNSName: host, Roots: roots, CurrentTime: now})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should point the webhook configurations at the local webhook server", func() {
+		const manifests = `
+---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: MutatingWebhookConfiguration
+metadata:
+  creationTimestamp: null
+  name: mutating-webhook-configuration
+webhooks:
+- clientConfig:
+    caBundle: Cg==
+    service:
+      name: webhook-service
+      namespace: system
+      path: /mutate-crew-testproject-org-v1-captain
+  failurePolicy: Fail
+  name: mcaptain.kb.io
+
+---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  creationTimestamp: null
+  name: validating-webhook-configuration
+webhooks:
+- clientConfig:
+    caBundle: Cg==
+    service:
+      name: webhook-service
+      namespace: system
+      path: /validate-crew-testproject-org-v1-captain
+  failurePolicy: Fail
+  name: vcaptain.kb.io
+`
+		configurations, err := localWebhookConfigurations([]byte(manifests), "project-",
+			"https://host.docker.internal:9443", []byte("cert"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(configurations)).To(Equal(`---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: project-mutating-webhook-configuration
+webhooks:
+- clientConfig:
+    caBundle: Y2VydA==
+    url: https://host.docker.internal:9443/mutate-crew-testproject-org-v1-captain
+  failurePolicy: Fail
+  name: mcaptain.kb.io
+---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: project-validating-webhook-configuration
+webhooks:
+- clientConfig:
+    caBundle: Y2VydA==
+    url: https://host.docker.internal:9443/validate-crew-testproject-org-v1-captain
+  failurePolicy: Fail
+  name: vcaptain.kb.io
+`))
+	})
+
+	It("should find the settings of the webhook server in main.go", func() {
+		certDir, port := webhookServerSettings("\tmgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{\n" +
+			"\t\tPort:               9443,\n")
+		Expect(certDir).To(BeEmpty())
+		Expect(port).To(Equal(9443))
+
+		certDir, port = webhookServerSettings("\t\tPort:               8443,\n" +
+			"\t\tCertDir:            \"/etc/webhook/certs\",\n")
+		Expect(certDir).To(Equal("/etc/webhook/certs"))
+		Expect(port).To(Equal(8443))
+	})
+})
@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/pkg/tools"
+)
+
+const (
+	setupEnvtestCommandName = "setup-envtest"
+
+	k8sVersionFlag = "k8s-version"
+	binDirFlag     = "bin-dir"
+	assetsURLFlag  = "assets-url"
+	printFlag      = "print"
+
+	printFormatPath = "path"
+	printFormatEnv  = "env"
+
+	// defaultEnvtestAssetsURL is where the kubebuilder-tools archives with the envtest binaries are published.
+	defaultEnvtestAssetsURL = "https://storage.googleapis.com/kubebuilder-tools"
+)
+
+// envtestBinaries are the binaries envtest needs to run a control plane.
+var envtestBinaries = []string{"etcd", "kube-apiserver", "kubectl"}
+
+// envtestAssets downloads the binaries used by envtest.
+type envtestAssets struct {
+	client *http.Client
+	// url is the URL the kubebuilder-tools archives are downloaded from.
+	url string
+	// binDir is the directory the binaries are written to, in a subdirectory per version and platform.
+	binDir string
+}
+
+func (c cli) newSetupEnvtestCmd() *cobra.Command {
+	assets := envtestAssets{client: http.DefaultClient}
+	var version, format string
+	cmd := &cobra.Command{
+		Use:   setupEnvtestCommandName,
+		Short: "Download the binaries used by envtest to run the tests of the project",
+		Long: `Download the etcd, kube-apiserver and kubectl binaries used by envtest for a Kubernetes version, unless
+they already were, and print the directory they are in.
+
+envtest, which the controller tests are based on, finds them through the KUBEBUILDER_ASSETS environment
+variable. With --print=env, the command exporting it is printed instead.
+`,
+		Example: fmt.Sprintf(`  # Download the default Kubernetes version into testbin/ and run the tests
+  KUBEBUILDER_ASSETS=$(%[1]s alpha %[2]s) go test ./...
+
+  # Download Kubernetes 1.18.8 and export KUBEBUILDER_ASSETS in the current shell
+  eval $(%[1]s alpha %[2]s --%[3]s 1.18.8 --%[4]s %[5]s)
+`, c.commandName, setupEnvtestCommandName, k8sVersionFlag, printFlag, printFormatEnv),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if format != printFormatPath && format != printFormatEnv {
+				return fmt.Errorf("invalid --%s %q, must be one of %q or %q",
+					printFlag, format, printFormatPath, printFormatEnv)
+			}
+			dir, err := assets.setup(version, runtime.GOOS, runtime.GOARCH)
+			if err != nil {
+				return err
+			}
+			if format == printFormatEnv {
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "export KUBEBUILDER_ASSETS=%q\n", dir)
+			} else {
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), dir)
+			}
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&version, k8sVersionFlag, tools.EnvtestK8sVersion,
+		"Kubernetes version of the binaries")
+	cmd.Flags().StringVar(&assets.binDir, binDirFlag, "testbin",
+		"directory the binaries are downloaded into, in a subdirectory per Kubernetes version and platform")
+	cmd.Flags().StringVar(&assets.url, assetsURLFlag, defaultEnvtestAssetsURL,
+		"URL the kubebuilder-tools archives are downloaded from, e.g. a mirror")
+	cmd.Flags().StringVar(&format, printFlag, printFormatPath,
+		fmt.Sprintf("what to print, either the directory of the binaries (%q) or the command exporting "+
+			"KUBEBUILDER_ASSETS (%q)", printFormatPath, printFormatEnv))
+	return cmd
+}
+
+// setup downloads the binaries for version, goos and goarch, unless they all already are in their directory,
+// and returns the absolute path of that directory.
+func (a envtestAssets) setup(version, goos, goarch string) (string, error) {
+	dir, err := filepath.Abs(filepath.Join(a.binDir, fmt.Sprintf("%s-%s-%s", version, goos, goarch)))
+	if err != nil {
+		return "", err
+	}
+	if hasEnvtestBinaries(dir) {
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(a.binDir, 0755); err != nil {
+		return "", err
+	}
+	// The binaries are extracted next to their directory, which is only renamed once they all are, so an
+	// interrupted download is not mistaken for a complete one.
+	tmpDir, err := ioutil.TempDir(a.binDir, ".download-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	archive := fmt.Sprintf("kubebuilder-tools-%s-%s-%s.tar.gz", version, goos, goarch)
+	if err := a.download(a.url+"/"+archive, tmpDir); err != nil {
+		return "", fmt.Errorf("unable to download %s: %v", archive, err)
+	}
+	if !hasEnvtestBinaries(tmpDir) {
+		return "", fmt.Errorf("%s does not contain the %v binaries", archive, envtestBinaries)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// download extracts the files of the archive at url that are under a bin/ directory into dir.
+func (a envtestAssets) download(url, dir string) error {
+	resp, err := a.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %q", resp.Status)
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		// Archives contain kubebuilder/bin/<binary>
+		if hdr.Typeflag != tar.TypeReg || path.Base(path.Dir(hdr.Name)) != "bin" {
+			continue
+		}
+		if err := writeBinary(filepath.Join(dir, path.Base(hdr.Name)), tr); err != nil {
+			return err
+		}
+	}
+}
+
+// writeBinary writes the executable file filePath with the content of r.
+func writeBinary(filePath string, r io.Reader) error {
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755) //nolint:gosec
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil { //nolint:gosec
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// hasEnvtestBinaries returns true if every envtest binary is in dir.
+func hasEnvtestBinaries(dir string) bool {
+	for _, name := range envtestBinaries {
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || info.IsDir() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// makeToolsArchive returns a gzipped tarball with the layout of the kubebuilder-tools archives.
+func makeToolsArchive(binaries ...string) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	ExpectWithOffset(1, tw.WriteHeader(&tar.Header{Name: "kubebuilder/", Typeflag: tar.TypeDir, Mode: 0755})).
+		To(Succeed())
+	for _, name := range binaries {
+		content := []byte("#!/bin/sh\necho " + name + "\n")
+		ExpectWithOffset(1, tw.WriteHeader(&tar.Header{
+			Name:     "kubebuilder/bin/" + name,
+			Typeflag: tar.TypeReg,
+			Mode:     0755,
+			Size:     int64(len(content)),
+		})).To(Succeed())
+		_, err := tw.Write(content)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	}
+	ExpectWithOffset(1, tw.Close()).To(Succeed())
+	ExpectWithOffset(1, gz.Close()).To(Succeed())
+	return buf.Bytes()
+}
+
+var _ = Describe("setup-envtest", func() {
+	var (
+		assets   envtestAssets
+		server   *httptest.Server
+		archives map[string][]byte
+		requests int
+	)
+
+	BeforeEach(func() {
+		archives = map[string][]byte{
+			"/kubebuilder-tools-1.19.2-linux-amd64.tar.gz": makeToolsArchive(envtestBinaries...),
+		}
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			archive, found := archives[r.URL.Path]
+			if !found {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(archive)
+		}))
+
+		binDir, err := ioutil.TempDir("", "envtest")
+		Expect(err).NotTo(HaveOccurred())
+		assets = envtestAssets{client: server.Client(), url: server.URL, binDir: binDir}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(os.RemoveAll(assets.binDir)).To(Succeed())
+	})
+
+	It("should download the binaries once into a directory per version and platform", func() {
+		dir, err := assets.setup("1.19.2", "linux", "amd64")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dir).To(Equal(filepath.Join(assets.binDir, "1.19.2-linux-amd64")))
+		for _, name := range envtestBinaries {
+			info, err := os.Stat(filepath.Join(dir, name))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+		}
+
+		_, err = assets.setup("1.19.2", "linux", "amd64")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(Equal(1))
+	})
+
+	It("should fail for a version that is not published", func() {
+		_, err := assets.setup("1.0.0", "linux", "amd64")
+		Expect(err).To(MatchError(`unable to download kubebuilder-tools-1.0.0-linux-amd64.tar.gz: ` +
+			`unexpected status "404 Not Found"`))
+	})
+
+	It("should not leave incomplete binaries behind", func() {
+		archives["/kubebuilder-tools-1.19.2-linux-amd64.tar.gz"] = makeToolsArchive("etcd")
+		_, err := assets.setup("1.19.2", "linux", "amd64")
+		Expect(err).To(MatchError(ContainSubstring("does not contain the [etcd kube-apiserver kubectl] binaries")))
+
+		entries, err := ioutil.ReadDir(assets.binDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(BeEmpty())
+	})
+})
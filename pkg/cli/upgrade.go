@@ -0,0 +1,255 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+const (
+	upgradeCommandName = "upgrade"
+
+	branchFlag   = "branch"
+	worktreeFlag = "worktree"
+)
+
+func (c cli) newUpgradeCmd() *cobra.Command {
+	var (
+		to        []string
+		branch    string
+		worktree  string
+		initFlags map[string]string
+	)
+	cmd := &cobra.Command{
+		Use:   upgradeCommandName,
+		Short: "Scaffold the project again with other plugins in a git worktree",
+		Long: `Scaffold the project again with the plugins set with --to, in a new branch checked out in a git
+worktree, so that the diff of the branch shows what upgrading the plugins changes, and write the checklist of
+the steps left to do by hand, e.g. porting the code of the controllers.
+
+The branch starts from the current commit. The files of the project are removed from it and the project is
+scaffolded again: by running its command history, as 'alpha replay' does, if it was recorded, or else by
+running the commands scaffolding the state recorded in the PROJECT file, as 'alpha verify' does. The init
+options that the new plugins add or rename are set with --init-flag. The scaffolded files are then committed,
+the PROJECT file being written again by the commands.
+
+The checklist lists the files of the project that were not scaffolded again, the scaffolded files that differ
+from the ones of the project, and the resources that the commands did not create.
+`,
+		Example: fmt.Sprintf(`  # Scaffold the project with go/v3-alpha in the upgrade-go-v3-alpha branch, checked out next to the project
+  %[1]s alpha %[2]s --%[3]s go/v3-alpha
+
+  # Review the upgrade
+  git diff HEAD upgrade-go-v3-alpha
+`, c.commandName, upgradeCommandName, toFlag),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !c.configured {
+				return errors.New("unable to find configuration file, project must be initialized")
+			}
+			if len(to) == 0 {
+				return fmt.Errorf("--%s is required", toFlag)
+			}
+			project, err := internalconfig.Read()
+			if err != nil {
+				return fmt.Errorf("failed to read config: %v", err)
+			}
+			commands, err := upgradeCommands(*project, to, initFlags)
+			if err != nil {
+				return err
+			}
+			commands = appendInheritedFlags(commands, cmd)
+
+			root, err := runGit(".", "rev-parse", "--show-toplevel")
+			if err != nil {
+				return fmt.Errorf("the project must be in a git repository: %v", err)
+			}
+			wd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("error getting current directory: %v", err)
+			}
+			rel, err := filepath.Rel(root, wd)
+			if err != nil {
+				return err
+			}
+			files, err := runGit(".", "ls-files")
+			if err != nil {
+				return err
+			}
+			if branch == "" {
+				branch = "upgrade-" + strings.NewReplacer("/", "-", ",", "-", ".", "-").Replace(strings.Join(to, "-"))
+			}
+			if worktree == "" {
+				worktree = filepath.Join(filepath.Dir(root), filepath.Base(root)+"-"+branch)
+			}
+
+			if _, err := runGit(".", "worktree", "add", "-b", branch, worktree, "HEAD"); err != nil {
+				return err
+			}
+			dir := filepath.Join(worktree, rel)
+			if _, err := runGit(dir, "rm", "-r", "-q", "--ignore-unmatch", "--", "."); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+			executable, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("unable to find the %s executable: %v", c.commandName, err)
+			}
+			err = runCommands(cmd.OutOrStdout(), c.commandName, commands, func(args []string) error {
+				command := exec.Command(executable, args...)
+				command.Dir, command.Stdout, command.Stderr = dir, cmd.OutOrStdout(), cmd.ErrOrStderr()
+				return command.Run()
+			})
+			if err != nil {
+				return fmt.Errorf("unable to scaffold the project in %s: %v", worktree, err)
+			}
+			if _, err := runGit(dir, "add", "-A", "--", "."); err != nil {
+				return err
+			}
+			message := fmt.Sprintf("Scaffold the project with %s", strings.Join(to, ","))
+			if _, err := runGit(dir, "commit", "-q", "-m", message); err != nil {
+				return err
+			}
+
+			scaffolded, err := internalconfig.ReadFrom(filepath.Join(dir, internalconfig.DefaultPath))
+			if err != nil {
+				return fmt.Errorf("failed to read the scaffolded config: %v", err)
+			}
+			checklist, err := upgradeChecklist(strings.Split(files, "\n"), ".", dir, *project, *scaffolded)
+			if err != nil {
+				return err
+			}
+			var b strings.Builder
+			fmt.Fprintf(&b, "\nThe project was scaffolded with %s in %s, on the %s branch.\n",
+				strings.Join(to, ","), worktree, branch)
+			fmt.Fprintf(&b, "Review the upgrade with:\n$ git diff HEAD %s\n", branch)
+			if len(checklist) != 0 {
+				b.WriteString("\nSteps left to do in the branch:\n")
+				for _, step := range checklist {
+					fmt.Fprintf(&b, "- [ ] %s\n", step)
+				}
+			}
+			_, err = fmt.Fprint(cmd.OutOrStdout(), b.String())
+			return err
+		},
+	}
+	cmd.Flags().StringSliceVar(&to, toFlag, nil, "plugin keys the project is scaffolded with, e.g. go/v3-alpha")
+	cmd.Flags().StringVar(&branch, branchFlag, "",
+		"branch the project is scaffolded in, defaults to upgrade-<plugins>")
+	cmd.Flags().StringVar(&worktree, worktreeFlag, "",
+		"directory the branch is checked out in, defaults to <repository>-<branch> next to the repository")
+	cmd.Flags().StringToStringVar(&initFlags, initFlagFlag, nil,
+		"flag set on the init command, as <name>=<value>, e.g. project-version=3-alpha")
+	return cmd
+}
+
+// upgradeCommands returns the arguments of the commands scaffolding cfg again with plugins: its command history
+// if it was recorded, or else the commands scaffolding the state of cfg. initFlags are set on init.
+func upgradeCommands(cfg config.Config, plugins []string, initFlags map[string]string) ([][]string, error) {
+	if len(cfg.History) != 0 {
+		return replayCommands(cfg, plugins, initFlags)
+	}
+	spec, err := newVerifySpec(cfg, initFlags)
+	if err != nil {
+		return nil, err
+	}
+	spec.Plugins = plugins
+	return spec.commands(), nil
+}
+
+// appendInheritedFlags appends the inherited flags of cmd that were set to each of commands.
+func appendInheritedFlags(commands [][]string, cmd *cobra.Command) [][]string {
+	inherited := inheritedFlagArgs(cmd)
+	for i := range commands {
+		commands[i] = append(commands[i], inherited...)
+	}
+	return commands
+}
+
+// upgradeChecklist returns the steps left to do by hand after scaffolding the project in projectDir again in
+// scaffoldDir: porting the files of the project, relative to projectDir, that were not scaffolded, merging the
+// scaffolded files that differ, and creating the resources of project that scaffolded does not track.
+func upgradeChecklist(files []string, projectDir, scaffoldDir string, project, scaffolded config.Config) (
+	[]string, error) {
+	var missing, modified []string
+	for _, file := range files {
+		if file == "" || file == internalconfig.DefaultPath {
+			continue
+		}
+		actual, err := ioutil.ReadFile(filepath.Join(projectDir, file))
+		if os.IsNotExist(err) {
+			// Deleted but not committed yet
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		expected, err := ioutil.ReadFile(filepath.Join(scaffoldDir, file))
+		switch {
+		case os.IsNotExist(err):
+			missing = append(missing, file)
+		case err != nil:
+			return nil, err
+		case !bytes.Equal(actual, expected):
+			modified = append(modified, file)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(modified)
+
+	checklist := make([]string, 0, len(missing)+len(modified))
+	for _, file := range missing {
+		checklist = append(checklist, fmt.Sprintf("port %s, which was not scaffolded, if it is still needed", file))
+	}
+	for _, file := range modified {
+		checklist = append(checklist, fmt.Sprintf("merge the changes made to %s into the scaffolded one", file))
+	}
+	for _, res := range project.Resources {
+		if !scaffolded.HasResource(res) {
+			checklist = append(checklist, fmt.Sprintf("create the API of the %s Kind of %s/%s again, "+
+				"it was not created by the commands", res.Kind, res.Group, res.Version))
+		}
+	}
+	return checklist, nil
+}
+
+// runGit runs git with args in dir, returning its trimmed output.
+func runGit(dir string, args ...string) (string, error) {
+	command := exec.Command("git", args...)
+	command.Dir = dir
+	var stdout, stderr bytes.Buffer
+	command.Stdout, command.Stderr = &stdout, &stderr
+	if err := command.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %v: %s", strings.Join(args, " "), err,
+			strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+var _ = Describe("alpha upgrade", func() {
+	captain := config.GVK{Group: "crew", Version: "v1", Kind: "Captain"}
+	mate := config.GVK{Group: "crew", Version: "v1", Kind: "Mate"}
+
+	It("should run the command history with the plugins, if it was recorded", func() {
+		cfg := config.Config{
+			Version: config.Version3Alpha,
+			History: []config.RecordedCommand{{Command: "init", Flags: []string{"--plugins=go/v2"}}},
+		}
+		Expect(upgradeCommands(cfg, []string{"go/v3-alpha"}, nil)).To(Equal([][]string{
+			{"init", "--plugins=go/v3-alpha"},
+		}))
+	})
+
+	It("should scaffold the state of the PROJECT file with the plugins otherwise", func() {
+		cfg := config.Config{Version: config.Version2, Domain: "my.domain", Resources: []config.GVK{captain}}
+		commands, err := upgradeCommands(cfg, []string{"go/v3-alpha"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(commands).To(HaveLen(2))
+		Expect(commands[0]).To(ContainElement("--plugins=go/v3-alpha"))
+		Expect(commands[1][:2]).To(Equal([]string{"create", "api"}))
+	})
+
+	It("should list the files to port and merge, and the resources to create again", func() {
+		tmp, err := ioutil.TempDir("", "upgrade")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmp)
+		projectDir, scaffoldDir := filepath.Join(tmp, "project"), filepath.Join(tmp, "scaffold")
+		write := func(dir, path, content string) {
+			Expect(os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(dir, path), []byte(content), 0644)).To(Succeed())
+		}
+		write(projectDir, "PROJECT", "version: 2")
+		write(projectDir, "Makefile", "all:")
+		write(projectDir, "main.go", "package main // edited")
+		write(projectDir, "controllers/mate_controller.go", "package controllers")
+		write(scaffoldDir, "PROJECT", "version: 3-alpha")
+		write(scaffoldDir, "Makefile", "all:")
+		write(scaffoldDir, "main.go", "package main")
+
+		files := []string{"Makefile", "PROJECT", "controllers/mate_controller.go", "deleted.go", "main.go", ""}
+		project := config.Config{Resources: []config.GVK{captain, mate}}
+		scaffolded := config.Config{Resources: []config.GVK{captain}}
+		Expect(upgradeChecklist(files, projectDir, scaffoldDir, project, scaffolded)).To(Equal([]string{
+			"port controllers/mate_controller.go, which was not scaffolded, if it is still needed",
+			"merge the changes made to main.go into the scaffolded one",
+			"create the API of the Mate Kind of crew/v1 again, it was not created by the commands",
+		}))
+	})
+})
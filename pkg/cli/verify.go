@@ -0,0 +1,384 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+const (
+	verifyCommandName = "verify"
+
+	initFlagFlag = "init-flag"
+	diffFlag     = "diff"
+
+	// diffContext is the number of unchanged lines around the changed ones in a diff
+	diffContext = 3
+)
+
+func (c cli) newVerifyCmd() *cobra.Command {
+	var initFlags map[string]string
+	var showDiff bool
+	cmd := &cobra.Command{
+		Use:   verifyCommandName,
+		Short: "Verify that the files the plugins own match their scaffold",
+		Long: `Verify that the files the plugins own, the Makefile, the Dockerfile and the manifests in config/, match
+the ones the plugins scaffold for the state recorded in the PROJECT file, e.g. before upgrading the plugins, to
+find the changes made to those files that an upgrade would have to keep.
+
+The project is scaffolded again in a temporary directory, by an init command followed by a create api command
+per resource and a create webhook command per resource with webhooks, with the options recorded in the PROJECT
+file. Init options that are not recorded, e.g. --go-version, are passed with --init-flag. The scaffolded files
+are then compared with the ones of the project: the differing and missing ones are listed, with their diff from
+the scaffold with --diff. The samples in config/samples are not compared, as they are filled by the user, nor
+are the manifests that are only generated, e.g. the CRDs.
+
+Only the state recorded in the PROJECT file is scaffolded, so a file edited by a command whose options are not
+recorded, e.g. the kinds of webhooks of the resources, may differ without having been edited by the user.
+`,
+		Example: fmt.Sprintf(`  # List the files that differ from their scaffold
+  %[1]s alpha %[2]s
+
+  # Print the diff of the files from their scaffold, for a project initialized with --go-version 1.15
+  %[1]s alpha %[2]s --%[3]s go-version=1.15 --%[4]s
+`, c.commandName, verifyCommandName, initFlagFlag, diffFlag),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !c.configured {
+				return errors.New("unable to find configuration file, project must be initialized")
+			}
+			project, err := internalconfig.Load()
+			if err != nil {
+				return fmt.Errorf("failed to read config: %v", err)
+			}
+			spec, err := newVerifySpec(project.Config, initFlags)
+			if err != nil {
+				return err
+			}
+			executable, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("unable to find the %s executable: %v", c.commandName, err)
+			}
+			wd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("error getting current directory: %v", err)
+			}
+
+			// The project is valid from here on, so its drift and the scaffolding failures are not usage errors
+			cmd.SilenceUsage = true
+			tmp, err := ioutil.TempDir("", "kubebuilder-verify-")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(tmp)
+			// The names derived from the directory of the project are the same in the scaffold
+			dir := filepath.Join(tmp, filepath.Base(wd))
+			if err := os.Mkdir(dir, 0755); err != nil {
+				return err
+			}
+			// The output of the commands is only written if one of them fails
+			var log bytes.Buffer
+			err = runCommands(&log, c.commandName, spec.commands(), func(args []string) error {
+				command := exec.Command(executable, args...)
+				command.Dir, command.Stdout, command.Stderr = dir, &log, &log
+				return command.Run()
+			})
+			if err != nil {
+				return fmt.Errorf("unable to scaffold the project: %v\n%s", err, log.String())
+			}
+
+			drifts, err := compareScaffold(dir, ".")
+			if err != nil {
+				return err
+			}
+			if err := writeDrifts(cmd.OutOrStdout(), drifts, showDiff); err != nil {
+				return err
+			}
+			if len(drifts) != 0 {
+				return fmt.Errorf("%d file(s) differ from their scaffold", len(drifts))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringToStringVar(&initFlags, initFlagFlag, nil,
+		"init flag that is not recorded in the PROJECT file, as <name>=<value>, e.g. go-version=1.15")
+	cmd.Flags().BoolVar(&showDiff, diffFlag, false, "print the diff of the files from their scaffold")
+	return cmd
+}
+
+// newVerifySpec returns the spec scaffolding the state recorded in cfg, initFlags being passed to init.
+// Dependencies are neither downloaded nor built, as only the files are compared.
+func newVerifySpec(cfg config.Config, initFlags map[string]string) (projectSpec, error) {
+	if cfg.IsV1() {
+		return projectSpec{}, errors.New("version 1 projects do not track their resources, " +
+			"they can not be verified")
+	}
+
+	flags := map[string]string{"fetch-deps": "false"}
+	if cfg.Offline {
+		// Offline projects do not fetch dependencies, the flag can not be set too
+		flags = map[string]string{"offline": "true"}
+	}
+	setBool := func(name string, value bool) {
+		if value {
+			flags[name] = "true"
+		}
+	}
+	setBool("component-config", cfg.ComponentConfig)
+	setBool("metrics-auth-filter", cfg.MetricsAuthFilter)
+	setBool("typed-clients", cfg.TypedClients)
+	setBool("apis-module", cfg.APIsModule)
+	setBool("skip-go-mod-tidy", cfg.SkipGoMod)
+	if cfg.TestFramework != "" {
+		flags["test-framework"] = cfg.TestFramework
+	}
+	if cfg.WebhookPort != 0 {
+		flags["webhook-port"] = strconv.Itoa(cfg.WebhookPort)
+	}
+	if cfg.WebhookHost != "" {
+		flags["webhook-host"] = cfg.WebhookHost
+	}
+	if cfg.WebhookCertDir != "" {
+		flags["webhook-cert-dir"] = cfg.WebhookCertDir
+	}
+	for name, value := range initFlags {
+		flags[name] = value
+	}
+
+	spec := projectSpec{
+		ProjectVersion: cfg.Version,
+		Domain:         cfg.Domain,
+		Repo:           cfg.Repo,
+		ProjectName:    cfg.ProjectName,
+		Flags:          flags,
+	}
+	if cfg.Layout != "" {
+		spec.Plugins = strings.Split(cfg.Layout, ",")
+	}
+	for _, res := range cfg.Resources {
+		api := apiSpec{
+			Group:   res.Group,
+			Version: res.Version,
+			Kind:    res.Kind,
+			Plural:  res.Plural,
+			Flags:   map[string]string{"make": "false"},
+		}
+		if res.CRDVersion != "" {
+			api.Flags["crd-version"] = res.CRDVersion
+		}
+		if res.Defaults != "" {
+			api.Flags["defaults"] = res.Defaults
+		}
+		if cfg.APIsModule {
+			// The go.mod files are not verified, so the modules are not tidied
+			api.Flags["skip-go-mod"] = "true"
+		}
+		spec.APIs = append(spec.APIs, api)
+
+		// A resource defaulted by a webhook gets it from create api
+		if res.WebhookVersion == "" || res.Defaults == config.DefaultsWebhook {
+			continue
+		}
+		// The kinds of webhooks are not recorded, and a webhook needs one of them
+		webhook := webhookSpec{
+			Group:                  res.Group,
+			Version:                res.Version,
+			Kind:                   res.Kind,
+			ProgrammaticValidation: true,
+			Flags:                  map[string]string{"webhook-version": res.WebhookVersion},
+		}
+		spec.Webhooks = append(spec.Webhooks, webhook)
+	}
+	return spec, nil
+}
+
+// isVerified returns true if the file at path, relative to the root of the project, is owned by the plugins.
+func isVerified(path string) bool {
+	path = filepath.ToSlash(path)
+	switch {
+	case path == "Makefile", path == "Dockerfile":
+		return true
+	case strings.HasPrefix(path, "config/samples/"):
+		return false
+	default:
+		return strings.HasPrefix(path, "config/")
+	}
+}
+
+// drift is a file of the project that differs from its scaffold.
+type drift struct {
+	// path is relative to the root of the project.
+	path     string
+	expected []byte
+	// actual is nil if the file is missing.
+	actual []byte
+}
+
+// compareScaffold returns the files owned by the plugins that are scaffolded under scaffoldDir and differ in
+// root, sorted by path. The files that are only in root are not compared, as they are generated or were added.
+func compareScaffold(scaffoldDir, root string) ([]drift, error) {
+	var drifts []drift
+	err := filepath.Walk(scaffoldDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(scaffoldDir, path)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || !isVerified(rel) {
+			return nil
+		}
+
+		expected, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		actual, err := ioutil.ReadFile(filepath.Join(root, rel))
+		switch {
+		case os.IsNotExist(err):
+			drifts = append(drifts, drift{path: filepath.ToSlash(rel), expected: expected})
+		case err != nil:
+			return err
+		case !bytes.Equal(actual, expected):
+			drifts = append(drifts, drift{path: filepath.ToSlash(rel), expected: expected, actual: actual})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].path < drifts[j].path })
+	return drifts, nil
+}
+
+// writeDrifts writes each drift, and its diff from the scaffold if showDiff is true, or a line reporting that
+// the files match their scaffold if there is no drift.
+func writeDrifts(out io.Writer, drifts []drift, showDiff bool) error {
+	var b strings.Builder
+	if len(drifts) == 0 {
+		b.WriteString("every file owned by the plugins matches its scaffold\n")
+	}
+	for _, d := range drifts {
+		if d.actual == nil {
+			fmt.Fprintf(&b, "missing: %s\n", d.path)
+			continue
+		}
+		fmt.Fprintf(&b, "modified: %s\n", d.path)
+		if showDiff {
+			writeLineDiff(&b, d.path, string(d.expected), string(d.actual))
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// writeLineDiff writes the unified diff from expected to actual, the contents of the file at path.
+func writeLineDiff(b *strings.Builder, path, expected, actual string) {
+	a, z := withoutEmptyLast(strings.SplitAfter(expected, "\n")), withoutEmptyLast(strings.SplitAfter(actual, "\n"))
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and z[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(z)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(z) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == z[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	// Each line of the diff is prefixed by ' ', '-' or '+', with the line number it starts at in a and z
+	type diffLine struct {
+		op   byte
+		text string
+		i, j int
+	}
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(z) {
+		switch {
+		case i < len(a) && j < len(z) && a[i] == z[j]:
+			lines = append(lines, diffLine{' ', a[i], i, j})
+			i, j = i+1, j+1
+		case j == len(z) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', a[i], i, j})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', z[j], i, j})
+			j++
+		}
+	}
+
+	fmt.Fprintf(b, "--- a/%s\n+++ b/%s\n", path, path)
+	for start := 0; start < len(lines); {
+		if lines[start].op == ' ' {
+			start++
+			continue
+		}
+		// A hunk spans the changes that are less than twice the context apart, with the context around them
+		end := start
+		for k := start; k < len(lines) && k-end <= 2*diffContext; k++ {
+			if lines[k].op != ' ' {
+				end = k + 1
+			}
+		}
+		from, to := start-diffContext, end+diffContext
+		if from < 0 {
+			from = 0
+		}
+		if to > len(lines) {
+			to = len(lines)
+		}
+		var removed, added int
+		for _, line := range lines[from:to] {
+			if line.op != '+' {
+				removed++
+			}
+			if line.op != '-' {
+				added++
+			}
+		}
+		fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", lines[from].i+1, removed, lines[from].j+1, added)
+		for _, line := range lines[from:to] {
+			b.WriteString(string(line.op) + strings.TrimSuffix(line.text, "\n") + "\n")
+		}
+		start = to
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+var _ = Describe("verify", func() {
+	It("should scaffold the state recorded in the PROJECT file", func() {
+		spec, err := newVerifySpec(config.Config{
+			Version:         config.Version3Alpha,
+			Domain:          "my.domain",
+			Repo:            "example.com/project",
+			ProjectName:     "project",
+			Layout:          "go.kubebuilder.io/v3-alpha",
+			ComponentConfig: true,
+			TestFramework:   config.TestFrameworkGoTest,
+			WebhookPort:     9444,
+			Resources: []config.GVK{
+				{Group: "crew", Version: "v1", Kind: "Captain", CRDVersion: "v1", WebhookVersion: "v1"},
+				{Group: "crew", Version: "v1", Kind: "Admiral", Plural: "admirales", Defaults: "webhook",
+					WebhookVersion: "v1"},
+			},
+		}, map[string]string{"go-version": "1.15"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.commands()).To(Equal([][]string{
+			{"init", "--project-version=3-alpha", "--plugins=go.kubebuilder.io/v3-alpha", "--domain=my.domain",
+				"--repo=example.com/project", "--project-name=project", "--component-config=true",
+				"--fetch-deps=false", "--go-version=1.15", "--test-framework=gotest", "--webhook-port=9444"},
+			{"create", "api", "--group=crew", "--version=v1", "--kind=Captain", "--resource=true",
+				"--controller=true", "--crd-version=v1", "--make=false"},
+			{"create", "api", "--group=crew", "--version=v1", "--kind=Admiral", "--plural=admirales",
+				"--resource=true", "--controller=true", "--defaults=webhook", "--make=false"},
+			{"create", "webhook", "--group=crew", "--version=v1", "--kind=Captain", "--programmatic-validation",
+				"--webhook-version=v1"},
+		}))
+	})
+
+	It("should not fetch the dependencies of offline projects", func() {
+		spec, err := newVerifySpec(config.Config{Version: config.Version3Alpha, Offline: true}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.commands()).To(Equal([][]string{{"init", "--project-version=3-alpha", "--offline=true"}}))
+	})
+
+	It("should not tidy the modules of projects with a separate module for the APIs", func() {
+		spec, err := newVerifySpec(config.Config{
+			Version:    config.Version3Alpha,
+			APIsModule: true,
+			Resources:  []config.GVK{{Group: "crew", Version: "v1", Kind: "Captain"}},
+		}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.commands()).To(Equal([][]string{
+			{"init", "--project-version=3-alpha", "--apis-module=true", "--fetch-deps=false"},
+			{"create", "api", "--group=crew", "--version=v1", "--kind=Captain", "--resource=true",
+				"--controller=true", "--make=false", "--skip-go-mod=true"},
+		}))
+	})
+
+	It("should reject version 1 projects", func() {
+		_, err := newVerifySpec(config.Config{Version: config.Version1}, nil)
+		Expect(err).To(MatchError(ContainSubstring("version 1 projects do not track their resources")))
+	})
+
+	Context("comparing the files with their scaffold", func() {
+		var scaffoldDir, root string
+
+		write := func(dir string, files map[string]string) {
+			for path, content := range files {
+				path = filepath.Join(dir, path)
+				Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+			}
+		}
+
+		BeforeEach(func() {
+			var err error
+			scaffoldDir, err = ioutil.TempDir("", "verify-scaffold")
+			Expect(err).NotTo(HaveOccurred())
+			root, err = ioutil.TempDir("", "verify-project")
+			Expect(err).NotTo(HaveOccurred())
+
+			write(scaffoldDir, map[string]string{
+				"Makefile":                            "all: build\n",
+				"Dockerfile":                          "FROM golang\n",
+				"config/manager/manager.yaml":         "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n",
+				"config/default/kustomization.yaml":   "resources: []\n",
+				"config/samples/crew_v1_captain.yaml": "spec: {}\n",
+				"main.go":                             "package main\n",
+			})
+			write(root, map[string]string{
+				"Makefile":                            "all: build\n",
+				"Dockerfile":                          "FROM golang\n",
+				"config/manager/manager.yaml":         "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n",
+				"config/samples/crew_v1_captain.yaml": "spec:\n  foo: bar\n",
+				"config/crd/bases/crew.yaml":          "kind: CustomResourceDefinition\n",
+				"main.go":                             "package main\n\nfunc main() {}\n",
+			})
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(scaffoldDir)).To(Succeed())
+			Expect(os.RemoveAll(root)).To(Succeed())
+		})
+
+		It("should list the files owned by the plugins that differ or are missing", func() {
+			drifts, err := compareScaffold(scaffoldDir, root)
+			Expect(err).NotTo(HaveOccurred())
+			out := &bytes.Buffer{}
+			Expect(writeDrifts(out, drifts, false)).To(Succeed())
+			Expect(out.String()).To(Equal("missing: config/default/kustomization.yaml\n" +
+				"modified: config/manager/manager.yaml\n"))
+		})
+
+		It("should report that the files match their scaffold if none differs", func() {
+			out := &bytes.Buffer{}
+			Expect(writeDrifts(out, nil, true)).To(Succeed())
+			Expect(out.String()).To(Equal("every file owned by the plugins matches its scaffold\n"))
+		})
+
+		It("should print the diff of the files from their scaffold", func() {
+			drifts, err := compareScaffold(scaffoldDir, root)
+			Expect(err).NotTo(HaveOccurred())
+			out := &bytes.Buffer{}
+			Expect(writeDrifts(out, drifts, true)).To(Succeed())
+			Expect(out.String()).To(Equal(strings.Join([]string{
+				"missing: config/default/kustomization.yaml",
+				"modified: config/manager/manager.yaml",
+				"--- a/config/manager/manager.yaml",
+				"+++ b/config/manager/manager.yaml",
+				"@@ -1,5 +1,5 @@",
+				" a", "-b", "+B", " c", " d", " e",
+				"@@ -8,3 +8,4 @@",
+				" h", " i", " j", "+k",
+			}, "\n") + "\n"))
+		})
+	})
+})
@@ -0,0 +1,172 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+const (
+	suppressWarningsFlag = "suppress-warnings"
+	warningsFormatFlag   = "warnings-format"
+
+	// allWarnings can be passed to --suppress-warnings to suppress every warning.
+	allWarnings = "all"
+
+	warningsFormatText = "text"
+	warningsFormatJSON = "json"
+
+	migrationGuideURL = "https://book.kubebuilder.io/migration/guide.html"
+)
+
+// IDs of the warnings written by the cli, which can be passed to --suppress-warnings.
+const (
+	deprecatedPluginWarningID         = "deprecated-plugin"
+	deprecatedProjectVersionWarningID = "deprecated-project-version"
+)
+
+// warningSeverity indicates how relevant a warning is to the user.
+type warningSeverity string
+
+const (
+	severityWarning warningSeverity = "warning"
+)
+
+// warning is a notice that does not stop the cli from running, like the deprecation of a plugin.
+type warning struct {
+	// ID identifies the kind of warning, so that it can be suppressed.
+	ID string `json:"id"`
+	// Severity indicates how relevant the warning is.
+	Severity warningSeverity `json:"severity"`
+	// Message describes the warning.
+	Message string `json:"message"`
+	// Link points to migration instructions or documentation about the warning.
+	Link string `json:"link,omitempty"`
+}
+
+// warningWriter writes non-suppressed warnings in a given format.
+type warningWriter struct {
+	out        io.Writer
+	format     string
+	suppressed map[string]bool
+}
+
+// newWarningWriter returns a warningWriter, or an error if format is not supported.
+func newWarningWriter(out io.Writer, format string, suppressedIDs []string) (warningWriter, error) {
+	if format != warningsFormatText && format != warningsFormatJSON {
+		return warningWriter{}, fmt.Errorf("invalid warnings format %q, possible values: (%s, %s)",
+			format, warningsFormatText, warningsFormatJSON)
+	}
+
+	w := warningWriter{out: out, format: format, suppressed: make(map[string]bool, len(suppressedIDs))}
+	for _, id := range suppressedIDs {
+		w.suppressed[strings.TrimSpace(id)] = true
+	}
+	return w, nil
+}
+
+// write writes warnings to w's output, skipping those that were suppressed.
+func (w warningWriter) write(warnings ...warning) error {
+	if w.suppressed[allWarnings] {
+		return nil
+	}
+
+	for _, wrn := range warnings {
+		if w.suppressed[wrn.ID] {
+			continue
+		}
+
+		var err error
+		switch w.format {
+		case warningsFormatJSON:
+			err = json.NewEncoder(w.out).Encode(wrn)
+		default:
+			msg := fmt.Sprintf("[%s %s] %s\n", strings.ToUpper(string(wrn.Severity)), wrn.ID, wrn.Message)
+			if wrn.Link != "" {
+				msg += fmt.Sprintf("See %s\n", wrn.Link)
+			}
+			msg += fmt.Sprintf("Suppress this warning with --%s=%s\n\n", suppressWarningsFlag, wrn.ID)
+			_, err = fmt.Fprintf(w.out, noticeColor, msg)
+		}
+		if err != nil {
+			return fmt.Errorf("error writing warning %q: %v", wrn.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// deprecationWarnings returns a warning for each deprecated plugin.
+func deprecationWarnings(plugins ...plugin.Base) []warning {
+	var warnings []warning
+	for _, p := range plugins {
+		if d, isDeprecated := p.(plugin.Deprecated); isDeprecated {
+			warnings = append(warnings, warning{
+				ID:       deprecatedPluginWarningID,
+				Severity: severityWarning,
+				Message: fmt.Sprintf("plugin %q is deprecated: %s", plugin.KeyFor(p), d.DeprecationWarning()) +
+					removalNotice(pluginRemovalVersion(p)),
+				Link: migrationGuideURL,
+			})
+		}
+	}
+	return warnings
+}
+
+// projectVersionDeprecation is the deprecation of a project version.
+type projectVersionDeprecation struct {
+	// Warning tells the users of the project version what to do, e.g. which version to upgrade to.
+	Warning string
+	// RemovalVersion is the version of the CLI the project version is removed in, if announced.
+	RemovalVersion string
+}
+
+// projectVersionDeprecationWarnings returns a warning if the project version is deprecated.
+func (c cli) projectVersionDeprecationWarnings() []warning {
+	d, isDeprecated := c.projectVersionDeprecations[c.projectVersion]
+	if !isDeprecated {
+		return nil
+	}
+	return []warning{{
+		ID:       deprecatedProjectVersionWarningID,
+		Severity: severityWarning,
+		Message: fmt.Sprintf("project version %q is deprecated: %s", c.projectVersion, d.Warning) +
+			removalNotice(d.RemovalVersion),
+		Link: migrationGuideURL,
+	}}
+}
+
+// pluginRemovalVersion returns the version of the CLI p is removed in, if p announces it.
+func pluginRemovalVersion(p plugin.Base) string {
+	if r, isRemovable := p.(plugin.Removable); isRemovable {
+		return r.RemovalVersion()
+	}
+	return ""
+}
+
+// removalNotice returns the notice appended to a deprecation warning announcing its removal in removalVersion.
+func removalNotice(removalVersion string) string {
+	if removalVersion == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (to be removed in %s)", removalVersion)
+}
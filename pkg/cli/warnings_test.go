@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+var _ plugin.Deprecated = mockDeprecatedPlugin{}
+
+type mockDeprecatedPlugin struct{ mockPlugin }
+
+func (mockDeprecatedPlugin) DeprecationWarning() string { return "use go.example.com/v2 instead" }
+
+var _ = Describe("warningWriter", func() {
+
+	var (
+		out            *bytes.Buffer
+		w              warningWriter
+		err            error
+		deprecated     = mockDeprecatedPlugin{makeBasePlugin("go.example.com", "v1", config.Version3Alpha).(mockPlugin)}
+		testWarnings   = deprecationWarnings(deprecated, makeBasePlugin("go.example.com", "v2", config.Version3Alpha))
+		expectedReport = warning{
+			ID:       deprecatedPluginWarningID,
+			Severity: severityWarning,
+			Message:  `plugin "go.example.com/v1" is deprecated: use go.example.com/v2 instead`,
+			Link:     migrationGuideURL,
+		}
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+	})
+
+	It("should return a warning for each deprecated plugin", func() {
+		Expect(testWarnings).To(Equal([]warning{expectedReport}))
+	})
+
+	It("should announce the removal of plugins and deprecated project versions", func() {
+		removable := mockRemovablePlugin{deprecated}
+		Expect(deprecationWarnings(removable)[0].Message).To(Equal(
+			`plugin "go.example.com/v1" is deprecated: use go.example.com/v2 instead (to be removed in v4.0.0)`))
+
+		c := cli{projectVersion: config.Version2, projectVersionDeprecations: map[string]projectVersionDeprecation{
+			config.Version2: {Warning: "upgrade to 3-alpha", RemovalVersion: "v3.0.0"},
+		}}
+		Expect(c.projectVersionDeprecationWarnings()).To(Equal([]warning{{
+			ID:       deprecatedProjectVersionWarningID,
+			Severity: severityWarning,
+			Message:  `project version "2" is deprecated: upgrade to 3-alpha (to be removed in v3.0.0)`,
+			Link:     migrationGuideURL,
+		}}))
+
+		c.projectVersion = config.Version3Alpha
+		Expect(c.projectVersionDeprecationWarnings()).To(BeEmpty())
+	})
+
+	It("should write warnings as text", func() {
+		w, err = newWarningWriter(out, warningsFormatText, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.write(testWarnings...)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("[WARNING deprecated-plugin] " + expectedReport.Message))
+		Expect(out.String()).To(ContainSubstring("See " + migrationGuideURL))
+		Expect(out.String()).To(ContainSubstring("--suppress-warnings=deprecated-plugin"))
+	})
+
+	It("should write warnings as JSON", func() {
+		w, err = newWarningWriter(out, warningsFormatJSON, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.write(testWarnings...)).To(Succeed())
+
+		var written warning
+		Expect(json.Unmarshal(out.Bytes(), &written)).To(Succeed())
+		Expect(written).To(Equal(expectedReport))
+	})
+
+	It("should not write suppressed warnings", func() {
+		By("suppressing the warning by ID")
+		w, err = newWarningWriter(out, warningsFormatText, []string{"foo", deprecatedPluginWarningID})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.write(testWarnings...)).To(Succeed())
+		Expect(out.String()).To(BeEmpty())
+
+		By("suppressing all warnings")
+		w, err = newWarningWriter(out, warningsFormatJSON, []string{allWarnings})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.write(testWarnings...)).To(Succeed())
+		Expect(out.String()).To(BeEmpty())
+	})
+
+	It("should return an error for an unknown format", func() {
+		_, err = newWarningWriter(out, "yaml", nil)
+		Expect(err).To(MatchError(ContainSubstring(`invalid warnings format "yaml"`)))
+	})
+})
@@ -37,14 +37,16 @@ func (c *cli) newCreateWebhookCmd() *cobra.Command {
 		),
 	}
 
-	// Lookup the plugin for projectVersion and bind it to the command.
-	c.bindCreateWebhook(ctx, cmd)
+	// Lookup the plugin for projectVersion and bind it to the command once it runs.
+	c.bindLazily(cmd, func() { c.bindCreateWebhook(ctx, cmd) })
 	return cmd
 }
 
 func (c cli) newWebhookContext() plugin.Context {
 	ctx := plugin.Context{
 		CommandName: c.commandName,
+		Logger:      c.logger,
+		GlobalFlags: c.globalFlags,
 		Description: `Scaffold a webhook for an API resource.
 `,
 	}
@@ -55,17 +57,13 @@ func (c cli) newWebhookContext() plugin.Context {
 }
 
 func (c cli) bindCreateWebhook(ctx plugin.Context, cmd *cobra.Command) {
-	var getter plugin.CreateWebhookPluginGetter
+	// Every resolved plugin supporting webhook creation is chained.
+	var subcommands []plugin.GenericSubcommand
+	var keys []string
 	for _, p := range c.resolvedPlugins {
-		tmpGetter, isGetter := p.(plugin.CreateWebhookPluginGetter)
-		if isGetter {
-			if getter != nil {
-				err := fmt.Errorf("duplicate webhook creation plugins for project version %q (%s, %s), "+
-					"use a more specific plugin key", c.projectVersion, plugin.KeyFor(getter), plugin.KeyFor(p))
-				cmdErr(cmd, err)
-				return
-			}
-			getter = tmpGetter
+		if getter, isGetter := p.(plugin.CreateWebhookPluginGetter); isGetter {
+			subcommands = append(subcommands, getter.GetCreateWebhookPlugin())
+			keys = append(keys, plugin.KeyFor(p))
 		}
 	}
 
@@ -75,18 +73,18 @@ func (c cli) bindCreateWebhook(ctx plugin.Context, cmd *cobra.Command) {
 		return
 	}
 
-	if getter == nil {
+	if len(subcommands) == 0 {
 		err := fmt.Errorf("layout plugin %q does not support a webhook creation plugin", cfg.Layout)
 		cmdErr(cmd, err)
 		return
 	}
 
-	createWebhook := getter.GetCreateWebhookPlugin()
-	createWebhook.InjectConfig(&cfg.Config)
-	createWebhook.BindFlags(cmd.Flags())
-	createWebhook.UpdateContext(&ctx)
+	if err := bindSubcommands(&ctx, cmd, &cfg.Config, subcommands, keys); err != nil {
+		cmdErr(cmd, err)
+		return
+	}
 	cmd.Long = ctx.Description
 	cmd.Example = ctx.Examples
-	cmd.RunE = runECmdFunc(cfg, createWebhook,
+	cmd.RunE = runECmdFunc(cfg, subcommands,
 		fmt.Sprintf("failed to create webhook with version %q", c.projectVersion))
 }
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logger provides the leveled, structured logger the cli and its plugins write their output with.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Level is the verbosity of a Logger.
+type Level int
+
+const (
+	// QuietLevel does not write informational messages, only what a command explicitly requests from the user.
+	QuietLevel Level = iota - 1
+	// NormalLevel writes informational messages, e.g. which scaffold is being written.
+	NormalLevel
+	// VerboseLevel also writes debug messages, e.g. each scaffolded file and each executed command.
+	VerboseLevel
+)
+
+// Logger writes leveled messages. Each message can be followed by alternating keys and values, which are written
+// as key=value pairs, e.g. Debug("writing file", "path", "main.go") writes `writing file path="main.go"`.
+type Logger interface {
+	// Info writes a message unless the logger is quiet.
+	Info(msg string, keysAndValues ...interface{})
+	// Debug writes a message only if the logger is verbose.
+	Debug(msg string, keysAndValues ...interface{})
+	// Level returns the verbosity of the logger.
+	Level() Level
+}
+
+type logger struct {
+	mu    sync.Mutex
+	out   io.Writer
+	level Level
+}
+
+// New returns a Logger that writes the messages enabled by level to out.
+func New(out io.Writer, level Level) Logger {
+	return &logger{out: out, level: level}
+}
+
+// Info implements Logger
+func (l *logger) Info(msg string, keysAndValues ...interface{}) {
+	if l.level >= NormalLevel {
+		l.write(msg, keysAndValues)
+	}
+}
+
+// Debug implements Logger
+func (l *logger) Debug(msg string, keysAndValues ...interface{}) {
+	if l.level >= VerboseLevel {
+		l.write("[debug] "+msg, keysAndValues)
+	}
+}
+
+// Level implements Logger
+func (l *logger) Level() Level {
+	return l.level
+}
+
+func (l *logger) write(msg string, keysAndValues []interface{}) {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		var value interface{} = "<missing>"
+		if i+1 < len(keysAndValues) {
+			value = keysAndValues[i+1]
+		}
+		if s, isString := value.(string); isString {
+			value = fmt.Sprintf("%q", s)
+		}
+		fmt.Fprintf(&b, " %v=%v", keysAndValues[i], value)
+	}
+	b.WriteString("\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = io.WriteString(l.out, b.String())
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLogger = New(os.Stdout, NormalLevel)
+)
+
+// Default returns the logger set by the cli, which also passes it to plugins through their plugin.Context.
+// It writes informational messages to stdout if none was set.
+func Default() Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
+
+// SetDefault sets the logger returned by Default.
+func SetDefault(l Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = l
+}
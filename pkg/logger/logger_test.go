@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logger", func() {
+	var out *bytes.Buffer
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+	})
+
+	It("should write key-value pairs", func() {
+		New(out, NormalLevel).Info("writing file", "path", "main.go", "count", 2, "dangling")
+		Expect(out.String()).To(Equal(`writing file path="main.go" count=2 dangling="<missing>"` + "\n"))
+	})
+
+	It("should only write the messages enabled by its level", func() {
+		By("being quiet")
+		l := New(out, QuietLevel)
+		l.Info("info")
+		l.Debug("debug")
+		Expect(out.String()).To(BeEmpty())
+
+		By("being normal")
+		l = New(out, NormalLevel)
+		l.Info("info")
+		l.Debug("debug")
+		Expect(out.String()).To(Equal("info\n"))
+
+		By("being verbose")
+		out.Reset()
+		l = New(out, VerboseLevel)
+		l.Info("info")
+		l.Debug("debug")
+		Expect(out.String()).To(Equal("info\n[debug] debug\n"))
+	})
+
+	It("should set the default logger", func() {
+		previous := Default()
+		defer SetDefault(previous)
+
+		l := New(out, VerboseLevel)
+		SetDefault(l)
+		Expect(Default()).To(BeIdenticalTo(l))
+	})
+})
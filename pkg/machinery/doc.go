@@ -0,0 +1,33 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package machinery scaffolds files from the templates and inserters defined with the
+// sigs.k8s.io/kubebuilder/pkg/model/file package, against a sigs.k8s.io/kubebuilder/pkg/model.Universe.
+//
+// It is the machinery used by the plugins shipped with kubebuilder, and can be used by other plugins to get
+// the same guarantees: existing files are skipped, overwritten or reported according to the IfExistsAction
+// of each template, code fragments are only inserted at their markers if they are not already present, and
+// Go files are formatted and have their imports fixed. Templates embedding file.BoilerplateMixin get the
+// boilerplate of the universe injected. SetExistingFileResolver lets the caller decide instead which of the
+// existing files that would be skipped or reported are overwritten.
+//
+// Example:
+//
+//	universe := model.NewUniverse(model.WithConfig(cfg), model.WithBoilerplate(boilerplate))
+//	if err := machinery.NewScaffold().Execute(universe, &MyTemplate{}, &MyInserter{}); err != nil {
+//		return err
+//	}
+package machinery
@@ -171,3 +171,24 @@ func (e closeFileError) Unwrap() error {
 func IsCloseFileError(err error) bool {
 	return errors.As(err, &closeFileError{})
 }
+
+// chmodError is returned if the permissions of the file could not be changed
+type chmodError struct {
+	path string
+	err  error
+}
+
+// Error implements error interface
+func (e chmodError) Error() string {
+	return fmt.Sprintf("failed to change the permissions of %s: %v", e.path, e.err)
+}
+
+// Unwrap implements Wrapper interface
+func (e chmodError) Unwrap() error {
+	return e.err
+}
+
+// IsChmodError checks if the returned error is because the permissions of the file could not be changed
+func IsChmodError(err error) bool {
+	return errors.As(err, &chmodError{})
+}
@@ -42,6 +42,7 @@ var _ = Describe("Errors", func() {
 		readFileErr        = readFileError{path, err}
 		writeFileErr       = writeFileError{path, err}
 		closeFileErr       = closeFileError{path, err}
+		chmodErr           = chmodError{path, err}
 	)
 
 	DescribeTable("IsXxxxError should return true for themselves and false for the rest",
@@ -52,19 +53,21 @@ var _ = Describe("Errors", func() {
 			}
 		},
 		Entry("file exists", IsFileExistsError, fileExistsErr,
-			openFileErr, createDirectoryErr, createFileErr, readFileErr, writeFileErr, closeFileErr),
+			openFileErr, createDirectoryErr, createFileErr, readFileErr, writeFileErr, closeFileErr, chmodErr),
 		Entry("open file", IsOpenFileError, openFileErr,
-			fileExistsErr, createDirectoryErr, createFileErr, readFileErr, writeFileErr, closeFileErr),
+			fileExistsErr, createDirectoryErr, createFileErr, readFileErr, writeFileErr, closeFileErr, chmodErr),
 		Entry("create directory", IsCreateDirectoryError, createDirectoryErr,
-			fileExistsErr, openFileErr, createFileErr, readFileErr, writeFileErr, closeFileErr),
+			fileExistsErr, openFileErr, createFileErr, readFileErr, writeFileErr, closeFileErr, chmodErr),
 		Entry("create file", IsCreateFileError, createFileErr,
-			fileExistsErr, openFileErr, createDirectoryErr, readFileErr, writeFileErr, closeFileErr),
+			fileExistsErr, openFileErr, createDirectoryErr, readFileErr, writeFileErr, closeFileErr, chmodErr),
 		Entry("read file", IsReadFileError, readFileErr,
-			fileExistsErr, openFileErr, createDirectoryErr, createFileErr, writeFileErr, closeFileErr),
+			fileExistsErr, openFileErr, createDirectoryErr, createFileErr, writeFileErr, closeFileErr, chmodErr),
 		Entry("write file", IsWriteFileError, writeFileErr,
-			fileExistsErr, openFileErr, createDirectoryErr, createFileErr, readFileErr, closeFileErr),
+			fileExistsErr, openFileErr, createDirectoryErr, createFileErr, readFileErr, closeFileErr, chmodErr),
 		Entry("close file", IsCloseFileError, closeFileErr,
-			fileExistsErr, openFileErr, createDirectoryErr, createFileErr, readFileErr, writeFileErr),
+			fileExistsErr, openFileErr, createDirectoryErr, createFileErr, readFileErr, writeFileErr, chmodErr),
+		Entry("chmod", IsChmodError, chmodErr,
+			fileExistsErr, openFileErr, createDirectoryErr, createFileErr, readFileErr, writeFileErr, closeFileErr),
 	)
 
 	DescribeTable("should contain the wrapped error and error message",
@@ -79,5 +82,6 @@ var _ = Describe("Errors", func() {
 		Entry("read file", readFileErr),
 		Entry("write file", writeFileErr),
 		Entry("close file", closeFileErr),
+		Entry("chmod", chmodErr),
 	)
 })
@@ -42,6 +42,9 @@ type FileSystem interface {
 	// Create creates the directory and file and returns a self-closing
 	// io.Writer pointing to that file. If the file exists, it truncates it.
 	Create(path string) (io.Writer, error)
+
+	// Chmod changes the permissions of the file
+	Chmod(path string, perm os.FileMode) error
 }
 
 // fileSystem implements FileSystem
@@ -124,6 +127,15 @@ func (fs fileSystem) Create(path string) (io.Writer, error) {
 	return &writeFile{path, wc}, nil
 }
 
+// Chmod implements FileSystem.Chmod
+func (fs fileSystem) Chmod(path string, perm os.FileMode) error {
+	if err := fs.fs.Chmod(path, perm); err != nil {
+		return chmodError{path, err}
+	}
+
+	return nil
+}
+
 var _ io.ReadCloser = &readFile{}
 
 // readFile implements io.Reader
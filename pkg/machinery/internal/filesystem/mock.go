@@ -19,6 +19,8 @@ package filesystem
 import (
 	"bytes"
 	"io"
+	"os"
+	"sync"
 )
 
 // mockFileSystem implements FileSystem
@@ -34,6 +36,10 @@ type mockFileSystem struct {
 	output          *bytes.Buffer
 	writeFileError  error
 	closeFileError  error
+	chmodError      error
+	permissions     map[string]os.FileMode
+	// lock guards output and permissions, as files may be written concurrently
+	lock *sync.Mutex
 }
 
 // NewMock returns a new FileSystem
@@ -42,6 +48,7 @@ func NewMock(options ...MockOptions) FileSystem {
 	fs := mockFileSystem{
 		exists: func(_ string) bool { return false },
 		output: new(bytes.Buffer),
+		lock:   new(sync.Mutex),
 	}
 
 	// Apply options
@@ -132,6 +139,20 @@ func MockCloseFileError(err error) MockOptions {
 	}
 }
 
+// MockChmodError makes FileSystem.Chmod return err
+func MockChmodError(err error) MockOptions {
+	return func(fs *mockFileSystem) {
+		fs.chmodError = err
+	}
+}
+
+// MockPermissions provides a map where the permissions set with FileSystem.Chmod will be stored by path
+func MockPermissions(permissions map[string]os.FileMode) MockOptions {
+	return func(fs *mockFileSystem) {
+		fs.permissions = permissions
+	}
+}
+
 // Exists implements FileSystem.Exists
 func (fs mockFileSystem) Exists(path string) (bool, error) {
 	if fs.existsError != nil {
@@ -164,7 +185,21 @@ func (fs mockFileSystem) Create(path string) (io.Writer, error) {
 		return nil, createFileError{path, fs.createFileError}
 	}
 
-	return &mockWriteFile{path, fs.output, fs.writeFileError, fs.closeFileError}, nil
+	return &mockWriteFile{path, fs.output, fs.lock, fs.writeFileError, fs.closeFileError}, nil
+}
+
+// Chmod implements FileSystem.Chmod
+func (fs mockFileSystem) Chmod(path string, perm os.FileMode) error {
+	if fs.chmodError != nil {
+		return chmodError{path, fs.chmodError}
+	}
+
+	if fs.permissions != nil {
+		fs.lock.Lock()
+		defer fs.lock.Unlock()
+		fs.permissions[path] = perm
+	}
+	return nil
 }
 
 // mockReadFile implements io.Reader mocking a readFile for tests
@@ -197,6 +232,7 @@ func (f *mockReadFile) Close() error {
 type mockWriteFile struct {
 	path           string
 	content        *bytes.Buffer
+	lock           *sync.Mutex
 	writeFileError error
 	closeFileError error
 }
@@ -213,5 +249,7 @@ func (f *mockWriteFile) Write(content []byte) (n int, err error) {
 		return 0, writeFileError{f.path, f.writeFileError}
 	}
 
+	f.lock.Lock()
+	defer f.lock.Unlock()
 	return f.content.Write(content)
 }
@@ -0,0 +1,644 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinery
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"golang.org/x/tools/imports"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery/internal/filesystem"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var options = imports.Options{
+	Comments:   true,
+	TabIndent:  true,
+	TabWidth:   8,
+	FormatOnly: true,
+}
+
+// ExistingFileResolver decides whether an existing file, that its template would skip or fail on, is
+// overwritten with the scaffolded contents, or kept as is.
+type ExistingFileResolver func(path string) (overwrite bool)
+
+var (
+	resolverMu           sync.RWMutex
+	existingFileResolver ExistingFileResolver
+)
+
+// SetExistingFileResolver sets the resolver that every scaffold consults for the existing files it would skip
+// or fail on, e.g. to let users decide which ones to overwrite. Setting nil restores the IfExistsAction of
+// the templates.
+func SetExistingFileResolver(r ExistingFileResolver) {
+	resolverMu.Lock()
+	defer resolverMu.Unlock()
+	existingFileResolver = r
+}
+
+func getExistingFileResolver() ExistingFileResolver {
+	resolverMu.RLock()
+	defer resolverMu.RUnlock()
+	return existingFileResolver
+}
+
+// Scaffold uses templates to scaffold new files
+type Scaffold interface {
+	// Execute writes to disk the provided files
+	Execute(*model.Universe, ...file.Builder) error
+}
+
+// scaffold implements Scaffold interface
+type scaffold struct {
+	// plugins is the list of plugins we should allow to transform our generated scaffolding
+	plugins []model.Plugin
+
+	// fs allows to mock the file system for tests
+	fs filesystem.FileSystem
+}
+
+// NewScaffold returns a new Scaffold with the provided plugins
+func NewScaffold(plugins ...model.Plugin) Scaffold {
+	return &scaffold{
+		plugins: plugins,
+		fs:      filesystem.New(),
+	}
+}
+
+// Execute implements Scaffold.Execute
+func (s *scaffold) Execute(universe *model.Universe, files ...file.Builder) error {
+	// Initialize the universe files
+	universe.Files = make(map[string]*file.File, len(files))
+
+	// Set the repo as the local prefix so that it knows how to group imports
+	if universe.Config != nil {
+		imports.LocalPrefix = universe.Config.Repo
+	}
+
+	// Templates are rendered concurrently in batches, and each batch is rendered before running the next
+	// inserter so that code fragments are always inserted in order into the content they expect
+	var batch []templateModel
+	for _, f := range files {
+		// Inject common fields
+		universe.InjectInto(f)
+
+		// Validate file builders
+		if reqValFile, requiresValidation := f.(file.RequiresValidation); requiresValidation {
+			if err := reqValFile.Validate(); err != nil {
+				return file.NewValidateError(err)
+			}
+		}
+
+		// Build models for Template builders
+		if t, isTemplate := f.(file.Template); isTemplate {
+			m, err := s.buildFileModel(t, universe.Files)
+			if err != nil {
+				return err
+			}
+			if m != nil {
+				batch = append(batch, templateModel{t, m})
+			}
+		}
+
+		// Build models for Inserter builders
+		if i, isInserter := f.(file.Inserter); isInserter {
+			if err := renderTemplates(batch); err != nil {
+				return err
+			}
+			batch = nil
+
+			if err := s.updateFileModel(i, universe.Files); err != nil {
+				return err
+			}
+		}
+	}
+	if err := renderTemplates(batch); err != nil {
+		return err
+	}
+
+	// Execute plugins
+	for _, plugin := range s.plugins {
+		if err := plugin.Pipe(universe); err != nil {
+			return model.NewPluginError(err)
+		}
+	}
+
+	// Persist the files to disk
+	return s.writeFiles(universe.Files)
+}
+
+// templateModel is a model waiting for the contents of its template to be rendered
+type templateModel struct {
+	template file.Template
+	model    *file.File
+}
+
+// buildFileModel builds the model of a single file, returning nil if the template has to be skipped.
+// The contents of the returned model are not set until the template is rendered.
+func (scaffold) buildFileModel(t file.Template, models map[string]*file.File) (*file.File, error) {
+	// Set the template default values
+	err := t.SetTemplateDefaults()
+	if err != nil {
+		return nil, file.NewSetTemplateDefaultsError(err)
+	}
+
+	// Handle already existing models
+	if _, found := models[t.GetPath()]; found {
+		switch t.GetIfExistsAction() {
+		case file.Skip:
+			return nil, nil
+		case file.Error:
+			return nil, modelAlreadyExistsError{t.GetPath()}
+		case file.Overwrite, file.MergeWithMarkers:
+		default:
+			return nil, unknownIfExistsActionError{t.GetPath(), t.GetIfExistsAction()}
+		}
+	}
+
+	m := &file.File{
+		Path:           t.GetPath(),
+		IfExistsAction: t.GetIfExistsAction(),
+	}
+	if p, hasPermissions := t.(file.HasPermissions); hasPermissions {
+		m.Permissions = p.GetPermissions()
+	}
+
+	models[m.Path] = m
+	return m, nil
+}
+
+// renderTemplates sets the contents of each model from its template, rendering them concurrently.
+// If several templates fail, the error of the first one is returned.
+func renderTemplates(batch []templateModel) error {
+	errs := make([]error, len(batch))
+	parallelize(len(batch), func(i int) {
+		b, err := doTemplate(batch[i].template)
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		batch[i].model.Contents = string(b)
+	})
+	return firstError(errs)
+}
+
+// parallelize calls do for every index in [0, n), using at most as many goroutines as usable CPUs
+func parallelize(n int, do func(i int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				do(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// firstError returns the first non-nil error of errs
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// doTemplate executes the template for a file using the input
+func doTemplate(t file.Template) ([]byte, error) {
+	temp, err := newTemplate(t).Parse(t.GetBody())
+	if err != nil {
+		return nil, err
+	}
+
+	out := &bytes.Buffer{}
+	err = temp.Execute(out, t)
+	if err != nil {
+		return nil, err
+	}
+	b := out.Bytes()
+
+	// TODO(adirio): move go-formatting to write step
+	// gofmt the imports
+	if filepath.Ext(t.GetPath()) == ".go" {
+		b, err = imports.Process(t.GetPath(), b, &options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// newTemplate a new template with common functions and the delimiters of t
+func newTemplate(t file.Template) *template.Template {
+	fm := file.DefaultFuncMap()
+	useFM, ok := t.(file.UseCustomFuncMap)
+	if ok {
+		fm = useFM.GetFuncMap()
+	}
+	temp := template.New(fmt.Sprintf("%T", t)).Funcs(fm)
+	if useDelims, ok := t.(file.UseCustomDelimiters); ok {
+		temp = temp.Delims(useDelims.GetDelimiters())
+	}
+	return temp
+}
+
+// updateFileModel updates a single file
+func (s scaffold) updateFileModel(i file.Inserter, models map[string]*file.File) error {
+	m, err := s.loadPreviousModel(i, models)
+	if err != nil {
+		return err
+	}
+
+	if p, hasPermissions := i.(file.HasPermissions); hasPermissions && p.GetPermissions() != 0 {
+		m.Permissions = p.GetPermissions()
+	}
+
+	// Get valid code fragments
+	codeFragments := getValidCodeFragments(i)
+
+	// Remove code fragments that already were applied
+	filterExistingValues(m.Contents, codeFragments)
+
+	// If no code fragment to insert, we are done
+	if len(codeFragments) == 0 {
+		return nil
+	}
+
+	content, err := insertStrings(m.Contents, codeFragments)
+	if err != nil {
+		return err
+	}
+
+	// TODO(adirio): move go-formatting to write step
+	formattedContent := content
+	if ext := filepath.Ext(i.GetPath()); ext == ".go" {
+		formattedContent, err = imports.Process(i.GetPath(), content, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	m.Contents = string(formattedContent)
+	m.IfExistsAction = file.Overwrite
+	models[m.Path] = m
+	return nil
+}
+
+// loadPreviousModel gets the previous model from the models map or the actual file
+func (s scaffold) loadPreviousModel(i file.Inserter, models map[string]*file.File) (*file.File, error) {
+	// Lets see if we already have a model for this file
+	if m, found := models[i.GetPath()]; found {
+		// Check if there is already an scaffolded file
+		exists, err := s.fs.Exists(i.GetPath())
+		if err != nil {
+			return nil, err
+		}
+
+		// If there is a model but no scaffolded file we return the model
+		if !exists {
+			return m, nil
+		}
+
+		// If both a model and a file are found, check which has preference
+		switch m.IfExistsAction {
+		case file.Skip:
+			// File has preference
+			fromFile, err := s.loadModelFromFile(i.GetPath())
+			if err != nil {
+				return m, nil
+			}
+			return fromFile, nil
+		case file.Error:
+			// Writing will result in an error, so we can return error now
+			return nil, fileAlreadyExistsError{i.GetPath()}
+		case file.Overwrite, file.MergeWithMarkers:
+			// Model has preference, the file is merged into it when writing if required
+			return m, nil
+		default:
+			return nil, unknownIfExistsActionError{i.GetPath(), m.IfExistsAction}
+		}
+	}
+
+	// There was no model
+	return s.loadModelFromFile(i.GetPath())
+}
+
+// loadModelFromFile gets the previous model from the actual file
+func (s scaffold) loadModelFromFile(path string) (f *file.File, err error) {
+	reader, err := s.fs.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		closeErr := reader.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return
+	}
+
+	f = &file.File{Path: path, Contents: string(content)}
+	return
+}
+
+// getValidCodeFragments obtains the code fragments from a file.Inserter
+func getValidCodeFragments(i file.Inserter) file.CodeFragmentsMap {
+	// Get the code fragments
+	codeFragments := i.GetCodeFragments()
+
+	// Validate the code fragments
+	validMarkers := i.GetMarkers()
+	for marker := range codeFragments {
+		valid := false
+		for _, validMarker := range validMarkers {
+			if marker == validMarker {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			delete(codeFragments, marker)
+		}
+	}
+
+	return codeFragments
+}
+
+// filterExistingValues removes the values that already exist, a multi-line value existing if its lines are found
+// consecutively in content. Lines are compared ignoring the amount of spaces, as the formatting of the file may have
+// changed their indentation and alignment.
+func filterExistingValues(content string, codeFragmentsMap file.CodeFragmentsMap) {
+	contentLines := normalizedLines(content)
+	for marker, codeFragments := range codeFragmentsMap {
+		missing := make([]string, 0, len(codeFragments))
+		for _, codeFragment := range codeFragments {
+			if !containsLines(contentLines, normalizedLines(codeFragment)) {
+				missing = append(missing, codeFragment)
+			}
+		}
+		if len(missing) == 0 {
+			delete(codeFragmentsMap, marker)
+		} else {
+			codeFragmentsMap[marker] = missing
+		}
+	}
+}
+
+// normalizedLines returns the lines of s with their words separated by a single space, ignoring the empty lines at
+// the beginning and at the end of s.
+func normalizedLines(s string) []string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return lines
+}
+
+// containsLines returns true if lines contains all the lines of sub consecutively
+func containsLines(lines, sub []string) bool {
+	for i := 0; i+len(sub) <= len(lines); i++ {
+		found := true
+		for j := range sub {
+			if lines[i+j] != sub[j] {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+func insertStrings(content string, codeFragmentsMap file.CodeFragmentsMap) ([]byte, error) {
+	out := new(bytes.Buffer)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		for marker, codeFragments := range codeFragmentsMap {
+			if strings.TrimSpace(line) == strings.TrimSpace(marker.String()) {
+				for _, codeFragment := range codeFragments {
+					_, _ = out.WriteString(codeFragment) // bytes.Buffer.WriteString always returns nil errors
+				}
+			}
+		}
+
+		_, _ = out.WriteString(line + "\n") // bytes.Buffer.WriteString always returns nil errors
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// writeFiles writes the models concurrently, as each of them targets a different path.
+// If several files fail to be written, the error of the first one by path is returned.
+func (s scaffold) writeFiles(models map[string]*file.File) error {
+	paths := make([]string, 0, len(models))
+	for path := range models {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	if err := s.resolveExistingFiles(paths, models); err != nil {
+		return err
+	}
+
+	errs := make([]error, len(paths))
+	parallelize(len(paths), func(i int) {
+		errs[i] = s.writeFile(models[paths[i]])
+	})
+	return firstError(errs)
+}
+
+// resolveExistingFiles lets the resolver set, if any, decide in order of path whether the existing files that
+// would be skipped or fail to be written are overwritten.
+func (s scaffold) resolveExistingFiles(paths []string, models map[string]*file.File) error {
+	resolve := getExistingFileResolver()
+	if resolve == nil {
+		return nil
+	}
+	for _, path := range paths {
+		f := models[path]
+		if f.IfExistsAction != file.Skip && f.IfExistsAction != file.Error {
+			continue
+		}
+		exists, err := s.fs.Exists(f.Path)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if resolve(f.Path) {
+			f.IfExistsAction = file.Overwrite
+		} else {
+			f.IfExistsAction = file.Skip
+		}
+	}
+	return nil
+}
+
+func (s scaffold) writeFile(f *file.File) error {
+	// Check if the file to write already exists
+	exists, err := s.fs.Exists(f.Path)
+	if err != nil {
+		return err
+	}
+	if exists {
+		switch f.IfExistsAction {
+		case file.Overwrite:
+			// By not returning, the file is written as if it didn't exist
+		case file.MergeWithMarkers:
+			// By not returning, the file is written with the code fragments inserted in the existing one
+			if err := s.mergeWithMarkers(f); err != nil {
+				return err
+			}
+		case file.Skip:
+			// By returning nil, the file is not written but the process will carry on
+			logger.Default().Debug("skipping existing file", "path", f.Path)
+			return nil
+		case file.Error:
+			// By returning an error, the file is not written and the process will fail
+			return fileAlreadyExistsError{f.Path}
+		default:
+			return unknownIfExistsActionError{f.Path, f.IfExistsAction}
+		}
+	}
+
+	logger.Default().Debug("writing file", "path", f.Path)
+	writer, err := s.fs.Create(f.Path)
+	if err != nil {
+		return err
+	}
+
+	if _, err = writer.Write([]byte(f.Contents)); err != nil {
+		return err
+	}
+
+	// Permissions are set explicitly as they are only applied by the file system on creation
+	if f.Permissions != 0 {
+		return s.fs.Chmod(f.Path, f.Permissions)
+	}
+	return nil
+}
+
+// mergeWithMarkers adds to f the code fragments inserted right before each marker of the existing file,
+// if the contents of f have the same marker. Code fragments are told apart from the rest of the file by
+// comparing the lines preceding each marker in both contents: the lines that follow their common prefix
+// in the existing file, and do not follow it in f, are kept.
+func (s scaffold) mergeWithMarkers(f *file.File) error {
+	existing, err := s.loadModelFromFile(f.Path)
+	if err != nil {
+		return err
+	}
+
+	existingSegments := splitByMarkers(existing.Contents)
+	var merged, segment []string
+	for _, line := range strings.Split(f.Contents, "\n") {
+		if !file.IsMarkerLine(line) {
+			segment = append(segment, line)
+			merged = append(merged, line)
+			continue
+		}
+		if existingSegment, found := existingSegments[strings.TrimSpace(line)]; found {
+			merged = append(merged, insertedLines(existingSegment, segment)...)
+		}
+		segment = nil
+		merged = append(merged, line)
+	}
+	content := []byte(strings.Join(merged, "\n"))
+
+	if filepath.Ext(f.Path) == ".go" {
+		if content, err = imports.Process(f.Path, content, nil); err != nil {
+			return err
+		}
+	}
+	f.Contents = string(content)
+	return nil
+}
+
+// splitByMarkers returns the lines preceding each marker of content, up to the previous marker, by marker.
+func splitByMarkers(content string) map[string][]string {
+	segments := make(map[string][]string)
+	var segment []string
+	for _, line := range strings.Split(content, "\n") {
+		if file.IsMarkerLine(line) {
+			segments[strings.TrimSpace(line)] = segment
+			segment = nil
+			continue
+		}
+		segment = append(segment, line)
+	}
+	return segments
+}
+
+// insertedLines returns the lines of existing that follow their common prefix with updated and are not
+// part of the rest of updated, all lines being compared without surrounding spaces.
+func insertedLines(existing, updated []string) []string {
+	common := 0
+	for common < len(existing) && common < len(updated) &&
+		strings.TrimSpace(existing[common]) == strings.TrimSpace(updated[common]) {
+		common++
+	}
+
+	updatedRest := make(map[string]struct{}, len(updated)-common)
+	for _, line := range updated[common:] {
+		updatedRest[strings.TrimSpace(line)] = struct{}{}
+	}
+	var inserted []string
+	for _, line := range existing[common:] {
+		if _, found := updatedRest[strings.TrimSpace(line)]; !found {
+			inserted = append(inserted, line)
+		}
+	}
+	return inserted
+}
@@ -17,15 +17,18 @@ package machinery
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	"sigs.k8s.io/kubebuilder/pkg/machinery/internal/filesystem"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/filesystem"
 )
 
 func TestScaffold(t *testing.T) {
@@ -140,6 +143,26 @@ var _ = Describe("Scaffold", func() {
 				"package file\n",
 				fakeTemplate{fakeBuilder: fakeBuilder{path: "file.go"}, body: "package    file"},
 			),
+			Entry("should use custom delimiters",
+				"chart: {{ .Values.name }}\npath: file.yaml",
+				fakeDelimsTemplate{
+					fakeTemplate: fakeTemplate{
+						fakeBuilder: fakeBuilder{path: "file.yaml"},
+						body:        "chart: {{ .Values.name }}\npath: [[ .GetPath ]]",
+					},
+					left: "[[", right: "]]",
+				},
+			),
+			Entry("should render YAML with the default functions",
+				"spec:\n  replicas: 1\n  selector:\n    app: manager",
+				fakeTemplate{
+					fakeBuilder: fakeBuilder{path: "file.yaml"},
+					body: "spec:\n" +
+						`{{ toYaml (index .Values 0) | indent 2 }}` + "\n" +
+						`{{ "selector:\n  app: manager" | indent 2 }}`,
+					values: []interface{}{map[string]int{"replicas": 1}},
+				},
+			),
 		)
 
 		DescribeTable("file builders related errors",
@@ -258,6 +281,17 @@ var _ = Describe("Scaffold", func() {
 					file.NewMarkerFor("file.go", "-"): {"1\n", "2\n"}},
 				},
 			),
+			Entry("should overwrite models updated by previous inserters",
+				"",
+				fileContent,
+				fakeTemplate{fakeBuilder: fakeBuilder{ifExistsAction: file.Overwrite}, body: `
+// +kubebuilder:scaffold:-
+`},
+				fakeInserter{codeFragments: file.CodeFragmentsMap{
+					file.NewMarkerFor("file.go", "-"): {"1\n", "2\n"}},
+				},
+				fakeTemplate{fakeBuilder: fakeBuilder{ifExistsAction: file.Overwrite}, body: fileContent},
+			),
 			Entry("should use files over optional models",
 				`
 // +kubebuilder:scaffold:-
@@ -316,6 +350,28 @@ var _ = Describe("Scaffold", func() {
 					},
 				},
 			),
+			Entry("should filter already existing multi-line code fragments",
+				`
+if a {
+	b(x,   y)
+}
+// +kubebuilder:scaffold:-
+`,
+				`
+if a {
+	b(x,   y)
+}
+if a {
+	c()
+}
+// +kubebuilder:scaffold:-
+`,
+				fakeInserter{
+					codeFragments: file.CodeFragmentsMap{
+						file.NewMarkerFor("file.go", "-"): {"if a {\n\tb(x, y)\n}\n", "if a {\n\tc()\n}\n"},
+					},
+				},
+			),
 			Entry("should not insert anything if no code fragment",
 				"", // input is provided through a template as mock fs doesn't copy it to the output buffer if no-op
 				`
@@ -407,6 +463,143 @@ var _ = Describe("Scaffold", func() {
 				Expect(IsFileAlreadyExistsError(err)).To(BeTrue())
 				Expect(output.String()).To(BeEmpty())
 			})
+
+			It("should keep the code inserted before markers if configured to do so", func() {
+				s = &scaffold{
+					fs: filesystem.NewMock(
+						filesystem.MockExists(func(_ string) bool { return true }),
+						filesystem.MockInput(bytes.NewBufferString(
+							"kind: List\nitems:\n- old\n- inserted 1\n- inserted 2\n# +kubebuilder:scaffold:items\n")),
+						filesystem.MockOutput(&output),
+					),
+				}
+				Expect(s.Execute(
+					model.NewUniverse(),
+					fakeTemplate{
+						fakeBuilder: fakeBuilder{path: "list.yaml", ifExistsAction: file.MergeWithMarkers},
+						body:        "apiVersion: v1\nkind: List\nitems:\n- new\n# +kubebuilder:scaffold:items\n",
+					},
+				)).To(Succeed())
+				Expect(output.String()).To(Equal(
+					"apiVersion: v1\nkind: List\nitems:\n- new\n- old\n- inserted 1\n- inserted 2\n" +
+						"# +kubebuilder:scaffold:items\n"))
+			})
+
+			It("should error if the action is unknown", func() {
+				err := s.Execute(
+					model.NewUniverse(),
+					fakeTemplate{fakeBuilder: fakeBuilder{path: "filename", ifExistsAction: 10}, body: fileContent},
+				)
+				Expect(err).To(HaveOccurred())
+				Expect(IsUnknownIfExistsActionError(err)).To(BeTrue())
+				Expect(output.String()).To(BeEmpty())
+			})
+
+			Context("with an existing file resolver", func() {
+				var resolved []string
+
+				BeforeEach(func() {
+					resolved = nil
+				})
+
+				AfterEach(func() {
+					SetExistingFileResolver(nil)
+				})
+
+				It("should overwrite the files it decides to", func() {
+					SetExistingFileResolver(func(path string) bool {
+						resolved = append(resolved, path)
+						return true
+					})
+					Expect(s.Execute(
+						model.NewUniverse(),
+						fakeTemplate{fakeBuilder: fakeBuilder{path: "filename", ifExistsAction: file.Error}, body: fileContent},
+					)).To(Succeed())
+					Expect(resolved).To(Equal([]string{"filename"}))
+					Expect(output.String()).To(Equal(fileContent))
+				})
+
+				It("should keep the files it decides to", func() {
+					SetExistingFileResolver(func(path string) bool {
+						resolved = append(resolved, path)
+						return false
+					})
+					Expect(s.Execute(
+						model.NewUniverse(),
+						fakeTemplate{fakeBuilder: fakeBuilder{path: "filename", ifExistsAction: file.Error}, body: fileContent},
+					)).To(Succeed())
+					Expect(resolved).To(Equal([]string{"filename"}))
+					Expect(output.String()).To(BeEmpty())
+				})
+
+				It("should not be consulted for the files that are overwritten anyway", func() {
+					SetExistingFileResolver(func(path string) bool {
+						resolved = append(resolved, path)
+						return false
+					})
+					Expect(s.Execute(
+						model.NewUniverse(),
+						fakeTemplate{fakeBuilder: fakeBuilder{path: "filename", ifExistsAction: file.Overwrite}, body: fileContent},
+					)).To(Succeed())
+					Expect(resolved).To(BeEmpty())
+					Expect(output.String()).To(Equal(fileContent))
+				})
+			})
+		})
+
+		It("should set the permissions of files", func() {
+			permissions := make(map[string]os.FileMode)
+			s := &scaffold{fs: filesystem.NewMock(filesystem.MockPermissions(permissions))}
+			Expect(s.Execute(
+				model.NewUniverse(),
+				fakePermissionsTemplate{
+					fakeTemplate: fakeTemplate{fakeBuilder: fakeBuilder{path: "hack/script.sh"}, body: fileContent},
+					permissions:  0755,
+				},
+				fakeTemplate{fakeBuilder: fakeBuilder{path: "README.md"}, body: fileContent},
+			)).To(Succeed())
+			Expect(permissions).To(Equal(map[string]os.FileMode{"hack/script.sh": 0755}))
+		})
+
+		It("should render and write every file of large scaffolds", func() {
+			const files = 100
+			permissions := make(map[string]os.FileMode, files)
+			s := &scaffold{fs: filesystem.NewMock(
+				filesystem.MockPermissions(permissions),
+				filesystem.MockOutput(&output),
+			)}
+
+			builders := make([]file.Builder, 0, files)
+			expected := make(map[string]os.FileMode, files)
+			for i := 0; i < files; i++ {
+				path := fmt.Sprintf("file%d.txt", i)
+				builders = append(builders, fakePermissionsTemplate{
+					fakeTemplate: fakeTemplate{fakeBuilder: fakeBuilder{path: path}, body: fileContent},
+					permissions:  os.FileMode(0600 + i),
+				})
+				expected[path] = os.FileMode(0600 + i)
+			}
+
+			Expect(s.Execute(model.NewUniverse(), builders...)).To(Succeed())
+			Expect(permissions).To(Equal(expected))
+			Expect(output.String()).To(Equal(strings.Repeat(fileContent, files)))
+		})
+
+		It("should return the error of the first broken template", func() {
+			broken := fakeTemplate{fakeBuilder: fakeBuilder{path: "broken.txt"}, body: "{{ .Field }"}
+			_, expectedErr := doTemplate(broken)
+			Expect(expectedErr).To(HaveOccurred())
+
+			builders := []file.Builder{broken}
+			for i := 0; i < 50; i++ {
+				builders = append(builders,
+					fakeTemplate{fakeBuilder: fakeBuilder{path: fmt.Sprintf("file%d.txt", i)}, body: fileContent},
+					fakeTemplate{fakeBuilder: fakeBuilder{path: fmt.Sprintf("file%d.go", i)}, body: fileContent},
+				)
+			}
+
+			s := &scaffold{fs: filesystem.NewMock()}
+			Expect(s.Execute(model.NewUniverse(), builders...)).To(MatchError(expectedErr.Error()))
 		})
 
 		DescribeTable("filesystem errors",
@@ -516,8 +709,14 @@ var _ file.Template = fakeTemplate{}
 type fakeTemplate struct {
 	fakeBuilder
 
-	body string
-	err  error
+	body   string
+	err    error
+	values []interface{}
+}
+
+// Values are used to render the template body
+func (f fakeTemplate) Values() []interface{} {
+	return f.values
 }
 
 // GetBody implements file.Template
@@ -534,6 +733,34 @@ func (f fakeTemplate) SetTemplateDefaults() error {
 	return nil
 }
 
+var _ file.HasPermissions = fakePermissionsTemplate{}
+
+// fakePermissionsTemplate is used to mock a file.Template with specific permissions in order to test Scaffold
+type fakePermissionsTemplate struct {
+	fakeTemplate
+
+	permissions os.FileMode
+}
+
+// GetPermissions implements file.HasPermissions
+func (f fakePermissionsTemplate) GetPermissions() os.FileMode {
+	return f.permissions
+}
+
+var _ file.UseCustomDelimiters = fakeDelimsTemplate{}
+
+// fakeDelimsTemplate is used to mock a file.Template with custom delimiters in order to test Scaffold
+type fakeDelimsTemplate struct {
+	fakeTemplate
+
+	left, right string
+}
+
+// GetDelimiters implements file.UseCustomDelimiters
+func (f fakeDelimsTemplate) GetDelimiters() (string, string) {
+	return f.left, f.right
+}
+
 type fakeInserter struct {
 	fakeBuilder
 
@@ -18,6 +18,8 @@ package config
 
 import (
 	"fmt"
+	"path"
+	"regexp"
 	"strings"
 
 	"sigs.k8s.io/yaml"
@@ -30,6 +32,46 @@ const (
 	Version3Alpha = "3-alpha"
 )
 
+// Test frameworks
+const (
+	TestFrameworkGinkgo   = "ginkgo"
+	TestFrameworkGinkgoV2 = "ginkgo-v2"
+	TestFrameworkGoTest   = "gotest"
+)
+
+// Ways the fields of a resource are defaulted
+const (
+	// DefaultsMarkers defaults the fields with the +kubebuilder:default markers of the types, applied by the API server
+	DefaultsMarkers = "markers"
+	// DefaultsWebhook defaults the fields with a defaulting webhook
+	DefaultsWebhook = "webhook"
+)
+
+// Layout profiles, selecting the directories of the Go packages of a project
+const (
+	// ProfileDefault lays out the APIs in api/, or apis/ for multi-group projects, and the controllers in
+	// controllers/
+	ProfileDefault = "default"
+	// ProfileInternal lays out the APIs like ProfileDefault and the controllers in internal/controller/, so that
+	// other modules can not import them
+	ProfileInternal = "internal"
+	// ProfileLegacy lays out the APIs in pkg/apis/ and the controllers in pkg/controller/, as kubebuilder v1 did
+	ProfileLegacy = "legacy"
+)
+
+// Profiles are the supported layout profiles
+var Profiles = []string{ProfileDefault, ProfileInternal, ProfileLegacy}
+
+// Defaults of the webhook server of the manager, the ones of controller-runtime in the manager container
+const (
+	DefaultWebhookPort    = 9443
+	DefaultWebhookCertDir = "/tmp/k8s-webhook-server/serving-certs"
+)
+
+// DefaultManifestImage is the image of the manager manifest of the projects without an Image, replaced with the
+// IMG of the Makefile on deployment
+const DefaultManifestImage = "controller:latest"
+
 // Config is the unmarshalled representation of the configuration file
 type Config struct {
 	// Version is the project version, defaults to "1" (backwards compatibility)
@@ -41,9 +83,27 @@ type Config struct {
 	// Repo is the go package name of the project root
 	Repo string `json:"repo,omitempty"`
 
+	// BasePath is the path of the project relative to the root of the Go module it belongs to, set on
+	// initialization in a subdirectory of an existing module, whose go.mod is then shared with the project
+	BasePath string `json:"basePath,omitempty"`
+
+	// Workspace is the path of the project relative to the directory of the go.work file using its module, "."
+	// if they are the same, set on initialization if there is such a file
+	Workspace string `json:"workspace,omitempty"`
+
 	// ProjectName is the name of this controller project set on initialization.
 	ProjectName string `json:"projectName,omitempty"`
 
+	// Namespace is the namespace the manifests deploy the project into, set on initialization
+	Namespace string `json:"namespace,omitempty"`
+
+	// NamePrefix is prepended to the names of the resources deployed by the manifests, set on initialization
+	NamePrefix string `json:"namePrefix,omitempty"`
+
+	// Image is the reference of the manager image, the default IMG of the Makefile and the image of the manager
+	// manifest, set on initialization or with edit --image
+	Image string `json:"image,omitempty"`
+
 	// Resources tracks scaffolded resources in the project
 	// This info is tracked only in project with version 2
 	Resources []GVK `json:"resources,omitempty"`
@@ -51,6 +111,75 @@ type Config struct {
 	// Multigroup tracks if the project has more than one group
 	MultiGroup bool `json:"multigroup,omitempty"`
 
+	// APIsModule tracks if the Go types of the APIs are in a Go module of their own, set on initialization, so that
+	// other projects can import them without the dependencies of the manager
+	APIsModule bool `json:"apisModule,omitempty"`
+
+	// Values are the values set on initialization with --set, exposed to the templates of every command as .Values
+	Values map[string]string `json:"values,omitempty"`
+
+	// Profile is the layout profile selecting the directories of the APIs and controllers, set on
+	// initialization, ProfileDefault is used if empty
+	Profile string `json:"profile,omitempty"`
+
+	// Offline tracks if the project is scaffolded without network access, in which case
+	// commands neither download dependencies nor run make
+	Offline bool `json:"offline,omitempty"`
+
+	// SkipGoMod tracks if the commands leave go.mod and go.sum untouched, set on initialization, in which case
+	// the dependencies of the project are managed separately, e.g. with bazel
+	SkipGoMod bool `json:"skipGoMod,omitempty"`
+
+	// TestFramework is the framework the scaffolded tests are written with, set on initialization, Ginkgo v1 is
+	// used if empty
+	TestFramework string `json:"testFramework,omitempty"`
+
+	// WebhookPort, WebhookHost and WebhookCertDir configure the webhook server of the manager, set on
+	// initialization or when creating a webhook. The defaults are used if unset
+	WebhookPort    int    `json:"webhookPort,omitempty"`
+	WebhookHost    string `json:"webhookHost,omitempty"`
+	WebhookCertDir string `json:"webhookCertDir,omitempty"`
+
+	// ComponentConfig tracks if the manager loads its options from a ControllerManagerConfig file, deployed in a
+	// ConfigMap by the manifests, instead of from command-line flags
+	ComponentConfig bool `json:"componentConfig,omitempty"`
+
+	// MetricsAuthFilter tracks if the manager authenticates and authorizes the requests to its metrics endpoint
+	// itself, instead of the kube-rbac-proxy sidecar the manifests deploy otherwise
+	MetricsAuthFilter bool `json:"metricsAuthFilter,omitempty"`
+
+	// Pprof tracks if the manager can serve its runtime profiles on a pprof endpoint, set on initialization and
+	// disabled by the manifests
+	Pprof bool `json:"pprof,omitempty"`
+
+	// Tracing tracks if the manager exports the traces of the reconciliations with OpenTelemetry, set on
+	// initialization, the controllers created afterwards starting a span for each of them
+	Tracing bool `json:"tracing,omitempty"`
+
+	// ZapFlags tracks if the manager binds the zap options of its logger to command-line flags, set on
+	// initialization, the manifests logging JSON at the info level
+	ZapFlags bool `json:"zapFlags,omitempty"`
+
+	// NamespaceScoped tracks if the manager only watches the namespaces of its WATCH_NAMESPACE variable, set on
+	// initialization, the manifests granting it Roles in its namespace instead of ClusterRoles
+	NamespaceScoped bool `json:"namespaceScoped,omitempty"`
+
+	// WatchNamespaces are the namespaces the cache of the manager is restricted to by its manifests, set on
+	// initialization, the manager keeping the cluster-wide permissions to access them
+	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
+
+	// HighAvailability tracks if the manager is scaffolded for high availability, set on initialization, its
+	// leader election tolerating an unavailability of the API server and the manifests running several replicas
+	HighAvailability bool `json:"highAvailability,omitempty"`
+
+	// TypedClients tracks if typed clientsets, listers and informers are generated for the resources with
+	// code-generator, set on initialization
+	TypedClients bool `json:"typedClients,omitempty"`
+
+	// History is the list of the scaffolding commands that were run in the project, in order, only recorded in
+	// projects with version 3-alpha
+	History []RecordedCommand `json:"history,omitempty"`
+
 	// Layout contains a key specifying which plugin created a project.
 	Layout string `json:"layout,omitempty"`
 
@@ -59,6 +188,14 @@ type Config struct {
 	Plugins PluginConfigs `json:"plugins,omitempty"`
 }
 
+// RecordedCommand is a scaffolding command recorded in the history of the project
+type RecordedCommand struct {
+	// Command is the path of the command below the root one, e.g. "create api"
+	Command string `json:"command"`
+	// Flags are the flags that were set, sorted by name, as --name=value
+	Flags []string `json:"flags,omitempty"`
+}
+
 // PluginConfigs holds a set of arbitrary plugin configuration objects mapped by plugin key.
 type PluginConfigs map[string]pluginConfig
 
@@ -80,6 +217,126 @@ func (c Config) IsV3() bool {
 	return c.Version == Version3Alpha
 }
 
+// UsesGinkgoV2 returns true if the scaffolded tests are written with Ginkgo v2
+func (c Config) UsesGinkgoV2() bool {
+	return c.TestFramework == TestFrameworkGinkgoV2
+}
+
+// UsesGoTest returns true if the scaffolded tests are written with the standard testing package
+func (c Config) UsesGoTest() bool {
+	return c.TestFramework == TestFrameworkGoTest
+}
+
+// GetWebhookPort returns the port the webhook server of the manager listens on
+func (c Config) GetWebhookPort() int {
+	if c.WebhookPort == 0 {
+		return DefaultWebhookPort
+	}
+	return c.WebhookPort
+}
+
+// GetWebhookCertDir returns the directory the webhook server of the manager reads its certificate from
+func (c Config) GetWebhookCertDir() string {
+	if c.WebhookCertDir == "" {
+		return DefaultWebhookCertDir
+	}
+	return c.WebhookCertDir
+}
+
+// GetManifestImage returns the image of the manager manifest, Image or DefaultManifestImage if it is unset
+func (c Config) GetManifestImage() string {
+	if c.Image == "" {
+		return DefaultManifestImage
+	}
+	return c.Image
+}
+
+// imageRegexp matches the image references: an optional registry host, the slash-separated lowercase path
+// components, an optional tag and an optional digest
+var imageRegexp = regexp.MustCompile(`^([a-zA-Z0-9.-]+(:[0-9]+)?/)?[a-z0-9]+([._-][a-z0-9]+)*` +
+	`(/[a-z0-9]+([._-][a-z0-9]+)*)*(:[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127})?(@sha256:[a-f0-9]{64})?$`)
+
+// ValidateImage returns an error if image is not an image reference, e.g. quay.io/acme/operator:v0.1.0
+func ValidateImage(image string) error {
+	if !imageRegexp.MatchString(image) {
+		return fmt.Errorf("invalid image %q, expected a reference such as quay.io/acme/operator:v0.1.0", image)
+	}
+	return nil
+}
+
+// ImageName returns the name of an image reference, without its tag and digest, which kustomize and Tilt match
+// the image of the manager manifest by
+func ImageName(image string) string {
+	if i := strings.Index(image, "@"); i != -1 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// Directories are the directories of the Go packages of a project, as slash-separated paths relative to its root
+type Directories struct {
+	// APIs is the directory of the Go types of the APIs
+	APIs string
+	// Types is the directory of the Go types of a group-version, with %[group] and %[version] placeholders
+	Types string
+	// Controllers is the directory of the controllers
+	Controllers string
+	// Controller is the directory of the controllers of a group, with a %[group] placeholder
+	Controller string
+	// ControllersPackage is the name of the Go package of the controllers
+	ControllersPackage string
+}
+
+// ValidateProfile returns an error if the layout profile of the project is not supported
+func (c Config) ValidateProfile() error {
+	switch c.Profile {
+	case "", ProfileDefault, ProfileInternal, ProfileLegacy:
+		return nil
+	default:
+		return fmt.Errorf("layout profile %q is not supported, possible values: (%s)",
+			c.Profile, strings.Join(Profiles, ", "))
+	}
+}
+
+// GetDirectories returns the directories of the Go packages of the project according to its layout profile
+func (c Config) GetDirectories() Directories {
+	var dirs Directories
+	switch c.Profile {
+	case ProfileLegacy:
+		dirs.APIs = path.Join("pkg", "apis")
+		dirs.Controllers = path.Join("pkg", "controller")
+	case ProfileInternal:
+		dirs.APIs = "api"
+		dirs.Controllers = path.Join("internal", "controller")
+	default:
+		dirs.APIs = "api"
+		dirs.Controllers = "controllers"
+	}
+	// Legacy projects always laid out the APIs by group
+	if c.MultiGroup && c.Profile != ProfileLegacy {
+		dirs.APIs = "apis"
+	}
+
+	dirs.Types = path.Join(dirs.APIs, "%[version]")
+	dirs.Controller = dirs.Controllers
+	if c.MultiGroup || c.Profile == ProfileLegacy {
+		dirs.Types = path.Join(dirs.APIs, "%[group]", "%[version]")
+	}
+	if c.MultiGroup {
+		dirs.Controller = path.Join(dirs.Controllers, "%[group]")
+	}
+	dirs.ControllersPackage = path.Base(dirs.Controllers)
+	return dirs
+}
+
+// TypesOf returns the directory of the Go types of version of group
+func (d Directories) TypesOf(group, version string) string {
+	return strings.NewReplacer("%[group]", group, "%[version]", version).Replace(d.Types)
+}
+
 // HasResource returns true if API resource is already tracked
 func (c Config) HasResource(target GVK) bool {
 	// Return true if the target resource is found in the tracked resources
@@ -112,6 +369,79 @@ func (c *Config) AddResource(gvk GVK) bool {
 	return true
 }
 
+// UpdateResource tracks the provided resource, updating the API versions of its manifests, the way it is defaulted
+// and its plural if it was already tracked
+// It returns if the configuration was modified
+// NOTE: in v1 resources are not tracked, so we return false
+func (c *Config) UpdateResource(gvk GVK) bool {
+	// Short-circuit v1
+	if c.IsV1() {
+		return false
+	}
+
+	for i, r := range c.Resources {
+		if !r.isEqualTo(gvk) {
+			continue
+		}
+
+		modified := false
+		if gvk.CRDVersion != "" && gvk.CRDVersion != r.CRDVersion {
+			c.Resources[i].CRDVersion = gvk.CRDVersion
+			modified = true
+		}
+		if gvk.WebhookVersion != "" && gvk.WebhookVersion != r.WebhookVersion {
+			c.Resources[i].WebhookVersion = gvk.WebhookVersion
+			modified = true
+		}
+		if gvk.Defaults != "" && gvk.Defaults != r.Defaults {
+			c.Resources[i].Defaults = gvk.Defaults
+			modified = true
+		}
+		// The webhooks are scaffolded additively, so they are only ever added here
+		if gvk.ValidatingWebhook && !r.ValidatingWebhook {
+			c.Resources[i].ValidatingWebhook = true
+			modified = true
+		}
+		if gvk.ConversionWebhook && !r.ConversionWebhook {
+			c.Resources[i].ConversionWebhook = true
+			modified = true
+		}
+		if gvk.Plural != "" && gvk.Plural != r.Plural {
+			c.Resources[i].Plural = gvk.Plural
+			modified = true
+		}
+		return modified
+	}
+
+	// Append the resource to the tracked ones, return true
+	c.Resources = append(c.Resources, gvk)
+	return true
+}
+
+// RecordCommand appends cmd to the history of the project
+// It returns if the configuration was modified
+func (c *Config) RecordCommand(cmd RecordedCommand) bool {
+	if !c.IsV3() {
+		return false
+	}
+	c.History = append(c.History, cmd)
+	return true
+}
+
+// RemoveResource stops tracking the provided resource, every entry equal to it is removed
+// It returns if the configuration was modified
+func (c *Config) RemoveResource(gvk GVK) bool {
+	resources := make([]GVK, 0, len(c.Resources))
+	for _, r := range c.Resources {
+		if !r.isEqualTo(gvk) {
+			resources = append(resources, r)
+		}
+	}
+	modified := len(resources) != len(c.Resources)
+	c.Resources = resources
+	return modified
+}
+
 // HasGroup returns true if group is already tracked
 func (c Config) HasGroup(group string) bool {
 	// Return true if the target group is found in the tracked resources
@@ -130,6 +460,26 @@ type GVK struct {
 	Group   string `json:"group,omitempty"`
 	Version string `json:"version,omitempty"`
 	Kind    string `json:"kind,omitempty"`
+
+	// Plural is the resource name of the Kind, only recorded if it is not the one computed from the Kind
+	Plural string `json:"plural,omitempty"`
+
+	// CRDVersion is the API version of the CustomResourceDefinition manifest of the resource
+	CRDVersion string `json:"crdVersion,omitempty"`
+
+	// WebhookVersion is the API version of the webhook configuration manifests of the resource
+	WebhookVersion string `json:"webhookVersion,omitempty"`
+
+	// Defaults is how the fields of the resource are defaulted, either by markers or by a defaulting webhook
+	Defaults string `json:"defaults,omitempty"`
+
+	// ValidatingWebhook and ConversionWebhook are true if the resource has a validating or a conversion webhook,
+	// its defaulting webhook being recorded by Defaults
+	ValidatingWebhook bool `json:"validatingWebhook,omitempty"`
+	ConversionWebhook bool `json:"conversionWebhook,omitempty"`
+
+	// Hub is true if the resource is the version of its Kind that the other versions are converted to and from
+	Hub bool `json:"hub,omitempty"`
 }
 
 // isEqualTo compares it with another resource
@@ -191,7 +541,8 @@ func (c *Config) EncodePluginConfig(key string, configObj interface{}) error {
 	if err != nil {
 		return fmt.Errorf("failed to convert %T object to bytes: %s", configObj, err)
 	}
-	var fields map[string]interface{}
+	// Any YAML value is supported, not only objects, e.g. a list of images.
+	var fields interface{}
 	if err := yaml.Unmarshal(b, &fields); err != nil {
 		return fmt.Errorf("failed to unmarshal %T object bytes: %s", configObj, err)
 	}
@@ -202,6 +553,14 @@ func (c *Config) EncodePluginConfig(key string, configObj interface{}) error {
 	return nil
 }
 
+// HasPluginConfig returns true if a plugin config is stored in c under key. It allows plugins to
+// tell apart a config that was never encoded from an empty one, since DecodePluginConfig leaves
+// configObj untouched in both cases.
+func (c Config) HasPluginConfig(key string) bool {
+	_, hasKey := c.Plugins[key]
+	return hasKey
+}
+
 // DecodePluginConfig decodes a plugin config stored in c into configObj, which must be a pointer
 // This method is intended to be used for custom configuration objects, which were introduced
 // in project version 3-alpha. EncodePluginConfig will return an error if used on any project version < v3.
@@ -17,6 +17,8 @@ limitations under the License.
 package config
 
 import (
+	"strings"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -86,6 +88,17 @@ var _ = Describe("Config", func() {
 		}
 		Expect(config.EncodePluginConfig(key, pluginConfig)).To(Succeed())
 		Expect(config).To(Equal(expectedConfig))
+
+		By("Using config version 3-alpha with extra fields as list")
+		config = Config{Version: Version3Alpha}
+		expectedConfig = Config{
+			Version: Version3Alpha,
+			Plugins: PluginConfigs{
+				"plugin-x": []interface{}{"plugin value 1", "plugin value 2"},
+			},
+		}
+		Expect(config.EncodePluginConfig(key, []string{"plugin value 1", "plugin value 2"})).To(Succeed())
+		Expect(config).To(Equal(expectedConfig))
 	})
 
 	It("should decode correctly", func() {
@@ -149,5 +162,174 @@ var _ = Describe("Config", func() {
 		}
 		Expect(config.DecodePluginConfig(key, &pluginConfig)).To(Succeed())
 		Expect(pluginConfig).To(Equal(expectedPluginConfig))
+
+		By("Using config version 3-alpha with extra fields as list")
+		config = Config{
+			Version: Version3Alpha,
+			Plugins: PluginConfigs{
+				"plugin-x": []interface{}{"plugin value 1", "plugin value 2"},
+			},
+		}
+		var values []string
+		Expect(config.DecodePluginConfig(key, &values)).To(Succeed())
+		Expect(values).To(Equal([]string{"plugin value 1", "plugin value 2"}))
+	})
+
+	It("should report stored plugin configs", func() {
+		config := Config{Version: Version3Alpha}
+		Expect(config.HasPluginConfig("plugin-x")).To(BeFalse())
+
+		Expect(config.EncodePluginConfig("plugin-x", struct{}{})).To(Succeed())
+		Expect(config.HasPluginConfig("plugin-x")).To(BeTrue())
+		Expect(config.HasPluginConfig("plugin-y")).To(BeFalse())
+	})
+
+	It("should default the webhook server options", func() {
+		config := Config{Version: Version3Alpha}
+		Expect(config.GetWebhookPort()).To(Equal(DefaultWebhookPort))
+		Expect(config.GetWebhookCertDir()).To(Equal(DefaultWebhookCertDir))
+
+		config.WebhookPort, config.WebhookCertDir = 9444, "/certs"
+		Expect(config.GetWebhookPort()).To(Equal(9444))
+		Expect(config.GetWebhookCertDir()).To(Equal("/certs"))
+	})
+
+	It("should default the image of the manager manifest", func() {
+		config := Config{Version: Version3Alpha}
+		Expect(config.GetManifestImage()).To(Equal(DefaultManifestImage))
+
+		config.Image = "quay.io/acme/operator:v0.1.0"
+		Expect(config.GetManifestImage()).To(Equal("quay.io/acme/operator:v0.1.0"))
+	})
+
+	It("should validate the image references and return their names", func() {
+		for image, name := range map[string]string{
+			"operator":                                        "operator",
+			"operator:latest":                                 "operator",
+			"quay.io/acme/operator:v0.1.0":                    "quay.io/acme/operator",
+			"localhost:5000/operator":                         "localhost:5000/operator",
+			"localhost:5000/operator:v0.1":                    "localhost:5000/operator",
+			"acme/operator@sha256:" + strings.Repeat("a", 64): "acme/operator",
+		} {
+			Expect(ValidateImage(image)).To(Succeed(), image)
+			Expect(ImageName(image)).To(Equal(name), image)
+		}
+		for _, image := range []string{"", "Operator", "quay.io/acme/operator:", "acme operator", "-operator"} {
+			Expect(ValidateImage(image)).NotTo(Succeed(), image)
+		}
+	})
+
+	It("should lay out the directories according to the profile", func() {
+		config := Config{Version: Version3Alpha}
+		Expect(config.ValidateProfile()).To(Succeed())
+		Expect(config.GetDirectories()).To(Equal(Directories{
+			APIs:               "api",
+			Types:              "api/%[version]",
+			Controllers:        "controllers",
+			Controller:         "controllers",
+			ControllersPackage: "controllers",
+		}))
+
+		config.MultiGroup = true
+		Expect(config.GetDirectories().Types).To(Equal("apis/%[group]/%[version]"))
+		Expect(config.GetDirectories().Controller).To(Equal("controllers/%[group]"))
+		Expect(config.GetDirectories().TypesOf("crew", "v1")).To(Equal("apis/crew/v1"))
+
+		config.MultiGroup, config.Profile = false, ProfileInternal
+		Expect(config.GetDirectories()).To(Equal(Directories{
+			APIs:               "api",
+			Types:              "api/%[version]",
+			Controllers:        "internal/controller",
+			Controller:         "internal/controller",
+			ControllersPackage: "controller",
+		}))
+
+		config.Profile = ProfileLegacy
+		Expect(config.GetDirectories()).To(Equal(Directories{
+			APIs:               "pkg/apis",
+			Types:              "pkg/apis/%[group]/%[version]",
+			Controllers:        "pkg/controller",
+			Controller:         "pkg/controller",
+			ControllersPackage: "controller",
+		}))
+
+		config.Profile = "flat"
+		Expect(config.ValidateProfile()).NotTo(Succeed())
+	})
+
+	It("should update tracked resources correctly", func() {
+		var (
+			config Config
+			gvk    = GVK{Group: "crew", Version: "v1", Kind: "FirstMate"}
+		)
+
+		By("Using config version 1")
+		config = Config{Version: Version1}
+		Expect(config.UpdateResource(gvk)).To(BeFalse())
+		Expect(config.Resources).To(BeEmpty())
+
+		By("Using config version 3-alpha with an untracked resource")
+		config = Config{Version: Version3Alpha}
+		gvk.CRDVersion = "v1"
+		Expect(config.UpdateResource(gvk)).To(BeTrue())
+		Expect(config.Resources).To(Equal([]GVK{gvk}))
+
+		By("Using config version 3-alpha with a tracked resource")
+		Expect(config.UpdateResource(GVK{Group: "crew", Version: "v1", Kind: "FirstMate", WebhookVersion: "v1"})).
+			To(BeTrue())
+		Expect(config.Resources).To(Equal([]GVK{
+			{Group: "crew", Version: "v1", Kind: "FirstMate", CRDVersion: "v1", WebhookVersion: "v1"},
+		}))
+
+		By("Using config version 3-alpha with a tracked resource and a new plural")
+		Expect(config.UpdateResource(GVK{Group: "crew", Version: "v1", Kind: "FirstMate", Plural: "mates"})).
+			To(BeTrue())
+		Expect(config.Resources[0].Plural).To(Equal("mates"))
+
+		By("Using config version 3-alpha with a tracked resource and a new way to default it")
+		Expect(config.UpdateResource(GVK{Group: "crew", Version: "v1", Kind: "FirstMate", Defaults: DefaultsWebhook})).
+			To(BeTrue())
+		Expect(config.Resources[0].Defaults).To(Equal(DefaultsWebhook))
+
+		By("Using config version 3-alpha with a tracked resource and a new webhook")
+		Expect(config.UpdateResource(GVK{Group: "crew", Version: "v1", Kind: "FirstMate", ConversionWebhook: true})).
+			To(BeTrue())
+		Expect(config.UpdateResource(GVK{Group: "crew", Version: "v1", Kind: "FirstMate", ValidatingWebhook: true})).
+			To(BeTrue())
+		Expect(config.Resources[0].ValidatingWebhook).To(BeTrue())
+		Expect(config.Resources[0].ConversionWebhook).To(BeTrue())
+
+		By("Using config version 3-alpha with a tracked resource and no new versions")
+		Expect(config.UpdateResource(GVK{Group: "crew", Version: "v1", Kind: "FirstMate"})).To(BeFalse())
+		Expect(config.Resources).To(HaveLen(1))
+		Expect(config.Resources[0].Plural).To(Equal("mates"))
+	})
+
+	It("should record the commands in the history of version 3-alpha projects only", func() {
+		cmd := RecordedCommand{Command: "create api", Flags: []string{"--group=crew", "--kind=Captain"}}
+
+		config := Config{Version: Version2}
+		Expect(config.RecordCommand(cmd)).To(BeFalse())
+		Expect(config.History).To(BeEmpty())
+
+		config = Config{Version: Version3Alpha}
+		Expect(config.RecordCommand(RecordedCommand{Command: "init"})).To(BeTrue())
+		Expect(config.RecordCommand(cmd)).To(BeTrue())
+		Expect(config.History).To(Equal([]RecordedCommand{{Command: "init"}, cmd}))
+	})
+
+	It("should remove tracked resources correctly", func() {
+		config := Config{Version: Version3Alpha, Resources: []GVK{
+			{Group: "crew", Version: "v1", Kind: "Captain", CRDVersion: "v1"},
+			{Group: "crew", Version: "v1", Kind: "FirstMate"},
+		}}
+
+		By("Removing an untracked resource")
+		Expect(config.RemoveResource(GVK{Group: "crew", Version: "v2", Kind: "Captain"})).To(BeFalse())
+		Expect(config.Resources).To(HaveLen(2))
+
+		By("Removing a tracked resource regardless of its manifest versions")
+		Expect(config.RemoveResource(GVK{Group: "crew", Version: "v1", Kind: "Captain"})).To(BeTrue())
+		Expect(config.Resources).To(Equal([]GVK{{Group: "crew", Version: "v1", Kind: "FirstMate"}}))
 	})
 })
@@ -16,6 +16,10 @@ limitations under the License.
 
 package file
 
+import (
+	"os"
+)
+
 // IfExistsAction determines what to do if the scaffold file already exists
 type IfExistsAction int
 
@@ -28,6 +32,10 @@ const (
 
 	// Overwrite truncates and overwrites the existing file
 	Overwrite
+
+	// MergeWithMarkers overwrites the existing file, keeping the code fragments that were inserted
+	// right before each of its markers, ex. by a file.Inserter, if the new contents have the same marker
+	MergeWithMarkers
 )
 
 // File describes a file that will be written
@@ -40,4 +48,7 @@ type File struct {
 
 	// IfExistsAction determines what to do if the file exists
 	IfExistsAction IfExistsAction `json:"ifExistsAction,omitempty"`
+
+	// Permissions are the permissions of the file, the file system default ones are used if unset
+	Permissions os.FileMode `json:"permissions,omitempty"`
 }
@@ -19,12 +19,37 @@ package file
 import (
 	"strings"
 	"text/template"
+
+	"sigs.k8s.io/yaml"
 )
 
 // DefaultFuncMap returns the default template.FuncMap for rendering the template.
 func DefaultFuncMap() template.FuncMap {
 	return template.FuncMap{
-		"title": strings.Title,
-		"lower": strings.ToLower,
+		"title":  strings.Title,
+		"lower":  strings.ToLower,
+		"indent": indent,
+		"toYaml": toYAML,
+	}
+}
+
+// indent prefixes every non-empty line of s with the given number of spaces, ex. to nest a YAML block.
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// toYAML returns the YAML representation of v, without a trailing newline so that it can be piped to indent.
+func toYAML(v interface{}) (string, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
 	}
+	return strings.TrimSuffix(string(b), "\n"), nil
 }
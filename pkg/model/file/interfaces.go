@@ -17,8 +17,10 @@ limitations under the License.
 package file
 
 import (
+	"os"
 	"text/template"
 
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/model/resource"
 )
 
@@ -46,6 +48,13 @@ type Template interface {
 	SetTemplateDefaults() error
 }
 
+// HasPermissions is a file builder that requires specific permissions, ex. 0755 for scripts
+type HasPermissions interface {
+	Builder
+	// GetPermissions returns the permissions of the file, the default ones are used if 0
+	GetPermissions() os.FileMode
+}
+
 // Inserter is a file builder that inserts code fragments in marked positions
 type Inserter interface {
 	Builder
@@ -73,6 +82,12 @@ type HasMultiGroup interface {
 	InjectMultiGroup(bool)
 }
 
+// HasDirectories allows the directories of the Go packages of the project to be used on a template
+type HasDirectories interface {
+	// InjectDirectories sets the template directories
+	InjectDirectories(config.Directories)
+}
+
 // HasBoilerplate allows a boilerplate to be used on a template
 type HasBoilerplate interface {
 	// InjectBoilerplate sets the template boilerplate
@@ -85,6 +100,12 @@ type HasResource interface {
 	InjectResource(*resource.Resource)
 }
 
+// HasValues allows the values set with --set to be used on a template
+type HasValues interface {
+	// InjectValues sets the template values
+	InjectValues(map[string]string)
+}
+
 // HasProjectName allows a project name to be used on a template.
 type HasProjectName interface {
 	// InjectProjectName sets the template project name.
@@ -96,3 +117,10 @@ type UseCustomFuncMap interface {
 	// GetFuncMap returns a custom FuncMap.
 	GetFuncMap() template.FuncMap
 }
+
+// UseCustomDelimiters allows a template to use custom action delimiters instead of "{{" and "}}", ex. to
+// scaffold files that use them themselves, like Helm charts.
+type UseCustomDelimiters interface {
+	// GetDelimiters returns the left and right action delimiters.
+	GetDelimiters() (left, right string)
+}
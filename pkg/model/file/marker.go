@@ -19,6 +19,7 @@ package file
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 )
 
 const prefix = "+kubebuilder:scaffold:"
@@ -28,9 +29,17 @@ var commentsByExt = map[string]string{
 	//  this is a backwards incompatible change, and thus should be done for next project version.
 	".go":   "// ",
 	".yaml": "# ",
+	".yml":  "# ",
+	".mk":   "# ",
 	// When adding additional file extensions, update also the NewMarkerFor documentation and error
 }
 
+// commentsByName are used for files without an extension.
+var commentsByName = map[string]string{
+	"Makefile":   "# ",
+	"Dockerfile": "# ",
+}
+
 // Marker represents a machine-readable comment that will be used for scaffolding purposes
 type Marker struct {
 	comment string
@@ -38,14 +47,24 @@ type Marker struct {
 }
 
 // NewMarkerFor creates a new marker customized for the specific file
-// Supported file extensions: .go, .ext
+// Supported file extensions: .go, .yaml, .yml, .mk
+// Supported file names: Makefile, Dockerfile
 func NewMarkerFor(path string, value string) Marker {
 	ext := filepath.Ext(path)
 	if comment, found := commentsByExt[ext]; found {
 		return Marker{comment, value}
 	}
+	if comment, found := commentsByName[filepath.Base(path)]; found {
+		return Marker{comment, value}
+	}
+
+	panic(fmt.Errorf("unknown file extension: '%s', expected '.go', '.yaml', '.yml' or '.mk', "+
+		"or a Makefile or Dockerfile", ext))
+}
 
-	panic(fmt.Errorf("unknown file extension: '%s', expected '.go' or '.yaml'", ext))
+// IsMarkerLine returns true if line contains a marker, whatever the file type
+func IsMarkerLine(line string) bool {
+	return strings.Contains(line, prefix)
 }
 
 // String implements Stringer
@@ -17,6 +17,9 @@ limitations under the License.
 package file
 
 import (
+	"os"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/model/resource"
 )
 
@@ -46,6 +49,7 @@ func (t *IfExistsActionMixin) GetIfExistsAction() IfExistsAction {
 type TemplateMixin struct {
 	PathMixin
 	IfExistsActionMixin
+	ValuesMixin
 
 	// TemplateBody is the template body to execute
 	TemplateBody string
@@ -56,6 +60,40 @@ func (t *TemplateMixin) GetBody() string {
 	return t.TemplateBody
 }
 
+// ValuesMixin provides templates with the values set with --set, e.g. {{ .Values.team }}
+type ValuesMixin struct {
+	Values map[string]string
+}
+
+// InjectValues implements HasValues
+func (m *ValuesMixin) InjectValues(values map[string]string) {
+	if m.Values == nil {
+		m.Values = values
+	}
+}
+
+// PermissionsMixin provides file builders with specific permissions
+type PermissionsMixin struct {
+	// Permissions are the permissions of the file, the default ones are used if 0
+	Permissions os.FileMode
+}
+
+// GetPermissions implements HasPermissions
+func (m *PermissionsMixin) GetPermissions() os.FileMode {
+	return m.Permissions
+}
+
+// DelimitersMixin provides templates with custom action delimiters
+type DelimitersMixin struct {
+	// LeftDelimiter and RightDelimiter replace "{{" and "}}", respectively, if set
+	LeftDelimiter, RightDelimiter string
+}
+
+// GetDelimiters implements UseCustomDelimiters
+func (m *DelimitersMixin) GetDelimiters() (string, string) {
+	return m.LeftDelimiter, m.RightDelimiter
+}
+
 // InserterMixin is the mixin that should be embedded in Inserter builders
 type InserterMixin struct {
 	PathMixin
@@ -104,6 +142,17 @@ func (m *MultiGroupMixin) InjectMultiGroup(flag bool) {
 	m.MultiGroup = flag
 }
 
+// DirectoriesMixin provides templates with injectable directories of the Go packages of the project
+type DirectoriesMixin struct {
+	// Directories are the directories of the APIs and controllers, laid out by the profile of the project
+	Directories config.Directories
+}
+
+// InjectDirectories implements HasDirectories
+func (m *DirectoriesMixin) InjectDirectories(dirs config.Directories) {
+	m.Directories = dirs
+}
+
 // BoilerplateMixin provides templates with a injectable boilerplate field
 type BoilerplateMixin struct {
 	// Boilerplate is the contents of a Boilerplate go header file
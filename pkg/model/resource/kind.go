@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+var (
+	// builtinKinds are the Kinds served by Kubernetes, by lowercase Kind. Resources named after them are
+	// ambiguous for users and tools that only look at the Kind or at the resource name, e.g. kubectl get.
+	builtinKinds = map[string]string{}
+
+	// reservedKindSuffixes are the suffixes of the types scaffolded along with the one of a Kind.
+	reservedKindSuffixes = []string{"List", "Spec", "Status"}
+)
+
+func init() {
+	for group, kinds := range map[string][]string{
+		"core": {"Binding", "ComponentStatus", "ConfigMap", "Endpoints", "Event", "LimitRange", "Namespace", "Node",
+			"PersistentVolume", "PersistentVolumeClaim", "Pod", "PodTemplate", "ReplicationController",
+			"ResourceQuota", "Secret", "Service", "ServiceAccount"},
+		"admissionregistration.k8s.io": {"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"},
+		"apiextensions.k8s.io":         {"CustomResourceDefinition"},
+		"apiregistration.k8s.io":       {"APIService"},
+		"apps":                         {"ControllerRevision", "DaemonSet", "Deployment", "ReplicaSet", "StatefulSet"},
+		"authentication.k8s.io":        {"TokenReview"},
+		"authorization.k8s.io": {"LocalSubjectAccessReview", "SelfSubjectAccessReview", "SelfSubjectRulesReview",
+			"SubjectAccessReview"},
+		"autoscaling":               {"HorizontalPodAutoscaler"},
+		"batch":                     {"CronJob", "Job"},
+		"certificates.k8s.io":       {"CertificateSigningRequest"},
+		"coordination.k8s.io":       {"Lease"},
+		"discovery.k8s.io":          {"EndpointSlice"},
+		"networking.k8s.io":         {"Ingress", "IngressClass", "NetworkPolicy"},
+		"node.k8s.io":               {"RuntimeClass"},
+		"policy":                    {"PodDisruptionBudget", "PodSecurityPolicy"},
+		"rbac.authorization.k8s.io": {"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"},
+		"scheduling.k8s.io":         {"PriorityClass"},
+		"storage.k8s.io":            {"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"},
+	} {
+		for _, kind := range kinds {
+			builtinKinds[strings.ToLower(kind)] = fmt.Sprintf("%s (%s)", kind, group)
+		}
+	}
+}
+
+// ValidateForProject verifies that a resource with these options can be added to the project of c: the name of
+// its CRD must be valid, and its Kind must not be the one of a built-in resource or only differ in case from the
+// Kind of another resource of its group, ignoring the resource with the same group, version and Kind itself.
+func (opts *Options) ValidateForProject(c *config.Config) error {
+	res := opts.NewResource(c, true)
+	if err := validation.IsDNS1123Subdomain(res.Plural + "." + res.Domain); err != nil {
+		return fmt.Errorf("the CRD name %s.%s is invalid: (%v)", res.Plural, res.Domain, err)
+	}
+
+	if builtin, found := builtinKinds[strings.ToLower(opts.Kind)]; found {
+		return fmt.Errorf("Kind %s collides with the built-in Kind %s", opts.Kind, builtin)
+	}
+
+	var collisions []string
+	for _, gvk := range c.Resources {
+		if gvk.Group == opts.Group && gvk.Kind != opts.Kind && strings.EqualFold(gvk.Kind, opts.Kind) {
+			collisions = append(collisions, fmt.Sprintf("%s/%s, Kind=%s", gvk.Group, gvk.Version, gvk.Kind))
+		}
+	}
+	if len(collisions) != 0 {
+		sort.Strings(collisions)
+		return fmt.Errorf("Kind %s only differs in case from the tracked resources %s, its files and CRD "+
+			"would collide with theirs", opts.Kind, strings.Join(collisions, "; "))
+	}
+
+	return nil
+}
+
+// KindWarnings returns the reasons why the Kind of the options may be confusing, even if it is valid.
+func (opts *Options) KindWarnings() []string {
+	var warnings []string
+	for _, suffix := range reservedKindSuffixes {
+		if opts.Kind != suffix && strings.HasSuffix(opts.Kind, suffix) {
+			warnings = append(warnings, fmt.Sprintf("Kind %s ends with %q, so its type is named like the %s type "+
+				"of a Kind %s", opts.Kind, suffix, strings.ToLower(suffix), strings.TrimSuffix(opts.Kind, suffix)))
+		}
+	}
+	return warnings
+}
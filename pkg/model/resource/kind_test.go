@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	. "sigs.k8s.io/kubebuilder/pkg/model/resource"
+)
+
+var _ = Describe("Resource Kind", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			Version:   config.Version3Alpha,
+			Domain:    "testproject.org",
+			Resources: []config.GVK{{Group: "crew", Version: "v1", Kind: "FirstMate"}},
+		}
+	})
+
+	It("should accept new and already tracked Kinds", func() {
+		Expect((&Options{Group: "crew", Version: "v1", Kind: "Captain"}).ValidateForProject(cfg)).To(Succeed())
+		Expect((&Options{Group: "crew", Version: "v2", Kind: "FirstMate"}).ValidateForProject(cfg)).To(Succeed())
+		Expect((&Options{Group: "ship", Version: "v1", Kind: "Firstmate"}).ValidateForProject(cfg)).To(Succeed())
+	})
+
+	It("should reject built-in Kinds regardless of their case", func() {
+		options := &Options{Group: "crew", Version: "v1", Kind: "ConfigMap"}
+		Expect(options.ValidateForProject(cfg)).To(MatchError(
+			"Kind ConfigMap collides with the built-in Kind ConfigMap (core)"))
+
+		options.Kind = "Configmap"
+		Expect(options.ValidateForProject(cfg)).To(MatchError(ContainSubstring("built-in Kind ConfigMap")))
+	})
+
+	It("should reject Kinds only differing in case from a tracked one of the group", func() {
+		options := &Options{Group: "crew", Version: "v2", Kind: "Firstmate"}
+		Expect(options.ValidateForProject(cfg)).To(MatchError(ContainSubstring(
+			"Kind Firstmate only differs in case from the tracked resources crew/v1, Kind=FirstMate")))
+	})
+
+	It("should reject resources with an invalid CRD name", func() {
+		options := &Options{Group: "crew", Version: "v1", Kind: "Captain", Plural: strings.Repeat("a", 63)}
+		cfg.Domain = strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." + strings.Repeat("d", 63)
+		Expect(options.ValidateForProject(cfg)).To(MatchError(ContainSubstring("the CRD name")))
+	})
+
+	It("should warn about Kinds ending with the suffix of a scaffolded type", func() {
+		Expect((&Options{Kind: "Captain"}).KindWarnings()).To(BeEmpty())
+		Expect((&Options{Kind: "Status"}).KindWarnings()).To(BeEmpty())
+		Expect((&Options{Kind: "CaptainList"}).KindWarnings()).To(Equal([]string{
+			`Kind CaptainList ends with "List", so its type is named like the list type of a Kind Captain`,
+		}))
+	})
+})
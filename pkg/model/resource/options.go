@@ -34,11 +34,19 @@ const (
 	groupRequired   = "group cannot be empty"
 	versionRequired = "version cannot be empty"
 	kindRequired    = "kind cannot be empty"
+
+	// DefaultCRDVersion is the API version of the CRD manifests if none is provided
+	DefaultCRDVersion = "v1beta1"
+	// DefaultWebhookVersion is the API version of the webhook configuration manifests if none is provided
+	DefaultWebhookVersion = "v1beta1"
 )
 
 var (
 	versionRegex = regexp.MustCompile(versionPattern)
 
+	// supportedManifestVersions are the API versions of the CRD and webhook configuration manifests that can be scaffolded
+	supportedManifestVersions = []string{"v1beta1", "v1"}
+
 	coreGroups = map[string]string{
 		"admission":             "k8s.io",
 		"admissionregistration": "k8s.io",
@@ -83,6 +91,26 @@ type Options struct {
 
 	// Namespaced is true if the resource is namespaced.
 	Namespaced bool
+
+	// ExternalAPIPath is the go package of an API that is not defined in the project.
+	// Optional
+	ExternalAPIPath string
+
+	// ExternalAPIDomain is the domain of the external API group.
+	// Optional
+	ExternalAPIDomain string
+
+	// CRDVersion is the API version of the CRD manifest.
+	// Optional
+	CRDVersion string
+
+	// WebhookVersion is the API version of the webhook configuration manifests.
+	// Optional
+	WebhookVersion string
+
+	// Defaults is how the fields of the resource are defaulted, either by markers or by a defaulting webhook.
+	// Optional
+	Defaults string
 }
 
 // Validate verifies that all the fields have valid values
@@ -134,7 +162,37 @@ func (opts *Options) Validate() error {
 		return fmt.Errorf("invalid Kind: %#v", validationErrors)
 	}
 
-	// TODO: validate plural strings if provided
+	// Check that the external API domain is only provided along with an external API path
+	if opts.ExternalAPIDomain != "" {
+		if opts.ExternalAPIPath == "" {
+			return fmt.Errorf("external API domain requires an external API path")
+		}
+		if err := validation.IsDNS1123Subdomain(opts.ExternalAPIDomain); err != nil {
+			return fmt.Errorf("external API domain is invalid: (%v)", err)
+		}
+	}
+
+	// Check that the manifest API versions are supported
+	if opts.CRDVersion != "" && !isSupportedManifestVersion(opts.CRDVersion) {
+		return fmt.Errorf("CRD version %q is not supported, must be one of %v", opts.CRDVersion, supportedManifestVersions)
+	}
+	if opts.WebhookVersion != "" && !isSupportedManifestVersion(opts.WebhookVersion) {
+		return fmt.Errorf("webhook version %q is not supported, must be one of %v",
+			opts.WebhookVersion, supportedManifestVersions)
+	}
+
+	// Check that the fields are defaulted in a known way
+	if opts.Defaults != "" && opts.Defaults != config.DefaultsMarkers && opts.Defaults != config.DefaultsWebhook {
+		return fmt.Errorf("defaults %q is not supported, must be one of %v", opts.Defaults,
+			[]string{config.DefaultsMarkers, config.DefaultsWebhook})
+	}
+
+	// Check that the plural is a valid resource name, as it is used in the CRD manifest and the RBAC rules
+	if opts.Plural != "" {
+		if errs := validation.IsDNS1035Label(opts.Plural); len(errs) != 0 {
+			return fmt.Errorf("invalid plural: %#v", errs)
+		}
+	}
 
 	return nil
 }
@@ -145,7 +203,35 @@ func (opts *Options) GVK() config.GVK {
 		Group:   opts.Group,
 		Version: opts.Version,
 		Kind:    opts.Kind,
+		Plural:  customPlural(opts.Kind, opts.Plural),
+
+		CRDVersion:     opts.CRDVersion,
+		WebhookVersion: opts.WebhookVersion,
+		Defaults:       opts.Defaults,
+	}
+}
+
+// DefaultPlural returns the plural computed for kind if none is provided
+func DefaultPlural(kind string) string {
+	return flect.Pluralize(strings.ToLower(kind))
+}
+
+// customPlural returns plural if it is not the default one of kind, or an empty string
+func customPlural(kind, plural string) string {
+	if plural == DefaultPlural(kind) {
+		return ""
 	}
+	return plural
+}
+
+// isSupportedManifestVersion returns true if version is a supported API version for the CRD and webhook manifests
+func isSupportedManifestVersion(version string) bool {
+	for _, supported := range supportedManifestVersions {
+		if version == supported {
+			return true
+		}
+	}
+	return false
 }
 
 // safeImport returns a cleaned version of the provided string that can be used for imports
@@ -163,21 +249,31 @@ func (opts *Options) safeImport(unsafe string) string {
 func (opts *Options) NewResource(c *config.Config, doResource bool) *Resource {
 	res := opts.newResource()
 
+	// The versions of a Kind share its CRD, so the plural recorded for any of them is used unless one is provided
+	if opts.Plural == "" {
+		for _, gvk := range c.Resources {
+			if gvk.Group == opts.Group && gvk.Kind == opts.Kind && gvk.Plural != "" {
+				res.Plural = gvk.Plural
+				break
+			}
+		}
+	}
+
 	replacer := res.Replacer()
 
-	pkg := replacer.Replace(path.Join(c.Repo, "api", "%[version]"))
-	if c.MultiGroup {
-		pkg = replacer.Replace(path.Join(c.Repo, "apis", "%[group]", "%[version]"))
-	}
+	pkg := replacer.Replace(path.Join(c.Repo, c.GetDirectories().Types))
 	domain := c.Domain
 
-	// pkg and domain may need to be changed in case we are referring to a builtin core resource:
+	// pkg and domain may need to be changed in case we are referring to an external or builtin core resource:
+	//  - Check if an external API path was provided             => external resource
 	//  - Check if we are scaffolding the resource now           => project resource
 	//  - Check if we already scaffolded the resource            => project resource
 	//  - Check if the resource group is a well-known core group => builtin core resource
 	//  - In any other case, default to                          => project resource
-	// TODO: need to support '--resource-pkg-path' flag for specifying resourcePath
-	if !doResource {
+	if opts.ExternalAPIPath != "" {
+		pkg = opts.ExternalAPIPath
+		domain = opts.ExternalAPIDomain
+	} else if !doResource {
 		if !c.HasResource(opts.GVK()) {
 			if coreDomain, found := coreGroups[opts.Group]; found {
 				pkg = replacer.Replace(path.Join("k8s.io", "api", "%[group]", "%[version]"))
@@ -199,7 +295,7 @@ func (opts *Options) newResource() *Resource {
 	// If not provided, compute a plural for for Kind
 	plural := opts.Plural
 	if plural == "" {
-		plural = flect.Pluralize(strings.ToLower(opts.Kind))
+		plural = DefaultPlural(opts.Kind)
 	}
 
 	return &Resource{
@@ -210,5 +306,8 @@ func (opts *Options) newResource() *Resource {
 		Kind:             opts.Kind,
 		Plural:           plural,
 		ImportAlias:      opts.safeImport(opts.Group + opts.Version),
+		CRDVersion:       opts.CRDVersion,
+		WebhookVersion:   opts.WebhookVersion,
+		Defaults:         opts.Defaults,
 	}
 }
@@ -104,6 +104,48 @@ var _ = Describe("Resource Options", func() {
 			Entry("should fail validation if Kind starts with number", "0ValidityKind"),
 		)
 
+		It("should fail if an external API domain is provided without an external API path", func() {
+			options := &Options{Group: "crew", Version: "v1", Kind: "FirstMate", ExternalAPIDomain: "example.com"}
+			Expect(options.Validate()).To(MatchError("external API domain requires an external API path"))
+		})
+
+		It("should fail if the external API domain is invalid", func() {
+			options := &Options{Group: "crew", Version: "v1", Kind: "FirstMate",
+				ExternalAPIPath: "example.com/api/v1", ExternalAPIDomain: "Example.com"}
+			Expect(options.Validate()).To(MatchError(ContainSubstring("external API domain is invalid")))
+		})
+
+		It("should succeed if the manifest versions are supported", func() {
+			options := &Options{Group: "crew", Version: "v1", Kind: "FirstMate", CRDVersion: "v1", WebhookVersion: "v1beta1"}
+			Expect(options.Validate()).To(Succeed())
+		})
+
+		It("should fail if the CRD version is not supported", func() {
+			options := &Options{Group: "crew", Version: "v1", Kind: "FirstMate", CRDVersion: "v2"}
+			Expect(options.Validate()).To(MatchError(ContainSubstring(`CRD version "v2" is not supported`)))
+		})
+
+		It("should fail if the webhook version is not supported", func() {
+			options := &Options{Group: "crew", Version: "v1", Kind: "FirstMate", WebhookVersion: "v1alpha1"}
+			Expect(options.Validate()).To(MatchError(ContainSubstring(`webhook version "v1alpha1" is not supported`)))
+		})
+
+		It("should fail if the way to default the fields is not supported", func() {
+			options := &Options{Group: "crew", Version: "v1", Kind: "FirstMate", Defaults: "openapi"}
+			Expect(options.Validate()).To(MatchError(ContainSubstring(`defaults "openapi" is not supported`)))
+
+			options.Defaults = "markers"
+			Expect(options.Validate()).To(Succeed())
+		})
+
+		It("should fail if the Plural is not a valid resource name", func() {
+			options := &Options{Group: "crew", Version: "v1", Kind: "Proxy", Plural: "Proxies"}
+			Expect(options.Validate()).To(MatchError(ContainSubstring("invalid plural")))
+
+			options.Plural = "proxy-entries"
+			Expect(options.Validate()).To(Succeed())
+		})
+
 		It("should fail if Kind starts with a lowercase character", func() {
 			options := &Options{Group: "crew", Kind: "lOWERCASESTART", Version: "v1"}
 			err := options.Validate()
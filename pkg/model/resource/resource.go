@@ -51,6 +51,15 @@ type Resource struct {
 
 	// Namespaced is true if the resource is namespaced.
 	Namespaced bool `json:"namespaced,omitempty"`
+
+	// CRDVersion is the API version of the CRD manifest.
+	CRDVersion string `json:"crdVersion,omitempty"`
+
+	// WebhookVersion is the API version of the webhook configuration manifests.
+	WebhookVersion string `json:"webhookVersion,omitempty"`
+
+	// Defaults is how the fields of the resource are defaulted, either by markers or by a defaulting webhook.
+	Defaults string `json:"defaults,omitempty"`
 }
 
 // GVK returns the group-version-kind information to check against tracked resources in the configuration file
@@ -59,9 +68,19 @@ func (r *Resource) GVK() config.GVK {
 		Group:   r.Group,
 		Version: r.Version,
 		Kind:    r.Kind,
+		Plural:  customPlural(r.Kind, r.Plural),
+
+		CRDVersion:     r.CRDVersion,
+		WebhookVersion: r.WebhookVersion,
+		Defaults:       r.Defaults,
 	}
 }
 
+// HasCustomPlural returns true if the plural of the resource is not the one computed from its Kind.
+func (r *Resource) HasCustomPlural() bool {
+	return customPlural(r.Kind, r.Plural) != ""
+}
+
 func wrapKey(key string) string {
 	return fmt.Sprintf("%%[%s]", key)
 }
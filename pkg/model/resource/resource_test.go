@@ -129,6 +129,32 @@ var _ = Describe("Resource", func() {
 			Expect(resource.Plural).To(Equal("mates"))
 		})
 
+		It("should only record the Plural in the config if it is not the computed one", func() {
+			options := &Options{Group: "net", Version: "v1", Kind: "Proxy"}
+			resource := options.NewResource(&config.Config{Version: config.Version3Alpha}, true)
+			Expect(resource.Plural).To(Equal("proxies"))
+			Expect(resource.HasCustomPlural()).To(BeFalse())
+			Expect(resource.GVK().Plural).To(BeEmpty())
+
+			options.Plural = "proxyentries"
+			resource = options.NewResource(&config.Config{Version: config.Version3Alpha}, true)
+			Expect(resource.HasCustomPlural()).To(BeTrue())
+			Expect(resource.GVK().Plural).To(Equal("proxyentries"))
+		})
+
+		It("should use the Plural recorded for another version of the Kind", func() {
+			cfg := &config.Config{
+				Version:   config.Version3Alpha,
+				Resources: []config.GVK{{Group: "net", Version: "v1", Kind: "Proxy", Plural: "proxyentries"}},
+			}
+
+			options := &Options{Group: "net", Version: "v2", Kind: "Proxy"}
+			Expect(options.NewResource(cfg, true).Plural).To(Equal("proxyentries"))
+
+			options = &Options{Group: "other", Version: "v2", Kind: "Proxy"}
+			Expect(options.NewResource(cfg, true).Plural).To(Equal("proxies"))
+		})
+
 		It("should allow hyphens and dots in group names", func() {
 			singleGroupConfig := &config.Config{
 				Version: config.Version2,
@@ -234,5 +260,31 @@ var _ = Describe("Resource", func() {
 			Expect(resource.Package).To(Equal(path.Join("k8s.io", "api", options.Group, options.Version)))
 			Expect(resource.Domain).To(Equal("authentication.k8s.io"))
 		})
+
+		It("should use external apis", func() {
+			projectConfig := &config.Config{
+				Version: config.Version2,
+				Domain:  "test.io",
+				Repo:    "test",
+			}
+
+			options := &Options{
+				Group:             "cert-manager",
+				Version:           "v1",
+				Kind:              "Certificate",
+				ExternalAPIPath:   "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1",
+				ExternalAPIDomain: "io",
+			}
+			Expect(options.Validate()).To(Succeed())
+
+			resource := options.NewResource(projectConfig, false)
+			Expect(resource.Package).To(Equal(options.ExternalAPIPath))
+			Expect(resource.Domain).To(Equal("cert-manager.io"))
+
+			options.ExternalAPIDomain = ""
+			resource = options.NewResource(projectConfig, false)
+			Expect(resource.Package).To(Equal(options.ExternalAPIPath))
+			Expect(resource.Domain).To(Equal("cert-manager"))
+		})
 	})
 })
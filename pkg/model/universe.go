@@ -33,6 +33,9 @@ type Universe struct {
 	// Resource contains the information of the API that is being scaffolded
 	Resource *resource.Resource `json:"resource,omitempty"`
 
+	// Values are the values set with --set for the command, overriding the ones of the project configuration
+	Values map[string]string `json:"values,omitempty"`
+
 	// Files contains the model of the files that are being scaffolded
 	Files map[string]*file.File `json:"files,omitempty"`
 }
@@ -78,6 +81,27 @@ func WithResource(resource *resource.Resource) UniverseOption {
 	}
 }
 
+// WithValues stores the values set for the command
+func WithValues(values map[string]string) UniverseOption {
+	return func(universe *Universe) {
+		universe.Values = values
+	}
+}
+
+// values returns the values of the project configuration overridden by the ones of the universe
+func (u Universe) values() map[string]string {
+	values := make(map[string]string)
+	if u.Config != nil {
+		for key, value := range u.Config.Values {
+			values[key] = value
+		}
+	}
+	for key, value := range u.Values {
+		values[key] = value
+	}
+	return values
+}
+
 // InjectInto injects fields from the universe into the builder
 func (u Universe) InjectInto(builder file.Builder) {
 	// Inject project configuration
@@ -91,10 +115,17 @@ func (u Universe) InjectInto(builder file.Builder) {
 		if builderWithMultiGroup, hasMultiGroup := builder.(file.HasMultiGroup); hasMultiGroup {
 			builderWithMultiGroup.InjectMultiGroup(u.Config.MultiGroup)
 		}
+		if builderWithDirectories, hasDirectories := builder.(file.HasDirectories); hasDirectories {
+			builderWithDirectories.InjectDirectories(u.Config.GetDirectories())
+		}
 		if builderWithProjectName, hasProjectName := builder.(file.HasProjectName); hasProjectName {
 			builderWithProjectName.InjectProjectName(u.Config.ProjectName)
 		}
 	}
+	// Inject values
+	if builderWithValues, hasValues := builder.(file.HasValues); hasValues {
+		builderWithValues.InjectValues(u.values())
+	}
 	// Inject boilerplate
 	if builderWithBoilerplate, hasBoilerplate := builder.(file.HasBoilerplate); hasBoilerplate {
 		builderWithBoilerplate.InjectBoilerplate(u.Boilerplate)
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/apiserver/v1/scaffolds"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+type createAPISubcommand struct {
+	config *config.Config
+
+	resource *resource.Options
+
+	// runMake indicates whether to run make generate after scaffolding the resource
+	runMake bool
+}
+
+var (
+	_ plugin.CreateAPI   = &createAPISubcommand{}
+	_ cmdutil.RunOptions = &createAPISubcommand{}
+)
+
+func (p *createAPISubcommand) UpdateContext(ctx *plugin.Context) {
+	ctx.Description = `Scaffold a resource served by the aggregated API server.
+
+Writes the types of the resource, implementing the resource.Object interface of apiserver-runtime, adds them to
+the API server in main.go and writes the APIService of its group-version, through which the Kubernetes API server
+proxies its requests to the API server.
+
+After the scaffold is written, make generate is run to generate the deepcopy code and the OpenAPI definitions.
+`
+	ctx.Examples = fmt.Sprintf(`  # Create a flunders API with Group: wardle, Version: v1alpha1 and Kind: Flunder
+  %[1]s create api --group wardle --version v1alpha1 --kind Flunder
+
+  # Edit the types of the resource
+  nano apis/wardle/v1alpha1/flunder_types.go
+
+  # Regenerate the code and run the API server locally
+  make run
+`, ctx.CommandName)
+}
+
+func (p *createAPISubcommand) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&p.runMake, "make", true, "if true, run make generate after generating files")
+
+	p.resource = &resource.Options{}
+	fs.StringVar(&p.resource.Kind, "kind", "", "resource Kind")
+	fs.StringVar(&p.resource.Group, "group", "", "resource Group")
+	fs.StringVar(&p.resource.Version, "version", "", "resource Version")
+	fs.StringVar(&p.resource.Plural, "plural", "",
+		"resource plural, the resource name served by the API server, "+
+			"only needed if it is not the one computed from the Kind")
+	fs.BoolVar(&p.resource.Namespaced, "namespaced", true, "resource is namespaced")
+}
+
+func (p *createAPISubcommand) InjectConfig(c *config.Config) {
+	p.config = c
+}
+
+func (p *createAPISubcommand) Scaffold() error {
+	return cmdutil.Scaffold(p)
+}
+
+func (p *createAPISubcommand) Validate() error {
+	if err := p.resource.Validate(); err != nil {
+		return err
+	}
+
+	// The resources are laid out by group and served under the group of their APIService
+	if p.resource.Group == "" {
+		return errors.New("group cannot be empty, the resources of an API server are served in an API group")
+	}
+
+	if p.config.HasResource(p.resource.GVK()) {
+		return errors.New("API resource already exists")
+	}
+
+	// Every version of a Kind would be the storage version, the others need conversions to it
+	for _, r := range p.config.Resources {
+		if r.Group == p.resource.Group && r.Kind == p.resource.Kind {
+			return fmt.Errorf("%s/%s, Kind=%s already exists, serving several versions of a Kind is not "+
+				"supported yet", r.Group, r.Version, r.Kind)
+		}
+	}
+
+	return nil
+}
+
+func (p *createAPISubcommand) GetScaffolder() (scaffold.Scaffolder, error) {
+	// Load the boilerplate
+	bp, err := ioutil.ReadFile(filepath.Join("hack", "boilerplate.go.txt")) // nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("unable to load boilerplate: %v", err)
+	}
+
+	cfg, err := loadPluginConfig(p.config)
+	if err != nil {
+		return nil, err
+	}
+
+	res := p.resource.NewResource(p.config, true)
+	return scaffolds.NewAPIScaffolder(p.config, string(bp), res, cfg.Storage), nil
+}
+
+func (p *createAPISubcommand) PostScaffold() error {
+	if p.runMake {
+		return util.RunCmd("Running make generate", "make", "generate")
+	}
+	return nil
+}
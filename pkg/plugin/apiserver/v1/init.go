@@ -0,0 +1,214 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/apiserver/v1/scaffolds"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/license"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+type initSubcommand struct {
+	config *config.Config
+	// For help text.
+	commandName string
+
+	// boilerplate options
+	license   string
+	owner     string
+	ownerFlag *pflag.Flag
+	// ownerFromGit is true if the owner defaults to the author of the git config
+	ownerFromGit bool
+	// copyrightYear is the year of the copyright, the current one if empty
+	copyrightYear string
+
+	// storage is where the API server stores the resources
+	storage string
+
+	fetchDeps bool
+}
+
+var (
+	_ plugin.Init        = &initSubcommand{}
+	_ cmdutil.RunOptions = &initSubcommand{}
+)
+
+func (p *initSubcommand) UpdateContext(ctx *plugin.Context) {
+	ctx.Description = `Initialize a new aggregated API server project built with sigs.k8s.io/apiserver-runtime.
+
+An aggregated API server serves its APIs behind the Kubernetes API server, which proxies their requests to it
+according to APIService objects. Unlike CRDs, its APIs can be stored elsewhere than in the etcd of the cluster
+and served by custom code.
+
+Writes the following files:
+- a boilerplate license file, with the header of the --license, and its LICENSE file unless it is none
+- a PROJECT file with the domain and repo
+- a Makefile to build the API server and generate its deepcopy and OpenAPI code
+- a Dockerfile to build the API server image
+- a go.mod with project dependencies, unless one already exists
+- a main.go running the API server, storing the resources in etcd or, with --storage=filepath, in JSON files
+- the manifests in config/ deploying the API server with its RBAC, its serving certificate issued by
+  cert-manager and its storage, either an etcd StatefulSet or a PersistentVolumeClaim
+`
+	ctx.Examples = fmt.Sprintf(`  # Scaffold an API server storing its resources in etcd
+  %[1]s init --plugins %[2]s --domain example.org --repo example.org/wardle
+
+  # Scaffold an API server storing its resources in JSON files, without etcd
+  %[1]s init --plugins %[2]s --domain example.org --repo example.org/wardle --storage filepath
+`, ctx.CommandName, plugin.KeyFor(Plugin{}))
+
+	p.commandName = ctx.CommandName
+}
+
+func (p *initSubcommand) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&p.fetchDeps, "fetch-deps", true, "ensure dependencies are downloaded")
+
+	// boilerplate args
+	fs.StringVar(&p.license, "license", license.Apache2, license.Description())
+	fs.StringVar(&p.owner, "owner", "", "owner to add to the copyright")
+	p.ownerFlag = fs.Lookup("owner")
+	fs.BoolVar(&p.ownerFromGit, "owner-from-git", true, "if --owner is not set, use the user.name and "+
+		"user.email of the git config as owner")
+	fs.StringVar(&p.copyrightYear, "copyright-year", "", "year of the copyright of the boilerplate header and "+
+		"the LICENSE file, or range of years, e.g. 2018-2020, defaults to the current year")
+
+	// project args
+	fs.StringVar(&p.config.Repo, "repo", "", "name to use for go module (e.g., github.com/user/repo), "+
+		"defaults to the go package of the current working directory.")
+	fs.StringVar(&p.config.Domain, "domain", "my.domain", "domain for groups")
+	fs.StringVar(&p.config.ProjectName, "project-name", "",
+		"name of this project, the prefix of the names of its manifests, defaults to the current directory name")
+
+	fs.StringVar(&p.storage, "storage", scaffolds.StorageEtcd,
+		fmt.Sprintf("where the API server stores the resources, either %q in an etcd deployed with it or %q "+
+			"in JSON files, a PersistentVolumeClaim being mounted for them", scaffolds.StorageEtcd,
+			scaffolds.StorageFilepath))
+}
+
+func (p *initSubcommand) InjectConfig(c *config.Config) {
+	// v3 project configs get a 'layout' value.
+	c.Layout = plugin.KeyFor(Plugin{})
+	p.config = c
+}
+
+func (p *initSubcommand) Scaffold() error {
+	return cmdutil.Scaffold(p)
+}
+
+func (p *initSubcommand) Validate() error {
+	if _, err := license.Lookup(p.license); err != nil {
+		return err
+	}
+	if p.copyrightYear != "" {
+		if err := license.ValidateYear(p.copyrightYear); err != nil {
+			return err
+		}
+	}
+	if !p.ownerFlag.Changed && p.ownerFromGit {
+		if p.owner = util.GitAuthor(); p.owner != "" {
+			logger.Default().Info(fmt.Sprintf("Using %q of the git config as owner", p.owner))
+		}
+	}
+
+	switch p.storage {
+	case scaffolds.StorageEtcd, scaffolds.StorageFilepath:
+	default:
+		return fmt.Errorf("storage %q is not supported, possible values: (%s, %s)",
+			p.storage, scaffolds.StorageEtcd, scaffolds.StorageFilepath)
+	}
+	if err := p.config.EncodePluginConfig(plugin.KeyFor(Plugin{}), pluginConfig{Storage: p.storage}); err != nil {
+		return err
+	}
+
+	if p.config.ProjectName == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("error getting current directory: %v", err)
+		}
+		p.config.ProjectName = strings.ToLower(filepath.Base(dir))
+	}
+	if err := validation.IsDNS1123Label(p.config.ProjectName); err != nil {
+		return fmt.Errorf("project name (%s) is invalid: %v", p.config.ProjectName, err)
+	}
+	p.config.Namespace = p.config.ProjectName + "-system"
+	p.config.NamePrefix = p.config.ProjectName + "-"
+	// The APIs are laid out by group, as an API server commonly serves several of them
+	p.config.MultiGroup = true
+
+	// If a go.mod already exists, its module path is used as the repository, which must then match the flag.
+	if _, err := os.Stat("go.mod"); err == nil {
+		modulePath, err := util.FindModulePath("go.mod")
+		if err != nil {
+			return fmt.Errorf("error reading module path from existing go.mod: %v", err)
+		}
+		switch p.config.Repo {
+		case "":
+			p.config.Repo = modulePath
+		case modulePath:
+		default:
+			return fmt.Errorf("repository %q does not match module path %q declared in the existing go.mod",
+				p.config.Repo, modulePath)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("error checking for an existing go.mod: %v", err)
+	}
+	if p.config.Repo == "" {
+		repoPath, err := util.FindCurrentRepo()
+		if err != nil {
+			return fmt.Errorf("error finding current repository: %v", err)
+		}
+		p.config.Repo = repoPath
+	}
+
+	return nil
+}
+
+func (p *initSubcommand) GetScaffolder() (scaffold.Scaffolder, error) {
+	return scaffolds.NewInitScaffolder(p.config, p.license, p.owner, p.copyrightYear, p.storage), nil
+}
+
+func (p *initSubcommand) PostScaffold() error {
+	if !p.fetchDeps {
+		logger.Default().Info("Skipping fetching dependencies.")
+		return nil
+	}
+
+	err := util.RunCmd("Get apiserver-runtime", "go", "get",
+		"sigs.k8s.io/apiserver-runtime@"+scaffolds.APIServerRuntimeVersion)
+	if err != nil {
+		return err
+	}
+	if err := util.RunCmd("Update go.mod", "go", "mod", "tidy"); err != nil {
+		return err
+	}
+
+	logger.Default().Info(fmt.Sprintf("Next: define a resource with:\n$ %s create api", p.commandName))
+	return nil
+}
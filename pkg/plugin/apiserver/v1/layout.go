@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+var _ plugin.LayoutDescriber = Plugin{}
+
+// DescribeLayout implements plugin.LayoutDescriber
+func (Plugin) DescribeLayout(*config.Config) map[string]string {
+	return map[string]string{
+		"Dockerfile":              "multi-stage build of the API server image",
+		"Makefile":                "targets to generate code, build, run and deploy the API server",
+		"go.mod":                  "Go module of the project, pinning apiserver-runtime",
+		"main.go":                 "entrypoint of the API server, which registers the resources and their storage",
+		"hack":                    "helper files for development",
+		"hack/boilerplate.go.txt": "license header prepended to generated and scaffolded Go files",
+		"apis":                    "Go types of the project resources, one package per group and version",
+		"pkg/generated/openapi":   "OpenAPI definitions of the project resources, generated by 'make generate'",
+		"config/apiserver":        "manifests deploying the API server, its storage and the APIServices registering it",
+		"config/samples":          "sample objects of the project resources",
+	}
+}
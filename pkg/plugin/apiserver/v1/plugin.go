@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+const pluginName = "apiserver" + plugin.DefaultNameQualifier
+
+var (
+	supportedProjectVersions = []string{config.Version3Alpha}
+	pluginVersion            = plugin.Version{Number: 1, Stage: plugin.AlphaStage}
+)
+
+var (
+	_ plugin.Base                  = Plugin{}
+	_ plugin.InitPluginGetter      = Plugin{}
+	_ plugin.CreateAPIPluginGetter = Plugin{}
+)
+
+// Plugin scaffolds an aggregated API server built with sigs.k8s.io/apiserver-runtime, for APIs that can not be
+// served as CustomResourceDefinitions, e.g. because they are not stored in etcd or need custom REST handlers.
+// It is a base plugin scaffolding both the Go code and the manifests, so it is not chained with the kustomize
+// plugins.
+type Plugin struct {
+	initSubcommand
+	createAPISubcommand
+}
+
+func (Plugin) Name() string                           { return pluginName }
+func (Plugin) Version() plugin.Version                { return pluginVersion }
+func (Plugin) SupportedProjectVersions() []string     { return supportedProjectVersions }
+func (p Plugin) GetInitPlugin() plugin.Init           { return &p.initSubcommand }
+func (p Plugin) GetCreateAPIPlugin() plugin.CreateAPI { return &p.createAPISubcommand }
+
+// pluginConfig is the configuration of the plugin stored in the PROJECT file.
+type pluginConfig struct {
+	// Storage is where the API server stores the resources, either in etcd or in files.
+	Storage string `json:"storage,omitempty"`
+}
+
+// loadPluginConfig returns the configuration of the plugin stored in c, which is empty if it was not stored.
+func loadPluginConfig(c *config.Config) (pluginConfig, error) {
+	var cfg pluginConfig
+	err := c.DecodePluginConfig(plugin.KeyFor(Plugin{}), &cfg)
+	return cfg, err
+}
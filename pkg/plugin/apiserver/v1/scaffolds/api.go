@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/apiserver/v1/scaffolds/internal/templates"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/apiserver/v1/scaffolds/internal/templates/api"
+	configtemplates "sigs.k8s.io/kubebuilder/pkg/plugin/apiserver/v1/scaffolds/internal/templates/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+var _ scaffold.Scaffolder = &apiScaffolder{}
+
+// apiScaffolder scaffolds the types of a resource served by the API server, along with the APIService of its
+// group-version
+type apiScaffolder struct {
+	config      *config.Config
+	boilerplate string
+	resource    *resource.Resource
+	storage     string
+}
+
+// NewAPIScaffolder returns a new Scaffolder for the resources of an aggregated API server
+func NewAPIScaffolder(
+	config *config.Config,
+	boilerplate string,
+	res *resource.Resource,
+	storage string,
+) scaffold.Scaffolder {
+	return &apiScaffolder{
+		config:      config,
+		boilerplate: boilerplate,
+		resource:    res,
+		storage:     storage,
+	}
+}
+
+// Scaffold implements Scaffolder
+func (s *apiScaffolder) Scaffold() error {
+	logger.Default().Info("Writing scaffold for you to edit...")
+
+	s.config.UpdateResource(s.resource.GVK())
+
+	universe := model.NewUniverse(
+		model.WithConfig(s.config),
+		model.WithBoilerplate(s.boilerplate),
+		model.WithResource(s.resource),
+	)
+
+	if err := machinery.NewScaffold().Execute(
+		universe,
+		&api.Types{},
+		&api.Group{},
+	); err != nil {
+		return fmt.Errorf("error scaffolding APIs: %v", err)
+	}
+
+	namespace, namePrefix := s.config.Namespace, s.config.NamePrefix
+	if err := machinery.NewScaffold().Execute(
+		universe,
+		&configtemplates.APIService{Namespace: namespace, NamePrefix: namePrefix},
+		&configtemplates.KustomizationUpdater{},
+		&configtemplates.Sample{},
+	); err != nil {
+		return fmt.Errorf("error scaffolding manifests: %v", err)
+	}
+
+	if err := machinery.NewScaffold().Execute(
+		universe,
+		&templates.MainUpdater{Etcd: s.storage != StorageFilepath},
+	); err != nil {
+		return fmt.Errorf("error updating main.go: %v", err)
+	}
+
+	return nil
+}
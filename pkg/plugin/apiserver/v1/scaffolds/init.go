@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/apiserver/v1/scaffolds/internal/templates"
+	configtemplates "sigs.k8s.io/kubebuilder/pkg/plugin/apiserver/v1/scaffolds/internal/templates/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/apiserver/v1/scaffolds/internal/templates/hack"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/license"
+	kustomizev1 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v1"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+const (
+	// APIServerRuntimeVersion is the kubernetes-sigs/apiserver-runtime version to be used in the project
+	APIServerRuntimeVersion = "v1.0.2"
+	// ControllerToolsVersion is the kubernetes-sigs/controller-tools version generating the deepcopy code
+	ControllerToolsVersion = "v0.4.1"
+	// KubeOpenAPIVersion is the kubernetes/kube-openapi version generating the OpenAPI definitions, the one
+	// required by apiserver-runtime
+	KubeOpenAPIVersion = "v0.0.0-20201113171705-d219536bb9fd"
+
+	// StorageEtcd stores the resources in an etcd deployed along with the API server
+	StorageEtcd = "etcd"
+	// StorageFilepath stores the resources in JSON files, in a volume mounted by the API server
+	StorageFilepath = "filepath"
+)
+
+var _ scaffold.Scaffolder = &initScaffolder{}
+
+type initScaffolder struct {
+	config          *config.Config
+	boilerplatePath string
+	license         string
+	owner           string
+	copyrightYear   string
+	storage         string
+}
+
+// NewInitScaffolder returns a new Scaffolder for aggregated API server project initialization operations
+func NewInitScaffolder(config *config.Config, license, owner, copyrightYear, storage string) scaffold.Scaffolder {
+	return &initScaffolder{
+		config:          config,
+		boilerplatePath: filepath.Join("hack", "boilerplate.go.txt"),
+		license:         license,
+		owner:           owner,
+		copyrightYear:   copyrightYear,
+		storage:         storage,
+	}
+}
+
+func (s *initScaffolder) newUniverse(boilerplate string) *model.Universe {
+	return model.NewUniverse(
+		model.WithConfig(s.config),
+		model.WithBoilerplate(boilerplate),
+	)
+}
+
+// Scaffold implements Scaffolder
+func (s *initScaffolder) Scaffold() error {
+	logger.Default().Info("Writing scaffold for you to edit...")
+
+	bpFile := &hack.Boilerplate{}
+	bpFile.Path = s.boilerplatePath
+	bpFile.License = s.license
+	bpFile.Owner = s.owner
+	bpFile.Year = s.copyrightYear
+	if err := machinery.NewScaffold().Execute(
+		s.newUniverse(""),
+		bpFile,
+	); err != nil {
+		return err
+	}
+
+	boilerplate, err := ioutil.ReadFile(s.boilerplatePath) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	l, err := license.Lookup(s.license)
+	if err != nil {
+		return err
+	}
+
+	etcd := s.storage == StorageEtcd
+	namespace, namePrefix := s.config.Namespace, s.config.NamePrefix
+	builders := []file.Builder{
+		&templates.GoMod{APIServerRuntimeVersion: APIServerRuntimeVersion},
+		&templates.Main{Etcd: etcd},
+		&templates.OpenAPIDefinitions{},
+		&templates.Makefile{
+			BoilerplatePath:        s.boilerplatePath,
+			ControllerToolsVersion: ControllerToolsVersion,
+			KubeOpenAPIVersion:     KubeOpenAPIVersion,
+			KustomizeVersion:       kustomizev1.KustomizeVersion,
+			Etcd:                   etcd,
+		},
+		&templates.Dockerfile{},
+		&configtemplates.Kustomization{Etcd: etcd},
+		&configtemplates.Namespace{Namespace: namespace},
+		&configtemplates.RBAC{Namespace: namespace, NamePrefix: namePrefix},
+		&configtemplates.Certificate{Namespace: namespace, NamePrefix: namePrefix},
+		&configtemplates.APIServer{Namespace: namespace, NamePrefix: namePrefix, Etcd: etcd},
+	}
+	if l.Text != "" {
+		builders = append(builders, &templates.License{Text: l.Text, Owner: s.owner, Year: s.copyrightYear})
+	}
+	if etcd {
+		builders = append(builders, &configtemplates.Etcd{Namespace: namespace, NamePrefix: namePrefix})
+	} else {
+		builders = append(builders, &configtemplates.Storage{Namespace: namespace, NamePrefix: namePrefix})
+	}
+
+	return machinery.NewScaffold().Execute(s.newUniverse(string(boilerplate)), builders...)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Group{}
+
+// Group scaffolds the apis/<group>/<version>/groupversion_info.go
+type Group struct {
+	file.TemplateMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *Group) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("apis", "%[group]", "%[version]", "groupversion_info.go")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+
+	f.TemplateBody = groupTemplate
+
+	return nil
+}
+
+//nolint:lll
+const groupTemplate = `{{ .Boilerplate }}
+
+// Package {{ .Resource.Version }} contains API Schema definitions for the {{ .Resource.Group }} {{ .Resource.Version }} API group
+// +k8s:openapi-gen=true
+// +kubebuilder:object:generate=true
+// +groupName={{ .Resource.Domain }}
+package {{ .Resource.Version }}
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersion is group version of these objects, which the API server registers from their resource.Object methods
+var GroupVersion = schema.GroupVersion{Group: "{{ .Resource.Domain }}", Version: "{{ .Resource.Version }}"}
+`
@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Types{}
+
+// Types scaffolds the apis/<group>/<version>/<kind>_types.go, whose types implement the resource.Object interface
+// of apiserver-runtime so that the API server can serve them
+type Types struct {
+	file.TemplateMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *Types) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("apis", "%[group]", "%[version]", "%[kind]_types.go")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+
+	f.TemplateBody = typesTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const typesTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder/resource/resourcestrategy"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// {{ .Resource.Kind }}Spec defines the desired state of {{ .Resource.Kind }}
+type {{ .Resource.Kind }}Spec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Foo is an example field of {{ .Resource.Kind }}. Edit {{ lower .Resource.Kind }}_types.go to remove/update
+	Foo string ` + "`" + `json:"foo,omitempty"` + "`" + `
+}
+
+// {{ .Resource.Kind }}Status defines the observed state of {{ .Resource.Kind }}
+type {{ .Resource.Kind }}Status struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+}
+
+// +kubebuilder:object:root=true
+
+// {{ .Resource.Kind }} is the Schema for the {{ .Resource.Plural }} API
+type {{ .Resource.Kind }} struct {
+	metav1.TypeMeta   ` + "`" + `json:",inline"` + "`" + `
+	metav1.ObjectMeta ` + "`" + `json:"metadata,omitempty"` + "`" + `
+
+	Spec   {{ .Resource.Kind }}Spec   ` + "`" + `json:"spec,omitempty"` + "`" + `
+	Status {{ .Resource.Kind }}Status ` + "`" + `json:"status,omitempty"` + "`" + `
+}
+
+// +kubebuilder:object:root=true
+
+// {{ .Resource.Kind }}List contains a list of {{ .Resource.Kind }}
+type {{ .Resource.Kind }}List struct {
+	metav1.TypeMeta ` + "`" + `json:",inline"` + "`" + `
+	metav1.ListMeta ` + "`" + `json:"metadata,omitempty"` + "`" + `
+	Items           []{{ .Resource.Kind }} ` + "`" + `json:"items"` + "`" + `
+}
+
+var _ resource.Object = &{{ .Resource.Kind }}{}
+var _ resource.ObjectWithStatusSubResource = &{{ .Resource.Kind }}{}
+var _ resourcestrategy.Validater = &{{ .Resource.Kind }}{}
+
+// GetObjectMeta implements resource.Object
+func (in *{{ .Resource.Kind }}) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+// NamespaceScoped implements resource.Object
+func (in *{{ .Resource.Kind }}) NamespaceScoped() bool {
+	return {{ .Resource.Namespaced }}
+}
+
+// New implements resource.Object
+func (in *{{ .Resource.Kind }}) New() runtime.Object {
+	return &{{ .Resource.Kind }}{}
+}
+
+// NewList implements resource.Object
+func (in *{{ .Resource.Kind }}) NewList() runtime.Object {
+	return &{{ .Resource.Kind }}List{}
+}
+
+// GetGroupVersionResource implements resource.Object
+func (in *{{ .Resource.Kind }}) GetGroupVersionResource() schema.GroupVersionResource {
+	return GroupVersion.WithResource("{{ .Resource.Plural }}")
+}
+
+// IsStorageVersion implements resource.Object
+func (in *{{ .Resource.Kind }}) IsStorageVersion() bool {
+	return true
+}
+
+// Validate implements resourcestrategy.Validater
+func (in *{{ .Resource.Kind }}) Validate(ctx context.Context) field.ErrorList {
+	// TODO(user): return the errors of the invalid fields, the object is then rejected
+	return nil
+}
+
+// GetStatus implements resource.ObjectWithStatusSubResource
+func (in *{{ .Resource.Kind }}) GetStatus() resource.StatusSubResource {
+	return in.Status
+}
+
+var _ resource.ObjectList = &{{ .Resource.Kind }}List{}
+
+// GetListMeta implements resource.ObjectList
+func (in *{{ .Resource.Kind }}List) GetListMeta() *metav1.ListMeta {
+	return &in.ListMeta
+}
+
+var _ resource.StatusSubResource = {{ .Resource.Kind }}Status{}
+
+// SubResourceName implements resource.SubResource
+func (in {{ .Resource.Kind }}Status) SubResourceName() string {
+	return "status"
+}
+
+// CopyTo implements resource.StatusSubResource
+func (in {{ .Resource.Kind }}Status) CopyTo(parent resource.ObjectWithStatusSubResource) {
+	parent.(*{{ .Resource.Kind }}).Status = in
+}
+`
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &APIServer{}
+
+// APIServer scaffolds the deployment of the API server and the service the Kubernetes API server proxies the
+// requests to
+type APIServer struct {
+	file.TemplateMixin
+
+	// Namespace is the namespace the API server is deployed into
+	Namespace string
+	// NamePrefix is prepended to the names of the manifests
+	NamePrefix string
+	// Etcd determines whether the API server stores the resources in etcd, or else in JSON files
+	Etcd bool
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *APIServer) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "apiserver", "apiserver.yaml")
+	}
+
+	f.TemplateBody = apiServerTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const apiServerTemplate = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .NamePrefix }}apiserver
+  namespace: {{ .Namespace }}
+  labels:
+    app.kubernetes.io/name: {{ .NamePrefix }}apiserver
+spec:
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: {{ .NamePrefix }}apiserver
+  replicas: 1
+{{- if not .Etcd }}
+  # The volume of the JSON files is only mounted by a single replica
+  strategy:
+    type: Recreate
+{{- end }}
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: {{ .NamePrefix }}apiserver
+    spec:
+      serviceAccountName: {{ .NamePrefix }}apiserver
+      containers:
+      - name: apiserver
+        image: apiserver:latest
+        args:
+        - --secure-port=8443
+        - --tls-cert-file=/apiserver.local.config/certificates/tls.crt
+        - --tls-private-key-file=/apiserver.local.config/certificates/tls.key
+{{- if .Etcd }}
+        - --etcd-servers=http://{{ .NamePrefix }}etcd.{{ .Namespace }}.svc:2379
+{{- else }}
+        env:
+        - name: DATA_DIR
+          value: /data
+{{- end }}
+        ports:
+        - containerPort: 8443
+          name: https
+        volumeMounts:
+        - name: serving-cert
+          mountPath: /apiserver.local.config/certificates
+          readOnly: true
+{{- if not .Etcd }}
+        - name: data
+          mountPath: /data
+{{- end }}
+        resources:
+          limits:
+            cpu: 100m
+            memory: 100Mi
+          requests:
+            cpu: 100m
+            memory: 50Mi
+      volumes:
+      - name: serving-cert
+        secret:
+          secretName: {{ .NamePrefix }}serving-cert
+{{- if not .Etcd }}
+      - name: data
+        persistentVolumeClaim:
+          claimName: {{ .NamePrefix }}data
+{{- end }}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .NamePrefix }}apiserver
+  namespace: {{ .Namespace }}
+spec:
+  ports:
+  - port: 443
+    targetPort: https
+  selector:
+    app.kubernetes.io/name: {{ .NamePrefix }}apiserver
+`
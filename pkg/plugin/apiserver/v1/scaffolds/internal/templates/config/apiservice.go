@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &APIService{}
+
+// APIService scaffolds the APIService registering the group-version of a resource with the Kubernetes API server,
+// which then proxies its requests to the API server
+type APIService struct {
+	file.TemplateMixin
+	file.ResourceMixin
+
+	// Namespace is the namespace the API server is deployed into
+	Namespace string
+	// NamePrefix is prepended to the names of the manifests
+	NamePrefix string
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *APIService) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "apiserver", "apiservice_%[group]_%[version].yaml")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+
+	f.TemplateBody = apiServiceTemplate
+
+	// The other Kinds of the group-version are served through the same APIService
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+const apiServiceTemplate = `apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: {{ .Resource.Version }}.{{ .Resource.Domain }}
+  annotations:
+    cert-manager.io/inject-ca-from: {{ .Namespace }}/{{ .NamePrefix }}serving-cert
+spec:
+  group: {{ .Resource.Domain }}
+  version: {{ .Resource.Version }}
+  groupPriorityMinimum: 1000
+  versionPriority: 15
+  service:
+    name: {{ .NamePrefix }}apiserver
+    namespace: {{ .Namespace }}
+    port: 443
+`
@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Certificate{}
+
+// Certificate scaffolds the self-signed issuer and the serving certificate of the API server, issued by cert-manager
+type Certificate struct {
+	file.TemplateMixin
+
+	// Namespace is the namespace the API server is deployed into
+	Namespace string
+	// NamePrefix is prepended to the names of the manifests
+	NamePrefix string
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *Certificate) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "apiserver", "certificate.yaml")
+	}
+
+	f.TemplateBody = certificateTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const certificateTemplate = `# cert-manager injects the CA of the serving certificate into the APIServices
+# More document can be found at https://docs.cert-manager.io
+apiVersion: cert-manager.io/v1alpha2
+kind: Issuer
+metadata:
+  name: {{ .NamePrefix }}selfsigned-issuer
+  namespace: {{ .Namespace }}
+spec:
+  selfSigned: {}
+---
+apiVersion: cert-manager.io/v1alpha2
+kind: Certificate
+metadata:
+  name: {{ .NamePrefix }}serving-cert
+  namespace: {{ .Namespace }}
+spec:
+  dnsNames:
+  - {{ .NamePrefix }}apiserver.{{ .Namespace }}.svc
+  - {{ .NamePrefix }}apiserver.{{ .Namespace }}.svc.cluster.local
+  issuerRef:
+    kind: Issuer
+    name: {{ .NamePrefix }}selfsigned-issuer
+  secretName: {{ .NamePrefix }}serving-cert
+`
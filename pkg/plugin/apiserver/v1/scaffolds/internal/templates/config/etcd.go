@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+// etcdImage is the etcd image the resources are stored in
+const etcdImage = "k8s.gcr.io/etcd:3.4.13-0"
+
+var _ file.Template = &Etcd{}
+
+// Etcd scaffolds the etcd the API server stores the resources in
+type Etcd struct {
+	file.TemplateMixin
+
+	// Namespace is the namespace the API server is deployed into
+	Namespace string
+	// NamePrefix is prepended to the names of the manifests
+	NamePrefix string
+	// Image is the etcd image
+	Image string
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *Etcd) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "apiserver", "etcd.yaml")
+	}
+
+	if f.Image == "" {
+		f.Image = etcdImage
+	}
+
+	f.TemplateBody = etcdTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const etcdTemplate = `# A single etcd member storing the resources of the API server
+# TODO(user): use an etcd cluster for production, e.g. managed by an etcd operator
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: {{ .NamePrefix }}etcd
+  namespace: {{ .Namespace }}
+  labels:
+    app.kubernetes.io/name: {{ .NamePrefix }}etcd
+spec:
+  serviceName: {{ .NamePrefix }}etcd
+  replicas: 1
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: {{ .NamePrefix }}etcd
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: {{ .NamePrefix }}etcd
+    spec:
+      containers:
+      - name: etcd
+        image: {{ .Image }}
+        command:
+        - etcd
+        - --data-dir=/var/lib/etcd
+        - --listen-client-urls=http://0.0.0.0:2379
+        - --advertise-client-urls=http://{{ .NamePrefix }}etcd.{{ .Namespace }}.svc:2379
+        ports:
+        - containerPort: 2379
+          name: client
+        volumeMounts:
+        - name: data
+          mountPath: /var/lib/etcd
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      accessModes:
+      - ReadWriteOnce
+      resources:
+        requests:
+          storage: 1Gi
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .NamePrefix }}etcd
+  namespace: {{ .Namespace }}
+spec:
+  ports:
+  - port: 2379
+    targetPort: client
+  selector:
+    app.kubernetes.io/name: {{ .NamePrefix }}etcd
+`
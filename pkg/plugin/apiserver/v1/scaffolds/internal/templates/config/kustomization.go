@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+const kustomizationPath = "config/apiserver/kustomization.yaml"
+
+var _ file.Template = &Kustomization{}
+
+// Kustomization scaffolds the kustomization file of the API server manifests
+type Kustomization struct {
+	file.TemplateMixin
+
+	// Etcd determines whether the etcd manifests are deployed, or else the volume of the JSON files
+	Etcd bool
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *Kustomization) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.FromSlash(kustomizationPath)
+	}
+
+	f.TemplateBody = fmt.Sprintf(kustomizationTemplate, file.NewMarkerFor(f.Path, apiServiceMarker))
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+var _ file.Inserter = &KustomizationUpdater{}
+
+// KustomizationUpdater adds the APIService of the group-version of a resource to the kustomization file
+type KustomizationUpdater struct {
+	file.ResourceMixin
+}
+
+// GetPath implements file.Builder
+func (*KustomizationUpdater) GetPath() string {
+	return filepath.FromSlash(kustomizationPath)
+}
+
+// GetIfExistsAction implements file.Builder
+func (*KustomizationUpdater) GetIfExistsAction() file.IfExistsAction {
+	return file.Overwrite
+}
+
+const apiServiceMarker = "apiservices"
+
+// GetMarkers implements file.Inserter
+func (f *KustomizationUpdater) GetMarkers() []file.Marker {
+	return []file.Marker{file.NewMarkerFor(f.GetPath(), apiServiceMarker)}
+}
+
+const apiServiceCodeFragment = `- apiservice_%s_%s.yaml
+`
+
+// GetCodeFragments implements file.Inserter
+func (f *KustomizationUpdater) GetCodeFragments() file.CodeFragmentsMap {
+	fragments := make(file.CodeFragmentsMap, 1)
+
+	// If resource is not being provided we are creating the file, not updating it
+	if f.Resource == nil {
+		return fragments
+	}
+
+	fragments[file.NewMarkerFor(f.GetPath(), apiServiceMarker)] = []string{
+		fmt.Sprintf(apiServiceCodeFragment, f.Resource.Group, f.Resource.Version),
+	}
+	return fragments
+}
+
+// The manifests name their namespace and each other explicitly rather than with the namespace and namePrefix
+// fields, as the API server must read the authentication configuration from a RoleBinding in kube-system.
+const kustomizationTemplate = `resources:
+- namespace.yaml
+- rbac.yaml
+- certificate.yaml
+- apiserver.yaml
+{{- if .Etcd }}
+- etcd.yaml
+{{- else }}
+- storage.yaml
+{{- end }}
+# The APIServices registering the group-versions served by the API server with the Kubernetes API server
+%s
+`
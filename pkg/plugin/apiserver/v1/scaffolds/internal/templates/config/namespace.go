@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Namespace{}
+
+// Namespace scaffolds the namespace the API server is deployed into
+type Namespace struct {
+	file.TemplateMixin
+
+	// Namespace is the namespace the API server is deployed into
+	Namespace string
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *Namespace) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "apiserver", "namespace.yaml")
+	}
+
+	f.TemplateBody = namespaceTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const namespaceTemplate = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: {{ .Namespace }}
+`
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &RBAC{}
+
+// RBAC scaffolds the service account of the API server and the permissions it needs to delegate the
+// authentication and authorization of the requests to the Kubernetes API server
+type RBAC struct {
+	file.TemplateMixin
+
+	// Namespace is the namespace the API server is deployed into
+	Namespace string
+	// NamePrefix is prepended to the names of the manifests
+	NamePrefix string
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *RBAC) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "apiserver", "rbac.yaml")
+	}
+
+	f.TemplateBody = rbacTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const rbacTemplate = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ .NamePrefix }}apiserver
+  namespace: {{ .Namespace }}
+---
+# Allows the API server to delegate the authentication and authorization of the requests
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: {{ .NamePrefix }}auth-delegator
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: system:auth-delegator
+subjects:
+- kind: ServiceAccount
+  name: {{ .NamePrefix }}apiserver
+  namespace: {{ .Namespace }}
+---
+# Allows the API server to read the configuration of the request header authentication
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: {{ .NamePrefix }}auth-reader
+  namespace: kube-system
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: extension-apiserver-authentication-reader
+subjects:
+- kind: ServiceAccount
+  name: {{ .NamePrefix }}apiserver
+  namespace: {{ .Namespace }}
+---
+# Allows the admission and the priority and fairness of the API server to watch their configuration
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: {{ .NamePrefix }}apiserver
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - namespaces
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - admissionregistration.k8s.io
+  resources:
+  - mutatingwebhookconfigurations
+  - validatingwebhookconfigurations
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - flowcontrol.apiserver.k8s.io
+  resources:
+  - flowschemas
+  - prioritylevelconfigurations
+  verbs:
+  - get
+  - list
+  - watch
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: {{ .NamePrefix }}apiserver
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: {{ .NamePrefix }}apiserver
+subjects:
+- kind: ServiceAccount
+  name: {{ .NamePrefix }}apiserver
+  namespace: {{ .Namespace }}
+`
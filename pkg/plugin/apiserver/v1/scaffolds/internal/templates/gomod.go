@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &GoMod{}
+
+// GoMod scaffolds the go.mod of the project
+type GoMod struct {
+	file.TemplateMixin
+	file.RepositoryMixin
+
+	// APIServerRuntimeVersion is the sigs.k8s.io/apiserver-runtime version required by the project
+	APIServerRuntimeVersion string
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *GoMod) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = "go.mod"
+	}
+
+	f.TemplateBody = goModTemplate
+
+	// An existing go.mod is kept as its module path was already validated and it may contain dependencies
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+const goModTemplate = `
+module {{ .Repo }}
+
+go 1.15
+
+require sigs.k8s.io/apiserver-runtime {{ .APIServerRuntimeVersion }}
+`
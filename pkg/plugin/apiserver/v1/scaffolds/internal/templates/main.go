@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+const defaultMainPath = "main.go"
+
+var _ file.Template = &Main{}
+
+// Main scaffolds the API server entry point
+type Main struct {
+	file.TemplateMixin
+	file.BoilerplateMixin
+	file.RepositoryMixin
+	file.ProjectNameMixin
+
+	// Etcd determines whether the API server stores the resources in etcd, or else in JSON files
+	Etcd bool
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *Main) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = defaultMainPath
+	}
+
+	f.TemplateBody = fmt.Sprintf(mainTemplate,
+		file.NewMarkerFor(f.Path, importMarker),
+		file.NewMarkerFor(f.Path, resourceMarker),
+	)
+
+	return nil
+}
+
+var _ file.Inserter = &MainUpdater{}
+
+// MainUpdater updates main.go to serve a resource
+type MainUpdater struct {
+	file.ResourceMixin
+
+	// Etcd determines whether the resource is stored in etcd, or else in JSON files
+	Etcd bool
+}
+
+// GetPath implements file.Builder
+func (*MainUpdater) GetPath() string {
+	return defaultMainPath
+}
+
+// GetIfExistsAction implements file.Builder
+func (*MainUpdater) GetIfExistsAction() file.IfExistsAction {
+	return file.Overwrite
+}
+
+const (
+	importMarker   = "imports"
+	resourceMarker = "resources"
+)
+
+// GetMarkers implements file.Inserter
+func (f *MainUpdater) GetMarkers() []file.Marker {
+	return []file.Marker{
+		file.NewMarkerFor(defaultMainPath, importMarker),
+		file.NewMarkerFor(defaultMainPath, resourceMarker),
+	}
+}
+
+const (
+	apiImportCodeFragment = `%s "%s"
+`
+	filepathImportCodeFragment = `"sigs.k8s.io/apiserver-runtime/pkg/experimental/storage/filepath"
+`
+	resourceCodeFragment = `WithResource(&%s.%s{}).
+`
+	filepathResourceCodeFragment = `WithResourceAndHandler(&%[1]s.%[2]s{},
+			filepath.NewJSONFilepathStorageProvider(&%[1]s.%[2]s{}, dataDir)).
+`
+)
+
+// GetCodeFragments implements file.Inserter
+func (f *MainUpdater) GetCodeFragments() file.CodeFragmentsMap {
+	fragments := make(file.CodeFragmentsMap, 2)
+
+	// If resource is not being provided we are creating the file, not updating it
+	if f.Resource == nil {
+		return fragments
+	}
+
+	imports := []string{fmt.Sprintf(apiImportCodeFragment, f.Resource.ImportAlias, f.Resource.Package)}
+	resources := make([]string, 0, 1)
+	if f.Etcd {
+		resources = append(resources, fmt.Sprintf(resourceCodeFragment, f.Resource.ImportAlias, f.Resource.Kind))
+	} else {
+		imports = append(imports, filepathImportCodeFragment)
+		resources = append(resources,
+			fmt.Sprintf(filepathResourceCodeFragment, f.Resource.ImportAlias, f.Resource.Kind))
+	}
+
+	fragments[file.NewMarkerFor(defaultMainPath, importMarker)] = imports
+	fragments[file.NewMarkerFor(defaultMainPath, resourceMarker)] = resources
+
+	return fragments
+}
+
+var mainTemplate = `{{ .Boilerplate }}
+
+package main
+
+import (
+{{- if not .Etcd }}
+	"os"
+
+{{- end }}
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/apiserver-runtime/pkg/builder"
+
+	"{{ .Repo }}/pkg/generated/openapi"
+	%s
+)
+{{- if not .Etcd }}
+
+// dataDir is the directory the resources are stored in as JSON files, overridden by the DATA_DIR environment
+// variable
+var dataDir = "data"
+
+func init() {
+	if dir := os.Getenv("DATA_DIR"); dir != "" {
+		dataDir = dir
+	}
+}
+{{- end }}
+
+func main() {
+	err := builder.APIServer.
+		%s
+		WithOpenAPIDefinitions("{{ .ProjectName }}", "v0.0.0", openapi.GetOpenAPIDefinitions).
+{{- if not .Etcd }}
+		WithoutEtcd().
+{{- end }}
+		WithLocalDebugExtension().
+		Execute()
+	if err != nil {
+		klog.Fatal(err)
+	}
+}
+`
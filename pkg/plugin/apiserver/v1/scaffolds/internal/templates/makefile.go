@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Makefile{}
+
+// Makefile scaffolds the Makefile generating, building and deploying the API server
+type Makefile struct {
+	file.TemplateMixin
+	file.RepositoryMixin
+	file.ProjectNameMixin
+
+	// BoilerplatePath is the path to the boilerplate file of the generated code
+	BoilerplatePath string
+	// ControllerToolsVersion is the kubernetes-sigs/controller-tools version generating the deepcopy code
+	ControllerToolsVersion string
+	// KubeOpenAPIVersion is the kubernetes/kube-openapi version generating the OpenAPI definitions
+	KubeOpenAPIVersion string
+	// KustomizeVersion is the kubernetes-sigs/kustomize version building the manifests
+	KustomizeVersion string
+	// Etcd determines whether the API server stores the resources in etcd, which 'make run' then expects locally
+	Etcd bool
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *Makefile) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = "Makefile"
+	}
+
+	f.TemplateBody = makefileTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const makefileTemplate = `# Image URL to use all building/pushing image targets
+IMG ?= {{ .ProjectName }}:latest
+
+# Directory the tools are installed into
+LOCALBIN = $(shell pwd)/bin
+
+# Packages the OpenAPI definitions are generated for, the APIs of the project and the apimachinery types they use
+APIMACHINERY_DIRS = k8s.io/apimachinery/pkg/apis/meta/v1,k8s.io/apimachinery/pkg/runtime,k8s.io/apimachinery/pkg/version
+OPENAPI_INPUT_DIRS = $(shell go list ./apis/... 2>/dev/null | tr '\n' ',')$(APIMACHINERY_DIRS)
+
+all: apiserver
+
+# Run tests
+test: generate fmt vet
+	go test ./... -coverprofile cover.out
+
+# Build apiserver binary
+apiserver: generate fmt vet
+	go build -o bin/apiserver main.go
+
+# Run the API server locally on https://localhost:8443, without authenticating nor authorizing the requests
+run: generate fmt vet
+{{- if .Etcd }}
+	go run ./main.go --secure-port=8443 --standalone-debug-mode --etcd-servers=http://127.0.0.1:2379
+{{- else }}
+	go run ./main.go --secure-port=8443 --standalone-debug-mode
+{{- end }}
+
+# Deploy the API server in the configured Kubernetes cluster in ~/.kube/config, cert-manager must be installed
+deploy: kustomize
+	cd config/apiserver && $(KUSTOMIZE) edit set image apiserver=${IMG}
+	$(KUSTOMIZE) build config/apiserver | kubectl apply -f -
+
+# UnDeploy the API server from the configured Kubernetes cluster in ~/.kube/config
+undeploy: kustomize
+	$(KUSTOMIZE) build config/apiserver | kubectl delete -f -
+
+# Run go fmt against code
+fmt:
+	go fmt ./...
+
+# Run go vet against code
+vet:
+	go vet ./...
+
+# Generate the deepcopy code and the OpenAPI definitions of the APIs
+generate: controller-gen openapi-gen
+	$(CONTROLLER_GEN) object:headerFile="{{ .BoilerplatePath }}" paths="./..."
+	@{ \
+	set -e ;\
+	OUTPUT_BASE=$$(mktemp -d) ;\
+	$(OPENAPI_GEN) --input-dirs $(OPENAPI_INPUT_DIRS) --output-package {{ .Repo }}/pkg/generated/openapi \
+		--output-file-base zz_generated.openapi --go-header-file {{ .BoilerplatePath }} \
+		--output-base $$OUTPUT_BASE --report-filename /dev/null ;\
+	cp $$OUTPUT_BASE/{{ .Repo }}/pkg/generated/openapi/zz_generated.openapi.go pkg/generated/openapi/ ;\
+	rm -rf $$OUTPUT_BASE ;\
+	}
+
+# Build the docker image
+docker-build: test
+	docker build . -t ${IMG}
+
+# Push the docker image
+docker-push:
+	docker push ${IMG}
+
+CONTROLLER_GEN = $(LOCALBIN)/controller-gen
+controller-gen:
+	$(call go-install-tool,$(CONTROLLER_GEN),sigs.k8s.io/controller-tools/cmd/controller-gen@{{ .ControllerToolsVersion }})
+
+OPENAPI_GEN = $(LOCALBIN)/openapi-gen
+openapi-gen:
+	$(call go-install-tool,$(OPENAPI_GEN),k8s.io/kube-openapi/cmd/openapi-gen@{{ .KubeOpenAPIVersion }})
+
+KUSTOMIZE = $(LOCALBIN)/kustomize
+kustomize:
+	$(call go-install-tool,$(KUSTOMIZE),sigs.k8s.io/kustomize/kustomize/v3@{{ .KustomizeVersion }})
+
+# go-install-tool installs the package $(2) at the version following the @ into $(1), unless it exists
+define go-install-tool
+@[ -f $(1) ] || { \
+set -e ;\
+TMP_DIR=$$(mktemp -d) ;\
+cd $$TMP_DIR ;\
+go mod init tmp ;\
+echo "Installing $(2)" ;\
+GOBIN=$(LOCALBIN) go get $(2) ;\
+rm -rf $$TMP_DIR ;\
+}
+endef
+`
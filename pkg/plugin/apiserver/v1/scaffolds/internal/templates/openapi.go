@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &OpenAPIDefinitions{}
+
+// OpenAPIDefinitions scaffolds the OpenAPI definitions of the APIs served by the API server until they are
+// generated, so that the project builds before its first API is created
+type OpenAPIDefinitions struct {
+	file.TemplateMixin
+	file.BoilerplateMixin
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *OpenAPIDefinitions) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("pkg", "generated", "openapi", "zz_generated.openapi.go")
+	}
+
+	f.TemplateBody = openAPIDefinitionsTemplate
+
+	// The generated definitions are never replaced
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+const openAPIDefinitionsTemplate = `// +build !ignore_autogenerated
+
+{{ .Boilerplate }}
+
+// Package openapi is replaced by the OpenAPI definitions generated by openapi-gen when running 'make generate'.
+package openapi
+
+import (
+	common "k8s.io/kube-openapi/pkg/common"
+)
+
+func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
+	return map[string]common.OpenAPIDefinition{}
+}
+`
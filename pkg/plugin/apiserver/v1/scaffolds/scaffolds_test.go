@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+)
+
+var _ = Describe("Scaffolders", func() {
+	var dir, wd string
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		var err error
+		wd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err = ioutil.TempDir("", "apiserver-scaffolds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(dir)).To(Succeed())
+
+		cfg = &config.Config{
+			Version:     config.Version3Alpha,
+			Domain:      "example.org",
+			Repo:        "example.org/wardle",
+			ProjectName: "wardle",
+			Namespace:   "wardle-system",
+			NamePrefix:  "wardle-",
+			MultiGroup:  true,
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(wd)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	read := func(path string) string {
+		bs, err := ioutil.ReadFile(filepath.FromSlash(path))
+		Expect(err).NotTo(HaveOccurred())
+		return string(bs)
+	}
+
+	createAPI := func(storage, version, kind string) {
+		opts := resource.Options{Group: "wardle", Version: version, Kind: kind, Namespaced: true}
+		res := opts.NewResource(cfg, true)
+		Expect(NewAPIScaffolder(cfg, "", res, storage).Scaffold()).To(Succeed())
+	}
+
+	It("should serve the resources stored in etcd", func() {
+		Expect(NewInitScaffolder(cfg, "none", "", "", StorageEtcd).Scaffold()).To(Succeed())
+		Expect(read("config/apiserver/etcd.yaml")).To(ContainSubstring("name: wardle-etcd\n"))
+		Expect(read("config/apiserver/apiserver.yaml")).To(ContainSubstring(
+			"--etcd-servers=http://wardle-etcd.wardle-system.svc:2379\n"))
+		Expect(read("main.go")).NotTo(ContainSubstring("WithoutEtcd()"))
+		_, err := os.Stat(filepath.Join("config", "apiserver", "storage.yaml"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		createAPI(StorageEtcd, "v1alpha1", "Flunder")
+		createAPI(StorageEtcd, "v1alpha1", "Fischer")
+
+		main := read("main.go")
+		Expect(main).To(ContainSubstring(`wardlev1alpha1 "example.org/wardle/apis/wardle/v1alpha1"`))
+		Expect(main).To(ContainSubstring("WithResource(&wardlev1alpha1.Flunder{}).\n" +
+			"\t\tWithResource(&wardlev1alpha1.Fischer{}).\n" +
+			"\t\t// +kubebuilder:scaffold:resources\n"))
+		Expect(read("apis/wardle/v1alpha1/flunder_types.go")).To(ContainSubstring(
+			`return GroupVersion.WithResource("flunders")`))
+		Expect(read("config/apiserver/kustomization.yaml")).To(ContainSubstring(
+			"- etcd.yaml\n# The APIServices registering the group-versions served by the API server with the " +
+				"Kubernetes API server\n- apiservice_wardle_v1alpha1.yaml\n# +kubebuilder:scaffold:apiservices\n"))
+		Expect(read("config/apiserver/apiservice_wardle_v1alpha1.yaml")).To(And(
+			ContainSubstring("name: v1alpha1.wardle.example.org\n"),
+			ContainSubstring("cert-manager.io/inject-ca-from: wardle-system/wardle-serving-cert\n"),
+		))
+		Expect(read("config/samples/wardle_v1alpha1_fischer.yaml")).To(ContainSubstring(
+			"apiVersion: wardle.example.org/v1alpha1\nkind: Fischer\n"))
+	})
+
+	It("should serve the resources stored in JSON files", func() {
+		Expect(NewInitScaffolder(cfg, "none", "", "", StorageFilepath).Scaffold()).To(Succeed())
+		Expect(read("config/apiserver/storage.yaml")).To(ContainSubstring("name: wardle-data\n"))
+		Expect(read("config/apiserver/apiserver.yaml")).To(ContainSubstring("claimName: wardle-data\n"))
+		_, err := os.Stat(filepath.Join("config", "apiserver", "etcd.yaml"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		createAPI(StorageFilepath, "v1alpha1", "Flunder")
+
+		main := read("main.go")
+		Expect(main).To(ContainSubstring(`"sigs.k8s.io/apiserver-runtime/pkg/experimental/storage/filepath"`))
+		Expect(main).To(ContainSubstring("WithResourceAndHandler(&wardlev1alpha1.Flunder{},\n" +
+			"\t\t\tfilepath.NewJSONFilepathStorageProvider(&wardlev1alpha1.Flunder{}, dataDir)).\n"))
+		Expect(main).To(ContainSubstring("WithoutEtcd()."))
+	})
+
+	It("should write the header and the LICENSE file of the license with the copyright year", func() {
+		Expect(NewInitScaffolder(cfg, "MIT", "Jane Doe", "2018-2020", StorageEtcd).Scaffold()).To(Succeed())
+		Expect(read("hack/boilerplate.go.txt")).To(Equal(
+			"/*\nCopyright 2018-2020 Jane Doe.\n\nSPDX-License-Identifier: MIT\n*/"))
+		Expect(read("LICENSE")).To(HavePrefix("MIT License\n\nCopyright (c) 2018-2020 Jane Doe\n"))
+		Expect(read("main.go")).To(HavePrefix("/*\nCopyright 2018-2020 Jane Doe.\n"))
+	})
+
+	It("should not write a LICENSE file without a license", func() {
+		Expect(NewInitScaffolder(cfg, "none", "", "", StorageEtcd).Scaffold()).To(Succeed())
+		_, err := os.Stat("LICENSE")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
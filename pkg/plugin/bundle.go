@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"fmt"
+)
+
+// Bundle allows a group of plugins to be used under a single key, each of its plugins being chained in order.
+type Bundle interface {
+	Base
+	// Plugins returns the plugins grouped by this bundle, in the order their subcommands are run.
+	Plugins() []Base
+}
+
+type bundle struct {
+	name                     string
+	version                  Version
+	plugins                  []Base
+	supportedProjectVersions []string
+}
+
+// NewBundle returns a Bundle with the provided name and version that groups plugins. The bundle supports the
+// project versions that are supported by every one of its plugins, which must be at least one.
+func NewBundle(name string, version Version, plugins ...Base) (Bundle, error) {
+	if len(plugins) == 0 {
+		return nil, fmt.Errorf("bundle %q must contain at least one plugin", Key(name, version.String()))
+	}
+
+	supportedProjectVersions := plugins[0].SupportedProjectVersions()
+	for _, p := range plugins[1:] {
+		supportedProjectVersions = intersectProjectVersions(supportedProjectVersions, p.SupportedProjectVersions())
+	}
+	if len(supportedProjectVersions) == 0 {
+		return nil, fmt.Errorf("plugins of bundle %q do not support any common project version",
+			Key(name, version.String()))
+	}
+
+	return bundle{
+		name:                     name,
+		version:                  version,
+		plugins:                  plugins,
+		supportedProjectVersions: supportedProjectVersions,
+	}, nil
+}
+
+// Name implements Base
+func (b bundle) Name() string {
+	return b.name
+}
+
+// Version implements Base
+func (b bundle) Version() Version {
+	return b.version
+}
+
+// SupportedProjectVersions implements Base
+func (b bundle) SupportedProjectVersions() []string {
+	return b.supportedProjectVersions
+}
+
+// Plugins implements Bundle
+func (b bundle) Plugins() []Base {
+	return b.plugins
+}
+
+// intersectProjectVersions returns the project versions contained in both a and b, keeping the order of a.
+func intersectProjectVersions(a, b []string) []string {
+	common := make([]string, 0, len(a))
+	for _, version := range a {
+		for _, other := range b {
+			if version == other {
+				common = append(common, version)
+				break
+			}
+		}
+	}
+	return common
+}
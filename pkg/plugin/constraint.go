@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Valid version constraint operators.
+const (
+	// EqualOperator matches a single version. It is used when a constraint has no operator.
+	EqualOperator = "="
+	// GreaterOperator matches versions greater than the constraint version.
+	GreaterOperator = ">"
+	// GreaterOrEqualOperator matches versions greater than or equal to the constraint version.
+	GreaterOrEqualOperator = ">="
+	// LessOperator matches versions less than the constraint version.
+	LessOperator = "<"
+	// LessOrEqualOperator matches versions less than or equal to the constraint version.
+	LessOrEqualOperator = "<="
+	// CompatibleOperator matches versions with the same number as the constraint version, whatever their stage.
+	CompatibleOperator = "~"
+)
+
+// operators are sorted so that two-character operators are matched before their prefixes.
+var operators = []string{
+	GreaterOrEqualOperator,
+	LessOrEqualOperator,
+	GreaterOperator,
+	LessOperator,
+	EqualOperator,
+	CompatibleOperator,
+}
+
+// VersionConstraint restricts the plugin versions a plugin key can be resolved to.
+type VersionConstraint struct {
+	// Operator is one of the constraint operators, EqualOperator by default.
+	Operator string
+	// Version is compared to plugin versions using Operator.
+	Version Version
+}
+
+// ParseVersionConstraint parses constraint into a VersionConstraint, assuming it is a version
+// as accepted by ParseVersion, optionally prefixed by one of: =, >, >=, <, <=, ~
+func ParseVersionConstraint(constraint string) (c VersionConstraint, err error) {
+	c.Operator = EqualOperator
+	for _, op := range operators {
+		if strings.HasPrefix(constraint, op) {
+			c.Operator = op
+			constraint = strings.TrimPrefix(constraint, op)
+			break
+		}
+	}
+
+	if c.Version, err = ParseVersion(constraint); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// IsRange returns true if c can match more than one version.
+func (c VersionConstraint) IsRange() bool {
+	return c.Operator != EqualOperator
+}
+
+// Check returns true if v satisfies c.
+func (c VersionConstraint) Check(v Version) bool {
+	switch cmp := v.Compare(c.Version); c.Operator {
+	case GreaterOperator:
+		return cmp > 0
+	case GreaterOrEqualOperator:
+		return cmp >= 0
+	case LessOperator:
+		return cmp < 0
+	case LessOrEqualOperator:
+		return cmp <= 0
+	case CompatibleOperator:
+		return v.Number == c.Version.Number
+	default:
+		return cmp == 0
+	}
+}
+
+func (c VersionConstraint) String() string {
+	if c.Operator == EqualOperator {
+		return c.Version.String()
+	}
+	return fmt.Sprintf("%s%s", c.Operator, c.Version)
+}
@@ -19,6 +19,7 @@ package plugin
 import (
 	"github.com/spf13/pflag"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 )
 
@@ -41,6 +42,17 @@ type Deprecated interface {
 	DeprecationWarning() string
 }
 
+// Removable is a Deprecated plugin that announces the release of the CLI it is removed in.
+type Removable interface {
+	Deprecated
+	// RemovalVersion returns the version of the CLI the plugin is removed in, e.g. "v3.0.0".
+	RemovalVersion() string
+}
+
+// GenericSubcommand is run by the CLI in phases: each phase is run for every subcommand of a plugin chain,
+// in order, before the next phase starts. This way no file is written until every subcommand has been
+// validated, and post-scaffolding steps see the files and config written by the whole chain.
+// A subcommand is only injected the config, and bound its flags, when its command runs or its help is written.
 type GenericSubcommand interface {
 	// UpdateContext updates a Context with command-specific help text, like description and examples.
 	// Can be a no-op if default help text is desired.
@@ -48,14 +60,23 @@ type GenericSubcommand interface {
 	// BindFlags binds the plugin's flags to the CLI. This allows each plugin to define its own
 	// command line flags for the kubebuilder subcommand.
 	BindFlags(*pflag.FlagSet)
-	// Run runs the subcommand.
-	Run() error
 	// InjectConfig passes a config to a plugin. The plugin may modify the
 	// config. Initializing, loading, and saving the config is managed by the
 	// cli package.
 	InjectConfig(*config.Config)
+	// Validate verifies that the subcommand can be run, ex. that its flags are valid. No file must be
+	// written in this phase.
+	Validate() error
+	// Scaffold writes the files of the subcommand and updates the config accordingly. The config is
+	// saved once every subcommand of the chain has scaffolded.
+	Scaffold() error
+	// PostScaffold finishes running the subcommand, ex. by running commands on the scaffolded project.
+	PostScaffold() error
 }
 
+// Context is the help text of a command, updated by the subcommands of its plugins. When several plugins are
+// chained, each subcommand is given a Context without the help text of the others, and the help text it sets
+// is written in a section labeled with the key of its plugin.
 type Context struct {
 	// CommandName sets the command name for a plugin.
 	CommandName string
@@ -64,6 +85,11 @@ type Context struct {
 	// Examples are one or more examples of the command-line usage
 	// of this plugin's project subcommand support. It is used to display help.
 	Examples string
+	// Logger writes the output of a plugin at the verbosity requested by the user.
+	Logger logger.Logger
+	// GlobalFlags are the persistent flags registered by the CLI embedding this plugin, already
+	// parsed when the plugin is run. It is nil if no global flags were registered.
+	GlobalFlags *pflag.FlagSet
 }
 
 type InitPluginGetter interface {
@@ -95,3 +121,52 @@ type CreateWebhookPluginGetter interface {
 type CreateWebhook interface {
 	GenericSubcommand
 }
+
+type DeleteAPIPluginGetter interface {
+	Base
+	// GetDeleteAPIPlugin returns the underlying DeleteAPI interface.
+	GetDeleteAPIPlugin() DeleteAPI
+}
+
+// DeleteAPI removes the files and code scaffolded for a resource, the plugins that scaffolded them
+// being chained in the same order as for API creation.
+type DeleteAPI interface {
+	GenericSubcommand
+}
+
+type DeleteWebhookPluginGetter interface {
+	Base
+	// GetDeleteWebhookPlugin returns the underlying DeleteWebhook interface.
+	GetDeleteWebhookPlugin() DeleteWebhook
+}
+
+// DeleteWebhook removes the webhooks scaffolded for a resource, keeping the resource itself.
+type DeleteWebhook interface {
+	GenericSubcommand
+}
+
+// Command is a subcommand contributed by a plugin, in addition to the subcommands
+// every plugin can implement, ex. "kubebuilder create channel" or "kubebuilder bundle".
+type Command struct {
+	// Path is the space separated path to the command from the root command, ex. "create channel".
+	// Commands can be mounted under the root, "alpha" and "create" commands.
+	Path string
+	// Short is a short description of the command, displayed in the help of its parent command.
+	Short string
+	// Subcommand implements the command, which requires an initialized project.
+	Subcommand GenericSubcommand
+}
+
+type CommandsPluginGetter interface {
+	Base
+	// GetCommands returns the commands contributed by the plugin.
+	GetCommands() []Command
+}
+
+// LayoutDescriber is implemented by plugins that describe what the files and directories they scaffold are for.
+type LayoutDescriber interface {
+	Base
+	// DescribeLayout returns a short description of each file or directory the plugin scaffolds for the project
+	// configured by c, by slash-separated path relative to the project root, ex. "config/rbac".
+	DescribeLayout(c *config.Config) map[string]string
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package license holds the catalog of the licenses projects can be scaffolded with: the header of their source
+// files and the text of their LICENSE file.
+package license
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// Apache2 is the alias of Apache-2.0 accepted for compatibility, the default license of the projects
+	Apache2 = "apache2"
+	// None is the license whose header only holds the copyright, no LICENSE file being written
+	None = "none"
+)
+
+// License is a license of the catalog
+type License struct {
+	// ID is the SPDX identifier of the license, e.g. "MIT", or "proprietary"
+	ID string
+	// Header is the template of the header of the source files, with the .Owner and .Year fields
+	Header string
+	// Text is the template of the LICENSE file, with the .Owner and .Year fields, empty if it is not written
+	Text string
+}
+
+var catalog = []License{
+	{ID: "AGPL-3.0", Header: agplHeader, Text: agplText},
+	{ID: "Apache-2.0", Header: apacheHeader, Text: apacheText},
+	{ID: "BSD-3-Clause", Header: spdxHeader("BSD-3-Clause"), Text: bsd3ClauseText},
+	{ID: "MIT", Header: spdxHeader("MIT"), Text: mitText},
+	{ID: "MPL-2.0", Header: mplHeader, Text: mplText},
+	{ID: "proprietary", Header: proprietaryHeader, Text: proprietaryText},
+}
+
+// IDs returns the identifiers of the licenses of the catalog, sorted
+func IDs() []string {
+	ids := make([]string, 0, len(catalog))
+	for _, l := range catalog {
+		ids = append(ids, l.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Lookup returns the license identified by id, case-insensitively, either a license of the catalog, None, or
+// Apache2 which is also the license of an empty id
+func Lookup(id string) (License, error) {
+	switch strings.ToLower(id) {
+	case "", Apache2:
+		id = "Apache-2.0"
+	case None:
+		return License{ID: None, Header: noneHeader}, nil
+	}
+	for _, l := range catalog {
+		if strings.EqualFold(l.ID, id) {
+			return l, nil
+		}
+	}
+	return License{}, fmt.Errorf("unknown license %q, possible values: (%s, %s, %s)",
+		id, strings.Join(IDs(), ", "), Apache2, None)
+}
+
+var yearRegexp = regexp.MustCompile(`^[0-9]{4}(-[0-9]{4})?$`)
+
+// ValidateYear checks that year is the year of a copyright, e.g. 2020, or a range of years, e.g. 2018-2020
+func ValidateYear(year string) error {
+	if !yearRegexp.MatchString(year) {
+		return fmt.Errorf("invalid copyright year %q, expected a year, e.g. 2020, or a range of years, "+
+			"e.g. 2018-2020", year)
+	}
+	return nil
+}
+
+// Description returns the description of the licenses for the help of a --license flag
+func Description() string {
+	return fmt.Sprintf("license of the boilerplate header and the LICENSE file, the SPDX identifier of one of (%s), "+
+		"%q standing for Apache-2.0, or %q for a header with the copyright only and no LICENSE file",
+		strings.Join(IDs(), ", "), Apache2, None)
+}
+
+// copyrightLine is the first line of the headers, only written if the owner is set
+const copyrightLine = `{{ if .Owner -}}
+Copyright {{ .Year }} {{ .Owner }}.
+{{- end }}`
+
+func spdxHeader(id string) string {
+	return `/*
+` + copyrightLine + `
+
+SPDX-License-Identifier: ` + id + `
+*/`
+}
+
+const noneHeader = `/*
+` + copyrightLine + `
+*/`
+
+const apacheHeader = `/*
+` + copyrightLine + `
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/`
+
+const mplHeader = `/*
+` + copyrightLine + `
+
+This Source Code Form is subject to the terms of the Mozilla Public
+License, v. 2.0. If a copy of the MPL was not distributed with this
+file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+SPDX-License-Identifier: MPL-2.0
+*/`
+
+const agplHeader = `/*
+` + copyrightLine + `
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+SPDX-License-Identifier: AGPL-3.0-or-later
+*/`
+
+const proprietaryHeader = `/*
+` + copyrightLine + `
+
+All rights reserved. This file is proprietary and confidential, see the
+LICENSE file for the terms it may be used under.
+*/`
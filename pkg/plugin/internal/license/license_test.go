@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package license
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestLookup(t *testing.T) {
+	for id, expected := range map[string]string{
+		"":             "Apache-2.0",
+		"apache2":      "Apache-2.0",
+		"Apache-2.0":   "Apache-2.0",
+		"mit":          "MIT",
+		"BSD-3-Clause": "BSD-3-Clause",
+		"mpl-2.0":      "MPL-2.0",
+		"AGPL-3.0":     "AGPL-3.0",
+		"Proprietary":  "proprietary",
+		"none":         "none",
+	} {
+		l, err := Lookup(id)
+		if err != nil {
+			t.Errorf("unexpected error looking up %q: %v", id, err)
+			continue
+		}
+		if l.ID != expected {
+			t.Errorf("license %q: expected %q, got %q", id, expected, l.ID)
+		}
+	}
+
+	if l, _ := Lookup(None); l.Text != "" {
+		t.Errorf("license none has a LICENSE file")
+	}
+
+	_, err := Lookup("GPL-2.0")
+	expected := `unknown license "GPL-2.0", possible values: ` +
+		`(AGPL-3.0, Apache-2.0, BSD-3-Clause, MIT, MPL-2.0, proprietary, apache2, none)`
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestValidateYear(t *testing.T) {
+	for _, year := range []string{"2020", "2018-2020"} {
+		if err := ValidateYear(year); err != nil {
+			t.Errorf("unexpected error validating %q: %v", year, err)
+		}
+	}
+	for _, year := range []string{"", "20", "2020-", "2018 2020", "next year"} {
+		if err := ValidateYear(year); err == nil {
+			t.Errorf("expected an error validating %q", year)
+		}
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	data := struct{ Owner, Year string }{Owner: "The Kubernetes Authors", Year: "2020"}
+	for _, id := range append(IDs(), None) {
+		l, _ := Lookup(id)
+		for name, body := range map[string]string{"header": l.Header, "text": l.Text} {
+			tmpl, err := template.New(id).Parse(body)
+			if err != nil {
+				t.Errorf("%s of license %s: %v", name, id, err)
+				continue
+			}
+			out := &bytes.Buffer{}
+			if err := tmpl.Execute(out, data); err != nil {
+				t.Errorf("%s of license %s: %v", name, id, err)
+			}
+			if name == "header" && !strings.Contains(out.String(), "Copyright 2020 The Kubernetes Authors.") {
+				t.Errorf("header of license %s misses the copyright:\n%s", id, out)
+			}
+		}
+	}
+
+	mit, _ := Lookup("MIT")
+	out := &bytes.Buffer{}
+	if err := template.Must(template.New("MIT").Parse(mit.Text)).Execute(out, data); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "\nCopyright (c) 2020 The Kubernetes Authors\n") {
+		t.Errorf("LICENSE file of MIT misses the copyright:\n%s", out)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
+)
+
+// domainSkipDirs are not walked when changing the domain, as they hold binaries, dependencies or git objects.
+var domainSkipDirs = map[string]bool{
+	".git":    true,
+	"bin":     true,
+	"testbin": true,
+	"vendor":  true,
+}
+
+// ChangeDomain replaces every qualified group, "<group>.<oldDomain>", of the provided groups with
+// "<group>.<newDomain>" in the contents and names of the files under root, as well as its dashed form
+// used in webhook paths. Binary files and the directories in domainSkipDirs are left untouched.
+//
+// The qualified groups are the only occurrences of the domain that can be safely replaced, so the files
+// that still contain oldDomain afterwards, e.g. in a leader election ID, are returned sorted for the user
+// to review them. The occurrences that are part of the module path, repo, are not references to the domain
+// and do not make a file be returned.
+func ChangeDomain(root, repo string, groups []string, oldDomain, newDomain string) ([]string, error) {
+	if errs := validation.IsDNS1123Subdomain(newDomain); len(errs) != 0 {
+		return nil, fmt.Errorf("invalid domain %q: %s", newDomain, strings.Join(errs, ", "))
+	}
+	if len(groups) == 0 || oldDomain == newDomain {
+		return nil, nil
+	}
+
+	replace := func(s []byte) []byte {
+		s = replaceQualifiedGroups(s, groups, ".", oldDomain, newDomain)
+		// Webhook paths contain the qualified groups with dashes instead of dots
+		return replaceQualifiedGroups(s, groups, "-", oldDomain, newDomain)
+	}
+
+	var pending []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root && domainSkipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(content, 0) != -1 {
+			return nil
+		}
+
+		newPath := filepath.Join(filepath.Dir(path), string(replace([]byte(info.Name()))))
+		newContent := replace(content)
+		if newPath != path || !bytes.Equal(newContent, content) {
+			if err := ioutil.WriteFile(newPath, newContent, info.Mode()); err != nil {
+				return err
+			}
+			if newPath != path {
+				if err := os.Remove(path); err != nil {
+					return err
+				}
+			}
+		}
+
+		if repo != "" {
+			newContent = bytes.Replace(newContent, []byte(repo), nil, -1)
+		}
+		if bytes.Contains(newContent, []byte(oldDomain)) {
+			rel, err := filepath.Rel(root, newPath)
+			if err != nil {
+				return err
+			}
+			pending = append(pending, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(pending)
+	return pending, nil
+}
+
+// replaceQualifiedGroups replaces oldDomain with newDomain in the groups qualified with sep in s.
+// The dashed form is only replaced in webhook paths, as it may be part of any other name.
+func replaceQualifiedGroups(s []byte, groups []string, sep, oldDomain, newDomain string) []byte {
+	quoted := make([]string, 0, len(groups))
+	for _, group := range groups {
+		quoted = append(quoted, regexp.QuoteMeta(strings.Replace(group, ".", sep, -1)))
+	}
+	oldDomain = strings.Replace(oldDomain, ".", sep, -1)
+	newDomain = strings.Replace(newDomain, ".", sep, -1)
+	expr := `(?:` + strings.Join(quoted, "|") + `)` + regexp.QuoteMeta(sep+oldDomain)
+	if sep != "." {
+		expr = `/(?:mutate|validate)-` + expr
+	}
+	re := regexp.MustCompile(expr)
+
+	var out []byte
+	last := 0
+	for _, loc := range re.FindAllIndex(s, -1) {
+		// A qualified group continues neither before nor after the match, webhook paths continue with the version
+		if sep == "." && (loc[0] > 0 && isDNSLabelChar(s[loc[0]-1]) || loc[1] < len(s) && isDNSLabelChar(s[loc[1]])) ||
+			sep != "." && (loc[1] == len(s) || s[loc[1]] != '-') {
+			continue
+		}
+		out = append(out, s[last:loc[1]-len(oldDomain)]...)
+		out = append(out, newDomain...)
+		last = loc[1]
+	}
+	return append(out, s[last:]...)
+}
+
+// isDNSLabelChar returns true if c may be part of a DNS label.
+func isDNSLabelChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-'
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestChangeDomain(t *testing.T) {
+	root, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	files := map[string]string{
+		"api/v1/groupversion_info.go": "// +groupName=crew.example.com\n" +
+			`GroupVersion = schema.GroupVersion{Group: "crew.example.com", Version: "v1"}` + "\n",
+		"api/v1/captain_webhook.go": "// +kubebuilder:webhook:path=/mutate-crew-example-com-v1-captain," +
+			"groups=crew.example.com,name=mcaptain.kb.io\n",
+		"config/crd/bases/crew.example.com_captains.yaml": "  name: captains.crew.example.com\n",
+		"config/crd/kustomization.yaml":                   "- bases/crew.example.com_captains.yaml\n",
+		"go.mod":                                          "module example.com/p3\n",
+		"controllers/captain_controller.go":               `crewv1 "example.com/p3/api/v1"` + "\n",
+		"main.go":                                         `LeaderElectionID: "14be1926.example.com"` + "\n",
+		"README.md":                                       "screw.example.com crew.example.community\n",
+		"bin/manager":                                     "crew.example.com\x00",
+		"vendor/crew.example.com/doc.go":                  "crew.example.com\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pending, err := ChangeDomain(root, "example.com/p3", []string{"crew"}, "example.com", "new.example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"README.md", "main.go"}; !reflect.DeepEqual(pending, expected) {
+		t.Errorf("expected %v to be reported, got %v", expected, pending)
+	}
+
+	expected := map[string]string{
+		"api/v1/groupversion_info.go": "// +groupName=crew.new.example.org\n" +
+			`GroupVersion = schema.GroupVersion{Group: "crew.new.example.org", Version: "v1"}` + "\n",
+		"api/v1/captain_webhook.go": "// +kubebuilder:webhook:path=/mutate-crew-new-example-org-v1-captain," +
+			"groups=crew.new.example.org,name=mcaptain.kb.io\n",
+		"config/crd/bases/crew.new.example.org_captains.yaml": "  name: captains.crew.new.example.org\n",
+		"config/crd/kustomization.yaml":                       "- bases/crew.new.example.org_captains.yaml\n",
+		"go.mod":                                              files["go.mod"],
+		"controllers/captain_controller.go":                   files["controllers/captain_controller.go"],
+		"main.go":                                             files["main.go"],
+		"README.md":                                           files["README.md"],
+		"bin/manager":                                         files["bin/manager"],
+		"vendor/crew.example.com/doc.go":                      files["vendor/crew.example.com/doc.go"],
+	}
+	for path, content := range expected {
+		b, err := ioutil.ReadFile(filepath.Join(root, path))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if string(b) != content {
+			t.Errorf("expected %s to be %q, got %q", path, content, string(b))
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "config/crd/bases/crew.example.com_captains.yaml")); !os.IsNotExist(err) {
+		t.Errorf("expected the file named after the old domain to be removed, got %v", err)
+	}
+
+	if _, err := ChangeDomain(root, "example.com/p3", []string{"crew"}, "new.example.org", "Example.com"); err == nil {
+		t.Error("expected an invalid domain to fail")
+	}
+}
@@ -17,17 +17,38 @@ limitations under the License.
 package util
 
 import (
-	"fmt"
+	"io/ioutil"
 	"os"
-	"os/exec"
 	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/tools"
 )
 
-// RunCmd prints the provided message and command and then executes it binding stdout and stderr
+// RunCmd prints the provided message and command and then executes it binding stdout and stderr.
+// The output of the command is discarded if the logger is quiet, except for stderr. The command is
+// executed in the tools environment set by the cli.
 func RunCmd(msg, cmd string, args ...string) error {
-	c := exec.Command(cmd, args...) //nolint:gosec
+	return RunCmdInDir(msg, "", cmd, args...)
+}
+
+// RunCmdInDir is RunCmd executing the command in dir, the current directory if empty.
+func RunCmdInDir(msg, dir, cmd string, args ...string) error {
+	log := logger.Default()
+	c := tools.Default().Command(cmd, args...)
+	c.Dir = dir
 	c.Stdout = os.Stdout
+	if log.Level() <= logger.QuietLevel {
+		c.Stdout = ioutil.Discard
+	}
 	c.Stderr = os.Stderr
-	fmt.Println(msg + ":\n$ " + strings.Join(c.Args, " "))
-	return c.Run()
+	if dir != "" {
+		log.Info(msg + ":\n$ cd " + dir + " && " + strings.Join(c.Args, " "))
+	} else {
+		log.Info(msg + ":\n$ " + strings.Join(c.Args, " "))
+	}
+	log.Debug("executing command", "command", strings.Join(c.Args, " "))
+	err := c.Run()
+	log.Debug("command finished", "command", strings.Join(c.Args, " "), "error", err)
+	return err
 }
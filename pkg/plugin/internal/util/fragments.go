@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// RemoveCodeFragments removes the lines of each of the code fragments from the file at path, as they were
+// inserted at a marker. Files may have been formatted since, so any run of blanks in a fragment matches any
+// other, and Go files are formatted again. Fragments that are not found, e.g. because they were edited, and
+// a file that does not exist are ignored.
+func RemoveCodeFragments(path string, fragments ...string) error {
+	replacements := make(map[string]string, len(fragments))
+	for _, fragment := range fragments {
+		replacements[fragment] = ""
+	}
+	return ReplaceCodeFragments(path, replacements)
+}
+
+// ReplaceCodeFragments replaces the lines of each code fragment key of replacements with its value in the file
+// at path, matching the fragments as RemoveCodeFragments does.
+func ReplaceCodeFragments(path string, replacements map[string]string) error {
+	content, err := ioutil.ReadFile(path) // nolint:gosec
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	updated := content
+	for fragment, replacement := range replacements {
+		if strings.TrimSpace(fragment) == "" {
+			continue
+		}
+		updated = fragmentRegexp(fragment).ReplaceAllLiteral(updated, []byte(replacement))
+	}
+	if bytes.Equal(updated, content) {
+		return nil
+	}
+	if filepath.Ext(path) == ".go" {
+		if updated, err = format.Source(updated); err != nil {
+			return err
+		}
+	}
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(path, updated, 0644)
+}
+
+// fragmentRegexp returns a regexp matching the whole lines of fragment.
+func fragmentRegexp(fragment string) *regexp.Regexp {
+	fields := strings.Fields(fragment)
+	for i, field := range fields {
+		fields[i] = regexp.QuoteMeta(field)
+	}
+	return regexp.MustCompile(`(?m)^[ \t]*` + strings.Join(fields, `\s*`) + `[ \t]*(?:\n|\z)`)
+}
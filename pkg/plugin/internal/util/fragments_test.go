@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoveCodeFragments(t *testing.T) {
+	root, err := ioutil.TempDir("", "fragments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		fragments []string
+		expected  string
+	}{
+		{
+			name: "go file formatted after the insertion",
+			file: "main.go",
+			content: "package main\n\nimport (\n\t\"fmt\"\n\n\tcrewv1 \"example.com/project/api/v1\"\n)\n\n" +
+				"func main() {\n\tfmt.Println()\n\tif err = (&controllers.CaptainReconciler{\n" +
+				"\t\tClient: mgr.GetClient(),\n\t}).SetupWithManager(mgr); err != nil {\n\t\treturn\n\t}\n" +
+				"\t// +kubebuilder:scaffold:builder\n}\n",
+			fragments: []string{
+				"crewv1 \"example.com/project/api/v1\"\n",
+				"if err = (&controllers.CaptainReconciler{\nClient:   mgr.GetClient(),\n" +
+					"}).SetupWithManager(mgr); err != nil {\n\t\treturn\n\t}\n",
+			},
+			expected: "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() {\n\tfmt.Println()\n" +
+				"\t// +kubebuilder:scaffold:builder\n}\n",
+		},
+		{
+			name:      "yaml lines",
+			file:      "kustomization.yaml",
+			content:   "resources:\n- bases/crew.example.com_captains.yaml\n- bases/crew.example.com_admirals.yaml\n",
+			fragments: []string{"- bases/crew.example.com_captains.yaml\n"},
+			expected:  "resources:\n- bases/crew.example.com_admirals.yaml\n",
+		},
+		{
+			name:      "partial lines and missing fragments",
+			file:      "kustomization-missing.yaml",
+			content:   "resources:\n- bases/crew.example.com_captains.yaml\n",
+			fragments: []string{"- bases/crew.example.com", "- bases/crew.example.com_sailors.yaml\n", ""},
+			expected:  "resources:\n- bases/crew.example.com_captains.yaml\n",
+		},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(root, test.file)
+		if err := ioutil.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := RemoveCodeFragments(path, test.fragments...); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != test.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.expected, content)
+		}
+	}
+
+	if err := RemoveCodeFragments(filepath.Join(root, "missing.go"), "package main"); err != nil {
+		t.Errorf("a missing file should be ignored: %v", err)
+	}
+}
+
+func TestReplaceCodeFragments(t *testing.T) {
+	root, err := ioutil.TempDir("", "fragments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	path := filepath.Join(root, "kustomization.yaml")
+	content := "patchesStrategicMerge:\n- patches/webhook_in_captains.yaml\n#- patches/webhook_in_admirals.yaml\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReplaceCodeFragments(path, map[string]string{
+		"- patches/webhook_in_captains.yaml\n": "#- patches/webhook_in_captains.yaml\n",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "patchesStrategicMerge:\n#- patches/webhook_in_captains.yaml\n#- patches/webhook_in_admirals.yaml\n"
+	if string(updated) != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, updated)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/tools"
+)
+
+// GitAuthor returns the author git config sets for the commits of the current directory: its user.name followed
+// by its user.email in angle brackets, either of which may be unset. It is empty if git is not installed or if
+// neither is set.
+func GitAuthor() string {
+	name, email := gitConfig("user.name"), gitConfig("user.email")
+	switch {
+	case email == "":
+		return name
+	case name == "":
+		return "<" + email + ">"
+	default:
+		return name + " <" + email + ">"
+	}
+}
+
+// gitConfig returns the value of a git config key, empty if it is unset
+func gitConfig(key string) string {
+	cmd := tools.Default().Command("git", "config", "--get", key)
+	logger.Default().Debug("executing command", "command", strings.Join(cmd.Args, " "))
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
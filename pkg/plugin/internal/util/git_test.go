@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitAuthor(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "git-author")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the global config of the test is read
+	gitconfig := filepath.Join(dir, ".gitconfig")
+	for key, value := range map[string]string{
+		"HOME":                dir,
+		"GIT_CONFIG_GLOBAL":   gitconfig,
+		"GIT_CONFIG_NOSYSTEM": "1",
+	} {
+		defer os.Setenv(key, os.Getenv(key))
+		if err := os.Setenv(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for config, expected := range map[string]string{
+		"":                                 "",
+		"[user]\n\tname = Jane Doe\n":      "Jane Doe",
+		"[user]\n\temail = jane@doe.org\n": "<jane@doe.org>",
+		"[user]\n\tname = Jane Doe\n\temail = jane@doe.org\n": "Jane Doe <jane@doe.org>",
+	} {
+		if err := ioutil.WriteFile(gitconfig, []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if author := GitAuthor(); author != expected {
+			t.Errorf("git config %q: expected author %q, got %q", config, expected, author)
+		}
+	}
+}
@@ -18,10 +18,12 @@ package util
 
 import (
 	"fmt"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/tools"
 )
 
 // ValidateGoVersion verifies that Go is installed and the current go version is supported by kubebuilder
@@ -34,7 +36,8 @@ func ValidateGoVersion() error {
 }
 
 func fetchAndCheckGoVersion() error {
-	cmd := exec.Command("go", "version")
+	cmd := tools.Default().Command("go", "version")
+	logger.Default().Debug("executing command", "command", strings.Join(cmd.Args, " "))
 	out, err := cmd.Output()
 	if err != nil {
 		return fmt.Errorf("failed to retrieve 'go version': %v", string(out))
@@ -18,11 +18,17 @@ package util
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/tools"
 )
 
 // module and goMod arg just enough of the output of `go mod edit -json` for our purposes
@@ -33,10 +39,15 @@ type module struct {
 	Path string
 }
 
-// findGoModulePath finds the path of the current module, if present.
-func findGoModulePath(forceModules bool) (string, error) {
-	cmd := exec.Command("go", "mod", "edit", "-json")
-	cmd.Env = append(cmd.Env, os.Environ()...)
+// findGoModulePath finds the path of the module declared in goModPath, or of the current module if
+// goModPath is empty.
+func findGoModulePath(forceModules bool, goModPath string) (string, error) {
+	args := []string{"mod", "edit", "-json"}
+	if goModPath != "" {
+		args = append(args, goModPath)
+	}
+	cmd := tools.Default().Command("go", args...)
+	logger.Default().Debug("executing command", "command", strings.Join(cmd.Args, " "))
 	if forceModules {
 		cmd.Env = append(cmd.Env, "GO111MODULE=on" /* turn on modules just for these commands */)
 	}
@@ -58,7 +69,7 @@ func findGoModulePath(forceModules bool) (string, error) {
 // though a combination of go/packages and `go mod` commands/tricks.
 func FindCurrentRepo() (string, error) {
 	// easiest case: existing go module
-	path, err := findGoModulePath(false)
+	path, err := findGoModulePath(false, "")
 	if err == nil {
 		return path, nil
 	}
@@ -77,8 +88,8 @@ func FindCurrentRepo() (string, error) {
 	}
 
 	// otherwise, try to get `go mod init` to guess for us -- it's pretty good
-	cmd := exec.Command("go", "mod", "init")
-	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd := tools.Default().Command("go", "mod", "init")
+	logger.Default().Debug("executing command", "command", strings.Join(cmd.Args, " "))
 	cmd.Env = append(cmd.Env, "GO111MODULE=on" /* turn on modules just for these commands */)
 	if _, err := cmd.Output(); err != nil {
 		if exitErr, isExitErr := err.(*exec.ExitError); isExitErr {
@@ -89,5 +100,50 @@ func FindCurrentRepo() (string, error) {
 			"package data, or by initializing a module: %v", err)
 	}
 	defer os.Remove("go.mod") // clean up after ourselves
-	return findGoModulePath(true)
+	return findGoModulePath(true, "")
+}
+
+// FindModuleRoot returns the closest parent directory of dir that has a go.mod file, dir being excluded.
+func FindModuleRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	root, err := findDirWith(filepath.Dir(dir), "go.mod")
+	if err == nil && root == "" {
+		err = errors.New("no go.mod found in the parent directories")
+	}
+	return root, err
+}
+
+// FindWorkspaceRoot returns the closest directory among dir and its parents that has a go.work file, empty if
+// there is none.
+func FindWorkspaceRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	return findDirWith(dir, "go.work")
+}
+
+// findDirWith returns the closest directory among dir, which must be absolute, and its parents that has a file
+// named name, empty if there is none.
+func findDirWith(dir, name string) (string, error) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return dir, nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// FindModulePath returns the module path declared in the provided go.mod file.
+func FindModulePath(goModPath string) (string, error) {
+	return findGoModulePath(true, goModPath)
 }
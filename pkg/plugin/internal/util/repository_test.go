@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindModuleRoot(t *testing.T) {
+	root, err := ioutil.TempDir("", "module")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	// The temporary directory may be a symbolic link, e.g. on macOS
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(root, "operators", "captain")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FindModuleRoot(dir); err == nil {
+		t.Error("expected an error without any go.mod")
+	}
+
+	for _, path := range []string{filepath.Join(root, "go.mod"), filepath.Join(dir, "go.mod")} {
+		if err := ioutil.WriteFile(path, []byte("module example.com/monorepo\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	found, err := FindModuleRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found != root {
+		t.Errorf("expected module root %q, got %q", root, found)
+	}
+}
+
+func TestFindWorkspaceRoot(t *testing.T) {
+	root, err := ioutil.TempDir("", "workspace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(root, "operator")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := FindWorkspaceRoot(dir); err != nil || found != "" {
+		t.Errorf("expected no workspace, got %q, %v", found, err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(root, "go.work"), []byte("go 1.18\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []string{root, dir} {
+		found, err := FindWorkspaceRoot(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found != root {
+			t.Errorf("expected workspace root %q from %q, got %q", root, d, found)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+)
+
+// maxSampleDepth bounds the nesting of the sampled structs, as types may be recursive
+const maxSampleDepth = 5
+
+// SpecSample returns the YAML lines, indented by two spaces, of plausible example values of the fields of the
+// Spec struct of kind defined in the Go file at path. The values are derived from the default, enum and bound
+// validation markers of the fields, or else from their names and types. It returns false if the file or the
+// struct does not exist.
+func SpecSample(path, kind string) (string, bool, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+
+	s := specSampler{types: make(map[string]*ast.TypeSpec)}
+	for _, decl := range f.Decls {
+		genDecl, isGenDecl := decl.(*ast.GenDecl)
+		if !isGenDecl || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			// The comments of single type declarations are attached to the declaration
+			if typeSpec.Doc == nil && len(genDecl.Specs) == 1 {
+				typeSpec.Doc = genDecl.Doc
+			}
+			s.types[typeSpec.Name.Name] = typeSpec
+		}
+	}
+
+	spec, found := s.types[kind+"Spec"]
+	if !found {
+		return "", false, nil
+	}
+	structType, isStruct := spec.Type.(*ast.StructType)
+	if !isStruct {
+		return "", false, nil
+	}
+
+	var b strings.Builder
+	for _, line := range s.structLines(structType, 0) {
+		b.WriteString("  " + line + "\n")
+	}
+	return b.String(), true, nil
+}
+
+// specSampler derives example values from the types declared in a Go file
+type specSampler struct {
+	types map[string]*ast.TypeSpec
+}
+
+// structLines returns the YAML lines of the example values of the fields of structType, unindented
+func (s specSampler) structLines(structType *ast.StructType, depth int) []string {
+	var lines []string
+	for _, field := range structType.Fields.List {
+		name, inline := jsonName(field)
+		if inline {
+			// The fields of embedded structs of the file are part of the embedding one
+			if embedded, isStruct := s.structOf(field.Type); isStruct && depth < maxSampleDepth {
+				lines = append(lines, s.structLines(embedded, depth+1)...)
+			}
+			continue
+		}
+		if name == "" {
+			continue
+		}
+
+		scalar, values, ok := s.value(name, field.Type, fieldMarkers(field.Doc), depth)
+		switch {
+		case !ok:
+		case scalar != "":
+			lines = append(lines, name+": "+scalar)
+		default:
+			lines = append(lines, name+":")
+			for _, value := range values {
+				lines = append(lines, "  "+value)
+			}
+		}
+	}
+	return lines
+}
+
+// value returns an example value of a field named name of type expr, either a scalar or the YAML lines of a
+// mapping or a sequence, and false if no plausible one is known
+func (s specSampler) value(name string, expr ast.Expr, markers map[string]string, depth int) (string, []string, bool) {
+	if value, found := markers["default"]; found {
+		return value, nil, true
+	}
+	if depth >= maxSampleDepth {
+		return "", nil, false
+	}
+
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return s.value(name, t.X, markers, depth)
+	case *ast.Ident:
+		if typeSpec, found := s.types[t.Name]; found {
+			// Named types inherit the markers of their declaration
+			for key, value := range fieldMarkers(typeSpec.Doc) {
+				if _, set := markers[key]; !set {
+					markers[key] = value
+				}
+			}
+			if structType, isStruct := typeSpec.Type.(*ast.StructType); isStruct {
+				lines := s.structLines(structType, depth+1)
+				if len(lines) == 0 {
+					return "{}", nil, true
+				}
+				return "", lines, true
+			}
+			return s.value(name, typeSpec.Type, markers, depth+1)
+		}
+		return scalarValue(name, t.Name, markers)
+	case *ast.SelectorExpr:
+		// Only the well-known types of the Kubernetes API machinery get a value
+		switch t.Sel.Name {
+		case "Duration":
+			return "1m0s", nil, true
+		case "Quantity":
+			return `"1"`, nil, true
+		}
+		return "", nil, false
+	case *ast.ArrayType:
+		elemMarkers := make(map[string]string, len(markers))
+		for key, value := range markers {
+			if strings.HasPrefix(key, "validation:items:") {
+				elemMarkers["validation:"+strings.TrimPrefix(key, "validation:items:")] = value
+			}
+		}
+		scalar, lines, ok := s.value(flect.Singularize(name), t.Elt, elemMarkers, depth+1)
+		if !ok {
+			return "", nil, false
+		}
+		if scalar != "" {
+			return "", []string{"- " + scalar}, true
+		}
+		values := []string{"- " + lines[0]}
+		for _, line := range lines[1:] {
+			values = append(values, "  "+line)
+		}
+		return "", values, true
+	case *ast.MapType:
+		key := flect.Dasherize(flect.Singularize(name))
+		scalar, lines, ok := s.value(key, t.Value, map[string]string{}, depth+1)
+		if !ok {
+			return "", nil, false
+		}
+		if scalar != "" {
+			return "", []string{key + ": " + scalar}, true
+		}
+		values := []string{key + ":"}
+		for _, line := range lines {
+			values = append(values, "  "+line)
+		}
+		return "", values, true
+	}
+	return "", nil, false
+}
+
+// structOf returns the struct declared in the file that expr refers to, if any
+func (s specSampler) structOf(expr ast.Expr) (*ast.StructType, bool) {
+	if star, isStar := expr.(*ast.StarExpr); isStar {
+		expr = star.X
+	}
+	ident, isIdent := expr.(*ast.Ident)
+	if !isIdent {
+		return nil, false
+	}
+	typeSpec, found := s.types[ident.Name]
+	if !found {
+		return nil, false
+	}
+	structType, isStruct := typeSpec.Type.(*ast.StructType)
+	return structType, isStruct
+}
+
+// scalarValue returns an example value of a field named name of the builtin type typeName
+func scalarValue(name, typeName string, markers map[string]string) (string, []string, bool) {
+	if enum, found := markers["validation:Enum"]; found {
+		return strings.Split(enum, ";")[0], nil, true
+	}
+
+	switch typeName {
+	case "string":
+		value := flect.Dasherize(name)
+		if minLength, err := strconv.Atoi(markers["validation:MinLength"]); err == nil && len(value) < minLength {
+			value += strings.Repeat("x", minLength-len(value))
+		}
+		if maxLength, err := strconv.Atoi(markers["validation:MaxLength"]); err == nil && len(value) > maxLength {
+			value = value[:maxLength]
+		}
+		return quoteIfAmbiguous(value), nil, true
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64":
+		value := 1.0
+		if minimum, err := strconv.ParseFloat(markers["validation:Minimum"], 64); err == nil && minimum > value {
+			value = minimum
+		}
+		if maximum, err := strconv.ParseFloat(markers["validation:Maximum"], 64); err == nil && maximum < value {
+			value = maximum
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64), nil, true
+	case "bool":
+		return "true", nil, true
+	}
+	return "", nil, false
+}
+
+// quoteIfAmbiguous quotes value if YAML would not read it as a string
+func quoteIfAmbiguous(value string) string {
+	if value == "" {
+		return `""`
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return strconv.Quote(value)
+	}
+	switch strings.ToLower(value) {
+	case "true", "false", "yes", "no", "on", "off", "null", "y", "n", "~":
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// jsonName returns the name of the JSON field of field, empty if it is not serialized, and whether its fields
+// are inlined into the enclosing struct
+func jsonName(field *ast.Field) (string, bool) {
+	var tag string
+	if field.Tag != nil {
+		if unquoted, err := strconv.Unquote(field.Tag.Value); err == nil {
+			tag = reflect.StructTag(unquoted).Get("json")
+		}
+	}
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if option == "inline" {
+			return "", true
+		}
+	}
+	if len(field.Names) == 0 {
+		return parts[0], parts[0] == ""
+	}
+	if parts[0] == "-" {
+		return "", false
+	}
+	if parts[0] == "" {
+		return field.Names[0].Name, false
+	}
+	return parts[0], false
+}
+
+// fieldMarkers returns the values of the +kubebuilder markers of doc, keyed by their name without the prefix
+func fieldMarkers(doc *ast.CommentGroup) map[string]string {
+	markers := make(map[string]string)
+	if doc == nil {
+		return markers
+	}
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(text, "+kubebuilder:") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "+kubebuilder:")
+		if i := strings.Index(text, "="); i > 0 {
+			markers[strings.TrimSuffix(text[:i], ":")] = strings.TrimSpace(text[i+1:])
+		}
+	}
+	return markers
+}
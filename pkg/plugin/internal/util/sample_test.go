@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSpecSample(t *testing.T) {
+	root, err := ioutil.TempDir("", "sample")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name: "scaffolded fields",
+			content: "package v1\n\ntype CaptainSpec struct {\n\t// Foo is an example field\n" +
+				"\tFoo string `json:\"foo,omitempty\"`\n}\n",
+			expected: "  foo: foo\n",
+		},
+		{
+			name: "markers",
+			content: "package v1\n\ntype CaptainSpec struct {\n" +
+				"\t// +kubebuilder:default=3\n\tReplicas *int32 `json:\"replicas,omitempty\"`\n" +
+				"\t// +kubebuilder:validation:Minimum=5\n\t// +kubebuilder:validation:Maximum=10\n" +
+				"\tMinSailors int `json:\"minSailors\"`\n" +
+				"\t// +kubebuilder:validation:MaxLength=4\n\tShipName string `json:\"shipName\"`\n" +
+				"\tPolicy Policy `json:\"policy\"`\n}\n\n" +
+				"// +kubebuilder:validation:Enum=Always;Never\ntype Policy string\n",
+			expected: "  replicas: 3\n  minSailors: 5\n  shipName: ship\n  policy: Always\n",
+		},
+		{
+			name: "nested types",
+			content: "package v1\n\nimport corev1 \"k8s.io/api/core/v1\"\n\ntype CaptainSpec struct {\n" +
+				"\tCrew []Sailor `json:\"crew\"`\n\tPorts []int32 `json:\"ports\"`\n" +
+				"\tLabels map[string]string `json:\"labels\"`\n\tResources corev1.ResourceRequirements `json:\"resources\"`\n" +
+				"\tCommon `json:\",inline\"`\n\tInternal string `json:\"-\"`\n}\n\n" +
+				"type Sailor struct {\n\tName string `json:\"name\"`\n\tOnDuty bool `json:\"onDuty\"`\n}\n\n" +
+				"type Common struct {\n\tShip *Ship `json:\"ship,omitempty\"`\n}\n\ntype Ship struct{}\n",
+			expected: "  crew:\n    - name: name\n      onDuty: true\n  ports:\n    - 1\n  labels:\n    label: label\n" +
+				"  ship: {}\n",
+		},
+	}
+
+	for _, test := range tests {
+		path := filepath.Join(root, "captain_types.go")
+		if err := ioutil.WriteFile(path, []byte(test.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		sample, found, err := SpecSample(path, "Captain")
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !found {
+			t.Fatalf("%s: CaptainSpec not found", test.name)
+		}
+		if sample != test.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.name, test.expected, sample)
+		}
+	}
+
+	if _, found, err := SpecSample(filepath.Join(root, "missing_types.go"), "Captain"); err != nil || found {
+		t.Errorf("a missing file should not be found: %v", err)
+	}
+}
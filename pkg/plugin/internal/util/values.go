@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// valueKeyRe matches the keys of the values that can be used as fields in templates, e.g. {{ .Values.team }}
+var valueKeyRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseValues parses the key=value pairs of the --set flags into values, the last one of a key winning.
+func ParseValues(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		i := strings.Index(pair, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("value %q is invalid, must be of the form key=value", pair)
+		}
+		key := pair[:i]
+		if !valueKeyRe.MatchString(key) {
+			return nil, fmt.Errorf("key %q of value %q is invalid, must only contain letters, digits and "+
+				"underscores, and not start with a digit", key, pair)
+		}
+		values[key] = pair[i+1:]
+	}
+	return values, nil
+}
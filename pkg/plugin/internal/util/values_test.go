@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseValues(t *testing.T) {
+	values, err := ParseValues([]string{"team=platform", "registry=quay.io/acme", "team=core", "empty=", "eq=a=b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"team": "core", "registry": "quay.io/acme", "empty": "", "eq": "a=b"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	if values, err := ParseValues(nil); err != nil || values != nil {
+		t.Errorf("expected no values, got %v, %v", values, err)
+	}
+
+	for _, pair := range []string{"team", "=platform", "team-name=platform", "1team=platform"} {
+		if _, err := ParseValues([]string{pair}); err == nil {
+			t.Errorf("expected %q to be invalid", pair)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
+)
+
+// DevEnvironment is the environment whose manager logs at the debug level
+const DevEnvironment = "dev"
+
+// Environments are the environments a kustomize overlay of config/default is scaffolded for
+type Environments []string
+
+// BindFlags binds the environments to fs
+func (e *Environments) BindFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar((*[]string)(e), "environments", nil, "environments to scaffold a kustomize overlay "+
+		"of config/default and a deploy-<environment> Makefile target for, e.g. dev,staging,prod")
+}
+
+// Validate checks that the environments are distinct and that their namespaces are valid
+func (e Environments) Validate(projectName string) error {
+	seen := make(map[string]bool, len(e))
+	for _, env := range e {
+		if err := validation.IsDNS1123Label(env); err != nil {
+			return fmt.Errorf("environment (%s) is invalid: %v", env, err)
+		}
+		if err := validation.IsDNS1123Label(Namespace(projectName, env)); err != nil {
+			return fmt.Errorf("namespace (%s) of the environment %s is invalid: %v", Namespace(projectName, env), env, err)
+		}
+		if seen[env] {
+			return fmt.Errorf("environment %s is set twice", env)
+		}
+		seen[env] = true
+	}
+	return nil
+}
+
+// Namespace returns the namespace the overlay of env deploys the project named projectName into
+func Namespace(projectName, env string) string {
+	return projectName + "-" + env
+}
+
+// LogLevel returns the log level of the manager in env
+func LogLevel(env string) string {
+	if env == DevEnvironment {
+		return "debug"
+	}
+	return "info"
+}
+
+// DeployTargets returns the Makefile targets deploying the overlay of each environment
+func (e Environments) DeployTargets() string {
+	var targets strings.Builder
+	for _, env := range e {
+		_, _ = fmt.Fprintf(&targets, `
+# Deploy controller in the %[1]s environment, with the overlay of config/overlays/%[1]s
+deploy-%[1]s: manifests kustomize
+	$(KUSTOMIZE) build config/overlays/%[1]s | kubectl apply -f -
+`, env)
+	}
+	return targets.String()
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package options contains the settings shared by the kustomize plugins
+package options
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/pflag"
+)
+
+// Default settings of the manager Deployment
+const (
+	DefaultReplicas      = 1
+	DefaultCPURequest    = "100m"
+	DefaultMemoryRequest = "20Mi"
+	DefaultCPULimit      = "100m"
+	DefaultMemoryLimit   = "30Mi"
+
+	// HighAvailabilityReplicas is the number of replicas of a highly available manager
+	HighAvailabilityReplicas = 2
+)
+
+// quantityRegexp matches the usual forms of a Kubernetes resource quantity, e.g. 100m, 0.5 or 64Mi
+var quantityRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(m|k|M|G|T|P|E|Ki|Mi|Gi|Ti|Pi|Ei)?$`)
+
+// Manager are the settings of the manager Deployment
+type Manager struct {
+	// Replicas is the number of replicas of the manager, only the leader reconciles
+	Replicas int
+	// CPURequest and MemoryRequest are the resources requested by the manager container
+	CPURequest    string
+	MemoryRequest string
+	// CPULimit and MemoryLimit are the resource limits of the manager container
+	CPULimit    string
+	MemoryLimit string
+	// PodDisruptionBudget is true if a PodDisruptionBudget keeps a replica of the manager available
+	PodDisruptionBudget bool
+	// TopologySpread is true if the replicas of the manager are spread across the nodes and zones of the cluster
+	TopologySpread bool
+
+	replicasFlag *pflag.Flag
+}
+
+// BindFlags binds the manager settings to fs
+func (m *Manager) BindFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&m.Replicas, "manager-replicas", DefaultReplicas, "number of replicas of the manager")
+	m.replicasFlag = fs.Lookup("manager-replicas")
+	fs.StringVar(&m.CPURequest, "manager-cpu-request", DefaultCPURequest, "CPU requested by the manager container")
+	fs.StringVar(&m.MemoryRequest, "manager-memory-request", DefaultMemoryRequest,
+		"memory requested by the manager container")
+	fs.StringVar(&m.CPULimit, "manager-cpu-limit", DefaultCPULimit, "CPU limit of the manager container")
+	fs.StringVar(&m.MemoryLimit, "manager-memory-limit", DefaultMemoryLimit, "memory limit of the manager container")
+	fs.BoolVar(&m.PodDisruptionBudget, "manager-pdb", false,
+		"scaffold a PodDisruptionBudget keeping a replica of the manager available, requires --manager-replicas > 1")
+	fs.BoolVar(&m.TopologySpread, "manager-topology-spread", false,
+		"spread the replicas of the manager across the nodes and zones of the cluster with topologySpreadConstraints")
+}
+
+// WithHighAvailability returns the settings with the PodDisruptionBudget and the topology spread of a highly
+// available manager, and its replicas unless they were set with --manager-replicas
+func (m Manager) WithHighAvailability() Manager {
+	if m.replicasFlag == nil || !m.replicasFlag.Changed {
+		m.Replicas = HighAvailabilityReplicas
+	}
+	m.PodDisruptionBudget = true
+	m.TopologySpread = true
+	return m
+}
+
+// WithDefaults returns the settings with the unset ones replaced by their default
+func (m Manager) WithDefaults() Manager {
+	if m.Replicas == 0 {
+		m.Replicas = DefaultReplicas
+	}
+	if m.CPURequest == "" {
+		m.CPURequest = DefaultCPURequest
+	}
+	if m.MemoryRequest == "" {
+		m.MemoryRequest = DefaultMemoryRequest
+	}
+	if m.CPULimit == "" {
+		m.CPULimit = DefaultCPULimit
+	}
+	if m.MemoryLimit == "" {
+		m.MemoryLimit = DefaultMemoryLimit
+	}
+	return m
+}
+
+// Validate checks that the settings make a valid and schedulable manager Deployment
+func (m Manager) Validate() error {
+	if m.Replicas < 1 {
+		return fmt.Errorf("manager replicas (%d) must be at least 1", m.Replicas)
+	}
+	if m.PodDisruptionBudget && m.Replicas < 2 {
+		return errors.New("a PodDisruptionBudget requires at least 2 manager replicas, " +
+			"otherwise it prevents the node of the manager from being drained")
+	}
+	for _, q := range []struct{ name, value string }{
+		{"CPU request", m.CPURequest},
+		{"memory request", m.MemoryRequest},
+		{"CPU limit", m.CPULimit},
+		{"memory limit", m.MemoryLimit},
+	} {
+		if !quantityRegexp.MatchString(q.value) {
+			return fmt.Errorf("manager %s (%s) is not a valid quantity", q.name, q.value)
+		}
+	}
+	return nil
+}
@@ -44,7 +44,7 @@ func (f *EnableCAInjectionPatch) SetTemplateDefaults() error {
 
 const enableCAInjectionPatchTemplate = `# The following patch adds a directive for certmanager to inject CA into the CRD
 # CRD conversion requires k8s 1.13 or later.
-apiVersion: apiextensions.k8s.io/v1beta1
+apiVersion: apiextensions.k8s.io/{{ if eq .Resource.CRDVersion "v1" }}v1{{ else }}v1beta1{{ end }}
 kind: CustomResourceDefinition
 metadata:
   annotations:
@@ -44,6 +44,23 @@ func (f *EnableWebhookPatch) SetTemplateDefaults() error {
 
 const enableWebhookPatchTemplate = `# The following patch enables conversion webhook for CRD
 # CRD conversion requires k8s 1.13 or later.
+{{ if eq .Resource.CRDVersion "v1" -}}
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: {{ .Resource.Plural }}.{{ .Resource.Domain }}
+spec:
+  conversion:
+    strategy: Webhook
+    webhook:
+      clientConfig:
+        service:
+          namespace: system
+          name: webhook-service
+          path: /convert
+      conversionReviewVersions:
+      - v1beta1
+{{ else -}}
 apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
@@ -59,4 +76,5 @@ spec:
         namespace: system
         name: webhook-service
         path: /convert
+{{ end -}}
 `
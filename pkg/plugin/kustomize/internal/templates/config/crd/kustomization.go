@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
 )
 
 var _ file.Template = &Kustomization{}
@@ -72,6 +73,16 @@ const (
 `
 )
 
+// KustomizationFragments returns the code fragments of the kustomization file for res, so that they can also be
+// removed when the resource is deleted
+func KustomizationFragments(res *resource.Resource) []string {
+	return []string{
+		fmt.Sprintf(resourceCodeFragment, res.Domain, res.Plural),
+		fmt.Sprintf(webhookPatchCodeFragment, res.Plural),
+		fmt.Sprintf(caInjectionPatchCodeFragment, res.Plural),
+	}
+}
+
 // GetCodeFragments implements file.Inserter
 func (f *Kustomization) GetCodeFragments() file.CodeFragmentsMap {
 	fragments := make(file.CodeFragmentsMap, 3)
@@ -27,6 +27,7 @@ var _ file.Template = &KustomizeConfig{}
 // KustomizeConfig scaffolds the kustomizeconfig file in crd folder.
 type KustomizeConfig struct {
 	file.TemplateMixin
+	file.ResourceMixin
 }
 
 // SetTemplateDefaults implements input.Template
@@ -48,12 +49,12 @@ nameReference:
   fieldSpecs:
   - kind: CustomResourceDefinition
     group: apiextensions.k8s.io
-    path: spec/conversion/webhookClientConfig/service/name
+    path: spec/conversion/{{ if eq .Resource.CRDVersion "v1" }}webhook/clientConfig{{ else }}webhookClientConfig{{ end }}/service/name
 
 namespace:
 - kind: CustomResourceDefinition
   group: apiextensions.k8s.io
-  path: spec/conversion/webhookClientConfig/service/namespace
+  path: spec/conversion/{{ if eq .Resource.CRDVersion "v1" }}webhook/clientConfig{{ else }}webhookClientConfig{{ end }}/service/namespace
   create: false
 
 varReference:
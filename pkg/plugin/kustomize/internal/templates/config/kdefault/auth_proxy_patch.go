@@ -28,6 +28,11 @@ var _ file.Template = &AuthProxyPatch{}
 // prometheus metrics for manager Pod.
 type AuthProxyPatch struct {
 	file.TemplateMixin
+
+	// RestrictedPodSecurity determines whether the proxy complies with the "restricted" Pod Security Standard
+	RestrictedPodSecurity bool
+	// ComponentConfig determines whether the metrics address is set in the manager config file instead of a flag
+	ComponentConfig bool
 }
 
 // SetTemplateDefaults implements input.Template
@@ -64,8 +69,18 @@ spec:
         ports:
         - containerPort: 8443
           name: https
+{{- if .RestrictedPodSecurity }}
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+          readOnlyRootFilesystem: true
+{{- end }}
+{{- if not .ComponentConfig }}
       - name: manager
         args:
         - "--metrics-addr=127.0.0.1:8080"
         - "--enable-leader-election"
+{{- end }}
 `
@@ -27,7 +27,16 @@ var _ file.Template = &Kustomize{}
 // Kustomize scaffolds the Kustomization file for the default overlay
 type Kustomize struct {
 	file.TemplateMixin
-	file.ProjectNameMixin
+
+	// Namespace is the namespace all resources are deployed into
+	Namespace string
+	// NamePrefix is prepended to the names of all resources
+	NamePrefix string
+
+	// NetworkPolicy determines whether the network-policy manifests are added to the default overlay
+	NetworkPolicy bool
+	// MetricsAuthFilter determines whether the manager protects the metrics endpoint itself, without the auth proxy
+	MetricsAuthFilter bool
 }
 
 // SetTemplateDefaults implements input.Template
@@ -44,14 +53,14 @@ func (f *Kustomize) SetTemplateDefaults() error {
 }
 
 const kustomizeTemplate = `# Adds namespace to all resources.
-namespace: {{ .ProjectName }}-system
+namespace: {{ .Namespace }}
 
 # Value of this field is prepended to the
 # names of all resources, e.g. a deployment named
 # "wordpress" becomes "alices-wordpress".
 # Note that it should also match with the prefix (text before '-') of the namespace
 # field above.
-namePrefix: {{ .ProjectName }}-
+namePrefix: {{ .NamePrefix }}
 
 # Labels to add to all resources and selectors.
 #commonLabels:
@@ -68,12 +77,22 @@ bases:
 #- ../certmanager
 # [PROMETHEUS] To enable prometheus monitor, uncomment all sections with 'PROMETHEUS'.
 #- ../prometheus
+{{- if .NetworkPolicy }}
+# [NETWORK POLICY] Restricts the manager ingress to the metrics and webhook ports.
+- ../network-policy
+{{- end }}
 
 patchesStrategicMerge:
+{{- if .MetricsAuthFilter }}
+  # The manager protects the /metrics endpoint itself, serving it over HTTPS
+  # to the clients allowed to get it only.
+- manager_metrics_patch.yaml
+{{- else }}
   # Protect the /metrics endpoint by putting it behind auth.
   # If you want your controller-manager to expose the /metrics
   # endpoint w/o any authn/z, please comment the following line.
 - manager_auth_proxy_patch.yaml
+{{- end }}
 
 # [WEBHOOK] To enable webhook, uncomment all the sections with [WEBHOOK] prefix including the one in
 # crd/kustomization.yaml
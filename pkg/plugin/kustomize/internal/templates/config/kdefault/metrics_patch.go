@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kdefault
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &MetricsPatch{}
+
+// MetricsPatch scaffolds the patch file exposing the metrics endpoint the manager Pod
+// serves over HTTPS itself, used instead of the AuthProxyPatch.
+type MetricsPatch struct {
+	file.TemplateMixin
+
+	// ComponentConfig determines whether the metrics address is set in the manager config file instead of a flag
+	ComponentConfig bool
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *MetricsPatch) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "default", "manager_metrics_patch.yaml")
+	}
+
+	f.TemplateBody = kustomizeMetricsPatchTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const kustomizeMetricsPatchTemplate = `# This patch exposes the metrics endpoint the controller manager
+# serves over HTTPS on the port 8443, authenticating the requests with
+# TokenReviews and authorizing them using SubjectAccessReviews.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+{{- if not .ComponentConfig }}
+        args:
+        - "--metrics-addr=:8443"
+        - "--enable-leader-election"
+{{- end }}
+        ports:
+        - containerPort: 8443
+          name: https
+`
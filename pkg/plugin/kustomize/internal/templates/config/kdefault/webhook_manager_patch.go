@@ -27,6 +27,11 @@ var _ file.Template = &ManagerWebhookPatch{}
 // ManagerWebhookPatch scaffolds a ManagerWebhookPatch for a Resource
 type ManagerWebhookPatch struct {
 	file.TemplateMixin
+
+	// Port is the port the webhook server of the manager listens on
+	Port int
+	// CertDir is the directory the webhook server of the manager reads its certificate from
+	CertDir string
 }
 
 // SetTemplateDefaults implements input.Template
@@ -51,11 +56,11 @@ spec:
       containers:
       - name: manager
         ports:
-        - containerPort: 9443
+        - containerPort: {{ .Port }}
           name: webhook-server
           protocol: TCP
         volumeMounts:
-        - mountPath: /tmp/k8s-webhook-server/serving-certs
+        - mountPath: {{ .CertDir }}
           name: cert
           readOnly: true
       volumes:
@@ -0,0 +1,200 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/options"
+)
+
+var _ file.Template = &Config{}
+
+// Config scaffolds yaml config for the manager.
+type Config struct {
+	file.TemplateMixin
+	file.ProjectNameMixin
+
+	// Image is controller manager image name
+	Image string
+
+	// RestrictedPodSecurity determines whether the manager complies with the "restricted" Pod Security Standard
+	RestrictedPodSecurity bool
+
+	// ComponentConfig determines whether the manager loads its options from the mounted manager-config ConfigMap
+	ComponentConfig bool
+
+	// Pprof determines whether the pprof endpoint of the manager is disabled by its --pprof-addr flag
+	Pprof bool
+
+	// Tracing determines whether the OpenTelemetry environment variables of the manager are set
+	Tracing bool
+
+	// ZapFlags determines whether the logger of the manager is configured by its --zap-* flags
+	ZapFlags bool
+
+	// NamespaceScoped determines whether the manager only watches the namespaces of its WATCH_NAMESPACE variable
+	NamespaceScoped bool
+	// WatchNamespaces are the namespaces of the WATCH_NAMESPACE variable of a manager that is not namespace-scoped
+	WatchNamespaces []string
+
+	// Manager are the replicas and resources of the manager
+	Manager options.Manager
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Config) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "manager", "manager.yaml")
+	}
+
+	f.TemplateBody = configTemplate
+
+	f.Manager = f.Manager.WithDefaults()
+
+	return nil
+}
+
+const configTemplate = `apiVersion: v1
+kind: Namespace
+metadata:
+  labels:
+    control-plane: controller-manager
+  name: system
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+  labels:
+    control-plane: controller-manager
+    app.kubernetes.io/name: {{ .ProjectName }}
+spec:
+  selector:
+    matchLabels:
+      control-plane: controller-manager
+  replicas: {{ .Manager.Replicas }}
+  template:
+    metadata:
+      labels:
+        control-plane: controller-manager
+        app.kubernetes.io/name: {{ .ProjectName }}
+    spec:
+      securityContext:
+        runAsUser: 65532
+{{- if .RestrictedPodSecurity }}
+        runAsNonRoot: true
+        seccompProfile:
+          type: RuntimeDefault
+{{- end }}
+      containers:
+      - command:
+        - /manager
+        args:
+{{- if .ComponentConfig }}
+        - --config=/controller_manager_config.yaml
+{{- else }}
+        - --enable-leader-election
+{{- end }}
+{{- if .Pprof }}
+        # Set to an address, e.g. :8082, to serve the runtime profiles, only with a port-forward to the manager
+        - --pprof-addr=0
+{{- end }}
+{{- if .ZapFlags }}
+        # Set --zap-log-level to debug, or to a number for the more verbose levels, to troubleshoot the manager
+        - --zap-devel=false
+        - --zap-encoder=json
+        - --zap-log-level=info
+        - --zap-stacktrace-level=error
+{{- end }}
+{{- if or .Tracing .NamespaceScoped .WatchNamespaces }}
+        env:
+{{- end }}
+{{- if .NamespaceScoped }}
+        # The comma-separated namespaces the manager watches, its own one by default. The manager-role Role that
+        # controller-gen generates from the RBAC markers of the controllers, scoped with namespace=system, only
+        # grants it access to its own namespace: every other namespace added here needs a Role and a RoleBinding
+        # of its own. Watching all the namespaces with "" requires cluster-wide markers and a ClusterRoleBinding
+        # in config/rbac/role_binding.yaml instead.
+        - name: WATCH_NAMESPACE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.namespace
+{{- else if .WatchNamespaces }}
+        # The comma-separated namespaces the manager watches, all of them if empty. The manager-role ClusterRole
+        # still grants it access to the resources of every namespace.
+        - name: WATCH_NAMESPACE
+          value: {{ range $i, $namespace := .WatchNamespaces }}{{ if $i }},{{ end }}{{ $namespace }}{{ end }}
+{{- end }}
+{{- if .Tracing }}
+        # The host:port address of the OTLP gRPC endpoint the traces are exported to, e.g. the one of an
+        # OpenTelemetry Collector, none are exported if empty
+        - name: OTEL_EXPORTER_OTLP_ENDPOINT
+          value: ""
+        - name: OTEL_SERVICE_NAME
+          value: {{ .ProjectName }}
+{{- end }}
+        image: {{ .Image }}
+        name: manager
+        securityContext:
+          allowPrivilegeEscalation: false
+{{- if .RestrictedPodSecurity }}
+          capabilities:
+            drop:
+            - ALL
+          readOnlyRootFilesystem: true
+{{- end }}
+        resources:
+          limits:
+            cpu: {{ .Manager.CPULimit }}
+            memory: {{ .Manager.MemoryLimit }}
+          requests:
+            cpu: {{ .Manager.CPURequest }}
+            memory: {{ .Manager.MemoryRequest }}
+{{- if .ComponentConfig }}
+        volumeMounts:
+        - name: manager-config
+          mountPath: /controller_manager_config.yaml
+          subPath: controller_manager_config.yaml
+{{- end }}
+      terminationGracePeriodSeconds: 10
+{{- if .Manager.TopologySpread }}
+      # The replicas are spread across the nodes and the zones, so that one failing does not take all of them
+      # down. Set whenUnsatisfiable to DoNotSchedule to enforce it, once the cluster has enough of them.
+      topologySpreadConstraints:
+      - maxSkew: 1
+        topologyKey: kubernetes.io/hostname
+        whenUnsatisfiable: ScheduleAnyway
+        labelSelector:
+          matchLabels:
+            control-plane: controller-manager
+      - maxSkew: 1
+        topologyKey: topology.kubernetes.io/zone
+        whenUnsatisfiable: ScheduleAnyway
+        labelSelector:
+          matchLabels:
+            control-plane: controller-manager
+{{- end }}
+{{- if .ComponentConfig }}
+      volumes:
+      - name: manager-config
+        configMap:
+          name: manager-config
+{{- end }}
+`
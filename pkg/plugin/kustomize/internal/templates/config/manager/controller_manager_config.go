@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &ControllerManagerConfig{}
+
+// ControllerManagerConfig scaffolds the ControllerManagerConfig file the manager loads its options from.
+type ControllerManagerConfig struct {
+	file.TemplateMixin
+
+	// MetricsAuthFilter determines whether the manager serves its metrics over HTTPS itself
+	MetricsAuthFilter bool
+
+	// WebhookPort, WebhookHost and WebhookCertDir configure the webhook server, the defaults are used if empty
+	WebhookPort    int
+	WebhookHost    string
+	WebhookCertDir string
+
+	// HighAvailability determines whether the leader election timings tolerate an unavailability of the API server
+	HighAvailability bool
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *ControllerManagerConfig) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "manager", "controller_manager_config.yaml")
+	}
+
+	f.TemplateBody = controllerManagerConfigTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const controllerManagerConfigTemplate = `# Options of the manager, mounted from the manager-config ConfigMap
+# and loaded with its --config flag.
+apiVersion: controller-runtime.sigs.k8s.io/v1alpha1
+kind: ControllerManagerConfig
+metrics:
+{{- if .MetricsAuthFilter }}
+  bindAddress: :8443
+{{- else }}
+  bindAddress: 127.0.0.1:8080
+{{- end }}
+webhook:
+  port: {{ .WebhookPort }}
+{{- if .WebhookHost }}
+  host: {{ printf "%q" .WebhookHost }}
+{{- end }}
+{{- if .WebhookCertDir }}
+  certDir: {{ .WebhookCertDir }}
+{{- end }}
+leaderElection:
+  leaderElect: true
+{{- if .HighAvailability }}
+  # Longer than the defaults of 15s, 10s and 2s, so that an API server unavailable for less than renewDeadline,
+  # e.g. while it is upgraded, does not make the leader exit
+  leaseDuration: 137s
+  renewDeadline: 107s
+  retryPeriod: 26s
+{{- end }}
+`
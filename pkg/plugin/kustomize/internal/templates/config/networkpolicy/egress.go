@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkpolicy
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &AllowEgressTraffic{}
+
+// AllowEgressTraffic scaffolds a NetworkPolicy that restricts the egress traffic of the manager
+type AllowEgressTraffic struct {
+	file.TemplateMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *AllowEgressTraffic) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "network-policy", "allow-egress-traffic.yaml")
+	}
+
+	f.TemplateBody = allowEgressTrafficTemplate
+
+	return nil
+}
+
+const allowEgressTrafficTemplate = `# This NetworkPolicy restricts the egress traffic of the manager to DNS
+# resolution and the API server. Add rules here if your controllers need
+# to reach other services.
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  labels:
+    control-plane: controller-manager
+  name: allow-egress-traffic
+  namespace: system
+spec:
+  podSelector:
+    matchLabels:
+      control-plane: controller-manager
+  policyTypes:
+  - Egress
+  egress:
+  # DNS
+  - ports:
+    - port: 53
+      protocol: UDP
+    - port: 53
+      protocol: TCP
+  # API server (the kubernetes service and its endpoints)
+  - ports:
+    - port: 443
+      protocol: TCP
+    - port: 6443
+      protocol: TCP
+`
@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package manager
+package networkpolicy
 
 import (
 	"path/filepath"
@@ -24,7 +24,7 @@ import (
 
 var _ file.Template = &Kustomization{}
 
-// Kustomization scaffolds the Kustomization file in manager folder.
+// Kustomization scaffolds the kustomization in the network-policy folder
 type Kustomization struct {
 	file.TemplateMixin
 }
@@ -32,16 +32,16 @@ type Kustomization struct {
 // SetTemplateDefaults implements input.Template
 func (f *Kustomization) SetTemplateDefaults() error {
 	if f.Path == "" {
-		f.Path = filepath.Join("config", "manager", "kustomization.yaml")
+		f.Path = filepath.Join("config", "network-policy", "kustomization.yaml")
 	}
 
-	f.TemplateBody = kustomizeManagerTemplate
-
-	f.IfExistsAction = file.Error
+	f.TemplateBody = kustomizationTemplate
 
 	return nil
 }
 
-const kustomizeManagerTemplate = `resources:
-- manager.yaml
+const kustomizationTemplate = `resources:
+- allow-metrics-traffic.yaml
+- allow-webhook-traffic.yaml
+- allow-egress-traffic.yaml
 `
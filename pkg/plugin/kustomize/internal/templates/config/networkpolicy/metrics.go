@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkpolicy
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &AllowMetricsTraffic{}
+
+// AllowMetricsTraffic scaffolds a NetworkPolicy that only allows metrics scraping from labeled namespaces
+type AllowMetricsTraffic struct {
+	file.TemplateMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *AllowMetricsTraffic) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "network-policy", "allow-metrics-traffic.yaml")
+	}
+
+	f.TemplateBody = allowMetricsTrafficTemplate
+
+	return nil
+}
+
+const allowMetricsTrafficTemplate = `# This NetworkPolicy allows ingress traffic to the metrics endpoint
+# of the manager only from namespaces labeled with 'metrics: enabled'.
+# Label the namespace of your Prometheus instance accordingly.
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  labels:
+    control-plane: controller-manager
+  name: allow-metrics-traffic
+  namespace: system
+spec:
+  podSelector:
+    matchLabels:
+      control-plane: controller-manager
+  policyTypes:
+  - Ingress
+  ingress:
+  - from:
+    - namespaceSelector:
+        matchLabels:
+          metrics: enabled
+    ports:
+    - port: 8443
+      protocol: TCP
+`
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkpolicy
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &AllowWebhookTraffic{}
+
+// AllowWebhookTraffic scaffolds a NetworkPolicy that allows the API server to reach the webhook server
+type AllowWebhookTraffic struct {
+	file.TemplateMixin
+
+	// Port is the port the webhook server of the manager listens on
+	Port int
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *AllowWebhookTraffic) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "network-policy", "allow-webhook-traffic.yaml")
+	}
+
+	f.TemplateBody = allowWebhookTrafficTemplate
+
+	return nil
+}
+
+const allowWebhookTrafficTemplate = `# This NetworkPolicy allows ingress traffic to the webhook server of the manager.
+# The API server calling the webhooks usually runs outside of the pod network,
+# so traffic is allowed from any source, but only on the webhook port.
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  labels:
+    control-plane: controller-manager
+  name: allow-webhook-traffic
+  namespace: system
+spec:
+  podSelector:
+    matchLabels:
+      control-plane: controller-manager
+  policyTypes:
+  - Ingress
+  ingress:
+  - ports:
+    - port: {{ .Port }}
+      protocol: TCP
+`
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlay
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Kustomization{}
+
+// Kustomization scaffolds the kustomization of the overlay of config/default of an environment
+type Kustomization struct {
+	file.TemplateMixin
+
+	// Environment is the name of the environment
+	Environment string
+	// Namespace is the namespace the overlay deploys the project into
+	Namespace string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Kustomization) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "overlays", f.Environment, "kustomization.yaml")
+	}
+
+	f.TemplateBody = kustomizationTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const kustomizationTemplate = `# Deploys the manifests of config/default into the {{ .Environment }} environment,
+# with 'make deploy-{{ .Environment }}'.
+namespace: {{ .Namespace }}
+
+bases:
+- ../../default
+
+patchesStrategicMerge:
+# Patches the manager Deployment for this environment.
+- manager_patch.yaml
+`
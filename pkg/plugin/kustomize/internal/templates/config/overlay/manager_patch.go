@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlay
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &ManagerPatch{}
+
+// ManagerPatch scaffolds the patch of the manager Deployment in the overlay of an environment
+type ManagerPatch struct {
+	file.TemplateMixin
+
+	// Environment is the name of the environment
+	Environment string
+	// Image is the image of the manager in the environment
+	Image string
+	// Replicas is the number of replicas of the manager in the environment
+	Replicas int
+	// LogLevel is the log level of the manager in the environment, set if ZapFlags is
+	LogLevel string
+
+	// ZapFlags determines whether the logger of the manager is configured by its --zap-* flags
+	ZapFlags bool
+	// ComponentConfig, Pprof and MetricsAuthFilter determine the arguments of the manager in config/default,
+	// which the patch replaces to set the log level
+	ComponentConfig   bool
+	Pprof             bool
+	MetricsAuthFilter bool
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *ManagerPatch) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "overlays", f.Environment, "manager_patch.yaml")
+	}
+
+	f.TemplateBody = managerPatchTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const managerPatchTemplate = `# Sets the image{{ if .ZapFlags }}, the replicas and the log level{{ else }} and the replicas{{ end }} of the manager in the
+# {{ .Environment }} environment.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller-manager
+  namespace: system
+spec:
+  replicas: {{ .Replicas }}
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: {{ .Image }}
+{{- if .ZapFlags }}
+        # The arguments replace the ones of config/default, only to set the log level
+        args:
+{{- if .ComponentConfig }}
+        - --config=/controller_manager_config.yaml
+{{- if .Pprof }}
+        - --pprof-addr=0
+{{- end }}
+        - --zap-devel=false
+        - --zap-encoder=json
+        - --zap-stacktrace-level=error
+{{- else if .MetricsAuthFilter }}
+        - "--metrics-addr=:8443"
+        - "--enable-leader-election"
+{{- else }}
+        - "--metrics-addr=127.0.0.1:8080"
+        - "--enable-leader-election"
+{{- end }}
+        - --zap-log-level={{ .LogLevel }}
+{{- end }}
+`
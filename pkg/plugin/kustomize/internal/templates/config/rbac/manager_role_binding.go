@@ -27,6 +27,10 @@ var _ file.Template = &ManagerRoleBinding{}
 // ManagerRoleBinding scaffolds the config/rbac/role_binding.yaml file
 type ManagerRoleBinding struct {
 	file.TemplateMixin
+
+	// NamespaceScoped binds the manager-role Role of the namespace of the manager instead of a ClusterRole, the
+	// manager only watching its namespace
+	NamespaceScoped bool
 }
 
 // SetTemplateDefaults implements input.Template
@@ -41,6 +45,16 @@ func (f *ManagerRoleBinding) SetTemplateDefaults() error {
 }
 
 const managerBindingTemplate = `apiVersion: rbac.authorization.k8s.io/v1
+{{- if .NamespaceScoped }}
+kind: RoleBinding
+metadata:
+  name: manager-rolebinding
+  namespace: system
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: manager-role
+{{- else }}
 kind: ClusterRoleBinding
 metadata:
   name: manager-rolebinding
@@ -48,6 +62,7 @@ roleRef:
   apiGroup: rbac.authorization.k8s.io
   kind: ClusterRole
   name: manager-role
+{{- end }}
 subjects:
 - kind: ServiceAccount
   name: default
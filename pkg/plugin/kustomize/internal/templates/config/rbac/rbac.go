@@ -27,6 +27,9 @@ var _ file.Template = &KustomizeRBAC{}
 // KustomizeRBAC scaffolds the Kustomization file in rbac folder.
 type KustomizeRBAC struct {
 	file.TemplateMixin
+
+	// MetricsAuthFilter determines whether the manager, instead of the auth proxy, protects the metrics endpoint
+	MetricsAuthFilter bool
 }
 
 // SetTemplateDefaults implements input.Template
@@ -47,9 +50,15 @@ const kustomizeRBACTemplate = `resources:
 - role_binding.yaml
 - leader_election_role.yaml
 - leader_election_role_binding.yaml
+{{- if .MetricsAuthFilter }}
+# The manager protects the /metrics endpoint itself, the proxy role
+# allows it to create TokenReviews and SubjectAccessReviews and the
+# clients scraping the metrics need the metrics reader role.
+{{- else }}
 # Comment the following 4 lines if you want to disable
 # the auth proxy (https://github.com/brancz/kube-rbac-proxy)
 # which protects your /metrics endpoint.
+{{- end }}
 - auth_proxy_service.yaml
 - auth_proxy_role.yaml
 - auth_proxy_role_binding.yaml
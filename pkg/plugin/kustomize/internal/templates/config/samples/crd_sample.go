@@ -28,6 +28,9 @@ var _ file.Template = &CRDSample{}
 type CRDSample struct {
 	file.TemplateMixin
 	file.ResourceMixin
+
+	// Spec are the YAML lines of the example values of the Spec fields, derived from the Go types of the resource
+	Spec string
 }
 
 // SetTemplateDefaults implements input.Template
@@ -37,7 +40,8 @@ func (f *CRDSample) SetTemplateDefaults() error {
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
 
-	f.IfExistsAction = file.Error
+	// A base plugin may have already written a sample tailored to its API
+	f.IfExistsAction = file.Skip
 
 	f.TemplateBody = crdSampleTemplate
 
@@ -49,6 +53,11 @@ kind: {{ .Resource.Kind }}
 metadata:
   name: {{ lower .Resource.Kind }}-sample
 spec:
+{{- if .Spec }}
+  # TODO(user): edit the example values of the fields, derived from the Go types of the resource
+{{ .Spec }}
+{{- else }}
   # Add fields here
   foo: bar
+{{- end }}
 `
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package samples
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+)
+
+var _ file.Template = &Kustomization{}
+var _ file.Inserter = &Kustomization{}
+
+// Kustomization scaffolds the kustomization file listing the samples of the resources
+type Kustomization struct {
+	file.TemplateMixin
+	file.ResourceMixin
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *Kustomization) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "samples", "kustomization.yaml")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+
+	f.TemplateBody = fmt.Sprintf(kustomizationTemplate, file.NewMarkerFor(f.Path, sampleMarker))
+
+	return nil
+}
+
+const sampleMarker = "manifestskustomizesamples"
+
+// GetMarkers implements file.Inserter
+func (f *Kustomization) GetMarkers() []file.Marker {
+	return []file.Marker{file.NewMarkerFor(f.Path, sampleMarker)}
+}
+
+const sampleCodeFragment = `- %s
+`
+
+// KustomizationFragments returns the code fragments of the kustomization file for res, so that they can also be
+// removed when the resource is deleted
+func KustomizationFragments(res *resource.Resource) []string {
+	return []string{fmt.Sprintf(sampleCodeFragment, res.Replacer().Replace("%[group]_%[version]_%[kind].yaml"))}
+}
+
+// GetCodeFragments implements file.Inserter
+func (f *Kustomization) GetCodeFragments() file.CodeFragmentsMap {
+	return file.CodeFragmentsMap{
+		file.NewMarkerFor(f.Path, sampleMarker): KustomizationFragments(f.Resource),
+	}
+}
+
+const kustomizationTemplate = `# Samples of the resources, created with 'kustomize build config/samples | kubectl apply -f -'
+resources:
+%s
+`
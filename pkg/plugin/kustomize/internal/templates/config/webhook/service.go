@@ -27,6 +27,9 @@ var _ file.Template = &Service{}
 // Service scaffolds the Service file in manager folder.
 type Service struct {
 	file.TemplateMixin
+
+	// Port is the port the webhook server of the manager listens on
+	Port int
 }
 
 // SetTemplateDefaults implements input.Template
@@ -51,7 +54,7 @@ metadata:
 spec:
   ports:
     - port: 443
-      targetPort: 9443
+      targetPort: {{ .Port }}
   selector:
     control-plane: controller-manager
 `
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v1/scaffolds"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+type createAPISubcommand struct {
+	config *config.Config
+
+	// existingResources are the resources that the project had before the plugin chain ran
+	existingResources []config.GVK
+}
+
+var (
+	_ plugin.CreateAPI   = &createAPISubcommand{}
+	_ cmdutil.RunOptions = &createAPISubcommand{}
+)
+
+func (p *createAPISubcommand) UpdateContext(ctx *plugin.Context) {
+	ctx.Description += `
+The kustomize manifests of a new resource (CRD patches, sample and RBAC roles) are written under config/.
+`
+}
+
+func (p *createAPISubcommand) BindFlags(*pflag.FlagSet) {}
+
+func (p *createAPISubcommand) InjectConfig(c *config.Config) {
+	p.config = c
+	p.existingResources = append([]config.GVK(nil), c.Resources...)
+}
+
+func (p *createAPISubcommand) Scaffold() error {
+	return cmdutil.Scaffold(p)
+}
+
+func (p *createAPISubcommand) Validate() error {
+	return nil
+}
+
+// GetScaffolder scaffolds the manifests of the resources added to the config by the plugins that ran before this
+// one. Nothing is scaffolded if the command only created a controller, as no resource was added.
+func (p *createAPISubcommand) GetScaffolder() (scaffold.Scaffolder, error) {
+	var resources []*resource.Resource
+	for _, gvk := range p.config.Resources {
+		if p.isExisting(gvk) {
+			continue
+		}
+		opts := &resource.Options{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Plural: gvk.Plural,
+			CRDVersion: gvk.CRDVersion}
+		resources = append(resources, opts.NewResource(p.config, true))
+	}
+	if len(resources) == 0 {
+		return nil, nil
+	}
+
+	return scaffolds.NewAPIScaffolder(p.config, resources...), nil
+}
+
+func (p *createAPISubcommand) PostScaffold() error {
+	return nil
+}
+
+// isExisting returns true if gvk was already part of the project before the plugin chain ran.
+func (p *createAPISubcommand) isExisting(gvk config.GVK) bool {
+	for _, existing := range p.existingResources {
+		if existing == gvk {
+			return true
+		}
+	}
+	return false
+}
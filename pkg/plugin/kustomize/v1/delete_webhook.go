@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v1/scaffolds"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+type deleteWebhookSubcommand struct {
+	config *config.Config
+
+	// webhookResources are the resources that had webhooks before the plugin chain ran
+	webhookResources []config.GVK
+}
+
+var (
+	_ plugin.DeleteWebhook = &deleteWebhookSubcommand{}
+	_ cmdutil.RunOptions   = &deleteWebhookSubcommand{}
+)
+
+func (p *deleteWebhookSubcommand) UpdateContext(ctx *plugin.Context) {
+	ctx.Description += `
+The webhooks of the resource are deleted from the webhook configurations under config/webhook/ and, with the
+last version of the Kind, the conversion webhook and CA injection patches of its CRD are commented out again.
+`
+}
+
+func (p *deleteWebhookSubcommand) BindFlags(*pflag.FlagSet) {}
+
+func (p *deleteWebhookSubcommand) InjectConfig(c *config.Config) {
+	p.config = c
+	p.webhookResources = nil
+	for _, gvk := range c.Resources {
+		if gvk.WebhookVersion != "" {
+			p.webhookResources = append(p.webhookResources, gvk)
+		}
+	}
+}
+
+func (p *deleteWebhookSubcommand) Scaffold() error {
+	return cmdutil.Scaffold(p)
+}
+
+func (p *deleteWebhookSubcommand) Validate() error {
+	return nil
+}
+
+// GetScaffolder deletes the webhooks of the resources which webhook version was removed from the config by the
+// plugins that ran before this one.
+func (p *deleteWebhookSubcommand) GetScaffolder() (scaffold.Scaffolder, error) {
+	var resources []*resource.Resource
+	for _, gvk := range p.webhookResources {
+		if p.hasWebhook(gvk) {
+			continue
+		}
+		opts := &resource.Options{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Plural: gvk.Plural}
+		resources = append(resources, opts.NewResource(p.config, false))
+	}
+	if len(resources) == 0 {
+		return nil, nil
+	}
+
+	return scaffolds.NewDeleteWebhookScaffolder(p.config, resources...), nil
+}
+
+// hasWebhook returns true if the config still records a webhook version for gvk.
+func (p *deleteWebhookSubcommand) hasWebhook(gvk config.GVK) bool {
+	for _, r := range p.config.Resources {
+		if r.Group == gvk.Group && r.Version == gvk.Version && r.Kind == gvk.Kind {
+			return r.WebhookVersion != ""
+		}
+	}
+	return false
+}
+
+func (p *deleteWebhookSubcommand) PostScaffold() error {
+	return nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+// KustomizeVersion is the kubernetes-sigs/kustomize version the scaffolded manifests are compatible with
+const KustomizeVersion = "v3.5.4"
+
+const pluginName = "kustomize.common" + plugin.DefaultNameQualifier
+
+var (
+	supportedProjectVersions = []string{config.Version3Alpha}
+	pluginVersion            = plugin.Version{Number: 1}
+)
+
+var (
+	_ plugin.Base                      = Plugin{}
+	_ plugin.InitPluginGetter          = Plugin{}
+	_ plugin.CreateAPIPluginGetter     = Plugin{}
+	_ plugin.CreateWebhookPluginGetter = Plugin{}
+	_ plugin.DeleteAPIPluginGetter     = Plugin{}
+	_ plugin.DeleteWebhookPluginGetter = Plugin{}
+)
+
+// Plugin scaffolds the kustomize manifests under config/. It is meant to be chained with a language base
+// plugin, which sets the project name and adds the created resources to the config before this plugin runs.
+type Plugin struct {
+	initSubcommand
+	createAPISubcommand
+	createWebhookSubcommand
+	deleteAPISubcommand
+	deleteWebhookSubcommand
+}
+
+func (Plugin) Name() string                                   { return pluginName }
+func (Plugin) Version() plugin.Version                        { return pluginVersion }
+func (Plugin) SupportedProjectVersions() []string             { return supportedProjectVersions }
+func (p Plugin) GetInitPlugin() plugin.Init                   { return &p.initSubcommand }
+func (p Plugin) GetCreateAPIPlugin() plugin.CreateAPI         { return &p.createAPISubcommand }
+func (p Plugin) GetCreateWebhookPlugin() plugin.CreateWebhook { return &p.createWebhookSubcommand }
+func (p Plugin) GetDeleteAPIPlugin() plugin.DeleteAPI         { return &p.deleteAPISubcommand }
+func (p Plugin) GetDeleteWebhookPlugin() plugin.DeleteWebhook { return &p.deleteWebhookSubcommand }
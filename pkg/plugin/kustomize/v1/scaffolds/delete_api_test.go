@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+)
+
+var _ = Describe("deleteAPIScaffolder", func() {
+	var (
+		cfg          *config.Config
+		v1, v2       *resource.Resource
+		tmpDir       string
+		oldDir       string
+		sharedFiles  []string
+		crdKustomize = filepath.Join("config", "crd", "kustomization.yaml")
+	)
+
+	newResource := func(version string) *resource.Resource {
+		opts := &resource.Options{Group: "crew", Version: version, Kind: "Captain"}
+		return opts.NewResource(cfg, true)
+	}
+
+	BeforeEach(func() {
+		cfg = &config.Config{Version: config.Version3Alpha, Domain: "my.domain", Repo: "example.com/project"}
+		v1, v2 = newResource("v1"), newResource("v2")
+		cfg.Resources = []config.GVK{v1.GVK(), v2.GVK()}
+		sharedFiles = []string{
+			filepath.Join("config", "rbac", "captain_editor_role.yaml"),
+			filepath.Join("config", "rbac", "captain_viewer_role.yaml"),
+			filepath.Join("config", "crd", "patches", "webhook_in_captains.yaml"),
+			filepath.Join("config", "crd", "patches", "cainjection_in_captains.yaml"),
+			filepath.Join("config", "crd", "bases", "crew.my.domain_captains.yaml"),
+		}
+
+		var err error
+		oldDir, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		tmpDir, err = ioutil.TempDir("", "kustomize-scaffolds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		Expect(NewAPIScaffolder(cfg, v1, v2).Scaffold()).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join("config", "crd", "bases"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(sharedFiles[4], []byte("kind: CustomResourceDefinition\n"), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldDir)).To(Succeed())
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("should only delete the sample while another version of the Kind is tracked", func() {
+		cfg.RemoveResource(v1.GVK())
+		Expect(NewDeleteAPIScaffolder(cfg, v1).Scaffold()).To(Succeed())
+
+		Expect(filepath.Join("config", "samples", "crew_v1_captain.yaml")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join("config", "samples", "crew_v2_captain.yaml")).To(BeARegularFile())
+		for _, path := range sharedFiles {
+			Expect(path).To(BeARegularFile())
+		}
+		kustomization, err := ioutil.ReadFile(crdKustomize)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(kustomization)).To(ContainSubstring("- bases/crew.my.domain_captains.yaml\n"))
+		samples, err := ioutil.ReadFile(filepath.Join("config", "samples", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(samples)).NotTo(ContainSubstring("- crew_v1_captain.yaml\n"))
+		Expect(string(samples)).To(ContainSubstring("- crew_v2_captain.yaml\n"))
+	})
+
+	It("should delete the manifests of the Kind with its last version", func() {
+		kustomization, err := ioutil.ReadFile(crdKustomize)
+		Expect(err).NotTo(HaveOccurred())
+		// Enable the webhook patch as users are told to do
+		enabled := []byte("- patches/webhook_in_captains.yaml\n")
+		kustomization = append(kustomization, enabled...)
+		Expect(ioutil.WriteFile(crdKustomize, kustomization, 0644)).To(Succeed())
+
+		cfg.RemoveResource(v1.GVK())
+		cfg.RemoveResource(v2.GVK())
+		Expect(NewDeleteAPIScaffolder(cfg, v1, v2).Scaffold()).To(Succeed())
+
+		Expect(filepath.Join("config", "samples", "crew_v1_captain.yaml")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join("config", "samples", "crew_v2_captain.yaml")).NotTo(BeAnExistingFile())
+		for _, path := range sharedFiles {
+			Expect(path).NotTo(BeAnExistingFile())
+		}
+		kustomization, err = ioutil.ReadFile(crdKustomize)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(kustomization)).NotTo(ContainSubstring("captains"))
+		Expect(string(kustomization)).To(ContainSubstring("# +kubebuilder:scaffold:crdkustomizeresource\n"))
+	})
+})
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+)
+
+const webhookManifests = `
+---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: MutatingWebhookConfiguration
+metadata:
+  creationTimestamp: null
+  name: mutating-webhook-configuration
+webhooks:
+- clientConfig:
+    caBundle: Cg==
+    service:
+      name: webhook-service
+      namespace: system
+      path: /mutate-crew-my-domain-v1-captain
+  failurePolicy: Fail
+  name: mcaptain.kb.io
+- clientConfig:
+    caBundle: Cg==
+    service:
+      name: webhook-service
+      namespace: system
+      path: /mutate-crew-my-domain-v1-admiral
+  failurePolicy: Fail
+  name: madmiral.kb.io
+
+---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  creationTimestamp: null
+  name: validating-webhook-configuration
+webhooks:
+- clientConfig:
+    caBundle: Cg==
+    service:
+      name: webhook-service
+      namespace: system
+      path: /validate-crew-my-domain-v1-captain
+  failurePolicy: Fail
+  name: vcaptain.kb.io
+`
+
+var _ = Describe("deleteWebhookScaffolder", func() {
+	var (
+		cfg          *config.Config
+		captain      *resource.Resource
+		tmpDir       string
+		oldDir       string
+		manifests    = filepath.Join("config", "webhook", "manifests.yaml")
+		crdKustomize = filepath.Join("config", "crd", "kustomization.yaml")
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{Version: config.Version3Alpha, Domain: "my.domain", Repo: "example.com/project"}
+		opts := &resource.Options{Group: "crew", Version: "v1", Kind: "Captain"}
+		captain = opts.NewResource(cfg, true)
+		cfg.Resources = []config.GVK{captain.GVK(), {Group: "crew", Version: "v1", Kind: "Admiral", WebhookVersion: "v1"}}
+
+		var err error
+		oldDir, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		tmpDir, err = ioutil.TempDir("", "kustomize-scaffolds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		Expect(NewAPIScaffolder(cfg, captain).Scaffold()).To(Succeed())
+		Expect(os.MkdirAll(filepath.Dir(manifests), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(manifests, []byte(webhookManifests), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldDir)).To(Succeed())
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("should only delete the webhooks of the resource from the webhook configurations", func() {
+		Expect(NewDeleteWebhookScaffolder(cfg, captain).Scaffold()).To(Succeed())
+
+		content, err := ioutil.ReadFile(manifests)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).NotTo(ContainSubstring("captain"))
+		Expect(string(content)).To(ContainSubstring("path: /mutate-crew-my-domain-v1-admiral\n"))
+		Expect(string(content)).To(HavePrefix("\n---\napiVersion: admissionregistration.k8s.io/v1beta1\n"))
+		Expect(string(content)).To(HaveSuffix("  name: validating-webhook-configuration\nwebhooks: []\n"))
+		Expect(strings.Count(string(content), "\n\n---\n")).To(Equal(1))
+	})
+
+	It("should comment out the CRD patches of the Kind again", func() {
+		kustomization, err := ioutil.ReadFile(crdKustomize)
+		Expect(err).NotTo(HaveOccurred())
+		enabled := strings.Replace(string(kustomization), "#- patches/", "- patches/", -1)
+		Expect(ioutil.WriteFile(crdKustomize, []byte(enabled), 0644)).To(Succeed())
+
+		Expect(NewDeleteWebhookScaffolder(cfg, captain).Scaffold()).To(Succeed())
+
+		kustomization, err = ioutil.ReadFile(crdKustomize)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(kustomization)).To(ContainSubstring("\n#- patches/webhook_in_captains.yaml\n"))
+		Expect(string(kustomization)).To(ContainSubstring("\n#- patches/cainjection_in_captains.yaml\n"))
+	})
+
+	It("should keep the CRD patches while another version of the Kind has webhooks", func() {
+		cfg.Resources = append(cfg.Resources, config.GVK{Group: "crew", Version: "v2", Kind: "Captain",
+			WebhookVersion: "v1"})
+		kustomization, err := ioutil.ReadFile(crdKustomize)
+		Expect(err).NotTo(HaveOccurred())
+		enabled := strings.Replace(string(kustomization), "#- patches/", "- patches/", -1)
+		Expect(ioutil.WriteFile(crdKustomize, []byte(enabled), 0644)).To(Succeed())
+
+		Expect(NewDeleteWebhookScaffolder(cfg, captain).Scaffold()).To(Succeed())
+
+		kustomization, err = ioutil.ReadFile(crdKustomize)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(kustomization)).To(Equal(enabled))
+	})
+})
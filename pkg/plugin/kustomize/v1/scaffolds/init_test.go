@@ -0,0 +1,439 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/options"
+)
+
+// The following types only contain the fields of a Deployment checked by the Pod Security Standards.
+type deployment struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Template struct {
+			Spec podSpec `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+type podSpec struct {
+	HostNetwork     bool             `json:"hostNetwork"`
+	HostPID         bool             `json:"hostPID"`
+	HostIPC         bool             `json:"hostIPC"`
+	SecurityContext *securityContext `json:"securityContext"`
+	Containers      []container      `json:"containers"`
+}
+
+type container struct {
+	Name            string           `json:"name"`
+	SecurityContext *securityContext `json:"securityContext"`
+}
+
+type securityContext struct {
+	Privileged               *bool `json:"privileged"`
+	AllowPrivilegeEscalation *bool `json:"allowPrivilegeEscalation"`
+	RunAsNonRoot             *bool `json:"runAsNonRoot"`
+	RunAsUser                *int  `json:"runAsUser"`
+	ReadOnlyRootFilesystem   *bool `json:"readOnlyRootFilesystem"`
+	SeccompProfile           *struct {
+		Type string `json:"type"`
+	} `json:"seccompProfile"`
+	Capabilities *struct {
+		Add  []string `json:"add"`
+		Drop []string `json:"drop"`
+	} `json:"capabilities"`
+}
+
+// readDeployment returns the pod spec of the first Deployment found in the given manifest
+func readDeployment(path string) podSpec {
+	content, err := ioutil.ReadFile(path)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	for _, doc := range bytes.Split(content, []byte("\n---")) {
+		var d deployment
+		ExpectWithOffset(1, yaml.Unmarshal(doc, &d)).To(Succeed())
+		if d.Kind == "Deployment" {
+			return d.Spec.Template.Spec
+		}
+	}
+	Fail("no Deployment found in " + path)
+	return podSpec{}
+}
+
+// managerPodSpec returns the manager pod spec with the auth proxy patch applied
+func managerPodSpec() podSpec {
+	pod := readDeployment(filepath.Join("config", "manager", "manager.yaml"))
+	patch := readDeployment(filepath.Join("config", "default", "manager_auth_proxy_patch.yaml"))
+
+	for _, c := range patch.Containers {
+		patched := false
+		for i := range pod.Containers {
+			if pod.Containers[i].Name == c.Name {
+				if c.SecurityContext != nil {
+					pod.Containers[i].SecurityContext = c.SecurityContext
+				}
+				patched = true
+			}
+		}
+		if !patched {
+			pod.Containers = append(pod.Containers, c)
+		}
+	}
+
+	return pod
+}
+
+// restrictedViolations returns the checks of the "restricted" Pod Security Standard that the pod spec fails
+func restrictedViolations(pod podSpec) []string {
+	var violations []string
+	if pod.HostNetwork || pod.HostPID || pod.HostIPC {
+		violations = append(violations, "host namespaces")
+	}
+
+	podSC := pod.SecurityContext
+	if podSC == nil {
+		podSC = &securityContext{}
+	}
+	for _, c := range pod.Containers {
+		sc := c.SecurityContext
+		if sc == nil {
+			sc = &securityContext{}
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			violations = append(violations, c.Name+": privileged")
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			violations = append(violations, c.Name+": allowPrivilegeEscalation != false")
+		}
+		if sc.Capabilities == nil || !containsString(sc.Capabilities.Drop, "ALL") {
+			violations = append(violations, c.Name+": capabilities not dropping ALL")
+		} else {
+			for _, capability := range sc.Capabilities.Add {
+				if capability != "NET_BIND_SERVICE" {
+					violations = append(violations, c.Name+": capability "+capability+" added")
+				}
+			}
+		}
+		runAsNonRoot := podSC.RunAsNonRoot
+		if sc.RunAsNonRoot != nil {
+			runAsNonRoot = sc.RunAsNonRoot
+		}
+		if runAsNonRoot == nil || !*runAsNonRoot {
+			violations = append(violations, c.Name+": runAsNonRoot != true")
+		}
+		runAsUser := podSC.RunAsUser
+		if sc.RunAsUser != nil {
+			runAsUser = sc.RunAsUser
+		}
+		if runAsUser != nil && *runAsUser == 0 {
+			violations = append(violations, c.Name+": runAsUser=0")
+		}
+		seccompProfile := podSC.SeccompProfile
+		if sc.SeccompProfile != nil {
+			seccompProfile = sc.SeccompProfile
+		}
+		if seccompProfile == nil || (seccompProfile.Type != "RuntimeDefault" && seccompProfile.Type != "Localhost") {
+			violations = append(violations, c.Name+": seccompProfile")
+		}
+	}
+
+	return violations
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+var _ = Describe("initScaffolder", func() {
+	var (
+		cfg    *config.Config
+		tmpDir string
+		oldDir string
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{Version: config.Version3Alpha, Domain: "my.domain", ProjectName: "project"}
+
+		var err error
+		oldDir, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		tmpDir, err = ioutil.TempDir("", "kustomize-scaffolds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldDir)).To(Succeed())
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("should scaffold a manager compliant with the restricted Pod Security Standard when requested", func() {
+		Expect(NewInitScaffolder(cfg, false, true, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		pod := managerPodSpec()
+		Expect(pod.Containers).To(HaveLen(2))
+		Expect(restrictedViolations(pod)).To(BeEmpty())
+		for _, c := range pod.Containers {
+			Expect(c.SecurityContext.ReadOnlyRootFilesystem).NotTo(BeNil())
+			Expect(*c.SecurityContext.ReadOnlyRootFilesystem).To(BeTrue())
+		}
+	})
+
+	It("should not enforce the restricted Pod Security Standard by default", func() {
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		Expect(restrictedViolations(managerPodSpec())).NotTo(BeEmpty())
+	})
+
+	It("should not deploy the auth proxy when the manager protects the metrics endpoint itself", func() {
+		cfg.MetricsAuthFilter = true
+		Expect(NewInitScaffolder(cfg, false, true, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		Expect(filepath.Join("config", "default", "manager_auth_proxy_patch.yaml")).NotTo(BeAnExistingFile())
+		patch := readDeployment(filepath.Join("config", "default", "manager_metrics_patch.yaml"))
+		Expect(patch.Containers).To(HaveLen(1))
+		Expect(patch.Containers[0].Name).To(Equal("manager"))
+
+		kustomization, err := ioutil.ReadFile(filepath.Join("config", "default", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(kustomization)).To(ContainSubstring("manager_metrics_patch.yaml\n"))
+		Expect(string(kustomization)).NotTo(ContainSubstring("manager_auth_proxy_patch.yaml"))
+		Expect(filepath.Join("config", "rbac", "auth_proxy_role.yaml")).To(BeARegularFile())
+		Expect(filepath.Join("config", "rbac", "auth_proxy_service.yaml")).To(BeARegularFile())
+	})
+
+	It("should mount the manager config generated in a ConfigMap when the project uses a component config", func() {
+		cfg.ComponentConfig = true
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		deployment, err := ioutil.ReadFile(filepath.Join("config", "manager", "manager.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(deployment)).To(ContainSubstring("        - --config=/controller_manager_config.yaml\n"))
+		Expect(string(deployment)).To(ContainSubstring("        configMap:\n          name: manager-config\n"))
+
+		kustomization, err := ioutil.ReadFile(filepath.Join("config", "manager", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(kustomization)).To(ContainSubstring("configMapGenerator:\n- name: manager-config\n" +
+			"  files:\n  - controller_manager_config.yaml\n"))
+
+		managerConfig, err := ioutil.ReadFile(filepath.Join("config", "manager", "controller_manager_config.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(managerConfig)).To(ContainSubstring("\nkind: ControllerManagerConfig\n"))
+		Expect(string(managerConfig)).To(ContainSubstring("\n  bindAddress: 127.0.0.1:8080\n"))
+
+		// Args patched into the manager would replace its --config flag
+		patch := readDeployment(filepath.Join("config", "default", "manager_auth_proxy_patch.yaml"))
+		Expect(patch.Containers).To(HaveLen(1))
+		Expect(patch.Containers[0].Name).To(Equal("kube-rbac-proxy"))
+	})
+
+	It("should disable the pprof endpoint of the manager when the project has one", func() {
+		cfg.Pprof = true
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		deployment, err := ioutil.ReadFile(filepath.Join("config", "manager", "manager.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(deployment)).To(ContainSubstring("        - --enable-leader-election\n"))
+		Expect(string(deployment)).To(ContainSubstring("        - --pprof-addr=0\n"))
+	})
+
+	It("should set the image of the manager when the project has one", func() {
+		cfg.Image = "quay.io/acme/operator:v0.1.0"
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		deployment, err := ioutil.ReadFile(filepath.Join("config", "manager", "manager.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(deployment)).To(ContainSubstring("        image: quay.io/acme/operator:v0.1.0\n"))
+	})
+
+	It("should set the OpenTelemetry environment of the manager when the project is traced", func() {
+		cfg.Tracing = true
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		deployment, err := ioutil.ReadFile(filepath.Join("config", "manager", "manager.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(deployment)).To(ContainSubstring("        - name: OTEL_EXPORTER_OTLP_ENDPOINT\n"))
+		Expect(string(deployment)).To(ContainSubstring("        - name: OTEL_SERVICE_NAME\n          value: " +
+			cfg.ProjectName + "\n"))
+	})
+
+	It("should set the zap flags of the manager when the project binds them", func() {
+		cfg.ZapFlags = true
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		deployment, err := ioutil.ReadFile(filepath.Join("config", "manager", "manager.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(deployment)).To(ContainSubstring("        - --zap-encoder=json\n"))
+		Expect(string(deployment)).To(ContainSubstring("        - --zap-log-level=info\n"))
+	})
+
+	It("should watch the namespace of the manager with a Role when the project is namespace-scoped", func() {
+		cfg.NamespaceScoped, cfg.Tracing = true, true
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		deployment, err := ioutil.ReadFile(filepath.Join("config", "manager", "manager.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Count(string(deployment), "        env:\n")).To(Equal(1))
+		Expect(string(deployment)).To(ContainSubstring("        - name: WATCH_NAMESPACE\n          valueFrom:\n" +
+			"            fieldRef:\n              fieldPath: metadata.namespace\n"))
+		binding, err := ioutil.ReadFile(filepath.Join("config", "rbac", "role_binding.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(binding)).To(ContainSubstring("kind: RoleBinding\nmetadata:\n  name: manager-rolebinding\n" +
+			"  namespace: system\nroleRef:\n  apiGroup: rbac.authorization.k8s.io\n  kind: Role\n"))
+	})
+
+	It("should set the namespaces the manager watches with its ClusterRole when the project has some", func() {
+		cfg.WatchNamespaces = []string{"crew", "fleet"}
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		deployment, err := ioutil.ReadFile(filepath.Join("config", "manager", "manager.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(deployment)).To(ContainSubstring("        env:\n"))
+		Expect(string(deployment)).To(ContainSubstring("        - name: WATCH_NAMESPACE\n          value: crew,fleet\n"))
+		binding, err := ioutil.ReadFile(filepath.Join("config", "rbac", "role_binding.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(binding)).To(ContainSubstring("kind: ClusterRoleBinding\n"))
+	})
+
+	It("should bind the ClusterRole of the manager by default", func() {
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		deployment, err := ioutil.ReadFile(filepath.Join("config", "manager", "manager.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(deployment)).NotTo(ContainSubstring("WATCH_NAMESPACE"))
+		binding, err := ioutil.ReadFile(filepath.Join("config", "rbac", "role_binding.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(binding)).To(ContainSubstring("kind: ClusterRoleBinding\n"))
+		Expect(string(binding)).To(ContainSubstring("  kind: ClusterRole\n"))
+	})
+
+	It("should scaffold the manager replicas, resources and PodDisruptionBudget", func() {
+		manager := options.Manager{
+			Replicas:            3,
+			CPURequest:          "200m",
+			MemoryRequest:       "64Mi",
+			CPULimit:            "1",
+			MemoryLimit:         "128Mi",
+			PodDisruptionBudget: true,
+		}
+		Expect(NewInitScaffolder(cfg, false, false, manager, nil).Scaffold()).To(Succeed())
+
+		deployment, err := ioutil.ReadFile(filepath.Join("config", "manager", "manager.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(deployment)).To(ContainSubstring("  replicas: 3\n"))
+		Expect(string(deployment)).To(ContainSubstring("          limits:\n            cpu: 1\n" +
+			"            memory: 128Mi\n          requests:\n            cpu: 200m\n            memory: 64Mi\n"))
+
+		kustomization, err := ioutil.ReadFile(filepath.Join("config", "manager", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(kustomization)).To(Equal("resources:\n- manager.yaml\n- pdb.yaml\n"))
+		Expect(filepath.Join("config", "manager", "pdb.yaml")).To(BeARegularFile())
+	})
+
+	It("should scaffold a highly available manager with the preset", func() {
+		cfg.ComponentConfig, cfg.HighAvailability = true, true
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}.WithHighAvailability(), nil).Scaffold()).
+			To(Succeed())
+
+		deployment, err := ioutil.ReadFile(filepath.Join("config", "manager", "manager.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(deployment)).To(ContainSubstring("  replicas: 2\n"))
+		Expect(string(deployment)).To(ContainSubstring("      topologySpreadConstraints:\n      - maxSkew: 1\n" +
+			"        topologyKey: kubernetes.io/hostname\n"))
+		Expect(string(deployment)).To(ContainSubstring("        topologyKey: topology.kubernetes.io/zone\n"))
+		Expect(filepath.Join("config", "manager", "pdb.yaml")).To(BeARegularFile())
+
+		managerConfig, err := ioutil.ReadFile(filepath.Join("config", "manager", "controller_manager_config.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(managerConfig)).To(ContainSubstring("\n  leaseDuration: 137s\n  renewDeadline: 107s\n" +
+			"  retryPeriod: 26s\n"))
+	})
+
+	It("should deploy into the namespace, with the name prefix and labelled with the project name of the config", func() {
+		cfg.Namespace, cfg.NamePrefix = "operators", "acme-"
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		kustomization, err := ioutil.ReadFile(filepath.Join("config", "default", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(kustomization)).To(HavePrefix("# Adds namespace to all resources.\nnamespace: operators\n"))
+		Expect(string(kustomization)).To(ContainSubstring("\nnamePrefix: acme-\n"))
+
+		deployment, err := ioutil.ReadFile(filepath.Join("config", "manager", "manager.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(deployment)).To(ContainSubstring("\n        app.kubernetes.io/name: project\n"))
+	})
+
+	It("should scaffold an overlay and a deploy target per environment", func() {
+		cfg.Image, cfg.ZapFlags = "quay.io/acme/operator:v0.1.0", true
+		const makefile = "# UnDeploy controller\nundeploy:\n\t$(KUSTOMIZE) build config/default | kubectl delete -f -\n\n" +
+			"# Generate manifests e.g. CRD, RBAC etc.\nmanifests: controller-gen\n"
+		Expect(ioutil.WriteFile("Makefile", []byte(makefile), 0644)).To(Succeed())
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{Replicas: 2}, options.Environments{"dev", "prod"}).
+			Scaffold()).To(Succeed())
+
+		kustomization, err := ioutil.ReadFile(filepath.Join("config", "overlays", "dev", "kustomization.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(kustomization)).To(ContainSubstring("\nnamespace: project-dev\n"))
+		Expect(string(kustomization)).To(ContainSubstring("\n- manager_patch.yaml\n"))
+		patch, err := ioutil.ReadFile(filepath.Join("config", "overlays", "dev", "manager_patch.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(patch)).To(ContainSubstring("  replicas: 2\n"))
+		Expect(string(patch)).To(ContainSubstring("        image: quay.io/acme/operator:dev\n"))
+		Expect(string(patch)).To(ContainSubstring(
+			"        - \"--enable-leader-election\"\n        - --zap-log-level=debug\n"))
+		patch, err = ioutil.ReadFile(filepath.Join("config", "overlays", "prod", "manager_patch.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(patch)).To(ContainSubstring("        - --zap-log-level=info\n"))
+
+		updated, err := ioutil.ReadFile("Makefile")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(updated)).To(ContainSubstring("kubectl delete -f -\n\n" +
+			"# Deploy controller in the dev environment, with the overlay of config/overlays/dev\n" +
+			"deploy-dev: manifests kustomize\n\t$(KUSTOMIZE) build config/overlays/dev | kubectl apply -f -\n\n" +
+			"# Deploy controller in the prod environment, with the overlay of config/overlays/prod\n" +
+			"deploy-prod: manifests kustomize\n\t$(KUSTOMIZE) build config/overlays/prod | kubectl apply -f -\n\n" +
+			"# Generate manifests"))
+	})
+
+	It("should not scaffold the log level of an environment if the manager does not bind the zap flags", func() {
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, options.Environments{"dev"}).Scaffold()).
+			To(Succeed())
+
+		patch, err := ioutil.ReadFile(filepath.Join("config", "overlays", "dev", "manager_patch.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(patch)).To(ContainSubstring("  replicas: 1\n"))
+		Expect(string(patch)).To(ContainSubstring("        image: project:dev\n"))
+		Expect(string(patch)).NotTo(ContainSubstring("args:"))
+	})
+})
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/options"
+)
+
+// undeployTargetRe matches the undeploy target of a Makefile and its recipe, which the deploy targets of the
+// environments are added after
+var undeployTargetRe = regexp.MustCompile(`(?m)^undeploy:.*\n(?:\t.*\n)*`)
+
+// addDeployTargets adds the deploy targets of environments after the undeploy target of the Makefile written by
+// the base plugin, if any.
+func addDeployTargets(path string, environments options.Environments) error {
+	if len(environments) == 0 {
+		return nil
+	}
+	bs, err := ioutil.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	targets := environments.DeployTargets()
+	loc := undeployTargetRe.FindIndex(bs)
+	if loc == nil {
+		logger.Default().Info(fmt.Sprintf("%s does not define the undeploy target, add the deploy targets of the "+
+			"environments to it:\n%s", path, targets))
+		return nil
+	}
+	updated := make([]byte, 0, len(bs)+len(targets))
+	updated = append(append(append(updated, bs[:loc[1]]...), targets...), bs[loc[1]:]...)
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(path, updated, 0644)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+var (
+	// webhookAPIVersionRe matches the API version of the webhook configurations in a manifest
+	webhookAPIVersionRe = regexp.MustCompile(`(?m)^apiVersion: admissionregistration\.k8s\.io/v1(beta1)?$`)
+	// webhookTargetPortRe matches the webhook server port targeted by the webhook Service
+	webhookTargetPortRe = regexp.MustCompile(`(?m)^(\s+targetPort: )\d+$`)
+	// webhookIngressPortRe matches the webhook server port allowed by the NetworkPolicy
+	webhookIngressPortRe = regexp.MustCompile(`(?m)^(\s+- port: )\d+$`)
+	// webhookContainerPortRe matches the webhook server port exposed by the manager patch
+	webhookContainerPortRe = regexp.MustCompile(`(?m)^(\s+- containerPort: )\d+$`)
+	// webhookCertDirRe matches the mount path of the webhook server certificate in the manager patch
+	webhookCertDirRe = regexp.MustCompile(`(?m)^(\s+- mountPath: ).*$`)
+	// webhookConfigRe matches the webhook server section of the ControllerManagerConfig
+	webhookConfigRe = regexp.MustCompile(`(?m)^webhook:\n(?:  .*\n)*`)
+)
+
+var _ scaffold.Scaffolder = &webhookScaffolder{}
+
+type webhookScaffolder struct {
+	config         *config.Config
+	webhookVersion string
+}
+
+// NewWebhookScaffolder returns a new Scaffolder that updates the webhook configuration patches to webhookVersion,
+// if set, and the webhook server manifests to the options of the config
+func NewWebhookScaffolder(config *config.Config, webhookVersion string) scaffold.Scaffolder {
+	return &webhookScaffolder{
+		config:         config,
+		webhookVersion: webhookVersion,
+	}
+}
+
+// Scaffold implements Scaffolder
+func (s *webhookScaffolder) Scaffold() error {
+	if s.webhookVersion != "" {
+		path := filepath.Join("config", "default", "webhookcainjection_patch.yaml")
+		err := updateManifest(path, "the webhook version", func(bs []byte) []byte {
+			return webhookAPIVersionRe.ReplaceAllLiteral(bs,
+				[]byte("apiVersion: admissionregistration.k8s.io/"+s.webhookVersion))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	port := []byte("${1}" + strconv.Itoa(s.config.GetWebhookPort()))
+	for _, manifest := range []struct {
+		path string
+		re   *regexp.Regexp
+	}{
+		{filepath.Join("config", "webhook", "service.yaml"), webhookTargetPortRe},
+		{filepath.Join("config", "network-policy", "allow-webhook-traffic.yaml"), webhookIngressPortRe},
+	} {
+		err := updateManifest(manifest.path, "the webhook server port", func(bs []byte) []byte {
+			return manifest.re.ReplaceAll(bs, port)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	certDir := []byte("${1}" + s.config.GetWebhookCertDir())
+	err := updateManifest(filepath.Join("config", "default", "manager_webhook_patch.yaml"),
+		"the webhook server options", func(bs []byte) []byte {
+			return webhookCertDirRe.ReplaceAll(webhookContainerPortRe.ReplaceAll(bs, port), certDir)
+		})
+	if err != nil {
+		return err
+	}
+
+	webhookConfig := fmt.Sprintf("webhook:\n  port: %d\n", s.config.GetWebhookPort())
+	if s.config.WebhookHost != "" {
+		webhookConfig += fmt.Sprintf("  host: %q\n", s.config.WebhookHost)
+	}
+	if s.config.WebhookCertDir != "" {
+		webhookConfig += fmt.Sprintf("  certDir: %s\n", s.config.WebhookCertDir)
+	}
+	return updateManifest(filepath.Join("config", "manager", "controller_manager_config.yaml"),
+		"the webhook server options", func(bs []byte) []byte {
+			return webhookConfigRe.ReplaceAllLiteral(bs, []byte(webhookConfig))
+		})
+}
+
+// updateManifest rewrites the manifest at path with update, if it exists and update changes it
+func updateManifest(path, what string, update func([]byte) []byte) error {
+	bs, err := ioutil.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	updated := update(bs)
+	if bytes.Equal(updated, bs) {
+		return nil
+	}
+
+	logger.Default().Info(fmt.Sprintf("Updating %s to %s...", path, what))
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(path, updated, 0644)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/options"
+)
+
+var _ = Describe("webhookScaffolder", func() {
+	var (
+		cfg    *config.Config
+		tmpDir string
+		oldDir string
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			Version:         config.Version3Alpha,
+			Domain:          "my.domain",
+			ProjectName:     "project",
+			ComponentConfig: true,
+		}
+
+		var err error
+		oldDir, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		tmpDir, err = ioutil.TempDir("", "kustomize-scaffolds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		Expect(NewInitScaffolder(cfg, true, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldDir)).To(Succeed())
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	read := func(path ...string) string {
+		content, err := ioutil.ReadFile(filepath.Join(path...))
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	It("should move the webhook server manifests to the port and certificate directory of the config", func() {
+		service, patch := read("config", "webhook", "service.yaml"), read("config", "default", "manager_webhook_patch.yaml")
+		cfg.WebhookPort, cfg.WebhookHost, cfg.WebhookCertDir = 9444, "0.0.0.0", "/certs"
+		Expect(NewWebhookScaffolder(cfg, "").Scaffold()).To(Succeed())
+
+		Expect(read("config", "webhook", "service.yaml")).To(ContainSubstring("    - port: 443\n      targetPort: 9444\n"))
+		Expect(read("config", "network-policy", "allow-webhook-traffic.yaml")).To(ContainSubstring("- port: 9444\n"))
+		Expect(read("config", "default", "manager_webhook_patch.yaml")).To(And(
+			ContainSubstring("- containerPort: 9444\n"), ContainSubstring("- mountPath: /certs\n")))
+		Expect(read("config", "manager", "controller_manager_config.yaml")).To(ContainSubstring(
+			"\nwebhook:\n  port: 9444\n  host: \"0.0.0.0\"\n  certDir: /certs\nleaderElection:\n"))
+
+		cfg.WebhookPort, cfg.WebhookHost, cfg.WebhookCertDir = 0, "", ""
+		Expect(NewWebhookScaffolder(cfg, "").Scaffold()).To(Succeed())
+
+		Expect(read("config", "webhook", "service.yaml")).To(Equal(service))
+		Expect(read("config", "default", "manager_webhook_patch.yaml")).To(Equal(patch))
+		Expect(read("config", "manager", "controller_manager_config.yaml")).To(ContainSubstring(
+			"\nwebhook:\n  port: 9443\nleaderElection:\n"))
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2/scaffolds"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+type deleteAPISubcommand struct {
+	config *config.Config
+
+	// existingResources are the resources that the project had before the plugin chain ran
+	existingResources []config.GVK
+}
+
+var (
+	_ plugin.DeleteAPI   = &deleteAPISubcommand{}
+	_ cmdutil.RunOptions = &deleteAPISubcommand{}
+)
+
+func (p *deleteAPISubcommand) UpdateContext(ctx *plugin.Context) {
+	ctx.Description += `
+The kustomize manifests of the resource are deleted from config/: its sample and, with the last version of the
+Kind, its RBAC roles, its CRD and CRD patches and their entries in the CRD kustomization.
+`
+}
+
+func (p *deleteAPISubcommand) BindFlags(*pflag.FlagSet) {}
+
+func (p *deleteAPISubcommand) InjectConfig(c *config.Config) {
+	p.config = c
+	p.existingResources = append([]config.GVK(nil), c.Resources...)
+}
+
+func (p *deleteAPISubcommand) Scaffold() error {
+	return cmdutil.Scaffold(p)
+}
+
+func (p *deleteAPISubcommand) Validate() error {
+	return nil
+}
+
+// GetScaffolder deletes the manifests of the resources removed from the config by the plugins that ran before
+// this one.
+func (p *deleteAPISubcommand) GetScaffolder() (scaffold.Scaffolder, error) {
+	var resources []*resource.Resource
+	for _, gvk := range p.existingResources {
+		if p.config.HasResource(gvk) {
+			continue
+		}
+		opts := &resource.Options{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Plural: gvk.Plural,
+			CRDVersion: gvk.CRDVersion}
+		resources = append(resources, opts.NewResource(p.config, true))
+	}
+	if len(resources) == 0 {
+		return nil, nil
+	}
+
+	return scaffolds.NewDeleteAPIScaffolder(p.config, resources...), nil
+}
+
+func (p *deleteAPISubcommand) PostScaffold() error {
+	return nil
+}
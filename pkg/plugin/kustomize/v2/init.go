@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/options"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2/scaffolds"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+// longestResourceName is the longest name of the resources deployed by the scaffolded manifests
+const longestResourceName = "controller-manager-metrics-service"
+
+type initSubcommand struct {
+	config *config.Config
+
+	// networkPolicy determines whether the manager NetworkPolicies are scaffolded
+	networkPolicy bool
+	// restrictedPodSecurity determines whether the manager complies with the "restricted" Pod Security Standard
+	restrictedPodSecurity bool
+	// manager are the replicas and resources of the manager
+	manager options.Manager
+	// environments are the environments an overlay of config/default is scaffolded for
+	environments options.Environments
+}
+
+var (
+	_ plugin.Init        = &initSubcommand{}
+	_ cmdutil.RunOptions = &initSubcommand{}
+)
+
+func (p *initSubcommand) UpdateContext(ctx *plugin.Context) {
+	ctx.Description += `
+Writes the following kustomize manifests under config/:
+- a Kustomization.yaml for customizating manifests
+- a Patch file for customizing image for manager manifests
+- a Patch file for enabling prometheus metrics
+- the RBAC, webhook and cert-manager manifests of the manager
+- a default overlay deploying every resource into the namespace set with --namespace, with the prefix set with
+  --name-prefix, which default to <project name>-system and <project name>-
+- optionally, NetworkPolicies restricting the manager traffic (--network-policy)
+- optionally, a manager securityContext complying with the "restricted" Pod Security Standard (--restricted-pod-security)
+- optionally, a PodDisruptionBudget keeping a replica of the manager available (--manager-pdb)
+- optionally, topologySpreadConstraints spreading the replicas of the manager across the nodes and zones of the
+  cluster (--manager-topology-spread)
+- optionally, an overlay of the default one per environment of --environments, in config/overlays/<environment>,
+  deploying the project into the <project name>-<environment> namespace with the <environment> tag of the image,
+  the manager logging at the debug level in the dev environment if it binds the zap flags, and its
+  deploy-<environment> Makefile target
+
+The replicas and resources of the manager are set with the --manager-* flags. With --high-availability, the
+manager runs 2 replicas, spread across the nodes and zones and kept available by a PodDisruptionBudget.
+`
+}
+
+func (p *initSubcommand) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&p.networkPolicy, "network-policy", false,
+		"scaffold NetworkPolicies restricting the manager ingress to the metrics and webhook ports")
+	fs.BoolVar(&p.restrictedPodSecurity, "restricted-pod-security", false,
+		"scaffold the manager with a securityContext complying with the \"restricted\" Pod Security Standard")
+	p.manager.BindFlags(fs)
+	p.environments.BindFlags(fs)
+	fs.StringVar(&p.config.Namespace, "namespace", "",
+		"namespace the manifests deploy the project into, defaults to the project name with a -system suffix")
+	fs.StringVar(&p.config.NamePrefix, "name-prefix", "",
+		"prefix of the names of the resources deployed by the manifests, defaults to the project name and a dash")
+}
+
+func (p *initSubcommand) InjectConfig(c *config.Config) {
+	p.config = c
+}
+
+func (p *initSubcommand) Scaffold() error {
+	return cmdutil.Scaffold(p)
+}
+
+func (p *initSubcommand) Validate() error {
+	// The project name is usually set by the base plugin, as it is used as the manifests' namespace and prefix.
+	if p.config.ProjectName == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("error getting current directory: %v", err)
+		}
+		p.config.ProjectName = strings.ToLower(filepath.Base(dir))
+	}
+	if err := validation.IsDNS1123Label(p.config.ProjectName); err != nil {
+		return fmt.Errorf("project name (%s) is invalid: %v", p.config.ProjectName, err)
+	}
+
+	if p.config.Namespace == "" {
+		p.config.Namespace = p.config.ProjectName + "-system"
+	} else if err := validation.IsDNS1123Label(p.config.Namespace); err != nil {
+		return fmt.Errorf("namespace (%s) is invalid: %v", p.config.Namespace, err)
+	}
+	if p.config.NamePrefix == "" {
+		p.config.NamePrefix = p.config.ProjectName + "-"
+	} else if err := validation.IsDNS1123Label(p.config.NamePrefix + longestResourceName); err != nil {
+		// The longest name of the scaffolded resources must still be a valid Service name once prefixed
+		return fmt.Errorf("name prefix (%s) is invalid: %v", p.config.NamePrefix, err)
+	}
+
+	// The replicas of a highly available manager can still be set with --manager-replicas
+	if p.config.HighAvailability {
+		p.manager = p.manager.WithHighAvailability()
+	}
+	if err := p.manager.Validate(); err != nil {
+		return err
+	}
+	if err := p.environments.Validate(p.config.ProjectName); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *initSubcommand) GetScaffolder() (scaffold.Scaffolder, error) {
+	return scaffolds.NewInitScaffolder(p.config, p.networkPolicy, p.restrictedPodSecurity, p.manager,
+		p.environments), nil
+}
+
+func (p *initSubcommand) PostScaffold() error {
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/crd"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/rbac"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/samples"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2/scaffolds/internal/templates/config/crd"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+var _ scaffold.Scaffolder = &apiScaffolder{}
+
+// apiScaffolder contains configuration for generating the kustomize manifests of new resources.
+type apiScaffolder struct {
+	config    *config.Config
+	resources []*resource.Resource
+}
+
+// NewAPIScaffolder returns a new Scaffolder for the kustomize manifests of the provided resources
+func NewAPIScaffolder(config *config.Config, resources ...*resource.Resource) scaffold.Scaffolder {
+	return &apiScaffolder{
+		config:    config,
+		resources: resources,
+	}
+}
+
+// Scaffold implements Scaffolder
+func (s *apiScaffolder) Scaffold() error {
+	logger.Default().Info("Writing kustomize manifests for you to edit...")
+
+	for _, res := range s.resources {
+		spec, err := s.specSample(res)
+		if err != nil {
+			return fmt.Errorf("error deriving the sample of %s from its Go types: %v", res.Kind, err)
+		}
+
+		if err := machinery.NewScaffold().Execute(
+			s.newUniverse(res),
+			&samples.CRDSample{Spec: spec},
+			&rbac.CRDEditorRole{},
+			&rbac.CRDViewerRole{},
+			&crd.EnableWebhookPatch{},
+			&crdv2.EnableCAInjectionPatch{},
+		); err != nil {
+			return fmt.Errorf("error scaffolding manifests: %v", err)
+		}
+
+		if err := machinery.NewScaffold().Execute(
+			s.newUniverse(res),
+			&crdv2.Kustomization{},
+			&crdv2.KustomizeConfig{},
+			&samples.Kustomization{},
+		); err != nil {
+			return fmt.Errorf("error scaffolding kustomization: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// specSample returns the example values of the Spec fields of res, derived from the Go types scaffolded by the base
+// plugin, or an empty string if they are not found
+func (s *apiScaffolder) specSample(res *resource.Resource) (string, error) {
+	path := filepath.Join(s.config.GetDirectories().Types, "%[kind]_types.go")
+	spec, _, err := util.SpecSample(res.Replacer().Replace(path), res.Kind)
+	return strings.TrimSuffix(spec, "\n"), err
+}
+
+func (s *apiScaffolder) newUniverse(res *resource.Resource) *model.Universe {
+	return model.NewUniverse(
+		model.WithConfig(s.config),
+		model.WithResource(res),
+	)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/samples"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2/scaffolds/internal/templates/config/crd"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+var _ scaffold.Scaffolder = &deleteAPIScaffolder{}
+
+// deleteAPIScaffolder removes the kustomize manifests of deleted resources.
+type deleteAPIScaffolder struct {
+	config    *config.Config
+	resources []*resource.Resource
+}
+
+// NewDeleteAPIScaffolder returns a new Scaffolder removing the kustomize manifests of the provided resources
+func NewDeleteAPIScaffolder(config *config.Config, resources ...*resource.Resource) scaffold.Scaffolder {
+	return &deleteAPIScaffolder{
+		config:    config,
+		resources: resources,
+	}
+}
+
+// Scaffold implements Scaffolder
+func (s *deleteAPIScaffolder) Scaffold() error {
+	logger.Default().Info("Deleting the kustomize manifests of the resource...")
+
+	for _, res := range s.resources {
+		replacer := res.Replacer()
+		paths := []string{filepath.Join("config", "samples", replacer.Replace("%[group]_%[version]_%[kind].yaml"))}
+		if err := util.RemoveCodeFragments(filepath.Join("config", "samples", "kustomization.yaml"),
+			samples.KustomizationFragments(res)...); err != nil {
+			return fmt.Errorf("error updating the samples kustomization: %v", err)
+		}
+
+		// The CRD and the roles are shared by the versions of the Kind, so they are kept until the last one
+		if !s.hasKind(res) {
+			paths = append(paths,
+				filepath.Join("config", "rbac", replacer.Replace("%[kind]_editor_role.yaml")),
+				filepath.Join("config", "rbac", replacer.Replace("%[kind]_viewer_role.yaml")),
+				filepath.Join("config", "crd", "patches", replacer.Replace("webhook_in_%[plural].yaml")),
+				filepath.Join("config", "crd", "patches", replacer.Replace("cainjection_in_%[plural].yaml")),
+				filepath.Join("config", "crd", "bases", fmt.Sprintf("%s_%s.yaml", res.Domain, res.Plural)),
+			)
+
+			// The patches may have been enabled, i.e. uncommented
+			fragments := crdv2.KustomizationFragments(res)
+			for _, fragment := range fragments {
+				if strings.HasPrefix(fragment, "#") {
+					fragments = append(fragments, strings.TrimPrefix(fragment, "#"))
+				}
+			}
+			if err := util.RemoveCodeFragments(filepath.Join("config", "crd", "kustomization.yaml"),
+				fragments...); err != nil {
+				return fmt.Errorf("error updating the CRD kustomization: %v", err)
+			}
+		}
+
+		for _, path := range paths {
+			if err := os.Remove(path); err == nil {
+				logger.Default().Info(fmt.Sprintf("Deleted %s", path))
+			} else if !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasKind returns true if another version of the group and Kind of res is still tracked.
+func (s *deleteAPIScaffolder) hasKind(res *resource.Resource) bool {
+	for _, gvk := range s.config.Resources {
+		if gvk.Group == res.Group && gvk.Kind == res.Kind {
+			return true
+		}
+	}
+	return false
+}
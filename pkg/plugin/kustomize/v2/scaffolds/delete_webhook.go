@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
+	crdv2 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2/scaffolds/internal/templates/config/crd"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+// documentSeparatorRe matches the separators of the documents of a YAML stream
+var documentSeparatorRe = regexp.MustCompile(`(?m)^---$`)
+
+var _ scaffold.Scaffolder = &deleteWebhookScaffolder{}
+
+// deleteWebhookScaffolder removes the webhooks of resources from the kustomize manifests.
+type deleteWebhookScaffolder struct {
+	config    *config.Config
+	resources []*resource.Resource
+}
+
+// NewDeleteWebhookScaffolder returns a new Scaffolder removing the webhooks of the provided resources from the
+// kustomize manifests
+func NewDeleteWebhookScaffolder(config *config.Config, resources ...*resource.Resource) scaffold.Scaffolder {
+	return &deleteWebhookScaffolder{
+		config:    config,
+		resources: resources,
+	}
+}
+
+// Scaffold implements Scaffolder
+func (s *deleteWebhookScaffolder) Scaffold() error {
+	logger.Default().Info("Deleting the webhooks of the resource from the kustomize manifests...")
+
+	paths := make(map[string]bool, 2*len(s.resources))
+	for _, res := range s.resources {
+		suffix := fmt.Sprintf("%s-%s-%s", strings.Replace(res.Domain, ".", "-", -1), res.Version,
+			strings.ToLower(res.Kind))
+		paths["/mutate-"+suffix] = true
+		paths["/validate-"+suffix] = true
+
+		// The conversion webhook and the CA injection are configured for the CRD, shared by the versions
+		if !s.hasWebhook(res) {
+			replacements := make(map[string]string, 2)
+			// The first fragment is the CRD itself, the others are its patches
+			for _, fragment := range crdv2.KustomizationFragments(res)[1:] {
+				replacements[strings.TrimPrefix(fragment, "#")] = fragment
+			}
+			if err := util.ReplaceCodeFragments(filepath.Join("config", "crd", "kustomization.yaml"),
+				replacements); err != nil {
+				return fmt.Errorf("error updating the CRD kustomization: %v", err)
+			}
+		}
+	}
+
+	// The manifests are generated by controller-gen, which does not write them once no webhook is left
+	if err := removeWebhooks(filepath.Join("config", "webhook", "manifests.yaml"), paths); err != nil {
+		return fmt.Errorf("error updating the webhook manifests: %v", err)
+	}
+
+	for _, gvk := range s.config.Resources {
+		if gvk.WebhookVersion != "" {
+			return nil
+		}
+	}
+	logger.Default().Info("The project has no webhook left, the [WEBHOOK] and [CERTMANAGER] sections of " +
+		"config/default/kustomization.yaml can be commented out again")
+	return nil
+}
+
+// hasWebhook returns true if a version of the group and Kind of res still has webhooks.
+func (s *deleteWebhookScaffolder) hasWebhook(res *resource.Resource) bool {
+	for _, gvk := range s.config.Resources {
+		if gvk.Group == res.Group && gvk.Kind == res.Kind && gvk.WebhookVersion != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// removeWebhooks removes the webhooks served at the provided paths from the webhook configurations of the
+// manifests at path. The configurations are kept even if no webhook is left in them, as they are patched by
+// the other manifests.
+func removeWebhooks(path string, paths map[string]bool) error {
+	content, err := ioutil.ReadFile(path) // nolint:gosec
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	changed := false
+	docs := documentSeparatorRe.Split(string(content), -1)
+	for i, doc := range docs {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return err
+		}
+		webhooks, _ := obj["webhooks"].([]interface{})
+		kept := make([]interface{}, 0, len(webhooks))
+		for _, webhook := range webhooks {
+			if !paths[webhookPath(webhook)] {
+				kept = append(kept, webhook)
+			}
+		}
+		if len(kept) == len(webhooks) {
+			continue
+		}
+
+		obj["webhooks"] = kept
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		trimmed := strings.TrimRight(doc, "\n")
+		docs[i] = "\n" + strings.TrimRight(string(out), "\n") + doc[len(trimmed):]
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	logger.Default().Info(fmt.Sprintf("Updated %s", path))
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(path, []byte(strings.Join(docs, "---")), 0644)
+}
+
+// webhookPath returns the path of the service of a webhook of a webhook configuration.
+func webhookPath(webhook interface{}) string {
+	value := webhook
+	for _, field := range []string{"clientConfig", "service", "path"} {
+		fields, isMap := value.(map[string]interface{})
+		if !isMap {
+			return ""
+		}
+		value = fields[field]
+	}
+	path, _ := value.(string)
+	return path
+}
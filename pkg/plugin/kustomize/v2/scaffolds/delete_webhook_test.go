@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+)
+
+// Only the CRD patches listed with the patches field of kustomize v4 differ from the kustomize/v1 plugin
+var _ = Describe("deleteWebhookScaffolder", func() {
+	var (
+		cfg          *config.Config
+		captain      *resource.Resource
+		tmpDir       string
+		oldDir       string
+		crdKustomize = filepath.Join("config", "crd", "kustomization.yaml")
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{Version: config.Version3Alpha, Domain: "my.domain", Repo: "example.com/project"}
+		opts := &resource.Options{Group: "crew", Version: "v1", Kind: "Captain"}
+		captain = opts.NewResource(cfg, true)
+		cfg.Resources = []config.GVK{captain.GVK(), {Group: "crew", Version: "v1", Kind: "Admiral", WebhookVersion: "v1"}}
+
+		var err error
+		oldDir, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		tmpDir, err = ioutil.TempDir("", "kustomize-scaffolds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		Expect(NewAPIScaffolder(cfg, captain).Scaffold()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldDir)).To(Succeed())
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("should comment out the CRD patches of the Kind again", func() {
+		kustomization, err := ioutil.ReadFile(crdKustomize)
+		Expect(err).NotTo(HaveOccurred())
+		enabled := strings.Replace(string(kustomization), "#- path: patches/", "- path: patches/", -1)
+		Expect(ioutil.WriteFile(crdKustomize, []byte(enabled), 0644)).To(Succeed())
+
+		Expect(NewDeleteWebhookScaffolder(cfg, captain).Scaffold()).To(Succeed())
+
+		kustomization, err = ioutil.ReadFile(crdKustomize)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(kustomization)).To(ContainSubstring("\n#- path: patches/webhook_in_captains.yaml\n"))
+		Expect(string(kustomization)).To(ContainSubstring("\n#- path: patches/cainjection_in_captains.yaml\n"))
+	})
+
+	It("should keep the CRD patches while another version of the Kind has webhooks", func() {
+		cfg.Resources = append(cfg.Resources, config.GVK{Group: "crew", Version: "v2", Kind: "Captain",
+			WebhookVersion: "v1"})
+		kustomization, err := ioutil.ReadFile(crdKustomize)
+		Expect(err).NotTo(HaveOccurred())
+		enabled := strings.Replace(string(kustomization), "#- path: patches/", "- path: patches/", -1)
+		Expect(ioutil.WriteFile(crdKustomize, []byte(enabled), 0644)).To(Succeed())
+
+		Expect(NewDeleteWebhookScaffolder(cfg, captain).Scaffold()).To(Succeed())
+
+		kustomization, err = ioutil.ReadFile(crdKustomize)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(kustomization)).To(Equal(enabled))
+	})
+})
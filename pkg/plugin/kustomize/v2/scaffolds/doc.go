@@ -0,0 +1,21 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scaffolds contains libraries for scaffolding the kustomize manifests of a project. The templates are
+// shared with the v1 plugin, except for the ones overridden in internal/templates: the manifests relying on the
+// vars, bases and patchesStrategicMerge fields, replaced by replacements, resources and patches in kustomize v4,
+// and the PodDisruptionBudget, which uses policy/v1.
+package scaffolds
@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/options"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/certmanager"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/kdefault"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/manager"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/networkpolicy"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/overlay"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/prometheus"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/rbac"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/templates/config/webhook"
+	certmanagerv2 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2/scaffolds/internal/templates/config/certmanager"
+	kdefaultv2 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2/scaffolds/internal/templates/config/kdefault"
+	managerv2 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2/scaffolds/internal/templates/config/manager"
+	overlayv2 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2/scaffolds/internal/templates/config/overlay"
+	webhookv2 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2/scaffolds/internal/templates/config/webhook"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+var _ scaffold.Scaffolder = &initScaffolder{}
+
+type initScaffolder struct {
+	config *config.Config
+
+	// networkPolicy determines whether the manager NetworkPolicies are scaffolded
+	networkPolicy bool
+	// restrictedPodSecurity determines whether the manager complies with the "restricted" Pod Security Standard
+	restrictedPodSecurity bool
+	// manager are the replicas and resources of the manager
+	manager options.Manager
+	// environments are the environments an overlay of config/default is scaffolded for
+	environments options.Environments
+}
+
+// NewInitScaffolder returns a new Scaffolder for the kustomize manifests of a project
+func NewInitScaffolder(
+	config *config.Config,
+	networkPolicy, restrictedPodSecurity bool,
+	manager options.Manager,
+	environments options.Environments,
+) scaffold.Scaffolder {
+	return &initScaffolder{
+		config:                config,
+		networkPolicy:         networkPolicy,
+		restrictedPodSecurity: restrictedPodSecurity,
+		manager:               manager,
+		environments:          environments,
+	}
+}
+
+// Scaffold implements Scaffolder
+func (s *initScaffolder) Scaffold() error {
+	logger.Default().Info("Writing kustomize manifests for you to edit...")
+	templates := []file.Builder{
+		&rbac.AuthProxyRole{},
+		&rbac.AuthProxyRoleBinding{},
+		&rbac.AuthProxyService{},
+		&rbac.ClientClusterRole{},
+		&manager.Config{
+			Image:                 s.config.GetManifestImage(),
+			RestrictedPodSecurity: s.restrictedPodSecurity,
+			ComponentConfig:       s.config.ComponentConfig,
+			Pprof:                 s.config.Pprof,
+			Tracing:               s.config.Tracing,
+			ZapFlags:              s.config.ZapFlags,
+			NamespaceScoped:       s.config.NamespaceScoped,
+			WatchNamespaces:       s.config.WatchNamespaces,
+			Manager:               s.manager,
+		},
+		&kdefaultv2.Kustomize{
+			Namespace:         s.config.Namespace,
+			NamePrefix:        s.config.NamePrefix,
+			NetworkPolicy:     s.networkPolicy,
+			MetricsAuthFilter: s.config.MetricsAuthFilter,
+		},
+		&kdefault.ManagerWebhookPatch{Port: s.config.GetWebhookPort(), CertDir: s.config.GetWebhookCertDir()},
+		&rbac.ManagerRoleBinding{NamespaceScoped: s.config.NamespaceScoped},
+		&rbac.LeaderElectionRole{},
+		&rbac.LeaderElectionRoleBinding{},
+		&rbac.KustomizeRBAC{MetricsAuthFilter: s.config.MetricsAuthFilter},
+		&manager.Kustomization{
+			PodDisruptionBudget: s.manager.PodDisruptionBudget,
+			ComponentConfig:     s.config.ComponentConfig,
+		},
+		&webhook.Kustomization{},
+		&webhookv2.KustomizeConfigWebhook{},
+		&webhook.Service{Port: s.config.GetWebhookPort()},
+		&kdefaultv2.InjectCAPatch{},
+		&prometheus.Kustomization{},
+		&prometheus.ServiceMonitor{},
+		&certmanagerv2.CertManager{},
+		&certmanager.Kustomization{},
+		&certmanagerv2.KustomizeConfig{},
+	}
+	// The auth proxy is not needed when the manager protects the metrics endpoint itself, which still requires the
+	// proxy role to create TokenReviews and SubjectAccessReviews, and the metrics Service
+	if s.config.MetricsAuthFilter {
+		templates = append(templates, &kdefault.MetricsPatch{ComponentConfig: s.config.ComponentConfig})
+	} else {
+		templates = append(templates, &kdefault.AuthProxyPatch{
+			RestrictedPodSecurity: s.restrictedPodSecurity,
+			ComponentConfig:       s.config.ComponentConfig,
+		})
+	}
+	if s.config.ComponentConfig {
+		templates = append(templates, &manager.ControllerManagerConfig{
+			MetricsAuthFilter: s.config.MetricsAuthFilter,
+			WebhookPort:       s.config.GetWebhookPort(),
+			WebhookHost:       s.config.WebhookHost,
+			WebhookCertDir:    s.config.WebhookCertDir,
+			HighAvailability:  s.config.HighAvailability,
+		})
+	}
+	if s.manager.PodDisruptionBudget {
+		templates = append(templates, &managerv2.PodDisruptionBudget{})
+	}
+	templates = append(templates, s.overlays()...)
+	if s.networkPolicy {
+		templates = append(templates,
+			&networkpolicy.Kustomization{},
+			&networkpolicy.AllowMetricsTraffic{},
+			&networkpolicy.AllowWebhookTraffic{Port: s.config.GetWebhookPort()},
+			&networkpolicy.AllowEgressTraffic{},
+		)
+	}
+
+	if err := machinery.NewScaffold().Execute(
+		model.NewUniverse(model.WithConfig(s.config)),
+		templates...,
+	); err != nil {
+		return err
+	}
+
+	if err := updateMakefile("Makefile"); err != nil {
+		return err
+	}
+	return addDeployTargets("Makefile", s.environments)
+}
+
+// overlays returns the templates of the overlays of the environments
+func (s *initScaffolder) overlays() []file.Builder {
+	// The image of each environment is tagged with its name
+	imageName := s.config.ProjectName
+	if s.config.Image != "" {
+		imageName = config.ImageName(s.config.Image)
+	}
+	templates := make([]file.Builder, 0, 2*len(s.environments))
+	for _, env := range s.environments {
+		templates = append(templates,
+			&overlayv2.Kustomization{Environment: env, Namespace: options.Namespace(s.config.ProjectName, env)},
+			&overlay.ManagerPatch{
+				Environment:       env,
+				Image:             imageName + ":" + env,
+				Replicas:          s.manager.WithDefaults().Replicas,
+				LogLevel:          options.LogLevel(env),
+				ZapFlags:          s.config.ZapFlags,
+				ComponentConfig:   s.config.ComponentConfig,
+				Pprof:             s.config.Pprof,
+				MetricsAuthFilter: s.config.MetricsAuthFilter,
+			},
+		)
+	}
+	return templates
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/options"
+)
+
+// The manifests scaffolded the same way as by the kustomize/v1 plugin are tested by it, only the changes required
+// by kustomize v4 are tested here.
+var _ = Describe("initScaffolder", func() {
+	var (
+		cfg    *config.Config
+		tmpDir string
+		oldDir string
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{Version: config.Version3Alpha, Domain: "my.domain", ProjectName: "project"}
+
+		var err error
+		oldDir, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		tmpDir, err = ioutil.TempDir("", "kustomize-scaffolds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldDir)).To(Succeed())
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	read := func(path ...string) string {
+		content, err := ioutil.ReadFile(filepath.Join(path...))
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	It("should patch the manifests with patches and replacements instead of the fields removed by kustomize v4", func() {
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, options.Environments{"dev"}).Scaffold()).
+			To(Succeed())
+
+		kustomization := read("config", "default", "kustomization.yaml")
+		Expect(kustomization).To(ContainSubstring("\nresources:\n- ../crd\n"))
+		Expect(kustomization).To(ContainSubstring("\npatches:\n"))
+		Expect(kustomization).To(ContainSubstring("\n- path: manager_auth_proxy_patch.yaml\n"))
+		Expect(kustomization).To(ContainSubstring("\n#- path: manager_webhook_patch.yaml\n"))
+		Expect(kustomization).To(ContainSubstring("\n#replacements:\n"))
+		for _, removed := range []string{"\nbases:", "\npatchesStrategicMerge:", "\nvars:", "\ncommonLabels:"} {
+			Expect(kustomization).NotTo(ContainSubstring(removed))
+		}
+
+		Expect(read("config", "certmanager", "certificate.yaml")).To(ContainSubstring(
+			"  - SERVICE_NAME.SERVICE_NAMESPACE.svc\n"))
+		Expect(read("config", "overlays", "dev", "kustomization.yaml")).To(ContainSubstring(
+			"\n- path: manager_patch.yaml\n"))
+	})
+
+	It("should install the kustomize version the manifests require with the Makefile", func() {
+		const makefile = "# Download kustomize locally if necessary\nkustomize:\n" +
+			"ifeq (, $(shell which kustomize))\n\tgo get sigs.k8s.io/kustomize/kustomize/v3@v3.5.4\n" +
+			"KUSTOMIZE=$(GOBIN)/kustomize\nelse\nKUSTOMIZE=$(shell which kustomize)\nendif\n\n" +
+			"# Generate code\ngenerate: controller-gen\n"
+		Expect(ioutil.WriteFile("Makefile", []byte(makefile), 0644)).To(Succeed())
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		updated := read("Makefile")
+		Expect(updated).To(HavePrefix("# Download kustomize locally if necessary\n" +
+			"# The manifests require kustomize " + KustomizeVersion + " or newer"))
+		Expect(updated).To(ContainSubstring("\nKUSTOMIZE_VERSION ?= v4.5.7\n"))
+		Expect(updated).To(HaveSuffix("\t}\n\n# Generate code\ngenerate: controller-gen\n"))
+		Expect(updated).NotTo(ContainSubstring("kustomize/v3"))
+	})
+
+	It("should keep a Makefile without the kustomize target", func() {
+		const makefile = "# Generate code\ngenerate: controller-gen\n"
+		Expect(ioutil.WriteFile("Makefile", []byte(makefile), 0644)).To(Succeed())
+		Expect(NewInitScaffolder(cfg, false, false, options.Manager{}, nil).Scaffold()).To(Succeed())
+
+		Expect(read("Makefile")).To(Equal(makefile))
+	})
+})
@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kdefault
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Kustomize{}
+
+// Kustomize scaffolds the Kustomization file for the default overlay
+type Kustomize struct {
+	file.TemplateMixin
+
+	// Namespace is the namespace all resources are deployed into
+	Namespace string
+	// NamePrefix is prepended to the names of all resources
+	NamePrefix string
+
+	// NetworkPolicy determines whether the network-policy manifests are added to the default overlay
+	NetworkPolicy bool
+	// MetricsAuthFilter determines whether the manager protects the metrics endpoint itself, without the auth proxy
+	MetricsAuthFilter bool
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Kustomize) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("config", "default", "kustomization.yaml")
+	}
+
+	f.TemplateBody = kustomizeTemplate
+
+	f.IfExistsAction = file.Error
+
+	return nil
+}
+
+const kustomizeTemplate = `# Adds namespace to all resources.
+namespace: {{ .Namespace }}
+
+# Value of this field is prepended to the
+# names of all resources, e.g. a deployment named
+# "wordpress" becomes "alices-wordpress".
+# Note that it should also match with the prefix (text before '-') of the namespace
+# field above.
+namePrefix: {{ .NamePrefix }}
+
+# Labels to add to all resources and selectors.
+#labels:
+#- includeSelectors: true
+#  pairs:
+#    someName: someValue
+
+resources:
+- ../crd
+- ../rbac
+- ../manager
+# [WEBHOOK] To enable webhook, uncomment all the sections with [WEBHOOK] prefix including the one in
+# crd/kustomization.yaml
+#- ../webhook
+# [CERTMANAGER] To enable cert-manager, uncomment all sections with 'CERTMANAGER'. 'WEBHOOK' components are required.
+#- ../certmanager
+# [PROMETHEUS] To enable prometheus monitor, uncomment all sections with 'PROMETHEUS'.
+#- ../prometheus
+{{- if .NetworkPolicy }}
+# [NETWORK POLICY] Restricts the manager ingress to the metrics and webhook ports.
+- ../network-policy
+{{- end }}
+
+patches:
+{{- if .MetricsAuthFilter }}
+# The manager protects the /metrics endpoint itself, serving it over HTTPS
+# to the clients allowed to get it only.
+- path: manager_metrics_patch.yaml
+{{- else }}
+# Protect the /metrics endpoint by putting it behind auth.
+# If you want your controller-manager to expose the /metrics
+# endpoint w/o any authn/z, please comment the following line.
+- path: manager_auth_proxy_patch.yaml
+{{- end }}
+
+# [WEBHOOK] To enable webhook, uncomment all the sections with [WEBHOOK] prefix including the one in
+# crd/kustomization.yaml
+#- path: manager_webhook_patch.yaml
+
+# [CERTMANAGER] To enable cert-manager, uncomment all sections with 'CERTMANAGER'.
+# Uncomment 'CERTMANAGER' sections in crd/kustomization.yaml to enable the CA injection in the admission webhooks.
+# 'CERTMANAGER' needs to be enabled to use ca injection
+#- path: webhookcainjection_patch.yaml
+
+# [CERTMANAGER] To enable cert-manager, uncomment all sections with 'CERTMANAGER' prefix.
+# The following replacements substitute the CERTIFICATE_NAMESPACE, CERTIFICATE_NAME, SERVICE_NAME and
+# SERVICE_NAMESPACE placeholders of the cert-manager annotations and of the certificate.
+#replacements:
+#- source: # namespace of the certificate CR
+#    kind: Certificate
+#    group: cert-manager.io
+#    version: v1alpha2
+#    name: serving-cert # this name should match the one in certificate.yaml
+#    fieldPath: .metadata.namespace
+#  targets:
+#    - select:
+#        kind: ValidatingWebhookConfiguration
+#      fieldPaths:
+#        - .metadata.annotations.[cert-manager.io/inject-ca-from]
+#      options:
+#        delimiter: '/'
+#        index: 0
+#        create: true
+#    - select:
+#        kind: MutatingWebhookConfiguration
+#      fieldPaths:
+#        - .metadata.annotations.[cert-manager.io/inject-ca-from]
+#      options:
+#        delimiter: '/'
+#        index: 0
+#        create: true
+#    - select:
+#        kind: CustomResourceDefinition
+#      fieldPaths:
+#        - .metadata.annotations.[cert-manager.io/inject-ca-from]
+#      options:
+#        delimiter: '/'
+#        index: 0
+#        create: true
+#- source: # name of the certificate CR
+#    kind: Certificate
+#    group: cert-manager.io
+#    version: v1alpha2
+#    name: serving-cert # this name should match the one in certificate.yaml
+#    fieldPath: .metadata.name
+#  targets:
+#    - select:
+#        kind: ValidatingWebhookConfiguration
+#      fieldPaths:
+#        - .metadata.annotations.[cert-manager.io/inject-ca-from]
+#      options:
+#        delimiter: '/'
+#        index: 1
+#        create: true
+#    - select:
+#        kind: MutatingWebhookConfiguration
+#      fieldPaths:
+#        - .metadata.annotations.[cert-manager.io/inject-ca-from]
+#      options:
+#        delimiter: '/'
+#        index: 1
+#        create: true
+#    - select:
+#        kind: CustomResourceDefinition
+#      fieldPaths:
+#        - .metadata.annotations.[cert-manager.io/inject-ca-from]
+#      options:
+#        delimiter: '/'
+#        index: 1
+#        create: true
+#- source: # name of the service
+#    kind: Service
+#    version: v1
+#    name: webhook-service
+#    fieldPath: .metadata.name
+#  targets:
+#    - select:
+#        kind: Certificate
+#        group: cert-manager.io
+#        version: v1alpha2
+#      fieldPaths:
+#        - .spec.dnsNames.0
+#        - .spec.dnsNames.1
+#      options:
+#        delimiter: '.'
+#        index: 0
+#        create: true
+#- source: # namespace of the service
+#    kind: Service
+#    version: v1
+#    name: webhook-service
+#    fieldPath: .metadata.namespace
+#  targets:
+#    - select:
+#        kind: Certificate
+#        group: cert-manager.io
+#        version: v1alpha2
+#      fieldPaths:
+#        - .spec.dnsNames.0
+#        - .spec.dnsNames.1
+#      options:
+#        delimiter: '.'
+#        index: 1
+#        create: true
+`
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/internal/options"
+)
+
+// KustomizeVersion is the kubernetes-sigs/kustomize version installed by the project's Makefile
+const KustomizeVersion = "v4.5.7"
+
+// kustomizeTargetRe matches the kustomize target of a Makefile, either up to the end of its conditional block or,
+// if kustomize is built from hack/tools, up to its build rule
+var kustomizeTargetRe = regexp.MustCompile(`(?ms)^kustomize:\n.*?^endif\n` +
+	`|^KUSTOMIZE = \$\(LOCALBIN\)/kustomize\n.*?go-build-tool,\$\(KUSTOMIZE\),[^\n]*\n`)
+
+//nolint:lll
+const kustomizeTarget = `# The manifests require kustomize ` + KustomizeVersion + ` or newer, so a pinned binary is installed in bin/
+KUSTOMIZE_VERSION ?= ` + KustomizeVersion + `
+KUSTOMIZE_INSTALL_SCRIPT ?= https://raw.githubusercontent.com/kubernetes-sigs/kustomize/master/hack/install_kustomize.sh
+KUSTOMIZE = $(shell pwd)/bin/kustomize
+kustomize:
+	@test -s $(KUSTOMIZE) || { \
+	set -e ;\
+	mkdir -p $(shell pwd)/bin ;\
+	curl -sSfL $(KUSTOMIZE_INSTALL_SCRIPT) | bash -s -- $(subst v,,$(KUSTOMIZE_VERSION)) $(shell pwd)/bin ;\
+	}
+`
+
+// updateMakefile replaces the kustomize target of the Makefile written by the base plugin, if any, so that it
+// installs a kustomize version compatible with the scaffolded manifests.
+func updateMakefile(path string) error {
+	bs, err := ioutil.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if !kustomizeTargetRe.Match(bs) {
+		logger.Default().Info(fmt.Sprintf("%s does not define the kustomize target, make sure kustomize %s or newer is used",
+			path, KustomizeVersion))
+		return nil
+	}
+
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(path, kustomizeTargetRe.ReplaceAll(bs, []byte(kustomizeTarget)), 0644)
+}
+
+// undeployTargetRe matches the undeploy target of a Makefile and its recipe, which the deploy targets of the
+// environments are added after
+var undeployTargetRe = regexp.MustCompile(`(?m)^undeploy:.*\n(?:\t.*\n)*`)
+
+// addDeployTargets adds the deploy targets of environments after the undeploy target of the Makefile written by
+// the base plugin, if any.
+func addDeployTargets(path string, environments options.Environments) error {
+	if len(environments) == 0 {
+		return nil
+	}
+	bs, err := ioutil.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	targets := environments.DeployTargets()
+	loc := undeployTargetRe.FindIndex(bs)
+	if loc == nil {
+		logger.Default().Info(fmt.Sprintf("%s does not define the undeploy target, add the deploy targets of the "+
+			"environments to it:\n%s", path, targets))
+		return nil
+	}
+	updated := make([]byte, 0, len(bs)+len(targets))
+	updated = append(append(append(updated, bs[:loc[1]]...), targets...), bs[loc[1]:]...)
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(path, updated, 0644)
+}
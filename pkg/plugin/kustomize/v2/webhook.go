@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v2/scaffolds"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+)
+
+type createWebhookSubcommand struct {
+	config *config.Config
+}
+
+var (
+	_ plugin.CreateWebhook = &createWebhookSubcommand{}
+	_ cmdutil.RunOptions   = &createWebhookSubcommand{}
+)
+
+func (p *createWebhookSubcommand) UpdateContext(ctx *plugin.Context) {
+	ctx.Description += `
+The webhook configuration patches under config/ are updated to the webhook version used by the project,
+and the webhook server manifests to its port and certificate directory.
+`
+}
+
+func (p *createWebhookSubcommand) BindFlags(*pflag.FlagSet) {}
+
+func (p *createWebhookSubcommand) InjectConfig(c *config.Config) {
+	p.config = c
+}
+
+func (p *createWebhookSubcommand) Scaffold() error {
+	return cmdutil.Scaffold(p)
+}
+
+func (p *createWebhookSubcommand) Validate() error {
+	return nil
+}
+
+// GetScaffolder updates the manifests to the webhook version and webhook server options recorded in the config by
+// the plugins that ran before this one. All the resources of a project share the same webhook version.
+func (p *createWebhookSubcommand) GetScaffolder() (scaffold.Scaffolder, error) {
+	for _, gvk := range p.config.Resources {
+		if gvk.WebhookVersion != "" {
+			return scaffolds.NewWebhookScaffolder(p.config, gvk.WebhookVersion), nil
+		}
+	}
+
+	return scaffolds.NewWebhookScaffolder(p.config, ""), nil
+}
+
+func (p *createWebhookSubcommand) PostScaffold() error {
+	return nil
+}
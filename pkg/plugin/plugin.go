@@ -97,6 +97,11 @@ func ParseVersion(version string) (v Version, err error) {
 	return v, v.Validate()
 }
 
+// IsStable returns true if v has no stage, so it is neither alpha nor beta.
+func (v Version) IsStable() bool {
+	return v.Stage == ""
+}
+
 // Compare returns -1 if v < vp, 0 if v == vp, and 1 if v > vp.
 func (v Version) Compare(vp Version) int {
 	if v.Number == vp.Number {
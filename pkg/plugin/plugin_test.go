@@ -154,3 +154,121 @@ var _ = g.Describe("Compare", func() {
 	})
 
 })
+
+var _ = g.Describe("ParseVersionConstraint", func() {
+
+	g.It("should parse a version without an operator as an exact match", func() {
+		c, err := ParseVersionConstraint("v2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).To(Equal(VersionConstraint{Operator: EqualOperator, Version: Version{Number: 2}}))
+		Expect(c.IsRange()).To(BeFalse())
+		Expect(c.String()).To(Equal("v2"))
+	})
+
+	g.It("should parse a version with an operator", func() {
+		for constraint, expected := range map[string]VersionConstraint{
+			"=v2":       {Operator: EqualOperator, Version: Version{Number: 2}},
+			">v2":       {Operator: GreaterOperator, Version: Version{Number: 2}},
+			">=v2":      {Operator: GreaterOrEqualOperator, Version: Version{Number: 2}},
+			"<3":        {Operator: LessOperator, Version: Version{Number: 3}},
+			"<=v3-beta": {Operator: LessOrEqualOperator, Version: Version{Number: 3, Stage: BetaStage}},
+			"~v3":       {Operator: CompatibleOperator, Version: Version{Number: 3}},
+		} {
+			c, err := ParseVersionConstraint(constraint)
+			Expect(err).NotTo(HaveOccurred(), constraint)
+			Expect(c).To(Equal(expected), constraint)
+			Expect(c.IsRange()).To(Equal(expected.Operator != EqualOperator), constraint)
+		}
+	})
+
+	g.It("should fail with an invalid version or operator", func() {
+		for _, constraint := range []string{"", ">=", "=>v2", "!v2", "~>v2", ">=v1.0.0"} {
+			_, err := ParseVersionConstraint(constraint)
+			Expect(err).To(HaveOccurred(), constraint)
+		}
+	})
+
+})
+
+var _ = g.Describe("VersionConstraint.Check", func() {
+
+	var (
+		v2Alpha = Version{Number: 2, Stage: AlphaStage}
+		v2      = Version{Number: 2}
+		v3Alpha = Version{Number: 3, Stage: AlphaStage}
+		v3Beta  = Version{Number: 3, Stage: BetaStage}
+		v3      = Version{Number: 3}
+		v4      = Version{Number: 4}
+
+		versions = []Version{v2Alpha, v2, v3Alpha, v3Beta, v3, v4}
+	)
+
+	matching := func(constraint string) (matched []Version) {
+		c, err := ParseVersionConstraint(constraint)
+		Expect(err).NotTo(HaveOccurred())
+		for _, v := range versions {
+			if c.Check(v) {
+				matched = append(matched, v)
+			}
+		}
+		return matched
+	}
+
+	g.It("should match the versions satisfying the constraint", func() {
+		Expect(matching("v3")).To(Equal([]Version{v3}))
+		Expect(matching("=v3-beta")).To(Equal([]Version{v3Beta}))
+		Expect(matching(">v3-alpha")).To(Equal([]Version{v3Beta, v3, v4}))
+		Expect(matching(">=v2")).To(Equal([]Version{v2, v3Alpha, v3Beta, v3, v4}))
+		Expect(matching("<v3")).To(Equal([]Version{v2Alpha, v2, v3Alpha, v3Beta}))
+		Expect(matching("<=v2")).To(Equal([]Version{v2Alpha, v2}))
+		Expect(matching("~v3")).To(Equal([]Version{v3Alpha, v3Beta, v3}))
+		Expect(matching("~v5")).To(BeEmpty())
+	})
+
+})
+
+type mockBase struct {
+	name            string
+	version         Version
+	projectVersions []string
+}
+
+func (p mockBase) Name() string                       { return p.name }
+func (p mockBase) Version() Version                   { return p.version }
+func (p mockBase) SupportedProjectVersions() []string { return p.projectVersions }
+
+var _ = g.Describe("NewBundle", func() {
+
+	var (
+		name    = "bundle.kubebuilder.io"
+		version = Version{Number: 1}
+		p1      = mockBase{name: "go.kubebuilder.io", version: Version{Number: 1}, projectVersions: []string{"2", "3-alpha"}}
+		p2      = mockBase{name: "kustomize.kubebuilder.io", version: Version{Number: 1}, projectVersions: []string{"3-alpha"}}
+		p3      = mockBase{name: "helm.kubebuilder.io", version: Version{Number: 1}, projectVersions: []string{"2"}}
+	)
+
+	g.It("should group the plugins in order", func() {
+		b, err := NewBundle(name, version, p1, p2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b.Name()).To(Equal(name))
+		Expect(b.Version()).To(Equal(version))
+		Expect(b.Plugins()).To(Equal([]Base{p1, p2}))
+	})
+
+	g.It("should only support the project versions common to every plugin", func() {
+		b, err := NewBundle(name, version, p1, p2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b.SupportedProjectVersions()).To(Equal([]string{"3-alpha"}))
+	})
+
+	g.It("should fail if no plugin is provided", func() {
+		_, err := NewBundle(name, version)
+		Expect(err).To(HaveOccurred())
+	})
+
+	g.It("should fail if the plugins do not support a common project version", func() {
+		_, err := NewBundle(name, version, p2, p3)
+		Expect(err).To(HaveOccurred())
+	})
+
+})
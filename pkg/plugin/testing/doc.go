@@ -0,0 +1,23 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing helps plugin authors test their scaffolds the way the built-in plugins are tested: their
+// subcommands are run by a Runner against a temporary Project, like the CLI would run them, and the scaffolded
+// files are compared with golden files by CompareGolden.
+//
+// Plugins write their files relative to the current directory, so a Project changes to its directory until it is
+// closed, and tests using it must not run in parallel.
+package testing
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// UpdateGoldenEnvVar is the environment variable that, set to a non-empty value, makes CompareGolden write the
+// golden files instead of comparing them, e.g. UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnvVar = "UPDATE_GOLDEN"
+
+// CompareGolden compares files, by slash-separated path, with the golden files in dir, returning an error that
+// describes every missing, unexpected and different file. The paths matching one of the ignore patterns, in the
+// syntax of path.Match, are not compared, e.g. "go.sum" or "bin/*".
+//
+// If UpdateGoldenEnvVar is set, the golden files are replaced by files instead, except the ignored ones.
+func CompareGolden(dir string, files map[string]string, ignore ...string) error {
+	compared, err := withoutIgnored(files, ignore)
+	if err != nil {
+		return err
+	}
+	if os.Getenv(UpdateGoldenEnvVar) != "" {
+		return writeGolden(dir, compared)
+	}
+
+	golden, err := (&Project{Dir: dir}).Files()
+	if err != nil {
+		return fmt.Errorf("unable to read the golden files: %v", err)
+	}
+	if golden, err = withoutIgnored(golden, ignore); err != nil {
+		return err
+	}
+
+	var diffs []string
+	for _, p := range Paths(golden) {
+		contents, found := compared[p]
+		if !found {
+			diffs = append(diffs, fmt.Sprintf("%s: missing", p))
+		} else if diff := diffLines(golden[p], contents); diff != "" {
+			diffs = append(diffs, fmt.Sprintf("%s: %s", p, diff))
+		}
+	}
+	for _, p := range Paths(compared) {
+		if _, found := golden[p]; !found {
+			diffs = append(diffs, fmt.Sprintf("%s: unexpected", p))
+		}
+	}
+	if len(diffs) != 0 {
+		return fmt.Errorf("files differ from the golden files in %s, set %s=1 to update them:\n%s",
+			dir, UpdateGoldenEnvVar, strings.Join(diffs, "\n"))
+	}
+	return nil
+}
+
+// withoutIgnored returns files without the paths matching one of the ignore patterns.
+func withoutIgnored(files map[string]string, ignore []string) (map[string]string, error) {
+	kept := make(map[string]string, len(files))
+	for p, contents := range files {
+		ignored := false
+		for _, pattern := range ignore {
+			matched, err := path.Match(pattern, p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ignore pattern %q: %v", pattern, err)
+			}
+			if matched {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			kept[p] = contents
+		}
+	}
+	return kept, nil
+}
+
+// writeGolden replaces the golden files in dir by files.
+func writeGolden(dir string, files map[string]string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	for _, p := range Paths(files) {
+		golden := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(golden, []byte(files[p]), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffLines describes the first line where actual differs from expected, or returns an empty string if they are
+// equal.
+func diffLines(expected, actual string) string {
+	if expected == actual {
+		return ""
+	}
+	expectedLines, actualLines := strings.Split(expected, "\n"), strings.Split(actual, "\n")
+	for i := 0; ; i++ {
+		switch {
+		case i >= len(expectedLines):
+			return fmt.Sprintf("line %d: unexpected %q", i+1, actualLines[i])
+		case i >= len(actualLines):
+			return fmt.Sprintf("line %d: missing %q", i+1, expectedLines[i])
+		case expectedLines[i] != actualLines[i]:
+			return fmt.Sprintf("line %d: expected %q, got %q", i+1, expectedLines[i], actualLines[i])
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CompareGolden", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "golden")
+		Expect(err).NotTo(HaveOccurred())
+		golden := &Project{Dir: dir}
+		Expect(golden.WriteFiles(map[string]string{
+			"Makefile":          "all: build\n\nbuild:\n",
+			"config/crd/a.yaml": "kind: A\n",
+			"go.sum":            "golden\n",
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(UpdateGoldenEnvVar)).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should succeed if the files are the golden ones, except the ignored ones", func() {
+		Expect(CompareGolden(dir, map[string]string{
+			"Makefile":          "all: build\n\nbuild:\n",
+			"config/crd/a.yaml": "kind: A\n",
+			"go.sum":            "other\n",
+			"bin/tool":          "binary",
+		}, "go.sum", "bin/*")).To(Succeed())
+	})
+
+	It("should describe the missing, unexpected and different files", func() {
+		Expect(CompareGolden(dir, map[string]string{
+			"Makefile":          "all: build\n\nbuild: generate\n",
+			"config/crd/b.yaml": "kind: B\n",
+		}, "go.sum")).To(MatchError("files differ from the golden files in " + dir + ", set UPDATE_GOLDEN=1 " +
+			"to update them:\n" +
+			`Makefile: line 3: expected "build:", got "build: generate"` + "\n" +
+			"config/crd/a.yaml: missing\n" +
+			"config/crd/b.yaml: unexpected"))
+	})
+
+	It("should describe the lines missing at the end of a file", func() {
+		Expect(CompareGolden(dir, map[string]string{
+			"Makefile":          "all: build",
+			"config/crd/a.yaml": "kind: A\n",
+		}, "go.sum")).To(MatchError(ContainSubstring(`Makefile: line 2: missing ""`)))
+	})
+
+	It("should write the golden files if requested", func() {
+		Expect(os.Setenv(UpdateGoldenEnvVar, "1")).To(Succeed())
+		Expect(CompareGolden(dir, map[string]string{
+			"Makefile": "all:\n",
+			"bin/tool": "binary",
+		}, "bin/*")).To(Succeed())
+
+		Expect(os.Unsetenv(UpdateGoldenEnvVar)).To(Succeed())
+		Expect((&Project{Dir: dir}).Files()).To(Equal(map[string]string{"Makefile": "all:\n"}))
+		_, err := os.Stat(filepath.Join(dir, "bin"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("should reject invalid ignore patterns", func() {
+		Expect(CompareGolden(dir, map[string]string{"Makefile": ""}, "[")).To(MatchError(
+			`invalid ignore pattern "[": syntax error in pattern`))
+	})
+})
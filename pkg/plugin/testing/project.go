@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Project is a temporary project directory that plugins are run against. It stands in for the filesystem of a
+// project: it can be seeded with files before running the plugins, and its files read after.
+type Project struct {
+	// Dir is the directory of the project, the current directory until the project is closed.
+	Dir string
+
+	// wd is the directory that was current when the project was created.
+	wd string
+}
+
+// NewProject creates an empty project in a temporary directory and changes the current directory to it.
+func NewProject() (*Project, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := ioutil.TempDir("", "kubebuilder-plugin-test")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chdir(dir); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+	return &Project{Dir: dir, wd: wd}, nil
+}
+
+// Close changes the current directory back to the one the project was created from and removes the project.
+func (p *Project) Close() error {
+	if err := os.Chdir(p.wd); err != nil {
+		return err
+	}
+	return os.RemoveAll(p.Dir)
+}
+
+// WriteFiles writes files, by slash-separated path relative to the project, with their contents, e.g. to seed the
+// project with the files a plugin expects.
+func (p *Project) WriteFiles(files map[string]string) error {
+	for path, contents := range files {
+		path = filepath.Join(p.Dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadFile returns the contents of the file at path, slash-separated and relative to the project.
+func (p *Project) ReadFile(path string) (string, error) {
+	bs, err := ioutil.ReadFile(filepath.Join(p.Dir, filepath.FromSlash(path)))
+	return string(bs), err
+}
+
+// Files returns the contents of every file of the project, by slash-separated path relative to the project.
+func (p *Project) Files() (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.Walk(p.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		bs, err := ioutil.ReadFile(path) //nolint:gosec
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(p.Dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(bs)
+		return nil
+	})
+	return files, err
+}
+
+// Paths returns the sorted paths of files.
+func Paths(files map[string]string) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
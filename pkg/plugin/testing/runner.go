@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	internalconfig "sigs.k8s.io/kubebuilder/internal/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+// Runner runs the subcommands of a chain of plugins in the current directory, e.g. a Project, the way the CLI
+// runs them: their flags are parsed from the arguments, they are validated, they scaffold and the config is saved.
+type Runner struct {
+	// Plugins are chained in order for every subcommand, the plugins of bundles being expanded.
+	Plugins []plugin.Base
+	// ProjectVersion is the version of the config created by init, config.Version3Alpha by default.
+	ProjectVersion string
+	// CommandName is the name of the CLI in the help text of the subcommands, "kubebuilder" by default.
+	CommandName string
+	// PostScaffold runs the post-scaffolding phase too, which is skipped by default as it usually runs commands
+	// on the scaffolded project, e.g. go mod tidy.
+	PostScaffold bool
+}
+
+// NewRunner returns a Runner chaining plugins.
+func NewRunner(plugins ...plugin.Base) *Runner {
+	return &Runner{Plugins: plugins}
+}
+
+// Run runs the subcommands of the plugins for command with args, as flags. The command is either "init",
+// "create api", "create webhook", "delete api", "delete webhook" or the path of a command contributed by the
+// plugins, e.g. "create channel". Every command but init requires the project to be initialized.
+func (r *Runner) Run(command string, args ...string) error {
+	subcommands := r.subcommands(command)
+	if len(subcommands) == 0 {
+		return fmt.Errorf("none of the plugins implements %q", command)
+	}
+
+	var cfg *internalconfig.Config
+	if command == "init" {
+		if _, err := internalconfig.Read(); err == nil {
+			return fmt.Errorf("config already initialized")
+		}
+		cfg = internalconfig.New(internalconfig.DefaultPath)
+		cfg.Version = r.ProjectVersion
+		if cfg.Version == "" {
+			cfg.Version = config.Version3Alpha
+		}
+	} else {
+		var err error
+		if cfg, err = internalconfig.LoadInitialized(); err != nil {
+			return err
+		}
+	}
+
+	commandName := r.CommandName
+	if commandName == "" {
+		commandName = "kubebuilder"
+	}
+	ctx := &plugin.Context{CommandName: commandName}
+	fs := pflag.NewFlagSet(command, pflag.ContinueOnError)
+	for _, subcommand := range subcommands {
+		subcommand.InjectConfig(&cfg.Config)
+		subcommand.BindFlags(fs)
+		subcommand.UpdateContext(ctx)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// The layout records the whole plugin chain, so that later commands chain the same plugins
+	if command == "init" && cfg.IsV3() {
+		keys := make([]string, 0, len(r.Plugins))
+		for _, p := range r.Plugins {
+			keys = append(keys, plugin.KeyFor(p))
+		}
+		cfg.Layout = strings.Join(keys, ",")
+	}
+
+	for _, subcommand := range subcommands {
+		if err := subcommand.Validate(); err != nil {
+			return fmt.Errorf("%s: %v", command, err)
+		}
+	}
+	for _, subcommand := range subcommands {
+		if err := subcommand.Scaffold(); err != nil {
+			return fmt.Errorf("%s: %v", command, err)
+		}
+	}
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+	if !r.PostScaffold {
+		return nil
+	}
+	for _, subcommand := range subcommands {
+		if err := subcommand.PostScaffold(); err != nil {
+			return fmt.Errorf("%s: %v", command, err)
+		}
+	}
+	return nil
+}
+
+// subcommands returns the subcommands of the plugins for command, in the order they are chained.
+func (r *Runner) subcommands(command string) []plugin.GenericSubcommand {
+	var subcommands []plugin.GenericSubcommand
+	for _, p := range expand(r.Plugins) {
+		var subcommand plugin.GenericSubcommand
+		switch command {
+		case "init":
+			if getter, ok := p.(plugin.InitPluginGetter); ok {
+				subcommand = getter.GetInitPlugin()
+			}
+		case "create api":
+			if getter, ok := p.(plugin.CreateAPIPluginGetter); ok {
+				subcommand = getter.GetCreateAPIPlugin()
+			}
+		case "create webhook":
+			if getter, ok := p.(plugin.CreateWebhookPluginGetter); ok {
+				subcommand = getter.GetCreateWebhookPlugin()
+			}
+		case "delete api":
+			if getter, ok := p.(plugin.DeleteAPIPluginGetter); ok {
+				subcommand = getter.GetDeleteAPIPlugin()
+			}
+		case "delete webhook":
+			if getter, ok := p.(plugin.DeleteWebhookPluginGetter); ok {
+				subcommand = getter.GetDeleteWebhookPlugin()
+			}
+		default:
+			if getter, ok := p.(plugin.CommandsPluginGetter); ok {
+				for _, c := range getter.GetCommands() {
+					if c.Path == command {
+						subcommand = c.Subcommand
+					}
+				}
+			}
+		}
+		if subcommand != nil {
+			subcommands = append(subcommands, subcommand)
+		}
+	}
+	return subcommands
+}
+
+// expand returns plugins with the plugins of bundles in their place.
+func expand(plugins []plugin.Base) []plugin.Base {
+	var expanded []plugin.Base
+	for _, p := range plugins {
+		if bundle, isBundle := p.(plugin.Bundle); isBundle {
+			expanded = append(expanded, expand(bundle.Plugins())...)
+			continue
+		}
+		expanded = append(expanded, p)
+	}
+	return expanded
+}
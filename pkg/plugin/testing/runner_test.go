@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+// fakePlugin writes a file per subcommand, named after the flags it is run with.
+type fakePlugin struct {
+	name string
+	init *fakeSubcommand
+	api  *fakeSubcommand
+}
+
+func (p fakePlugin) Name() string                         { return p.name }
+func (p fakePlugin) Version() plugin.Version              { return plugin.Version{Number: 1} }
+func (p fakePlugin) SupportedProjectVersions() []string   { return []string{config.Version3Alpha} }
+func (p fakePlugin) GetInitPlugin() plugin.Init           { return p.init }
+func (p fakePlugin) GetCreateAPIPlugin() plugin.CreateAPI { return p.api }
+
+type fakeSubcommand struct {
+	config       *config.Config
+	file         string
+	kind         string
+	postScaffold bool
+	invalid      bool
+}
+
+func (s *fakeSubcommand) UpdateContext(*plugin.Context) {}
+func (s *fakeSubcommand) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.kind, s.file+"-kind", "", "")
+}
+func (s *fakeSubcommand) InjectConfig(c *config.Config) { s.config = c }
+func (s *fakeSubcommand) Validate() error {
+	if s.invalid {
+		return errors.New("invalid")
+	}
+	return nil
+}
+func (s *fakeSubcommand) Scaffold() error {
+	s.config.Domain = "example.org"
+	return ioutil.WriteFile(s.file, []byte("kind: "+s.kind+"\n"), 0644)
+}
+func (s *fakeSubcommand) PostScaffold() error {
+	s.postScaffold = true
+	return nil
+}
+
+var _ = Describe("Runner", func() {
+	var (
+		project *Project
+		base    fakePlugin
+		other   fakePlugin
+	)
+
+	BeforeEach(func() {
+		var err error
+		project, err = NewProject()
+		Expect(err).NotTo(HaveOccurred())
+
+		base = fakePlugin{name: "base.example.org",
+			init: &fakeSubcommand{file: "base"}, api: &fakeSubcommand{file: "base-api"}}
+		other = fakePlugin{name: "other.example.org",
+			init: &fakeSubcommand{file: "other"}, api: &fakeSubcommand{file: "other-api"}}
+	})
+
+	AfterEach(func() {
+		Expect(project.Close()).To(Succeed())
+	})
+
+	It("should run the subcommands of the chained plugins in the project", func() {
+		wd, err := os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		dir, err := filepath.EvalSymlinks(project.Dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filepath.EvalSymlinks(wd)).To(Equal(dir))
+
+		runner := NewRunner(base, other)
+		Expect(runner.Run("init", "--base-kind=Init", "--other-kind=Other")).To(Succeed())
+		Expect(runner.Run("create api", "--base-api-kind=Captain")).To(Succeed())
+
+		files, err := project.Files()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(files).To(Equal(map[string]string{
+			"PROJECT":   "domain: example.org\nlayout: base.example.org/v1,other.example.org/v1\nversion: 3-alpha\n",
+			"base":      "kind: Init\n",
+			"other":     "kind: Other\n",
+			"base-api":  "kind: Captain\n",
+			"other-api": "kind: \n",
+		}))
+		Expect(base.init.postScaffold).To(BeFalse())
+	})
+
+	It("should only post-scaffold if requested", func() {
+		runner := NewRunner(base)
+		runner.PostScaffold = true
+		Expect(runner.Run("init")).To(Succeed())
+		Expect(base.init.postScaffold).To(BeTrue())
+	})
+
+	It("should not scaffold anything if a subcommand is invalid", func() {
+		other.init.invalid = true
+		Expect(NewRunner(base, other).Run("init")).To(MatchError("init: invalid"))
+		Expect(project.Files()).To(BeEmpty())
+	})
+
+	It("should fail for the commands the plugins do not implement or that need a project", func() {
+		Expect(NewRunner(base).Run("create webhook")).To(MatchError(`none of the plugins implements "create webhook"`))
+		Expect(NewRunner(base).Run("create api")).To(MatchError(
+			"unable to find configuration file, project must be initialized"))
+		Expect(NewRunner(base).Run("init", "--unknown")).To(MatchError("unknown flag: --unknown"))
+	})
+
+	It("should expand bundles", func() {
+		bundle, err := plugin.NewBundle("bundle.example.org", plugin.Version{Number: 1}, base, other)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(NewRunner(bundle).Run("init")).To(Succeed())
+		Expect(project.ReadFile("other")).To(Equal("kind: \n"))
+		Expect(project.ReadFile("PROJECT")).To(ContainSubstring("layout: bundle.example.org/v1\n"))
+	})
+})
@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/tilt/v1/scaffolds"
+)
+
+type initSubcommand struct {
+	config *config.Config
+
+	// kindCluster is the name of the kind cluster used by the dev-up and dev-down targets by default
+	kindCluster string
+}
+
+var (
+	_ plugin.Init        = &initSubcommand{}
+	_ cmdutil.RunOptions = &initSubcommand{}
+)
+
+func (p *initSubcommand) UpdateContext(ctx *plugin.Context) {
+	ctx.Description += `
+Writes the following files for live development with Tilt (https://tilt.dev) in a kind cluster:
+- a Tiltfile rebuilding the manager on changes and syncing it into its running container
+- the dev-up and dev-down Makefile targets, creating the kind cluster and running Tilt, and tearing them down
+`
+}
+
+func (p *initSubcommand) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&p.kindCluster, "kind-cluster", "",
+		"name of the kind cluster the manager is developed in, defaults to the project name with a -dev suffix")
+}
+
+func (p *initSubcommand) InjectConfig(c *config.Config) {
+	p.config = c
+}
+
+func (p *initSubcommand) Scaffold() error {
+	return cmdutil.Scaffold(p)
+}
+
+func (p *initSubcommand) Validate() error {
+	// The project name is usually set by the base plugin, as it is the prefix of the manifests' names.
+	if p.config.ProjectName == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("error getting current directory: %v", err)
+		}
+		p.config.ProjectName = strings.ToLower(filepath.Base(dir))
+	}
+	if p.kindCluster == "" {
+		p.kindCluster = p.config.ProjectName + "-dev"
+	}
+	if err := validation.IsDNS1123Label(p.kindCluster); err != nil {
+		return fmt.Errorf("kind cluster name (%s) is invalid: %v", p.kindCluster, err)
+	}
+	return nil
+}
+
+func (p *initSubcommand) GetScaffolder() (scaffold.Scaffolder, error) {
+	return scaffolds.NewInitScaffolder(p.config, p.kindCluster), nil
+}
+
+func (p *initSubcommand) PostScaffold() error {
+	logger.Default().Info("Next: develop the manager in a kind cluster, with kind and tilt installed, with:\n" +
+		"$ make dev-up")
+	return nil
+}
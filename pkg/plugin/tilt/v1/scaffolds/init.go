@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/tilt/v1/scaffolds/internal/templates"
+)
+
+var _ scaffold.Scaffolder = &initScaffolder{}
+
+type initScaffolder struct {
+	config *config.Config
+
+	// kindCluster is the name of the kind cluster used by the dev-up and dev-down targets by default
+	kindCluster string
+}
+
+// NewInitScaffolder returns a new Scaffolder for the Tilt live development setup of a project
+func NewInitScaffolder(config *config.Config, kindCluster string) scaffold.Scaffolder {
+	return &initScaffolder{
+		config:      config,
+		kindCluster: kindCluster,
+	}
+}
+
+// Scaffold implements Scaffolder
+func (s *initScaffolder) Scaffold() error {
+	logger.Default().Info("Writing Tiltfile for you to edit...")
+
+	if err := machinery.NewScaffold().Execute(
+		model.NewUniverse(model.WithConfig(s.config)),
+		&templates.Tiltfile{
+			NamePrefix:  s.namePrefix(),
+			KindCluster: s.kindCluster,
+			ImageName:   config.ImageName(s.config.GetManifestImage()),
+		},
+	); err != nil {
+		return err
+	}
+
+	return updateMakefile("Makefile", s.kindCluster)
+}
+
+// namePrefix returns the prefix of the names of the resources deployed by the manifests, set by the kustomize
+// plugin chained before this one, or else the default one of the kustomize plugins
+func (s *initScaffolder) namePrefix() string {
+	if s.config.NamePrefix != "" {
+		return s.config.NamePrefix
+	}
+	return s.config.ProjectName + "-"
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Tiltfile{}
+
+// Tiltfile scaffolds the Tiltfile running the manager in a kind cluster
+type Tiltfile struct {
+	file.TemplateMixin
+
+	// NamePrefix is the prefix of the names of the resources deployed by the manifests
+	NamePrefix string
+
+	// KindCluster is the name of the kind cluster Tilt deploys to, unless KIND_CLUSTER is set
+	KindCluster string
+
+	// ImageName is the name of the image of the manager manifest, which Tilt replaces with the image it builds
+	ImageName string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Tiltfile) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = "Tiltfile"
+	}
+
+	f.TemplateBody = tiltfileTemplate
+
+	if f.ImageName == "" {
+		f.ImageName = "controller"
+	}
+
+	// A Tiltfile tuned by the project is kept
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+const tiltfileTemplate = `# -*- mode: Python -*-
+# Runs the manager in a kind cluster and redeploys it on changes, started by 'make dev-up'.
+# See https://docs.tilt.dev for the Tiltfile API.
+
+load('ext://restart_process', 'docker_build_with_restart')
+
+kind_cluster = os.getenv('KIND_CLUSTER', '{{ .KindCluster }}')
+allow_k8s_contexts('kind-' + kind_cluster)
+
+# The API packages depend on whether the project is multi-group
+api_dirs = [d for d in ['api', 'apis'] if os.path.exists(d)]
+
+# The manifests are generated, and the kustomize binary built, before they are read
+local('make manifests kustomize')
+
+# The image starts the manager through the restart wrapper, so the command of the manager container is removed.
+# Syncing the binary requires a writable root filesystem, the manager can not run with readOnlyRootFilesystem.
+objects = decode_yaml_stream(local('bin/kustomize build config/default'))
+for o in objects:
+    if o['kind'] == 'Deployment':
+        for c in o['spec']['template']['spec']['containers']:
+            if c['name'] == 'manager':
+                c.pop('command', None)
+k8s_yaml(encode_yaml_stream(objects))
+
+# Regenerate the code and the manifests when the APIs change
+local_resource(
+    'generate',
+    'make generate manifests',
+    deps=api_dirs,
+    ignore=['**/zz_generated.*'],
+)
+
+# The manager is built on the host, only its binary is synced into the running container
+local_resource(
+    'manager-binary',
+    'CGO_ENABLED=0 GOOS=linux go build -o bin/tilt/manager main.go',
+    deps=['main.go', 'go.mod', 'go.sum', 'controllers'] + api_dirs,
+    resource_deps=['generate'],
+)
+
+docker_build_with_restart(
+    '{{ .ImageName }}',
+    'bin/tilt',
+    dockerfile_contents="""FROM alpine:3.12
+WORKDIR /workspace
+COPY manager /workspace/manager
+RUN chown -R 65532:65532 /workspace
+""",
+    entrypoint=['/workspace/manager'],
+    only=['manager'],
+    live_update=[sync('bin/tilt/manager', '/workspace/manager')],
+)
+
+k8s_resource('{{ .NamePrefix }}controller-manager', resource_deps=['manager-binary'])
+`
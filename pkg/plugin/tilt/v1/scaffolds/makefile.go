@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+)
+
+// devUpTargetRe matches the dev-up target of a Makefile
+var devUpTargetRe = regexp.MustCompile(`(?m)^dev-up:`)
+
+// devTargets are the Makefile targets running the manager in a kind cluster with Tilt
+const devTargets = `
+# Name of the kind cluster the manager is developed in with Tilt
+KIND_CLUSTER ?= %s
+# Create the kind cluster, unless it exists, and run the manager in it with Tilt, redeploying it on changes
+dev-up:
+	kind get clusters | grep -qx $(KIND_CLUSTER) || kind create cluster --name $(KIND_CLUSTER)
+	kubectl config use-context kind-$(KIND_CLUSTER)
+	KIND_CLUSTER=$(KIND_CLUSTER) tilt up
+
+# Remove the resources deployed by Tilt and delete the kind cluster
+dev-down:
+	KIND_CLUSTER=$(KIND_CLUSTER) tilt down
+	kind delete cluster --name $(KIND_CLUSTER)
+`
+
+// updateMakefile appends the dev-up and dev-down targets to the Makefile written by the base plugin, unless it
+// already has them.
+func updateMakefile(path, kindCluster string) error {
+	bs, err := ioutil.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		logger.Default().Info(fmt.Sprintf("%s does not exist, run 'tilt up' in your kind cluster instead of "+
+			"'make dev-up'", path))
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if devUpTargetRe.Match(bs) {
+		return nil
+	}
+
+	updated := append(bytes.TrimRight(bs, "\n"), '\n')
+	updated = append(updated, fmt.Sprintf(devTargets, kindCluster)...)
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(path, updated, 0644)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("updateMakefile", func() {
+	var dir, path string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "tilt-scaffolds")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "Makefile")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should append the dev targets once", func() {
+		Expect(ioutil.WriteFile(path, []byte("all: manager\n\n"), 0644)).To(Succeed())
+
+		Expect(updateMakefile(path, "project-dev")).To(Succeed())
+		Expect(updateMakefile(path, "other-dev")).To(Succeed())
+
+		bs, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(bs)).To(HavePrefix("all: manager\n\n# Name of the kind cluster"))
+		Expect(string(bs)).To(ContainSubstring("KIND_CLUSTER ?= project-dev\n"))
+		Expect(string(bs)).NotTo(ContainSubstring("other-dev"))
+	})
+
+	It("should not create a Makefile", func() {
+		Expect(updateMakefile(path, "project-dev")).To(Succeed())
+		_, err := os.Stat(path)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
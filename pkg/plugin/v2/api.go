@@ -122,8 +122,8 @@ func (p *createAPIPlugin) InjectConfig(c *config.Config) {
 	p.config = c
 }
 
-func (p *createAPIPlugin) Run() error {
-	return cmdutil.Run(p)
+func (p *createAPIPlugin) Scaffold() error {
+	return cmdutil.Scaffold(p)
 }
 
 func (p *createAPIPlugin) Validate() error {
@@ -26,6 +26,7 @@ import (
 
 	"sigs.k8s.io/kubebuilder/internal/cmdutil"
 	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/plugin"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
@@ -104,8 +105,8 @@ func (p *initPlugin) InjectConfig(c *config.Config) {
 	p.config = c
 }
 
-func (p *initPlugin) Run() error {
-	return cmdutil.Run(p)
+func (p *initPlugin) Scaffold() error {
+	return cmdutil.Scaffold(p)
 }
 
 func (p *initPlugin) Validate() error {
@@ -151,7 +152,7 @@ func (p *initPlugin) GetScaffolder() (scaffold.Scaffolder, error) {
 
 func (p *initPlugin) PostScaffold() error {
 	if !p.fetchDeps {
-		fmt.Println("Skipping fetching dependencies.")
+		logger.Default().Info("Skipping fetching dependencies.")
 		return nil
 	}
 
@@ -173,6 +174,6 @@ func (p *initPlugin) PostScaffold() error {
 		return err
 	}
 
-	fmt.Printf("Next: define a resource with:\n$ %s create api\n", p.commandName)
+	logger.Default().Info(fmt.Sprintf("Next: define a resource with:\n$ %s create api", p.commandName))
 	return nil
 }
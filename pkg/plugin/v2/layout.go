@@ -0,0 +1,56 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+var _ plugin.LayoutDescriber = Plugin{}
+
+// DescribeLayout implements plugin.LayoutDescriber
+func (Plugin) DescribeLayout(c *config.Config) map[string]string {
+	layout := map[string]string{
+		".gitignore":              "files excluded from version control, like binaries",
+		"Dockerfile":              "multi-stage build of the manager image",
+		"Makefile":                "targets to generate code and manifests, test, build, run and deploy the manager",
+		"go.mod":                  "Go module of the project, pinning controller-runtime",
+		"main.go":                 "entrypoint of the manager, which registers the controllers and webhooks",
+		"hack":                    "helper files for development",
+		"hack/boilerplate.go.txt": "license header prepended to generated and scaffolded Go files",
+		"controllers":             "reconcilers of the project resources and the envtest suite that tests them",
+		"config":                  "kustomize manifests to deploy the manager",
+		"config/default":          "kustomization deploying the whole project, where optional components are enabled",
+		"config/manager":          "Deployment of the manager and the namespace it runs in",
+		"config/crd":              "CRDs generated by controller-gen and patches for conversion webhooks and CA injection",
+		"config/rbac":             "controller-gen generated roles, their bindings and editor and viewer roles per resource",
+		"config/samples":          "sample custom resources, one per resource",
+		"config/webhook":          "webhook configurations generated by controller-gen and the Service of the webhook server",
+		"config/certmanager":      "cert-manager Issuer and Certificate providing the webhook server certificate",
+		"config/prometheus":       "Prometheus Operator ServiceMonitor scraping the metrics of the manager",
+	}
+	if c.MultiGroup {
+		layout["apis"] = "Go types of the project resources, one package per group and version"
+		layout["controllers"] = "reconcilers of the project resources, one package per group, " +
+			"each with the envtest suite that tests them"
+	} else {
+		layout["api"] = "Go types of the project resources, one package per version, " +
+			"along with their webhooks and the registration of their group version"
+	}
+	return layout
+}
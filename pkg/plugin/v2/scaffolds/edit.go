@@ -21,7 +21,9 @@ import (
 	"io/ioutil"
 	"strings"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
 )
 
@@ -30,18 +32,31 @@ var _ scaffold.Scaffolder = &editScaffolder{}
 type editScaffolder struct {
 	config     *config.Config
 	multigroup bool
+	domain     string
 }
 
-// NewEditScaffolder returns a new Scaffolder for configuration edit operations
-func NewEditScaffolder(config *config.Config, multigroup bool) scaffold.Scaffolder {
+// NewEditScaffolder returns a new Scaffolder for configuration edit operations.
+// An empty domain keeps the domain of the project.
+func NewEditScaffolder(config *config.Config, multigroup bool, domain string) scaffold.Scaffolder {
 	return &editScaffolder{
 		config:     config,
 		multigroup: multigroup,
+		domain:     domain,
 	}
 }
 
 // Scaffold implements Scaffolder
 func (s *editScaffolder) Scaffold() error {
+	if s.domain != "" && s.domain != s.config.Domain {
+		if err := s.changeDomain(); err != nil {
+			return err
+		}
+		// The Dockerfile is only updated when the layout changes
+		if s.multigroup == s.config.MultiGroup {
+			return nil
+		}
+	}
+
 	s.config.MultiGroup = s.multigroup
 	filename := "Dockerfile"
 	bs, err := ioutil.ReadFile(filename)
@@ -73,6 +88,36 @@ func (s *editScaffolder) Scaffold() error {
 	return ioutil.WriteFile(filename, []byte(str), 0644)
 }
 
+// changeDomain moves the groups of the tracked resources to the new domain
+func (s *editScaffolder) changeDomain() error {
+	groups := make([]string, 0, len(s.config.Resources))
+	seen := make(map[string]bool, len(s.config.Resources))
+	for _, res := range s.config.Resources {
+		if res.Group != "" && !seen[res.Group] {
+			seen[res.Group] = true
+			groups = append(groups, res.Group)
+		}
+	}
+
+	pending, err := util.ChangeDomain(".", s.config.Repo, groups, s.config.Domain, s.domain)
+	if err != nil {
+		return err
+	}
+	// The PROJECT file is saved with the new domain afterwards
+	for i, path := range pending {
+		if path == "PROJECT" {
+			pending = append(pending[:i], pending[i+1:]...)
+			break
+		}
+	}
+	if len(pending) != 0 {
+		logger.Default().Info(fmt.Sprintf("The following files still reference the domain %q, "+
+			"review them manually:\n%s", s.config.Domain, strings.Join(pending, "\n")))
+	}
+	s.config.Domain = s.domain
+	return nil
+}
+
 func ensureExistAndReplace(input, match, replace string) (string, error) {
 	if !strings.Contains(input, match) {
 		return "", fmt.Errorf("can't find %q", match)
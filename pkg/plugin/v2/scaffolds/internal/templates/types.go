@@ -17,9 +17,9 @@ limitations under the License.
 package templates
 
 import (
-	"fmt"
 	"path/filepath"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
 )
 
@@ -43,7 +43,7 @@ func (f *Types) SetTemplateDefaults() error {
 		}
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-	fmt.Println(f.Path)
+	logger.Default().Info(f.Path)
 
 	f.TemplateBody = typesTemplate
 
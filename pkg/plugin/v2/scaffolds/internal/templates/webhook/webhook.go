@@ -17,10 +17,10 @@ limitations under the License.
 package webhook
 
 import (
-	"fmt"
 	"path/filepath"
 	"strings"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
 )
 
@@ -52,7 +52,7 @@ func (f *Webhook) SetTemplateDefaults() error {
 		}
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-	fmt.Println(f.Path)
+	logger.Default().Info(f.Path)
 
 	webhookTemplate := webhookTemplate
 	if f.Defaulting {
@@ -19,10 +19,11 @@ package scaffolds
 import (
 	"fmt"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/model/resource"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/machinery"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/v2/scaffolds/internal/templates"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/v2/scaffolds/internal/templates/webhook"
@@ -60,7 +61,7 @@ func NewWebhookScaffolder(
 
 // Scaffold implements Scaffolder
 func (s *webhookScaffolder) Scaffold() error {
-	fmt.Println("Writing scaffold for you to edit...")
+	logger.Default().Info("Writing scaffold for you to edit...")
 
 	switch {
 	case s.config.IsV2(), s.config.IsV3():
@@ -80,7 +81,7 @@ func (s *webhookScaffolder) newUniverse() *model.Universe {
 
 func (s *webhookScaffolder) scaffold() error {
 	if s.conversion {
-		fmt.Println(`Webhook server has been set up for you.
+		logger.Default().Info(`Webhook server has been set up for you.
 You need to implement the conversion.Hub and conversion.Convertible interfaces for your CRD types.`)
 	}
 
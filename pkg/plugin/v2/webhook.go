@@ -82,8 +82,8 @@ func (p *createWebhookPlugin) InjectConfig(c *config.Config) {
 	p.config = c
 }
 
-func (p *createWebhookPlugin) Run() error {
-	return cmdutil.Run(p)
+func (p *createWebhookPlugin) Scaffold() error {
+	return cmdutil.Scaffold(p)
 }
 
 func (p *createWebhookPlugin) Validate() error {
@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/model/resource"
@@ -36,6 +37,8 @@ import (
 	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds"
 	"sigs.k8s.io/kubebuilder/plugins/addon"
+	"sigs.k8s.io/kubebuilder/plugins/deployimage"
+	"sigs.k8s.io/kubebuilder/plugins/helm"
 )
 
 // (used only to gen api with --pattern=addon)
@@ -43,13 +46,32 @@ import (
 // TODO: remove this when a better solution for using addons is implemented.
 const KbDeclarativePatternVersion = "v0.0.0-20200522144838-848d48e5b073"
 
+// HelmVersion is the helm.sh/helm/v3 version rendering the charts of the APIs generated with --pattern=helm, the
+// last one built with the Kubernetes libraries required by controller-runtime
+const HelmVersion = "v3.3.4"
+
+// The strategies of the scaffolded controller to write the objects it reconciles
+const (
+	// reconcileStrategyUpdate leaves the controller to get, mutate and update the objects
+	reconcileStrategyUpdate = "update"
+	// reconcileStrategySSA makes the controller server-side apply the fields it owns with apply configurations
+	reconcileStrategySSA = "ssa"
+)
+
 type createAPIPlugin struct {
 	config *config.Config
 
 	// pattern indicates that we should use a plugin to build according to a pattern
 	pattern string
 
+	// image is the container image that the scaffolded controller deploys, if any
+	image string
+	// imageContainerPort is the port exposed by image, a Service is only scaffolded if it is set
+	imageContainerPort int
+
 	resource *resource.Options
+	// crdVersionFlag is not changed if the CRD version is the one of the project
+	crdVersionFlag *pflag.Flag
 
 	// Check if we have to scaffold resource and/or controller
 	resourceFlag   *pflag.Flag
@@ -57,11 +79,52 @@ type createAPIPlugin struct {
 	doResource     bool
 	doController   bool
 
-	// force indicates that the resource should be created even if it already exists
+	// force indicates that the resource should be created even if it already exists or its Kind collides with
+	// another one, the files of an existing resource are kept and only its missing Go files are scaffolded
 	force bool
 
+	// withPredicates indicates whether to filter the events that trigger reconciles in the scaffolded controller
+	withPredicates bool
+
+	// validationMarkers indicates whether to add example field validation markers and CEL validation rules to the
+	// scaffolded types, along with the tests asserting that the API server enforces them
+	validationMarkers bool
+
+	// reconcileStrategy is how the scaffolded controller writes the objects it reconciles
+	reconcileStrategy string
+
+	// serverSideApply indicates whether to generate the apply configuration of the resource, with which the
+	// scaffolded controller server-side applies the fields it owns, it is set by the ssa strategy and by the
+	// deprecated --ssa alias of it
+	serverSideApply bool
+
+	// withEvents indicates whether the scaffolded controller records events on the objects it reconciles with an
+	// EventRecorder, the RBAC rules of the events being added to its role
+	withEvents bool
+
+	// withControllerOptions indicates whether the concurrency and rate limiting options of the scaffolded
+	// controller are bound to flags of main.go
+	withControllerOptions bool
+
+	// withConditions indicates whether to add the Conditions of the standard Ready, Progressing and Degraded types
+	// to the status of the resource, along with the helpers setting them, maintained by the scaffolded controller
+	withConditions bool
+
+	// withMetrics indicates whether to register example custom Prometheus metrics in monitoring/metrics.go, the
+	// scaffolded controller counting the outcomes of its reconciliations with them
+	withMetrics bool
+
+	// values are the key=value pairs of the --set flags, overriding the ones of the config for this command
+	values    []string
+	valuesMap map[string]string
+
+	// skipGoMod indicates whether to leave go.mod and go.sum untouched, the dependencies of a --pattern not being
+	// added to them, which is always the case in projects initialized with --skip-go-mod-tidy
+	skipGoMod bool
+
 	// runMake indicates whether to run make or not after scaffolding APIs
-	runMake bool
+	runMake     bool
+	runMakeFlag *pflag.Flag
 }
 
 var (
@@ -76,11 +139,63 @@ create resource will prompt the user for if it should scaffold the Resource and
 scaffold a Controller for an existing Resource, select "n" for Resource.  To only define
 the schema for a Resource without writing a Controller, select "n" for Controller.
 
+Along with the first Controller of its directory, a samples_test.go creates the samples of config/samples with a
+dry run against the CRDs loaded by the envtest suite, so that 'make test' fails once they are no longer valid.
+
 After the scaffold is written, api will run make on the project.
 `
 	ctx.Examples = fmt.Sprintf(`  # Create a frigates API with Group: ship, Version: v1beta1 and Kind: Frigate
   %s create api --group ship --version v1beta1 --kind Frigate
 
+  # Create a frigates API whose CRD manifest uses apiextensions.k8s.io/v1
+  %s create api --group ship --version v1beta1 --kind Frigate --crd-version=v1
+
+  # Create a Proxy API whose resource name is not the computed plural "proxies"
+  %s create api --group net --version v1 --kind Proxy --plural=proxyentries
+
+  # Create a frigates API whose controller filters the events that trigger its reconciles
+  %s create api --group ship --version v1beta1 --kind Frigate --with-predicates
+
+  # Create a frigates API whose controller records events on the frigates, shown by kubectl describe
+  %s create api --group ship --version v1beta1 --kind Frigate --with-events
+
+  # Create a frigates API whose controller reconciles up to 4 frigates concurrently when run with
+  # --frigate-max-concurrent-reconciles=4
+  %s create api --group ship --version v1beta1 --kind Frigate --with-controller-options
+
+  # Create a frigates API whose controller counts the outcomes of its reconciles with the custom metrics
+  # of monitoring/metrics.go
+  %s create api --group ship --version v1beta1 --kind Frigate --with-metrics
+
+  # Create a frigates API whose status has Ready, Progressing and Degraded conditions, maintained by its controller
+  %s create api --group ship --version v1beta1 --kind Frigate --with-conditions
+
+  # Create a frigates API whose Spec is validated by the API server with validation markers and CEL rules
+  %s create api --group ship --version v1beta1 --kind Frigate --crd-version=v1 --with-validation-markers
+
+  # Create a frigates API whose fields are defaulted by the API server from +kubebuilder:default markers
+  %s create api --group ship --version v1beta1 --kind Frigate --crd-version=v1 --defaults=markers
+
+  # Create a frigates API whose fields are defaulted by a defaulting webhook
+  %s create api --group ship --version v1beta1 --kind Frigate --defaults=webhook
+
+  # Create a frigates API whose controller server-side applies the fields it owns with generated apply configurations
+  %s create api --group ship --version v1beta1 --kind Frigate --reconcile-strategy=ssa
+
+  # Create a controller for the core type Pod
+  %s create api --group core --version v1 --kind Pod --resource=false --controller
+
+  # Create a controller for an external type, e.g. cert-manager's Certificate
+  %s create api --group cert-manager --version v1 --kind Certificate --controller \
+    --external-api-path=github.com/jetstack/cert-manager/pkg/apis/certmanager/v1 --external-api-domain=io
+
+  # Create a Memcached API whose controller deploys the memcached image exposing the port 11211
+  %s create api --group cache --version v1alpha1 --kind Memcached \
+    --image=memcached:1.4.36-alpine --image-container-port=11211
+
+  # Scaffold again the deleted controller of the existing frigates API, keeping its other files
+  %s create api --group ship --version v1beta1 --kind Frigate --force
+
   # Edit the API Scheme
   nano api/v1beta1/frigate_types.go
 
@@ -96,11 +211,15 @@ After the scaffold is written, api will run make on the project.
   # Regenerate code and run against the Kubernetes cluster configured by ~/.kube/config
   make run
 	`,
-		ctx.CommandName)
+		ctx.CommandName, ctx.CommandName, ctx.CommandName, ctx.CommandName, ctx.CommandName, ctx.CommandName,
+		ctx.CommandName, ctx.CommandName, ctx.CommandName, ctx.CommandName, ctx.CommandName, ctx.CommandName,
+		ctx.CommandName, ctx.CommandName, ctx.CommandName, ctx.CommandName)
 }
 
 func (p *createAPIPlugin) BindFlags(fs *pflag.FlagSet) {
-	fs.BoolVar(&p.runMake, "make", true, "if true, run make after generating files")
+	fs.BoolVar(&p.runMake, "make", true, "if true, run make after generating files, defaults to false "+
+		"for projects scaffolded in offline mode")
+	p.runMakeFlag = fs.Lookup("make")
 
 	fs.BoolVar(&p.doResource, "resource", true,
 		"if set, generate the resource without prompting the user")
@@ -112,35 +231,216 @@ func (p *createAPIPlugin) BindFlags(fs *pflag.FlagSet) {
 	// TODO: remove this when a better solution for using addons is implemented.
 	if os.Getenv("KUBEBUILDER_ENABLE_PLUGINS") != "" {
 		fs.StringVar(&p.pattern, "pattern", "",
-			"generates an API following an extension pattern (addon)")
+			"generates an API following an extension pattern, either \"addon\" or \"helm\" to reconcile the "+
+				"objects of the API by rendering a Helm chart in helm-charts/ with the values of their spec")
 	}
 
+	fs.StringVar(&p.image, "image", "",
+		"container image that the scaffolded controller deploys and reconciles, implies --resource and --controller")
+	fs.IntVar(&p.imageContainerPort, "image-container-port", 0,
+		"port exposed by the container image passed with --image, a Service is scaffolded for it if set")
+
+	fs.BoolVar(&p.withPredicates, "with-predicates", false,
+		"filter the events of the resource that trigger reconciles in the scaffolded controller, only letting "+
+			"through its generation changes and the objects matching a label selector")
+
+	fs.BoolVar(&p.withEvents, "with-events", false,
+		"record Normal and Warning events on the objects of the resource from the scaffolded controller with an "+
+			"EventRecorder, adding the RBAC marker allowing the manager to create them")
+
+	fs.BoolVar(&p.withControllerOptions, "with-controller-options", false,
+		"bind the maximum number of concurrent reconciles and the limits of the workqueue rate limiter of the "+
+			"scaffolded controller to flags of main.go prefixed with its name, e.g. --<kind>-max-concurrent-reconciles")
+
+	fs.BoolVar(&p.withConditions, "with-conditions", false,
+		"add the Conditions field to the status of the resource, with the standard Ready, Progressing and Degraded "+
+			"types and the helpers setting them in conditions.go, and make the scaffolded controller maintain them")
+
+	fs.BoolVar(&p.withMetrics, "with-metrics", false,
+		"register example custom Prometheus metrics in monitoring/metrics.go, a counter of the reconcile outcomes "+
+			"and a histogram of the durations of the external calls of the controllers, served on the metrics "+
+			"endpoint of the manager, and count the reconcile outcomes of the scaffolded controller with them")
+
+	fs.BoolVar(&p.validationMarkers, "with-validation-markers", false,
+		"add example field validation markers and CEL validation rules to the Spec of the resource, enforced by the "+
+			"API server without a webhook, along with envtest tests asserting that invalid objects are rejected, "+
+			"requires --crd-version=v1")
+
+	fs.StringVar(&p.reconcileStrategy, "reconcile-strategy", reconcileStrategyUpdate,
+		fmt.Sprintf("how the scaffolded controller writes the objects it reconciles, either %q to implement the "+
+			"get/mutate/update pattern in its Reconcile, or %q to generate the apply configuration of the resource "+
+			"with applyconfiguration-gen into pkg/applyconfiguration, adding an apply-configurations target to the "+
+			"Makefile, the controller building the desired state of the fields it owns with it and server-side "+
+			"applying them as their field manager, not supported yet as the apply configurations require a newer "+
+			"client-go than the one of the scaffolded controller-runtime", reconcileStrategyUpdate, reconcileStrategySSA))
+	fs.BoolVar(&p.serverSideApply, "ssa", false, "alias of --reconcile-strategy="+reconcileStrategySSA)
+	_ = fs.MarkDeprecated("ssa", "use --reconcile-strategy="+reconcileStrategySSA+" instead")
+
+	fs.StringArrayVar(&p.values, "set", nil, "key=value pair exposed to the templates as .Values.<key>, "+
+		"overriding the one set on initialization for this command only, may be repeated")
+
+	fs.BoolVar(&p.skipGoMod, "skip-go-mod", false,
+		"leave go.mod and go.sum untouched when the dependencies are managed separately, e.g. with bazel, "+
+			"the dependency of --pattern is not added to them, always set for projects initialized with "+
+			"--skip-go-mod-tidy")
+
 	fs.BoolVar(&p.force, "force", false,
-		"attempt to create resource even if it already exists")
+		"attempt to create resource even if it already exists or its Kind collides with a built-in or tracked one, "+
+			"the existing files of the resource are kept and only its missing Go files are scaffolded again")
 	p.resource = &resource.Options{}
 	fs.StringVar(&p.resource.Kind, "kind", "", "resource Kind")
 	fs.StringVar(&p.resource.Group, "group", "", "resource Group")
 	fs.StringVar(&p.resource.Version, "version", "", "resource Version")
+	fs.StringVar(&p.resource.Plural, "plural", "",
+		"resource plural, used as the resource name of the CRD and in the RBAC rules, "+
+			"only needed if it is not the one computed from the Kind")
 	fs.BoolVar(&p.resource.Namespaced, "namespaced", true, "resource is namespaced")
+	fs.StringVar(&p.resource.ExternalAPIPath, "external-api-path", "",
+		"go package of an API not defined in this project (e.g. a third-party CRD) to scaffold a controller for, "+
+			"implies --resource=false")
+	fs.StringVar(&p.resource.ExternalAPIDomain, "external-api-domain", "",
+		"domain of the API group passed with --external-api-path")
+	fs.StringVar(&p.resource.CRDVersion, "crd-version", resource.DefaultCRDVersion,
+		"API version of the generated CRD manifest, one of v1beta1 or v1, all the CRDs of a project share it, "+
+			"defaults to the one of the existing APIs")
+	p.crdVersionFlag = fs.Lookup("crd-version")
+	fs.StringVar(&p.resource.Defaults, "defaults", "",
+		"how the fields of the resource are defaulted, either \"markers\" to scaffold +kubebuilder:default markers "+
+			"applied by the API server, which only support constant values and require --crd-version=v1, or "+
+			"\"webhook\" to scaffold a defaulting webhook, which can compute them but must be deployed and served")
 }
 
 func (p *createAPIPlugin) InjectConfig(c *config.Config) {
 	p.config = c
 }
 
-func (p *createAPIPlugin) Run() error {
-	return cmdutil.Run(p)
+func (p *createAPIPlugin) Scaffold() error {
+	return cmdutil.Scaffold(p)
 }
 
 func (p *createAPIPlugin) Validate() error {
+	// The CRDs of all the resources share the CRD version of the project
+	if !p.crdVersionFlag.Changed {
+		for _, r := range p.config.Resources {
+			if r.CRDVersion != "" {
+				p.resource.CRDVersion = r.CRDVersion
+				break
+			}
+		}
+	}
+
 	if err := p.resource.Validate(); err != nil {
 		return err
 	}
 
+	if p.serverSideApply {
+		p.reconcileStrategy = reconcileStrategySSA
+	}
+	switch p.reconcileStrategy {
+	case reconcileStrategyUpdate, reconcileStrategySSA:
+		p.serverSideApply = p.reconcileStrategy == reconcileStrategySSA
+	default:
+		return fmt.Errorf("reconcile strategy %q is not supported, possible values: (%s, %s)",
+			p.reconcileStrategy, reconcileStrategyUpdate, reconcileStrategySSA)
+	}
+
+	// TODO: accept it once the scaffolded controller-runtime is bumped along with client-go.
+	// The apply configurations import k8s.io/client-go/applyconfigurations, which the client-go required by the
+	// scaffolded controller-runtime does not provide, so the controller would not build
+	if p.serverSideApply {
+		return fmt.Errorf("server-side apply is not supported yet, the apply configurations require "+
+			"k8s.io/client-go %s or newer, which controller-runtime %s can not be built with",
+			scaffolds.CodeGeneratorApplyConfigurationsVersion, scaffolds.ControllerRuntimeVersion)
+	}
+
+	values, err := util.ParseValues(p.values)
+	if err != nil {
+		return err
+	}
+	p.valuesMap = values
+
+	// Offline projects only run make if explicitly requested, as it may download the code generators
+	if p.config.Offline && !p.runMakeFlag.Changed {
+		p.runMake = false
+	}
+
+	// Types of external APIs are not owned by the project, so they can not be scaffolded
+	if p.resource.ExternalAPIPath != "" {
+		if p.resourceFlag.Changed && p.doResource {
+			return errors.New("--resource can not be set when scaffolding a controller for an external API")
+		}
+		p.doResource = false
+	}
+
+	// The controller that deploys the image reconciles the fields of the scaffolded types
+	if p.image != "" {
+		if p.resource.ExternalAPIPath != "" {
+			return errors.New("--image can not be used with --external-api-path")
+		}
+		if p.pattern != "" {
+			return errors.New("--image can not be used with --pattern")
+		}
+		if p.withPredicates {
+			return errors.New("--with-predicates can not be used with --image")
+		}
+		if p.withEvents {
+			return errors.New("--with-events can not be used with --image")
+		}
+		if p.withControllerOptions {
+			return errors.New("--with-controller-options can not be used with --image")
+		}
+		if p.withMetrics {
+			return errors.New("--with-metrics can not be used with --image")
+		}
+		if p.withConditions {
+			return errors.New("--with-conditions can not be used with --image")
+		}
+		if p.validationMarkers {
+			return errors.New("--with-validation-markers can not be used with --image")
+		}
+		if p.resource.Defaults == config.DefaultsMarkers {
+			return errors.New("--defaults=markers can not be used with --image")
+		}
+		if p.serverSideApply {
+			return errors.New("--reconcile-strategy=ssa can not be used with --image")
+		}
+		if !p.resource.Namespaced {
+			return errors.New("--image requires a namespaced resource")
+		}
+		if (p.resourceFlag.Changed && !p.doResource) || (p.controllerFlag.Changed && !p.doController) {
+			return errors.New("--image requires both the resource and the controller to be scaffolded")
+		}
+		p.doResource, p.doController = true, true
+		p.resourceFlag.Changed, p.controllerFlag.Changed = true, true
+	}
+	if p.imageContainerPort != 0 {
+		if p.image == "" {
+			return errors.New("--image-container-port can only be used with --image")
+		}
+		if p.imageContainerPort < 1 || p.imageContainerPort > 65535 {
+			return fmt.Errorf("image container port %d is invalid, must be between 1 and 65535", p.imageContainerPort)
+		}
+	}
+
+	// The chart is released in the namespace of each object of the API, with the values of its scaffolded types
+	if strings.ToLower(p.pattern) == "helm" {
+		if p.resource.ExternalAPIPath != "" {
+			return errors.New("--pattern=helm can not be used with --external-api-path")
+		}
+		if !p.resource.Namespaced {
+			return errors.New("--pattern=helm requires a namespaced resource")
+		}
+		if (p.resourceFlag.Changed && !p.doResource) || (p.controllerFlag.Changed && !p.doController) {
+			return errors.New("--pattern=helm requires both the resource and the controller to be scaffolded")
+		}
+		p.doResource, p.doController = true, true
+		p.resourceFlag.Changed, p.controllerFlag.Changed = true, true
+	}
+
 	// TODO: re-evaluate whether y/n input still makes sense. We should probably always
 	// scaffold the resource and controller.
 	reader := bufio.NewReader(os.Stdin)
-	if !p.resourceFlag.Changed {
+	if !p.resourceFlag.Changed && p.resource.ExternalAPIPath == "" {
 		fmt.Println("Create Resource [y/n]")
 		p.doResource = util.YesNo(reader)
 	}
@@ -149,11 +449,132 @@ func (p *createAPIPlugin) Validate() error {
 		p.doController = util.YesNo(reader)
 	}
 
+	// The predicates are set on the builder of the scaffolded controller
+	if p.withPredicates {
+		if !p.doController {
+			return errors.New("--with-predicates requires the controller to be scaffolded")
+		}
+		if p.pattern != "" {
+			return errors.New("--with-predicates can not be used with --pattern")
+		}
+	}
+
+	// The recorder is set on the reconciler of the scaffolded controller
+	if p.withEvents {
+		if !p.doController {
+			return errors.New("--with-events requires the controller to be scaffolded")
+		}
+		if p.pattern != "" {
+			return errors.New("--with-events can not be used with --pattern")
+		}
+	}
+
+	// The options are set on the builder of the scaffolded controller
+	if p.withControllerOptions {
+		if !p.doController {
+			return errors.New("--with-controller-options requires the controller to be scaffolded")
+		}
+		if p.pattern != "" {
+			return errors.New("--with-controller-options can not be used with --pattern")
+		}
+	}
+
+	// The conditions are added to the scaffolded types, and the controller updates them with the status, unlike
+	// the server-side apply of --reconcile-strategy=ssa
+	if p.withConditions {
+		if !p.doResource {
+			return errors.New("--with-conditions requires the resource to be scaffolded")
+		}
+		if p.pattern != "" {
+			return errors.New("--with-conditions can not be used with --pattern")
+		}
+		if p.serverSideApply {
+			return errors.New("--with-conditions can not be used with --reconcile-strategy=ssa")
+		}
+	}
+
+	// The metrics are incremented by the scaffolded controller
+	if p.withMetrics {
+		if !p.doController {
+			return errors.New("--with-metrics requires the controller to be scaffolded")
+		}
+		if p.pattern != "" {
+			return errors.New("--with-metrics can not be used with --pattern")
+		}
+	}
+
+	// The validation markers are set on the scaffolded types, and CEL rules are only generated in v1 CRDs
+	if p.validationMarkers {
+		if !p.doResource {
+			return errors.New("--with-validation-markers requires the resource to be scaffolded")
+		}
+		if p.pattern != "" {
+			return errors.New("--with-validation-markers can not be used with --pattern")
+		}
+		if p.resource.CRDVersion != "v1" {
+			return errors.New("--with-validation-markers requires --crd-version=v1, " +
+				"as CEL validation rules are not supported by v1beta1 CRDs")
+		}
+	}
+
+	// The apply configuration is generated from the scaffolded types
+	if p.serverSideApply {
+		if !p.doResource {
+			return errors.New("--reconcile-strategy=ssa requires the resource to be scaffolded")
+		}
+		if p.pattern != "" {
+			return errors.New("--reconcile-strategy=ssa can not be used with --pattern")
+		}
+	}
+
+	// The module of the APIs only depends on the Kubernetes API machinery, unlike the types of the patterns and
+	// the webhooks, which are implemented in the packages of the types
+	if p.config.APIsModule {
+		if p.pattern != "" {
+			return errors.New("--pattern can not be used in a project with a separate module for the APIs")
+		}
+		if p.resource.Defaults == config.DefaultsWebhook {
+			return errors.New("--defaults=webhook can not be used in a project with a separate module for the " +
+				"APIs, as the webhook would make it depend on controller-runtime")
+		}
+	}
+
+	// The defaults are set on the scaffolded types, either by their markers or by their Default method
+	if p.resource.Defaults != "" {
+		if !p.doResource {
+			return fmt.Errorf("--defaults=%s requires the resource to be scaffolded", p.resource.Defaults)
+		}
+		if p.pattern != "" {
+			return errors.New("--defaults can not be used with --pattern")
+		}
+	}
+	switch p.resource.Defaults {
+	case config.DefaultsMarkers:
+		// Defaulting is only supported by the structural schemas of v1 CRDs
+		if p.resource.CRDVersion != "v1" {
+			return errors.New("--defaults=markers requires --crd-version=v1, " +
+				"as default markers are not supported by v1beta1 CRDs")
+		}
+	case config.DefaultsWebhook:
+		// The webhook configurations of all the resources are patched by the same kustomize manifests
+		p.resource.WebhookVersion = resource.DefaultWebhookVersion
+		for _, r := range p.config.Resources {
+			if r.WebhookVersion != "" {
+				p.resource.WebhookVersion = r.WebhookVersion
+				break
+			}
+		}
+	}
+
 	// In case we want to scaffold a resource API we need to do some checks
 	if p.doResource {
 		// Check that resource doesn't exist or flag force was set
-		if !p.force && p.config.HasResource(p.resource.GVK()) {
-			return errors.New("API resource already exists")
+		if p.config.HasResource(p.resource.GVK()) {
+			if !p.force {
+				return errors.New("API resource already exists, set --force to scaffold its missing Go files again")
+			}
+			logger.Default().Info("The resource already exists, only its missing Go files are scaffolded again " +
+				"as --force is set")
 		}
 
 		// Check that the provided group can be added to the project
@@ -161,6 +582,44 @@ func (p *createAPIPlugin) Validate() error {
 			return fmt.Errorf("multiple groups are not allowed by default, " +
 				"to enable multi-group visit kubebuilder.io/migration/multi-group.html")
 		}
+
+		// The CRDs of all the resources are generated by a single controller-gen invocation
+		gvk := p.resource.GVK()
+		for _, r := range p.config.Resources {
+			crdVersion := r.CRDVersion
+			if crdVersion == "" {
+				crdVersion = resource.DefaultCRDVersion
+			}
+			if crdVersion != gvk.CRDVersion && !(r.Group == gvk.Group && r.Version == gvk.Version && r.Kind == gvk.Kind) {
+				return fmt.Errorf("only one CRD version can be used for all resources, "+
+					"the project uses %q so --crd-version=%s is not allowed", crdVersion, gvk.CRDVersion)
+			}
+		}
+
+		// Kinds named like built-in or tracked ones are only created on purpose
+		if err := p.resource.ValidateForProject(p.config); err != nil {
+			if !p.force {
+				return fmt.Errorf("%v, set --force to create it anyway", err)
+			}
+			logger.Default().Info(fmt.Sprintf("Creating the resource as --force is set: %v", err))
+		}
+		for _, warning := range p.resource.KindWarnings() {
+			logger.Default().Info("Warning: " + warning)
+		}
+
+		// The versions of a Kind are served by the same CRD, so they share its plural
+		if p.resource.Plural != "" {
+			for _, r := range p.config.Resources {
+				plural := r.Plural
+				if plural == "" {
+					plural = resource.DefaultPlural(r.Kind)
+				}
+				if r.Group == gvk.Group && r.Kind == gvk.Kind && r.Version != gvk.Version && plural != p.resource.Plural {
+					return fmt.Errorf("the versions of a Kind share its plural, %s/%s, Kind=%s uses %q "+
+						"so --plural=%s is not allowed", r.Group, r.Version, r.Kind, plural, p.resource.Plural)
+				}
+			}
+		}
 	}
 
 	return nil
@@ -180,13 +639,20 @@ func (p *createAPIPlugin) GetScaffolder() (scaffold.Scaffolder, error) {
 		// Default pattern
 	case "addon":
 		plugins = append(plugins, &addon.Plugin{})
+	case "helm":
+		plugins = append(plugins, &helm.Plugin{})
 	default:
 		return nil, fmt.Errorf("unknown pattern %q", p.pattern)
 	}
+	if p.image != "" {
+		plugins = append(plugins, &deployimage.Plugin{Image: p.image, ContainerPort: p.imageContainerPort})
+	}
 
 	// Create the actual resource from the resource options
 	res := p.resource.NewResource(p.config, p.doResource)
-	return scaffolds.NewAPIScaffolder(p.config, string(bp), res, p.doResource, p.doController, plugins), nil
+	return scaffolds.NewAPIScaffolder(p.config, string(bp), res, p.doResource, p.doController, p.force,
+		p.withPredicates, p.validationMarkers, p.serverSideApply, p.withEvents,
+		p.withControllerOptions, p.withMetrics, p.withConditions, p.valuesMap, plugins), nil
 }
 
 func (p *createAPIPlugin) PostScaffold() error {
@@ -197,17 +663,58 @@ func (p *createAPIPlugin) PostScaffold() error {
 	case "addon":
 		// Ensure that we are pinning sigs.k8s.io/kubebuilder-declarative-pattern version
 		// TODO: either find a better way to inject this version (ex. tools.go).
-		err := util.RunCmd("Get kubebuilder-declarative-pattern dependency", "go", "get",
+		err := p.getDependency("kubebuilder-declarative-pattern",
 			"sigs.k8s.io/kubebuilder-declarative-pattern@"+KbDeclarativePatternVersion)
 		if err != nil {
 			return err
 		}
+	case "helm":
+		if err := p.getDependency("helm", "helm.sh/helm/v3@"+HelmVersion); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unknown pattern %q", p.pattern)
 	}
 
+	if p.doResource && p.config.APIsModule {
+		if err := p.tidyAPIsModule(); err != nil {
+			return err
+		}
+	}
+
 	if p.runMake {
 		return util.RunCmd("Running make", "make")
 	}
 	return nil
 }
+
+// tidyAPIsModule updates the go.mod of the module of the APIs with the dependencies of the scaffolded types, and
+// the one of the project with the requirement of the module of the APIs its code now imports.
+func (p *createAPIPlugin) tidyAPIsModule() error {
+	dir := p.config.GetDirectories().APIs
+	if p.skipGoMod || p.config.SkipGoMod || p.config.Offline {
+		logger.Default().Info(fmt.Sprintf("go.mod and go.sum were left untouched: run 'go mod tidy' in %s and "+
+			"in the project.", dir))
+		return nil
+	}
+	if err := util.RunCmdInDir("Update the go.mod of the APIs", dir, "go", "mod", "tidy"); err != nil {
+		return err
+	}
+	return util.RunCmd("Update go.mod", "go", "mod", "tidy")
+}
+
+// getDependency adds dependency, a module path and version, to the go.mod, unless the project is offline or
+// go.mod is left untouched
+func (p *createAPIPlugin) getDependency(name, dependency string) error {
+	if p.skipGoMod || p.config.SkipGoMod {
+		logger.Default().Info(fmt.Sprintf("go.mod and go.sum were left untouched: add %s to the dependencies "+
+			"of the project.", dependency))
+		return nil
+	}
+	if p.config.Offline {
+		logger.Default().Info(fmt.Sprintf("Offline mode: %s was not downloaded, add it with "+
+			"'go get %s' and vendor it again with 'go mod vendor'.", dependency, dependency))
+		return nil
+	}
+	return util.RunCmd(fmt.Sprintf("Get %s dependency", name), "go", "get", dependency)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+// createAPI validates the create api command run with args in the project of c, and tracks its resource in c as its
+// scaffolder would
+func createAPI(c *config.Config, args ...string) (*createAPIPlugin, error) {
+	p := &createAPIPlugin{}
+	fs := pflag.NewFlagSet("create api", pflag.ContinueOnError)
+	p.BindFlags(fs)
+	if err := fs.Parse(append(args, "--resource", "--controller", "--make=false")); err != nil {
+		return nil, err
+	}
+	p.InjectConfig(c)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	c.UpdateResource(p.resource.GVK())
+	return p, nil
+}
+
+var _ = Describe("createAPIPlugin", func() {
+	var c *config.Config
+
+	BeforeEach(func() {
+		c = &config.Config{Version: config.Version3Alpha, Domain: "example.org", Repo: "example.org/p3"}
+	})
+
+	It("should default the CRD version to the one of the project", func() {
+		_, err := createAPI(c, "--group", "crew", "--version", "v1", "--kind", "Captain", "--crd-version=v1")
+		Expect(err).NotTo(HaveOccurred())
+
+		p, err := createAPI(c, "--group", "crew", "--version", "v1", "--kind", "FirstMate")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.resource.CRDVersion).To(Equal("v1"))
+		Expect(c.Resources).To(HaveLen(2))
+		Expect(c.Resources[1].CRDVersion).To(Equal("v1"))
+	})
+
+	It("should reject a CRD version that differs from the one of the project", func() {
+		_, err := createAPI(c, "--group", "crew", "--version", "v1", "--kind", "Captain", "--crd-version=v1")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = createAPI(c, "--group", "crew", "--version", "v1", "--kind", "FirstMate", "--crd-version=v1beta1")
+		Expect(err).To(MatchError(ContainSubstring(`the project uses "v1" so --crd-version=v1beta1 is not allowed`)))
+	})
+
+	DescribeTable("should reject server-side apply until controller-runtime supports the apply configurations",
+		func(flag string) {
+			_, err := createAPI(c, "--group", "crew", "--version", "v1", "--kind", "Captain", flag)
+			Expect(err).To(MatchError(ContainSubstring("server-side apply is not supported yet")))
+			Expect(c.Resources).To(BeEmpty())
+		},
+		Entry("for the ssa reconcile strategy", "--reconcile-strategy=ssa"),
+		Entry("for its deprecated --ssa alias", "--ssa"),
+	)
+})
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds"
+)
+
+type deleteAPIPlugin struct {
+	config *config.Config
+
+	resource *resource.Options
+
+	// runMake indicates whether to run make or not after deleting the API
+	runMake     bool
+	runMakeFlag *pflag.Flag
+}
+
+var (
+	_ plugin.DeleteAPI   = &deleteAPIPlugin{}
+	_ cmdutil.RunOptions = &deleteAPIPlugin{}
+)
+
+func (p deleteAPIPlugin) UpdateContext(ctx *plugin.Context) {
+	ctx.Description = `Delete a Kubernetes API, undoing what create api scaffolded for it.
+
+The Go types and the controller of the resource are deleted, along with their registration in main.go and in
+the controller test suite, and the resource is no longer tracked in the PROJECT file. The types of the other
+resources of the version are kept, their group version files are only deleted with the last one.
+
+The webhook of the resource, if any, must be deleted first. After the files are deleted, delete api will run
+make generate manifests on the project.
+`
+	ctx.Examples = fmt.Sprintf(`  # Delete the frigates API with Group: ship, Version: v1beta1 and Kind: Frigate
+  %s delete api --group ship --version v1beta1 --kind Frigate
+`, ctx.CommandName)
+}
+
+func (p *deleteAPIPlugin) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&p.runMake, "make", true, "if true, run make generate manifests after deleting files, "+
+		"defaults to false for projects scaffolded in offline mode")
+	p.runMakeFlag = fs.Lookup("make")
+
+	p.resource = &resource.Options{}
+	fs.StringVar(&p.resource.Kind, "kind", "", "resource Kind")
+	fs.StringVar(&p.resource.Group, "group", "", "resource Group")
+	fs.StringVar(&p.resource.Version, "version", "", "resource Version")
+}
+
+func (p *deleteAPIPlugin) InjectConfig(c *config.Config) {
+	p.config = c
+}
+
+func (p *deleteAPIPlugin) Scaffold() error {
+	return cmdutil.Scaffold(p)
+}
+
+func (p *deleteAPIPlugin) Validate() error {
+	if err := p.resource.Validate(); err != nil {
+		return err
+	}
+
+	// Offline projects only run make if explicitly requested, as it may download the code generators
+	if p.config.Offline && !p.runMakeFlag.Changed {
+		p.runMake = false
+	}
+
+	if !p.config.HasResource(p.resource.GVK()) {
+		return fmt.Errorf("API resource %s/%s, Kind=%s is not tracked in the PROJECT file",
+			p.resource.Group, p.resource.Version, p.resource.Kind)
+	}
+	return nil
+}
+
+func (p *deleteAPIPlugin) GetScaffolder() (scaffold.Scaffolder, error) {
+	return scaffolds.NewDeleteAPIScaffolder(p.config, p.resource.NewResource(p.config, true)), nil
+}
+
+func (p *deleteAPIPlugin) PostScaffold() error {
+	if p.runMake {
+		return util.RunCmd("Running make", "make", "generate", "manifests")
+	}
+	return nil
+}
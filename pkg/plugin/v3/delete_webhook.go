@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds"
+)
+
+type deleteWebhookPlugin struct {
+	config *config.Config
+
+	resource *resource.Options
+
+	// runMake indicates whether to run make or not after deleting the webhooks
+	runMake     bool
+	runMakeFlag *pflag.Flag
+}
+
+var (
+	_ plugin.DeleteWebhook = &deleteWebhookPlugin{}
+	_ cmdutil.RunOptions   = &deleteWebhookPlugin{}
+)
+
+func (p deleteWebhookPlugin) UpdateContext(ctx *plugin.Context) {
+	ctx.Description = `Delete the webhooks of a Kubernetes API, undoing what create webhook scaffolded for it.
+
+The defaulting, validating and conversion webhooks of the resource are deleted together, with the file
+implementing them and their setup in main.go, and the PROJECT file no longer records a webhook version nor
+webhooks for the resource. After the files are deleted, delete webhook will run make manifests on the project.
+`
+	ctx.Examples = fmt.Sprintf(`  # Delete the webhooks of the CRD of group crew, version v1 and kind FirstMate
+  %s delete webhook --group crew --version v1 --kind FirstMate
+`, ctx.CommandName)
+}
+
+func (p *deleteWebhookPlugin) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&p.runMake, "make", true, "if true, run make manifests after deleting files, "+
+		"defaults to false for projects scaffolded in offline mode")
+	p.runMakeFlag = fs.Lookup("make")
+
+	p.resource = &resource.Options{}
+	fs.StringVar(&p.resource.Group, "group", "", "resource Group")
+	fs.StringVar(&p.resource.Version, "version", "", "resource Version")
+	fs.StringVar(&p.resource.Kind, "kind", "", "resource Kind")
+}
+
+func (p *deleteWebhookPlugin) InjectConfig(c *config.Config) {
+	p.config = c
+}
+
+func (p *deleteWebhookPlugin) Scaffold() error {
+	return cmdutil.Scaffold(p)
+}
+
+func (p *deleteWebhookPlugin) Validate() error {
+	if err := p.resource.Validate(); err != nil {
+		return err
+	}
+
+	// Offline projects only run make if explicitly requested, as it may download the code generators
+	if p.config.Offline && !p.runMakeFlag.Changed {
+		p.runMake = false
+	}
+
+	return nil
+}
+
+func (p *deleteWebhookPlugin) GetScaffolder() (scaffold.Scaffolder, error) {
+	return scaffolds.NewDeleteWebhookScaffolder(p.config, p.resource.NewResource(p.config, false)), nil
+}
+
+func (p *deleteWebhookPlugin) PostScaffold() error {
+	if p.runMake {
+		return util.RunCmd("Running make", "make", "manifests")
+	}
+	return nil
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package v3
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -26,8 +28,10 @@ import (
 
 	"sigs.k8s.io/kubebuilder/internal/cmdutil"
 	"sigs.k8s.io/kubebuilder/pkg/internal/validation"
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/license"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds"
@@ -41,9 +45,36 @@ type initPlugin struct {
 	// boilerplate options
 	license string
 	owner   string
+	// ownerFromGit is true if the owner defaults to the author of the git config
+	ownerFromGit bool
+	// copyrightYear is the year of the copyright, the current one if empty
+	copyrightYear string
+
+	// goVersion is the Go version targeted by the scaffolded project
+	goVersion string
+	// baseImage is the image the manager binary is packaged in
+	baseImage string
+
+	// qualityTargets is true if the lint and test-coverage Makefile targets are scaffolded
+	qualityTargets bool
+	// devContainer is true if a VS Code dev container is scaffolded
+	devContainer bool
+	// apiDocs is true if the api-docs Makefile target is scaffolded
+	apiDocs bool
+
+	// webhookServer are the options of the webhook server of the manager
+	webhookServer webhookServerOptions
+
+	// monorepo is true if the project is initialized in a subdirectory of the Go module of a parent directory
+	monorepo bool
+
+	// values are the key=value pairs of the --set flags, recorded in the config
+	values []string
 
 	// flags
 	fetchDeps          bool
+	fetchDepsFlag      *pflag.Flag
+	ownerFlag          *pflag.Flag
 	skipGoVersionCheck bool
 }
 
@@ -56,19 +87,63 @@ func (p *initPlugin) UpdateContext(ctx *plugin.Context) {
 	ctx.Description = `Initialize a new project including vendor/ directory and Go package directories.
 
 Writes the following files:
-- a boilerplate license file
+- a boilerplate license file, with the header of the --license, and its LICENSE file unless it is none
 - a PROJECT file with the domain and repo
-- a Makefile to build the project
-- a go.mod with project dependencies
-- a Kustomization.yaml for customizating manifests
-- a Patch file for customizing image for manager manifests
-- a Patch file for enabling prometheus metrics
-- a main.go to run
+- a Makefile to build the project, with lint and test-coverage targets unless --quality-targets=false
+- a golangci-lint config used by the lint target, unless --quality-targets=false
+- a hack/update-codegen.sh script run by the clients Makefile target, generating the typed clientsets, listers and
+  informers of the APIs into pkg/client with code-generator, if --typed-clients is set
+- a VS Code dev container with Go, kubectl, kind and kustomize installed, if --devcontainer is set
+- a hack/tools Go module pinning the versions of the tools the Makefile builds into bin/
+- an api/go.mod declaring the Go module of the APIs, if --apis-module is set, so that other projects can import
+  their types without the dependencies of the manager
+- a go.mod with project dependencies, unless one already exists or --monorepo is set, the project then sharing
+  the go.mod of the Go module of a parent directory, whose root is the build context of the Dockerfile
+- a main.go to run, serving the metrics over HTTPS to the authorized clients only if --metrics-auth-filter is set,
+  loading the manager options from the file of its --config flag if --component-config is set and serving the
+  runtime profiles on the address of its --pprof-addr flag if --pprof is set
+- with --zap-flags, the --zap-log-level, --zap-encoder and --zap-stacktrace-level flags of the logger of the
+  manager in main.go, its manifests logging JSON at the info level
+- with --tracing, the setup of an OpenTelemetry tracer provider in main.go exporting the spans the controllers
+  start for each reconciliation to the OTLP gRPC endpoint of the OTEL_EXPORTER_OTLP_ENDPOINT variable, set in
+  the manifests of the manager
+- with --namespace-scoped, the --watch-namespace flag of the manager in main.go, defaulting to its WATCH_NAMESPACE
+  variable, restricting its cache to those comma-separated namespaces, for clusters where cluster-wide
+  permissions are forbidden; the manifests set it to the namespace of the manager and the RBAC markers of the
+  controllers created afterwards generate Roles instead of ClusterRoles
+- with --watch-namespaces, the same flag in main.go, the manifests setting the WATCH_NAMESPACE variable to those
+  namespaces while keeping the ClusterRoles, to cache the resources of those namespaces only
+- with --high-availability, leader election timings in main.go, or in its ControllerManagerConfig file with
+  --component-config, letting the leader keep its lease through an unavailability of the API server of up to
+  107s, the manifests running 2 replicas of the manager spread across the nodes and zones and kept available by
+  a PodDisruptionBudget
+
+If a go.work file is found in the current or a parent directory, unless GOWORK=off, the module of the project is
+added to its workspace, the root of which is then the build context of the Dockerfile.
+
+The --profile flag selects the directories the APIs and controllers are created in, recorded in the PROJECT file
+for the subsequent commands: api/ and controllers/ by default, the controllers in internal/controller/ with the
+internal profile so that other modules can not import them, or pkg/apis/ and pkg/controller/ with the legacy one.
+
+The values set with --set, e.g. the name of the team owning the project, are recorded in the PROJECT file and
+exposed to the templates of this and the subsequent commands as .Values, e.g. {{ .Values.team }}.
 
 project will prompt the user to run 'dep ensure' after writing the project files.
 `
 	ctx.Examples = fmt.Sprintf(`  # Scaffold a project using the apache2 license with "The Kubernetes authors" as owners
-  %s init --project-version=2 --domain example.org --license apache2 --owner "The Kubernetes authors"
+  %[1]s init --project-version=2 --domain example.org --license apache2 --owner "The Kubernetes authors"
+
+  # Scaffold a project under the MIT license, writing its LICENSE file
+  %[1]s init --domain example.org --license MIT --owner "The Kubernetes authors"
+
+  # Scaffold a project with the controllers in internal/controller
+  %[1]s init --domain example.org --profile internal
+
+  # Scaffold a project in the operators/captain directory of the Go module declared in ../../go.mod
+  %[1]s init --domain example.org --monorepo
+
+  # Scaffold a project exposing the team owning it and its registry to the templates
+  %[1]s init --domain example.org --set team=platform --set registry=quay.io/acme
 `,
 		ctx.CommandName)
 
@@ -81,26 +156,92 @@ func (p *initPlugin) BindFlags(fs *pflag.FlagSet) {
 
 	// dependency args
 	fs.BoolVar(&p.fetchDeps, "fetch-deps", true, "ensure dependencies are downloaded")
+	p.fetchDepsFlag = fs.Lookup("fetch-deps")
+	fs.BoolVar(&p.config.Offline, "offline", false, "scaffold without network access, dependencies are not "+
+		"downloaded and make is not run, by this and any subsequent command")
+	fs.BoolVar(&p.config.SkipGoMod, "skip-go-mod-tidy", false, "leave go.mod and go.sum untouched, by this and "+
+		"any subsequent command, when the dependencies are managed separately, e.g. with bazel: controller-runtime "+
+		"is not added to them, they are not tidied and make is not run")
+	fs.StringVar(&p.goVersion, "go-version", scaffolds.DefaultGoVersion,
+		fmt.Sprintf("Go version used in go.mod, the Dockerfile builder image and the Makefile, "+
+			"may be one of (%s)", strings.Join(scaffolds.SupportedGoVersions, ", ")))
+	fs.StringVar(&p.baseImage, "base-image", scaffolds.DefaultBaseImage,
+		"image the Dockerfile packages the manager binary in, which must run it as a non-root user")
+	fs.StringVar(&p.config.Image, "image", "", "reference of the manager image, e.g. quay.io/acme/operator:v0.1.0, "+
+		"the default IMG of the Makefile and the image of the manager manifest, recorded in the PROJECT file and "+
+		"changed with 'edit --image', defaults to the project name with the latest tag")
+
+	fs.BoolVar(&p.qualityTargets, "quality-targets", true, "scaffold the lint and test-coverage Makefile "+
+		"targets along with the golangci-lint config")
+	fs.StringVar(&p.config.TestFramework, "test-framework", config.TestFrameworkGinkgo,
+		fmt.Sprintf("framework the scaffolded tests are written with, may be one of (%s), Ginkgo v2 requires "+
+			"Go 1.18 or newer to run the tests and gotest writes table-driven tests with the standard testing package",
+			strings.Join(testFrameworks, ", ")))
+	fs.BoolVar(&p.devContainer, "devcontainer", false, "scaffold a dev container for VS Code and Codespaces "+
+		"with Go, kubectl, kind and kustomize installed")
+	p.webhookServer.bindFlags(fs)
+	fs.BoolVar(&p.config.ComponentConfig, "component-config", false, "load the options of the manager from a "+
+		"ControllerManagerConfig file, deployed in a ConfigMap, instead of from command-line flags")
+	fs.BoolVar(&p.config.MetricsAuthFilter, "metrics-auth-filter", false, "serve the metrics over HTTPS from "+
+		"the manager, authenticating and authorizing the requests itself, instead of from a kube-rbac-proxy sidecar")
+	fs.BoolVar(&p.config.Tracing, "tracing", false, "trace the reconciliations with OpenTelemetry, the "+
+		"manager exporting the spans the controllers start to the OTLP endpoint set in its environment")
+	fs.BoolVar(&p.config.Pprof, "pprof", false, "add a pprof endpoint to the manager serving its runtime "+
+		"profiles on the address of its --pprof-addr flag, which the manifests disable")
+	fs.BoolVar(&p.config.ZapFlags, "zap-flags", false, "bind the level, encoder and stacktrace level of the "+
+		"logger of the manager to its --zap-* flags, the manifests setting them to log JSON at the info level")
+	fs.BoolVar(&p.config.NamespaceScoped, "namespace-scoped", false, "only watch the namespaces of the "+
+		"--watch-namespace flag of the manager, defaulting to its WATCH_NAMESPACE variable, the manifests "+
+		"setting it to the namespace of the manager and granting it Roles instead of ClusterRoles")
+	fs.StringSliceVar(&p.config.WatchNamespaces, "watch-namespaces", nil, "comma-separated namespaces the "+
+		"manager only watches, its --watch-namespace flag being set to them by the WATCH_NAMESPACE variable of "+
+		"the manifests, which keep granting it ClusterRoles")
+	fs.BoolVar(&p.config.HighAvailability, "high-availability", false, "scaffold the manager for high "+
+		"availability, its leader election tolerating an unavailability of the API server and its manifests "+
+		"running 2 replicas spread across the nodes and zones with a PodDisruptionBudget")
+	fs.BoolVar(&p.apiDocs, "api-docs", false, "scaffold the api-docs Makefile target generating the reference "+
+		"documentation of the APIs from their Go types with crd-ref-docs, along with its config")
+	fs.BoolVar(&p.config.APIsModule, "apis-module", false, "place the Go types of the APIs in a Go module of "+
+		"their own, required by the one of the project, so that other projects can import them without "+
+		"depending on controller-runtime; their webhooks and the --pattern plugins are not supported")
+	fs.BoolVar(&p.config.TypedClients, "typed-clients", false, "generate typed clientsets, listers and "+
+		"informers of the APIs into pkg/client with code-generator on 'make generate', the Kinds created "+
+		"afterwards being marked with +genclient")
 
 	// boilerplate args
-	fs.StringVar(&p.license, "license", "apache2",
-		"license to use to boilerplate, may be one of 'apache2', 'none'")
+	fs.StringVar(&p.license, "license", license.Apache2, license.Description())
 	fs.StringVar(&p.owner, "owner", "", "owner to add to the copyright")
+	p.ownerFlag = fs.Lookup("owner")
+	fs.BoolVar(&p.ownerFromGit, "owner-from-git", true, "if --owner is not set, use the user.name and "+
+		"user.email of the git config as owner")
+	fs.StringVar(&p.copyrightYear, "copyright-year", "", "year of the copyright of the boilerplate header and "+
+		"the LICENSE file, or range of years, e.g. 2018-2020, defaults to the current year")
 
 	// project args
 	fs.StringVar(&p.config.Repo, "repo", "", "name to use for go module (e.g., github.com/user/repo), "+
-		"defaults to the go package of the current working directory.")
+		"defaults to the module path of an existing go.mod or the go package of the current working directory.")
+	fs.BoolVar(&p.monorepo, "monorepo", false, "initialize the project in a subdirectory of the Go module of a "+
+		"parent directory, sharing its go.mod instead of creating one, the repo being the package of the "+
+		"current directory")
 	fs.StringVar(&p.config.Domain, "domain", "my.domain", "domain for groups")
-	fs.StringVar(&p.config.ProjectName, "project-name", "", "name of this project")
+	fs.StringVar(&p.config.Profile, "profile", config.ProfileDefault,
+		fmt.Sprintf("layout profile selecting the directories of the APIs and controllers, may be one of (%s): "+
+			"%s lays them out in api/ and controllers/, %s in api/ and internal/controller/ and %s in pkg/apis/ "+
+			"and pkg/controller/", strings.Join(config.Profiles, ", "),
+			config.ProfileDefault, config.ProfileInternal, config.ProfileLegacy))
+	fs.StringVar(&p.config.ProjectName, "project-name", "", "name of this project, used for the manager image "+
+		"unless --image is set, the namespace and name prefix of the manifests and the labels of the manager, "+
+		"defaults to the name of the current directory")
+	fs.StringArrayVar(&p.values, "set", nil, "key=value pair exposed to the templates of this and the subsequent "+
+		"commands as .Values.<key>, may be repeated")
 }
 
 func (p *initPlugin) InjectConfig(c *config.Config) {
-	c.Layout = plugin.KeyFor(Plugin{})
 	p.config = c
 }
 
-func (p *initPlugin) Run() error {
-	return cmdutil.Run(p)
+func (p *initPlugin) Scaffold() error {
+	return cmdutil.Scaffold(p)
 }
 
 func (p *initPlugin) Validate() error {
@@ -111,6 +252,81 @@ func (p *initPlugin) Validate() error {
 		}
 	}
 
+	if _, err := license.Lookup(p.license); err != nil {
+		return err
+	}
+	if p.copyrightYear != "" {
+		if err := license.ValidateYear(p.copyrightYear); err != nil {
+			return err
+		}
+	}
+	if !p.ownerFlag.Changed && p.ownerFromGit {
+		if p.owner = util.GitAuthor(); p.owner != "" {
+			logger.Default().Info(fmt.Sprintf("Using %q of the git config as owner", p.owner))
+		}
+	}
+
+	values, err := util.ParseValues(p.values)
+	if err != nil {
+		return err
+	}
+	p.config.Values = values
+
+	// Dependencies are never downloaded in offline mode
+	if p.config.Offline {
+		if p.fetchDepsFlag.Changed && p.fetchDeps {
+			return errors.New("--fetch-deps can not be used with --offline")
+		}
+		p.fetchDeps = false
+	}
+	if p.config.SkipGoMod {
+		if p.fetchDepsFlag.Changed && p.fetchDeps {
+			return errors.New("--fetch-deps can not be used with --skip-go-mod-tidy")
+		}
+		p.fetchDeps = false
+	}
+
+	if !isTestFrameworkSupported(p.config.TestFramework) {
+		return fmt.Errorf("test framework %q is not supported, supported frameworks are (%s)",
+			p.config.TestFramework, strings.Join(testFrameworks, ", "))
+	}
+	// Only the frameworks other than the default one are recorded in the config
+	if p.config.TestFramework == config.TestFrameworkGinkgo {
+		p.config.TestFramework = ""
+	}
+
+	if err := p.webhookServer.apply(p.config); err != nil {
+		return err
+	}
+
+	// The Roles of a namespace-scoped manager only grant it access to its own namespace
+	if len(p.config.WatchNamespaces) != 0 && p.config.NamespaceScoped {
+		return errors.New("--watch-namespaces can not be used with --namespace-scoped")
+	}
+	watched := make(map[string]bool, len(p.config.WatchNamespaces))
+	for _, namespace := range p.config.WatchNamespaces {
+		if err := validation.IsDNS1123Label(namespace); err != nil {
+			return fmt.Errorf("namespace to watch (%s) is invalid: %v", namespace, err)
+		}
+		if watched[namespace] {
+			return fmt.Errorf("namespace %s is watched twice", namespace)
+		}
+		watched[namespace] = true
+	}
+
+	if err := p.config.ValidateProfile(); err != nil {
+		return err
+	}
+	// Only the profiles other than the default one are recorded in the config
+	if p.config.Profile == config.ProfileDefault {
+		p.config.Profile = ""
+	}
+
+	// Check if the targeted Go version is supported by this plugin.
+	if p.goVersion, err = validateGoVersion(p.goVersion); err != nil {
+		return err
+	}
+
 	// Check if the project name is a valid k8s namespace (DNS 1123 label).
 	if p.config.ProjectName == "" {
 		dir, err := os.Getwd()
@@ -122,6 +338,40 @@ func (p *initPlugin) Validate() error {
 	if err := validation.IsDNS1123Label(p.config.ProjectName); err != nil {
 		return fmt.Errorf("project name (%s) is invalid: %v", p.config.ProjectName, err)
 	}
+	if p.config.Image != "" {
+		if err := config.ValidateImage(p.config.Image); err != nil {
+			return err
+		}
+	}
+
+	if err := p.detectWorkspace(); err != nil {
+		return err
+	}
+
+	if p.monorepo {
+		if p.config.APIsModule {
+			return errors.New("--apis-module can not be used with --monorepo, the project having no go.mod")
+		}
+		return p.validateMonorepo()
+	}
+
+	// If a go.mod already exists, its module path is used as the repository, which must then match the flag.
+	if _, err := os.Stat("go.mod"); err == nil {
+		modulePath, err := util.FindModulePath("go.mod")
+		if err != nil {
+			return fmt.Errorf("error reading module path from existing go.mod: %v", err)
+		}
+		switch p.config.Repo {
+		case "":
+			p.config.Repo = modulePath
+		case modulePath:
+		default:
+			return fmt.Errorf("repository %q does not match module path %q declared in the existing go.mod",
+				p.config.Repo, modulePath)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("error checking for an existing go.mod: %v", err)
+	}
 
 	// Try to guess repository if flag is not set.
 	if p.config.Repo == "" {
@@ -135,20 +385,113 @@ func (p *initPlugin) Validate() error {
 	return nil
 }
 
+// detectWorkspace records the path of the project in the go.work workspace of the current directory, if any and
+// the workspace mode is not turned off.
+func (p *initPlugin) detectWorkspace() error {
+	if os.Getenv("GOWORK") == "off" {
+		return nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %v", err)
+	}
+	root, err := util.FindWorkspaceRoot(dir)
+	if err != nil {
+		return fmt.Errorf("error checking for a go.work file: %v", err)
+	}
+	if root == "" {
+		return nil
+	}
+	workspace, err := filepath.Rel(root, dir)
+	if err != nil {
+		return err
+	}
+	p.config.Workspace = filepath.ToSlash(workspace)
+	return nil
+}
+
+// validateMonorepo sets the repository and the base path of a project initialized in a subdirectory of the Go
+// module of a parent directory.
+func (p *initPlugin) validateMonorepo() error {
+	if _, err := os.Stat("go.mod"); err == nil {
+		return errors.New("--monorepo can not be used in a directory with a go.mod, the project would be a module")
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("error checking for an existing go.mod: %v", err)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %v", err)
+	}
+	root, err := util.FindModuleRoot(dir)
+	if err != nil {
+		return fmt.Errorf("error finding the Go module of the project: %v", err)
+	}
+	modulePath, err := util.FindModulePath(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return fmt.Errorf("error reading module path from the go.mod of %s: %v", root, err)
+	}
+	basePath, err := filepath.Rel(root, dir)
+	if err != nil {
+		return err
+	}
+	p.config.BasePath = filepath.ToSlash(basePath)
+
+	repo := path.Join(modulePath, p.config.BasePath)
+	switch p.config.Repo {
+	case "":
+		p.config.Repo = repo
+	case repo:
+	default:
+		return fmt.Errorf("repository %q does not match package %q of the current directory in module %q",
+			p.config.Repo, repo, modulePath)
+	}
+	return nil
+}
+
 func (p *initPlugin) GetScaffolder() (scaffold.Scaffolder, error) {
-	return scaffolds.NewInitScaffolder(p.config, p.license, p.owner), nil
+	return scaffolds.NewInitScaffolder(p.config, p.license, p.owner, p.copyrightYear, p.goVersion, p.baseImage,
+		p.qualityTargets, p.devContainer, p.apiDocs), nil
 }
 
 func (p *initPlugin) PostScaffold() error {
+	// The module of the project is added to the workspace, which does not require any download
+	if p.config.Workspace != "" {
+		module := "."
+		if p.config.BasePath != "" {
+			module = scaffolds.ModuleRoot(p.config.BasePath)
+		}
+		if err := util.RunCmd("Add the module to the go.work workspace", "go", "work", "use", module); err != nil {
+			return err
+		}
+	}
+
+	// The module of the APIs is required from its directory, which does not require any download either
+	if p.config.APIsModule {
+		if err := p.requireAPIsModule(); err != nil {
+			return err
+		}
+	}
+
+	if p.config.Offline {
+		logger.Default().Info(vendoringInstructions(p.dependencies()))
+		return nil
+	}
+
+	if p.config.SkipGoMod {
+		logger.Default().Info(fmt.Sprintf("go.mod and go.sum were left untouched and make was not run: add "+
+			"%s to the dependencies of the project, then run make.", strings.Join(p.dependencies(), ", ")))
+		return nil
+	}
+
 	if !p.fetchDeps {
-		fmt.Println("Skipping fetching dependencies.")
+		logger.Default().Info("Skipping fetching dependencies.")
 		return nil
 	}
 
 	// Ensure that we are pinning controller-runtime version
 	// xref: https://github.com/kubernetes-sigs/kubebuilder/issues/997
-	err := util.RunCmd("Get controller runtime", "go", "get",
-		"sigs.k8s.io/controller-runtime@"+scaffolds.ControllerRuntimeVersion)
+	err := util.RunCmd("Get dependencies", "go", append([]string{"get"}, p.dependencies()...)...)
 	if err != nil {
 		return err
 	}
@@ -164,6 +507,70 @@ func (p *initPlugin) PostScaffold() error {
 		return err
 	}
 
-	fmt.Printf("Next: define a resource with:\n$ %s create api\n", p.commandName)
+	logger.Default().Info(fmt.Sprintf("Next: define a resource with:\n$ %s create api", p.commandName))
 	return nil
 }
+
+// requireAPIsModule adds the Go module of the APIs to the requirements of the go.mod, replaced by its directory.
+func (p *initPlugin) requireAPIsModule() error {
+	dir := p.config.GetDirectories().APIs
+	module := path.Join(p.config.Repo, dir)
+	if p.config.SkipGoMod {
+		logger.Default().Info(fmt.Sprintf("go.mod was left untouched: require %s, replaced by ./%s.", module, dir))
+		return nil
+	}
+	return util.RunCmd("Require the module of the APIs", "go", "mod", "edit",
+		"-require="+module+"@v0.0.0", "-replace="+module+"=./"+dir)
+}
+
+// dependencies returns the modules the project is pinned to, with their versions.
+func (p *initPlugin) dependencies() []string {
+	deps := []string{"sigs.k8s.io/controller-runtime@" + scaffolds.ControllerRuntimeVersion}
+	if p.config.Tracing {
+		for _, module := range []string{"otel", "otel/sdk", "otel/exporters/otlp"} {
+			deps = append(deps, "go.opentelemetry.io/"+module+"@"+scaffolds.OpenTelemetryVersion)
+		}
+	}
+	return deps
+}
+
+// vendoringInstructions explains how to provide the dependencies of a project scaffolded in offline mode, deps
+// being the modules it is pinned to.
+func vendoringInstructions(deps []string) string {
+	return fmt.Sprintf(`Offline mode: dependencies were not downloaded and make was not run.
+To provide them, from a copy of the project on a machine with network access:
+$ go get %s
+$ go mod tidy
+$ go mod vendor
+$ make tools
+Then copy back go.mod, go.sum, the vendor/ directory, hack/tools/go.sum and the bin/ directory, where
+'make tools' built the versions of controller-gen and kustomize pinned in hack/tools/go.mod, so that the
+Makefile does not download them. Build with GOFLAGS=-mod=vendor to use the vendored dependencies.`,
+		strings.Join(deps, " "))
+}
+
+// validateGoVersion returns version without its optional "go" prefix, or an error if it is not one of the Go
+// versions this plugin can scaffold for.
+func validateGoVersion(version string) (string, error) {
+	version = strings.TrimPrefix(version, "go")
+	for _, supported := range scaffolds.SupportedGoVersions {
+		if version == supported {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("go version %q is not supported, supported versions are (%s)",
+		version, strings.Join(scaffolds.SupportedGoVersions, ", "))
+}
+
+// testFrameworks are the frameworks the scaffolded tests can be written with
+var testFrameworks = []string{config.TestFrameworkGinkgo, config.TestFrameworkGinkgoV2, config.TestFrameworkGoTest}
+
+// isTestFrameworkSupported returns true if framework is one of the frameworks this plugin can scaffold tests with.
+func isTestFrameworkSupported(framework string) bool {
+	for _, supported := range testFrameworks {
+		if framework == supported {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds"
+)
+
+var _ = Describe("validateGoVersion", func() {
+	DescribeTable("should accept the supported Go versions",
+		func(version, expected string) {
+			Expect(validateGoVersion(version)).To(Equal(expected))
+		},
+		Entry("for the default version", scaffolds.DefaultGoVersion, scaffolds.DefaultGoVersion),
+		Entry("for the latest supported version", "1.15", "1.15"),
+		Entry("for a version prefixed with go", "go1.14", "1.14"),
+	)
+
+	DescribeTable("should reject the other Go versions",
+		func(version string) {
+			_, err := validateGoVersion(version)
+			Expect(err).To(MatchError(ContainSubstring("is not supported, supported versions are (1.13, 1.14, 1.15)")))
+		},
+		Entry("for an older version", "1.12"),
+		Entry("for a newer version", "1.16"),
+		Entry("for a patch version", "1.15.2"),
+		Entry("for an empty version", ""),
+	)
+})
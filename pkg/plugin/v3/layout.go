@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin"
+)
+
+var _ plugin.LayoutDescriber = Plugin{}
+
+// DescribeLayout implements plugin.LayoutDescriber
+func (Plugin) DescribeLayout(c *config.Config) map[string]string {
+	layout := map[string]string{
+		".dockerignore":           "files excluded from the context of the manager image build",
+		".gitignore":              "files excluded from version control, like binaries and test assets",
+		"Dockerfile":              "multi-stage build of the manager image",
+		"Makefile":                "targets to generate code and manifests, test, build, run and deploy the manager",
+		"go.mod":                  "Go module of the project, pinning controller-runtime",
+		"main.go":                 "entrypoint of the manager, which registers the controllers and webhooks",
+		"hack":                    "helper files for development",
+		"hack/boilerplate.go.txt": "license header prepended to generated and scaffolded Go files",
+		"hack/tools":              "Go module pinning the versions of the tools the Makefile builds into bin/",
+	}
+	dirs := c.GetDirectories()
+	if c.MultiGroup {
+		layout[dirs.Controllers] = "reconcilers of the project resources, one package per group, " +
+			"each with the envtest suite that tests them"
+	} else {
+		layout[dirs.Controllers] = "reconcilers of the project resources and the envtest suite that tests them"
+	}
+	if strings.Contains(dirs.Types, "%[group]") {
+		layout[dirs.APIs] = "Go types of the project resources, one package per group and version"
+	} else {
+		layout[dirs.APIs] = "Go types of the project resources, one package per version, " +
+			"along with their webhooks and the registration of their group version"
+	}
+	// The manager image of a project in a subdirectory is built from the root of its module or workspace
+	switch {
+	case c.Workspace != "" && c.Workspace != ".":
+		delete(layout, ".dockerignore")
+		layout["Dockerfile"] = "multi-stage build of the manager image, from the root of the go.work workspace"
+		layout["Dockerfile.dockerignore"] = "files excluded from the context of the manager image build"
+	case c.Workspace == "" && c.BasePath != "":
+		delete(layout, ".dockerignore")
+		layout["Dockerfile"] = "multi-stage build of the manager image, from the root of the Go module"
+		layout["Dockerfile.dockerignore"] = "files excluded from the context of the manager image build"
+	}
+	if c.BasePath != "" {
+		// The project shares the go.mod of its module
+		delete(layout, "go.mod")
+	}
+	if c.APIsModule {
+		layout[dirs.APIs+"/go.mod"] = "Go module of the APIs, only depending on the Kubernetes API machinery so that " +
+			"other projects can import their types"
+	}
+	if c.TypedClients {
+		layout["hack/update-codegen.sh"] = "script generating the typed clients of the APIs with code-generator"
+		layout["pkg/client"] = "typed clientsets, listers and informers of the APIs, generated by 'make clients'"
+	}
+	return layout
+}
@@ -21,7 +21,9 @@ import (
 	"sigs.k8s.io/kubebuilder/pkg/plugin"
 )
 
-const pluginName = "go" + plugin.DefaultNameQualifier
+// pluginName is prefixed with "base" as this plugin only scaffolds the Go code of a project,
+// it is bundled with the kustomize plugin to provide "go.kubebuilder.io/v3-alpha".
+const pluginName = "base.go" + plugin.DefaultNameQualifier
 
 var (
 	supportedProjectVersions = []string{config.Version3Alpha}
@@ -33,12 +35,16 @@ var (
 	_ plugin.InitPluginGetter          = Plugin{}
 	_ plugin.CreateAPIPluginGetter     = Plugin{}
 	_ plugin.CreateWebhookPluginGetter = Plugin{}
+	_ plugin.DeleteAPIPluginGetter     = Plugin{}
+	_ plugin.DeleteWebhookPluginGetter = Plugin{}
 )
 
 type Plugin struct {
 	initPlugin
 	createAPIPlugin
 	createWebhookPlugin
+	deleteAPIPlugin
+	deleteWebhookPlugin
 }
 
 func (Plugin) Name() string                                   { return pluginName }
@@ -47,3 +53,5 @@ func (Plugin) SupportedProjectVersions() []string             { return supported
 func (p Plugin) GetInitPlugin() plugin.Init                   { return &p.initPlugin }
 func (p Plugin) GetCreateAPIPlugin() plugin.CreateAPI         { return &p.createAPIPlugin }
 func (p Plugin) GetCreateWebhookPlugin() plugin.CreateWebhook { return &p.createWebhookPlugin }
+func (p Plugin) GetDeleteAPIPlugin() plugin.DeleteAPI         { return &p.deleteAPIPlugin }
+func (p Plugin) GetDeleteWebhookPlugin() plugin.DeleteWebhook { return &p.deleteWebhookPlugin }
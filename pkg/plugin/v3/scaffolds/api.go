@@ -18,18 +18,22 @@ package scaffolds
 
 import (
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
 	"sigs.k8s.io/kubebuilder/pkg/model/resource"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/machinery"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/api"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/controller"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/crd"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/rbac"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/samples"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/hack"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/monitoring"
 )
 
 var _ scaffold.Scaffolder = &apiScaffolder{}
@@ -46,6 +50,25 @@ type apiScaffolder struct {
 	doResource bool
 	// doController indicates whether to scaffold controller files or not
 	doController bool
+	// force indicates whether to keep the existing files instead of failing
+	force bool
+	// withPredicates indicates whether to filter the events that trigger reconciles in the controller
+	withPredicates bool
+	// validationMarkers indicates whether to add example validation markers to the types, along with their tests
+	validationMarkers bool
+	// serverSideApply indicates whether to generate the apply configuration of the resource, server-side applied
+	// by the controller
+	serverSideApply bool
+	// withEvents indicates whether the controller records events on the objects it reconciles
+	withEvents bool
+	// withControllerOptions indicates whether the options of the controller are bound to flags of main.go
+	withControllerOptions bool
+	// withMetrics indicates whether the controller counts its reconciliations with the metrics of monitoring
+	withMetrics bool
+	// withConditions indicates whether to add the Conditions to the status, maintained by the controller
+	withConditions bool
+	// values are the values of the command, overriding the ones of the config
+	values map[string]string
 }
 
 // NewAPIScaffolder returns a new Scaffolder for API/controller creation operations
@@ -53,22 +76,34 @@ func NewAPIScaffolder(
 	config *config.Config,
 	boilerplate string,
 	res *resource.Resource,
-	doResource, doController bool,
+	doResource, doController, force, withPredicates, validationMarkers, serverSideApply, withEvents,
+	withControllerOptions, withMetrics, withConditions bool,
+	values map[string]string,
 	plugins []model.Plugin,
 ) scaffold.Scaffolder {
 	return &apiScaffolder{
-		config:       config,
-		boilerplate:  boilerplate,
-		resource:     res,
-		plugins:      plugins,
-		doResource:   doResource,
-		doController: doController,
+		config:            config,
+		boilerplate:       boilerplate,
+		resource:          res,
+		plugins:           plugins,
+		doResource:        doResource,
+		doController:      doController,
+		force:             force,
+		withPredicates:    withPredicates,
+		validationMarkers: validationMarkers,
+		serverSideApply:   serverSideApply,
+		withEvents:        withEvents,
+
+		withControllerOptions: withControllerOptions,
+		withMetrics:           withMetrics,
+		withConditions:        withConditions,
+		values:                values,
 	}
 }
 
 // Scaffold implements Scaffolder
 func (s *apiScaffolder) Scaffold() error {
-	fmt.Println("Writing scaffold for you to edit...")
+	logger.Default().Info("Writing scaffold for you to edit...")
 	return s.scaffold()
 }
 
@@ -77,53 +112,141 @@ func (s *apiScaffolder) newUniverse() *model.Universe {
 		model.WithConfig(s.config),
 		model.WithBoilerplate(s.boilerplate),
 		model.WithResource(s.resource),
+		model.WithValues(s.values),
 	)
 }
 
 // TODO: re-use universe created by s.newUniverse() if possible.
 func (s *apiScaffolder) scaffold() error {
-	if s.doResource {
-		s.config.AddResource(s.resource.GVK())
+	if s.withControllerOptions {
+		if err := requireFlagsMarker("main.go"); err != nil {
+			return err
+		}
+	}
 
-		if err := machinery.NewScaffold(s.plugins...).Execute(
-			s.newUniverse(),
-			&api.Types{},
-			&api.Group{},
-			&samples.CRDSample{},
-			&rbac.CRDEditorRole{},
-			&rbac.CRDViewerRole{},
-			&crd.EnableWebhookPatch{},
-			&crd.EnableCAInjectionPatch{},
-		); err != nil {
+	if s.doResource {
+		s.config.UpdateResource(s.resource.GVK())
+
+		builders := []file.Builder{
+			&api.Types{
+				Force:               s.force,
+				ValidationMarkers:   s.validationMarkers,
+				DefaultingMarkers:   s.resource.Defaults == config.DefaultsMarkers,
+				TypedClients:        s.config.TypedClients,
+				ApplyConfigurations: s.serverSideApply,
+				WithConditions:      s.withConditions,
+			},
+			&api.Group{TypedClients: s.config.TypedClients, APIsModule: s.config.APIsModule},
+		}
+		if s.withConditions {
+			builders = append(builders, &api.Conditions{})
+		}
+		if err := machinery.NewScaffold(s.plugins...).Execute(s.newUniverse(), builders...); err != nil {
 			return fmt.Errorf("error scaffolding APIs: %v", err)
 		}
 
-		if err := machinery.NewScaffold().Execute(
-			s.newUniverse(),
-			&crd.Kustomization{},
-			&crd.KustomizeConfig{},
-		); err != nil {
-			return fmt.Errorf("error scaffolding kustomization: %v", err)
+		if err := s.scaffoldConversion(); err != nil {
+			return err
 		}
-
 	}
 
 	if s.doController {
-		if err := machinery.NewScaffold(s.plugins...).Execute(
-			s.newUniverse(),
-			&controller.SuiteTest{},
-			&controller.Controller{},
-		); err != nil {
+		builders := []file.Builder{
+			&controller.SuiteTest{TestFramework: s.config.TestFramework},
+			// The samples are validated against the CRDs loaded by the suite
+			&controller.SamplesTest{TestFramework: s.config.TestFramework},
+			&controller.Controller{
+				Force:           s.force,
+				WithPredicates:  s.withPredicates,
+				ServerSideApply: s.serverSideApply,
+				WithEvents:      s.withEvents,
+				WithMetrics:     s.withMetrics,
+				WithConditions:  s.withConditions,
+				Tracing:         s.config.Tracing,
+				NamespaceScoped: s.config.NamespaceScoped,
+
+				WithControllerOptions: s.withControllerOptions,
+			},
+		}
+		if s.withPredicates {
+			builders = append(builders, &controller.Predicates{})
+		}
+		if s.withControllerOptions {
+			builders = append(builders, &controller.Options{})
+		}
+		if s.withMetrics {
+			builders = append(builders, &monitoring.Metrics{}, &monitoring.MetricsTest{})
+		}
+		// The tests create objects of the resource, so only the resources of the project get some
+		if (s.config.UsesGinkgoV2() || s.config.UsesGoTest()) && s.config.HasResource(s.resource.GVK()) {
+			builders = append(builders, &controller.ControllerTest{TestFramework: s.config.TestFramework})
+		}
+		// The validation markers are enforced by the API server of the envtest environment of the suite
+		if s.validationMarkers {
+			builders = append(builders, &controller.ValidationTest{TestFramework: s.config.TestFramework})
+		}
+		if err := machinery.NewScaffold(s.plugins...).Execute(s.newUniverse(), builders...); err != nil {
 			return fmt.Errorf("error scaffolding controller: %v", err)
 		}
 	}
 
 	if err := machinery.NewScaffold(s.plugins...).Execute(
 		s.newUniverse(),
-		&templates.MainUpdater{WireResource: s.doResource, WireController: s.doController},
+		&templates.MainUpdater{WireResource: s.doResource, WireController: s.doController,
+			WireControllerOptions: s.withControllerOptions},
 	); err != nil {
 		return fmt.Errorf("error updating main.go: %v", err)
 	}
 
+	if s.doResource && s.resource.Defaults == config.DefaultsWebhook {
+		webhooks := &webhookScaffolder{
+			config:      s.config,
+			boilerplate: s.boilerplate,
+			resource:    s.resource,
+			defaulting:  true,
+		}
+		if err := webhooks.scaffold(); err != nil {
+			return fmt.Errorf("error scaffolding the defaulting webhook: %v", err)
+		}
+	}
+
+	if s.doResource {
+		if err := updateMakefile("Makefile", s.resource); err != nil {
+			return fmt.Errorf("error updating Makefile: %v", err)
+		}
+	}
+	if s.validationMarkers {
+		if err := requireCELValidation("Makefile"); err != nil {
+			return fmt.Errorf("error updating Makefile: %v", err)
+		}
+	}
+	if s.serverSideApply {
+		if err := machinery.NewScaffold().Execute(
+			s.newUniverse(),
+			&hack.UpdateApplyConfigurations{BoilerplatePath: filepath.Join("hack", "boilerplate.go.txt")},
+		); err != nil {
+			return fmt.Errorf("error scaffolding the apply configurations script: %v", err)
+		}
+		if err := requireApplyConfigurations("Makefile"); err != nil {
+			return fmt.Errorf("error updating Makefile: %v", err)
+		}
+	}
+
 	return nil
 }
+
+// requireFlagsMarker returns an error if the main.go file at path lacks the marker before which the flags of the
+// options of the controllers are bound, as the ones scaffolded before it was introduced do.
+func requireFlagsMarker(path string) error {
+	content, err := ioutil.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", path, err)
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == templates.FlagsMarker.String() {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s has no %q line, add it before the call to flag.Parse() for the flags of the options "+
+		"of the controller to be bound there", path, templates.FlagsMarker.String())
+}
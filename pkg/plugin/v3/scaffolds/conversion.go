@@ -0,0 +1,150 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/api"
+)
+
+const (
+	rootMarker           = "// +kubebuilder:object:root=true\n"
+	storageVersionMarker = "// +kubebuilder:storageversion"
+)
+
+// scaffoldConversion scaffolds the conversion of the resource to and from the hub of its Kind if another version
+// of the Kind is tracked. The hub is the version recorded as such in the config, or the first tracked one, which
+// is then recorded.
+func (s *apiScaffolder) scaffoldConversion() error {
+	hubIndex := -1
+	for i, r := range s.config.Resources {
+		if r.Group != s.resource.Group || r.Kind != s.resource.Kind {
+			continue
+		}
+		if r.Version == s.resource.Version {
+			if r.Hub {
+				return nil
+			}
+			continue
+		}
+		if hubIndex == -1 || (r.Hub && !s.config.Resources[hubIndex].Hub) {
+			hubIndex = i
+		}
+	}
+	if hubIndex == -1 {
+		return nil
+	}
+
+	s.config.Resources[hubIndex].Hub = true
+	hubGVK := s.config.Resources[hubIndex]
+	hubOptions := &resource.Options{Group: hubGVK.Group, Version: hubGVK.Version, Kind: hubGVK.Kind,
+		Plural: hubGVK.Plural}
+	hub := hubOptions.NewResource(s.config, true)
+
+	if err := machinery.NewScaffold(s.plugins...).Execute(
+		model.NewUniverse(
+			model.WithConfig(s.config),
+			model.WithBoilerplate(s.boilerplate),
+			model.WithResource(hub),
+		),
+		&api.ConversionHub{},
+	); err != nil {
+		return fmt.Errorf("error scaffolding the conversion hub: %v", err)
+	}
+	if err := machinery.NewScaffold(s.plugins...).Execute(
+		s.newUniverse(),
+		&api.Conversion{Hub: hub},
+		&api.ConversionTest{Hub: hub},
+	); err != nil {
+		return fmt.Errorf("error scaffolding the conversion: %v", err)
+	}
+	logger.Default().Info(fmt.Sprintf("%s is the conversion hub of %s, %s converts to and from it",
+		hub.Version, hub.Kind, s.resource.Version))
+
+	if err := s.markStorageVersion(hub); err != nil {
+		return fmt.Errorf("error marking the storage version: %v", err)
+	}
+	if hubGVK.WebhookVersion == "" {
+		logger.Default().Info(fmt.Sprintf("The conversions are served by a webhook, scaffold it with "+
+			"create webhook --group %s --version %s --kind %s --conversion", hub.Group, hub.Version, hub.Kind))
+	}
+	return nil
+}
+
+// markStorageVersion adds the storage version marker to the type of the hub, unless a version of the Kind
+// already has it, as a CRD serving several versions must store exactly one of them.
+func (s *apiScaffolder) markStorageVersion(hub *resource.Resource) error {
+	var hubPath string
+	for _, r := range s.config.Resources {
+		if r.Group != hub.Group || r.Kind != hub.Kind {
+			continue
+		}
+		path := s.typesPath(r.Group, r.Version, r.Kind)
+		content, err := ioutil.ReadFile(path) // nolint:gosec
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if strings.Contains(string(content), storageVersionMarker) {
+			return nil
+		}
+		if r.Version == hub.Version {
+			hubPath = path
+		}
+	}
+	if hubPath == "" {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(hubPath) // nolint:gosec
+	if err != nil {
+		return err
+	}
+	typeIndex := strings.Index(string(content), "\ntype "+hub.Kind+" struct")
+	markerIndex := strings.LastIndex(string(content[:typeIndex+1]), rootMarker)
+	if typeIndex == -1 || markerIndex == -1 || strings.Contains(string(content[markerIndex:typeIndex]), "\ntype ") {
+		logger.Default().Info(fmt.Sprintf("Add %s to the type of the version of %s to store in %s",
+			storageVersionMarker, hub.Kind, hubPath))
+		return nil
+	}
+
+	insertAt := markerIndex + len(rootMarker)
+	updated := string(content[:insertAt]) + storageVersionMarker + "\n" + string(content[insertAt:])
+	// false positive
+	// nolint:gosec
+	if err := ioutil.WriteFile(hubPath, []byte(updated), 0644); err != nil {
+		return err
+	}
+	logger.Default().Info(fmt.Sprintf("%s is stored by the API server, as marked with %s in %s, "+
+		"move the marker to store another version", hub.Version, storageVersionMarker, hubPath))
+	return nil
+}
+
+// typesPath returns the path of the file of the types of a resource of the project
+func (s *apiScaffolder) typesPath(group, version, kind string) string {
+	return filepath.Join(s.config.GetDirectories().TypesOf(group, version), strings.ToLower(kind)+"_types.go")
+}
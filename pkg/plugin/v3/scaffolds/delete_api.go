@@ -0,0 +1,270 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/controller"
+)
+
+const deepCopyFile = "zz_generated.deepcopy.go"
+
+var _ scaffold.Scaffolder = &deleteAPIScaffolder{}
+
+// deleteAPIScaffolder removes the Go types and the controller of a resource, and their registration.
+type deleteAPIScaffolder struct {
+	config   *config.Config
+	resource *resource.Resource
+}
+
+// NewDeleteAPIScaffolder returns a new Scaffolder for API/controller deletion operations
+func NewDeleteAPIScaffolder(config *config.Config, res *resource.Resource) scaffold.Scaffolder {
+	return &deleteAPIScaffolder{
+		config:   config,
+		resource: res,
+	}
+}
+
+// Scaffold implements Scaffolder
+func (s *deleteAPIScaffolder) Scaffold() error {
+	dirs := s.config.GetDirectories()
+	replacer := s.resource.Replacer()
+	apiDir, controllersDir := replacer.Replace(dirs.Types), replacer.Replace(dirs.Controller)
+	typesPath := filepath.Join(apiDir, replacer.Replace("%[kind]_types.go"))
+	controllerPath := filepath.Join(controllersDir, replacer.Replace("%[kind]_controller.go"))
+
+	// The webhook is deleted on its own, as its manifests are not removed with the resource
+	webhookPath := filepath.Join(apiDir, replacer.Replace("%[kind]_webhook.go"))
+	if _, err := os.Stat(webhookPath); err == nil {
+		return fmt.Errorf("%s exists, delete the webhook of the resource first", webhookPath)
+	}
+
+	// The other versions of the Kind are converted to and from the hub
+	var otherVersions []int
+	isHub := false
+	for i, r := range s.config.Resources {
+		if r.Group != s.resource.Group || r.Kind != s.resource.Kind {
+			continue
+		}
+		if r.Version == s.resource.Version {
+			isHub = isHub || r.Hub
+		} else {
+			otherVersions = append(otherVersions, i)
+		}
+	}
+	if isHub && len(otherVersions) != 0 {
+		return fmt.Errorf("%s is the conversion hub of %s, delete its other versions first",
+			s.resource.Version, s.resource.Kind)
+	}
+
+	logger.Default().Info("Deleting the scaffold of the resource...")
+	// Without spokes left, the hub has nothing to convert
+	if len(otherVersions) == 1 && s.config.Resources[otherVersions[0]].Hub {
+		hub := s.config.Resources[otherVersions[0]]
+		s.config.Resources[otherVersions[0]].Hub = false
+		hubDir := dirs.TypesOf(hub.Group, hub.Version)
+		if _, err := removeFiles(filepath.Join(hubDir, replacer.Replace("%[kind]_conversion.go"))); err != nil {
+			return err
+		}
+	}
+	s.config.RemoveResource(s.resource.GVK())
+	versionTracked := false
+	for _, r := range s.config.Resources {
+		if r.Group == s.resource.Group && r.Version == s.resource.Version {
+			versionTracked = true
+		}
+	}
+
+	types, err := declaredTypes(typesPath)
+	if err != nil {
+		return err
+	}
+	removed := []string{typesPath, controllerPath, strings.TrimSuffix(controllerPath, ".go") + "_test.go",
+		filepath.Join(apiDir, replacer.Replace("%[kind]_conversion.go")),
+		filepath.Join(apiDir, replacer.Replace("%[kind]_conversion_test.go"))}
+	if !versionTracked {
+		removed = append(removed, filepath.Join(apiDir, "groupversion_info.go"), filepath.Join(apiDir, deepCopyFile),
+			filepath.Join(apiDir, "webhook_suite_test.go"), filepath.Join(apiDir, "conditions.go"))
+	} else if err := removeDeepCopies(filepath.Join(apiDir, deepCopyFile), types); err != nil {
+		return err
+	}
+	existed, err := removeFiles(removed...)
+	if err != nil {
+		return err
+	}
+	if !versionTracked {
+		// The directory of the version is only removed if no other file, e.g. a webhook, was added to it,
+		// and so is the one of the group
+		_ = os.Remove(apiDir)
+		if s.config.MultiGroup {
+			_ = os.Remove(filepath.Dir(apiDir))
+		}
+	}
+
+	if err := s.updateMain(existed[controllerPath], !versionTracked); err != nil {
+		return fmt.Errorf("error updating main.go: %v", err)
+	}
+	if !versionTracked {
+		apiImport, addScheme := controller.SuiteTestFragments(s.resource, s.config.TestFramework)
+		if err := util.RemoveCodeFragments(filepath.Join(controllersDir, "suite_test.go"),
+			apiImport, addScheme); err != nil {
+			return fmt.Errorf("error updating suite_test.go: %v", err)
+		}
+	}
+	return nil
+}
+
+// updateMain removes the setup of the reconciler from main.go, if it was scaffolded, and the registration
+// of the version if it has no resource left.
+func (s *deleteAPIScaffolder) updateMain(reconciler, version bool) error {
+	const mainPath = "main.go"
+	fragments := templates.NewMainFragments(s.config.Repo, s.config.MultiGroup, s.config.GetDirectories(), s.resource)
+
+	var removed []string
+	if reconciler {
+		removed = append(removed, fragments.ReconcilerSetup, fragments.ReconcilerSetupWithOptions,
+			fragments.ControllerOptionsFlags)
+	}
+	if version {
+		removed = append(removed, fragments.APIImport, fragments.AddScheme)
+	}
+	if err := util.RemoveCodeFragments(mainPath, removed...); err != nil {
+		return err
+	}
+	if !reconciler {
+		return nil
+	}
+
+	// The controllers package is only imported while another reconciler of the package is set up
+	content, err := ioutil.ReadFile(mainPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	controllersPackage := s.config.GetDirectories().ControllersPackage
+	if s.config.MultiGroup {
+		controllersPackage = s.resource.GroupPackageName + controllersPackage
+	}
+	if strings.Contains(string(content), "&"+controllersPackage+".") {
+		return nil
+	}
+	return util.RemoveCodeFragments(mainPath, fragments.ControllerImport)
+}
+
+// removeFiles removes the files at paths that exist, which are returned.
+func removeFiles(paths ...string) (map[string]bool, error) {
+	existed := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		err := os.Remove(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		logger.Default().Info(fmt.Sprintf("Deleted %s", path))
+		existed[path] = true
+	}
+	return existed, nil
+}
+
+// declaredTypes returns the names of the types declared in the Go file at path, if it exists.
+func declaredTypes(path string) (map[string]bool, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	types := make(map[string]bool)
+	for _, decl := range f.Decls {
+		if gen, isGen := decl.(*ast.GenDecl); isGen && gen.Tok == token.TYPE {
+			for _, spec := range gen.Specs {
+				types[spec.(*ast.TypeSpec).Name.Name] = true
+			}
+		}
+	}
+	return types, nil
+}
+
+// removeDeepCopies removes the methods of the provided types, along with their doc comments, from the generated
+// deepcopy file at path, so that the package still builds until the file is generated again.
+func removeDeepCopies(path string, types map[string]bool) error {
+	fset := token.NewFileSet()
+	content, err := ioutil.ReadFile(path) // nolint:gosec
+	if os.IsNotExist(err) || len(types) == 0 {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	f, err := parser.ParseFile(fset, path, content, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	// Offsets of the methods to remove, which are removed from the last one so that the others stay valid
+	var ranges [][2]int
+	for _, decl := range f.Decls {
+		fn, isFunc := decl.(*ast.FuncDecl)
+		if !isFunc || fn.Recv == nil || len(fn.Recv.List) != 1 {
+			continue
+		}
+		recv := fn.Recv.List[0].Type
+		if star, isStar := recv.(*ast.StarExpr); isStar {
+			recv = star.X
+		}
+		if ident, isIdent := recv.(*ast.Ident); !isIdent || !types[ident.Name] {
+			continue
+		}
+		start := fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		ranges = append(ranges, [2]int{fset.Position(start).Offset, fset.Position(fn.End()).Offset})
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] > ranges[j][0] })
+	for _, r := range ranges {
+		content = append(content[:r[0]], content[r[1]:]...)
+	}
+
+	if content, err = format.Source(content); err != nil {
+		return err
+	}
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(path, content, 0644)
+}
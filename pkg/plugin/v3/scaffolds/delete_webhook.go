@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/api"
+)
+
+var _ scaffold.Scaffolder = &deleteWebhookScaffolder{}
+
+// deleteWebhookScaffolder removes the webhooks of a resource and their registration.
+type deleteWebhookScaffolder struct {
+	config   *config.Config
+	resource *resource.Resource
+}
+
+// NewDeleteWebhookScaffolder returns a new Scaffolder for webhook deletion operations
+func NewDeleteWebhookScaffolder(config *config.Config, res *resource.Resource) scaffold.Scaffolder {
+	return &deleteWebhookScaffolder{
+		config:   config,
+		resource: res,
+	}
+}
+
+// Scaffold implements Scaffolder
+func (s *deleteWebhookScaffolder) Scaffold() error {
+	webhookPath := s.resource.Replacer().Replace(filepath.Join(s.config.GetDirectories().Types, "%[kind]_webhook.go"))
+	if _, err := os.Stat(webhookPath); os.IsNotExist(err) {
+		return fmt.Errorf("%s does not exist, the resource has no webhook to delete", webhookPath)
+	} else if err != nil {
+		return err
+	}
+
+	logger.Default().Info("Deleting the webhooks of the resource...")
+	if _, err := removeFiles(webhookPath, strings.TrimSuffix(webhookPath, ".go")+"_test.go"); err != nil {
+		return err
+	}
+
+	suitePath := filepath.Join(filepath.Dir(webhookPath), "webhook_suite_test.go")
+	setup := api.WebhookSuiteTestFragment(s.resource, s.config.TestFramework)
+	if err := util.RemoveCodeFragments(suitePath, setup); err != nil {
+		return fmt.Errorf("error updating %s: %v", suitePath, err)
+	}
+
+	fragments := templates.NewMainFragments(s.config.Repo, s.config.MultiGroup, s.config.GetDirectories(), s.resource)
+	// The legacy fragment is removed after the current one, which contains its lines
+	for _, fragment := range []string{fragments.WebhookSetup, fragments.LegacyWebhookSetup} {
+		if err := util.RemoveCodeFragments("main.go", fragment); err != nil {
+			return fmt.Errorf("error updating main.go: %v", err)
+		}
+	}
+
+	// The resource no longer has webhook configuration manifests, nor webhooks
+	gvk := s.resource.GVK()
+	for i, r := range s.config.Resources {
+		if r.Group == gvk.Group && r.Version == gvk.Version && r.Kind == gvk.Kind {
+			s.config.Resources[i].WebhookVersion = ""
+			s.config.Resources[i].ValidatingWebhook, s.config.Resources[i].ConversionWebhook = false, false
+			if r.Defaults == config.DefaultsWebhook {
+				s.config.Resources[i].Defaults = ""
+			}
+		}
+	}
+	return nil
+}
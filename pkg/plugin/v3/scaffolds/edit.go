@@ -19,9 +19,15 @@ package scaffolds
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
 	"strings"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/util"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
 )
 
@@ -30,47 +36,149 @@ var _ scaffold.Scaffolder = &editScaffolder{}
 type editScaffolder struct {
 	config     *config.Config
 	multigroup bool
+	domain     string
+	image      string
 }
 
-// NewEditScaffolder returns a new Scaffolder for configuration edit operations
-func NewEditScaffolder(config *config.Config, multigroup bool) scaffold.Scaffolder {
+// NewEditScaffolder returns a new Scaffolder for configuration edit operations.
+// An empty domain keeps the domain of the project, and an empty image its manager image.
+func NewEditScaffolder(config *config.Config, multigroup bool, domain, image string) scaffold.Scaffolder {
 	return &editScaffolder{
 		config:     config,
 		multigroup: multigroup,
+		domain:     domain,
+		image:      image,
 	}
 }
 
 // Scaffold implements Scaffolder
 func (s *editScaffolder) Scaffold() error {
+	if s.image != "" && s.image != s.config.Image {
+		if err := s.changeImage(); err != nil {
+			return err
+		}
+	}
+
+	if s.domain != "" && s.domain != s.config.Domain {
+		if err := s.changeDomain(); err != nil {
+			return err
+		}
+		// The Dockerfile is only updated when the layout changes
+		if s.multigroup == s.config.MultiGroup {
+			return nil
+		}
+	}
+
+	apisDir := s.config.GetDirectories().APIs
 	s.config.MultiGroup = s.multigroup
+	// The Dockerfile of a project in a workspace copies it whole, whatever the layout
+	if s.config.Workspace != "" || s.config.GetDirectories().APIs == apisDir {
+		return nil
+	}
 	filename := "Dockerfile"
 	bs, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return err
 	}
-	str := string(bs)
 
 	// update dockerfile
-	if s.multigroup {
-		str, err = ensureExistAndReplace(
-			str,
-			"COPY api/ api/",
-			`COPY apis/ apis/`)
-		if err != nil {
+	str, err := ensureExistAndReplace(
+		string(bs),
+		s.copyDirective(apisDir),
+		s.copyDirective(s.config.GetDirectories().APIs))
+	if err != nil {
+		return err
+	}
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(filename, []byte(str), 0644)
+}
+
+// copyDirective returns the Dockerfile directive copying dir of the project, whose path in the build context is
+// prefixed with the base path of the project in its Go module.
+func (s *editScaffolder) copyDirective(dir string) string {
+	dir = path.Join(s.config.BasePath, dir) + "/"
+	return fmt.Sprintf("COPY %s %s", dir, dir)
+}
+
+// changeDomain moves the groups of the tracked resources to the new domain
+func (s *editScaffolder) changeDomain() error {
+	groups := make([]string, 0, len(s.config.Resources))
+	seen := make(map[string]bool, len(s.config.Resources))
+	for _, res := range s.config.Resources {
+		if res.Group != "" && !seen[res.Group] {
+			seen[res.Group] = true
+			groups = append(groups, res.Group)
+		}
+	}
+
+	pending, err := util.ChangeDomain(".", s.config.Repo, groups, s.config.Domain, s.domain)
+	if err != nil {
+		return err
+	}
+	// The PROJECT file is saved with the new domain afterwards
+	for i, path := range pending {
+		if path == "PROJECT" {
+			pending = append(pending[:i], pending[i+1:]...)
+			break
+		}
+	}
+	if len(pending) != 0 {
+		logger.Default().Info(fmt.Sprintf("The following files still reference the domain %q, "+
+			"review them manually:\n%s", s.config.Domain, strings.Join(pending, "\n")))
+	}
+	s.config.Domain = s.domain
+	return nil
+}
+
+// changeImage sets the manager image in the files it was scaffolded into, skipping the ones that do not exist: the
+// default IMG of the Makefile and the image of the manager manifest, along with its name in the deploy target of
+// the Makefile and in the Tiltfile, which match the image of the manifest by name
+func (s *editScaffolder) changeImage() error {
+	manifestImage := s.config.GetManifestImage()
+	name, newName := regexp.QuoteMeta(config.ImageName(manifestImage)), config.ImageName(s.image)
+	type replacement struct {
+		pattern     *regexp.Regexp
+		replacement string
+	}
+	files := []struct {
+		filename     string
+		replacements []replacement
+	}{
+		{"Makefile", []replacement{
+			{regexp.MustCompile(`(?m)^(IMG \?= ).+$`), "${1}" + s.image},
+			{regexp.MustCompile(`(edit set image )` + name + `=`), "${1}" + newName + "="},
+		}},
+		{filepath.Join("config", "manager", "manager.yaml"), []replacement{
+			{regexp.MustCompile(`(?m)^(\s+image: )` + regexp.QuoteMeta(manifestImage) + `$`), "${1}" + s.image},
+		}},
+		{"Tiltfile", []replacement{
+			{regexp.MustCompile(`(docker_build_with_restart\(\s+')` + name + `'`), "${1}" + newName + "'"},
+		}},
+	}
+	for _, f := range files {
+		filename := f.filename
+		bs, err := ioutil.ReadFile(filename) //nolint:gosec
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
 			return err
 		}
-	} else {
-		str, err = ensureExistAndReplace(
-			str,
-			"COPY apis/ apis/",
-			`COPY api/ api/`)
-		if err != nil {
+		str := string(bs)
+		for _, r := range f.replacements {
+			str = r.pattern.ReplaceAllString(str, r.replacement)
+		}
+		if str == string(bs) {
+			continue
+		}
+		// nolint:gosec
+		if err := ioutil.WriteFile(filename, []byte(str), 0644); err != nil {
 			return err
 		}
+		logger.Default().Info(fmt.Sprintf("Set the manager image to %s in %s", s.image, filename))
 	}
-	// false positive
-	// nolint:gosec
-	return ioutil.WriteFile(filename, []byte(str), 0644)
+	s.config.Image = s.image
+	return nil
 }
 
 func ensureExistAndReplace(input, match, replace string) (string, error) {
@@ -17,22 +17,23 @@ limitations under the License.
 package scaffolds
 
 import (
-	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/machinery"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/license"
+	kustomizev1 "sigs.k8s.io/kubebuilder/pkg/plugin/kustomize/v1"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/certmanager"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/api"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/hack"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/kdefault"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/manager"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/prometheus"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/rbac"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/webhook"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/devcontainer"
+	"sigs.k8s.io/kubebuilder/pkg/tools"
 )
 
 const (
@@ -40,15 +41,39 @@ const (
 	ControllerRuntimeVersion = "v0.6.2"
 	// ControllerToolsVersion is the kubernetes-sigs/controller-tools version to be used in the project
 	ControllerToolsVersion = "v0.3.0"
-	// KustomizeVersion is the kubernetes-sigs/kustomize version to be used in the project
-	KustomizeVersion = "v3.5.4"
-	// todo: update the tag release when the next version of the project be released with this script
-	// ControllerRuntimeEnvTestVersion version to be used to download the envtest setup script
-	ControllerRuntimeEnvTestVersion = "master"
+	// GolangciLintVersion is the golangci/golangci-lint version run by the lint target of the Makefile
+	GolangciLintVersion = "v1.31.0"
+	// KindVersion is the kubernetes-sigs/kind version installed in the dev container
+	KindVersion = "v0.9.0"
+	// OpenTelemetryVersion is the go.opentelemetry.io/otel version the manager traces the reconciliations with
+	OpenTelemetryVersion = "v0.13.0"
+	// DefaultGoVersion is the Go version targeted by the project if none is provided
+	DefaultGoVersion = templates.DefaultGoVersion
+	// DefaultBaseImage is the image the Dockerfile packages the manager binary in if none is provided
+	DefaultBaseImage = templates.DefaultBaseImage
+	// WorkspaceGoVersion is the Go version of the builder image of the projects in a go.work workspace, the first
+	// one supporting workspaces
+	WorkspaceGoVersion = "1.18"
+	// EnvtestK8sVersion is the Kubernetes version of the binaries envtest runs the tests against
+	EnvtestK8sVersion = tools.EnvtestK8sVersion
+	// GinkgoV2Version is the onsi/ginkgo version the tests are written with and run by, if they use Ginkgo v2
+	GinkgoV2Version = "v2.3.0"
+	// CRDRefDocsVersion is the elastic/crd-ref-docs version generating the reference documentation of the APIs
+	CRDRefDocsVersion = "v0.0.8"
+	// CodeGeneratorVersion is the kubernetes/code-generator version generating the typed clients of the APIs, the
+	// one of the Kubernetes libraries required by controller-runtime
+	CodeGeneratorVersion = "v0.18.6"
+	// APIMachineryVersion is the k8s.io/apimachinery version required by the Go module of the APIs if they have
+	// one of their own, the one of the Kubernetes libraries required by controller-runtime
+	APIMachineryVersion = "v0.18.6"
 
-	imageName = "controller:latest"
+	// imageTag is the tag of the manager image built by the Makefile, named after the project by default
+	imageTag = "latest"
 )
 
+// SupportedGoVersions are the Go versions that a project can be scaffolded for
+var SupportedGoVersions = []string{"1.13", "1.14", "1.15"}
+
 var _ scaffold.Scaffolder = &initScaffolder{}
 
 type initScaffolder struct {
@@ -56,15 +81,31 @@ type initScaffolder struct {
 	boilerplatePath string
 	license         string
 	owner           string
+	copyrightYear   string
+	goVersion       string
+	baseImage       string
+	qualityTargets  bool
+	devContainer    bool
+	apiDocs         bool
 }
 
 // NewInitScaffolder returns a new Scaffolder for project initialization operations
-func NewInitScaffolder(config *config.Config, license, owner string) scaffold.Scaffolder {
+func NewInitScaffolder(
+	config *config.Config,
+	license, owner, copyrightYear, goVersion, baseImage string,
+	qualityTargets, devContainer, apiDocs bool,
+) scaffold.Scaffolder {
 	return &initScaffolder{
 		config:          config,
 		boilerplatePath: filepath.Join("hack", "boilerplate.go.txt"),
 		license:         license,
 		owner:           owner,
+		copyrightYear:   copyrightYear,
+		goVersion:       goVersion,
+		baseImage:       baseImage,
+		qualityTargets:  qualityTargets,
+		devContainer:    devContainer,
+		apiDocs:         apiDocs,
 	}
 }
 
@@ -77,7 +118,7 @@ func (s *initScaffolder) newUniverse(boilerplate string) *model.Universe {
 
 // Scaffold implements Scaffolder
 func (s *initScaffolder) Scaffold() error {
-	fmt.Println("Writing scaffold for you to edit...")
+	logger.Default().Info("Writing scaffold for you to edit...")
 	return s.scaffold()
 }
 
@@ -87,6 +128,7 @@ func (s *initScaffolder) scaffold() error {
 	bpFile.Path = s.boilerplatePath
 	bpFile.License = s.license
 	bpFile.Owner = s.owner
+	bpFile.Year = s.copyrightYear
 	if err := machinery.NewScaffold().Execute(
 		s.newUniverse(""),
 		bpFile,
@@ -99,41 +141,180 @@ func (s *initScaffolder) scaffold() error {
 		return err
 	}
 
-	return machinery.NewScaffold().Execute(
-		s.newUniverse(string(boilerplate)),
+	l, err := license.Lookup(s.license)
+	if err != nil {
+		return err
+	}
+
+	// The image is built from the root of the workspace or module the project is in, if it is a subdirectory
+	var buildContext, sourceDir string
+	switch {
+	case s.config.Workspace != "" && s.config.Workspace != ".":
+		buildContext, sourceDir = ModuleRoot(s.config.Workspace), s.config.Workspace+"/"
+	case s.config.Workspace == "" && s.config.BasePath != "":
+		buildContext, sourceDir = ModuleRoot(s.config.BasePath), s.config.BasePath+"/"
+	}
+
+	// The APIs may be in a Go module of their own, required by the one of the project
+	var apisDir string
+	if s.config.APIsModule {
+		apisDir = s.config.GetDirectories().APIs
+	}
+
+	dockerfile := &templates.Dockerfile{
+		GoVersion: s.goVersion,
+		BaseImage: s.baseImage,
+		SourceDir: sourceDir,
+		APIsDir:   apisDir,
+	}
+	if s.config.Workspace != "" {
+		dockerfile.GoVersion, dockerfile.Workspace = WorkspaceGoVersion, true
+	}
+
+	builders := []file.Builder{
 		&templates.GitIgnore{},
-		&rbac.AuthProxyRole{},
-		&rbac.AuthProxyRoleBinding{},
-		&kdefault.AuthProxyPatch{},
-		&rbac.AuthProxyService{},
-		&rbac.ClientClusterRole{},
-		&manager.Config{Image: imageName},
-		&templates.Main{},
-		&templates.GoMod{ControllerRuntimeVersion: ControllerRuntimeVersion},
+		&templates.Main{
+			WebhookPort:       s.config.GetWebhookPort(),
+			WebhookHost:       s.config.WebhookHost,
+			WebhookCertDir:    s.config.WebhookCertDir,
+			ComponentConfig:   s.config.ComponentConfig,
+			MetricsAuthFilter: s.config.MetricsAuthFilter,
+			Pprof:             s.config.Pprof,
+			Tracing:           s.config.Tracing,
+			ZapFlags:          s.config.ZapFlags,
+			WatchNamespace:    s.config.NamespaceScoped || len(s.config.WatchNamespaces) != 0,
+			HighAvailability:  s.config.HighAvailability,
+		},
 		&templates.Makefile{
-			Image:                           imageName,
-			BoilerplatePath:                 s.boilerplatePath,
-			ControllerToolsVersion:          ControllerToolsVersion,
-			KustomizeVersion:                KustomizeVersion,
-			ControllerRuntimeEnvTestVersion: ControllerRuntimeEnvTestVersion,
+			Image:             s.image(),
+			ImageName:         config.ImageName(s.config.GetManifestImage()),
+			GoVersion:         s.goVersion,
+			BoilerplatePath:   s.boilerplatePath,
+			EnvtestK8sVersion: EnvtestK8sVersion,
+			QualityTargets:    s.qualityTargets,
+			GinkgoV2:          s.config.UsesGinkgoV2(),
+			APIDocs:           s.apiDocs,
+			TypedClients:      s.config.TypedClients,
+			BuildContext:      buildContext,
+			Workspace:         s.config.Workspace != "",
+			APIsDir:           apisDir,
 		},
-		&templates.Dockerfile{},
-		&templates.DockerignoreFile{},
-		&kdefault.Kustomize{},
-		&kdefault.ManagerWebhookPatch{},
-		&rbac.ManagerRoleBinding{},
-		&rbac.LeaderElectionRole{},
-		&rbac.LeaderElectionRoleBinding{},
-		&rbac.KustomizeRBAC{},
-		&manager.Kustomization{},
-		&webhook.Kustomization{},
-		&webhook.KustomizeConfigWebhook{},
-		&webhook.Service{},
-		&kdefault.InjectCAPatch{},
-		&prometheus.Kustomization{},
-		&prometheus.ServiceMonitor{},
-		&certmanager.CertManager{},
-		&certmanager.Kustomization{},
-		&certmanager.KustomizeConfig{},
-	)
+		&hack.ToolsGoMod{
+			GoVersion:              s.goVersion,
+			ControllerToolsVersion: ControllerToolsVersion,
+			KustomizeVersion:       kustomizev1.KustomizeVersion,
+			GolangciLintVersion:    s.golangciLintVersion(),
+			GinkgoVersion:          s.ginkgoV2Version(),
+			CRDRefDocsVersion:      s.crdRefDocsVersion(),
+			CodeGeneratorVersion:   s.codeGeneratorVersion(),
+		},
+		&hack.Tools{
+			GolangciLint:  s.qualityTargets,
+			Ginkgo:        s.config.UsesGinkgoV2(),
+			CRDRefDocs:    s.apiDocs,
+			CodeGenerator: s.config.TypedClients,
+		},
+		dockerfile,
+	}
+	// The project shares the go.mod of its module if it is a subdirectory of it
+	if s.config.BasePath == "" {
+		builders = append(builders, &templates.GoMod{
+			GoVersion:                s.goVersion,
+			ControllerRuntimeVersion: ControllerRuntimeVersion,
+			GinkgoVersion:            s.ginkgoV2Version(),
+		})
+	}
+	if s.config.APIsModule {
+		builders = append(builders, &api.GoMod{GoVersion: s.goVersion, APIMachineryVersion: APIMachineryVersion})
+	}
+	// The ignore file of a build context other than the project only applies to the Dockerfile next to it
+	dockerignore := &templates.DockerignoreFile{}
+	if buildContext != "" {
+		dockerignore.Path = "Dockerfile.dockerignore"
+	}
+	builders = append(builders, dockerignore)
+	// The license none has no LICENSE file
+	if l.Text != "" {
+		builders = append(builders, &templates.License{Text: l.Text, Owner: s.owner, Year: s.copyrightYear})
+	}
+	if s.qualityTargets {
+		builders = append(builders, &templates.GolangciLint{})
+	}
+	if s.apiDocs {
+		// The built-in types are linked to the documentation of the Kubernetes version the tests run against
+		k8sVersion := strings.Join(strings.SplitN(EnvtestK8sVersion, ".", 3)[:2], ".")
+		builders = append(builders, &hack.APIDocsConfig{KubernetesVersion: k8sVersion})
+	}
+	if s.config.TypedClients {
+		builders = append(builders, &hack.UpdateCodegen{BoilerplatePath: s.boilerplatePath})
+	}
+	if s.devContainer {
+		builders = append(builders,
+			&devcontainer.DevContainer{},
+			&devcontainer.Dockerfile{
+				GoVersion: s.goVersion,
+				// kubectl matches the version of the kube-apiserver the tests run against
+				KubectlVersion:   "v" + EnvtestK8sVersion,
+				KindVersion:      KindVersion,
+				KustomizeVersion: kustomizev1.KustomizeVersion,
+			},
+		)
+	}
+
+	return machinery.NewScaffold().Execute(s.newUniverse(string(boilerplate)), builders...)
+}
+
+// image returns the reference of the manager image, named after the project by default
+func (s *initScaffolder) image() string {
+	if s.config.Image != "" {
+		return s.config.Image
+	}
+	return s.config.ProjectName + ":" + imageTag
+}
+
+// golangciLintVersion returns the golangci-lint version pinned in hack/tools, if the lint target is scaffolded
+func (s *initScaffolder) golangciLintVersion() string {
+	if !s.qualityTargets {
+		return ""
+	}
+	return GolangciLintVersion
+}
+
+// crdRefDocsVersion returns the crd-ref-docs version pinned in hack/tools, if the api-docs target is scaffolded
+func (s *initScaffolder) crdRefDocsVersion() string {
+	if !s.apiDocs {
+		return ""
+	}
+	return CRDRefDocsVersion
+}
+
+// codeGeneratorVersion returns the code-generator version pinned in hack/tools, if the clients target is
+// scaffolded
+func (s *initScaffolder) codeGeneratorVersion() string {
+	if !s.config.TypedClients {
+		return ""
+	}
+	return CodeGeneratorVersion
+}
+
+// ginkgoV2Version returns the Ginkgo v2 version required by the project and pinned in hack/tools, if the tests
+// are written with Ginkgo v2
+func (s *initScaffolder) ginkgoV2Version() string {
+	if !s.config.UsesGinkgoV2() {
+		return ""
+	}
+	return GinkgoV2Version
+}
+
+// ModuleRoot returns the path of the root of the Go module or workspace of a project in basePath, relative to
+// the project, empty if basePath is.
+func ModuleRoot(basePath string) string {
+	if basePath == "" {
+		return ""
+	}
+	parents := make([]string, len(strings.Split(basePath, "/")))
+	for i := range parents {
+		parents[i] = ".."
+	}
+	return strings.Join(parents, "/")
 }
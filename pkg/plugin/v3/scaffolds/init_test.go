@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+var _ = Describe("initScaffolder", func() {
+	var (
+		cfg    *config.Config
+		tmpDir string
+		oldDir string
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			Version:     config.Version3Alpha,
+			Domain:      "my.domain",
+			Repo:        "example.com/project",
+			ProjectName: "project",
+		}
+
+		var err error
+		oldDir, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		tmpDir, err = ioutil.TempDir("", "v3-scaffolds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldDir)).To(Succeed())
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	read := func(path string) string {
+		content, err := ioutil.ReadFile(path)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	It("should target the Go version set with --go-version", func() {
+		Expect(NewInitScaffolder(cfg, "apache2", "The Authors", "2020", "1.15", "", false, false, false).Scaffold()).
+			To(Succeed())
+
+		Expect(read("go.mod")).To(ContainSubstring("\ngo 1.15\n"))
+		Expect(read("Dockerfile")).To(ContainSubstring("\nFROM golang:1.15 as builder\n"))
+		Expect(read("Makefile")).To(ContainSubstring("\nGO_MIN_VERSION = 1.15\n"))
+	})
+
+	It("should target the default Go version if none is set", func() {
+		Expect(NewInitScaffolder(cfg, "apache2", "The Authors", "2020", "", "", false, false, false).Scaffold()).
+			To(Succeed())
+
+		Expect(read("go.mod")).To(ContainSubstring("\ngo " + DefaultGoVersion + "\n"))
+		Expect(read("Dockerfile")).To(ContainSubstring("\nFROM golang:" + DefaultGoVersion + " as builder\n"))
+		Expect(read("Makefile")).To(ContainSubstring("\nGO_MIN_VERSION = " + DefaultGoVersion + "\n"))
+	})
+})
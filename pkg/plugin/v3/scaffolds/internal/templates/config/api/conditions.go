@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Conditions{}
+
+// Conditions scaffolds the conditions.go file of a group-version, defining the Condition type of the Conditions
+// of the Status of its Kinds and the helpers that set them
+type Conditions struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Conditions) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(f.Directories.Types, "conditions.go")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+	logger.Default().Info(f.Path)
+
+	f.TemplateBody = conditionsTemplate
+
+	// The conditions are shared by the Kinds of the group-version
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+//nolint:lll
+const conditionsTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The standard types of the conditions, following the API conventions, see
+// https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#typical-status-properties
+const (
+	// ConditionReady is True when the object is reconciled, its desired state being reached
+	ConditionReady = "Ready"
+	// ConditionProgressing is True while the object is converging to a new desired state
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded is True when the object failed to reach its desired state
+	ConditionDegraded = "Degraded"
+)
+
+// Condition is an observation of the state of an object. It is the metav1.Condition type of
+// k8s.io/apimachinery v0.19+, which the project does not depend on yet.
+type Condition struct {
+	// Type of the condition, in CamelCase, e.g. Ready
+	// +kubebuilder:validation:MaxLength=316
+	Type string ` + "`" + `json:"type"` + "`" + `
+
+	// Status of the condition, one of True, False or Unknown
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status metav1.ConditionStatus ` + "`" + `json:"status"` + "`" + `
+
+	// ObservedGeneration is the generation of the object the condition was set from
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ObservedGeneration int64 ` + "`" + `json:"observedGeneration,omitempty"` + "`" + `
+
+	// LastTransitionTime is the last time the status of the condition changed
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Format=date-time
+	LastTransitionTime metav1.Time ` + "`" + `json:"lastTransitionTime"` + "`" + `
+
+	// Reason is the identifier, in CamelCase, of the reason of the last transition of the condition
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:Pattern="^[A-Za-z]([A-Za-z0-9_,:]*[A-Za-z0-9_])?$"
+	Reason string ` + "`" + `json:"reason"` + "`" + `
+
+	// Message is a human readable description of the last transition of the condition
+	// +kubebuilder:validation:MaxLength=32768
+	Message string ` + "`" + `json:"message"` + "`" + `
+}
+
+// The helpers below do what the ones of the same names of k8s.io/apimachinery/pkg/api/meta do for
+// metav1.Condition in k8s.io/apimachinery v0.19+.
+
+// SetStatusCondition sets newCondition in conditions, replacing the one of the same type if any. Its
+// LastTransitionTime is set to now if it is zero, unless the status of the condition did not change.
+func SetStatusCondition(conditions *[]Condition, newCondition Condition) {
+	if conditions == nil {
+		return
+	}
+	existing := FindStatusCondition(*conditions, newCondition.Type)
+	if existing == nil {
+		if newCondition.LastTransitionTime.IsZero() {
+			newCondition.LastTransitionTime = metav1.Now()
+		}
+		*conditions = append(*conditions, newCondition)
+		return
+	}
+
+	if existing.Status != newCondition.Status {
+		existing.Status = newCondition.Status
+		if !newCondition.LastTransitionTime.IsZero() {
+			existing.LastTransitionTime = newCondition.LastTransitionTime
+		} else {
+			existing.LastTransitionTime = metav1.Now()
+		}
+	}
+	existing.Reason = newCondition.Reason
+	existing.Message = newCondition.Message
+	existing.ObservedGeneration = newCondition.ObservedGeneration
+}
+
+// RemoveStatusCondition removes the condition of conditionType from conditions
+func RemoveStatusCondition(conditions *[]Condition, conditionType string) {
+	if conditions == nil {
+		return
+	}
+	newConditions := make([]Condition, 0, len(*conditions))
+	for _, condition := range *conditions {
+		if condition.Type != conditionType {
+			newConditions = append(newConditions, condition)
+		}
+	}
+	*conditions = newConditions
+}
+
+// FindStatusCondition returns the condition of conditionType in conditions, nil if there is none
+func FindStatusCondition(conditions []Condition, conditionType string) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsStatusConditionTrue returns true if the condition of conditionType in conditions is True
+func IsStatusConditionTrue(conditions []Condition, conditionType string) bool {
+	condition := FindStatusCondition(conditions, conditionType)
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
+`
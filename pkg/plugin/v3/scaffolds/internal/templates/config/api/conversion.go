@@ -0,0 +1,234 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+)
+
+var (
+	_ file.Template = &ConversionHub{}
+	_ file.Template = &Conversion{}
+	_ file.Template = &ConversionTest{}
+)
+
+// conversionPath returns the path of a file of the conversion of the Kind of a resource
+func conversionPath(dirs config.Directories, res *resource.Resource, name string) string {
+	return res.Replacer().Replace(filepath.Join(dirs.Types, name))
+}
+
+// ConversionHub scaffolds the api/<version>/<kind>_conversion.go file marking a version as the conversion hub
+type ConversionHub struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *ConversionHub) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = conversionPath(f.Directories, f.Resource, "%[kind]_conversion.go")
+	}
+	logger.Default().Info(f.Path)
+
+	f.TemplateBody = conversionHubTemplate
+
+	// The hub may already be implemented, e.g. if another version was added before
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+// Conversion scaffolds the api/<version>/<kind>_conversion.go file converting a spoke version to and from the hub
+type Conversion struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+
+	// Hub is the resource of the version the spoke converts to and from
+	Hub *resource.Resource
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Conversion) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = conversionPath(f.Directories, f.Resource, "%[kind]_conversion.go")
+	}
+	logger.Default().Info(f.Path)
+
+	f.TemplateBody = conversionTemplate
+
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+// ConversionTest scaffolds the api/<version>/<kind>_conversion_test.go file checking that random objects of a
+// spoke version survive a round trip through the hub, and the ones of the hub a round trip through the spoke
+type ConversionTest struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+
+	// Hub is the resource of the version the spoke converts to and from
+	Hub *resource.Resource
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *ConversionTest) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = conversionPath(f.Directories, f.Resource, "%[kind]_conversion_test.go")
+	}
+	logger.Default().Info(f.Path)
+
+	f.TemplateBody = conversionTestTemplate
+
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+const conversionHubTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+// Hub marks this type as the conversion hub, the other versions of {{ .Resource.Kind }} convert to and from it.
+func (*{{ .Resource.Kind }}) Hub() {}
+`
+
+const conversionTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	{{ .Hub.ImportAlias }} "{{ .Hub.Package }}"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: every field must be converted, {{ lower .Resource.Kind }}_conversion_test.go fails if any is lost.
+
+// ConvertTo converts this {{ .Resource.Kind }} to the Hub version ({{ .Hub.Version }}).
+func (src *{{ .Resource.Kind }}) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*{{ .Hub.ImportAlias }}.{{ .Resource.Kind }})
+
+	// TODO(user): convert the fields of the spec and the status.
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec.Foo = src.Spec.Foo
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version ({{ .Hub.Version }}) to this version.
+func (dst *{{ .Resource.Kind }}) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*{{ .Hub.ImportAlias }}.{{ .Resource.Kind }})
+
+	// TODO(user): convert the fields of the spec and the status.
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec.Foo = src.Spec.Foo
+
+	return nil
+}
+`
+
+const conversionTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	fuzz "github.com/google/gofuzz"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/diff"
+
+	{{ .Hub.ImportAlias }} "{{ .Hub.Package }}"
+)
+
+// new{{ .Resource.Kind }}Fuzzer returns the fuzzer of the conversion tests, filling the metadata of the objects
+// as the API server does and leaving their kind and API version empty, which the conversion does not set. Add
+// fuzzer functions to metafuzzer.Funcs for the fields that only accept some values.
+func new{{ .Resource.Kind }}Fuzzer(t *testing.T) *fuzz.Fuzzer {
+	seed := time.Now().UnixNano()
+	t.Logf("fuzzing with the seed %d", seed)
+	return fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(seed), serializer.NewCodecFactory(runtime.NewScheme()))
+}
+
+// Test{{ .Resource.Kind }}ConversionRoundTrip converts random {{ .Resource.Kind }} objects to the Hub version
+// ({{ .Hub.Version }}) and back, failing if they are not the same afterwards.
+func Test{{ .Resource.Kind }}ConversionRoundTrip(t *testing.T) {
+	f := new{{ .Resource.Kind }}Fuzzer(t)
+	for i := 0; i < 100; i++ {
+		original := &{{ .Resource.Kind }}{}
+		f.Fuzz(original)
+
+		hub := &{{ .Hub.ImportAlias }}.{{ .Resource.Kind }}{}
+		if err := original.ConvertTo(hub); err != nil {
+			t.Fatalf("failed to convert to the Hub version: %v", err)
+		}
+		restored := &{{ .Resource.Kind }}{}
+		if err := restored.ConvertFrom(hub); err != nil {
+			t.Fatalf("failed to convert from the Hub version: %v", err)
+		}
+
+		if !equality.Semantic.DeepEqual(original, restored) {
+			t.Fatalf("the {{ .Resource.Kind }} changed after a round trip through the Hub version:\n%s",
+				diff.ObjectReflectDiff(original, restored))
+		}
+	}
+}
+
+// Test{{ .Resource.Kind }}HubConversionRoundTrip converts random {{ .Resource.Kind }} objects of the Hub version
+// ({{ .Hub.Version }}) to this version and back, failing if they are not the same afterwards. The fields that
+// this version lacks must then be kept by the conversion, e.g. in an annotation of the object.
+func Test{{ .Resource.Kind }}HubConversionRoundTrip(t *testing.T) {
+	f := new{{ .Resource.Kind }}Fuzzer(t)
+	for i := 0; i < 100; i++ {
+		original := &{{ .Hub.ImportAlias }}.{{ .Resource.Kind }}{}
+		f.Fuzz(original)
+
+		spoke := &{{ .Resource.Kind }}{}
+		if err := spoke.ConvertFrom(original); err != nil {
+			t.Fatalf("failed to convert from the Hub version: %v", err)
+		}
+		restored := &{{ .Hub.ImportAlias }}.{{ .Resource.Kind }}{}
+		if err := spoke.ConvertTo(restored); err != nil {
+			t.Fatalf("failed to convert to the Hub version: %v", err)
+		}
+
+		if !equality.Semantic.DeepEqual(original, restored) {
+			t.Fatalf("the {{ .Resource.Kind }} of the Hub version changed after a round trip through "+
+				"{{ .Resource.Version }}:\n%s", diff.ObjectReflectDiff(original, restored))
+		}
+	}
+}
+`
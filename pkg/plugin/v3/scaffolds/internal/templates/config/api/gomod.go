@@ -0,0 +1,61 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates"
+)
+
+var _ file.Template = &GoMod{}
+
+// GoMod scaffolds the go.mod of the Go module of the APIs, which only depends on the Kubernetes API machinery
+type GoMod struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.RepositoryMixin
+
+	// GoVersion is the Go version targeted by the project
+	GoVersion string
+	// APIMachineryVersion is the k8s.io/apimachinery version the types are built with
+	APIMachineryVersion string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *GoMod) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(f.Directories.APIs, "go.mod")
+	}
+
+	f.TemplateBody = goModTemplate
+
+	if f.GoVersion == "" {
+		f.GoVersion = templates.DefaultGoVersion
+	}
+
+	return nil
+}
+
+const goModTemplate = `
+module {{ .Repo }}/{{ .Directories.APIs }}
+
+go {{ .GoVersion }}
+
+require k8s.io/apimachinery {{ .APIMachineryVersion }}
+`
@@ -27,19 +27,20 @@ var _ file.Template = &Group{}
 // Group scaffolds the api/<version>/groupversion_info.go
 type Group struct {
 	file.TemplateMixin
-	file.MultiGroupMixin
+	file.DirectoriesMixin
 	file.BoilerplateMixin
 	file.ResourceMixin
+
+	// TypedClients adds the variable and function of the group-version used by the generated clients and listers
+	TypedClients bool
+	// APIsModule is true if the types are in a Go module of their own, which does not depend on controller-runtime
+	APIsModule bool
 }
 
 // SetTemplateDefaults implements input.Template
 func (f *Group) SetTemplateDefaults() error {
 	if f.Path == "" {
-		if f.MultiGroup {
-			f.Path = filepath.Join("apis", "%[group]", "%[version]", "groupversion_info.go")
-		} else {
-			f.Path = filepath.Join("api", "%[version]", "groupversion_info.go")
-		}
+		f.Path = filepath.Join(f.Directories.Types, "groupversion_info.go")
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
 
@@ -57,8 +58,14 @@ const groupTemplate = `{{ .Boilerplate }}
 package {{ .Resource.Version }}
 
 import (
+{{- if .APIsModule }}
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+{{- else }}
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
+{{- end }}
 )
 
 var (
@@ -66,9 +73,42 @@ var (
 	GroupVersion = schema.GroupVersion{Group: "{{ .Resource.Domain }}", Version: "{{ .Resource.Version }}"}
 
 	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
+{{- if .APIsModule }}
+	SchemeBuilder = &schemeBuilder{}
+{{- else }}
 	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+{{- end }}
 
 	// AddToScheme adds the types in this group-version to the given scheme.
 	AddToScheme = SchemeBuilder.AddToScheme
 )
+{{- if .APIsModule }}
+
+// schemeBuilder registers the types of the group-version like the scheme.Builder of controller-runtime, so that
+// the module of the APIs does not depend on it
+type schemeBuilder struct {
+	runtime.SchemeBuilder
+}
+
+// Register adds the objects to the types of the group-version added to a scheme.
+func (b *schemeBuilder) Register(objects ...runtime.Object) *schemeBuilder {
+	b.SchemeBuilder.Register(func(scheme *runtime.Scheme) error {
+		scheme.AddKnownTypes(GroupVersion, objects...)
+		metav1.AddToGroupVersion(scheme, GroupVersion)
+		return nil
+	})
+	return b
+}
+{{- end }}
+{{- if .TypedClients }}
+
+// SchemeGroupVersion is the group version the typed clients generated by client-gen are configured with
+var SchemeGroupVersion = GroupVersion
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource, as the listers generated by
+// lister-gen expect
+func Resource(resource string) schema.GroupResource {
+	return GroupVersion.WithResource(resource).GroupResource()
+}
+{{- end }}
 `
@@ -17,9 +17,10 @@ limitations under the License.
 package api
 
 import (
-	"fmt"
 	"path/filepath"
+	"strings"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
 )
 
@@ -28,30 +29,65 @@ var _ file.Template = &Types{}
 // Types scaffolds the api/<version>/<kind>_types.go file to define the schema for an API
 type Types struct {
 	file.TemplateMixin
-	file.MultiGroupMixin
+	file.DirectoriesMixin
 	file.BoilerplateMixin
 	file.ResourceMixin
+
+	// ResourceMarker are the parameters of the resource marker of the Kind, if any
+	ResourceMarker string
+
+	// Force keeps the file if it already exists instead of failing, so that the missing files of a tracked
+	// resource can be scaffolded again
+	Force bool
+
+	// ValidationMarkers adds example field validation markers and CEL validation rules to the Spec, which are
+	// enforced by the API server without a webhook
+	ValidationMarkers bool
+
+	// DefaultingMarkers adds example default markers to the Spec, which are applied by the API server instead of
+	// a defaulting webhook
+	DefaultingMarkers bool
+
+	// TypedClients adds the markers generating the typed client, lister and informer of the Kind
+	TypedClients bool
+
+	// ApplyConfigurations adds the markers generating the apply configuration of the Kind, used to server-side
+	// apply its objects
+	ApplyConfigurations bool
+
+	// WithConditions adds the Conditions field to the Status, of the Condition type of conditions.go
+	WithConditions bool
 }
 
 // SetTemplateDefaults implements input.Template
 func (f *Types) SetTemplateDefaults() error {
 	if f.Path == "" {
-		if f.MultiGroup {
-			f.Path = filepath.Join("apis", "%[group]", "%[version]", "%[kind]_types.go")
-		} else {
-			f.Path = filepath.Join("api", "%[version]", "%[kind]_types.go")
-		}
+		f.Path = filepath.Join(f.Directories.Types, "%[kind]_types.go")
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-	fmt.Println(f.Path)
+	logger.Default().Info(f.Path)
+
+	var markerParams []string
+	if f.Resource.HasCustomPlural() {
+		markerParams = append(markerParams, "path="+f.Resource.Plural)
+	}
+	if !f.Resource.Namespaced {
+		markerParams = append(markerParams, "scope=Cluster")
+	}
+	f.ResourceMarker = strings.Join(markerParams, ",")
 
 	f.TemplateBody = typesTemplate
 
-	f.IfExistsAction = file.Error
+	if f.Force {
+		f.IfExistsAction = file.Skip
+	} else {
+		f.IfExistsAction = file.Error
+	}
 
 	return nil
 }
 
+//nolint:lll
 const typesTemplate = `{{ .Boilerplate }}
 
 package {{ .Resource.Version }}
@@ -64,23 +100,86 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // {{ .Resource.Kind }}Spec defines the desired state of {{ .Resource.Kind }}
+{{- if .ValidationMarkers }}
+// +kubebuilder:validation:XValidation:rule="!has(self.minReplicas) || !has(self.maxReplicas) || self.minReplicas <= self.maxReplicas",message="minReplicas must not be greater than maxReplicas"
+{{- end }}
 type {{ .Resource.Kind }}Spec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+{{- if .ValidationMarkers }}
+
+	// The markers of the fields below are validated by the API server, see
+	// https://book.kubebuilder.io/reference/markers/crd-validation.html
+{{- end }}
+{{- if .DefaultingMarkers }}
+
+	// The fields below with a default marker are set by the API server when they are omitted, even by clients
+	// that do not know them, without running a webhook. Defaults are only constant values and are not applied
+	// to the objects stored before they were added: scaffold a defaulting webhook instead with
+	// "create webhook --defaulting" if they depend on other fields or on the state of the cluster.
+{{- end }}
 
 	// Foo is an example field of {{ .Resource.Kind }}. Edit {{ .Resource.Kind }}_types.go to remove/update
+{{- if .ValidationMarkers }}
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="foo is immutable"
+{{- end }}
+{{- if .DefaultingMarkers }}
+	// +kubebuilder:default="foo"
+{{- end }}
+{{- if or .ValidationMarkers .DefaultingMarkers }}
+	// +optional
+{{- end }}
 	Foo string ` + "`" + `json:"foo,omitempty"` + "`" + `
+{{- if .DefaultingMarkers }}
+
+	// Replicas is an example field of {{ .Resource.Kind }} set to 1 by the API server when omitted
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 ` + "`" + `json:"replicas,omitempty"` + "`" + `
+{{- end }}
+{{- if .ValidationMarkers }}
+
+	// MinReplicas is an example field of {{ .Resource.Kind }} that must not be greater than MaxReplicas
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReplicas *int32 ` + "`" + `json:"minReplicas,omitempty"` + "`" + `
+
+	// MaxReplicas is an example field of {{ .Resource.Kind }} that must not be lower than MinReplicas
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxReplicas *int32 ` + "`" + `json:"maxReplicas,omitempty"` + "`" + `
+{{- end }}
 }
 
 // {{ .Resource.Kind }}Status defines the observed state of {{ .Resource.Kind }}
 type {{ .Resource.Kind }}Status struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+{{- if .WithConditions }}
+
+	// Conditions are the latest observations of the state of the {{ .Resource.Kind }}, of the Ready, Progressing and
+	// Degraded types
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []Condition ` + "`" + `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"` + "`" + `
+{{- end }}
 }
 
+{{ if or .TypedClients .ApplyConfigurations -}}
+// +genclient
+{{ if not .Resource.Namespaced -}}
+// +genclient:nonNamespaced
+{{ end -}}
+{{ end -}}
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-{{ if not .Resource.Namespaced }} // +kubebuilder:resource:scope=Cluster {{ end }}
+{{ if .ResourceMarker }} // +kubebuilder:resource:{{ .ResourceMarker }} {{ end }}
 
 // {{ .Resource.Kind }} is the Schema for the {{ .Resource.Plural }} API
 type {{ .Resource.Kind }} struct {
@@ -17,10 +17,11 @@ limitations under the License.
 package api
 
 import (
-	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
 )
 
@@ -29,7 +30,7 @@ var _ file.Template = &Webhook{}
 // Webhook scaffolds a Webhook for a Resource
 type Webhook struct { // nolint:maligned
 	file.TemplateMixin
-	file.MultiGroupMixin
+	file.DirectoriesMixin
 	file.BoilerplateMixin
 	file.ResourceMixin
 
@@ -40,21 +41,30 @@ type Webhook struct { // nolint:maligned
 	Defaulting bool
 	// If scaffold the validating webhook
 	Validating bool
+
+	// Existing is the content of the webhook file of the resource, if it already exists, to which the defaulting
+	// and validating webhooks are appended
+	Existing string
 }
 
 // SetTemplateDefaults implements input.Template
 func (f *Webhook) SetTemplateDefaults() error {
 	if f.Path == "" {
-		if f.MultiGroup {
-			f.Path = filepath.Join("apis", "%[group]", "%[version]", "%[kind]_webhook.go")
-		} else {
-			f.Path = filepath.Join("api", "%[version]", "%[kind]_webhook.go")
-		}
+		f.Path = filepath.Join(f.Directories.Types, "%[kind]_webhook.go")
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-	fmt.Println(f.Path)
+	logger.Default().Info(f.Path)
 
 	webhookTemplate := webhookTemplate
+	if f.Existing != "" {
+		webhookTemplate = "{{ .Existing }}"
+		if f.Defaulting || f.Validating {
+			f.Existing = withImport(f.Existing, "sigs.k8s.io/controller-runtime/pkg/webhook")
+		}
+		if f.Validating {
+			f.Existing = withImport(f.Existing, "k8s.io/apimachinery/pkg/runtime")
+		}
+	}
 	if f.Defaulting {
 		webhookTemplate = webhookTemplate + defaultingWebhookTemplate
 	}
@@ -63,13 +73,27 @@ func (f *Webhook) SetTemplateDefaults() error {
 	}
 	f.TemplateBody = webhookTemplate
 
-	f.IfExistsAction = file.Error
+	if f.Existing != "" {
+		f.IfExistsAction = file.Overwrite
+	} else {
+		f.IfExistsAction = file.Error
+	}
 
 	f.GroupDomainWithDash = strings.Replace(f.Resource.Domain, ".", "-", -1)
 
 	return nil
 }
 
+// withImport adds the import of the package at importPath to the import declaration of the Go code of content, if
+// it is not imported yet
+func withImport(content, importPath string) string {
+	quoted := strconv.Quote(importPath)
+	if strings.Contains(content, quoted+"\n") {
+		return content
+	}
+	return strings.Replace(content, "import (\n", "import (\n\t"+quoted+"\n", 1)
+}
+
 const (
 	webhookTemplate = `{{ .Boilerplate }}
 
@@ -78,8 +102,10 @@ package {{ .Resource.Version }}
 import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
-	{{- if or .Validating .Defaulting }}
+	{{- if .Validating }}
 	"k8s.io/apimachinery/pkg/runtime"
+	{{- end }}
+	{{- if or .Validating .Defaulting }}
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	{{- end }}
 )
@@ -98,7 +124,7 @@ func (r *{{ .Resource.Kind }}) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 	//nolint:lll
 	defaultingWebhookTemplate = `
-// +kubebuilder:webhook:path=/mutate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=true,failurePolicy=fail,groups={{ .Resource.Domain }},resources={{ .Resource.Plural }},verbs=create;update,versions={{ .Resource.Version }},name=m{{ lower .Resource.Kind }}.kb.io
+// +kubebuilder:webhook:path=/mutate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=true,failurePolicy=fail,groups={{ .Resource.Domain }},resources={{ .Resource.Plural }},verbs=create;update,versions={{ .Resource.Version }},name=m{{ lower .Resource.Kind }}.kb.io{{ if eq .Resource.WebhookVersion "v1" }},sideEffects=None,webhookVersions=v1,admissionReviewVersions=v1beta1{{ end }}
 
 var _ webhook.Defaulter = &{{ .Resource.Kind }}{}
 
@@ -112,7 +138,7 @@ func (r *{{ .Resource.Kind }}) Default() {
 	//nolint:lll
 	validatingWebhookTemplate = `
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
-// +kubebuilder:webhook:verbs=create;update,path=/validate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=false,failurePolicy=fail,groups={{ .Resource.Domain }},resources={{ .Resource.Plural }},versions={{ .Resource.Version }},name=v{{ lower .Resource.Kind }}.kb.io
+// +kubebuilder:webhook:verbs=create;update,path=/validate-{{ .GroupDomainWithDash }}-{{ .Resource.Version }}-{{ lower .Resource.Kind }},mutating=false,failurePolicy=fail,groups={{ .Resource.Domain }},resources={{ .Resource.Plural }},versions={{ .Resource.Version }},name=v{{ lower .Resource.Kind }}.kb.io{{ if eq .Resource.WebhookVersion "v1" }},sideEffects=None,webhookVersions=v1,admissionReviewVersions=v1beta1{{ end }}
 
 var _ webhook.Validator = &{{ .Resource.Kind }}{}
 
@@ -0,0 +1,336 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+)
+
+var _ file.Template = &WebhookSuiteTest{}
+var _ file.Inserter = &WebhookSuiteTest{}
+
+// WebhookSuiteTest scaffolds the webhook_suite_test.go file of a group-version, which runs its webhooks in the
+// webhook server of a manager, their configurations being installed in the API server of an envtest environment
+type WebhookSuiteTest struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+
+	// TestFramework is the framework the suite is written with, Ginkgo v1 is used if empty
+	TestFramework string
+
+	// RootPath are the quoted ".." path elements leading from the directory of the suite to the root of the
+	// project, where the CRDs and the webhook configurations are generated in config/
+	RootPath string
+}
+
+// SetTemplateDefaults implements file.Template
+func (f *WebhookSuiteTest) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(f.Directories.Types, "webhook_suite_test.go")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+	f.RootPath = strings.Repeat(`"..", `, len(strings.Split(filepath.ToSlash(filepath.Dir(f.Path)), "/")))
+
+	template := webhookSuiteTestTemplate
+	if f.TestFramework == config.TestFrameworkGoTest {
+		template = webhookSuiteGoTestTemplate
+	}
+	f.TemplateBody = fmt.Sprintf(template, file.NewMarkerFor(f.Path, webhookSetupMarker))
+
+	return nil
+}
+
+// GinkgoV2 returns whether the suite is written with Ginkgo v2
+func (f *WebhookSuiteTest) GinkgoV2() bool {
+	return f.TestFramework == config.TestFrameworkGinkgoV2
+}
+
+const webhookSetupMarker = "webhook"
+
+// GetMarkers implements file.Inserter
+func (f *WebhookSuiteTest) GetMarkers() []file.Marker {
+	return []file.Marker{file.NewMarkerFor(f.Path, webhookSetupMarker)}
+}
+
+const (
+	webhookSetupCodeFragment = `err = (&%s{}).SetupWebhookWithManager(mgr)
+Expect(err).NotTo(HaveOccurred())
+
+`
+	goTestWebhookSetupCodeFragment = `if err = (&%s{}).SetupWebhookWithManager(mgr); err != nil {
+	return err
+}
+
+`
+)
+
+// WebhookSuiteTestFragment returns the code fragment of webhook_suite_test.go setting up the webhooks of res,
+// written with testFramework, so that it can also be removed when the webhooks are deleted
+func WebhookSuiteTestFragment(res *resource.Resource, testFramework string) string {
+	if testFramework == config.TestFrameworkGoTest {
+		return fmt.Sprintf(goTestWebhookSetupCodeFragment, res.Kind)
+	}
+	return fmt.Sprintf(webhookSetupCodeFragment, res.Kind)
+}
+
+// GetCodeFragments implements file.Inserter
+func (f *WebhookSuiteTest) GetCodeFragments() file.CodeFragmentsMap {
+	return file.CodeFragmentsMap{
+		file.NewMarkerFor(f.Path, webhookSetupMarker): {WebhookSuiteTestFragment(f.Resource, f.TestFramework)},
+	}
+}
+
+const webhookSuiteTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+{{ if .GinkgoV2 }}	. "github.com/onsi/ginkgo/v2"{{ else }}	. "github.com/onsi/ginkgo"{{ end }}
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	{{- if not .GinkgoV2 }}
+	"sigs.k8s.io/controller-runtime/pkg/envtest/printer"
+	{{- end }}
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// These tests use Ginkgo{{ if .GinkgoV2 }} v2{{ end }} (BDD-style Go testing framework). Refer to
+// {{ if .GinkgoV2 }}https{{ else }}http{{ end }}://onsi.github.io/ginkgo/ to learn more about Ginkgo.
+
+var k8sClient client.Client
+var testEnv *envtest.Environment
+var stop chan struct{}
+
+func TestWebhooks(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+{{ if .GinkgoV2 }}	RunSpecs(t, "Webhook Suite")
+{{- else }}	RunSpecsWithDefaultAndCustomReporters(t,
+		"Webhook Suite",
+		[]Reporter{printer.NewlineReporter{}})
+{{- end }}
+}
+
+var _ = BeforeSuite(func({{ if not .GinkgoV2 }}done Done{{ end }}) {
+	logf.SetLogger(zap.LoggerTo(GinkgoWriter, true))
+
+	By("bootstrapping test environment")
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths: []string{filepath.Join({{ .RootPath }}"config", "crd", "bases")},
+		// The webhook configurations are changed to call the webhook server started below
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			DirectoryPaths: []string{filepath.Join({{ .RootPath }}"config", "webhook")},
+		},
+	}
+
+	cfg, err := testEnv.Start()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(cfg).ToNot(BeNil())
+
+	scheme := runtime.NewScheme()
+	err = AddToScheme(scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(k8sClient).ToNot(BeNil())
+
+	By("starting the webhook server")
+	webhookInstallOptions := &testEnv.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             scheme,
+		Host:               webhookInstallOptions.LocalServingHost,
+		Port:               webhookInstallOptions.LocalServingPort,
+		CertDir:            webhookInstallOptions.LocalServingCertDir,
+		MetricsBindAddress: "0",
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	%s
+
+	stop = make(chan struct{})
+	go func() {
+		defer GinkgoRecover()
+		err := mgr.Start(stop)
+		Expect(err).ToNot(HaveOccurred())
+	}()
+
+	// The API server fails the requests the webhooks are called for until the server serves them
+	addr := net.JoinHostPort(webhookInstallOptions.LocalServingHost, strconv.Itoa(webhookInstallOptions.LocalServingPort))
+	dialer := &net.Dialer{Timeout: time.Second}
+	Eventually(func() error {
+		conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{InsecureSkipVerify: true}) // nolint:gosec
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}, 10*time.Second).Should(Succeed())
+{{- if not .GinkgoV2 }}
+
+	close(done)
+}, 60)
+{{- else }}
+})
+{{- end }}
+
+var _ = AfterSuite(func() {
+	By("tearing down the test environment")
+	close(stop)
+	err := testEnv.Stop()
+	Expect(err).ToNot(HaveOccurred())
+})
+
+// isDenied returns whether err is the denial of a request by a webhook, which is reported with the 403 code,
+// the reason of the status being the message of the webhook
+func isDenied(err error) bool {
+	status, ok := err.(apierrors.APIStatus)
+	return ok && status.Status().Code == http.StatusForbidden
+}
+`
+
+const webhookSuiteGoTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// These tests use the standard testing package, TestMain runs them against the API server of the envtest
+// environment, which calls the webhooks of the package served by the webhook server of a manager.
+
+var k8sClient client.Client
+
+func TestMain(m *testing.M) {
+	logf.SetLogger(zap.LoggerTo(os.Stderr, true))
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths: []string{filepath.Join({{ .RootPath }}"config", "crd", "bases")},
+		// The webhook configurations are changed to call the webhook server started by startTestEnv
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			DirectoryPaths: []string{filepath.Join({{ .RootPath }}"config", "webhook")},
+		},
+	}
+	stop := make(chan struct{})
+	code := 1
+	if err := startTestEnv(testEnv, stop); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to start the test environment: %%v\n", err)
+	} else {
+		code = m.Run()
+	}
+
+	close(stop)
+	if err := testEnv.Stop(); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to stop the test environment: %%v\n", err)
+		code = 1
+	}
+	os.Exit(code)
+}
+
+// startTestEnv starts testEnv, creates the client the tests use to reach its API server and runs the webhook
+// server until stop is closed
+func startTestEnv(testEnv *envtest.Environment, stop <-chan struct{}) error {
+	cfg, err := testEnv.Start()
+	if err != nil {
+		return err
+	}
+
+	scheme := runtime.NewScheme()
+	if err = AddToScheme(scheme); err != nil {
+		return err
+	}
+	if k8sClient, err = client.New(cfg, client.Options{Scheme: scheme}); err != nil {
+		return err
+	}
+
+	webhookInstallOptions := &testEnv.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             scheme,
+		Host:               webhookInstallOptions.LocalServingHost,
+		Port:               webhookInstallOptions.LocalServingPort,
+		CertDir:            webhookInstallOptions.LocalServingCertDir,
+		MetricsBindAddress: "0",
+	})
+	if err != nil {
+		return err
+	}
+
+	%s
+
+	go func() {
+		if err := mgr.Start(stop); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to run the manager: %%v\n", err)
+		}
+	}()
+
+	// The API server fails the requests the webhooks are called for until the server serves them
+	addr := net.JoinHostPort(webhookInstallOptions.LocalServingHost, strconv.Itoa(webhookInstallOptions.LocalServingPort))
+	dialer := &net.Dialer{Timeout: time.Second}
+	return wait.PollImmediate(100*time.Millisecond, 10*time.Second, func() (bool, error) {
+		conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{InsecureSkipVerify: true}) // nolint:gosec
+		if err != nil {
+			return false, nil
+		}
+		return true, conn.Close()
+	})
+}
+
+// isDenied returns whether err is the denial of a request by a webhook, which is reported with the 403 code,
+// the reason of the status being the message of the webhook
+func isDenied(err error) bool {
+	status, ok := err.(apierrors.APIStatus)
+	return ok && status.Status().Code == http.StatusForbidden
+}
+`
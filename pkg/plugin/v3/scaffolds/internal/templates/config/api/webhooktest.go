@@ -0,0 +1,276 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &WebhookTest{}
+
+// WebhookTest scaffolds the tests of the defaulting and validation of a Webhook, creating objects through the API
+// server of the envtest environment of webhook_suite_test.go, and with the standard testing package, the
+// table-driven tests of its methods. The tests of each webhook are independent, so that the ones of the webhooks
+// added to a resource are appended to its existing tests.
+type WebhookTest struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+
+	// TestFramework is the framework the tests are written with, Ginkgo v1 is used if empty
+	TestFramework string
+
+	// If the defaulting webhook is tested
+	Defaulting bool
+	// If the validating webhook is tested
+	Validating bool
+
+	// Existing is the content of the webhook test file of the resource, if it already exists, to which the tests of
+	// the defaulting and validating webhooks are appended
+	Existing string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *WebhookTest) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(f.Directories.Types, "%[kind]_webhook_test.go")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+	logger.Default().Info(f.Path)
+
+	header, helper := webhookTestHeaderTemplate, webhookTestHelperTemplate
+	defaulting, validating := webhookTestDefaultingTemplate, webhookTestValidatingTemplate
+	if f.TestFramework == config.TestFrameworkGoTest {
+		header = webhookGoTestHeaderTemplate
+		defaulting, validating = webhookGoTestDefaultingTemplate, webhookGoTestValidatingTemplate
+	}
+
+	f.TemplateBody = header + helper
+	f.IfExistsAction = file.Error
+	if f.Existing != "" {
+		f.TemplateBody = "{{ .Existing }}"
+		// The test files scaffolded before the tests were appendable declare no helper to create the objects
+		if !strings.Contains(f.Existing, "func new"+f.Resource.Kind+"(") {
+			f.TemplateBody += helper
+		}
+		if f.Defaulting && f.TestFramework == config.TestFrameworkGoTest {
+			f.Existing = withImport(f.Existing, "reflect")
+		}
+		f.IfExistsAction = file.Overwrite
+	}
+	if f.Defaulting {
+		f.TemplateBody += defaulting
+	}
+	if f.Validating {
+		f.TemplateBody += validating
+	}
+
+	return nil
+}
+
+// GinkgoV2 returns whether the tests are written with Ginkgo v2
+func (f *WebhookTest) GinkgoV2() bool {
+	return f.TestFramework == config.TestFrameworkGinkgoV2
+}
+
+const (
+	webhookTestHeaderTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"context"
+
+{{ if .GinkgoV2 }}	. "github.com/onsi/ginkgo/v2"{{ else }}	. "github.com/onsi/ginkgo"{{ end }}
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+`
+
+	webhookGoTestHeaderTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	"context"
+	{{- if .Defaulting }}
+	"reflect"
+	{{- end }}
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+`
+
+	//nolint:lll
+	webhookTestHelperTemplate = `
+// new{{ .Resource.Kind }} returns a {{ .Resource.Kind }} of spec to create, its namespace is ignored if it is cluster-scoped
+func new{{ .Resource.Kind }}(spec {{ .Resource.Kind }}Spec) *{{ .Resource.Kind }} {
+	return &{{ .Resource.Kind }}{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "test-webhook-{{ lower .Resource.Kind }}-", Namespace: "default"},
+		Spec:       spec,
+	}
+}
+`
+
+	webhookTestDefaultingTemplate = `
+var _ = Describe("{{ .Resource.Kind }} defaulting webhook", func() {
+	It("should default the created {{ .Resource.Kind }} objects", func() {
+		ctx := context.Background()
+		obj := new{{ .Resource.Kind }}({{ .Resource.Kind }}Spec{})
+		Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+		}()
+
+		// Defaulting the object again changes nothing, as the mutating webhook already defaulted it
+		defaulted := obj.DeepCopy()
+		defaulted.Default()
+		Expect(defaulted).To(Equal(obj))
+	})
+})
+`
+
+	webhookTestValidatingTemplate = `
+var _ = Describe("{{ .Resource.Kind }} validating webhook", func() {
+	It("should reject the invalid {{ .Resource.Kind }} objects", func() {
+		ctx := context.Background()
+		for _, tt := range []struct {
+			spec       {{ .Resource.Kind }}Spec
+			wantDenied bool
+		}{
+			{spec: {{ .Resource.Kind }}Spec{}},
+			// TODO(user): add the specs your validation logic accepts and rejects
+		} {
+			obj := new{{ .Resource.Kind }}(tt.spec)
+			err := k8sClient.Create(ctx, obj)
+			if tt.wantDenied {
+				Expect(isDenied(err)).To(BeTrue(), "expected %+v to be denied, got: %v", tt.spec, err)
+				continue
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+		}
+	})
+})
+`
+
+	webhookGoTestDefaultingTemplate = `
+func Test{{ .Resource.Kind }}Default(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  *{{ .Resource.Kind }}
+		want *{{ .Resource.Kind }}
+	}{
+		{
+			name: "empty {{ .Resource.Kind }}",
+			obj:  &{{ .Resource.Kind }}{},
+			want: &{{ .Resource.Kind }}{},
+		},
+		// TODO(user): add the cases of your defaulting logic
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.obj.Default()
+			if !reflect.DeepEqual(tt.obj, tt.want) {
+				t.Errorf("Default() = %+v, want %+v", tt.obj, tt.want)
+			}
+		})
+	}
+}
+
+func Test{{ .Resource.Kind }}DefaultingWebhook(t *testing.T) {
+	ctx := context.Background()
+	obj := new{{ .Resource.Kind }}({{ .Resource.Kind }}Spec{})
+	if err := k8sClient.Create(ctx, obj); err != nil {
+		t.Fatalf("unable to create the {{ .Resource.Kind }}: %v", err)
+	}
+	defer func() {
+		if err := k8sClient.Delete(ctx, obj); err != nil {
+			t.Errorf("unable to delete the {{ .Resource.Kind }}: %v", err)
+		}
+	}()
+
+	// Defaulting the object again changes nothing, as the mutating webhook already defaulted it
+	defaulted := obj.DeepCopy()
+	defaulted.Default()
+	if !reflect.DeepEqual(defaulted, obj) {
+		t.Errorf("the created {{ .Resource.Kind }} %+v is not defaulted, want %+v", obj, defaulted)
+	}
+}
+`
+
+	webhookGoTestValidatingTemplate = `
+func Test{{ .Resource.Kind }}Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		obj     *{{ .Resource.Kind }}
+		wantErr bool
+	}{
+		{
+			name: "empty {{ .Resource.Kind }}",
+			obj:  &{{ .Resource.Kind }}{},
+		},
+		// TODO(user): add the cases of your validation logic
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.obj.ValidateCreate(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := tt.obj.ValidateUpdate(tt.obj.DeepCopy()); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test{{ .Resource.Kind }}ValidatingWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       {{ .Resource.Kind }}Spec
+		wantDenied bool
+	}{
+		{
+			name: "empty {{ .Resource.Kind }}",
+		},
+		// TODO(user): add the cases of the specs your validation logic accepts and rejects
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			obj := new{{ .Resource.Kind }}(tt.spec)
+			err := k8sClient.Create(ctx, obj)
+			if isDenied(err) != tt.wantDenied || (err != nil && !tt.wantDenied) {
+				t.Fatalf("Create() error = %v, wantDenied %v", err, tt.wantDenied)
+			}
+			if err == nil {
+				if err := k8sClient.Delete(ctx, obj); err != nil {
+					t.Errorf("unable to delete the {{ .Resource.Kind }}: %v", err)
+				}
+			}
+		})
+	}
+}
+`
+)
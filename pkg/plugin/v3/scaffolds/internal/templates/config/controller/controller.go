@@ -17,9 +17,10 @@ limitations under the License.
 package controller
 
 import (
-	"fmt"
 	"path/filepath"
+	"strings"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
 )
 
@@ -28,26 +29,59 @@ var _ file.Template = &Controller{}
 // Controller scaffolds a Controller for a Resource
 type Controller struct {
 	file.TemplateMixin
-	file.MultiGroupMixin
+	file.DirectoriesMixin
 	file.BoilerplateMixin
 	file.ResourceMixin
+	file.RepositoryMixin
+
+	// Force keeps the file if it already exists instead of failing
+	Force bool
+	// WithPredicates filters the events of the resource that trigger reconciles with the predicates of predicates.go
+	WithPredicates bool
+	// ServerSideApply reconciles the resource by server-side applying the fields the controller owns with the apply
+	// configuration generated for it
+	ServerSideApply bool
+	// WithEvents records events on the reconciled objects with the EventRecorder the reconciler gets from the manager
+	WithEvents bool
+	// WithControllerOptions sets the concurrency and rate limiting options of controller_options.go, bound to flags of
+	// main.go, on the controller
+	WithControllerOptions bool
+	// WithConditions maintains the Ready, Progressing and Degraded conditions of the status of the reconciled objects
+	WithConditions bool
+	// WithMetrics counts the outcomes of the reconciliations with the custom metrics of monitoring/metrics.go
+	WithMetrics bool
+	// Tracing starts a span of the global tracer provider set up by main.go for every reconciliation
+	Tracing bool
+	// NamespaceScoped scopes the RBAC markers to the namespace of the manager, so that controller-gen generates a
+	// Role instead of a ClusterRole
+	NamespaceScoped bool
+
+	// ApplyConfigurationPackage is the Go package of the apply configurations of the group-version of the resource
+	ApplyConfigurationPackage string
 }
 
 // SetTemplateDefaults implements input.Template
 func (f *Controller) SetTemplateDefaults() error {
 	if f.Path == "" {
-		if f.MultiGroup {
-			f.Path = filepath.Join("controllers", "%[group]", "%[kind]_controller.go")
-		} else {
-			f.Path = filepath.Join("controllers", "%[kind]_controller.go")
-		}
+		f.Path = filepath.Join(f.Directories.Controller, "%[kind]_controller.go")
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-	fmt.Println(f.Path)
+	logger.Default().Info(f.Path)
 
 	f.TemplateBody = controllerTemplate
 
-	f.IfExistsAction = file.Error
+	if f.ServerSideApply && f.ApplyConfigurationPackage == "" {
+		// applyconfiguration-gen names the package of a group after its first segment, without dashes
+		group := strings.Split(f.Resource.Group, ".")[0]
+		f.ApplyConfigurationPackage = strings.Join([]string{f.Repo, "pkg", "applyconfiguration",
+			strings.ToLower(strings.Replace(group, "-", "", -1)), f.Resource.Version}, "/")
+	}
+
+	if f.Force {
+		f.IfExistsAction = file.Skip
+	} else {
+		f.IfExistsAction = file.Error
+	}
 
 	return nil
 }
@@ -55,39 +89,205 @@ func (f *Controller) SetTemplateDefaults() error {
 //nolint:lll
 const controllerTemplate = `{{ .Boilerplate }}
 
-package controllers
+package {{ .Directories.ControllersPackage }}
 
 import (
 	"context"
 	"github.com/go-logr/logr"
+	{{- if .Tracing }}
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+	{{- end }}
+	{{- if .WithEvents }}
+	corev1 "k8s.io/api/core/v1"
+	{{- end }}
+	{{- if .WithConditions }}
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	{{- end }}
+	{{- if .ServerSideApply }}
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	{{- end }}
 	"k8s.io/apimachinery/pkg/runtime"
+	{{- if .WithPredicates }}
+	"k8s.io/apimachinery/pkg/labels"
+	{{- end }}
+	{{- if .WithEvents }}
+	"k8s.io/client-go/tools/record"
+	{{- end }}
 	ctrl "sigs.k8s.io/controller-runtime"
+	{{- if .WithPredicates }}
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	{{- end }}
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	{{- if .WithPredicates }}
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	{{- end }}
 	{{ .Resource.ImportAlias }} "{{ .Resource.Package }}"
+	{{- if .ServerSideApply }}
+	{{ .Resource.ImportAlias }}ac "{{ .ApplyConfigurationPackage }}"
+	{{- end }}
+	{{- if .WithMetrics }}
+	"{{ .Repo }}/monitoring"
+	{{- end }}
 )
+{{- if .ServerSideApply }}
+
+// {{ .Resource.Kind | lower }}FieldOwner is the manager of the fields applied by the {{ .Resource.Kind }}Reconciler
+const {{ .Resource.Kind | lower }}FieldOwner = "{{ .Resource.Kind | lower }}-controller"
+{{- end }}
 
 // {{ .Resource.Kind }}Reconciler reconciles a {{ .Resource.Kind }} object
 type {{ .Resource.Kind }}Reconciler struct {
 	client.Client
 	Log logr.Logger
 	Scheme *runtime.Scheme
+	{{- if .WithEvents }}
+	// Recorder records the events of the reconciliations, defaulted by SetupWithManager
+	Recorder record.EventRecorder
+	{{- end }}
+	{{- if .WithControllerOptions }}
+	// Options are the concurrency and rate limiting options of the controller
+	Options ControllerOptions
+	{{- end }}
 }
 
-// +kubebuilder:rbac:groups={{ .Resource.Domain }},resources={{ .Resource.Plural }},verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups={{ .Resource.Domain }},resources={{ .Resource.Plural }}/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups={{ .Resource.Domain }},resources={{ .Resource.Plural }},verbs=get;list;watch;create;update;patch;delete{{ if .NamespaceScoped }},namespace=system{{ end }}
+// +kubebuilder:rbac:groups={{ .Resource.Domain }},resources={{ .Resource.Plural }}/status,verbs=get;update;patch{{ if .NamespaceScoped }},namespace=system{{ end }}
+{{- if .WithEvents }}
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch{{ if .NamespaceScoped }},namespace=system{{ end }}
+{{- end }}
 
-func (r *{{ .Resource.Kind }}Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+func (r *{{ .Resource.Kind }}Reconciler) Reconcile(req ctrl.Request) ({{ if .WithMetrics }}result ctrl.Result, err error{{ else }}ctrl.Result, error{{ end }}) {
+	{{- if .WithMetrics }}
+	// The outcome is counted by the metrics of the monitoring package, which also time the calls to external
+	// services, e.g. with defer monitoring.ObserveExternalCall("{{ .Resource.Kind | lower }}", "get-weather", time.Now())
+	defer func() {
+		monitoring.RecordReconcile("{{ .Resource.Kind | lower }}", result, err)
+	}()
+	{{- end }}
+	{{- if .Tracing }}
+	// The span is exported along with the ones started from ctx, e.g. with span.RecordError(ctx, err) on failures
+	ctx, span := global.Tracer("{{ .Resource.Kind | lower }}-controller").Start(context.Background(),
+		"{{ .Resource.Kind }}Reconciler.Reconcile", trace.WithAttributes(
+			label.String("namespace", req.Namespace), label.String("name", req.Name)))
+	defer span.End()
+	{{- if not (or .ServerSideApply .WithEvents .WithConditions) }}
+	_ = ctx
+	{{- end }}
+	{{- else if or .ServerSideApply .WithEvents .WithConditions }}
+	ctx := context.Background()
+	{{- else }}
 	_ = context.Background()
+	{{- end }}
 	_ = r.Log.WithValues("{{ .Resource.Kind | lower }}", req.NamespacedName)
 
 	// your logic here
+	{{- if or .ServerSideApply .WithEvents .WithConditions }}
+
+	// {{ if .ServerSideApply }}An apply also creates the object if it does not exist{{ else if .WithEvents }}The events are recorded on the object{{ else }}The conditions are set on the status of the object{{ end }}, so make sure it is still there
+	var {{ .Resource.Kind | lower }} {{ .Resource.ImportAlias }}.{{ .Resource.Kind }}
+	if err := r.Get(ctx, req.NamespacedName, &{{ .Resource.Kind | lower }}); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	{{- end }}
+	{{- if .ServerSideApply }}
+	if !{{ .Resource.Kind | lower }}.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Unlike the Go types, whose zero values would be applied too, the apply configurations of the desired state
+	// only hold the fields the controller owns, the other fields are left to their managers. Unlike an update of
+	// the object, applying them does not need its latest version, so it is not retried on conflicts.
+	// TODO(user): set the fields owned by the controller
+	desired := {{ .Resource.ImportAlias }}ac.{{ .Resource.Kind }}({{ if .Resource.Namespaced }}req.Name, req.Namespace{{ else }}req.Name{{ end }}).
+		WithLabels(map[string]string{"app.kubernetes.io/managed-by": {{ .Resource.Kind | lower }}FieldOwner})
+	if err := r.apply(ctx, desired, false); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// The status is a subresource, applied on its own
+	// TODO(user): set the fields of the status owned by the controller
+	desiredStatus := {{ .Resource.ImportAlias }}ac.{{ .Resource.Kind }}({{ if .Resource.Namespaced }}req.Name, req.Namespace{{ else }}req.Name{{ end }}).
+		WithStatus({{ .Resource.ImportAlias }}ac.{{ .Resource.Kind }}Status())
+	if err := r.apply(ctx, desiredStatus, true); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	{{- end }}
+	{{- if .WithConditions }}
+
+	// The conditions report the outcome of the reconciliation of the observed generation of the spec.
+	// TODO(user): set Progressing to True while the {{ .Resource.Kind }} converges, and Degraded to True with the
+	// reason of a failure, e.g.
+	// {{ .Resource.ImportAlias }}.SetStatusCondition(&{{ .Resource.Kind | lower }}.Status.Conditions, {{ .Resource.ImportAlias }}.Condition{Type: {{ .Resource.ImportAlias }}.ConditionDegraded,
+	// 	Status: metav1.ConditionTrue, Reason: "ReconcileFailed", Message: err.Error(), ObservedGeneration: {{ .Resource.Kind | lower }}.Generation})
+	for _, condition := range []{{ .Resource.ImportAlias }}.Condition{
+		{Type: {{ .Resource.ImportAlias }}.ConditionReady, Status: metav1.ConditionTrue, Reason: "Reconciled", Message: "{{ .Resource.Kind }} reconciled"},
+		{Type: {{ .Resource.ImportAlias }}.ConditionProgressing, Status: metav1.ConditionFalse, Reason: "Reconciled"},
+		{Type: {{ .Resource.ImportAlias }}.ConditionDegraded, Status: metav1.ConditionFalse, Reason: "Reconciled"},
+	} {
+		condition.ObservedGeneration = {{ .Resource.Kind | lower }}.Generation
+		{{ .Resource.ImportAlias }}.SetStatusCondition(&{{ .Resource.Kind | lower }}.Status.Conditions, condition)
+	}
+	if err := r.Status().Update(ctx, &{{ .Resource.Kind | lower }}); err != nil {
+		return ctrl.Result{}, err
+	}
+	{{- end }}
+	{{- if .WithEvents }}
+
+	// The events are shown by 'kubectl describe', the recorder aggregating the repeated ones. Failures are
+	// recorded as Warning events, e.g.
+	// r.Recorder.Eventf(&{{ .Resource.Kind | lower }}, corev1.EventTypeWarning, "ReconcileFailed", "Failed to reconcile: %v", err)
+	r.Recorder.Event(&{{ .Resource.Kind | lower }}, corev1.EventTypeNormal, "Reconciled", "{{ .Resource.Kind }} reconciled")
+	{{- end }}
 
 	return ctrl.Result{}, nil
 }
+{{- if .ServerSideApply }}
+
+// apply server-side applies the fields set in obj as the {{ .Resource.Kind }}Reconciler, through the status
+// subresource if status is true
+func (r *{{ .Resource.Kind }}Reconciler) apply(ctx context.Context, obj *{{ .Resource.ImportAlias }}ac.{{ .Resource.Kind }}ApplyConfiguration, status bool) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	patched := &unstructured.Unstructured{Object: content}
+	// ForceOwnership takes over the applied fields from their other managers instead of failing with a conflict
+	opts := []client.PatchOption{client.FieldOwner({{ .Resource.Kind | lower }}FieldOwner), client.ForceOwnership}
+	if status {
+		return r.Status().Patch(ctx, patched, client.Apply, opts...)
+	}
+	return r.Patch(ctx, patched, client.Apply, opts...)
+}
+{{- end }}
 
 func (r *{{ .Resource.Kind }}Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	{{- if .WithEvents }}
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("{{ .Resource.Kind | lower }}-controller")
+	}
+	{{- end }}
 	return ctrl.NewControllerManagedBy(mgr).
+		{{- if .WithPredicates }}
+		For(&{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{}, builder.WithPredicates(
+			// Updates of the metadata or the status do not change the generation, so they do not trigger reconciles.
+			// Objects with no spec, e.g. ConfigMaps, never change their generation, remove it to watch those.
+			predicate.GenerationChangedPredicate{},
+			// TODO(user): restrict the selector to only reconcile the objects with some labels, e.g.
+			// labels.SelectorFromSet(labels.Set{"app.kubernetes.io/managed-by": "my-operator"})
+			labelSelectorPredicate(labels.Everything()),
+		)).
+		// Event filters apply to the events of all the watched objects, e.g. to ignore the deletions:
+		// WithEventFilter(predicate.Funcs{
+		// 	DeleteFunc: func(e event.DeleteEvent) bool { return false },
+		// }).
+		{{- else }}
 		For(&{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{}).
+		{{- end }}
+		{{- if .WithControllerOptions }}
+		WithOptions(r.Options.toControllerOptions()).
+		{{- end }}
 		Complete(r)
 }
 `
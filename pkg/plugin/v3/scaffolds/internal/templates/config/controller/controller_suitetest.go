@@ -19,8 +19,11 @@ package controller
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
 )
 
 var _ file.Template = &SuiteTest{}
@@ -30,23 +33,34 @@ var _ file.Inserter = &SuiteTest{}
 type SuiteTest struct {
 	file.TemplateMixin
 	file.RepositoryMixin
-	file.MultiGroupMixin
+	file.DirectoriesMixin
 	file.BoilerplateMixin
 	file.ResourceMixin
+
+	// TestFramework is the framework the suite is written with, Ginkgo v1 is used if empty
+	TestFramework string
+
+	// RootPath are the quoted ".." path elements leading from the directory of the suite to the root of the
+	// project, where the CRDs are generated in config/crd/bases
+	RootPath string
 }
 
 // SetTemplateDefaults implements file.Template
 func (f *SuiteTest) SetTemplateDefaults() error {
 	if f.Path == "" {
-		if f.MultiGroup {
-			f.Path = filepath.Join("controllers", "%[group]", "suite_test.go")
-		} else {
-			f.Path = filepath.Join("controllers", "suite_test.go")
-		}
+		f.Path = filepath.Join(f.Directories.Controller, "suite_test.go")
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-
-	f.TemplateBody = fmt.Sprintf(controllerSuiteTestTemplate,
+	f.RootPath = strings.Repeat(`"..", `, len(strings.Split(filepath.ToSlash(filepath.Dir(f.Path)), "/")))
+
+	template := controllerSuiteTestTemplate
+	switch f.TestFramework {
+	case config.TestFrameworkGinkgoV2:
+		template = controllerSuiteTestGinkgoV2Template
+	case config.TestFrameworkGoTest:
+		template = controllerSuiteTestGoTestTemplate
+	}
+	f.TemplateBody = fmt.Sprintf(template,
 		file.NewMarkerFor(f.Path, importMarker),
 		file.NewMarkerFor(f.Path, addSchemeMarker),
 	)
@@ -73,20 +87,37 @@ const (
 	addschemeCodeFragment = `err = %s.AddToScheme(scheme.Scheme)
 Expect(err).NotTo(HaveOccurred())
 
+`
+	goTestAddschemeCodeFragment = `if err = %s.AddToScheme(scheme.Scheme); err != nil {
+	return err
+}
+
 `
 )
 
+// SuiteTestFragments returns the import and scheme registration code fragments of suite_test.go for res, written
+// with testFramework, so that they can also be removed when the resource is deleted
+func SuiteTestFragments(res *resource.Resource, testFramework string) (apiImport, addScheme string) {
+	addSchemeFragment := addschemeCodeFragment
+	if testFramework == config.TestFrameworkGoTest {
+		addSchemeFragment = goTestAddschemeCodeFragment
+	}
+	return fmt.Sprintf(apiImportCodeFragment, res.ImportAlias, res.Package),
+		fmt.Sprintf(addSchemeFragment, res.ImportAlias)
+}
+
 // GetCodeFragments implements file.Inserter
 func (f *SuiteTest) GetCodeFragments() file.CodeFragmentsMap {
 	fragments := make(file.CodeFragmentsMap, 2)
+	apiImport, apiAddScheme := SuiteTestFragments(f.Resource, f.TestFramework)
 
 	// Generate import code fragments
 	imports := make([]string, 0)
-	imports = append(imports, fmt.Sprintf(apiImportCodeFragment, f.Resource.ImportAlias, f.Resource.Package))
+	imports = append(imports, apiImport)
 
 	// Generate add scheme code fragments
 	addScheme := make([]string, 0)
-	addScheme = append(addScheme, fmt.Sprintf(addschemeCodeFragment, f.Resource.ImportAlias))
+	addScheme = append(addScheme, apiAddScheme)
 
 	// Only store code fragments in the map if the slices are non-empty
 	if len(imports) != 0 {
@@ -101,7 +132,7 @@ func (f *SuiteTest) GetCodeFragments() file.CodeFragmentsMap {
 
 const controllerSuiteTestTemplate = `{{ .Boilerplate }}
 
-package controllers
+package {{ .Directories.ControllersPackage }}
 
 import (
 	"path/filepath"
@@ -138,7 +169,7 @@ var _ = BeforeSuite(func(done Done) {
 
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths: []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		CRDDirectoryPaths: []string{filepath.Join({{ .RootPath }}"config", "crd", "bases")},
 	}
 
 	var err error
@@ -161,3 +192,117 @@ var _ = AfterSuite(func() {
 	Expect(err).ToNot(HaveOccurred())
 })
 `
+
+const controllerSuiteTestGinkgoV2Template = `{{ .Boilerplate }}
+
+package {{ .Directories.ControllersPackage }}
+
+import (
+	"path/filepath"
+	"testing"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	%s
+)
+
+// These tests use Ginkgo v2 (BDD-style Go testing framework). Refer to
+// https://onsi.github.io/ginkgo/ to learn more about Ginkgo.
+
+var cfg *rest.Config
+var k8sClient client.Client
+var testEnv *envtest.Environment
+
+func TestAPIs(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Controller Suite")
+}
+
+var _ = BeforeSuite(func() {
+	logf.SetLogger(zap.LoggerTo(GinkgoWriter, true))
+
+	By("bootstrapping test environment")
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths: []string{filepath.Join({{ .RootPath }}"config", "crd", "bases")},
+	}
+
+	var err error
+	cfg, err = testEnv.Start()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(cfg).ToNot(BeNil())
+
+	%s
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(k8sClient).ToNot(BeNil())
+})
+
+var _ = AfterSuite(func() {
+	By("tearing down the test environment")
+	err := testEnv.Stop()
+	Expect(err).ToNot(HaveOccurred())
+})
+`
+
+const controllerSuiteTestGoTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Directories.ControllersPackage }}
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	%s
+)
+
+// These tests use the standard testing package, TestMain runs them against the API server
+// of the envtest environment.
+
+var k8sClient client.Client
+
+func TestMain(m *testing.M) {
+	logf.SetLogger(zap.LoggerTo(os.Stderr, true))
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths: []string{filepath.Join({{ .RootPath }}"config", "crd", "bases")},
+	}
+	code := 1
+	if err := startTestEnv(testEnv); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to start the test environment: %%v\n", err)
+	} else {
+		code = m.Run()
+	}
+
+	if err := testEnv.Stop(); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to stop the test environment: %%v\n", err)
+		code = 1
+	}
+	os.Exit(code)
+}
+
+// startTestEnv starts testEnv and creates the client the tests use to reach its API server
+func startTestEnv(testEnv *envtest.Environment) error {
+	cfg, err := testEnv.Start()
+	if err != nil {
+		return err
+	}
+
+	%s
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	return err
+}
+`
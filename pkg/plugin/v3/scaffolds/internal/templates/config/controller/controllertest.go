@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &ControllerTest{}
+
+// ControllerTest scaffolds the tests of a Controller, run against the envtest environment of suite_test.go
+type ControllerTest struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+
+	// TestFramework is the framework the tests are written with, either Ginkgo v2 or the standard testing package
+	TestFramework string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *ControllerTest) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(f.Directories.Controller, "%[kind]_controller_test.go")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+	logger.Default().Info(f.Path)
+
+	f.TemplateBody = controllerTestTemplate
+	if f.TestFramework == config.TestFrameworkGoTest {
+		f.TemplateBody = controllerGoTestTemplate
+	}
+
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+//nolint:lll
+const controllerTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Directories.ControllersPackage }}
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	{{ .Resource.ImportAlias }} "{{ .Resource.Package }}"
+)
+
+var _ = Describe("{{ .Resource.Kind }} controller", Label("controller"), func() {
+	It("should reconcile a created {{ .Resource.Kind }}", func(ctx SpecContext) {
+		key := types.NamespacedName{Name: "test-{{ lower .Resource.Kind }}"{{ if .Resource.Namespaced }}, Namespace: "default"{{ end }}}
+		obj := &{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		}
+		Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(context.Background(), obj)).To(Succeed())
+		})
+
+		reconciler := &{{ .Resource.Kind }}Reconciler{
+			Client: k8sClient,
+			Log:    ctrl.Log.WithName("controllers").WithName("{{ .Resource.Kind }}"),
+			Scheme: scheme.Scheme,
+		}
+		_, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		// TODO(user): check the state of the cluster the reconciliation led to
+	}, SpecTimeout(time.Minute))
+})
+`
+
+//nolint:lll
+const controllerGoTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Directories.ControllersPackage }}
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	{{ .Resource.ImportAlias }} "{{ .Resource.Package }}"
+)
+
+func Test{{ .Resource.Kind }}Reconcile(t *testing.T) {
+	tests := []struct {
+		name    string
+		obj     *{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}
+		wantErr bool
+	}{
+		{
+			name: "created {{ .Resource.Kind }}",
+			obj: &{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-{{ lower .Resource.Kind }}"{{ if .Resource.Namespaced }}, Namespace: "default"{{ end }}},
+			},
+		},
+		// TODO(user): add the cases of your reconciliation logic
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := k8sClient.Create(ctx, tt.obj); err != nil {
+				t.Fatalf("unable to create the {{ .Resource.Kind }}: %v", err)
+			}
+			defer func() {
+				if err := k8sClient.Delete(ctx, tt.obj); err != nil {
+					t.Errorf("unable to delete the {{ .Resource.Kind }}: %v", err)
+				}
+			}()
+
+			reconciler := &{{ .Resource.Kind }}Reconciler{
+				Client: k8sClient,
+				Log:    ctrl.Log.WithName("controllers").WithName("{{ .Resource.Kind }}"),
+				Scheme: scheme.Scheme,
+			}
+			key := types.NamespacedName{Name: tt.obj.Name, Namespace: tt.obj.Namespace}
+			if _, err := reconciler.Reconcile(ctrl.Request{NamespacedName: key}); (err != nil) != tt.wantErr {
+				t.Errorf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			// TODO(user): check the state of the cluster the reconciliation led to
+		})
+	}
+}
+`
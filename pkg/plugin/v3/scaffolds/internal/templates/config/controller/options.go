@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Options{}
+
+// Options scaffolds the concurrency and rate limiting options shared by the Controllers of a package, bound to
+// command-line flags of main.go prefixed with the name of each Controller
+type Options struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Options) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(f.Directories.Controller, "controller_options.go")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+	logger.Default().Info(f.Path)
+
+	f.TemplateBody = optionsTemplate
+
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+//nolint:lll
+const optionsTemplate = `{{ .Boilerplate }}
+
+package {{ .Directories.ControllersPackage }}
+
+import (
+	"flag"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+// ControllerOptions are the concurrency and rate limiting options of a controller, their defaults being the ones
+// of controller-runtime
+type ControllerOptions struct {
+	// MaxConcurrentReconciles is the maximum number of objects reconciled concurrently
+	MaxConcurrentReconciles int
+	// RateLimiterBaseDelay and RateLimiterMaxDelay bound the exponential backoff of the requeues of an object
+	// after failed reconciliations
+	RateLimiterBaseDelay time.Duration
+	RateLimiterMaxDelay  time.Duration
+	// RateLimiterQPS and RateLimiterBurst limit the rate of the requeues of all the objects
+	RateLimiterQPS   float64
+	RateLimiterBurst int
+}
+
+// BindFlags binds the options to the flags of fs prefixed with the name of the controller, e.g.
+// --memcached-max-concurrent-reconciles
+func (o *ControllerOptions) BindFlags(fs *flag.FlagSet, name string) {
+	fs.IntVar(&o.MaxConcurrentReconciles, name+"-max-concurrent-reconciles", 1,
+		"The maximum number of objects reconciled concurrently by the "+name+" controller.")
+	fs.DurationVar(&o.RateLimiterBaseDelay, name+"-rate-limiter-base-delay", 5*time.Millisecond,
+		"The delay of the first requeue of an object after a failed reconciliation by the "+name+" controller, "+
+			"doubled on each subsequent failure.")
+	fs.DurationVar(&o.RateLimiterMaxDelay, name+"-rate-limiter-max-delay", 1000*time.Second,
+		"The maximum delay of the requeues of an object after failed reconciliations by the "+name+" controller.")
+	fs.Float64Var(&o.RateLimiterQPS, name+"-rate-limiter-qps", 10,
+		"The number of requeues per second of all the objects of the "+name+" controller.")
+	fs.IntVar(&o.RateLimiterBurst, name+"-rate-limiter-burst", 100,
+		"The maximum burst of requeues of all the objects of the "+name+" controller.")
+}
+
+// toControllerOptions returns the options of controller-runtime, rate limiting the requeues as its default rate
+// limiter does with the limits of o
+func (o ControllerOptions) toControllerOptions() controller.Options {
+	return controller.Options{
+		MaxConcurrentReconciles: o.MaxConcurrentReconciles,
+		RateLimiter: workqueue.NewMaxOfRateLimiter(
+			workqueue.NewItemExponentialFailureRateLimiter(o.RateLimiterBaseDelay, o.RateLimiterMaxDelay),
+			&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(o.RateLimiterQPS), o.RateLimiterBurst)},
+		),
+	}
+}
+`
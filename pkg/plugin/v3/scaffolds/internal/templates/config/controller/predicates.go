@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Predicates{}
+
+// Predicates scaffolds the predicates shared by the Controllers of a package to filter the events that trigger
+// their reconciles
+type Predicates struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Predicates) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(f.Directories.Controller, "predicates.go")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+	logger.Default().Info(f.Path)
+
+	f.TemplateBody = predicatesTemplate
+
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+const predicatesTemplate = `{{ .Boilerplate }}
+
+package {{ .Directories.ControllersPackage }}
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// labelSelectorPredicate only lets through the events of the objects whose labels match selector
+func labelSelectorPredicate(selector labels.Selector) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(meta metav1.Object, _ runtime.Object) bool {
+		return selector.Matches(labels.Set(meta.GetLabels()))
+	})
+}
+`
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &SamplesTest{}
+
+// SamplesTest scaffolds the test creating the samples of config/samples with a dry run against the API server of
+// the envtest environment of suite_test.go, so that they are validated by the schemas of the generated CRDs
+type SamplesTest struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+	file.MultiGroupMixin
+
+	// TestFramework is the framework the test is written with, Ginkgo v1 is used if empty
+	TestFramework string
+
+	// RootPath are the quoted ".." path elements leading from the directory of the test to the root of the project
+	RootPath string
+	// SamplesPattern matches the file names of the samples of config/samples the test creates, only the ones of
+	// the group of the controllers in multi-group projects
+	SamplesPattern string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *SamplesTest) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(f.Directories.Controller, "samples_test.go")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+	logger.Default().Info(f.Path)
+	f.RootPath = strings.Repeat(`"..", `, len(strings.Split(filepath.ToSlash(filepath.Dir(f.Path)), "/")))
+
+	// The samples are named after their group, version and kind
+	f.SamplesPattern = "*.yaml"
+	if f.MultiGroup {
+		f.SamplesPattern = f.Resource.Group + "_*.yaml"
+	}
+
+	f.TemplateBody = samplesTestTemplate
+	if f.TestFramework == config.TestFrameworkGoTest {
+		f.TemplateBody = samplesGoTestTemplate
+	}
+
+	// The test is shared by the resources of the controllers of the directory
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+// GinkgoV2 returns whether the test is written with Ginkgo v2
+func (f *SamplesTest) GinkgoV2() bool {
+	return f.TestFramework == config.TestFrameworkGinkgoV2
+}
+
+const samplesTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Directories.ControllersPackage }}
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+{{ if .GinkgoV2 }}	. "github.com/onsi/ginkgo/v2"{{ else }}	. "github.com/onsi/ginkgo"{{ end }}
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Samples", {{ if .GinkgoV2 }}Label("samples"), {{ end }}func() {
+	It("should be valid against the schemas of the CRDs", func() {
+		paths, err := filepath.Glob(filepath.Join({{ .RootPath }}"config", "samples", "{{ .SamplesPattern }}"))
+		Expect(err).NotTo(HaveOccurred())
+		for _, path := range paths {
+			if filepath.Base(path) == "kustomization.yaml" {
+				continue
+			}
+			samples, err := readSamples(path)
+			Expect(err).NotTo(HaveOccurred(), "unable to read %s", path)
+			for _, sample := range samples {
+				// The API server validates the sample without storing it
+				Expect(k8sClient.Create(context.Background(), sample, client.DryRunAll)).To(Succeed(),
+					"%s %s of %s is invalid", sample.GetKind(), sample.GetName(), path)
+			}
+		}
+	})
+})
+` + samplesReaderTemplate
+
+const samplesGoTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Directories.ControllersPackage }}
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TestSamples creates the samples of config/samples with a dry run, failing if they are not valid against the
+// schemas of the CRDs
+func TestSamples(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join({{ .RootPath }}"config", "samples", "{{ .SamplesPattern }}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		if filepath.Base(path) == "kustomization.yaml" {
+			continue
+		}
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			samples, err := readSamples(path)
+			if err != nil {
+				t.Fatalf("unable to read the samples: %v", err)
+			}
+			for _, sample := range samples {
+				// The API server validates the sample without storing it
+				if err := k8sClient.Create(context.Background(), sample, client.DryRunAll); err != nil {
+					t.Errorf("%s %s is invalid: %v", sample.GetKind(), sample.GetName(), err)
+				}
+			}
+		})
+	}
+}
+` + samplesReaderTemplate
+
+// samplesReaderTemplate is the function of the samples test reading the samples of a file
+const samplesReaderTemplate = `
+// readSamples decodes the objects of the YAML documents of the file at path, in the default namespace unless they
+// set one, which is ignored for the cluster-scoped resources
+func readSamples(path string) ([]*unstructured.Unstructured, error) {
+	f, err := os.Open(path) // nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var samples []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		sample := &unstructured.Unstructured{}
+		if err := decoder.Decode(&sample.Object); err == io.EOF {
+			return samples, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(sample.Object) == 0 {
+			continue
+		}
+		if sample.GetNamespace() == "" {
+			sample.SetNamespace("default")
+		}
+		samples = append(samples, sample)
+	}
+}
+`
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &ValidationTest{}
+
+// ValidationTest scaffolds the tests asserting that the API server of the envtest environment rejects the objects
+// breaking the validation markers of the types
+type ValidationTest struct {
+	file.TemplateMixin
+	file.DirectoriesMixin
+	file.BoilerplateMixin
+	file.ResourceMixin
+
+	// TestFramework is the framework the tests are written with
+	TestFramework string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *ValidationTest) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(f.Directories.Controller, "%[kind]_validation_test.go")
+	}
+	f.Path = f.Resource.Replacer().Replace(f.Path)
+	logger.Default().Info(f.Path)
+
+	f.TemplateBody = validationTestTemplate
+	if f.TestFramework == config.TestFrameworkGoTest {
+		f.TemplateBody = validationGoTestTemplate
+	}
+
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+// GinkgoV2 returns whether the tests are written with Ginkgo v2
+func (f *ValidationTest) GinkgoV2() bool {
+	return f.TestFramework == config.TestFrameworkGinkgoV2
+}
+
+//nolint:lll
+const validationTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Directories.ControllersPackage }}
+
+import (
+	"context"
+
+{{ if .GinkgoV2 }}	. "github.com/onsi/ginkgo/v2"{{ else }}	. "github.com/onsi/ginkgo"{{ end }}
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	{{ .Resource.ImportAlias }} "{{ .Resource.Package }}"
+)
+
+var _ = Describe("{{ .Resource.Kind }} validation", func() {
+	ptr := func(i int32) *int32 { return &i }
+	newObj := func(spec {{ .Resource.ImportAlias }}.{{ .Resource.Kind }}Spec) *{{ .Resource.ImportAlias }}.{{ .Resource.Kind }} {
+		return &{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-validation-{{ lower .Resource.Kind }}"{{ if .Resource.Namespaced }}, Namespace: "default"{{ end }}},
+			Spec:       spec,
+		}
+	}
+
+	It("should accept a valid {{ .Resource.Kind }}", func() {
+		ctx := context.Background()
+		obj := newObj({{ .Resource.ImportAlias }}.{{ .Resource.Kind }}Spec{Foo: "foo", MinReplicas: ptr(1), MaxReplicas: ptr(3)})
+		Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+	})
+
+	It("should reject the {{ .Resource.Kind }} objects breaking the validation markers", func() {
+		ctx := context.Background()
+		for _, spec := range []{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}Spec{
+			{Foo: "Not_A_DNS_Label"},
+			{MinReplicas: ptr(-1)},
+			{MinReplicas: ptr(3), MaxReplicas: ptr(1)},
+			// TODO(user): add the specs breaking the validation markers of your fields
+		} {
+			err := k8sClient.Create(ctx, newObj(spec))
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected %+v to be invalid, got: %v", spec, err)
+		}
+	})
+
+	It("should reject a change of the immutable foo", func() {
+		ctx := context.Background()
+		obj := newObj({{ .Resource.ImportAlias }}.{{ .Resource.Kind }}Spec{Foo: "foo"})
+		Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+		}()
+
+		obj.Spec.Foo = "bar"
+		err := k8sClient.Update(ctx, obj)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected the update to be invalid, got: %v", err)
+	})
+})
+`
+
+//nolint:lll
+const validationGoTestTemplate = `{{ .Boilerplate }}
+
+package {{ .Directories.ControllersPackage }}
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	{{ .Resource.ImportAlias }} "{{ .Resource.Package }}"
+)
+
+func Test{{ .Resource.Kind }}Validation(t *testing.T) {
+	ptr := func(i int32) *int32 { return &i }
+	tests := []struct {
+		name        string
+		spec        {{ .Resource.ImportAlias }}.{{ .Resource.Kind }}Spec
+		wantInvalid bool
+	}{
+		{
+			name: "valid {{ .Resource.Kind }}",
+			spec: {{ .Resource.ImportAlias }}.{{ .Resource.Kind }}Spec{Foo: "foo", MinReplicas: ptr(1), MaxReplicas: ptr(3)},
+		},
+		{
+			name:        "foo not matching its pattern",
+			spec:        {{ .Resource.ImportAlias }}.{{ .Resource.Kind }}Spec{Foo: "Not_A_DNS_Label"},
+			wantInvalid: true,
+		},
+		{
+			name:        "negative minReplicas",
+			spec:        {{ .Resource.ImportAlias }}.{{ .Resource.Kind }}Spec{MinReplicas: ptr(-1)},
+			wantInvalid: true,
+		},
+		{
+			name:        "minReplicas greater than maxReplicas",
+			spec:        {{ .Resource.ImportAlias }}.{{ .Resource.Kind }}Spec{MinReplicas: ptr(3), MaxReplicas: ptr(1)},
+			wantInvalid: true,
+		},
+		// TODO(user): add the cases of the validation markers of your fields
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			obj := &{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-validation-{{ lower .Resource.Kind }}"{{ if .Resource.Namespaced }}, Namespace: "default"{{ end }}},
+				Spec:       tt.spec,
+			}
+			err := k8sClient.Create(ctx, obj)
+			if apierrors.IsInvalid(err) != tt.wantInvalid || (err != nil && !tt.wantInvalid) {
+				t.Fatalf("Create() error = %v, wantInvalid %v", err, tt.wantInvalid)
+			}
+			if err == nil {
+				if err := k8sClient.Delete(ctx, obj); err != nil {
+					t.Errorf("unable to delete the {{ .Resource.Kind }}: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func Test{{ .Resource.Kind }}ImmutableFoo(t *testing.T) {
+	ctx := context.Background()
+	obj := &{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-validation-{{ lower .Resource.Kind }}"{{ if .Resource.Namespaced }}, Namespace: "default"{{ end }}},
+		Spec:       {{ .Resource.ImportAlias }}.{{ .Resource.Kind }}Spec{Foo: "foo"},
+	}
+	if err := k8sClient.Create(ctx, obj); err != nil {
+		t.Fatalf("unable to create the {{ .Resource.Kind }}: %v", err)
+	}
+	defer func() {
+		if err := k8sClient.Delete(ctx, obj); err != nil {
+			t.Errorf("unable to delete the {{ .Resource.Kind }}: %v", err)
+		}
+	}()
+
+	obj.Spec.Foo = "bar"
+	if err := k8sClient.Update(ctx, obj); !apierrors.IsInvalid(err) {
+		t.Errorf("Update() error = %v, want an invalid error", err)
+	}
+}
+`
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hack
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &APIDocsConfig{}
+
+// APIDocsConfig scaffolds the crd-ref-docs configuration used by the api-docs target of the Makefile
+type APIDocsConfig struct {
+	file.TemplateMixin
+
+	// KubernetesVersion is the Kubernetes version the generated documentation links to
+	KubernetesVersion string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *APIDocsConfig) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("hack", "api-docs", "config.yaml")
+	}
+
+	f.TemplateBody = apiDocsConfigTemplate
+
+	return nil
+}
+
+const apiDocsConfigTemplate = `# Configuration of crd-ref-docs, which generates the reference documentation of the APIs with 'make api-docs'.
+# See https://github.com/elastic/crd-ref-docs for all the options.
+processor:
+  # RE2 regular expressions of the types excluded from the documentation
+  ignoreTypes:
+    - "List$"
+  # RE2 regular expressions of the type fields excluded from the documentation
+  ignoreFields:
+    - "TypeMeta$"
+
+render:
+  # Kubernetes version of the API documentation that the built-in types link to
+  kubernetesVersion: {{ .KubernetesVersion }}
+`
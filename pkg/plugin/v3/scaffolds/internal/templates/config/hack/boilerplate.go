@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/license"
 )
 
 var _ file.Template = &Boilerplate{}
@@ -31,7 +32,7 @@ type Boilerplate struct {
 	file.TemplateMixin
 	file.BoilerplateMixin
 
-	// License is the License type to write
+	// License is the identifier of the license of the catalog to write the header of
 	License string
 
 	// Owner is the copyright owner - e.g. "The Kubernetes Authors"
@@ -57,37 +58,11 @@ func (f *Boilerplate) SetTemplateDefaults() error {
 		return nil
 	}
 
-	// Pick a template boilerplate option
-	switch f.License {
-	case "", "apache2":
-		f.TemplateBody = apache
-	case "none":
-		f.TemplateBody = none
+	l, err := license.Lookup(f.License)
+	if err != nil {
+		return err
 	}
+	f.TemplateBody = l.Header
 
 	return nil
 }
-
-const apache = `/*
-{{ if .Owner -}}
-Copyright {{ .Year }} {{ .Owner }}.
-{{- end }}
-
-Licensed under the Apache License, Version 2.0 (the "License");
-you may not use this file except in compliance with the License.
-You may obtain a copy of the License at
-
-    http://www.apache.org/licenses/LICENSE-2.0
-
-Unless required by applicable law or agreed to in writing, software
-distributed under the License is distributed on an "AS IS" BASIS,
-WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-See the License for the specific language governing permissions and
-limitations under the License.
-*/`
-
-const none = `/*
-{{ if .Owner -}}
-Copyright {{ .Year }} {{ .Owner }}.
-{{- end }}
-*/`
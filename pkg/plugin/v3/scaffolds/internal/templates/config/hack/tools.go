@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hack
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Tools{}
+
+// Tools scaffolds the file importing the tools the Makefile builds into bin/
+type Tools struct {
+	file.TemplateMixin
+	file.BoilerplateMixin
+
+	// GolangciLint is true if golangci-lint is built by the Makefile
+	GolangciLint bool
+	// Ginkgo is true if the ginkgo CLI is built by the Makefile
+	Ginkgo bool
+	// CRDRefDocs is true if crd-ref-docs is built by the Makefile
+	CRDRefDocs bool
+	// CodeGenerator is true if client-gen, lister-gen and informer-gen are built by the Makefile
+	CodeGenerator bool
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Tools) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("hack", "tools", "tools.go")
+	}
+
+	f.TemplateBody = toolsTemplate
+
+	return nil
+}
+
+const toolsTemplate = `//go:build tools
+// +build tools
+
+{{ .Boilerplate }}
+
+// Package tools pins the versions of the tools the Makefile builds into bin/.
+// Their sources are verified against hack/tools/go.sum when they are built.
+package tools
+
+import (
+{{- if .CRDRefDocs }}
+	_ "github.com/elastic/crd-ref-docs"
+{{- end }}
+{{- if .GolangciLint }}
+	_ "github.com/golangci/golangci-lint/cmd/golangci-lint"
+{{- end }}
+{{- if .Ginkgo }}
+	_ "github.com/onsi/ginkgo/v2/ginkgo"
+{{- end }}
+{{- if .CodeGenerator }}
+	_ "k8s.io/code-generator/cmd/client-gen"
+	_ "k8s.io/code-generator/cmd/informer-gen"
+	_ "k8s.io/code-generator/cmd/lister-gen"
+{{- end }}
+	_ "sigs.k8s.io/controller-tools/cmd/controller-gen"
+	_ "sigs.k8s.io/kustomize/kustomize/v3"
+)
+`
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hack
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates"
+)
+
+var _ file.Template = &ToolsGoMod{}
+
+// ToolsGoMod scaffolds the go.mod pinning the versions of the tools the Makefile builds into bin/
+type ToolsGoMod struct {
+	file.TemplateMixin
+	file.RepositoryMixin
+
+	// GoVersion is the Go version targeted by the project
+	GoVersion string
+	// ControllerToolsVersion is the kubernetes-sigs/controller-tools version to build controller-gen from
+	ControllerToolsVersion string
+	// KustomizeVersion is the kubernetes-sigs/kustomize version to build kustomize from
+	KustomizeVersion string
+	// GolangciLintVersion is the golangci/golangci-lint version to build golangci-lint from, if not empty
+	GolangciLintVersion string
+	// GinkgoVersion is the onsi/ginkgo/v2 version to build the ginkgo CLI from, if not empty
+	GinkgoVersion string
+	// CRDRefDocsVersion is the elastic/crd-ref-docs version to build crd-ref-docs from, if not empty
+	CRDRefDocsVersion string
+	// CodeGeneratorVersion is the kubernetes/code-generator version to build the client generators from, if not
+	// empty
+	CodeGeneratorVersion string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *ToolsGoMod) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("hack", "tools", "go.mod")
+	}
+
+	f.TemplateBody = toolsGoModTemplate
+
+	if f.GoVersion == "" {
+		f.GoVersion = templates.DefaultGoVersion
+	}
+
+	return nil
+}
+
+const toolsGoModTemplate = `module {{ .Repo }}/hack/tools
+
+go {{ .GoVersion }}
+
+require (
+{{- if .CRDRefDocsVersion }}
+	github.com/elastic/crd-ref-docs {{ .CRDRefDocsVersion }}
+{{- end }}
+{{- if .GolangciLintVersion }}
+	github.com/golangci/golangci-lint {{ .GolangciLintVersion }}
+{{- end }}
+{{- if .GinkgoVersion }}
+	github.com/onsi/ginkgo/v2 {{ .GinkgoVersion }}
+{{- end }}
+{{- if .CodeGeneratorVersion }}
+	k8s.io/code-generator {{ .CodeGeneratorVersion }}
+{{- end }}
+	sigs.k8s.io/controller-tools {{ .ControllerToolsVersion }}
+	sigs.k8s.io/kustomize/kustomize/v3 {{ .KustomizeVersion }}
+)
+`
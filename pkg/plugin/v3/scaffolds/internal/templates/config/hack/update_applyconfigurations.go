@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hack
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &UpdateApplyConfigurations{}
+
+// UpdateApplyConfigurations scaffolds the script generating the apply configurations of the APIs, used to
+// server-side apply their objects, with the applyconfiguration-gen binary the Makefile builds into bin/
+type UpdateApplyConfigurations struct {
+	file.TemplateMixin
+	file.PermissionsMixin
+	file.RepositoryMixin
+	file.DirectoriesMixin
+
+	// BoilerplatePath is the path to the boilerplate file of the generated code
+	BoilerplatePath string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *UpdateApplyConfigurations) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("hack", "update-applyconfigurations.sh")
+	}
+
+	f.TemplateBody = updateApplyConfigurationsTemplate
+
+	f.Permissions = 0755
+
+	return nil
+}
+
+const updateApplyConfigurationsTemplate = `#!/usr/bin/env bash
+
+# Generates the apply configurations of the group-versions of the APIs into pkg/applyconfiguration, with the
+# applyconfiguration-gen binary built into bin/ by 'make apply-configurations'. Only the Kinds marked with
+# +genclient, and the types they refer to, get one.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+REPO={{ .Repo }}
+ROOT=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+BIN=${ROOT}/bin
+OUTPUT_PKG=${REPO}/pkg/applyconfiguration
+HEADER=${ROOT}/{{ .BoilerplatePath }}
+
+cd "${ROOT}"
+
+# Directory of the Go types of the APIs
+{{- if eq .Directories.APIs "api" }}, apis/ for multi-group projects
+API_DIR=$([ -d apis ] && echo apis || echo api)
+{{- else }}
+API_DIR={{ .Directories.APIs }}
+{{- end }}
+# Group-versions of the Kinds marked with +genclient, relative to the root of the project, e.g. api/v1
+GROUP_VERSIONS=$(grep -rl --include='*_types.go' '^// +genclient$' "${API_DIR}" 2>/dev/null | xargs -r -n1 dirname \
+	| sort -u || true)
+if [ -z "${GROUP_VERSIONS}" ]; then
+	echo "no Kind is marked with +genclient, skipping the generation of the apply configurations"
+	exit 0
+fi
+INPUT_DIRS=$(echo "${GROUP_VERSIONS}" | sed "s|^|${REPO}/|" | paste -sd, -)
+
+# The generator writes the packages under its output base, which is then copied into pkg/applyconfiguration
+OUTPUT_BASE=$(mktemp -d)
+trap 'rm -rf "${OUTPUT_BASE}"' EXIT
+
+"${BIN}/applyconfiguration-gen" --input-dirs "${INPUT_DIRS}" \
+	--output-package "${OUTPUT_PKG}" --output-base "${OUTPUT_BASE}" --go-header-file "${HEADER}"
+
+rm -rf pkg/applyconfiguration
+mkdir -p pkg
+cp -r "${OUTPUT_BASE}/${OUTPUT_PKG}" pkg/applyconfiguration
+`
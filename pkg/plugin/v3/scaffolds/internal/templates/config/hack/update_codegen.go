@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hack
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &UpdateCodegen{}
+
+// UpdateCodegen scaffolds the script generating the typed clientset, listers and informers of the APIs with the
+// code-generator binaries the Makefile builds into bin/
+type UpdateCodegen struct {
+	file.TemplateMixin
+	file.PermissionsMixin
+	file.RepositoryMixin
+	file.DirectoriesMixin
+
+	// BoilerplatePath is the path to the boilerplate file of the generated code
+	BoilerplatePath string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *UpdateCodegen) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("hack", "update-codegen.sh")
+	}
+
+	f.TemplateBody = updateCodegenTemplate
+
+	f.Permissions = 0755
+
+	return nil
+}
+
+const updateCodegenTemplate = `#!/usr/bin/env bash
+
+# Generates the typed clientset, listers and informers of the group-versions of the APIs into pkg/client, with the
+# code-generator binaries built into bin/ by 'make clients'. Only the Kinds marked with +genclient are generated.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+REPO={{ .Repo }}
+ROOT=$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)
+BIN=${ROOT}/bin
+OUTPUT_PKG=${REPO}/pkg/client
+HEADER=${ROOT}/{{ .BoilerplatePath }}
+
+cd "${ROOT}"
+
+# Directory of the Go types of the APIs
+{{- if eq .Directories.APIs "api" }}, apis/ for multi-group projects
+API_DIR=$([ -d apis ] && echo apis || echo api)
+{{- else }}
+API_DIR={{ .Directories.APIs }}
+{{- end }}
+# Group-versions of the Kinds marked with +genclient, relative to the root of the project, e.g. api/v1
+GROUP_VERSIONS=$(grep -rl --include='*_types.go' '^// +genclient$' "${API_DIR}" 2>/dev/null | xargs -r -n1 dirname \
+	| sort -u || true)
+if [ -z "${GROUP_VERSIONS}" ]; then
+	echo "no Kind is marked with +genclient, skipping the generation of the clients"
+	exit 0
+fi
+INPUTS=$(echo "${GROUP_VERSIONS}" | paste -sd, -)
+INPUT_DIRS=$(echo "${GROUP_VERSIONS}" | sed "s|^|${REPO}/|" | paste -sd, -)
+
+# The generators write the packages under their output base, which is then copied into pkg/client
+OUTPUT_BASE=$(mktemp -d)
+trap 'rm -rf "${OUTPUT_BASE}"' EXIT
+
+"${BIN}/client-gen" --clientset-name versioned --input-base "${REPO}" --input "${INPUTS}" \
+	--output-package "${OUTPUT_PKG}/clientset" --output-base "${OUTPUT_BASE}" --go-header-file "${HEADER}"
+"${BIN}/lister-gen" --input-dirs "${INPUT_DIRS}" \
+	--output-package "${OUTPUT_PKG}/listers" --output-base "${OUTPUT_BASE}" --go-header-file "${HEADER}"
+"${BIN}/informer-gen" --input-dirs "${INPUT_DIRS}" \
+	--versioned-clientset-package "${OUTPUT_PKG}/clientset/versioned" --listers-package "${OUTPUT_PKG}/listers" \
+	--output-package "${OUTPUT_PKG}/informers" --output-base "${OUTPUT_BASE}" --go-header-file "${HEADER}"
+
+rm -rf pkg/client
+mkdir -p pkg
+cp -r "${OUTPUT_BASE}/${OUTPUT_PKG}" pkg/client
+`
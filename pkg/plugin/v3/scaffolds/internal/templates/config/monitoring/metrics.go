@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &Metrics{}
+
+// Metrics scaffolds the monitoring/metrics.go file registering example custom Prometheus metrics of the
+// controllers in the registry of controller-runtime, served on the metrics endpoint of the manager
+type Metrics struct {
+	file.TemplateMixin
+	file.BoilerplateMixin
+	file.ProjectNameMixin
+
+	// MetricsNamespace is the prefix of the names of the metrics, the project name with underscores
+	MetricsNamespace string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Metrics) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("monitoring", "metrics.go")
+	}
+	logger.Default().Info(f.Path)
+
+	f.TemplateBody = metricsTemplate
+
+	// The metrics are shared by the controllers of the project
+	f.IfExistsAction = file.Skip
+
+	// Prometheus metric names can not contain dashes
+	if f.MetricsNamespace == "" {
+		f.MetricsNamespace = strings.Replace(f.ProjectName, "-", "_", -1)
+	}
+
+	return nil
+}
+
+const metricsTemplate = `{{ .Boilerplate }}
+
+// Package monitoring holds the custom Prometheus metrics of the controllers, served along with the ones of
+// controller-runtime on the metrics endpoint of the manager.
+package monitoring
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The outcomes of the reconciliations counted by ReconcileOutcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeRequeue = "requeue"
+	OutcomeError   = "error"
+)
+
+var (
+	// ReconcileOutcomes counts the reconciliations of the controllers by outcome
+	ReconcileOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "{{ .MetricsNamespace }}",
+		Name:      "reconcile_outcomes_total",
+		Help:      "Number of reconciliations per controller and outcome.",
+	}, []string{"controller", "outcome"})
+
+	// ExternalCallDuration observes the durations of the calls of the controllers to services outside of the
+	// cluster, e.g. the API of a cloud provider
+	ExternalCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "{{ .MetricsNamespace }}",
+		Name:      "external_call_duration_seconds",
+		Help:      "Duration of the calls to external services per controller and call.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"controller", "call"})
+
+	// TODO(user): add your metrics, and register them below
+)
+
+func init() {
+	metrics.Registry.MustRegister(ReconcileOutcomes, ExternalCallDuration)
+}
+
+// RecordReconcile counts the outcome of a reconciliation of controller, that returned result and err
+func RecordReconcile(controller string, result ctrl.Result, err error) {
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	} else if result.Requeue || result.RequeueAfter > 0 {
+		outcome = OutcomeRequeue
+	}
+	ReconcileOutcomes.WithLabelValues(controller, outcome).Inc()
+}
+
+// ObserveExternalCall observes the duration of the call of controller started at start, it is meant to be
+// deferred when the call starts, e.g.
+//	defer monitoring.ObserveExternalCall("my-controller", "get-weather", time.Now())
+func ObserveExternalCall(controller, call string, start time.Time) {
+	ExternalCallDuration.WithLabelValues(controller, call).Observe(time.Since(start).Seconds())
+}
+`
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &MetricsTest{}
+
+// MetricsTest scaffolds the test asserting that the metrics of monitoring/metrics.go are registered in the
+// registry of controller-runtime and count the outcomes of the reconciliations
+type MetricsTest struct {
+	file.TemplateMixin
+	file.BoilerplateMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *MetricsTest) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join("monitoring", "metrics_test.go")
+	}
+	logger.Default().Info(f.Path)
+
+	f.TemplateBody = metricsTestTemplate
+
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+const metricsTestTemplate = `{{ .Boilerplate }}
+
+package monitoring
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func TestMetricsRegistration(t *testing.T) {
+	for name, collector := range map[string]prometheus.Collector{
+		"ReconcileOutcomes":    ReconcileOutcomes,
+		"ExternalCallDuration": ExternalCallDuration,
+	} {
+		// Registering a metric again fails if it is already registered
+		err := metrics.Registry.Register(collector)
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			t.Errorf("%s is not registered in the metrics registry of controller-runtime, Register() error = %v",
+				name, err)
+		}
+	}
+}
+
+func TestRecordReconcile(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  ctrl.Result
+		err     error
+		outcome string
+	}{
+		{name: "success", outcome: OutcomeSuccess},
+		{name: "requeue", result: ctrl.Result{RequeueAfter: time.Minute}, outcome: OutcomeRequeue},
+		{name: "error", err: errors.New("reconcile failed"), outcome: OutcomeError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := ReconcileOutcomes.WithLabelValues("test-"+tt.name, tt.outcome)
+			RecordReconcile("test-"+tt.name, tt.result, tt.err)
+			if got := testutil.ToFloat64(counter); got != 1 {
+				t.Errorf("the %s outcomes counter = %v, want 1", tt.outcome, got)
+			}
+		})
+	}
+}
+`
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devcontainer
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &DevContainer{}
+
+// DevContainer scaffolds the .devcontainer/devcontainer.json file used by VS Code and Codespaces
+type DevContainer struct {
+	file.TemplateMixin
+	file.ProjectNameMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *DevContainer) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(".devcontainer", "devcontainer.json")
+	}
+
+	f.TemplateBody = devContainerTemplate
+
+	return nil
+}
+
+const devContainerTemplate = `{
+  "name": "{{ .ProjectName }}",
+  "build": {
+    "dockerfile": "Dockerfile"
+  },
+  // kind runs the cluster nodes as containers of the Docker daemon running in the dev container
+  "features": {
+    "ghcr.io/devcontainers/features/docker-in-docker:2": {}
+  },
+  "runArgs": ["--network=host"],
+  "customizations": {
+    "vscode": {
+      "extensions": [
+        "golang.go",
+        "ms-kubernetes-tools.vscode-kubernetes-tools"
+      ]
+    }
+  },
+  "postCreateCommand": "go mod download"
+}
+`
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devcontainer
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates"
+)
+
+var _ file.Template = &Dockerfile{}
+
+// Dockerfile scaffolds the image of the dev container, with the tools used to develop the project installed
+type Dockerfile struct {
+	file.TemplateMixin
+
+	// GoVersion is the Go version of the base image
+	GoVersion string
+	// KubectlVersion is the kubectl version to install
+	KubectlVersion string
+	// KindVersion is the kubernetes-sigs/kind version to install
+	KindVersion string
+	// KustomizeVersion is the kubernetes-sigs/kustomize version to install
+	KustomizeVersion string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *Dockerfile) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = filepath.Join(".devcontainer", "Dockerfile")
+	}
+
+	f.TemplateBody = dockerfileTemplate
+
+	if f.GoVersion == "" {
+		f.GoVersion = templates.DefaultGoVersion
+	}
+
+	return nil
+}
+
+//nolint:lll
+const dockerfileTemplate = `FROM golang:{{ .GoVersion }}
+
+ARG KUBECTL_VERSION={{ .KubectlVersion }}
+ARG KIND_VERSION={{ .KindVersion }}
+ARG KUSTOMIZE_VERSION={{ .KustomizeVersion }}
+
+RUN ARCH=$(dpkg --print-architecture) \
+    && curl -sSLfo /usr/local/bin/kubectl https://dl.k8s.io/release/${KUBECTL_VERSION}/bin/linux/${ARCH}/kubectl \
+    && curl -sSLfo /usr/local/bin/kind https://kind.sigs.k8s.io/dl/${KIND_VERSION}/kind-linux-${ARCH} \
+    && curl -sSLf https://github.com/kubernetes-sigs/kustomize/releases/download/kustomize%2F${KUSTOMIZE_VERSION}/kustomize_${KUSTOMIZE_VERSION}_linux_${ARCH}.tar.gz \
+       | tar -xz -C /usr/local/bin kustomize \
+    && chmod +x /usr/local/bin/kubectl /usr/local/bin/kind /usr/local/bin/kustomize
+`
@@ -22,9 +22,26 @@ import (
 
 var _ file.Template = &Dockerfile{}
 
+// DefaultBaseImage is the image the manager binary is packaged in if none is set
+const DefaultBaseImage = "gcr.io/distroless/static:nonroot"
+
 // Dockerfile scaffolds a Dockerfile for building a main
 type Dockerfile struct {
 	file.TemplateMixin
+	file.DirectoriesMixin
+
+	// GoVersion is the Go version of the builder image
+	GoVersion string
+	// BaseImage is the image the manager binary is packaged in
+	BaseImage string
+	// SourceDir is the directory of the project in the build context, with a trailing slash, empty unless the
+	// build context is the root of the Go module or workspace the project is a subdirectory of
+	SourceDir string
+	// Workspace is true if the build context is the root of a go.work workspace the project is a module of,
+	// which is copied whole, as the manager may depend on any of its modules
+	Workspace bool
+	// APIsDir is the directory of the Go module of the APIs, empty if the APIs are in the module of the project
+	APIsDir string
 }
 
 // SetTemplateDefaults implements input.Template
@@ -35,31 +52,64 @@ func (f *Dockerfile) SetTemplateDefaults() error {
 
 	f.TemplateBody = dockerfileTemplate
 
+	if f.GoVersion == "" {
+		f.GoVersion = DefaultGoVersion
+	}
+	if f.BaseImage == "" {
+		f.BaseImage = DefaultBaseImage
+	}
+
 	return nil
 }
 
 const dockerfileTemplate = `# Build the manager binary
-FROM golang:1.13 as builder
+FROM golang:{{ .GoVersion }} as builder
+# Set by docker buildx to the platform the image is built for, e.g. with --platform linux/arm64
+ARG TARGETOS
+ARG TARGETARCH
 
 WORKDIR /workspace
+{{- if .Workspace }}
+# The build context is the root of the go.work workspace{{ with .SourceDir }}, the project being in {{ . }}{{ end }}
+# Copy the workspace, as the manager may depend on any of its modules
+COPY . .
+
+# Build
+RUN {{ with .SourceDir }}cd {{ . }} && {{ end }}\
+	CGO_ENABLED=0 GOOS=${TARGETOS:-linux} GOARCH=${TARGETARCH:-amd64} go build -a -o /workspace/manager main.go
+{{- else }}
+{{- if .SourceDir }}
+# The build context is the root of the Go module, the project being in {{ .SourceDir }}
+{{- end }}
 # Copy the Go Modules manifests
 COPY go.mod go.mod
 COPY go.sum go.sum
+{{- if .APIsDir }}
+# The APIs are in a module of their own, which the module of the project requires
+COPY {{ .APIsDir }}/go.mod {{ .APIsDir }}/go.mod
+{{- end }}
 # cache deps before building and copying source so that we don't need to re-download as much
 # and so that source changes don't invalidate our downloaded layer
 RUN go mod download
 
 # Copy the go source
-COPY main.go main.go
-COPY api/ api/
-COPY controllers/ controllers/
+COPY {{ .SourceDir }}main.go {{ .SourceDir }}main.go
+COPY {{ .SourceDir }}{{ .Directories.APIs }}/ {{ .SourceDir }}{{ .Directories.APIs }}/
+COPY {{ .SourceDir }}{{ .Directories.Controllers }}/ {{ .SourceDir }}{{ .Directories.Controllers }}/
 
 # Build
-RUN CGO_ENABLED=0 GOOS=linux GOARCH=amd64 GO111MODULE=on go build -a -o manager main.go
+RUN CGO_ENABLED=0 GOOS=${TARGETOS:-linux} GOARCH=${TARGETARCH:-amd64} GO111MODULE=on go build -a -o manager {{ .SourceDir }}main.go
+{{- end }}
+
+{{- if eq .BaseImage "gcr.io/distroless/static:nonroot" }}
 
 # Use distroless as minimal base image to package the manager binary
 # Refer to https://github.com/GoogleContainerTools/distroless for more details
-FROM gcr.io/distroless/static:nonroot
+{{- else }}
+
+# Package the manager binary in the base image set on init
+{{- end }}
+FROM {{ .BaseImage }}
 WORKDIR /
 COPY --from=builder /workspace/manager .
 USER 65532:65532
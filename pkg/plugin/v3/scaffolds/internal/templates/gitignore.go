@@ -53,6 +53,7 @@ testbin/*
 
 # Output of the go coverage tool, specifically when used with LiteIDE
 *.out
+cover.html
 
 # Kubernetes Generated files - skip generated files, except for vendored files
 
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &GolangciLint{}
+
+// GolangciLint scaffolds the .golangci.yml file configuring the linters run by the lint target of the Makefile
+type GolangciLint struct {
+	file.TemplateMixin
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *GolangciLint) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = ".golangci.yml"
+	}
+
+	f.TemplateBody = golangciLintTemplate
+
+	// A config tuned by the project is kept
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
+
+const golangciLintTemplate = `run:
+  timeout: 5m
+  allow-parallel-runners: true
+
+issues:
+  # Report missing doc comments, which are reported by golint but excluded by default
+  exclude-use-default: false
+  exclude-rules:
+    # The kubebuilder markers of the API types make for long lines
+    - path: "^apis?/"
+      linters:
+        - lll
+
+linters:
+  disable-all: true
+  enable:
+    - dupl
+    - errcheck
+    - goconst
+    - gocyclo
+    - gofmt
+    - goimports
+    - golint
+    - gosimple
+    - govet
+    - ineffassign
+    - lll
+    - misspell
+    - nakedret
+    - prealloc
+    - staticcheck
+    - typecheck
+    - unconvert
+    - unparam
+    - unused
+`
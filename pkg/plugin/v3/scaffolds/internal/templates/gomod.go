@@ -22,12 +22,19 @@ import (
 
 var _ file.Template = &GoMod{}
 
+// DefaultGoVersion is the Go version targeted by the project if none is set
+const DefaultGoVersion = "1.13"
+
 // GoMod writes a templatefile for go.mod
 type GoMod struct {
 	file.TemplateMixin
 	file.RepositoryMixin
 
+	// GoVersion is the Go version targeted by the project
+	GoVersion                string
 	ControllerRuntimeVersion string
+	// GinkgoVersion is the onsi/ginkgo/v2 version the tests are written with, if not empty
+	GinkgoVersion string
 }
 
 // SetTemplateDefaults implements input.Template
@@ -38,7 +45,12 @@ func (f *GoMod) SetTemplateDefaults() error {
 
 	f.TemplateBody = goModTemplate
 
-	f.IfExistsAction = file.Overwrite
+	// An existing go.mod is kept as its module path was already validated and it may contain dependencies
+	f.IfExistsAction = file.Skip
+
+	if f.GoVersion == "" {
+		f.GoVersion = DefaultGoVersion
+	}
 
 	return nil
 }
@@ -46,9 +58,12 @@ func (f *GoMod) SetTemplateDefaults() error {
 const goModTemplate = `
 module {{ .Repo }}
 
-go 1.13
+go {{ .GoVersion }}
 
 require (
+{{- if .GinkgoVersion }}
+	github.com/onsi/ginkgo/v2 {{ .GinkgoVersion }}
+{{- end }}
 	sigs.k8s.io/controller-runtime {{ .ControllerRuntimeVersion }}
 )
 `
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+)
+
+var _ file.Template = &License{}
+
+// License scaffolds the LICENSE file of the project, kept if it already exists
+type License struct {
+	file.TemplateMixin
+
+	// Text is the template of the text of the license, from the license catalog
+	Text string
+
+	// Owner is the copyright owner - e.g. "The Kubernetes Authors"
+	Owner string
+
+	// Year is the copyright year
+	Year string
+}
+
+// SetTemplateDefaults implements input.Template
+func (f *License) SetTemplateDefaults() error {
+	if f.Path == "" {
+		f.Path = "LICENSE"
+	}
+
+	if f.Year == "" {
+		f.Year = fmt.Sprintf("%v", time.Now().Year())
+	}
+
+	f.TemplateBody = f.Text
+
+	f.IfExistsAction = file.Skip
+
+	return nil
+}
@@ -19,10 +19,14 @@ package templates
 import (
 	"fmt"
 	"hash/fnv"
+	"path"
 	"path/filepath"
+	"strings"
 	"text/template"
 
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
 )
 
 const defaultMainPath = "main.go"
@@ -36,6 +40,34 @@ type Main struct {
 	file.BoilerplateMixin
 	file.DomainMixin
 	file.RepositoryMixin
+	file.ProjectNameMixin
+
+	// WebhookPort, WebhookHost and WebhookCertDir configure the webhook server, the host and certificate directory
+	// of controller-runtime are used if empty
+	WebhookPort    int
+	WebhookHost    string
+	WebhookCertDir string
+
+	// ComponentConfig determines whether the manager options are loaded from a ControllerManagerConfig file
+	ComponentConfig bool
+	// MetricsAuthFilter determines whether the manager serves its metrics over HTTPS to the authenticated and
+	// authorized clients only, instead of relying on a kube-rbac-proxy sidecar
+	MetricsAuthFilter bool
+	// Pprof determines whether the manager can serve the runtime profiles of net/http/pprof, on the address of
+	// its --pprof-addr flag
+	Pprof bool
+	// Tracing determines whether the manager exports the traces of the reconciliations to the OTLP endpoint of
+	// its environment
+	Tracing bool
+	// ZapFlags determines whether the level, encoder and stacktrace level of the logger are bound to the --zap-*
+	// flags of controller-runtime
+	ZapFlags bool
+	// WatchNamespace determines whether the cache of the manager is restricted to the namespaces of its
+	// --watch-namespace flag, defaulting to the WATCH_NAMESPACE environment variable
+	WatchNamespace bool
+	// HighAvailability determines whether the leader election timings let the leader keep its lease through an
+	// unavailability of the API server, the ControllerManagerConfig file setting them with ComponentConfig
+	HighAvailability bool
 }
 
 // SetTemplateDefaults implements file.Template
@@ -44,9 +76,15 @@ func (f *Main) SetTemplateDefaults() error {
 		f.Path = filepath.Join(defaultMainPath)
 	}
 
+	if f.WebhookPort == 0 {
+		f.WebhookPort = config.DefaultWebhookPort
+	}
+
 	f.TemplateBody = fmt.Sprintf(mainTemplate,
 		file.NewMarkerFor(f.Path, importMarker),
 		file.NewMarkerFor(f.Path, addSchemeMarker),
+		file.NewMarkerFor(f.Path, flagsMarker),
+		file.NewMarkerFor(f.Path, flagsMarker),
 		file.NewMarkerFor(f.Path, setupMarker),
 	)
 
@@ -72,10 +110,13 @@ var _ file.Inserter = &MainUpdater{}
 type MainUpdater struct { //nolint:maligned
 	file.RepositoryMixin
 	file.MultiGroupMixin
+	file.DirectoriesMixin
 	file.ResourceMixin
 
 	// Flags to indicate which parts need to be included when updating the file
 	WireResource, WireController, WireWebhook bool
+	// WireControllerOptions binds the options of the controller to the flags of main.go
+	WireControllerOptions bool
 }
 
 // GetPath implements Builder
@@ -91,14 +132,19 @@ func (*MainUpdater) GetIfExistsAction() file.IfExistsAction {
 const (
 	importMarker    = "imports"
 	addSchemeMarker = "scheme"
+	flagsMarker     = "flags"
 	setupMarker     = "builder"
 )
 
+// FlagsMarker is the marker of main.go before which the flags of the options of the controllers are bound
+var FlagsMarker = file.NewMarkerFor(defaultMainPath, flagsMarker)
+
 // GetMarkers implements file.Inserter
 func (f *MainUpdater) GetMarkers() []file.Marker {
 	return []file.Marker{
 		file.NewMarkerFor(defaultMainPath, importMarker),
 		file.NewMarkerFor(defaultMainPath, addSchemeMarker),
+		FlagsMarker,
 		file.NewMarkerFor(defaultMainPath, setupMarker),
 	}
 }
@@ -106,13 +152,13 @@ func (f *MainUpdater) GetMarkers() []file.Marker {
 const (
 	apiImportCodeFragment = `%s "%s"
 `
-	controllerImportCodeFragment = `"%s/controllers"
+	controllerImportCodeFragment = `"%s"
 `
-	multiGroupControllerImportCodeFragment = `%scontrollers "%s/controllers/%s"
+	multiGroupControllerImportCodeFragment = `%s%s "%s"
 `
 	addschemeCodeFragment = `utilruntime.Must(%s.AddToScheme(scheme))
 `
-	reconcilerSetupCodeFragment = `if err = (&controllers.%sReconciler{
+	reconcilerSetupCodeFragment = `if err = (&%s.%sReconciler{
 		Client: mgr.GetClient(),
 		Log: ctrl.Log.WithName("controllers").WithName("%s"),
 		Scheme: mgr.GetScheme(),
@@ -121,7 +167,7 @@ const (
 		os.Exit(1)
 	}
 `
-	multiGroupReconcilerSetupCodeFragment = `if err = (&%scontrollers.%sReconciler{
+	multiGroupReconcilerSetupCodeFragment = `if err = (&%s%s.%sReconciler{
 		Client: mgr.GetClient(),
 		Log: ctrl.Log.WithName("controllers").WithName("%s").WithName("%s"),
 		Scheme: mgr.GetScheme(),
@@ -130,13 +176,75 @@ const (
 		os.Exit(1)
 	}
 `
-	webhookSetupCodeFragment = `if err = (&%s.%s{}).SetupWebhookWithManager(mgr); err != nil {
+	controllerOptionsFlagsCodeFragment = `var %s %s.ControllerOptions
+	%s.BindFlags(flag.CommandLine, "%s")
+`
+	webhookSetupCodeFragment = `if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&%s.%s{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "%s")
+			os.Exit(1)
+		}
+	}
+`
+	legacyWebhookSetupCodeFragment = `if err = (&%s.%s{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "%s")
 		os.Exit(1)
 	}
 `
 )
 
+// MainFragments are the code fragments inserted into main.go for a resource, so that they can also be removed
+// when the resource is deleted
+type MainFragments struct {
+	APIImport        string
+	AddScheme        string
+	ControllerImport string
+	ReconcilerSetup  string
+	// WebhookSetup is skipped if the ENABLE_WEBHOOKS environment variable is false, e.g. to run the manager
+	// locally, unlike the LegacyWebhookSetup of the projects scaffolded before
+	WebhookSetup       string
+	LegacyWebhookSetup string
+
+	// ControllerOptionsFlags and ReconcilerSetupWithOptions bind the options of the controller to flags and set
+	// them on its reconciler, instead of ReconcilerSetup
+	ControllerOptionsFlags     string
+	ReconcilerSetupWithOptions string
+}
+
+// NewMainFragments returns the code fragments of main.go for res, its controller being in the directories dirs
+func NewMainFragments(repo string, multiGroup bool, dirs config.Directories, res *resource.Resource) MainFragments {
+	fragments := MainFragments{
+		APIImport:          fmt.Sprintf(apiImportCodeFragment, res.ImportAlias, res.Package),
+		AddScheme:          fmt.Sprintf(addschemeCodeFragment, res.ImportAlias),
+		WebhookSetup:       fmt.Sprintf(webhookSetupCodeFragment, res.ImportAlias, res.Kind, res.Kind),
+		LegacyWebhookSetup: fmt.Sprintf(legacyWebhookSetupCodeFragment, res.ImportAlias, res.Kind, res.Kind),
+	}
+	if !multiGroup {
+		fragments.ControllerImport = fmt.Sprintf(controllerImportCodeFragment, path.Join(repo, dirs.Controllers))
+		fragments.ReconcilerSetup = fmt.Sprintf(reconcilerSetupCodeFragment,
+			dirs.ControllersPackage, res.Kind, res.Kind, res.Kind)
+	} else {
+		fragments.ControllerImport = fmt.Sprintf(multiGroupControllerImportCodeFragment,
+			res.GroupPackageName, dirs.ControllersPackage, path.Join(repo, res.Replacer().Replace(dirs.Controller)))
+		fragments.ReconcilerSetup = fmt.Sprintf(multiGroupReconcilerSetupCodeFragment,
+			res.GroupPackageName, dirs.ControllersPackage, res.Kind, res.Group, res.Kind, res.Kind)
+	}
+
+	// The flags of a controller are prefixed with its name, along with its group in multi-group projects
+	controllersPackage, optionsVar, flagsPrefix :=
+		dirs.ControllersPackage, strings.ToLower(res.Kind)+"Options", strings.ToLower(res.Kind)
+	if multiGroup {
+		controllersPackage = res.GroupPackageName + dirs.ControllersPackage
+		optionsVar = res.GroupPackageName + res.Kind + "Options"
+		flagsPrefix = strings.ToLower(res.GroupPackageName + "-" + res.Kind)
+	}
+	fragments.ControllerOptionsFlags = fmt.Sprintf(controllerOptionsFlagsCodeFragment,
+		optionsVar, controllersPackage, optionsVar, flagsPrefix)
+	fragments.ReconcilerSetupWithOptions = strings.Replace(fragments.ReconcilerSetup,
+		"Scheme: mgr.GetScheme(),\n", fmt.Sprintf("Scheme: mgr.GetScheme(),\n\t\tOptions: %s,\n", optionsVar), 1)
+	return fragments
+}
+
 // GetCodeFragments implements file.Inserter
 func (f *MainUpdater) GetCodeFragments() file.CodeFragmentsMap {
 	fragments := make(file.CodeFragmentsMap, 3)
@@ -145,37 +253,34 @@ func (f *MainUpdater) GetCodeFragments() file.CodeFragmentsMap {
 	if f.Resource == nil {
 		return fragments
 	}
+	resourceFragments := NewMainFragments(f.Repo, f.MultiGroup, f.Directories, f.Resource)
 
 	// Generate import code fragments
 	imports := make([]string, 0)
-	imports = append(imports, fmt.Sprintf(apiImportCodeFragment, f.Resource.ImportAlias, f.Resource.Package))
+	imports = append(imports, resourceFragments.APIImport)
 	if f.WireController {
-		if !f.MultiGroup {
-			imports = append(imports, fmt.Sprintf(controllerImportCodeFragment, f.Repo))
-		} else {
-			imports = append(imports, fmt.Sprintf(multiGroupControllerImportCodeFragment,
-				f.Resource.GroupPackageName, f.Repo, f.Resource.Group))
-		}
+		imports = append(imports, resourceFragments.ControllerImport)
 	}
 
 	// Generate add scheme code fragments
 	addScheme := make([]string, 0)
-	addScheme = append(addScheme, fmt.Sprintf(addschemeCodeFragment, f.Resource.ImportAlias))
+	addScheme = append(addScheme, resourceFragments.AddScheme)
+
+	// Generate flags code fragments
+	flags := make([]string, 0)
+	if f.WireController && f.WireControllerOptions {
+		flags = append(flags, resourceFragments.ControllerOptionsFlags)
+	}
 
 	// Generate setup code fragments
 	setup := make([]string, 0)
-	if f.WireController {
-		if !f.MultiGroup {
-			setup = append(setup, fmt.Sprintf(reconcilerSetupCodeFragment,
-				f.Resource.Kind, f.Resource.Kind, f.Resource.Kind))
-		} else {
-			setup = append(setup, fmt.Sprintf(multiGroupReconcilerSetupCodeFragment,
-				f.Resource.GroupPackageName, f.Resource.Kind, f.Resource.Group, f.Resource.Kind, f.Resource.Kind))
-		}
+	if f.WireController && f.WireControllerOptions {
+		setup = append(setup, resourceFragments.ReconcilerSetupWithOptions)
+	} else if f.WireController {
+		setup = append(setup, resourceFragments.ReconcilerSetup)
 	}
 	if f.WireWebhook {
-		setup = append(setup, fmt.Sprintf(webhookSetupCodeFragment,
-			f.Resource.ImportAlias, f.Resource.Kind, f.Resource.Kind))
+		setup = append(setup, resourceFragments.WebhookSetup)
 	}
 
 	// Only store code fragments in the map if the slices are non-empty
@@ -185,6 +290,9 @@ func (f *MainUpdater) GetCodeFragments() file.CodeFragmentsMap {
 	if len(addScheme) != 0 {
 		fragments[file.NewMarkerFor(defaultMainPath, addSchemeMarker)] = addScheme
 	}
+	if len(flags) != 0 {
+		fragments[FlagsMarker] = flags
+	}
 	if len(setup) != 0 {
 		fragments[file.NewMarkerFor(defaultMainPath, setupMarker)] = setup
 	}
@@ -197,14 +305,69 @@ var mainTemplate = `{{ .Boilerplate }}
 package main
 
 import (
+{{- if or .MetricsAuthFilter .Pprof .Tracing }}
+	"context"
+{{- end }}
+{{- if .MetricsAuthFilter }}
+	"crypto/tls"
+{{- end }}
 	"flag"
+{{- if .ComponentConfig }}
+	"fmt"
+	"io/ioutil"
+{{- end }}
+{{- if or .MetricsAuthFilter .Pprof }}
+	"net/http"
+{{- end }}
+{{- if .Pprof }}
+	"net/http/pprof"
+{{- end }}
 	"os"
+{{- if or .MetricsAuthFilter .WatchNamespace }}
+	"strings"
+{{- end }}
+{{- if or .MetricsAuthFilter .Pprof .Tracing (and .HighAvailability (not .ComponentConfig)) }}
+	"time"
+{{- end }}
+
+{{- if .MetricsAuthFilter }}
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+{{- end }}
+{{- if .Tracing }}
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+{{- end }}
+{{- if .MetricsAuthFilter }}
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+{{- end }}
+{{- if or .MetricsAuthFilter .ComponentConfig }}
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+{{- end }}
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+{{- if .MetricsAuthFilter }}
+	"k8s.io/client-go/kubernetes"
+{{- end }}
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+{{- if .MetricsAuthFilter }}
+	"k8s.io/client-go/util/cert"
+{{- end }}
 	ctrl "sigs.k8s.io/controller-runtime"
+{{- if .WatchNamespace }}
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+{{- end }}
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+{{- if .MetricsAuthFilter }}
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+{{- end }}
+{{- if .ComponentConfig }}
+	"sigs.k8s.io/yaml"
+{{- end }}
 	%s
 )
 
@@ -220,27 +383,147 @@ func init() {
 }
 
 func main() {
+{{- if .ComponentConfig }}
+	var configFile string
+	flag.StringVar(&configFile, "config", "",
+		"The file the manager loads its ControllerManagerConfig from, the default options are used without it.")
+{{- if .Pprof }}
+	var pprofAddr string
+	flag.StringVar(&pprofAddr, "pprof-addr", ":8082", "The address the pprof endpoint binds to, " +
+		"serving the runtime profiles of the manager, 0 disables it.")
+{{- end }}
+{{- template "zapFlags" . }}
+{{- template "watchNamespaceFlag" . }}
+	%s
+	flag.Parse()
+
+{{ template "setLogger" . }}
+
+	options := ctrl.Options{
+		Scheme:             scheme,
+{{- if .MetricsAuthFilter }}
+		MetricsBindAddress: ":8443",
+{{- else }}
+		MetricsBindAddress: ":8080",
+{{- end }}
+		Port:               {{ .WebhookPort }},
+{{- if .WebhookHost }}
+		Host:               {{ printf "%%q" .WebhookHost }},
+{{- end }}
+{{- if .WebhookCertDir }}
+		CertDir:            {{ printf "%%q" .WebhookCertDir }},
+{{- end }}
+		LeaderElectionID:   "{{ hash .Repo }}.{{ .Domain }}",
+	}
+	if configFile != "" {
+		managerConfig, err := loadControllerManagerConfig(configFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load the manager config", "file", configFile)
+			os.Exit(1)
+		}
+		managerConfig.apply(&options)
+	}
+{{- if .WatchNamespace }}
+	if watchNamespace != "" {
+		options.Namespace, options.NewCache = watchNamespaceOptions(watchNamespace)
+	}
+{{- end }}
+{{- if .MetricsAuthFilter }}
+	// The metrics are served by the secureMetricsServer added below instead
+	metricsAddr := options.MetricsBindAddress
+	options.MetricsBindAddress = "0"
+{{- end }}
+
+{{- template "tracing" . }}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
+{{- else }}
 	var metricsAddr string
 	var enableLeaderElection bool
+{{- if .MetricsAuthFilter }}
+	flag.StringVar(&metricsAddr, "metrics-addr", ":8443", "The address the metric endpoint binds to, " +
+		"served over HTTPS to the authenticated clients allowed to get it only.")
+{{- else }}
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+{{- end }}
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. " +
 		"Enabling this will ensure there is only one active controller manager.")
+{{- if .Pprof }}
+	var pprofAddr string
+	flag.StringVar(&pprofAddr, "pprof-addr", ":8082", "The address the pprof endpoint binds to, " +
+		"serving the runtime profiles of the manager, 0 disables it.")
+{{- end }}
+{{- template "zapFlags" . }}
+{{- template "watchNamespaceFlag" . }}
+	%s
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseDevMode(true))) 
+{{ template "setLogger" . }}
+{{- template "tracing" . }}
+{{- if .HighAvailability }}
+
+	// The leader keeps its lease through an unavailability of the API server of up to the renew deadline, e.g.
+	// during its upgrade, instead of exiting, a standby replica taking over within the lease duration otherwise
+	leaseDuration, renewDeadline, retryPeriod := 137*time.Second, 107*time.Second, 26*time.Second
+{{- end }}
+{{- if .WatchNamespace }}
+
+	namespace, newCache := watchNamespaceOptions(watchNamespace)
+{{- end }}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:             scheme,
+{{- if .MetricsAuthFilter }}
+		// The metrics are served by the secureMetricsServer added below instead
+		MetricsBindAddress: "0",
+{{- else }}
 		MetricsBindAddress: metricsAddr,
-		Port:               9443, 
+{{- end }}
+		Port:               {{ .WebhookPort }},
+{{- if .WebhookHost }}
+		Host:               {{ printf "%%q" .WebhookHost }},
+{{- end }}
+{{- if .WebhookCertDir }}
+		CertDir:            {{ printf "%%q" .WebhookCertDir }},
+{{- end }}
 		LeaderElection:     enableLeaderElection, 
 		LeaderElectionID:   "{{ hash .Repo }}.{{ .Domain }}",
+{{- if .HighAvailability }}
+		LeaseDuration:      &leaseDuration,
+		RenewDeadline:      &renewDeadline,
+		RetryPeriod:        &retryPeriod,
+{{- end }}
+{{- if .WatchNamespace }}
+		Namespace:          namespace,
+		NewCache:           newCache,
+{{- end }}
 	})
+{{- end }}
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
+{{- if .MetricsAuthFilter }}
+	if err = mgr.Add(&secureMetricsServer{
+		addr:   metricsAddr,
+		client: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
+	}); err != nil {
+		setupLog.Error(err, "unable to add the metrics server")
+		os.Exit(1)
+	}
+{{- end }}
+{{- if .Pprof }}
+	// The profiles are served to debug the performance of the manager, e.g. with a port-forward to the address and
+	// 'go tool pprof http://localhost:8082/debug/pprof/heap'. They reveal its internals, so the endpoint is
+	// disabled by the manifests and should not be exposed outside of the cluster.
+	if pprofAddr != "0" {
+		if err = mgr.Add(&pprofServer{addr: pprofAddr}); err != nil {
+			setupLog.Error(err, "unable to add the pprof server")
+			os.Exit(1)
+		}
+	}
+{{- end }}
 
 	%s
 
@@ -250,4 +533,307 @@ func main() {
 		os.Exit(1)
 	}
 }
+{{- if .ComponentConfig }}
+
+// ControllerManagerConfig is the configuration of the manager loaded from the file of the --config flag. It is a
+// subset of the ControllerManagerConfiguration of controller-runtime v0.7+, that the file keeps working with.
+type ControllerManagerConfig struct {
+	metav1.TypeMeta ` + "`" + `json:",inline"` + "`" + `
+
+	// SyncPeriod is the minimum frequency at which the watched resources are reconciled
+	SyncPeriod *metav1.Duration ` + "`" + `json:"syncPeriod,omitempty"` + "`" + `
+	// CacheNamespace restricts the resources watched by the manager to a namespace, all are watched if empty
+	CacheNamespace string ` + "`" + `json:"cacheNamespace,omitempty"` + "`" + `
+
+	LeaderElection *LeaderElectionConfig ` + "`" + `json:"leaderElection,omitempty"` + "`" + `
+	Metrics        MetricsConfig         ` + "`" + `json:"metrics,omitempty"` + "`" + `
+	Webhook        WebhookConfig         ` + "`" + `json:"webhook,omitempty"` + "`" + `
+}
+
+// LeaderElectionConfig configures the leader election of the replicas of the manager
+type LeaderElectionConfig struct {
+	LeaderElect       *bool            ` + "`" + `json:"leaderElect,omitempty"` + "`" + `
+	ResourceName      string           ` + "`" + `json:"resourceName,omitempty"` + "`" + `
+	ResourceNamespace string           ` + "`" + `json:"resourceNamespace,omitempty"` + "`" + `
+	LeaseDuration     *metav1.Duration ` + "`" + `json:"leaseDuration,omitempty"` + "`" + `
+	RenewDeadline     *metav1.Duration ` + "`" + `json:"renewDeadline,omitempty"` + "`" + `
+	RetryPeriod       *metav1.Duration ` + "`" + `json:"retryPeriod,omitempty"` + "`" + `
+}
+
+// MetricsConfig configures the metrics endpoint of the manager
+type MetricsConfig struct {
+	BindAddress string ` + "`" + `json:"bindAddress,omitempty"` + "`" + `
+}
+
+// WebhookConfig configures the webhook server of the manager
+type WebhookConfig struct {
+	Port    *int   ` + "`" + `json:"port,omitempty"` + "`" + `
+	Host    string ` + "`" + `json:"host,omitempty"` + "`" + `
+	CertDir string ` + "`" + `json:"certDir,omitempty"` + "`" + `
+}
+
+// loadControllerManagerConfig reads the ControllerManagerConfig of the file at path, rejecting unknown fields
+func loadControllerManagerConfig(path string) (*ControllerManagerConfig, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &ControllerManagerConfig{}
+	if err := yaml.UnmarshalStrict(content, config); err != nil {
+		return nil, err
+	}
+	if config.APIVersion != "controller-runtime.sigs.k8s.io/v1alpha1" || config.Kind != "ControllerManagerConfig" {
+		return nil, fmt.Errorf("expected a controller-runtime.sigs.k8s.io/v1alpha1 ControllerManagerConfig, " +
+			"found apiVersion %%q and kind %%q", config.APIVersion, config.Kind)
+	}
+	return config, nil
+}
+
+// apply overrides options with the values set in the config
+func (c *ControllerManagerConfig) apply(options *ctrl.Options) {
+	if c.SyncPeriod != nil {
+		options.SyncPeriod = &c.SyncPeriod.Duration
+	}
+	if c.CacheNamespace != "" {
+		options.Namespace = c.CacheNamespace
+	}
+	if le := c.LeaderElection; le != nil {
+		if le.LeaderElect != nil {
+			options.LeaderElection = *le.LeaderElect
+		}
+		if le.ResourceName != "" {
+			options.LeaderElectionID = le.ResourceName
+		}
+		if le.ResourceNamespace != "" {
+			options.LeaderElectionNamespace = le.ResourceNamespace
+		}
+		if le.LeaseDuration != nil {
+			options.LeaseDuration = &le.LeaseDuration.Duration
+		}
+		if le.RenewDeadline != nil {
+			options.RenewDeadline = &le.RenewDeadline.Duration
+		}
+		if le.RetryPeriod != nil {
+			options.RetryPeriod = &le.RetryPeriod.Duration
+		}
+	}
+	if c.Metrics.BindAddress != "" {
+		options.MetricsBindAddress = c.Metrics.BindAddress
+	}
+	if c.Webhook.Port != nil {
+		options.Port = *c.Webhook.Port
+	}
+	if c.Webhook.Host != "" {
+		options.Host = c.Webhook.Host
+	}
+	if c.Webhook.CertDir != "" {
+		options.CertDir = c.Webhook.CertDir
+	}
+}
+{{- end }}
+{{- if .MetricsAuthFilter }}
+
+// secureMetricsServer serves the metrics of the manager over HTTPS, with a self-signed certificate, to the
+// clients authenticated with a TokenReview and authorized with a SubjectAccessReview to get the requested path.
+// It does what the filters.WithAuthenticationAndAuthorization metrics filter does in controller-runtime v0.16+.
+type secureMetricsServer struct {
+	addr   string
+	client kubernetes.Interface
+}
+
+// Start implements manager.Runnable
+func (s *secureMetricsServer) Start(stop <-chan struct{}) error {
+	certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey("localhost", nil, nil)
+	if err != nil {
+		return err
+	}
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:      s.addr,
+		Handler:   s.authorize(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})),
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12},
+	}
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+
+	if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, every replica serves its metrics
+func (s *secureMetricsServer) NeedLeaderElection() bool {
+	return false
+}
+
+// authorize only passes the requests of the clients allowed to get their path to next
+func (s *secureMetricsServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || token == r.Header.Get("Authorization") {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		review, err := s.client.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+			Spec: authenticationv1.TokenReviewSpec{Token: token},
+		}, metav1.CreateOptions{})
+		if err != nil || !review.Status.Authenticated {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		user := review.Status.User
+		extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+		for key, values := range user.Extra {
+			extra[key] = authorizationv1.ExtraValue(values)
+		}
+		access, err := s.client.AuthorizationV1().SubjectAccessReviews().Create(r.Context(),
+			&authorizationv1.SubjectAccessReview{
+				Spec: authorizationv1.SubjectAccessReviewSpec{
+					User:   user.Username,
+					UID:    user.UID,
+					Groups: user.Groups,
+					Extra:  extra,
+					NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+						Path: r.URL.Path,
+						Verb: strings.ToLower(r.Method),
+					},
+				},
+			}, metav1.CreateOptions{})
+		if err != nil || !access.Status.Allowed {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+{{- end }}
+{{- if .Pprof }}
+
+// pprofServer serves the runtime profiles of the manager under /debug/pprof/, as net/http/pprof does on the
+// default mux, e.g. the CPU profile on /debug/pprof/profile and the heap one on /debug/pprof/heap.
+type pprofServer struct {
+	addr string
+}
+
+// Start implements manager.Runnable
+func (s *pprofServer) Start(stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	server := &http.Server{Addr: s.addr, Handler: mux}
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, every replica can be profiled
+func (s *pprofServer) NeedLeaderElection() bool {
+	return false
+}
+{{- end }}
+{{- if .WatchNamespace }}
+
+// watchNamespaceOptions returns the Namespace and NewCache options of a manager only watching the comma-separated
+// namespaces, or all of them if empty. The cache then lists and watches the resources in each namespace, which the
+// RBAC of the manager must grant it access to, as with the DefaultNamespaces of the cache.Options of
+// controller-runtime v0.16+.
+func watchNamespaceOptions(namespaces string) (string, cache.NewCacheFunc) {
+	if !strings.Contains(namespaces, ",") {
+		return namespaces, cache.New
+	}
+	return "", cache.MultiNamespacedCacheBuilder(strings.Split(namespaces, ","))
+}
+{{- end }}
+{{- if .Tracing }}
+
+// setupTracing sets up the global tracer provider the reconcilers start their spans with, exporting them to the
+// OTLP gRPC endpoint, e.g. an OpenTelemetry Collector, at the host:port address endpoint. The spans are dropped
+// if endpoint is empty. The returned function flushes the spans not exported yet.
+func setupTracing(endpoint string) (func(), error) {
+	if endpoint == "" {
+		return func() {}, nil
+	}
+
+	exporter, err := otlp.NewExporter(otlp.WithInsecure(), otlp.WithAddress(endpoint))
+	if err != nil {
+		return nil, err
+	}
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "{{ .ProjectName }}"
+	}
+	processor := sdktrace.NewBatchSpanProcessor(exporter)
+	global.SetTracerProvider(sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(resource.New(semconv.ServiceNameKey.String(serviceName))),
+	))
+
+	return func() {
+		processor.Shutdown()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = exporter.Shutdown(ctx)
+	}, nil
+}
+{{- end }}
+{{- define "tracing" }}
+{{- if .Tracing }}
+
+	// The traces of the reconciliations are exported to the endpoint in OTEL_EXPORTER_OTLP_ENDPOINT, if set
+	shutdownTracing, err := setupTracing(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer shutdownTracing()
+{{- end }}
+{{- end }}
+{{- define "zapFlags" }}
+{{- if .ZapFlags }}
+	// The logger is set up with the --zap-log-level, --zap-encoder, --zap-stacktrace-level and --zap-devel flags,
+	// the development defaults logging human-readable debug messages until they are set
+	opts := zap.Options{
+		Development: true,
+	}
+	opts.BindFlags(flag.CommandLine)
+{{- end }}
+{{- end }}
+{{- define "watchNamespaceFlag" }}
+{{- if .WatchNamespace }}
+	var watchNamespace string
+	flag.StringVar(&watchNamespace, "watch-namespace", os.Getenv("WATCH_NAMESPACE"),
+		"The comma-separated namespaces the manager watches the resources of, all of them if empty. " +
+		"Defaults to the WATCH_NAMESPACE environment variable.")
+{{- end }}
+{{- end }}
+{{- define "setLogger" }}
+{{- if .ZapFlags }}
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+{{- else }}
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+{{- end }}
+{{- end }}
 `
@@ -25,17 +25,35 @@ var _ file.Template = &Makefile{}
 // Makefile scaffolds the Makefile
 type Makefile struct {
 	file.TemplateMixin
+	file.DirectoriesMixin
 
 	// Image is controller manager image name
 	Image string
+	// ImageName is the name of the image of the manager manifest, which the deploy target replaces with Image
+	ImageName string
+	// GoVersion is the minimum Go version required to build the project
+	GoVersion string
 	// BoilerplatePath is the path to the boilerplate file
 	BoilerplatePath string
-	// Controller tools version to use in the project
-	ControllerToolsVersion string
-	// Kustomize version to use in the project
-	KustomizeVersion string
-	// ControllerRuntimeEnvTestVersion version to be used to download the envtest setup script
-	ControllerRuntimeEnvTestVersion string
+	// EnvtestK8sVersion is the Kubernetes version of the binaries envtest runs the tests against
+	EnvtestK8sVersion string
+	// QualityTargets is true if the lint and test-coverage targets are scaffolded
+	QualityTargets bool
+	// GinkgoV2 is true if the tests are run by the ginkgo CLI, as they are written with Ginkgo v2
+	GinkgoV2 bool
+	// APIDocs is true if the api-docs target generating the reference documentation of the APIs is scaffolded
+	APIDocs bool
+	// TypedClients is true if the clients target generating the typed clientsets, listers and informers of the
+	// APIs is scaffolded, and run by the generate target
+	TypedClients bool
+	// BuildContext is the path of the build context of the image relative to the project, the root of the Go
+	// module or workspace the project is a subdirectory of, empty if it is the project itself
+	BuildContext string
+	// Workspace is true if the project is a module of a go.work workspace, which the tools are built outside of
+	Workspace bool
+	// APIsDir is the directory of the Go module of the APIs, their code and CRDs being generated from it, empty if
+	// the APIs are in the module of the project
+	APIsDir string
 }
 
 // SetTemplateDefaults implements input.Template
@@ -52,6 +70,14 @@ func (f *Makefile) SetTemplateDefaults() error {
 		f.Image = "controller:latest"
 	}
 
+	if f.ImageName == "" {
+		f.ImageName = "controller"
+	}
+
+	if f.GoVersion == "" {
+		f.GoVersion = DefaultGoVersion
+	}
+
 	return nil
 }
 
@@ -61,29 +87,57 @@ const makefileTemplate = `
 IMG ?= {{ .Image }}
 # Produce CRDs that work back to Kubernetes 1.11 (no version conversion)
 CRD_OPTIONS ?= "crd:trivialVersions=true"
+# Minimum Go version required to build the project
+GO_MIN_VERSION = {{ .GoVersion }}
 
-# Get the currently used golang install path (in GOPATH/bin, unless GOBIN is set)
-ifeq (,$(shell go env GOBIN))
-GOBIN=$(shell go env GOPATH)/bin
-else
-GOBIN=$(shell go env GOBIN)
-endif
+# Directory the tools pinned in hack/tools/go.mod are built into
+LOCALBIN = $(shell pwd)/bin
 
 all: manager
 
+{{ if .GinkgoV2 -}}
+# Ginkgo label filter selecting the specs run by 'make test', e.g. GINKGO_LABEL_FILTER=controller
+GINKGO_LABEL_FILTER ?=
+
+# Run tests
+test: go-version-check generate fmt vet manifests envtest ginkgo
+	KUBEBUILDER_ASSETS=$(ENVTEST_ASSETS) $(GINKGO) -r --cover --coverprofile=cover.out --label-filter="$(GINKGO_LABEL_FILTER)"
+{{- else -}}
 # Run tests
-ENVTEST_ASSETS_DIR=$(shell pwd)/testbin
-test: generate fmt vet manifests
-	mkdir -p ${ENVTEST_ASSETS_DIR}
-	test -f ${ENVTEST_ASSETS_DIR}/setup-envtest.sh || curl -sSLo ${ENVTEST_ASSETS_DIR}/setup-envtest.sh https://raw.githubusercontent.com/kubernetes-sigs/controller-runtime/{{ .ControllerRuntimeEnvTestVersion }}/hack/setup-envtest.sh
-	source ${ENVTEST_ASSETS_DIR}/setup-envtest.sh; fetch_envtest_tools $(ENVTEST_ASSETS_DIR); setup_envtest_env $(ENVTEST_ASSETS_DIR); go test ./... -coverprofile cover.out
+test: go-version-check generate fmt vet manifests envtest
+	KUBEBUILDER_ASSETS=$(ENVTEST_ASSETS) go test ./... -coverprofile cover.out
+{{- end }}
+{{- if .QualityTargets }}
+
+# Run tests and report their coverage, excluding generated code, in cover.html
+test-coverage: test
+	grep -v zz_generated cover.out > cover.filtered.out
+	go tool cover -func cover.filtered.out | tail -n 1
+	go tool cover -html cover.filtered.out -o cover.html
+
+# Run golangci-lint with the linters configured in .golangci.yml
+lint: golangci-lint
+	$(GOLANGCI_LINT) run
+{{- end }}
+
+# Kubernetes version of the etcd, kube-apiserver and kubectl binaries the tests run against
+ENVTEST_K8S_VERSION ?= {{ .EnvtestK8sVersion }}
+ENVTEST_PLATFORM = $(shell go env GOOS)-$(shell go env GOARCH)
+ENVTEST_ASSETS_DIR = $(shell pwd)/testbin
+ENVTEST_ASSETS = $(ENVTEST_ASSETS_DIR)/$(ENVTEST_K8S_VERSION)-$(ENVTEST_PLATFORM)
+# Download the binaries used by envtest, the directory they are in is exported as KUBEBUILDER_ASSETS by 'make test'
+envtest: $(ENVTEST_ASSETS)
+$(ENVTEST_ASSETS):
+	mkdir -p $(ENVTEST_ASSETS_DIR)
+	curl -sSLf https://storage.googleapis.com/kubebuilder-tools/kubebuilder-tools-$(ENVTEST_K8S_VERSION)-$(ENVTEST_PLATFORM).tar.gz | tar -xz -C $(ENVTEST_ASSETS_DIR)
+	mv $(ENVTEST_ASSETS_DIR)/kubebuilder/bin $@ && rm -rf $(ENVTEST_ASSETS_DIR)/kubebuilder
 
 # Build manager binary
-manager: generate fmt vet
+manager: go-version-check generate fmt vet
 	go build -o bin/manager main.go
 
 # Run against the configured Kubernetes cluster in ~/.kube/config
-run: generate fmt vet manifests
+run: go-version-check generate fmt vet manifests
 	go run ./main.go
 
 # Install CRDs into a cluster
@@ -96,7 +150,7 @@ uninstall: manifests kustomize
 
 # Deploy controller in the configured Kubernetes cluster in ~/.kube/config
 deploy: manifests kustomize
-	cd config/manager && $(KUSTOMIZE) edit set image controller=${IMG}
+	cd config/manager && $(KUSTOMIZE) edit set image {{ .ImageName }}=${IMG}
 	$(KUSTOMIZE) build config/default | kubectl apply -f -
 
 # UnDeploy controller from the configured Kubernetes cluster in ~/.kube/config
@@ -105,57 +159,146 @@ undeploy:
 
 # Generate manifests e.g. CRD, RBAC etc.
 manifests: controller-gen
+{{- if .APIsDir }}
+	$(CONTROLLER_GEN) rbac:roleName=manager-role webhook paths="./..."
+	cd {{ .APIsDir }} && $(CONTROLLER_GEN) $(CRD_OPTIONS) paths="./..." output:crd:artifacts:config=../config/crd/bases
+{{- else }}
 	$(CONTROLLER_GEN) $(CRD_OPTIONS) rbac:roleName=manager-role webhook paths="./..." output:crd:artifacts:config=config/crd/bases
+{{- end }}
 
 # Run go fmt against code
 fmt:
 	go fmt ./...
+{{- if .APIsDir }}
+	cd {{ .APIsDir }} && go fmt ./...
+{{- end }}
 
 # Run go vet against code
 vet:
 	go vet ./...
+{{- if .APIsDir }}
+	cd {{ .APIsDir }} && go vet ./...
+{{- end }}
 
 # Generate code
-generate: controller-gen
+generate: controller-gen{{ if .TypedClients }} clients{{ end }}
 	$(CONTROLLER_GEN) object:headerFile={{printf "%q" .BoilerplatePath}} paths="./..."
+{{- if .APIsDir }}
+	cd {{ .APIsDir }} && $(CONTROLLER_GEN) object:headerFile={{ printf "../%s" .BoilerplatePath | printf "%q" }} paths="./..."
+{{- end }}
+{{- if .TypedClients }}
+
+# Generate the typed clientset, listers and informers of the APIs into pkg/client
+clients: client-gen lister-gen informer-gen
+	hack/update-codegen.sh
+{{- end }}
+
+{{- if .APIDocs }}
+
+# Directory of the Go types of the APIs
+{{- if eq .Directories.APIs "api" }}, apis/ for multi-group projects
+API_DIR = $(if $(wildcard apis),apis,api)
+{{- else }}
+API_DIR = {{ .Directories.APIs }}
+{{- end }}
+# Reference documentation of the APIs, generated from their Go types, either markdown or asciidoctor
+API_DOCS_RENDERER ?= markdown
+API_DOCS ?= docs/api-reference.$(if $(filter markdown,$(API_DOCS_RENDERER)),md,asciidoc)
+
+# Generate the reference documentation of the APIs, it is only generated again when their Go types change
+api-docs: $(API_DOCS)
+$(API_DOCS): hack/api-docs/config.yaml $(shell find $(API_DIR) -name '*.go' 2>/dev/null) | crd-ref-docs
+	mkdir -p $(dir $@)
+	$(CRD_REF_DOCS) --source-path=./$(API_DIR) --config=hack/api-docs/config.yaml --renderer=$(API_DOCS_RENDERER) --output-path=$@
+{{- end }}
 
 # Build the docker image
 docker-build: test
+{{- if .BuildContext }}
+	docker build -f Dockerfile -t ${IMG} {{ .BuildContext }}
+{{- else }}
 	docker build . -t ${IMG}
+{{- end }}
 
 # Push the docker image
 docker-push:
 	docker push ${IMG}
 
-# find or download controller-gen
-# download controller-gen if necessary
-controller-gen:
-ifeq (, $(shell which controller-gen))
+# Check that the installed Go version is at least GO_MIN_VERSION
+go-version-check:
 	@{ \
 	set -e ;\
-	CONTROLLER_GEN_TMP_DIR=$$(mktemp -d) ;\
-	cd $$CONTROLLER_GEN_TMP_DIR ;\
-	go mod init tmp ;\
-	go get sigs.k8s.io/controller-tools/cmd/controller-gen@{{ .ControllerToolsVersion }} ;\
-	rm -rf $$CONTROLLER_GEN_TMP_DIR ;\
+	GO_VERSION=$$(go version | sed -E 's/.*go([0-9]+\.[0-9]+).*/\1/') ;\
+	if [ "$$(printf '%s\n' "$(GO_MIN_VERSION)" "$$GO_VERSION" | sort -V | head -n1)" != "$(GO_MIN_VERSION)" ]; then \
+		echo "Go $(GO_MIN_VERSION) or newer is required to build this project, found $$GO_VERSION" ;\
+		exit 1 ;\
+	fi ;\
 	}
-CONTROLLER_GEN=$(GOBIN)/controller-gen
-else
-CONTROLLER_GEN=$(shell which controller-gen)
-endif
 
-kustomize:
-ifeq (, $(shell which kustomize))
-	@{ \
-	set -e ;\
-	KUSTOMIZE_GEN_TMP_DIR=$$(mktemp -d) ;\
-	cd $$KUSTOMIZE_GEN_TMP_DIR ;\
-	go mod init tmp ;\
-	go get sigs.k8s.io/kustomize/kustomize/v3@{{ .KustomizeVersion }} ;\
-	rm -rf $$KUSTOMIZE_GEN_TMP_DIR ;\
-	}
-KUSTOMIZE=$(GOBIN)/kustomize
-else
-KUSTOMIZE=$(shell which kustomize)
-endif
+# Build the tools pinned in hack/tools/go.mod into bin/, their sources are verified against hack/tools/go.sum
+tools: controller-gen kustomize{{ if .QualityTargets }} golangci-lint{{ end }}{{ if .GinkgoV2 }} ginkgo{{ end }}{{ if .APIDocs }} crd-ref-docs{{ end }}{{ if .TypedClients }} client-gen lister-gen informer-gen{{ end }}
+
+CONTROLLER_GEN = $(LOCALBIN)/controller-gen
+controller-gen: $(CONTROLLER_GEN)
+$(CONTROLLER_GEN): hack/tools/go.mod
+	$(call go-build-tool,$(CONTROLLER_GEN),sigs.k8s.io/controller-tools/cmd/controller-gen)
+
+KUSTOMIZE = $(LOCALBIN)/kustomize
+kustomize: $(KUSTOMIZE)
+$(KUSTOMIZE): hack/tools/go.mod
+	$(call go-build-tool,$(KUSTOMIZE),sigs.k8s.io/kustomize/kustomize/v3)
+{{- if .QualityTargets }}
+
+GOLANGCI_LINT = $(LOCALBIN)/golangci-lint
+golangci-lint: $(GOLANGCI_LINT)
+$(GOLANGCI_LINT): hack/tools/go.mod
+	$(call go-build-tool,$(GOLANGCI_LINT),github.com/golangci/golangci-lint/cmd/golangci-lint)
+{{- end }}
+{{- if .GinkgoV2 }}
+
+GINKGO = $(LOCALBIN)/ginkgo
+ginkgo: $(GINKGO)
+$(GINKGO): hack/tools/go.mod
+	$(call go-build-tool,$(GINKGO),github.com/onsi/ginkgo/v2/ginkgo)
+{{- end }}
+{{- if .APIDocs }}
+
+CRD_REF_DOCS = $(LOCALBIN)/crd-ref-docs
+crd-ref-docs: $(CRD_REF_DOCS)
+$(CRD_REF_DOCS): hack/tools/go.mod
+	$(call go-build-tool,$(CRD_REF_DOCS),github.com/elastic/crd-ref-docs)
+{{- end }}
+{{- if .TypedClients }}
+
+CLIENT_GEN = $(LOCALBIN)/client-gen
+client-gen: $(CLIENT_GEN)
+$(CLIENT_GEN): hack/tools/go.mod
+	$(call go-build-tool,$(CLIENT_GEN),k8s.io/code-generator/cmd/client-gen)
+
+LISTER_GEN = $(LOCALBIN)/lister-gen
+lister-gen: $(LISTER_GEN)
+$(LISTER_GEN): hack/tools/go.mod
+	$(call go-build-tool,$(LISTER_GEN),k8s.io/code-generator/cmd/lister-gen)
+
+INFORMER_GEN = $(LOCALBIN)/informer-gen
+informer-gen: $(INFORMER_GEN)
+$(INFORMER_GEN): hack/tools/go.mod
+	$(call go-build-tool,$(INFORMER_GEN),k8s.io/code-generator/cmd/informer-gen)
+{{- end }}
+
+# go-build-tool builds the package $(2) into $(1), at the version required by hack/tools/go.mod
+{{- if .Workspace }}
+# hack/tools is not a module of the go.work workspace, so it is built with the workspace mode off
+{{- end }}
+define go-build-tool
+@{ \
+set -e ;\
+cd hack/tools ;\
+{{- if .Workspace }}
+export GOWORK=off ;\
+{{- end }}
+go mod tidy ;\
+go build -o $(1) $(2) ;\
+}
+endef
 `
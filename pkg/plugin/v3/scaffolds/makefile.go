@@ -0,0 +1,315 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+)
+
+// ControllerToolsWebhookV1Version is the first kubernetes-sigs/controller-tools version able to generate v1 webhook
+// configurations, it is installed by the Makefile of projects with v1 webhooks
+const ControllerToolsWebhookV1Version = "v0.4.1"
+
+const (
+	// ControllerToolsCELVersion is the first kubernetes-sigs/controller-tools version able to generate the CEL
+	// validation rules of CRDs, it is built by the Makefile of projects with validation markers
+	ControllerToolsCELVersion = "v0.9.0"
+	// EnvtestK8sCELVersion is the first Kubernetes version enforcing the CEL validation rules of CRDs by default,
+	// the tests of projects with validation markers run against it
+	EnvtestK8sCELVersion = "1.25.0"
+)
+
+// CodeGeneratorApplyConfigurationsVersion is the first kubernetes/code-generator version shipping
+// applyconfiguration-gen, it is built by the Makefile of projects with server-side applied resources
+const CodeGeneratorApplyConfigurationsVersion = "v0.22.0"
+
+const (
+	defaultCRDOptions = `CRD_OPTIONS ?= "crd:trivialVersions=true"`
+	// v1beta1CRDOptions pins the CRD version, as newer controller-gen releases default to v1 CRDs
+	v1beta1CRDOptions = `CRD_OPTIONS ?= "crd:crdVersions=v1beta1,trivialVersions=true"`
+	v1CRDOptions      = `# Produce v1 CRDs, which require Kubernetes 1.16 or newer
+CRD_OPTIONS ?= "crd:crdVersions=v1"`
+)
+
+var (
+	// crdOptionsRe matches the CRD_OPTIONS variable of a Makefile, along with its default comment
+	crdOptionsRe = regexp.MustCompile(`(?m)^(# Produce CRDs that work back to Kubernetes 1\.11 \(no version conversion\)\n)?` +
+		`CRD_OPTIONS \?= .*$`)
+	// defaultCRDOptionsRe matches the CRD_OPTIONS variable of a Makefile if it was not modified
+	defaultCRDOptionsRe = regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(defaultCRDOptions) + `$`)
+	// controllerGenVersionRe matches the controller-gen version installed by the Makefile of older projects
+	controllerGenVersionRe = regexp.MustCompile(`sigs\.k8s\.io/controller-tools/cmd/controller-gen@` +
+		regexp.QuoteMeta(ControllerToolsVersion) + `\b`)
+	// controllerToolsRequireRe matches the controller-tools version required by the tools go.mod
+	controllerToolsRequireRe = regexp.MustCompile(`(?m)^(\s*sigs\.k8s\.io/controller-tools )` +
+		regexp.QuoteMeta(ControllerToolsVersion) + `$`)
+	// anyControllerToolsRequireRe matches the controller-tools version required by the tools go.mod, whichever it is
+	anyControllerToolsRequireRe = regexp.MustCompile(`(?m)^(\s*sigs\.k8s\.io/controller-tools )(v\S+)$`)
+	// envtestK8sVersionRe matches the Kubernetes version of the binaries envtest runs the tests against
+	envtestK8sVersionRe = regexp.MustCompile(`(?m)^(ENVTEST_K8S_VERSION \?= )(\S+)$`)
+	// codeGeneratorRequireRe matches the code-generator version required by the tools go.mod, whichever it is
+	codeGeneratorRequireRe = regexp.MustCompile(`(?m)^(\s*k8s\.io/code-generator )(v\S+)$`)
+	// controllerToolsLineRe matches the line of the tools go.mod requiring controller-tools, or of the tools.go
+	// importing controller-gen, before which the code-generator ones are added
+	controllerToolsLineRe = regexp.MustCompile(`(?m)^[ \t]*(_ ")?sigs\.k8s\.io/controller-tools[ /]`)
+	// generateTargetRe and toolsTargetRe match the rules of the generate and tools targets of the Makefile
+	generateTargetRe = regexp.MustCompile(`(?m)^generate:.*$`)
+	toolsTargetRe    = regexp.MustCompile(`(?m)^tools:.*$`)
+)
+
+// applyConfigurationsTargets are the Makefile targets generating the apply configurations of the APIs
+const applyConfigurationsTargets = `# Generate the apply configurations of the APIs into pkg/applyconfiguration, for server-side apply
+apply-configurations: applyconfiguration-gen
+	hack/update-applyconfigurations.sh
+
+APPLYCONFIGURATION_GEN = $(LOCALBIN)/applyconfiguration-gen
+applyconfiguration-gen: $(APPLYCONFIGURATION_GEN)
+$(APPLYCONFIGURATION_GEN): hack/tools/go.mod
+	$(call go-build-tool,$(APPLYCONFIGURATION_GEN),k8s.io/code-generator/cmd/applyconfiguration-gen)
+
+`
+
+// updateMakefile adapts the controller-gen options of the Makefile, and the controller-gen version it builds, to
+// the manifest API versions of res
+func updateMakefile(path string, res *resource.Resource) error {
+	bs, err := ioutil.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	updated := bs
+	if res.CRDVersion == "v1" {
+		updated = crdOptionsRe.ReplaceAllLiteral(updated, []byte(v1CRDOptions))
+	}
+	if res.WebhookVersion == "v1" {
+		toolsPath := filepath.Join(filepath.Dir(path), "hack", "tools", "go.mod")
+		bumped, err := bumpControllerTools(toolsPath)
+		if err != nil {
+			return err
+		}
+		if bumped {
+			logger.Default().Info(fmt.Sprintf("%s now requires controller-tools %s, which is required by v1 "+
+				"webhooks, run 'make controller-gen' to rebuild it", toolsPath, ControllerToolsWebhookV1Version))
+		} else if controllerGenVersionRe.Match(updated) {
+			// Projects scaffolded before hack/tools was introduced install controller-gen from the Makefile
+			updated = controllerGenVersionRe.ReplaceAllLiteral(updated,
+				[]byte("sigs.k8s.io/controller-tools/cmd/controller-gen@"+ControllerToolsWebhookV1Version))
+			bumped = true
+			logger.Default().Info(fmt.Sprintf("%s now installs controller-gen %s, which is required by v1 webhooks, "+
+				"make sure that no older controller-gen binary is found in your PATH", path,
+				ControllerToolsWebhookV1Version))
+		}
+		if bumped {
+			updated = defaultCRDOptionsRe.ReplaceAllLiteral(updated, []byte(v1beta1CRDOptions))
+		}
+	}
+
+	if string(updated) == string(bs) {
+		return nil
+	}
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(path, updated, 0644)
+}
+
+// bumpControllerTools requires ControllerToolsWebhookV1Version in the tools go.mod at path if it requires the
+// default controller-tools version, returning whether it did
+func bumpControllerTools(path string) (bool, error) {
+	bs, err := ioutil.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if !controllerToolsRequireRe.Match(bs) {
+		return false, nil
+	}
+	updated := controllerToolsRequireRe.ReplaceAll(bs, []byte("${1}"+ControllerToolsWebhookV1Version))
+	// false positive
+	// nolint:gosec
+	return true, ioutil.WriteFile(path, updated, 0644)
+}
+
+// requireCELValidation makes the Makefile at path build a controller-gen able to generate the CEL validation rules
+// of CRDs, and run the tests against a kube-apiserver enforcing them, unless their versions are already newer
+func requireCELValidation(path string) error {
+	toolsPath := filepath.Join(filepath.Dir(path), "hack", "tools", "go.mod")
+	bumped, err := bumpVersion(toolsPath, anyControllerToolsRequireRe, ControllerToolsCELVersion)
+	if err != nil {
+		return err
+	}
+	if bumped {
+		logger.Default().Info(fmt.Sprintf("%s now requires controller-tools %s, which is required by CEL "+
+			"validation rules and built with Go 1.17 or newer, run 'make controller-gen' to rebuild it",
+			toolsPath, ControllerToolsCELVersion))
+	} else if _, err := os.Stat(toolsPath); os.IsNotExist(err) {
+		logger.Default().Info(fmt.Sprintf("CEL validation rules are only generated by controller-gen %s or newer, "+
+			"make sure that the Makefile installs one", ControllerToolsCELVersion))
+	}
+
+	bumped, err = bumpVersion(path, envtestK8sVersionRe, EnvtestK8sCELVersion)
+	if err != nil {
+		return err
+	}
+	if bumped {
+		logger.Default().Info(fmt.Sprintf("%s now runs the tests against Kubernetes %s, which enforces CEL "+
+			"validation rules", path, EnvtestK8sCELVersion))
+	}
+	return nil
+}
+
+// bumpVersion replaces the version matched by the second group of re in the file at path with version if it is
+// older, returning whether it did
+func bumpVersion(path string, re *regexp.Regexp, version string) (bool, error) {
+	bs, err := ioutil.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	match := re.FindSubmatch(bs)
+	if match == nil || compareVersions(string(match[2]), version) >= 0 {
+		return false, nil
+	}
+	updated := re.ReplaceAll(bs, []byte("${1}"+version))
+	// false positive
+	// nolint:gosec
+	return true, ioutil.WriteFile(path, updated, 0644)
+}
+
+// compareVersions compares two "major.minor.patch" versions, optionally prefixed by "v", returning a negative
+// number if a is lower than b, zero if they are equal and a positive number otherwise
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < 3; i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+// requireApplyConfigurations makes the Makefile at path generate the apply configurations of the APIs with an
+// applyconfiguration-gen built from the tools go.mod, unless it already does
+func requireApplyConfigurations(path string) error {
+	toolsDir := filepath.Join(filepath.Dir(path), "hack", "tools")
+	if _, err := os.Stat(filepath.Join(toolsDir, "go.mod")); os.IsNotExist(err) {
+		// Projects scaffolded before hack/tools was introduced do not build their tools from a go.mod
+		logger.Default().Info(fmt.Sprintf("%s does not build its tools from hack/tools/go.mod, generate the apply "+
+			"configurations with hack/update-applyconfigurations.sh and an applyconfiguration-gen %s or newer "+
+			"built into bin/", path, CodeGeneratorApplyConfigurationsVersion))
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	bs, err := ioutil.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if strings.Contains(string(bs), "\napply-configurations:") {
+		return nil
+	}
+
+	updated := appendToRule(bs, generateTargetRe, "apply-configurations")
+	updated = appendToRule(updated, toolsTargetRe, "applyconfiguration-gen")
+	// The targets are added before the go-build-tool function building the tools, or at the end of the Makefile
+	if i := strings.Index(string(updated), "# go-build-tool builds"); i != -1 {
+		updated = []byte(string(updated[:i]) + applyConfigurationsTargets + string(updated[i:]))
+	} else {
+		updated = append(append(updated, '\n'), strings.TrimSuffix(applyConfigurationsTargets, "\n")...)
+	}
+	// false positive
+	// nolint:gosec
+	if err := ioutil.WriteFile(path, updated, 0644); err != nil {
+		return err
+	}
+
+	if err := requireTool(filepath.Join(toolsDir, "go.mod"),
+		"\tk8s.io/code-generator "+CodeGeneratorApplyConfigurationsVersion+"\n"); err != nil {
+		return err
+	}
+	if err := requireTool(filepath.Join(toolsDir, "tools.go"),
+		"\t_ \"k8s.io/code-generator/cmd/applyconfiguration-gen\"\n"); err != nil {
+		return err
+	}
+	bumped, err := bumpVersion(filepath.Join(toolsDir, "go.mod"), codeGeneratorRequireRe,
+		CodeGeneratorApplyConfigurationsVersion)
+	if err != nil {
+		return err
+	}
+	if bumped {
+		logger.Default().Info(fmt.Sprintf("%s now requires code-generator %s, which ships applyconfiguration-gen, "+
+			"run 'make tools' to rebuild the code generators", filepath.Join(toolsDir, "go.mod"),
+			CodeGeneratorApplyConfigurationsVersion))
+	}
+	logger.Default().Info(fmt.Sprintf("%s now generates the apply configurations of the APIs with 'make "+
+		"apply-configurations', they require k8s.io/client-go %s or newer", path,
+		CodeGeneratorApplyConfigurationsVersion))
+	return nil
+}
+
+// appendToRule appends prerequisite to the rule matched by re, unless it is not found
+func appendToRule(bs []byte, re *regexp.Regexp, prerequisite string) []byte {
+	return re.ReplaceAllFunc(bs, func(rule []byte) []byte {
+		return []byte(string(rule) + " " + prerequisite)
+	})
+}
+
+// requireTool adds line to the file at path, before the line of controller-tools, unless the file already mentions
+// the package of line or has no line of controller-tools
+func requireTool(path, line string) error {
+	bs, err := ioutil.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	pkg := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "_ "))[0]
+	loc := controllerToolsLineRe.FindIndex(bs)
+	if strings.Contains(string(bs), strings.Trim(pkg, `"`)) || loc == nil {
+		return nil
+	}
+	updated := string(bs[:loc[0]]) + line + string(bs[loc[0]:])
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(path, []byte(updated), 0644)
+}
@@ -17,12 +17,21 @@ limitations under the License.
 package scaffolds
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"sigs.k8s.io/kubebuilder/pkg/logger"
+	"sigs.k8s.io/kubebuilder/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
 	"sigs.k8s.io/kubebuilder/pkg/model/resource"
-	"sigs.k8s.io/kubebuilder/pkg/plugin/internal/machinery"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/scaffold"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates"
 	"sigs.k8s.io/kubebuilder/pkg/plugin/v3/scaffolds/internal/templates/config/api"
@@ -60,7 +69,7 @@ func NewWebhookScaffolder(
 
 // Scaffold implements Scaffolder
 func (s *webhookScaffolder) Scaffold() error {
-	fmt.Println("Writing scaffold for you to edit...")
+	logger.Default().Info("Writing scaffold for you to edit...")
 	return s.scaffold()
 }
 
@@ -73,18 +82,123 @@ func (s *webhookScaffolder) newUniverse() *model.Universe {
 }
 
 func (s *webhookScaffolder) scaffold() error {
-	if s.conversion {
-		fmt.Println(`Webhook server has been set up for you.
+	// The webhooks of a resource are scaffolded additively, the ones it already has being skipped
+	webhookPath := s.resource.Replacer().Replace(filepath.Join(s.config.GetDirectories().Types, "%[kind]_webhook.go"))
+	existing, err := ioutil.ReadFile(webhookPath) //nolint:gosec
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// The tests of the added webhooks are appended to the existing ones
+	testPath := s.resource.Replacer().Replace(filepath.Join(s.config.GetDirectories().Types, "%[kind]_webhook_test.go"))
+	existingTest, err := ioutil.ReadFile(testPath) //nolint:gosec
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	defaulting, validation, conversion := s.defaulting, s.validation, s.conversion
+	if existing != nil {
+		tracked := s.trackedGVK()
+		if defaulting && strings.Contains(string(existing), "webhook.Defaulter = &"+s.resource.Kind+"{}") {
+			logger.Default().Info(fmt.Sprintf("%s already implements the defaulting webhook, skipping it", webhookPath))
+			defaulting = false
+		}
+		if validation && strings.Contains(string(existing), "webhook.Validator = &"+s.resource.Kind+"{}") {
+			logger.Default().Info(fmt.Sprintf("%s already implements the validating webhook, skipping it", webhookPath))
+			validation = false
+		}
+		if conversion && tracked.ConversionWebhook {
+			logger.Default().Info("The resource already has a conversion webhook, skipping it")
+			conversion = false
+		}
+		if !defaulting && !validation && !conversion {
+			return fmt.Errorf("the resource already has the requested webhooks, implemented in %s", webhookPath)
+		}
+	}
+
+	if conversion {
+		logger.Default().Info(`Webhook server has been set up for you.
 You need to implement the conversion.Hub and conversion.Convertible interfaces for your CRD types.`)
 	}
 
-	if err := machinery.NewScaffold().Execute(
-		s.newUniverse(),
-		&api.Webhook{Defaulting: s.defaulting, Validating: s.validation},
+	// Record the webhook version and the webhooks of the resources owned by the project
+	if s.config.HasResource(s.resource.GVK()) {
+		gvk := s.resource.GVK()
+		gvk.ValidatingWebhook, gvk.ConversionWebhook = validation, conversion
+		s.config.UpdateResource(gvk)
+	}
+
+	builders := []file.Builder{
+		&api.Webhook{Defaulting: defaulting, Validating: validation, Existing: string(existing)},
 		&templates.MainUpdater{WireWebhook: true},
-	); err != nil {
+	}
+	// The webhooks are tested through the API server of an envtest environment calling them
+	if defaulting || validation {
+		builders = append(builders,
+			&api.WebhookSuiteTest{TestFramework: s.config.TestFramework},
+			&api.WebhookTest{
+				TestFramework: s.config.TestFramework,
+				Defaulting:    defaulting,
+				Validating:    validation,
+				Existing:      string(existingTest),
+			},
+		)
+	}
+	if err := machinery.NewScaffold().Execute(s.newUniverse(), builders...); err != nil {
+		return err
+	}
+
+	if err := updateWebhookServerOptions("main.go", s.config); err != nil {
 		return err
 	}
 
-	return nil
+	return updateMakefile("Makefile", s.resource)
+}
+
+// trackedGVK returns the resource as tracked by the config, empty if it is not
+func (s *webhookScaffolder) trackedGVK() config.GVK {
+	gvk := s.resource.GVK()
+	for _, r := range s.config.Resources {
+		if r.Group == gvk.Group && r.Version == gvk.Version && r.Kind == gvk.Kind {
+			return r
+		}
+	}
+	return config.GVK{}
+}
+
+// webhookServerOptionsRe matches the webhook server fields of the manager options in main.go
+var webhookServerOptionsRe = regexp.MustCompile(`(?m)^(\t+)Port:( +)\d+,\n(\t+Host:.*\n)?(\t+CertDir:.*\n)?`)
+
+// updateWebhookServerOptions sets the webhook server fields of the manager options in main.go to the ones of the
+// config, which may have been changed when creating the webhook
+func updateWebhookServerOptions(path string, c *config.Config) error {
+	bs, err := ioutil.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	updated := webhookServerOptionsRe.ReplaceAllFunc(bs, func(match []byte) []byte {
+		groups := webhookServerOptionsRe.FindSubmatch(match)
+		indent, width := string(groups[1]), len("Port:")+len(groups[2])
+		field := func(name, value string) string {
+			return fmt.Sprintf("%s%-*s%s,\n", indent, width, name+":", value)
+		}
+
+		options := field("Port", strconv.Itoa(c.GetWebhookPort()))
+		if c.WebhookHost != "" {
+			options += field("Host", strconv.Quote(c.WebhookHost))
+		}
+		if c.WebhookCertDir != "" {
+			options += field("CertDir", strconv.Quote(c.WebhookCertDir))
+		}
+		return []byte(options)
+	})
+	if bytes.Equal(updated, bs) {
+		return nil
+	}
+
+	logger.Default().Info(fmt.Sprintf("Updating the webhook server options in %s...", path))
+	// false positive
+	// nolint:gosec
+	return ioutil.WriteFile(path, updated, 0644)
 }
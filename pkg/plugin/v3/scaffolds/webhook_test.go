@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+	"sigs.k8s.io/kubebuilder/pkg/model/resource"
+)
+
+var _ = Describe("webhookScaffolder", func() {
+	var (
+		cfg         *config.Config
+		res         *resource.Resource
+		boilerplate string
+		tmpDir      string
+		oldDir      string
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			Version:     config.Version3Alpha,
+			Domain:      "my.domain",
+			Repo:        "example.com/project",
+			ProjectName: "project",
+		}
+
+		var err error
+		oldDir, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		tmpDir, err = ioutil.TempDir("", "v3-scaffolds")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldDir)).To(Succeed())
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	scaffoldAPI := func() {
+		Expect(NewInitScaffolder(cfg, "apache2", "The Authors", "2020", "", "", false, false, false).Scaffold()).
+			To(Succeed())
+		bs, err := ioutil.ReadFile(filepath.Join("hack", "boilerplate.go.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		boilerplate = string(bs)
+
+		opts := &resource.Options{Group: "crew", Version: "v1", Kind: "Captain", Namespaced: true}
+		cfg.AddResource(opts.GVK())
+		res = opts.NewResource(cfg, true)
+		Expect(NewAPIScaffolder(cfg, boilerplate, res,
+			true, true, false, false, false, false, false, false, false, false, nil, nil).Scaffold()).To(Succeed())
+	}
+
+	read := func(path ...string) string {
+		content, err := ioutil.ReadFile(filepath.Join(path...))
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return string(content)
+	}
+
+	It("should append the tests of a validating webhook added to a resource with a defaulting webhook", func() {
+		scaffoldAPI()
+		Expect(NewWebhookScaffolder(cfg, boilerplate, res, true, false, false).Scaffold()).To(Succeed())
+		tests := read("api", "v1", "captain_webhook_test.go")
+		Expect(tests).To(ContainSubstring(`var _ = Describe("Captain defaulting webhook", func() {`))
+		Expect(tests).NotTo(ContainSubstring("validating webhook"))
+
+		Expect(NewWebhookScaffolder(cfg, boilerplate, res, false, true, false).Scaffold()).To(Succeed())
+		Expect(read("api", "v1", "captain_webhook.go")).To(ContainSubstring("var _ webhook.Validator = &Captain{}"))
+		appended := read("api", "v1", "captain_webhook_test.go")
+		Expect(appended).To(HavePrefix(tests))
+		Expect(appended).To(ContainSubstring(`var _ = Describe("Captain validating webhook", func() {`))
+		Expect(strings.Count(appended, "func newCaptain(")).To(Equal(1))
+	})
+
+	It("should append the standard library tests of a defaulting webhook added to a resource", func() {
+		cfg.TestFramework = config.TestFrameworkGoTest
+		scaffoldAPI()
+		Expect(NewWebhookScaffolder(cfg, boilerplate, res, false, true, false).Scaffold()).To(Succeed())
+		tests := read("api", "v1", "captain_webhook_test.go")
+		Expect(tests).To(ContainSubstring("func TestCaptainValidatingWebhook(t *testing.T) {"))
+		Expect(tests).NotTo(ContainSubstring(`"reflect"`))
+
+		Expect(NewWebhookScaffolder(cfg, boilerplate, res, true, false, false).Scaffold()).To(Succeed())
+		appended := read("api", "v1", "captain_webhook_test.go")
+		Expect(appended).To(ContainSubstring("func TestCaptainValidatingWebhook(t *testing.T) {"))
+		Expect(appended).To(ContainSubstring("func TestCaptainDefaultingWebhook(t *testing.T) {"))
+		Expect(appended).To(ContainSubstring("\t\"reflect\"\n"))
+	})
+})
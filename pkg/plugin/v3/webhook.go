@@ -17,13 +17,16 @@ limitations under the License.
 package v3
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"path"
 	"path/filepath"
 
 	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/internal/cmdutil"
+	"sigs.k8s.io/kubebuilder/pkg/logger"
 	"sigs.k8s.io/kubebuilder/pkg/model/config"
 	"sigs.k8s.io/kubebuilder/pkg/model/resource"
 	"sigs.k8s.io/kubebuilder/pkg/plugin"
@@ -36,10 +39,15 @@ type createWebhookPlugin struct {
 	// For help text.
 	commandName string
 
-	resource   *resource.Options
+	resource *resource.Options
+	// webhookVersionFlag is not changed if the webhook version is the one of the project
+	webhookVersionFlag *pflag.Flag
+
 	defaulting bool
 	validation bool
 	conversion bool
+
+	webhookServer webhookServerOptions
 }
 
 var (
@@ -50,6 +58,15 @@ var (
 func (p *createWebhookPlugin) UpdateContext(ctx *plugin.Context) {
 	ctx.Description = `Scaffold a webhook for an API resource. You can choose to scaffold defaulting,
 validating and (or) conversion webhooks.
+
+The webhooks can be added one at a time: running create webhook again for a resource that already has some
+appends the requested ones to its webhook file, skipping the ones it has. The PROJECT file records which
+webhooks each resource has.
+
+The defaulting and validating webhooks get tests in <kind>_webhook_test.go, which create objects through the API
+server of the envtest environment of webhook_suite_test.go. The suite installs the webhook configurations of
+config/webhook in it, to call the webhooks run by the webhook server of a manager. The tests of the webhooks
+added to a resource are appended to its existing ones.
 `
 	ctx.Examples = fmt.Sprintf(`  # Create defaulting and validating webhooks for CRD of group crew, version v1
   # and kind FirstMate.
@@ -57,8 +74,17 @@ validating and (or) conversion webhooks.
 
   # Create conversion webhook for CRD of group crew, version v1 and kind FirstMate.
   %s create webhook --group crew --version v1 --kind FirstMate --conversion
+
+  # Add a validating webhook to the defaulting one of FirstMate, created beforehand
+  %s create webhook --group crew --version v1 --kind FirstMate --programmatic-validation
+
+  # Create a defaulting webhook whose configuration manifest uses admissionregistration.k8s.io/v1
+  %s create webhook --group crew --version v1 --kind FirstMate --defaulting --webhook-version=v1
+
+  # Create a validating webhook, moving the webhook server of the manager to the port 9444
+  %s create webhook --group crew --version v1 --kind FirstMate --programmatic-validation --webhook-port=9444
 `,
-		ctx.CommandName, ctx.CommandName)
+		ctx.CommandName, ctx.CommandName, ctx.CommandName, ctx.CommandName, ctx.CommandName)
 
 	p.commandName = ctx.CommandName
 }
@@ -76,17 +102,32 @@ func (p *createWebhookPlugin) BindFlags(fs *pflag.FlagSet) {
 		"if set, scaffold the validating webhook")
 	fs.BoolVar(&p.conversion, "conversion", false,
 		"if set, scaffold the conversion webhook")
+	fs.StringVar(&p.resource.WebhookVersion, "webhook-version", resource.DefaultWebhookVersion,
+		"API version of the generated webhook configuration manifests, one of v1beta1 or v1, "+
+			"all the webhooks of a project share it, defaults to the one of the existing webhooks")
+	p.webhookVersionFlag = fs.Lookup("webhook-version")
+	p.webhookServer.bindFlags(fs)
 }
 
 func (p *createWebhookPlugin) InjectConfig(c *config.Config) {
 	p.config = c
 }
 
-func (p *createWebhookPlugin) Run() error {
-	return cmdutil.Run(p)
+func (p *createWebhookPlugin) Scaffold() error {
+	return cmdutil.Scaffold(p)
 }
 
 func (p *createWebhookPlugin) Validate() error {
+	// The webhook configurations of all the resources share the webhook version of the project
+	if !p.webhookVersionFlag.Changed {
+		for _, r := range p.config.Resources {
+			if r.WebhookVersion != "" {
+				p.resource.WebhookVersion = r.WebhookVersion
+				break
+			}
+		}
+	}
+
 	if err := p.resource.Validate(); err != nil {
 		return err
 	}
@@ -96,6 +137,39 @@ func (p *createWebhookPlugin) Validate() error {
 			" --programmatic-validation and --conversion to be true", p.commandName)
 	}
 
+	// The webhooks are implemented in the packages of the types, which would then depend on controller-runtime
+	if p.config.APIsModule {
+		return errors.New("webhooks are not supported in a project with a separate module for the APIs, " +
+			"as they would make it depend on controller-runtime")
+	}
+
+	// The webhook configurations of all the resources are patched by the same kustomize manifests
+	gvk := p.resource.GVK()
+	for _, r := range p.config.Resources {
+		if r.WebhookVersion != "" && r.WebhookVersion != gvk.WebhookVersion &&
+			!(r.Group == gvk.Group && r.Version == gvk.Version && r.Kind == gvk.Kind) {
+			return fmt.Errorf("only one webhook version can be used for all resources, "+
+				"the project uses %q so --webhook-version=%s is not allowed", r.WebhookVersion, gvk.WebhookVersion)
+		}
+	}
+
+	if err := p.webhookServer.apply(p.config); err != nil {
+		return err
+	}
+
+	// The fields of a resource are defaulted either by markers or by a webhook
+	if p.defaulting {
+		for _, r := range p.config.Resources {
+			if r.Group == gvk.Group && r.Version == gvk.Version && r.Kind == gvk.Kind &&
+				r.Defaults == config.DefaultsMarkers {
+				logger.Default().Info(fmt.Sprintf("Warning: %s/%s, Kind=%s is defaulted by markers, remove the "+
+					"+kubebuilder:default markers of its types that the defaulting webhook replaces", r.Group,
+					r.Version, r.Kind))
+			}
+		}
+		p.resource.Defaults = config.DefaultsWebhook
+	}
+
 	return nil
 }
 
@@ -114,3 +188,61 @@ func (p *createWebhookPlugin) GetScaffolder() (scaffold.Scaffolder, error) {
 func (p *createWebhookPlugin) PostScaffold() error {
 	return nil
 }
+
+// Ports of the metrics endpoint of the manager in the manifests, over HTTP to the auth proxy and over HTTPS
+const (
+	metricsPort       = 8080
+	secureMetricsPort = 8443
+)
+
+// webhookServerOptions are the flags configuring the webhook server of the manager, recorded in the config by init
+// and create webhook for the later commands
+type webhookServerOptions struct {
+	port    int
+	host    string
+	certDir string
+
+	portFlag, hostFlag, certDirFlag *pflag.Flag
+}
+
+func (o *webhookServerOptions) bindFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.port, "webhook-port", config.DefaultWebhookPort,
+		"port the webhook server of the manager listens on, the same for all the webhooks of the project")
+	fs.StringVar(&o.host, "webhook-host", "",
+		"host the webhook server of the manager binds to, all the interfaces if empty")
+	fs.StringVar(&o.certDir, "webhook-cert-dir", config.DefaultWebhookCertDir,
+		"absolute directory the webhook server of the manager reads its tls.crt and tls.key from, "+
+			"where its manifests mount the certificate")
+	o.portFlag = fs.Lookup("webhook-port")
+	o.hostFlag = fs.Lookup("webhook-host")
+	o.certDirFlag = fs.Lookup("webhook-cert-dir")
+}
+
+// apply validates the flags that were set and records them in c, the defaults are not recorded
+func (o *webhookServerOptions) apply(c *config.Config) error {
+	if o.portFlag.Changed {
+		if o.port < 1 || o.port > 65535 {
+			return fmt.Errorf("webhook port %d is invalid, must be between 1 and 65535", o.port)
+		}
+		if o.port == metricsPort || o.port == secureMetricsPort {
+			return fmt.Errorf("webhook port %d is invalid, the metrics endpoint of the manager uses it", o.port)
+		}
+		c.WebhookPort = o.port
+		if o.port == config.DefaultWebhookPort {
+			c.WebhookPort = 0
+		}
+	}
+	if o.hostFlag.Changed {
+		c.WebhookHost = o.host
+	}
+	if o.certDirFlag.Changed {
+		if !path.IsAbs(o.certDir) {
+			return fmt.Errorf("webhook cert dir %q is invalid, must be an absolute path", o.certDir)
+		}
+		c.WebhookCertDir = path.Clean(o.certDir)
+		if c.WebhookCertDir == config.DefaultWebhookCertDir {
+			c.WebhookCertDir = ""
+		}
+	}
+	return nil
+}
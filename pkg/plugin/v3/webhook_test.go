@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/pkg/model/config"
+)
+
+var _ = Describe("createWebhookPlugin", func() {
+	It("should default the webhook version to the one of the project", func() {
+		c := &config.Config{
+			Version: config.Version3Alpha,
+			Domain:  "example.org",
+			Repo:    "example.org/p3",
+			Resources: []config.GVK{
+				{Group: "crew", Version: "v1", Kind: "Captain", WebhookVersion: "v1"},
+				{Group: "crew", Version: "v1", Kind: "FirstMate"},
+			},
+		}
+
+		p := &createWebhookPlugin{}
+		fs := pflag.NewFlagSet("create webhook", pflag.ContinueOnError)
+		p.BindFlags(fs)
+		Expect(fs.Parse([]string{"--group", "crew", "--version", "v1", "--kind", "FirstMate", "--defaulting"})).
+			To(Succeed())
+		p.InjectConfig(c)
+		Expect(p.Validate()).To(Succeed())
+		Expect(p.resource.WebhookVersion).To(Equal("v1"))
+	})
+})
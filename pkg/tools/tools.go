@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tools configures the environment the external tools run by the cli and its plugins, e.g. go, make
+// and controller-gen, are executed in.
+package tools
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+// EnvtestK8sVersion is the Kubernetes version of the envtest binaries that the setup-envtest command downloads
+// by default, and that the tests of the scaffolded projects run against.
+const EnvtestK8sVersion = "1.19.2"
+
+// Environment is the environment external tools are executed in.
+type Environment struct {
+	// Env holds the environment variables set for every tool, e.g. GOFLAGS or GOPROXY, overriding the ones
+	// inherited from the process.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Paths holds the binary executed for each tool, by tool name. The directories of these binaries are
+	// also prepended to the PATH, so tools executed by other tools, e.g. controller-gen by make, are found.
+	Paths map[string]string `json:"paths,omitempty"`
+}
+
+// LoadFile reads an Environment from the YAML file at path.
+func LoadFile(path string) (Environment, error) {
+	e := Environment{}
+	in, err := ioutil.ReadFile(path) // nolint:gosec
+	if err != nil {
+		return e, fmt.Errorf("unable to read tools config %q: %v", path, err)
+	}
+	if err := yaml.UnmarshalStrict(in, &e); err != nil {
+		return e, fmt.Errorf("unable to parse tools config %q: %v", path, err)
+	}
+	return e, nil
+}
+
+// Merge returns an Environment with the variables and paths of both e and other, those of other taking
+// precedence.
+func (e Environment) Merge(other Environment) Environment {
+	merged := Environment{Env: make(map[string]string), Paths: make(map[string]string)}
+	for _, from := range []Environment{e, other} {
+		for key, value := range from.Env {
+			merged.Env[key] = value
+		}
+		for name, path := range from.Paths {
+			merged.Paths[name] = path
+		}
+	}
+	return merged
+}
+
+// Command returns the command that executes the tool name with args in e.
+func (e Environment) Command(name string, args ...string) *exec.Cmd {
+	binary := name
+	if path, found := e.Paths[name]; found {
+		binary = path
+	}
+	cmd := exec.Command(binary, args...) //nolint:gosec
+	cmd.Env = e.environ(os.Environ())
+	return cmd
+}
+
+// environ returns base with the variables of e set, and the directories of its paths prepended to the PATH.
+func (e Environment) environ(base []string) []string {
+	overrides := make(map[string]string, len(e.Env)+1)
+	for key, value := range e.Env {
+		overrides[key] = value
+	}
+	if dirs := e.binaryDirs(); len(dirs) != 0 {
+		path, found := overrides["PATH"]
+		if !found {
+			path = lookupEnv(base, "PATH")
+		}
+		if path != "" {
+			dirs = append(dirs, path)
+		}
+		overrides["PATH"] = strings.Join(dirs, string(os.PathListSeparator))
+	}
+
+	env := make([]string, 0, len(base)+len(overrides))
+	for _, kv := range base {
+		if _, overridden := overrides[strings.SplitN(kv, "=", 2)[0]]; !overridden {
+			env = append(env, kv)
+		}
+	}
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env = append(env, key+"="+overrides[key])
+	}
+	return env
+}
+
+// binaryDirs returns the directories of the paths of e, sorted by tool name and without duplicates.
+func (e Environment) binaryDirs() []string {
+	names := make([]string, 0, len(e.Paths))
+	for name := range e.Paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dirs := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		dir := filepath.Dir(e.Paths[name])
+		if dir == "." || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// lookupEnv returns the value of key in env, or an empty string if it is not set.
+func lookupEnv(env []string, key string) string {
+	for _, kv := range env {
+		if strings.HasPrefix(kv, key+"=") {
+			return strings.TrimPrefix(kv, key+"=")
+		}
+	}
+	return ""
+}
+
+var (
+	defaultMu          sync.RWMutex
+	defaultEnvironment = Environment{}
+)
+
+// Default returns the Environment set by the cli. Tools inherit the environment of the process if none was set.
+func Default() Environment {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultEnvironment
+}
+
+// SetDefault sets the Environment returned by Default.
+func SetDefault(e Environment) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultEnvironment = e
+}
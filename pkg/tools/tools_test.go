@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tools
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Environment", func() {
+	It("should override the inherited variables", func() {
+		e := Environment{Env: map[string]string{"GOPROXY": "https://proxy.example.com", "GOFLAGS": "-mod=vendor"}}
+		Expect(e.environ([]string{"HOME=/root", "GOPROXY=direct"})).To(Equal([]string{
+			"HOME=/root", "GOFLAGS=-mod=vendor", "GOPROXY=https://proxy.example.com",
+		}))
+	})
+
+	It("should prepend the directories of the binaries to the PATH", func() {
+		e := Environment{Paths: map[string]string{
+			"go":             "/opt/go/bin/go",
+			"controller-gen": "/opt/tools/controller-gen",
+			"kustomize":      "/opt/tools/kustomize",
+			"make":           "make",
+		}}
+		Expect(e.environ([]string{"PATH=/usr/bin"})).To(Equal([]string{
+			"PATH=/opt/tools:/opt/go/bin:/usr/bin",
+		}))
+	})
+
+	It("should execute the configured binary", func() {
+		e := Environment{Paths: map[string]string{"go": "/opt/go/bin/go"}}
+		cmd := e.Command("go", "version")
+		Expect(cmd.Path).To(Equal("/opt/go/bin/go"))
+		Expect(cmd.Args).To(Equal([]string{"/opt/go/bin/go", "version"}))
+	})
+
+	It("should give precedence to the merged environment", func() {
+		e := Environment{
+			Env:   map[string]string{"GOPROXY": "direct", "GOPRIVATE": "example.com"},
+			Paths: map[string]string{"go": "/usr/bin/go"},
+		}
+		Expect(e.Merge(Environment{Env: map[string]string{"GOPROXY": "off"}})).To(Equal(Environment{
+			Env:   map[string]string{"GOPROXY": "off", "GOPRIVATE": "example.com"},
+			Paths: map[string]string{"go": "/usr/bin/go"},
+		}))
+	})
+
+	Context("LoadFile", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "tools")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should read the variables and paths", func() {
+			path := filepath.Join(dir, "tools.yaml")
+			Expect(ioutil.WriteFile(path, []byte("env:\n  GOFLAGS: -mod=vendor\npaths:\n  go: /opt/go/bin/go\n"),
+				0600)).To(Succeed())
+			Expect(LoadFile(path)).To(Equal(Environment{
+				Env:   map[string]string{"GOFLAGS": "-mod=vendor"},
+				Paths: map[string]string{"go": "/opt/go/bin/go"},
+			}))
+		})
+
+		It("should fail on unknown fields", func() {
+			path := filepath.Join(dir, "tools.yaml")
+			Expect(ioutil.WriteFile(path, []byte("envs:\n  GOFLAGS: -mod=vendor\n"), 0600)).To(Succeed())
+			_, err := LoadFile(path)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -21,6 +21,7 @@ import (
 
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
 )
 
 const exampleChannel = `# Versions for the stable channel
@@ -36,6 +37,6 @@ func ExampleChannel(u *model.Universe) error {
 		IfExistsAction: file.Skip,
 	}
 
-	_, err := AddFile(u, m)
+	_, err := util.AddFile(u, m)
 	return err
 }
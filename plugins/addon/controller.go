@@ -6,14 +6,15 @@ import (
 
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
 )
 
 // ReplaceController replaces the controller with a modified version
 func ReplaceController(u *model.Universe) error {
 	templateBody := controllerTemplate
 
-	funcs := DefaultTemplateFunctions()
-	contents, err := RunTemplate("controller", templateBody, u, funcs)
+	funcs := util.DefaultTemplateFunctions()
+	contents, err := util.RunTemplate("controller", templateBody, u, funcs)
 	if err != nil {
 		return err
 	}
@@ -24,7 +25,7 @@ func ReplaceController(u *model.Universe) error {
 		IfExistsAction: file.Error,
 	}
 
-	ReplaceFileIfExists(u, m)
+	util.ReplaceFileIfExists(u, m)
 
 	return nil
 }
@@ -22,6 +22,7 @@ import (
 
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
 )
 
 const exampleManifestVersion = "0.0.1"
@@ -39,7 +40,7 @@ func ExampleManifest(u *model.Universe) error {
 		IfExistsAction: file.Skip,
 	}
 
-	_, err := AddFile(u, m)
+	_, err := util.AddFile(u, m)
 
 	return err
 }
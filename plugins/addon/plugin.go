@@ -2,6 +2,7 @@ package addon
 
 import (
 	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
 )
 
 // Plugin implements model.Plugin
@@ -10,7 +11,7 @@ type Plugin struct {
 
 // Pipe implements model.Plugin
 func (p *Plugin) Pipe(u *model.Universe) error {
-	functions := []PluginFunc{
+	functions := []util.PluginFunc{
 		ExampleManifest,
 		ExampleChannel,
 		ReplaceController,
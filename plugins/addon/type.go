@@ -7,14 +7,15 @@ import (
 
 	"sigs.k8s.io/kubebuilder/pkg/model"
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
 )
 
 // ReplaceTypes replaces the API types with a modified version
 func ReplaceTypes(u *model.Universe) error {
-	funcs := DefaultTemplateFunctions()
+	funcs := util.DefaultTemplateFunctions()
 	funcs["JSONTag"] = JSONTag
 
-	contents, err := RunTemplate("types", typesTemplate, u, funcs)
+	contents, err := util.RunTemplate("types", typesTemplate, u, funcs)
 	if err != nil {
 		return err
 	}
@@ -32,7 +33,7 @@ func ReplaceTypes(u *model.Universe) error {
 		IfExistsAction: file.Error,
 	}
 
-	ReplaceFileIfExists(u, m)
+	util.ReplaceFileIfExists(u, m)
 
 	return nil
 }
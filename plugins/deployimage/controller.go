@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployimage
+
+import (
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
+)
+
+// ReplaceController replaces the controller with one that deploys and reconciles the image
+func (p *Plugin) ReplaceController(u *model.Universe) error {
+	funcs := util.DefaultTemplateFunctions()
+	funcs["upper"] = strings.ToUpper
+
+	contents, err := util.RunTemplate("controller", controllerTemplate, p.newData(u), funcs)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(u.Config.GetDirectories().Controller, "%[kind]_controller.go")
+
+	m := &file.File{
+		Path:           u.Resource.Replacer().Replace(path),
+		Contents:       contents,
+		IfExistsAction: file.Error,
+	}
+
+	util.ReplaceFileIfExists(u, m)
+
+	return nil
+}
+
+//nolint:lll
+const controllerTemplate = `{{ .Boilerplate }}
+
+package {{ .Config.GetDirectories.ControllersPackage }}
+
+import (
+	"context"
+	"os"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+{{- if .ContainerPort }}
+	"k8s.io/apimachinery/pkg/util/intstr"
+{{- end }}
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	{{ .Resource.ImportAlias }} "{{ .Resource.Package }}"
+)
+
+const (
+	// {{ lower .Resource.Kind }}DefaultImage is the image deployed for every {{ .Resource.Kind }},
+	// it can be overridden at runtime through the {{ upper .Resource.Kind }}_IMAGE environment variable
+	{{ lower .Resource.Kind }}DefaultImage = "{{ .Image }}"
+{{- if .ContainerPort }}
+	// {{ lower .Resource.Kind }}ContainerPort is the port exposed by the {{ .Resource.Kind }} image
+	{{ lower .Resource.Kind }}ContainerPort = {{ .ContainerPort }}
+{{- end }}
+)
+
+// {{ .Resource.Kind }}Reconciler reconciles a {{ .Resource.Kind }} object
+type {{ .Resource.Kind }}Reconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups={{ .Resource.Domain }},resources={{ .Resource.Plural }},verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups={{ .Resource.Domain }},resources={{ .Resource.Plural }}/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+{{- if .ContainerPort }}
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+{{- end }}
+
+func (r *{{ .Resource.Kind }}Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	log := r.Log.WithValues("{{ .Resource.Kind | lower }}", req.NamespacedName)
+
+	instance := &{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		// The owned objects are garbage collected once the {{ .Resource.Kind }} is deleted
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	labels := map[string]string{
+		"app.kubernetes.io/name":       "{{ lower .Resource.Kind }}",
+		"app.kubernetes.io/instance":   instance.Name,
+		"app.kubernetes.io/managed-by": "{{ lower .Resource.Kind }}-controller",
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: instance.Name, Namespace: instance.Namespace},
+	}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
+		deployment.Spec.Replicas = instance.Spec.Replicas
+		deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+		deployment.Spec.Template.ObjectMeta.Labels = labels
+		deployment.Spec.Template.Spec.Containers = []corev1.Container{{"{{"}}
+			Name:  "{{ lower .Resource.Kind }}",
+			Image: {{ lower .Resource.Kind }}Image(),
+			Env:   instance.Spec.Env,
+{{- if .ContainerPort }}
+			Ports: []corev1.ContainerPort{{"{{"}}ContainerPort: {{ lower .Resource.Kind }}ContainerPort{{"}}"}},
+{{- end }}
+		{{"}}"}}
+		return ctrl.SetControllerReference(instance, deployment, r.Scheme)
+	})
+	if err != nil {
+		log.Error(err, "unable to reconcile Deployment")
+		return ctrl.Result{}, err
+	}
+	log.V(1).Info("reconciled Deployment", "operation", op)
+{{- if .ContainerPort }}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: instance.Name, Namespace: instance.Namespace},
+	}
+	op, err = controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		// Only the fields owned by the controller are set so that the ones defaulted by
+		// the API server, e.g. the ClusterIP, are preserved
+		service.Spec.Selector = labels
+		service.Spec.Ports = []corev1.ServicePort{{"{{"}}
+			Port:       {{ lower .Resource.Kind }}ContainerPort,
+			TargetPort: intstr.FromInt({{ lower .Resource.Kind }}ContainerPort),
+		{{"}}"}}
+		return ctrl.SetControllerReference(instance, service, r.Scheme)
+	})
+	if err != nil {
+		log.Error(err, "unable to reconcile Service")
+		return ctrl.Result{}, err
+	}
+	log.V(1).Info("reconciled Service", "operation", op)
+{{- end }}
+
+	if instance.Status.AvailableReplicas != deployment.Status.AvailableReplicas {
+		instance.Status.AvailableReplicas = deployment.Status.AvailableReplicas
+		if err := r.Status().Update(ctx, instance); err != nil {
+			log.Error(err, "unable to update {{ .Resource.Kind }} status")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *{{ .Resource.Kind }}Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{}).
+		Owns(&appsv1.Deployment{}).
+{{- if .ContainerPort }}
+		Owns(&corev1.Service{}).
+{{- end }}
+		Complete(r)
+}
+
+// {{ lower .Resource.Kind }}Image returns the image deployed for every {{ .Resource.Kind }}
+func {{ lower .Resource.Kind }}Image() string {
+	if image, found := os.LookupEnv("{{ upper .Resource.Kind }}_IMAGE"); found {
+		return image
+	}
+	return {{ lower .Resource.Kind }}DefaultImage
+}
+`
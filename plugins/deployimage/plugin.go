@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployimage
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
+)
+
+// Plugin implements model.Plugin, scaffolding an API and a controller that deploy the provided image
+type Plugin struct {
+	// Image is the container image deployed by the controller
+	Image string
+	// ContainerPort is the port exposed by the image, a Service is only scaffolded if it is non-zero
+	ContainerPort int
+}
+
+// data is the input provided to the templates of this plugin
+type data struct {
+	*model.Universe
+
+	Image         string
+	ContainerPort int
+}
+
+// Pipe implements model.Plugin
+func (p *Plugin) Pipe(u *model.Universe) error {
+	functions := []util.PluginFunc{
+		p.ReplaceTypes,
+		p.AddSample,
+		p.ReplaceController,
+	}
+
+	for _, fn := range functions {
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newData returns the data used to render the templates
+func (p *Plugin) newData(u *model.Universe) data {
+	return data{
+		Universe:      u,
+		Image:         p.Image,
+		ContainerPort: p.ContainerPort,
+	}
+}
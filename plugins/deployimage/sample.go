@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployimage
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
+)
+
+// AddSample adds a sample CR that sets the deployment spec fields
+func (p *Plugin) AddSample(u *model.Universe) error {
+	// The sample is only added along with the API types, as the plugin is run for every scaffolded set of files
+	if _, found := u.Files[typesPath(u)]; !found {
+		return nil
+	}
+
+	contents, err := util.RunTemplate("sample", sampleTemplate, p.newData(u), util.DefaultTemplateFunctions())
+	if err != nil {
+		return err
+	}
+
+	m := &file.File{
+		Path:           u.Resource.Replacer().Replace(filepath.Join("config", "samples", "%[group]_%[version]_%[kind].yaml")),
+		Contents:       contents,
+		IfExistsAction: file.Error,
+	}
+
+	// The sample is scaffolded by the kustomize plugin, which does not overwrite the one added here
+	_, err = util.AddFile(u, m)
+
+	return err
+}
+
+const sampleTemplate = `apiVersion: {{ .Resource.Domain }}/{{ .Resource.Version }}
+kind: {{ .Resource.Kind }}
+metadata:
+  name: {{ lower .Resource.Kind }}-sample
+spec:
+  replicas: 1
+  env:
+  - name: EXAMPLE
+    value: example
+`
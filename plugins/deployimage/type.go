@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployimage
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
+)
+
+// ReplaceTypes replaces the API types with a version that defines the deployment spec fields
+func (p *Plugin) ReplaceTypes(u *model.Universe) error {
+	contents, err := util.RunTemplate("types", typesTemplate, p.newData(u), util.DefaultTemplateFunctions())
+	if err != nil {
+		return err
+	}
+
+	m := &file.File{
+		Path:           typesPath(u),
+		Contents:       contents,
+		IfExistsAction: file.Error,
+	}
+
+	util.ReplaceFileIfExists(u, m)
+
+	return nil
+}
+
+// typesPath returns the path of the API types of the universe's resource
+func typesPath(u *model.Universe) string {
+	path := filepath.Join(u.Config.GetDirectories().Types, "%[kind]_types.go")
+	return u.Resource.Replacer().Replace(path)
+}
+
+const typesTemplate = `{{ .Boilerplate }}
+
+package {{ .Resource.Version }}
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// {{ .Resource.Kind }}Spec defines the desired state of {{ .Resource.Kind }}
+type {{ .Resource.Kind }}Spec struct {
+	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// Replicas is the number of pods running the {{ .Image }} image
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 ` + "`" + `json:"replicas,omitempty"` + "`" + `
+
+	// Env is the list of environment variables set in the {{ .Image }} container
+	// +optional
+	Env []corev1.EnvVar ` + "`" + `json:"env,omitempty"` + "`" + `
+}
+
+// {{ .Resource.Kind }}Status defines the observed state of {{ .Resource.Kind }}
+type {{ .Resource.Kind }}Status struct {
+	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
+	// Important: Run "make" to regenerate code after modifying this file
+
+	// AvailableReplicas is the number of pods running the {{ .Image }} image that are available
+	AvailableReplicas int32 ` + "`" + `json:"availableReplicas,omitempty"` + "`" + `
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Available",type=integer,JSONPath=` + "`" + `.status.availableReplicas` + "`" + `
+
+// {{ .Resource.Kind }} is the Schema for the {{ .Resource.Plural }} API
+type {{ .Resource.Kind }} struct {
+	metav1.TypeMeta   ` + "`" + `json:",inline"` + "`" + `
+	metav1.ObjectMeta ` + "`" + `json:"metadata,omitempty"` + "`" + `
+
+	Spec   {{ .Resource.Kind }}Spec   ` + "`" + `json:"spec,omitempty"` + "`" + `
+	Status {{ .Resource.Kind }}Status ` + "`" + `json:"status,omitempty"` + "`" + `
+}
+
+// +kubebuilder:object:root=true
+
+// {{ .Resource.Kind }}List contains a list of {{ .Resource.Kind }}
+type {{ .Resource.Kind }}List struct {
+	metav1.TypeMeta ` + "`" + `json:",inline"` + "`" + `
+	metav1.ListMeta ` + "`" + `json:"metadata,omitempty"` + "`" + `
+	Items           []{{ .Resource.Kind }} ` + "`" + `json:"items"` + "`" + `
+}
+
+func init() {
+	SchemeBuilder.Register(&{{ .Resource.Kind }}{}, &{{ .Resource.Kind }}List{})
+}
+`
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
+)
+
+// AddChart adds the example chart rendered for every object of the API to helm-charts/<kind>, it can be replaced
+// by the chart of a helm-operator project
+func AddChart(u *model.Universe) error {
+	// The chart is only added along with the API types, as the plugin is run for every scaffolded set of files
+	if _, found := u.Files[typesPath(u)]; !found {
+		return nil
+	}
+
+	replacer := u.Resource.Replacer()
+	for name, contents := range map[string]string{
+		"Chart.yaml":  chartTemplate,
+		"values.yaml": valuesTemplate,
+		filepath.Join("templates", "deployment.yaml"): deploymentTemplate,
+	} {
+		// The chart templates are not rendered as Go templates, as they use the same delimiters
+		m := &file.File{
+			Path:           filepath.Join(chartDir(u), name),
+			Contents:       replacer.Replace(contents),
+			IfExistsAction: file.Skip,
+		}
+		if _, err := util.AddFile(u, m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chartDir returns the directory of the chart of the universe's resource
+func chartDir(u *model.Universe) string {
+	return u.Resource.Replacer().Replace(filepath.Join("helm-charts", "%[kind]"))
+}
+
+const chartTemplate = `apiVersion: v2
+name: %[kind]
+description: The chart rendered by the controller for every %[kind] object, with the values of its spec
+type: application
+version: 0.1.0
+appVersion: "1.19"
+`
+
+const valuesTemplate = `# Default values of the chart, overridden by the fields of the spec of the %[kind] objects
+# with the same names.
+replicaCount: 1
+
+image:
+  repository: nginx
+  tag: "1.19"
+`
+
+const deploymentTemplate = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+  namespace: {{ .Release.Namespace }}
+  labels:
+    app.kubernetes.io/name: {{ .Chart.Name }}
+    app.kubernetes.io/instance: {{ .Release.Name }}
+spec:
+  replicas: {{ .Values.replicaCount }}
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: {{ .Chart.Name }}
+      app.kubernetes.io/instance: {{ .Release.Name }}
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: {{ .Chart.Name }}
+        app.kubernetes.io/instance: {{ .Release.Name }}
+    spec:
+      containers:
+      - name: {{ .Chart.Name }}
+        image: "{{ .Values.image.repository }}:{{ .Values.image.tag }}"
+`
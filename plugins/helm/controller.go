@@ -0,0 +1,214 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
+)
+
+// ReplaceController replaces the controller with one that renders the chart for every object of the API and applies
+// the rendered objects
+func ReplaceController(u *model.Universe) error {
+	funcs := util.DefaultTemplateFunctions()
+	funcs["upper"] = strings.ToUpper
+
+	contents, err := util.RunTemplate("controller", controllerTemplate, u, funcs)
+	if err != nil {
+		return err
+	}
+
+	m := &file.File{
+		Path:           controllerPath(u),
+		Contents:       contents,
+		IfExistsAction: file.Error,
+	}
+
+	util.ReplaceFileIfExists(u, m)
+
+	return nil
+}
+
+// controllerPath returns the path of the controller of the universe's resource
+func controllerPath(u *model.Universe) string {
+	path := filepath.Join(u.Config.GetDirectories().Controller, "%[kind]_controller.go")
+	return u.Resource.Replacer().Replace(path)
+}
+
+//nolint:lll
+const controllerTemplate = `{{ .Boilerplate }}
+
+package {{ .Config.GetDirectories.ControllersPackage }}
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	{{ .Resource.ImportAlias }} "{{ .Resource.Package }}"
+)
+
+const (
+	// {{ lower .Resource.Kind }}DefaultChartDir is the directory of the chart rendered for every {{ .Resource.Kind }}, relative
+	// to the working directory of the manager, it can be overridden at runtime through the
+	// {{ upper .Resource.Kind }}_CHART_DIR environment variable
+	{{ lower .Resource.Kind }}DefaultChartDir = "helm-charts/{{ lower .Resource.Kind }}"
+	// {{ lower .Resource.Kind }}FieldOwner is the manager of the fields of the objects rendered from the chart
+	{{ lower .Resource.Kind }}FieldOwner = "{{ lower .Resource.Kind }}-controller"
+)
+
+// {{ .Resource.Kind }}Reconciler reconciles a {{ .Resource.Kind }} object
+type {{ .Resource.Kind }}Reconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups={{ .Resource.Domain }},resources={{ .Resource.Plural }},verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups={{ .Resource.Domain }},resources={{ .Resource.Plural }}/status,verbs=get;update;patch
+// TODO(user): grant the permissions on all the kinds of objects rendered from the chart
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+
+func (r *{{ .Resource.Kind }}Reconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	log := r.Log.WithValues("{{ .Resource.Kind | lower }}", req.NamespacedName)
+
+	instance := &{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		// The objects rendered from the chart are garbage collected once the {{ .Resource.Kind }} is deleted
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !instance.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	objects, err := render{{ .Resource.Kind }}Chart(instance)
+	if err != nil {
+		log.Error(err, "unable to render the chart")
+		return ctrl.Result{}, err
+	}
+	for _, obj := range objects {
+		// The chart is released in the namespace of the {{ .Resource.Kind }}, which owns the rendered objects,
+		// so the chart must only render namespaced objects
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(instance.Namespace)
+		}
+		if err := ctrl.SetControllerReference(instance, obj, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		// The objects are server-side applied, so the fields removed from the chart are removed from them too
+		err := r.Patch(ctx, obj, client.Apply,
+			client.FieldOwner({{ lower .Resource.Kind }}FieldOwner), client.ForceOwnership)
+		if err != nil {
+			log.Error(err, "unable to apply the rendered object", "kind", obj.GetKind(), "name", obj.GetName())
+			return ctrl.Result{}, err
+		}
+	}
+	log.V(1).Info("applied the objects rendered from the chart", "count", len(objects))
+	// TODO(user): delete the objects the chart no longer renders, e.g. by labeling the rendered objects and deleting
+	// the labeled objects that were not applied
+
+	return ctrl.Result{}, nil
+}
+
+func (r *{{ .Resource.Kind }}Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&{{ .Resource.ImportAlias }}.{{ .Resource.Kind }}{}).
+		// TODO(user): watch all the kinds of objects rendered from the chart, so that their changes are reverted
+		Owns(&appsv1.Deployment{}).
+		Complete(r)
+}
+
+// render{{ .Resource.Kind }}Chart renders the chart for instance, released with its name and namespace.
+// The fields of its spec override the values of the chart with the same JSON names.
+func render{{ .Resource.Kind }}Chart(
+	instance *{{ .Resource.ImportAlias }}.{{ .Resource.Kind }},
+) ([]*unstructured.Unstructured, error) {
+	dir := {{ lower .Resource.Kind }}DefaultChartDir
+	if chartDir, found := os.LookupEnv("{{ upper .Resource.Kind }}_CHART_DIR"); found {
+		dir = chartDir
+	}
+	chart, err := loader.Load(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the chart in %s: %v", dir, err)
+	}
+
+	values, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&instance.Spec)
+	if err != nil {
+		return nil, err
+	}
+	options := chartutil.ReleaseOptions{
+		Name:      instance.Name,
+		Namespace: instance.Namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	renderValues, err := chartutil.ToRenderValues(chart, values, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := engine.Render(chart, renderValues)
+	if err != nil {
+		return nil, err
+	}
+
+	// The manifests are decoded in the order of their names, the helpers and notes of the chart being skipped
+	names := make([]string, 0, len(manifests))
+	for name := range manifests {
+		if ext := filepath.Ext(name); ext == ".yaml" || ext == ".yml" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var objects []*unstructured.Unstructured
+	for _, name := range names {
+		decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifests[name]), 4096)
+		for {
+			obj := &unstructured.Unstructured{}
+			if err := decoder.Decode(&obj.Object); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("invalid manifest %s: %v", name, err)
+			}
+			// Documents left empty, e.g. by a condition of the template, are not applied
+			if len(obj.Object) != 0 {
+				objects = append(objects, obj)
+			}
+		}
+	}
+	return objects, nil
+}
+`
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/pkg/model/file"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
+)
+
+const (
+	dockerfilePath = "Dockerfile"
+	// managerCopy is the instruction of the Dockerfile copying the manager binary into the image
+	managerCopy = "COPY --from=builder /workspace/manager .\n"
+	// chartsCopy is the instruction of the Dockerfile copying the charts into the image, next to the manager
+	chartsCopy = "COPY helm-charts/ helm-charts/\n"
+)
+
+// UpdateDockerfile makes the Dockerfile of the project copy the charts into the manager image, as the controllers
+// load them at runtime
+func UpdateDockerfile(u *model.Universe) error {
+	// The Dockerfile is only updated along with the controller, as the plugin is run for every scaffolded set of files
+	if _, found := u.Files[controllerPath(u)]; !found {
+		return nil
+	}
+
+	// The Dockerfile is scaffolded when the project is initialized, so the current one is updated
+	bs, err := ioutil.ReadFile(dockerfilePath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	contents := string(bs)
+	if strings.Contains(contents, chartsCopy) || !strings.Contains(contents, managerCopy) {
+		return nil
+	}
+
+	m := &file.File{
+		Path:           dockerfilePath,
+		Contents:       strings.Replace(contents, managerCopy, managerCopy+chartsCopy, 1),
+		IfExistsAction: file.Overwrite,
+	}
+
+	_, err = util.AddFile(u, m)
+
+	return err
+}
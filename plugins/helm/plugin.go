@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"sigs.k8s.io/kubebuilder/pkg/model"
+	"sigs.k8s.io/kubebuilder/plugins/internal/util"
+)
+
+// Plugin implements model.Plugin, scaffolding an API and a controller that renders a Helm chart for every object of
+// the API, with the values of its spec, and applies the rendered objects. It eases the migration of helm-operator
+// projects to Go, as their charts can be reused as they are while the reconcile logic moves to the controller.
+type Plugin struct {
+}
+
+// Pipe implements model.Plugin
+func (p *Plugin) Pipe(u *model.Universe) error {
+	functions := []util.PluginFunc{
+		ReplaceTypes,
+		AddSample,
+		AddChart,
+		ReplaceController,
+		UpdateDockerfile,
+	}
+
+	for _, fn := range functions {
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
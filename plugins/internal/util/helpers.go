@@ -1,4 +1,4 @@
-package addon
+package util
 
 import (
 	"bytes"
@@ -12,9 +12,7 @@ import (
 	"sigs.k8s.io/kubebuilder/pkg/model/file"
 )
 
-// This file gathers functions that are likely to be useful to other
-// plugins.  Once we have validated they are used in more than one
-// place, we can promote them to a shared location.
+// This file gathers functions that are shared by the different plugins.
 
 // PluginFunc executes a step of Plugin
 type PluginFunc func(u *model.Universe) error
@@ -0,0 +1,9 @@
+module sigs.k8s.io/kubebuilder/testdata/project-v3-addon/hack/tools
+
+go 1.13
+
+require (
+	github.com/golangci/golangci-lint v1.31.0
+	sigs.k8s.io/controller-tools v0.3.0
+	sigs.k8s.io/kustomize/kustomize/v3 v3.5.4
+)
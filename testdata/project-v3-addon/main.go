@@ -51,6 +51,7 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	// +kubebuilder:scaffold:flags
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
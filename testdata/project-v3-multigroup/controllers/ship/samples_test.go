@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Samples", func() {
+	It("should be valid against the schemas of the CRDs", func() {
+		paths, err := filepath.Glob(filepath.Join("..", "..", "config", "samples", "ship_*.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		for _, path := range paths {
+			if filepath.Base(path) == "kustomization.yaml" {
+				continue
+			}
+			samples, err := readSamples(path)
+			Expect(err).NotTo(HaveOccurred(), "unable to read %s", path)
+			for _, sample := range samples {
+				// The API server validates the sample without storing it
+				Expect(k8sClient.Create(context.Background(), sample, client.DryRunAll)).To(Succeed(),
+					"%s %s of %s is invalid", sample.GetKind(), sample.GetName(), path)
+			}
+		}
+	})
+})
+
+// readSamples decodes the objects of the YAML documents of the file at path, in the default namespace unless they
+// set one, which is ignored for the cluster-scoped resources
+func readSamples(path string) ([]*unstructured.Unstructured, error) {
+	f, err := os.Open(path) // nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var samples []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		sample := &unstructured.Unstructured{}
+		if err := decoder.Decode(&sample.Object); err == io.EOF {
+			return samples, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(sample.Object) == 0 {
+			continue
+		}
+		if sample.GetNamespace() == "" {
+			sample.SetNamespace("default")
+		}
+		samples = append(samples, sample)
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newCaptain returns a Captain of spec to create, its namespace is ignored if it is cluster-scoped
+func newCaptain(spec CaptainSpec) *Captain {
+	return &Captain{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "test-webhook-captain-", Namespace: "default"},
+		Spec:       spec,
+	}
+}
+
+var _ = Describe("Captain defaulting webhook", func() {
+	It("should default the created Captain objects", func() {
+		ctx := context.Background()
+		obj := newCaptain(CaptainSpec{})
+		Expect(k8sClient.Create(ctx, obj)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+		}()
+
+		// Defaulting the object again changes nothing, as the mutating webhook already defaulted it
+		defaulted := obj.DeepCopy()
+		defaulted.Default()
+		Expect(defaulted).To(Equal(obj))
+	})
+})
+
+var _ = Describe("Captain validating webhook", func() {
+	It("should reject the invalid Captain objects", func() {
+		ctx := context.Background()
+		for _, tt := range []struct {
+			spec       CaptainSpec
+			wantDenied bool
+		}{
+			{spec: CaptainSpec{}},
+			// TODO(user): add the specs your validation logic accepts and rejects
+		} {
+			obj := newCaptain(tt.spec)
+			err := k8sClient.Create(ctx, obj)
+			if tt.wantDenied {
+				Expect(isDenied(err)).To(BeTrue(), "expected %+v to be denied, got: %v", tt.spec, err)
+				continue
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Delete(ctx, obj)).To(Succeed())
+		}
+	})
+})